@@ -18,6 +18,10 @@ type PoWConfig struct {
 	AdjustHeightGap      int32  `json:"adjustHeightGap"`
 	ExpectedPeriodMilSec int32  `json:"expectedPeriod"`
 	MaxTarget            uint32 `json:"maxTarget"`
+	// Version 难度调整算法版本，0为每AdjustHeightGap个区块调整一次的原算法，1为每个区块都调整的LWMA算法
+	Version int64 `json:"version"`
+	// ConfirmationDepth 区块之上至少有多少个区块时视为不可逆，未配置时使用defaultConfirmationDepth
+	ConfirmationDepth int64 `json:"confirmationDepth"`
 }
 
 // 目前未定义pb结构
@@ -118,5 +122,37 @@ func unmarshalPowConfig(input []byte) (*PoWConfig, error) {
 	powCfg.MaxTarget = uint32Map["maxTarget"]
 	powCfg.AdjustHeightGap = int32Map["adjustHeightGap"]
 	powCfg.ExpectedPeriodMilSec = int32Map["expectedPeriod"]
+	// version为可选字段，未配置时使用原算法
+	if v, ok := consCfg["version"]; ok {
+		version, err := parseVersion(v)
+		if err != nil {
+			return nil, err
+		}
+		powCfg.Version = version
+	}
+	powCfg.ConfirmationDepth = defaultConfirmationDepth
+	if v, ok := consCfg["confirmationDepth"]; ok {
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("marshal consensus config failed key confirmationDepth should be string")
+		}
+		depth, err := strconv.ParseInt(str, 10, 64)
+		if err != nil || depth < 0 {
+			return nil, fmt.Errorf("marshal consensus config failed key confirmationDepth set error")
+		}
+		powCfg.ConfirmationDepth = depth
+	}
 	return powCfg, nil
 }
+
+func parseVersion(v interface{}) (int64, error) {
+	str, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("marshal consensus config failed key version should be string")
+	}
+	version, err := strconv.ParseInt(str, 10, 64)
+	if err != nil || version < 0 || version > retargetVersionLWMA {
+		return 0, fmt.Errorf("marshal consensus config failed key version set error")
+	}
+	return version, nil
+}
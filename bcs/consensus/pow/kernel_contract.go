@@ -0,0 +1,171 @@
+package pow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
+)
+
+// 本文件实现pow难度调整参数的链上更新
+// 难度调整参数(调整算法版本、调整窗口、出块间隔、最大难度)原先固定在创世块中，只能通过updateConsensus整体替换共识才能修改
+// 现在通过提案-投票在trigger高度调用updateRetargetParams更新参数，参数写入三代合约存储，从trigger高度的下一个区块开始生效
+// contractBucket = "$pow"
+// key = "retarget"
+// value = retargetParams的json串
+// 计算某个区块的难度时，读取其父区块快照中的参数，保证各节点及各分叉上的计算结果确定
+const (
+	powBucket            = "$pow"
+	retargetKey          = "retarget"
+	updateRetargetMethod = "updateRetargetParams"
+)
+
+var (
+	ErrRetargetCaller = errors.New("retarget params can only be updated by proposal")
+	ErrRetargetHeight = errors.New("retarget params update height invalid")
+	ErrRetargetParams = errors.New("invalid retarget params")
+)
+
+// retargetParams 链上存储的难度调整参数，Height为参数开始生效的高度
+type retargetParams struct {
+	Height          int64  `json:"height"`
+	Version         int64  `json:"version"`
+	AdjustHeightGap int32  `json:"adjustHeightGap"`
+	ExpectedPeriod  int32  `json:"expectedPeriod"`
+	MaxTarget       uint32 `json:"maxTarget"`
+}
+
+// methodUpdateRetargetParams 更新难度调整参数，只能由提案合约在trigger高度调用
+// Args: height::trigger高度，args::待更新参数的json串
+// args可包含version、adjustHeightGap、expectedPeriod、maxTarget，与创世块配置一样均为string，未包含的参数保持不变
+func (pow *PoWConsensus) methodUpdateRetargetParams(contractCtx contract.KContext) (*contract.Response, error) {
+	if contractCtx.Caller() != utils.ProposalKernelContract {
+		return common.NewContractErrResponse(common.StatusBadRequest, ErrRetargetCaller.Error()), ErrRetargetCaller
+	}
+	txArgs := contractCtx.Args()
+	height, err := strconv.ParseInt(string(txArgs["height"]), 10, 64)
+	if err != nil || height <= 0 {
+		return common.NewContractErrResponse(common.StatusBadRequest, ErrRetargetHeight.Error()), ErrRetargetHeight
+	}
+	args := make(map[string]interface{})
+	if err := json.Unmarshal(txArgs["args"], &args); err != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, err.Error()), err
+	}
+
+	params := pow.genesisRetargetParams()
+	res, err := contractCtx.Get(powBucket, []byte(retargetKey))
+	if err == nil && res != nil {
+		if err := json.Unmarshal(res, params); err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+	}
+	if err := params.update(args); err != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, err.Error()), err
+	}
+	if err := pow.checkRetargetParams(params); err != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, err.Error()), err
+	}
+	params.Height = height + 1
+	rawBytes, err := json.Marshal(params)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if err := contractCtx.Put(powBucket, []byte(retargetKey), rawBytes); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	pow.XLog.Info("PoW::methodUpdateRetargetParams::retarget params updated", "params", string(rawBytes))
+	return common.NewContractOKResponse(rawBytes), nil
+}
+
+// update 用提案参数覆盖当前参数
+func (p *retargetParams) update(args map[string]interface{}) error {
+	for k, v := range args {
+		if k == "version" {
+			version, err := parseVersion(v)
+			if err != nil {
+				return err
+			}
+			p.Version = version
+			continue
+		}
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%w: %s should be string", ErrRetargetParams, k)
+		}
+		switch k {
+		case "adjustHeightGap", "expectedPeriod":
+			value, err := strconv.ParseInt(str, 10, 32)
+			if err != nil {
+				return fmt.Errorf("%w: %s set error", ErrRetargetParams, k)
+			}
+			if k == "adjustHeightGap" {
+				p.AdjustHeightGap = int32(value)
+			} else {
+				p.ExpectedPeriod = int32(value)
+			}
+		case "maxTarget":
+			value, err := strconv.ParseUint(str, 10, 32)
+			if err != nil {
+				return fmt.Errorf("%w: %s set error", ErrRetargetParams, k)
+			}
+			p.MaxTarget = uint32(value)
+		default:
+			return fmt.Errorf("%w: %s can not be updated", ErrRetargetParams, k)
+		}
+	}
+	return nil
+}
+
+// checkRetargetParams 检查参数是否可用于难度计算
+func (pow *PoWConsensus) checkRetargetParams(p *retargetParams) error {
+	if p.AdjustHeightGap < 2 || p.ExpectedPeriod <= 0 {
+		return fmt.Errorf("%w: adjustHeightGap should be at least 2 and expectedPeriod should be positive", ErrRetargetParams)
+	}
+	if pow.bitcoinFlag {
+		if _, fNegative, fOverflow := SetCompact(p.MaxTarget); fNegative || fOverflow {
+			return fmt.Errorf("%w: maxTarget is negative or overflow", ErrRetargetParams)
+		}
+	}
+	return nil
+}
+
+func (pow *PoWConsensus) genesisRetargetParams() *retargetParams {
+	return &retargetParams{
+		Version:         pow.config.Version,
+		AdjustHeightGap: pow.config.AdjustHeightGap,
+		ExpectedPeriod:  pow.config.ExpectedPeriodMilSec,
+		MaxTarget:       pow.config.MaxTarget,
+	}
+}
+
+// getRetargetConfig 读取blockId对应快照中的难度调整参数，未更新过时为创世块配置
+func (pow *PoWConsensus) getRetargetConfig(blockId []byte) (*PoWConfig, error) {
+	cfg := *pow.config
+	reader, err := pow.Ledger.CreateSnapshot(blockId)
+	if err != nil {
+		pow.XLog.Error("PoW::getRetargetConfig::CreateSnapshot err", "err", err)
+		return nil, err
+	}
+	res, err := reader.Get(powBucket, []byte(retargetKey))
+	if err != nil {
+		pow.XLog.Error("PoW::getRetargetConfig::reader Get err", "err", err)
+		return nil, err
+	}
+	if res == nil || res.PureData == nil || res.PureData.Value == nil {
+		return &cfg, nil
+	}
+	params := &retargetParams{}
+	if err := json.Unmarshal(res.PureData.Value, params); err != nil {
+		pow.XLog.Error("PoW::getRetargetConfig::unmarshal err", "err", err)
+		return nil, err
+	}
+	cfg.Version = params.Version
+	cfg.AdjustHeightGap = params.AdjustHeightGap
+	cfg.ExpectedPeriodMilSec = params.ExpectedPeriod
+	cfg.MaxTarget = params.MaxTarget
+	return &cfg, nil
+}
@@ -0,0 +1,95 @@
+package pow
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
+	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
+)
+
+// proposalKContext 模拟提案合约在trigger高度发起的调用
+type proposalKContext struct {
+	*kmock.FakeKContext
+}
+
+func (c *proposalKContext) Caller() string {
+	return utils.ProposalKernelContract
+}
+
+func newRetargetArgs(height string, params map[string]string) map[string][]byte {
+	a := make(map[string][]byte)
+	a["height"] = []byte(height)
+	a["args"], _ = json.Marshal(params)
+	return a
+}
+
+func TestMethodUpdateRetargetParams(t *testing.T) {
+	pow := prepareChain(t, getDefaultPoWConsensusConf(), 20, 5, 15*time.Second)
+	m := make(map[string]map[string][]byte)
+
+	args := newRetargetArgs("20", map[string]string{"version": "1"})
+	if _, err := pow.methodUpdateRetargetParams(kmock.NewFakeKContext(args, m)); err != ErrRetargetCaller {
+		t.Fatalf("only proposal can update retarget params, err:%v", err)
+	}
+	invalid := []map[string]string{
+		{"adjustHeightGap": "1"},
+		{"expectedPeriod": "0"},
+		{"version": "2"},
+		{"defaultTarget": "8"},
+	}
+	for _, params := range invalid {
+		args = newRetargetArgs("20", params)
+		if _, err := pow.methodUpdateRetargetParams(&proposalKContext{kmock.NewFakeKContext(args, m)}); err == nil {
+			t.Fatalf("invalid params should be rejected: %v", params)
+		}
+	}
+
+	args = newRetargetArgs("20", map[string]string{"version": "1", "adjustHeightGap": "5"})
+	fakeCtx := &proposalKContext{kmock.NewFakeKContext(args, m)}
+	if _, err := pow.methodUpdateRetargetParams(fakeCtx); err != nil {
+		t.Fatal(err)
+	}
+	// 后续提案在已更新参数的基础上修改
+	args = newRetargetArgs("30", map[string]string{"expectedPeriod": "10"})
+	fakeCtx = &proposalKContext{kmock.NewFakeKContext(args, m)}
+	resp, err := pow.methodUpdateRetargetParams(fakeCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := retargetParams{}
+	json.Unmarshal(resp.Body, &params)
+	want := retargetParams{Height: 31, Version: 1, AdjustHeightGap: 5, ExpectedPeriod: 10, MaxTarget: 10}
+	if params != want {
+		t.Fatalf("unexpected retarget params: %+v", params)
+	}
+
+	// 参数在快照中生效前仍使用创世块配置
+	tip := pow.Ledger.GetTipBlock()
+	if bits, err := pow.refreshDifficulty(tip.GetBlockid(), tip.GetHeight()+1); err != nil || bits != 5 {
+		t.Fatalf("genesis retarget params should be used, bits:%d err:%v", bits, err)
+	}
+	// 快照中的参数生效后使用LWMA算法，出块间隔15s大于期望的10s，难度下降
+	value, _ := fakeCtx.Get(powBucket, []byte(retargetKey))
+	l := pow.Ledger.(*kmock.FakeLedger)
+	l.SetSnapshot(powBucket, []byte(retargetKey), value)
+	cfg, err := pow.getRetargetConfig(tip.GetBlockid())
+	if err != nil || cfg.AdjustHeightGap != 5 || cfg.ExpectedPeriodMilSec != 10 || cfg.Version != 1 {
+		t.Fatalf("retarget params not loaded from snapshot: %+v, err:%v", cfg, err)
+	}
+	if bits, err := pow.refreshDifficulty(tip.GetBlockid(), tip.GetHeight()+1); err != nil || bits != 4 {
+		t.Fatalf("lwma should lower difficulty, bits:%d err:%v", bits, err)
+	}
+}
+
+func TestRetargetParamsUpdate(t *testing.T) {
+	p := &retargetParams{}
+	if err := p.update(map[string]interface{}{"maxTarget": 10}); !errors.Is(err, ErrRetargetParams) {
+		t.Fatalf("params should be string, err:%v", err)
+	}
+	if err := p.update(map[string]interface{}{"maxTarget": "486604799"}); err != nil || p.MaxTarget != 0x1d00ffff {
+		t.Fatalf("update maxTarget failed, err:%v", err)
+	}
+}
@@ -25,6 +25,10 @@ var (
 const (
 	MAX_TRIES = 1 << 32 // mining时的最大尝试次数
 	BLOCK_BUF = 100
+
+	retargetVersionLWMA = 1 // 配置version不小于该值时使用LWMA难度调整算法
+
+	defaultConfirmationDepth = 6 // 与Bitcoin一致，区块之上有6个区块时视为不可逆
 )
 
 func init() {
@@ -89,6 +93,7 @@ func NewPoWConsensus(cCtx context.ConsensusCtx, cCfg def.ConsensusConfig) consen
 	if target > 256 {
 		pow.bitcoinFlag = true
 	}
+	pow.status.pow = pow
 	pow.targetBits = target
 	pow.maxDifficulty = big.NewInt(int64(config.MaxTarget))
 	// 重启时需要重新更新目标target
@@ -167,8 +172,15 @@ func (pow *PoWConsensus) CheckMinerMatch(ctx xcontext.XContext, block context.Bl
 		ctx.GetLog().Warn("PoW::CheckMinerMatch::transfer PoWStorage err", "blockId", block.GetBlockid(), "miner", string(block.GetProposer()))
 		return false, err
 	}
+	// 难度调整参数以父区块快照为准
+	cfg, err := pow.getRetargetConfig(block.GetPreHash())
+	if err != nil {
+		ctx.GetLog().Warn("PoW::CheckMinerMatch::getRetargetConfig err", "error", err, "miner", string(block.GetProposer()))
+		return false, err
+	}
+	maxDifficulty := pow.maxDifficultyOf(cfg)
 	// 检查区块的区块头是否和和区块中的targetBits字段匹配
-	if !pow.IsProofed(block.GetBlockid(), s.TargetBits) {
+	if !pow.isProofed(block.GetBlockid(), s.TargetBits, maxDifficulty) {
 		ctx.GetLog().Warn("PoW::CheckMinerMatch::the actual difficulty of block received doesn't match its' blockid",
 			"blockid", fmt.Sprintf("%x", block.GetBlockid()), "miner", string(block.GetProposer()))
 		return false, err
@@ -206,7 +218,7 @@ func (pow *PoWConsensus) CheckMinerMatch(ctx xcontext.XContext, block context.Bl
 		return false, err
 	}
 	// 验证前导0
-	if !pow.IsProofed(block.GetBlockid(), targetBits) {
+	if !pow.isProofed(block.GetBlockid(), targetBits, maxDifficulty) {
 		ctx.GetLog().Warn("PoW::CheckMinerMatch::blockid IsProofed error", "miner", string(block.GetProposer()))
 		return false, err
 	}
@@ -247,6 +259,9 @@ func (pow *PoWConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, []
 	if err != nil {
 		pow.Stop()
 	}
+	if cfg, err := pow.getRetargetConfig(preBlock.GetBlockid()); err == nil {
+		pow.maxDifficulty = pow.maxDifficultyOf(cfg)
+	}
 	pow.targetBits = bits
 	store := &PoWStorage{
 		TargetBits: bits,
@@ -273,6 +288,7 @@ func (pow *PoWConsensus) GetConsensusStatus() (consensus.ConsensusStatus, error)
 
 // Stop 立即停止当前挖矿
 func (pow *PoWConsensus) Stop() error {
+	pow.Contract.GetKernRegistry().UnregisterKernMethod(powBucket, updateRetargetMethod)
 	// 发送停止信号
 	pow.sigc <- true
 	pow.XLog.Debug("PoW::Stop")
@@ -281,6 +297,9 @@ func (pow *PoWConsensus) Stop() error {
 
 // Start 重启实例
 func (pow *PoWConsensus) Start() error {
+	// 若有历史句柄，删除老句柄
+	pow.Contract.GetKernRegistry().UnregisterKernMethod(powBucket, updateRetargetMethod)
+	pow.Contract.GetKernRegistry().RegisterKernMethod(powBucket, updateRetargetMethod, pow.methodUpdateRetargetParams)
 	go func() {
 		var currentMining *mineTask
 		for {
@@ -310,6 +329,7 @@ func (pow *PoWConsensus) Start() error {
 
 // refreshDifficulty 计算difficulty in bitcoin
 // reference of bitcoin's pow: https://github.com/bitcoin/bitcoin/blob/master/src/pow.cpp#L49
+// 难度调整参数读取自tipHash对应的快照，Version为retargetVersionLWMA时使用LWMA算法每个区块调整一次
 func (pow *PoWConsensus) refreshDifficulty(tipHash []byte, nextHeight int64) (uint32, error) {
 	// 未到调整高度0 + Gap，直接返回default
 	if nextHeight <= int64(pow.config.AdjustHeightGap) {
@@ -320,36 +340,37 @@ func (pow *PoWConsensus) refreshDifficulty(tipHash []byte, nextHeight int64) (ui
 	if err != nil {
 		return pow.config.DefaultTarget, nil
 	}
+	cfg, err := pow.getRetargetConfig(tipHash)
+	if err != nil {
+		return 0, err
+	}
+	if cfg.Version >= retargetVersionLWMA {
+		return pow.lwmaDifficulty(cfg, block, nextHeight)
+	}
 	preBlock, err := pow.Ledger.QueryBlockHeader(block.GetPreHash())
 	if err != nil {
 		return pow.config.DefaultTarget, nil
 	}
-	in, err := pow.ParseConsensusStorage(preBlock)
+	prevTargetBits, err := pow.getTargetBits(preBlock)
 	if err != nil {
-		pow.XLog.Error("PoW::refreshDifficulty::ParseConsensusStorage err", "err", err, "blockId", tipHash)
+		pow.XLog.Error("PoW::refreshDifficulty::getTargetBits err", "err", err, "blockId", tipHash)
 		return 0, err
 	}
-	s, ok := in.(PoWStorage)
-	if !ok {
-		pow.XLog.Error("PoW::refreshDifficulty::transfer PoWStorage err")
-		return 0, PoWBlockItemErr
-	}
-	prevTargetBits := s.TargetBits
 	// 未到调整时机直接返回上一difficulty
-	if nextHeight%int64(pow.config.AdjustHeightGap) != 0 {
+	if nextHeight%int64(cfg.AdjustHeightGap) != 0 {
 		return prevTargetBits, nil
 	}
 
 	farBlock := preBlock
 	// preBlock已经回溯过一次，因此回溯总量-1，获取
-	for i := int32(0); i < pow.config.AdjustHeightGap-1; i++ {
+	for i := int32(0); i < cfg.AdjustHeightGap-1; i++ {
 		prevBlock, err := pow.Ledger.QueryBlockHeader(farBlock.GetPreHash())
 		if err != nil {
 			return pow.config.DefaultTarget, nil
 		}
 		farBlock = prevBlock
 	}
-	expectedTimeSpan := pow.config.ExpectedPeriodMilSec * (pow.config.AdjustHeightGap - 1)
+	expectedTimeSpan := cfg.ExpectedPeriodMilSec * (cfg.AdjustHeightGap - 1)
 	// ATTENTION: 此处并没有针对任意的Timestamp类型，目前只能是timestamp为nano类型
 	actualTimeSpan := int32((preBlock.GetTimestamp() - farBlock.GetTimestamp()) / 1e9)
 	pow.XLog.Debug("PoW::refreshDifficulty::timespan diff", "expectedTimeSpan", expectedTimeSpan, "actualTimeSpan", actualTimeSpan)
@@ -365,17 +386,7 @@ func (pow *PoWConsensus) refreshDifficulty(tipHash []byte, nextHeight int64) (ui
 		difficulty, _, _ := SetCompact(prevTargetBits) // prevTargetBits一定在之前检查过
 		difficulty.Mul(difficulty, big.NewInt(int64(actualTimeSpan)))
 		difficulty.Div(difficulty, big.NewInt(int64(expectedTimeSpan)))
-		if difficulty.Cmp(pow.maxDifficulty) == -1 {
-			pow.XLog.Debug("PoW::refreshDifficulty::retarget", "newTargetBits", pow.config.MaxTarget)
-			return pow.config.MaxTarget, nil
-		}
-		newTargetBits, ok := GetCompact(difficulty)
-		if !ok {
-			pow.XLog.Error("PoW::refreshDifficulty::difficulty GetCompact err")
-			return prevTargetBits, nil
-		}
-		pow.XLog.Debug("PoW::refreshDifficulty::adjust targetBits", "height", nextHeight, "targetBits", newTargetBits, "prevTargetBits", prevTargetBits)
-		return newTargetBits, nil
+		return pow.compactTarget(cfg, difficulty, prevTargetBits, nextHeight), nil
 	}
 
 	// 原xuperchain逻辑
@@ -383,22 +394,157 @@ func (pow *PoWConsensus) refreshDifficulty(tipHash []byte, nextHeight int64) (ui
 	difficulty.Lsh(difficulty, uint(prevTargetBits))
 	difficulty.Mul(difficulty, big.NewInt(int64(expectedTimeSpan)))
 	difficulty.Div(difficulty, big.NewInt(int64(actualTimeSpan)))
+	return pow.leadingZeroBits(cfg, difficulty, prevTargetBits, nextHeight), nil
+}
+
+// lwmaDifficulty LWMA(线性加权移动平均)难度调整算法，每个区块都根据最近AdjustHeightGap个区块调整难度
+// 越新的区块出块时间权重越大，相比按周期调整的原算法对算力变化响应更快，难度曲线也更平滑
+// reference: https://github.com/zawy12/difficulty-algorithms/issues/3
+func (pow *PoWConsensus) lwmaDifficulty(cfg *PoWConfig, tipBlock context.BlockInterface, nextHeight int64) (uint32, error) {
+	tipBits, err := pow.getTargetBits(tipBlock)
+	if err != nil {
+		pow.XLog.Error("PoW::lwmaDifficulty::getTargetBits err", "err", err)
+		return 0, err
+	}
+	// 自tip向前回溯窗口内的区块，blocks按高度从高到低排列，最后一个区块只用于计算出块时间
+	blocks := []context.BlockInterface{tipBlock}
+	for i := int32(0); i < cfg.AdjustHeightGap && blocks[len(blocks)-1].GetHeight() > 0; i++ {
+		preBlock, err := pow.Ledger.QueryBlockHeader(blocks[len(blocks)-1].GetPreHash())
+		if err != nil {
+			break
+		}
+		blocks = append(blocks, preBlock)
+	}
+	n := int64(len(blocks) - 1)
+	if n == 0 {
+		return tipBits, nil
+	}
+	period := int64(cfg.ExpectedPeriodMilSec) * 1e9
+	// 加权出块时间之和，第i个区块(由旧到新)权重为i，单个出块时间限制在[1ns, 6*period]之间
+	weightedSolveTime := big.NewInt(0)
+	// bitcoin算法中累加target，原xuperchain算法中累加难度
+	sum := big.NewInt(0)
+	for i := int64(1); i <= n; i++ {
+		block := blocks[n-i]
+		solveTime := block.GetTimestamp() - blocks[n-i+1].GetTimestamp()
+		if solveTime < 1 {
+			solveTime = 1
+		}
+		if solveTime > 6*period {
+			solveTime = 6 * period
+		}
+		weightedSolveTime.Add(weightedSolveTime, new(big.Int).Mul(big.NewInt(solveTime), big.NewInt(i)))
+		bits, err := pow.getTargetBits(block)
+		if err != nil {
+			pow.XLog.Error("PoW::lwmaDifficulty::getTargetBits err", "err", err, "height", block.GetHeight())
+			return 0, err
+		}
+		if pow.bitcoinFlag {
+			sum.Add(sum, targetOf(bits))
+		} else {
+			sum.Add(sum, pow.workOf(bits))
+		}
+	}
+	// 出块时间恰好为period时的加权和
+	expected := new(big.Int).Mul(big.NewInt(n*(n+1)/2), big.NewInt(period))
+	// 防止出块时间过短时难度跳变过大
+	if floor := new(big.Int).Div(expected, big.NewInt(10)); weightedSolveTime.Cmp(floor) < 0 {
+		weightedSolveTime = floor
+	}
+	pow.XLog.Debug("PoW::lwmaDifficulty::weighted solve time", "expected", expected, "actual", weightedSolveTime)
+
+	avg := sum.Div(sum, big.NewInt(n))
+	if pow.bitcoinFlag {
+		// 平均target按实际出块时间与期望出块时间之比放大
+		avg.Mul(avg, weightedSolveTime)
+		avg.Div(avg, expected)
+		return pow.compactTarget(cfg, avg, tipBits, nextHeight), nil
+	}
+	// 原xuperchain逻辑中难度为2^targetBits，平均难度按期望出块时间与实际出块时间之比放大
+	avg.Mul(avg, expected)
+	avg.Div(avg, weightedSolveTime)
+	return pow.leadingZeroBits(cfg, avg, tipBits, nextHeight), nil
+}
+
+// compactTarget 将bitcoin算法中计算得到的target转换为targetBits，并限制在最大难度之内
+func (pow *PoWConsensus) compactTarget(cfg *PoWConfig, target *big.Int, prevTargetBits uint32, nextHeight int64) uint32 {
+	if target.Cmp(pow.maxDifficultyOf(cfg)) == -1 {
+		pow.XLog.Debug("PoW::refreshDifficulty::retarget", "newTargetBits", cfg.MaxTarget)
+		return cfg.MaxTarget
+	}
+	newTargetBits, ok := GetCompact(target)
+	if !ok {
+		pow.XLog.Error("PoW::refreshDifficulty::difficulty GetCompact err")
+		return prevTargetBits
+	}
+	pow.XLog.Debug("PoW::refreshDifficulty::adjust targetBits", "height", nextHeight, "targetBits", newTargetBits, "prevTargetBits", prevTargetBits)
+	return newTargetBits
+}
+
+// leadingZeroBits 将原xuperchain算法中计算得到的难度转换为前导0个数，并限制在最大难度之内
+func (pow *PoWConsensus) leadingZeroBits(cfg *PoWConfig, difficulty *big.Int, prevTargetBits uint32, nextHeight int64) uint32 {
+	if difficulty.Sign() <= 0 {
+		return 0
+	}
 	newTargetBits := uint32(difficulty.BitLen() - 1)
-	if newTargetBits > pow.config.MaxTarget {
-		pow.XLog.Debug("PoW::refreshDifficulty::retarget", "newTargetBits", pow.config.MaxTarget)
-		newTargetBits = pow.config.MaxTarget
+	if newTargetBits > cfg.MaxTarget {
+		pow.XLog.Debug("PoW::refreshDifficulty::retarget", "newTargetBits", cfg.MaxTarget)
+		newTargetBits = cfg.MaxTarget
 	}
 	pow.XLog.Debug("PoW::refreshDifficulty::adjust targetBits", "height", nextHeight, "targetBits", newTargetBits, "prevTargetBits", prevTargetBits)
-	return newTargetBits, nil
+	return newTargetBits
+}
+
+// getTargetBits 获取区块中记录的targetBits
+func (pow *PoWConsensus) getTargetBits(block context.BlockInterface) (uint32, error) {
+	in, err := pow.ParseConsensusStorage(block)
+	if err != nil {
+		return 0, err
+	}
+	s, ok := in.(PoWStorage)
+	if !ok {
+		pow.XLog.Error("PoW::getTargetBits::transfer PoWStorage err")
+		return 0, PoWBlockItemErr
+	}
+	return s.TargetBits, nil
+}
+
+// maxDifficultyOf bitcoin算法下MaxTarget对应的最小target，原xuperchain算法中直接使用MaxTarget
+func (pow *PoWConsensus) maxDifficultyOf(cfg *PoWConfig) *big.Int {
+	if !pow.bitcoinFlag {
+		return big.NewInt(int64(cfg.MaxTarget))
+	}
+	return targetOf(cfg.MaxTarget)
+}
+
+// workOf 返回targetBits对应的工作量，即期望的hash次数
+// bitcoin算法中为2^256/(target+1)，原xuperchain算法中为2^targetBits
+func (pow *PoWConsensus) workOf(targetBits uint32) *big.Int {
+	if !pow.bitcoinFlag {
+		return new(big.Int).Lsh(big.NewInt(1), uint(targetBits))
+	}
+	target := targetOf(targetBits)
+	work := new(big.Int).Lsh(big.NewInt(1), 256)
+	return work.Div(work, target.Add(target, big.NewInt(1)))
+}
+
+// targetOf 返回bitcoin算法中targetBits对应的target
+func targetOf(targetBits uint32) *big.Int {
+	target, _, _ := SetCompact(targetBits)
+	return target
 }
 
 // IsProofed check workload proof
 func (pow *PoWConsensus) IsProofed(blockID []byte, targetBits uint32) bool {
+	return pow.isProofed(blockID, targetBits, pow.maxDifficulty)
+}
+
+func (pow *PoWConsensus) isProofed(blockID []byte, targetBits uint32, maxDifficulty *big.Int) bool {
 	hash := new(big.Int)
 	hash.SetBytes(blockID)
 	if pow.bitcoinFlag {
 		d, fNegative, fOverflow := SetCompact(targetBits)
-		if fNegative || fOverflow || d.Cmp(pow.maxDifficulty) == -1 { // d > maxDifficulty
+		if fNegative || fOverflow || d.Cmp(maxDifficulty) == -1 { // d > maxDifficulty
 			return false
 		}
 		if hash.Cmp(d) == 1 { // hash > d
@@ -1,7 +1,9 @@
 package pow
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"math/big"
 	"strconv"
 	"testing"
@@ -425,3 +427,135 @@ func TestCompeteMaster(t *testing.T) {
 	i := NewPoWConsensus(*cCtx, getConsensusConf(getPoWConsensusConf()))
 	i.CompeteMaster(3)
 }
+
+func getLWMAConsensusConf(defaultTarget uint32) []byte {
+	return []byte(fmt.Sprintf(`{
+		"defaultTarget": "%d",
+		"adjustHeightGap": "10",
+		"expectedPeriod": "15",
+		"maxTarget": "30",
+		"version": "1"
+	}`, defaultTarget))
+}
+
+// prepareChain 创建高度为[0, height]的账本，区块的targetBits均为bits，出块间隔均为interval
+func prepareChain(t *testing.T, config []byte, height int, bits uint32, interval time.Duration) *PoWConsensus {
+	cCtx, err := prepare(config)
+	if err != nil {
+		t.Fatal("prepare error", err)
+	}
+	by, _ := json.Marshal(PoWStorage{TargetBits: bits})
+	l := cCtx.Ledger.(*kmock.FakeLedger)
+	genesis := time.Unix(1609459200, 0)
+	for h := 0; h <= height; h++ {
+		if h > 2 {
+			l.Put(kmock.NewBlock(h))
+		}
+		b, _ := l.QueryBlockHeaderByHeight(int64(h))
+		block := b.(*kmock.FakeBlock)
+		block.SetTimestamp(genesis.Add(time.Duration(h) * interval).UnixNano())
+		if h > 0 {
+			block.ConsensusStorage = by
+		}
+	}
+	i := NewPoWConsensus(*cCtx, getConsensusConf(config))
+	if i == nil {
+		t.Fatal("NewPoWConsensus error")
+	}
+	return i.(*PoWConsensus)
+}
+
+func TestLWMADifficulty(t *testing.T) {
+	cases := []struct {
+		interval time.Duration
+		want     uint32
+	}{
+		{15 * time.Second, 10},
+		{7 * time.Second, 11},
+		{32 * time.Second, 8},
+	}
+	for _, c := range cases {
+		pow := prepareChain(t, getLWMAConsensusConf(5), 20, 10, c.interval)
+		tip := pow.Ledger.GetTipBlock()
+		bits, err := pow.refreshDifficulty(tip.GetBlockid(), tip.GetHeight()+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bits != c.want {
+			t.Errorf("interval %v: want targetBits %d, got %d", c.interval, c.want, bits)
+		}
+	}
+}
+
+func TestLWMADifficultyBitcoin(t *testing.T) {
+	const bits = 0x1d00ffff
+	pow := prepareChain(t, getLWMAConsensusConf(minTarget), 20, bits, 15*time.Second)
+	tip := pow.Ledger.GetTipBlock()
+	got, err := pow.refreshDifficulty(tip.GetBlockid(), tip.GetHeight()+1)
+	if err != nil || got != bits {
+		t.Fatalf("steady block interval should keep target, got %x err %v", got, err)
+	}
+
+	pow = prepareChain(t, getLWMAConsensusConf(minTarget), 20, bits, 7500*time.Millisecond)
+	tip = pow.Ledger.GetTipBlock()
+	got, err = pow.refreshDifficulty(tip.GetBlockid(), tip.GetHeight()+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := targetOf(bits)
+	want.Div(want, big.NewInt(2))
+	if targetOf(got).Cmp(want) != 0 {
+		t.Errorf("target should be halved when blocks come twice as fast, got %x", got)
+	}
+}
+
+func TestDifficultyStatus(t *testing.T) {
+	pow := prepareChain(t, getLWMAConsensusConf(5), 20, 10, 16*time.Second)
+	status, _ := pow.GetConsensusStatus()
+	info := ValidatorsInfo{}
+	if err := json.Unmarshal(status.GetCurrentValidatorsInfo(), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.TargetBits != 9 || info.Difficulty != "512" || info.RetargetVersion != 1 {
+		t.Errorf("unexpected difficulty info: %+v", info)
+	}
+	// 窗口内10个区块，每个区块期望1024次hash，出块间隔16s
+	if info.Hashrate != "64" {
+		t.Errorf("unexpected hashrate: %s", info.Hashrate)
+	}
+}
+
+func TestGetFinalizedBlock(t *testing.T) {
+	pow := prepareChain(t, getLWMAConsensusConf(5), 20, 10, 16*time.Second)
+	status, _ := pow.GetConsensusStatus()
+	// 未配置时确认深度为6
+	height, blockid := status.GetFinalizedBlock()
+	b, _ := pow.Ledger.QueryBlockHeaderByHeight(14)
+	if height != 14 || !bytes.Equal(blockid, b.GetBlockid()) {
+		t.Errorf("GetFinalizedBlock want 14, got %d", height)
+	}
+
+	config := []byte(`{
+		"defaultTarget": "5",
+		"adjustHeightGap": "10",
+		"expectedPeriod": "15",
+		"maxTarget": "30",
+		"confirmationDepth": "30"
+	}`)
+	pow = prepareChain(t, config, 20, 10, 16*time.Second)
+	status, _ = pow.GetConsensusStatus()
+	if height, _ := status.GetFinalizedBlock(); height != 0 {
+		t.Errorf("GetFinalizedBlock want 0 when chain shorter than depth, got %d", height)
+	}
+
+	config = []byte(`{
+		"defaultTarget": "5",
+		"adjustHeightGap": "10",
+		"expectedPeriod": "15",
+		"maxTarget": "30",
+		"confirmationDepth": "-1"
+	}`)
+	if _, err := unmarshalPowConfig(config); err == nil {
+		t.Error("negative confirmationDepth should be rejected")
+	}
+}
@@ -2,6 +2,9 @@ package pow
 
 import (
 	"encoding/json"
+	"math/big"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
 )
 
 // PoWStatus 实现了ConsensusStatus接口
@@ -10,10 +13,22 @@ type PoWStatus struct {
 	startHeight int64
 	newHeight   int64
 	miner       ValidatorsInfo
+	pow         *PoWConsensus
 }
 
 type ValidatorsInfo struct {
 	Validators []string `json:"validators"`
+	// TargetBits 下一个区块的targetBits
+	TargetBits uint32 `json:"targetBits"`
+	// Difficulty 下一个区块期望的hash次数
+	Difficulty string `json:"difficulty"`
+	// Hashrate 根据最近一个调整窗口内的区块估算的全网每秒hash次数
+	Hashrate string `json:"hashrate"`
+	// 当前生效的难度调整参数
+	RetargetVersion int64  `json:"retargetVersion"`
+	AdjustHeightGap int32  `json:"adjustHeightGap"`
+	ExpectedPeriod  int32  `json:"expectedPeriod"`
+	MaxTarget       uint32 `json:"maxTarget"`
 }
 
 // GetVersion 返回pow所在共识version
@@ -41,11 +56,65 @@ func (s *PoWStatus) GetCurrentTerm() int64 {
 	return s.newHeight
 }
 
-// GetCurrentValidatorsInfo 获取当前矿工信息
+// GetCurrentValidatorsInfo 获取当前矿工信息，以及当前难度和估算的全网算力
 func (s *PoWStatus) GetCurrentValidatorsInfo() []byte {
-	info, err := json.Marshal(s.miner)
+	info := s.miner
+	if s.pow != nil {
+		s.pow.fillDifficultyInfo(&info)
+	}
+	b, err := json.Marshal(info)
 	if err != nil {
 		return nil
 	}
-	return info
+	return b
+}
+
+// GetFinalizedBlock pow按确认深度计算不可逆区块，区块之上有confirmationDepth个区块时视为不可逆
+func (s *PoWStatus) GetFinalizedBlock() (int64, []byte) {
+	if s.pow == nil {
+		return 0, nil
+	}
+	return common.FinalizedByDepth(s.pow.Ledger, s.pow.config.ConfirmationDepth)
+}
+
+// fillDifficultyInfo 根据账本tip计算下一个区块的难度，并用最近一个调整窗口内的总工作量除以出块总时长估算算力
+func (pow *PoWConsensus) fillDifficultyInfo(info *ValidatorsInfo) {
+	tipBlock := pow.Ledger.GetTipBlock()
+	if tipBlock == nil {
+		return
+	}
+	cfg, err := pow.getRetargetConfig(tipBlock.GetBlockid())
+	if err != nil {
+		return
+	}
+	info.RetargetVersion = cfg.Version
+	info.AdjustHeightGap = cfg.AdjustHeightGap
+	info.ExpectedPeriod = cfg.ExpectedPeriodMilSec
+	info.MaxTarget = cfg.MaxTarget
+	bits, err := pow.refreshDifficulty(tipBlock.GetBlockid(), tipBlock.GetHeight()+1)
+	if err != nil {
+		bits = pow.targetBits
+	}
+	info.TargetBits = bits
+	info.Difficulty = pow.workOf(bits).String()
+
+	work := big.NewInt(0)
+	block := tipBlock
+	for i := int32(0); i < cfg.AdjustHeightGap && block.GetHeight() > 0; i++ {
+		blockBits, err := pow.getTargetBits(block)
+		if err != nil {
+			break
+		}
+		preBlock, err := pow.Ledger.QueryBlockHeader(block.GetPreHash())
+		if err != nil {
+			break
+		}
+		work.Add(work, pow.workOf(blockBits))
+		block = preBlock
+	}
+	info.Hashrate = "0"
+	if span := tipBlock.GetTimestamp() - block.GetTimestamp(); span > 0 {
+		work.Mul(work, big.NewInt(1e9))
+		info.Hashrate = work.Div(work, big.NewInt(span)).String()
+	}
 }
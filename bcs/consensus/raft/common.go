@@ -0,0 +1,122 @@
+package raft
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+var (
+	ErrNotLeader       = errors.New("Node isn't the raft leader.")
+	ErrInvalidProposer = errors.New("Block's proposer isn't a valid raft leader.")
+	ErrInvalidTerm     = errors.New("Block's term is lower than its parent.")
+	ErrConflictCommit  = errors.New("Block conflicts with committed blocks.")
+	ErrInvalidSign     = errors.New("Block's sign is invalid.")
+	ErrInvalidMsgSign  = errors.New("Raft message's sign is invalid.")
+	ErrInvalidConfig   = errors.New("Raft config is invalid, please check it.")
+	ErrEmptyValidators = errors.New("Current validators is empty.")
+	targetParamErr     = errors.New("Target paramters are invalid, please check them.")
+	aclErr             = errors.New("Raft needs valid acl account.")
+	memberChangeErr    = errors.New("Raft can only add or remove one validator at a time.")
+)
+
+const (
+	raftBucket           = "$raft"
+	validateKeys         = "validates"
+	contractGetValidates = "getValidates"
+	contractEditValidate = "editValidates"
+
+	fee = 1000
+
+	// 默认配置, 单位为毫秒
+	defaultMinInterval       = 500
+	defaultHeartbeatInterval = 500
+	defaultElectionTimeout   = 3000
+)
+
+type raftConfig struct {
+	// 两个区块之间的最小间隔, 单位为毫秒
+	MinInterval int64 `json:"min_interval"`
+	// 没有交易时出空块的最大间隔, 单位为毫秒, 为0时不出空块
+	MaxInterval int64 `json:"max_interval,omitempty"`
+	// leader发送心跳的间隔, 单位为毫秒
+	HeartbeatInterval int64 `json:"heartbeat_interval"`
+	// 选举超时, 实际超时在[ElectionTimeout, 2*ElectionTimeout)内随机, 单位为毫秒
+	ElectionTimeout int64        `json:"election_timeout"`
+	InitProposer    ProposerInfo `json:"init_proposer"`
+}
+
+type ProposerInfo struct {
+	Address []string `json:"address"`
+}
+
+// buildConfig 解析raft配置并填充默认值，选举超时必须大于两倍心跳间隔
+func buildConfig(input []byte) (*raftConfig, error) {
+	conf := &raftConfig{}
+	if err := json.Unmarshal(input, conf); err != nil {
+		return nil, err
+	}
+	if len(conf.InitProposer.Address) == 0 {
+		return nil, ErrEmptyValidators
+	}
+	if conf.MinInterval <= 0 {
+		conf.MinInterval = defaultMinInterval
+	}
+	if conf.HeartbeatInterval <= 0 {
+		conf.HeartbeatInterval = defaultHeartbeatInterval
+	}
+	if conf.ElectionTimeout <= 0 {
+		conf.ElectionTimeout = defaultElectionTimeout
+	}
+	if conf.ElectionTimeout < 2*conf.HeartbeatInterval || conf.MaxInterval < 0 {
+		return nil, ErrInvalidConfig
+	}
+	return conf, nil
+}
+
+// loadValidators 格式为 { "address": [$ADDR_STRING...] }
+func loadValidators(res []byte) ([]string, error) {
+	info := ProposerInfo{}
+	if err := json.Unmarshal(res, &info); err != nil {
+		return nil, err
+	}
+	return info.Address, nil
+}
+
+func find(a string, t []string) bool {
+	for _, v := range t {
+		if a == v {
+			return true
+		}
+	}
+	return false
+}
+
+// quorum 返回validators的多数派个数
+func quorum(validators []string) int {
+	return len(validators)/2 + 1
+}
+
+type raftStringConfig struct {
+	Version string `json:"version,omitempty"`
+}
+
+type raftIntConfig struct {
+	Version int64 `json:"version,omitempty"`
+}
+
+// ParseVersion 支持string格式和int格式的version type
+func ParseVersion(cfg string) (int64, error) {
+	intVersion := raftIntConfig{}
+	if err := json.Unmarshal([]byte(cfg), &intVersion); err == nil {
+		return intVersion.Version, nil
+	}
+	strVersion := raftStringConfig{}
+	if err := json.Unmarshal([]byte(cfg), &strVersion); err != nil {
+		return 0, err
+	}
+	if strVersion.Version == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(strVersion.Version, 10, 64)
+}
@@ -0,0 +1,133 @@
+package raft
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/contract"
+)
+
+// methodEditValidates 验证人变更，参数格式与xpoa一致
+// 为保证新旧验证人集合的多数派相交，每次只能增加或删除一个验证人，变更在包含该交易的区块之后立即生效
+// Args: validates::验证人钱包地址，以;分隔
+func (r *raftConsensus) methodEditValidates(contractCtx contract.KContext) (*contract.Response, error) {
+	txArgs := contractCtx.Args()
+	// 1. 核查发起者的权限
+	aks := make(map[string]float64)
+	if err := json.Unmarshal(txArgs["aksWeight"], &aks); err != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, "invalid acl: unmarshal err."), err
+	}
+	total, err := strconv.ParseInt(string(txArgs["rule"]), 10, 32)
+	if total != 1 || err != nil { // 目前必须是阈值模型
+		return common.NewContractErrResponse(common.StatusBadRequest, "invalid acl: rule should eq 1."), err
+	}
+	acceptValue, err := strconv.ParseFloat(string(txArgs["acceptValue"]), 64)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, "invalid acl: pls check accept value."), err
+	}
+	curVali, err := r.getCurrentValidators(contractCtx)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if !isAuthAddress(curVali, aks, acceptValue) {
+		return common.NewContractErrResponse(common.StatusBadRequest, aclErr.Error()), aclErr
+	}
+
+	// 2. 检查新的验证人集合
+	validatesAddrs := string(txArgs["validates"])
+	if validatesAddrs == "" {
+		return common.NewContractErrResponse(common.StatusBadRequest, targetParamErr.Error()), targetParamErr
+	}
+	validators := strings.Split(validatesAddrs, ";")
+	if !isSingleChange(curVali, validators) {
+		return common.NewContractErrResponse(common.StatusBadRequest, memberChangeErr.Error()), memberChangeErr
+	}
+	rawBytes, err := json.Marshal(&ProposerInfo{
+		Address: validators,
+	})
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if err := contractCtx.Put(raftBucket, []byte(fmt.Sprintf("%d_%s", r.version, validateKeys)), rawBytes); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	contractCtx.AddResourceUsed(contract.Limits{
+		XFee: fee,
+	})
+	return common.NewContractOKResponse(rawBytes), nil
+}
+
+// methodGetValidates 验证人获取
+// Return: validators::验证人钱包地址
+func (r *raftConsensus) methodGetValidates(contractCtx contract.KContext) (*contract.Response, error) {
+	validators, err := r.getCurrentValidators(contractCtx)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	jsonBytes, err := json.Marshal(map[string][]string{
+		"validators": validators,
+	})
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	contractCtx.AddResourceUsed(contract.Limits{
+		XFee: fee / 1000,
+	})
+	return common.NewContractOKResponse(jsonBytes), nil
+}
+
+// getCurrentValidators 读取当前的验证人集合，未修改过时为初始验证人
+func (r *raftConsensus) getCurrentValidators(contractCtx contract.KContext) ([]string, error) {
+	res, err := contractCtx.Get(raftBucket, []byte(fmt.Sprintf("%d_%s", r.version, validateKeys)))
+	if err != nil || res == nil {
+		return r.config.InitProposer.Address, nil
+	}
+	return loadValidators(res)
+}
+
+// isSingleChange 判断next是否由cur增加或删除一个验证人得到，验证人不能重复
+func isSingleChange(cur, next []string) bool {
+	set := make(map[string]bool, len(next))
+	for _, v := range next {
+		if v == "" || set[v] {
+			return false
+		}
+		set[v] = true
+	}
+	same := 0
+	for _, v := range cur {
+		if set[v] {
+			same++
+		}
+	}
+	added, removed := len(next)-same, len(cur)-same
+	return added+removed == 1
+}
+
+// isAuthAddress 判断输入aks在贪心下的签名地址数是否超过当前验证人的半数
+func isAuthAddress(validators []string, aks map[string]float64, threshold float64) bool {
+	for addr := range aks {
+		if !find(addr, validators) {
+			return false
+		}
+	}
+	weights := make([]float64, 0, len(aks))
+	for _, w := range aks {
+		weights = append(weights, w)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(weights)))
+	greedyCount := 0
+	sum := threshold
+	for _, w := range weights {
+		if sum <= 0 {
+			break
+		}
+		sum -= w
+		greedyCount++
+	}
+	return sum <= 0 && greedyCount >= quorum(validators)
+}
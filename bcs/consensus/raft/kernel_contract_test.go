@@ -0,0 +1,83 @@
+package raft
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	bmock "github.com/xuperchain/xupercore/bcs/consensus/mock"
+	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
+)
+
+var (
+	nodeB = "WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT"
+	nodeC = "akf7qunmeaqb51Wu418d6TyPKp4jdLdpV"
+	nodeD = "SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"
+)
+
+func newEditArgs(validates string, aks map[string]float64) map[string][]byte {
+	a := make(map[string][]byte)
+	a["validates"] = []byte(validates)
+	a["rule"] = []byte("1")
+	a["acceptValue"] = []byte("0.6")
+	a["aksWeight"], _ = json.Marshal(aks)
+	return a
+}
+
+func TestMethodEditValidates(t *testing.T) {
+	r := newTestRaft(t, getRaftConsensusConf(bmock.Miner, nodeB))
+	both := map[string]float64{bmock.Miner: 0.5, nodeB: 0.5}
+	m := make(map[string]map[string][]byte)
+
+	// 签名地址数未超过半数
+	args := newEditArgs(bmock.Miner+";"+nodeB+";"+nodeC, map[string]float64{nodeB: 0.6})
+	if _, err := r.methodEditValidates(kmock.NewFakeKContext(args, m)); err != aclErr {
+		t.Fatalf("acl should be checked, err:%v", err)
+	}
+	// 一次只能变更一个验证人
+	args = newEditArgs(bmock.Miner+";"+nodeC+";"+nodeD, both)
+	if _, err := r.methodEditValidates(kmock.NewFakeKContext(args, m)); err != memberChangeErr {
+		t.Fatalf("multiple changes should be rejected, err:%v", err)
+	}
+	args = newEditArgs(bmock.Miner+";"+nodeB+";"+nodeC, both)
+	fakeCtx := kmock.NewFakeKContext(args, m)
+	if _, err := r.methodEditValidates(fakeCtx); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := r.methodGetValidates(fakeCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validators := map[string][]string{}
+	json.Unmarshal(resp.Body, &validators)
+	if len(validators["validators"]) != 3 {
+		t.Fatalf("unexpected validators: %s", resp.Body)
+	}
+
+	// 变更在快照中生效后作为出块人的校验依据
+	key := []byte(fmt.Sprintf("%d_%s", r.version, validateKeys))
+	value, _ := fakeCtx.Get(raftBucket, key)
+	l, _ := r.cCtx.Ledger.(*kmock.FakeLedger)
+	l.SetSnapshot(raftBucket, key, value)
+	if v, _ := r.getValidatesByBlockId([]byte{2}); len(v) != 3 {
+		t.Fatalf("validators not loaded from snapshot: %v", v)
+	}
+}
+
+func TestIsSingleChange(t *testing.T) {
+	cases := []struct {
+		cur, next []string
+		want      bool
+	}{
+		{[]string{"a", "b"}, []string{"a", "b", "c"}, true},
+		{[]string{"a", "b"}, []string{"a"}, true},
+		{[]string{"a", "b"}, []string{"a", "c"}, false},
+		{[]string{"a", "b"}, []string{"b", "a"}, false},
+		{[]string{"a"}, []string{"a", "b", "b"}, false},
+	}
+	for i, c := range cases {
+		if got := isSingleChange(c.cur, c.next); got != c.want {
+			t.Fatalf("case %d: got %v, want %v", i, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,472 @@
+package raft
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	raftPb "github.com/xuperchain/xupercore/bcs/consensus/raft/pb"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+// 本文件实现raft的选举和提交逻辑。
+// 账本主干上的区块即raft日志，区块高度为日志序号，区块共识存储中的CurTerm为日志的任期，
+// 日志复制由区块同步完成，节点之间只交换投票、心跳和确认消息。
+// 节点重启后不持久化投票记录，为避免同一任期重复投票，重启后的一个选举超时内不投票，
+// 且follower在选举超时内收到过leader心跳时拒绝其他候选人的投票请求。
+// leader出块时将已提交的高度写入区块共识存储，随日志持久化，节点重启后从最新区块恢复提交高度。
+
+type role int
+
+const (
+	roleFollower role = iota
+	roleCandidate
+	roleLeader
+)
+
+func (r role) String() string {
+	switch r {
+	case roleLeader:
+		return "leader"
+	case roleCandidate:
+		return "candidate"
+	default:
+		return "follower"
+	}
+}
+
+// leaderHistory node.leaders保留的任期数，避免长期运行时无限增长
+const leaderHistory = 1024
+
+// raftLog 节点本地的raft日志，即账本主干
+type raftLog interface {
+	// lastEntry 返回最新区块的高度、任期和id
+	lastEntry() (height int64, term int64, id []byte)
+	// entryAt 返回主干上height高度区块的任期和id
+	entryAt(height int64) (term int64, id []byte, err error)
+	// lastCommit 返回最新区块中记录的leader已提交的高度
+	lastCommit() int64
+}
+
+// transport 向其他节点发送raft消息
+type transport interface {
+	send(msg *raftPb.RaftMsg, to []string)
+}
+
+// node raft状态机，所有方法并发安全
+type node struct {
+	mutex   sync.Mutex
+	address string
+	conf    *raftConfig
+	log     raftLog
+	net     transport
+	// validators 返回当前的验证人集合
+	validators func() []string
+	now        func() time.Time
+	random     *rand.Rand
+	xlog       logs.Logger
+
+	term     int64
+	role     role
+	votedFor string
+	leader   string
+	votes    map[string]bool
+	// 各任期的leader，用于校验区块的出块人
+	leaders map[int64]string
+	// 选举超时的截止时间
+	deadline time.Time
+	// 最近一次收到leader心跳的时间
+	leaderContact time.Time
+	// 重启后在该时间之前不投票
+	voteLock time.Time
+	// leader最近一次发送心跳的时间
+	heartbeat time.Time
+	// leader记录的各节点与本地主干一致的最高高度，以及最近一次收到确认的时间
+	match   map[string]int64
+	lastAck map[string]time.Time
+	// 已提交的区块，提交后不可回滚
+	commitHeight int64
+	commitId     []byte
+}
+
+func newNode(address string, conf *raftConfig, l raftLog, net transport,
+	validators func() []string, now func() time.Time, xlog logs.Logger) *node {
+	// 随机数种子加入节点地址，避免同时启动的节点选举超时相同
+	seed := now().UnixNano()
+	for _, c := range address {
+		seed = seed*31 + int64(c)
+	}
+	n := &node{
+		address:    address,
+		conf:       conf,
+		log:        l,
+		net:        net,
+		validators: validators,
+		now:        now,
+		random:     rand.New(rand.NewSource(seed)),
+		xlog:       xlog,
+		leaders:    make(map[int64]string),
+		match:      make(map[string]int64),
+		lastAck:    make(map[string]time.Time),
+	}
+	// 任期和提交高度从最新区块恢复
+	_, n.term, _ = l.lastEntry()
+	n.commitTo(l.lastCommit())
+	n.voteLock = now().Add(n.electionTimeout())
+	n.resetDeadline()
+	return n
+}
+
+func (n *node) electionTimeout() time.Duration {
+	return time.Duration(n.conf.ElectionTimeout) * time.Millisecond
+}
+
+// resetDeadline 在[ElectionTimeout, 2*ElectionTimeout)内随机选取下次选举超时
+func (n *node) resetDeadline() {
+	timeout := n.conf.ElectionTimeout + n.random.Int63n(n.conf.ElectionTimeout)
+	n.deadline = n.now().Add(time.Duration(timeout) * time.Millisecond)
+}
+
+// tick 定期驱动状态机，leader发送心跳，其余节点检查选举超时
+func (n *node) tick() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	now := n.now()
+	if n.role == roleLeader {
+		// leader在一个选举超时内未收到多数派确认时退位，避免被隔离的leader继续出块
+		if !n.checkQuorum(now) {
+			n.xlog.Warn("consensus:raft:tick: leader lost quorum, step down", "term", n.term)
+			n.becomeFollower(n.term, "")
+			return
+		}
+		if now.Sub(n.heartbeat) >= time.Duration(n.conf.HeartbeatInterval)*time.Millisecond {
+			n.broadcastHeartbeat()
+		}
+		return
+	}
+	if now.After(n.deadline) && find(n.address, n.validators()) {
+		n.startElection()
+	}
+}
+
+func (n *node) checkQuorum(now time.Time) bool {
+	validators := n.validators()
+	active := 0
+	for _, v := range validators {
+		if v == n.address || now.Sub(n.lastAck[v]) < n.electionTimeout() {
+			active++
+		}
+	}
+	return active >= quorum(validators)
+}
+
+func (n *node) startElection() {
+	n.term++
+	n.role = roleCandidate
+	n.votedFor = n.address
+	n.leader = ""
+	n.votes = map[string]bool{n.address: true}
+	n.resetDeadline()
+	n.xlog.Info("consensus:raft:startElection: start election", "term", n.term)
+	if n.hasQuorum(n.votes) {
+		n.becomeLeader()
+		return
+	}
+	height, term, _ := n.log.lastEntry()
+	n.net.send(&raftPb.RaftMsg{
+		Type:       raftPb.RaftMsgType_VOTE_REQUEST,
+		Term:       n.term,
+		From:       n.address,
+		LastHeight: height,
+		LastTerm:   term,
+	}, n.peers())
+}
+
+func (n *node) hasQuorum(set map[string]bool) bool {
+	validators := n.validators()
+	count := 0
+	for _, v := range validators {
+		if set[v] {
+			count++
+		}
+	}
+	return count >= quorum(validators)
+}
+
+func (n *node) becomeLeader() {
+	n.role = roleLeader
+	n.leader = n.address
+	n.setLeader(n.term, n.address)
+	n.match = make(map[string]int64)
+	now := n.now()
+	// 当选时视为所有节点均活跃，一个选举超时后开始检查多数派
+	for _, v := range n.validators() {
+		n.lastAck[v] = now
+	}
+	n.xlog.Info("consensus:raft:becomeLeader: become leader", "term", n.term)
+	n.broadcastHeartbeat()
+}
+
+func (n *node) becomeFollower(term int64, leader string) {
+	if term > n.term {
+		n.term = term
+		n.votedFor = ""
+	}
+	n.role = roleFollower
+	n.leader = leader
+	if leader != "" {
+		n.setLeader(term, leader)
+		n.leaderContact = n.now()
+	}
+	n.resetDeadline()
+}
+
+// setLeader 记录任期的leader，并清理leaderHistory个任期之前的记录
+func (n *node) setLeader(term int64, leader string) {
+	n.leaders[term] = leader
+	for t := range n.leaders {
+		if t <= term-leaderHistory {
+			delete(n.leaders, t)
+		}
+	}
+}
+
+func (n *node) broadcastHeartbeat() {
+	n.heartbeat = n.now()
+	height, _, id := n.log.lastEntry()
+	n.net.send(&raftPb.RaftMsg{
+		Type:         raftPb.RaftMsgType_HEARTBEAT,
+		Term:         n.term,
+		From:         n.address,
+		TipHeight:    height,
+		TipId:        id,
+		CommitHeight: n.commitHeight,
+		CommitId:     n.commitId,
+	}, n.peers())
+}
+
+// peers 返回除自己以外的验证人
+func (n *node) peers() []string {
+	var peers []string
+	for _, v := range n.validators() {
+		if v != n.address {
+			peers = append(peers, v)
+		}
+	}
+	return peers
+}
+
+// step 处理其他节点发来的消息
+func (n *node) step(msg *raftPb.RaftMsg) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if msg.GetFrom() == n.address || !find(msg.GetFrom(), n.validators()) {
+		return
+	}
+	switch msg.GetType() {
+	case raftPb.RaftMsgType_VOTE_REQUEST:
+		n.handleVoteRequest(msg)
+	case raftPb.RaftMsgType_VOTE_RESPONSE:
+		n.handleVoteResponse(msg)
+	case raftPb.RaftMsgType_HEARTBEAT:
+		n.handleHeartbeat(msg)
+	case raftPb.RaftMsgType_ACK:
+		n.handleAck(msg)
+	}
+}
+
+func (n *node) handleVoteRequest(msg *raftPb.RaftMsg) {
+	now := n.now()
+	// 仍与leader保持联系或重启后处于锁定期时，忽略投票请求且不提升任期
+	inLease := n.role == roleLeader || (n.leader != "" && now.Sub(n.leaderContact) < n.electionTimeout())
+	if inLease || now.Before(n.voteLock) {
+		return
+	}
+	if msg.GetTerm() > n.term {
+		n.becomeFollower(msg.GetTerm(), "")
+	}
+	height, term, _ := n.log.lastEntry()
+	// 候选人的最新区块至少与本地一样新时才投票
+	upToDate := msg.GetLastTerm() > term || (msg.GetLastTerm() == term && msg.GetLastHeight() >= height)
+	granted := msg.GetTerm() == n.term && upToDate && (n.votedFor == "" || n.votedFor == msg.GetFrom())
+	if granted {
+		n.votedFor = msg.GetFrom()
+		n.resetDeadline()
+	}
+	n.net.send(&raftPb.RaftMsg{
+		Type:    raftPb.RaftMsgType_VOTE_RESPONSE,
+		Term:    n.term,
+		From:    n.address,
+		Granted: granted,
+	}, []string{msg.GetFrom()})
+}
+
+func (n *node) handleVoteResponse(msg *raftPb.RaftMsg) {
+	if msg.GetTerm() > n.term {
+		n.becomeFollower(msg.GetTerm(), "")
+		return
+	}
+	if n.role != roleCandidate || msg.GetTerm() != n.term || !msg.GetGranted() {
+		return
+	}
+	n.votes[msg.GetFrom()] = true
+	if n.hasQuorum(n.votes) {
+		n.becomeLeader()
+	}
+}
+
+func (n *node) handleHeartbeat(msg *raftPb.RaftMsg) {
+	if msg.GetTerm() < n.term {
+		// 通知过期的leader退位
+		n.sendAck(msg.GetFrom())
+		return
+	}
+	n.becomeFollower(msg.GetTerm(), msg.GetFrom())
+	// 本地主干包含leader已提交的区块时，同步提交高度
+	if msg.GetCommitHeight() > n.commitHeight {
+		if _, id, err := n.log.entryAt(msg.GetCommitHeight()); err == nil && bytes.Equal(id, msg.GetCommitId()) {
+			n.commitHeight = msg.GetCommitHeight()
+			n.commitId = msg.GetCommitId()
+		}
+	}
+	n.sendAck(msg.GetFrom())
+}
+
+// commitTo 将提交高度推进到本地主干上height高度的区块
+func (n *node) commitTo(height int64) {
+	if height <= n.commitHeight {
+		return
+	}
+	if _, id, err := n.log.entryAt(height); err == nil {
+		n.commitHeight = height
+		n.commitId = id
+	}
+}
+
+func (n *node) sendAck(to string) {
+	height, _, id := n.log.lastEntry()
+	n.net.send(&raftPb.RaftMsg{
+		Type:      raftPb.RaftMsgType_ACK,
+		Term:      n.term,
+		From:      n.address,
+		TipHeight: height,
+		TipId:     id,
+	}, []string{to})
+}
+
+func (n *node) handleAck(msg *raftPb.RaftMsg) {
+	if msg.GetTerm() > n.term {
+		n.becomeFollower(msg.GetTerm(), "")
+		return
+	}
+	if n.role != roleLeader || msg.GetTerm() != n.term {
+		return
+	}
+	n.lastAck[msg.GetFrom()] = n.now()
+	// 对方最新区块在本地主干上时，对方与本地在该高度之前一致
+	if _, id, err := n.log.entryAt(msg.GetTipHeight()); err == nil && bytes.Equal(id, msg.GetTipId()) {
+		n.match[msg.GetFrom()] = msg.GetTipHeight()
+	}
+	n.advanceCommit()
+}
+
+// advanceCommit 多数派一致的最高区块属于当前任期时提交该区块，之前的区块随之提交
+func (n *node) advanceCommit() {
+	tipHeight, _, _ := n.log.lastEntry()
+	validators := n.validators()
+	var heights []int64
+	for _, v := range validators {
+		if v == n.address {
+			heights = append(heights, tipHeight)
+			continue
+		}
+		heights = append(heights, n.match[v])
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+	if len(heights) == 0 {
+		return
+	}
+	height := heights[quorum(validators)-1]
+	if height > tipHeight {
+		height = tipHeight
+	}
+	if height <= n.commitHeight {
+		return
+	}
+	term, id, err := n.log.entryAt(height)
+	if err != nil || term != n.term {
+		return
+	}
+	n.commitHeight = height
+	n.commitId = id
+	n.xlog.Debug("consensus:raft:advanceCommit: commit block", "height", height, "term", term)
+}
+
+// onBlock 本地账本确认新区块后调用，follower向leader确认，leader尝试推进提交高度，
+// commit为区块中记录的leader已提交的高度，区块在主干上时其祖先同样在主干上
+func (n *node) onBlock(term int64, proposer string, commit int64) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if term > n.term {
+		n.becomeFollower(term, proposer)
+	}
+	n.commitTo(commit)
+	switch {
+	case n.role == roleLeader:
+		n.advanceCommit()
+	case n.leader != "":
+		n.sendAck(n.leader)
+	}
+}
+
+// checkBlock 校验任期为term的区块的出块人，已提交的区块不允许被替换
+func (n *node) checkBlock(height, term int64, id []byte, proposer string) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if leader, ok := n.leaders[term]; ok && leader != proposer {
+		return ErrInvalidProposer
+	}
+	if height <= n.commitHeight {
+		if _, localId, err := n.log.entryAt(height); err == nil && !bytes.Equal(localId, id) {
+			return ErrConflictCommit
+		}
+	}
+	return nil
+}
+
+// isLeader 返回是否为当前任期的leader以及当前任期
+func (n *node) isLeader() (bool, int64) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.role == roleLeader, n.term
+}
+
+// needNoop leader当选后尚未在本任期出块时需要出一个空块，以便提交之前任期的区块
+func (n *node) needNoop() bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	_, term, _ := n.log.lastEntry()
+	return n.role == roleLeader && term < n.term
+}
+
+type nodeStatus struct {
+	Term         int64
+	Role         string
+	Leader       string
+	CommitHeight int64
+	CommitId     []byte
+}
+
+func (n *node) status() nodeStatus {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return nodeStatus{
+		Term:         n.term,
+		Role:         n.role.String(),
+		Leader:       n.leader,
+		CommitHeight: n.commitHeight,
+		CommitId:     n.commitId,
+	}
+}
@@ -0,0 +1,333 @@
+package raft
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	bmock "github.com/xuperchain/xupercore/bcs/consensus/mock"
+	raftPb "github.com/xuperchain/xupercore/bcs/consensus/raft/pb"
+)
+
+type testEntry struct {
+	term   int64
+	id     []byte
+	commit int64
+}
+
+// testLog 以切片模拟账本主干，下标为区块高度
+type testLog struct {
+	entries []testEntry
+}
+
+func (l *testLog) lastEntry() (int64, int64, []byte) {
+	e := l.entries[len(l.entries)-1]
+	return int64(len(l.entries) - 1), e.term, e.id
+}
+
+func (l *testLog) entryAt(height int64) (int64, []byte, error) {
+	if height < 0 || height >= int64(len(l.entries)) {
+		return 0, nil, errors.New("not found")
+	}
+	return l.entries[height].term, l.entries[height].id, nil
+}
+
+func (l *testLog) lastCommit() int64 {
+	return l.entries[len(l.entries)-1].commit
+}
+
+func (l *testLog) append(term int64, id string) {
+	l.appendCommit(term, id, 0)
+}
+
+func (l *testLog) appendCommit(term int64, id string, commit int64) {
+	l.entries = append(l.entries, testEntry{term: term, id: []byte(id), commit: commit})
+}
+
+// testCluster 内存中的多节点集群，消息按发送顺序投递，可以隔离节点
+type testCluster struct {
+	now        time.Time
+	nodes      map[string]*node
+	logs       map[string]*testLog
+	queue      []*raftPb.RaftMsg
+	to         [][]string
+	isolated   map[string]bool
+	validators []string
+}
+
+type testTransport struct {
+	c *testCluster
+}
+
+func (t *testTransport) send(msg *raftPb.RaftMsg, to []string) {
+	t.c.queue = append(t.c.queue, msg)
+	t.c.to = append(t.c.to, to)
+}
+
+func newTestCluster(addrs ...string) *testCluster {
+	c := &testCluster{
+		now:        time.Unix(1600000000, 0),
+		nodes:      make(map[string]*node),
+		logs:       make(map[string]*testLog),
+		isolated:   make(map[string]bool),
+		validators: addrs,
+	}
+	conf := &raftConfig{MinInterval: 100, HeartbeatInterval: 100, ElectionTimeout: 1000}
+	xlog := bmock.NewFakeLogger()
+	for _, addr := range addrs {
+		l := &testLog{}
+		l.append(0, "genesis")
+		c.logs[addr] = l
+		c.nodes[addr] = newNode(addr, conf, l, &testTransport{c}, func() []string { return c.validators },
+			func() time.Time { return c.now }, xlog)
+	}
+	return c
+}
+
+// deliver 投递队列中的消息直到队列为空，被隔离节点发出和接收的消息均被丢弃
+func (c *testCluster) deliver() {
+	for len(c.queue) > 0 {
+		msg, to := c.queue[0], c.to[0]
+		c.queue, c.to = c.queue[1:], c.to[1:]
+		if c.isolated[msg.GetFrom()] {
+			continue
+		}
+		for _, addr := range to {
+			if !c.isolated[addr] {
+				c.nodes[addr].step(msg)
+			}
+		}
+	}
+}
+
+// advance 推进时钟并驱动所有节点，被隔离的节点同样运行
+func (c *testCluster) advance(d time.Duration) {
+	step := 50 * time.Millisecond
+	for elapsed := time.Duration(0); elapsed < d; elapsed += step {
+		c.now = c.now.Add(step)
+		for _, addr := range c.validators {
+			c.nodes[addr].tick()
+		}
+		c.deliver()
+	}
+}
+
+func (c *testCluster) leaders() []string {
+	var leaders []string
+	for _, addr := range c.validators {
+		if isLeader, _ := c.nodes[addr].isLeader(); isLeader && !c.isolated[addr] {
+			leaders = append(leaders, addr)
+		}
+	}
+	return leaders
+}
+
+func (c *testCluster) electLeader(t *testing.T) string {
+	c.advance(5 * time.Second)
+	leaders := c.leaders()
+	if len(leaders) != 1 {
+		t.Fatalf("expect one leader, got:%v", leaders)
+	}
+	return leaders[0]
+}
+
+// propose leader出块并同步给未被隔离的节点，区块中记录leader已提交的高度
+func (c *testCluster) propose(leader, id string) {
+	_, term := c.nodes[leader].isLeader()
+	commit := c.nodes[leader].status().CommitHeight
+	for _, addr := range c.validators {
+		if addr == leader || !c.isolated[addr] {
+			c.logs[addr].appendCommit(term, id, commit)
+		}
+	}
+	for _, addr := range c.validators {
+		if addr == leader || !c.isolated[addr] {
+			c.nodes[addr].onBlock(term, leader, commit)
+		}
+	}
+	c.deliver()
+}
+
+func TestElection(t *testing.T) {
+	c := newTestCluster("A", "B", "C")
+	leader := c.electLeader(t)
+	for _, addr := range c.validators {
+		if s := c.nodes[addr].status(); s.Leader != leader || s.Term != 1 {
+			t.Fatalf("node %s has unexpected status:%+v", addr, s)
+		}
+	}
+
+	// leader被隔离后其余节点重新选举，原leader失去多数派后退位
+	c.isolated[leader] = true
+	newLeader := c.electLeader(t)
+	if newLeader == leader {
+		t.Fatal("isolated leader should not be re-elected")
+	}
+	if isLeader, _ := c.nodes[leader].isLeader(); isLeader {
+		t.Fatal("isolated leader should step down")
+	}
+	// 原leader恢复后集群重新收敛到同一个leader
+	delete(c.isolated, leader)
+	newLeader = c.electLeader(t)
+	s := c.nodes[newLeader].status()
+	for _, addr := range c.validators {
+		if cur := c.nodes[addr].status(); cur.Leader != newLeader || cur.Term != s.Term {
+			t.Fatalf("node %s should follow %s, status:%+v", addr, newLeader, cur)
+		}
+	}
+}
+
+func TestLeaderHistory(t *testing.T) {
+	c := newTestCluster("A", "B", "C")
+	leader := c.electLeader(t)
+	n := c.nodes[leader]
+	// 任期大幅推进后只保留最近leaderHistory个任期的leader
+	n.mutex.Lock()
+	n.setLeader(leaderHistory+10, "B")
+	n.mutex.Unlock()
+	if len(n.leaders) != 1 {
+		t.Fatalf("leaders of old terms should be pruned, got:%v", n.leaders)
+	}
+	if err := n.checkBlock(1, leaderHistory+10, nil, "C"); err != ErrInvalidProposer {
+		t.Fatalf("block from non-leader should be rejected, err:%v", err)
+	}
+}
+
+func TestVoteUpToDate(t *testing.T) {
+	c := newTestCluster("A", "B", "C")
+	leader := c.electLeader(t)
+	var lagging, voter string
+	for _, addr := range c.validators {
+		if addr == leader {
+			continue
+		}
+		if lagging == "" {
+			lagging = addr
+		} else {
+			voter = addr
+		}
+	}
+	// lagging节点错过一个区块
+	c.isolated[lagging] = true
+	c.propose(leader, "b1")
+	delete(c.isolated, lagging)
+
+	vote := func(from string, term, lastHeight, lastTerm int64) bool {
+		n := c.nodes[voter]
+		n.mutex.Lock()
+		// 模拟leader失联
+		n.leader = ""
+		n.mutex.Unlock()
+		n.step(&raftPb.RaftMsg{
+			Type:       raftPb.RaftMsgType_VOTE_REQUEST,
+			Term:       term,
+			From:       from,
+			LastHeight: lastHeight,
+			LastTerm:   lastTerm,
+		})
+		resp := c.queue[len(c.queue)-1]
+		c.queue, c.to = nil, nil
+		return resp.GetGranted()
+	}
+	// 重启后的锁定期内不投票
+	c.nodes[voter].voteLock = c.now.Add(time.Second)
+	c.nodes[voter].step(&raftPb.RaftMsg{Type: raftPb.RaftMsgType_VOTE_REQUEST, Term: 10, From: lagging, LastHeight: 1, LastTerm: 1})
+	if len(c.queue) != 0 {
+		t.Fatal("vote request should be ignored after restart")
+	}
+	c.nodes[voter].voteLock = time.Time{}
+	// 区块落后的候选人无法获得选票
+	if vote(lagging, 10, 0, 0) {
+		t.Fatal("candidate with stale blocks should not be voted")
+	}
+	if !vote(lagging, 11, 1, 1) || !vote(lagging, 11, 1, 1) {
+		t.Fatal("up-to-date candidate should be voted")
+	}
+	// 同一任期只投一票
+	if vote(leader, 11, 1, 1) {
+		t.Fatal("should vote only once in a term")
+	}
+}
+
+func TestCommit(t *testing.T) {
+	c := newTestCluster("A", "B", "C")
+	leader := c.electLeader(t)
+	// 多数派确认后提交，提交高度同步给follower
+	c.propose(leader, "b1")
+	if s := c.nodes[leader].status(); s.CommitHeight != 1 {
+		t.Fatalf("block should be committed, status:%+v", s)
+	}
+	c.advance(200 * time.Millisecond)
+	for _, addr := range c.validators {
+		if s := c.nodes[addr].status(); s.CommitHeight != 1 || string(s.CommitId) != "b1" {
+			t.Fatalf("node %s has unexpected commit:%+v", addr, s)
+		}
+	}
+	// 已提交的区块不能被替换
+	if err := c.nodes[leader].checkBlock(1, 1, []byte("fork"), leader); err != ErrConflictCommit {
+		t.Fatalf("conflict block should be rejected, err:%v", err)
+	}
+	// 同一任期只能有一个出块人
+	var other string
+	for _, addr := range c.validators {
+		if addr != leader {
+			other = addr
+		}
+	}
+	if err := c.nodes[leader].checkBlock(2, 1, []byte("b2"), other); err != ErrInvalidProposer {
+		t.Fatalf("block from non-leader should be rejected, err:%v", err)
+	}
+
+	// 只有leader一个节点时无法获得多数派确认，区块不提交
+	for _, addr := range c.validators {
+		if addr != leader {
+			c.isolated[addr] = true
+		}
+	}
+	c.propose(leader, "b2")
+	if s := c.nodes[leader].status(); s.CommitHeight != 1 {
+		t.Fatalf("block without quorum should not be committed, status:%+v", s)
+	}
+
+	// 重启后从最新区块中记录的提交高度恢复
+	restarted := newNode(leader, c.nodes[leader].conf, c.logs[leader], &testTransport{c},
+		func() []string { return c.validators }, func() time.Time { return c.now }, bmock.NewFakeLogger())
+	if s := restarted.status(); s.CommitHeight != 1 || string(s.CommitId) != "b1" {
+		t.Fatalf("commit should be restored after restart, status:%+v", s)
+	}
+	if err := restarted.checkBlock(1, 1, []byte("fork"), leader); err != ErrConflictCommit {
+		t.Fatalf("conflict block should be rejected after restart, err:%v", err)
+	}
+}
+
+func TestCommitPreviousTerm(t *testing.T) {
+	c := newTestCluster("A", "B", "C")
+	leader := c.electLeader(t)
+	c.isolated[leader] = true
+	c.electLeader(t)
+	delete(c.isolated, leader)
+	newLeader := c.electLeader(t)
+
+	// 之前任期的区块不能直接提交，新leader出块后随之提交
+	_, term := c.nodes[newLeader].isLeader()
+	for _, addr := range c.validators {
+		c.logs[addr].append(term-1, "old")
+	}
+	if !c.nodes[newLeader].needNoop() {
+		t.Fatal("new leader should need a noop block")
+	}
+	c.nodes[newLeader].mutex.Lock()
+	c.nodes[newLeader].advanceCommit()
+	c.nodes[newLeader].mutex.Unlock()
+	if s := c.nodes[newLeader].status(); s.CommitHeight != 0 {
+		t.Fatalf("block of previous term should not be committed directly, status:%+v", s)
+	}
+	c.propose(newLeader, fmt.Sprintf("noop%d", term))
+	if s := c.nodes[newLeader].status(); s.CommitHeight != 2 {
+		t.Fatalf("blocks should be committed by current term block, status:%+v", s)
+	}
+	if c.nodes[newLeader].needNoop() {
+		t.Fatal("noop block has been produced")
+	}
+}
@@ -0,0 +1,224 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: raftMsg.proto
+
+package raftPb
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// RaftMsgType raft共识节点之间的消息类型
+type RaftMsgType int32
+
+const (
+	// 候选人请求投票
+	RaftMsgType_VOTE_REQUEST RaftMsgType = 0
+	// 对投票请求的回复
+	RaftMsgType_VOTE_RESPONSE RaftMsgType = 1
+	// leader定期发送的心跳，携带leader的最新区块和已提交区块
+	RaftMsgType_HEARTBEAT RaftMsgType = 2
+	// follower对心跳和新区块的确认，携带本地的最新区块
+	RaftMsgType_ACK RaftMsgType = 3
+)
+
+var RaftMsgType_name = map[int32]string{
+	0: "VOTE_REQUEST",
+	1: "VOTE_RESPONSE",
+	2: "HEARTBEAT",
+	3: "ACK",
+}
+
+var RaftMsgType_value = map[string]int32{
+	"VOTE_REQUEST":  0,
+	"VOTE_RESPONSE": 1,
+	"HEARTBEAT":     2,
+	"ACK":           3,
+}
+
+func (x RaftMsgType) String() string {
+	return proto.EnumName(RaftMsgType_name, int32(x))
+}
+
+func (RaftMsgType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ff5d9944c887f44b, []int{0}
+}
+
+// RaftMsg raft共识节点之间交互的消息，各类型消息共用一个结构
+type RaftMsg struct {
+	Type RaftMsgType `protobuf:"varint,1,opt,name=type,proto3,enum=raftPb.RaftMsgType" json:"type,omitempty"`
+	// 发送方的任期
+	Term int64 `protobuf:"varint,2,opt,name=term,proto3" json:"term,omitempty"`
+	// 发送方地址
+	From string `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	// VOTE_REQUEST: 候选人最新区块的高度和任期
+	LastHeight int64 `protobuf:"varint,4,opt,name=lastHeight,proto3" json:"lastHeight,omitempty"`
+	LastTerm   int64 `protobuf:"varint,5,opt,name=lastTerm,proto3" json:"lastTerm,omitempty"`
+	// VOTE_RESPONSE: 是否投票给候选人
+	Granted bool `protobuf:"varint,6,opt,name=granted,proto3" json:"granted,omitempty"`
+	// HEARTBEAT/ACK: 发送方的最新区块
+	TipHeight int64  `protobuf:"varint,7,opt,name=tipHeight,proto3" json:"tipHeight,omitempty"`
+	TipId     []byte `protobuf:"bytes,8,opt,name=tipId,proto3" json:"tipId,omitempty"`
+	// HEARTBEAT: leader已提交的区块
+	CommitHeight int64  `protobuf:"varint,9,opt,name=commitHeight,proto3" json:"commitHeight,omitempty"`
+	CommitId     []byte `protobuf:"bytes,10,opt,name=commitId,proto3" json:"commitId,omitempty"`
+	// 发送者的公钥，用于校验from
+	PublicKey string `protobuf:"bytes,11,opt,name=publicKey,proto3" json:"publicKey,omitempty"`
+	// 发送者对消息(不含publicKey和sign)的签名
+	Sign                 []byte   `protobuf:"bytes,12,opt,name=sign,proto3" json:"sign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RaftMsg) Reset()         { *m = RaftMsg{} }
+func (m *RaftMsg) String() string { return proto.CompactTextString(m) }
+func (*RaftMsg) ProtoMessage()    {}
+func (*RaftMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ff5d9944c887f44b, []int{0}
+}
+
+func (m *RaftMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RaftMsg.Unmarshal(m, b)
+}
+func (m *RaftMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RaftMsg.Marshal(b, m, deterministic)
+}
+func (m *RaftMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RaftMsg.Merge(m, src)
+}
+func (m *RaftMsg) XXX_Size() int {
+	return xxx_messageInfo_RaftMsg.Size(m)
+}
+func (m *RaftMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_RaftMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RaftMsg proto.InternalMessageInfo
+
+func (m *RaftMsg) GetType() RaftMsgType {
+	if m != nil {
+		return m.Type
+	}
+	return RaftMsgType_VOTE_REQUEST
+}
+
+func (m *RaftMsg) GetTerm() int64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *RaftMsg) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *RaftMsg) GetLastHeight() int64 {
+	if m != nil {
+		return m.LastHeight
+	}
+	return 0
+}
+
+func (m *RaftMsg) GetLastTerm() int64 {
+	if m != nil {
+		return m.LastTerm
+	}
+	return 0
+}
+
+func (m *RaftMsg) GetGranted() bool {
+	if m != nil {
+		return m.Granted
+	}
+	return false
+}
+
+func (m *RaftMsg) GetTipHeight() int64 {
+	if m != nil {
+		return m.TipHeight
+	}
+	return 0
+}
+
+func (m *RaftMsg) GetTipId() []byte {
+	if m != nil {
+		return m.TipId
+	}
+	return nil
+}
+
+func (m *RaftMsg) GetCommitHeight() int64 {
+	if m != nil {
+		return m.CommitHeight
+	}
+	return 0
+}
+
+func (m *RaftMsg) GetCommitId() []byte {
+	if m != nil {
+		return m.CommitId
+	}
+	return nil
+}
+
+func (m *RaftMsg) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *RaftMsg) GetSign() []byte {
+	if m != nil {
+		return m.Sign
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("raftPb.RaftMsgType", RaftMsgType_name, RaftMsgType_value)
+	proto.RegisterType((*RaftMsg)(nil), "raftPb.RaftMsg")
+}
+
+func init() { proto.RegisterFile("raftMsg.proto", fileDescriptor_ff5d9944c887f44b) }
+
+var fileDescriptor_ff5d9944c887f44b = []byte{
+	// 305 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x91, 0xcf, 0x4e, 0xc2, 0x40,
+	0x10, 0xc6, 0x5d, 0x0a, 0x94, 0x0e, 0xc5, 0xd4, 0xd1, 0xc3, 0xc6, 0x18, 0xd3, 0x70, 0xb1, 0xf1,
+	0xc0, 0x41, 0x9f, 0x00, 0x4d, 0x13, 0x90, 0x28, 0xb8, 0x54, 0xaf, 0x86, 0x3f, 0x4b, 0xdd, 0x84,
+	0xd2, 0x4d, 0x59, 0x0f, 0xbc, 0xa8, 0xcf, 0x63, 0x76, 0x5a, 0xfe, 0x78, 0x9b, 0xdf, 0x37, 0xdf,
+	0x37, 0x9d, 0xe9, 0x42, 0xa7, 0x98, 0xad, 0xcc, 0xeb, 0x36, 0xed, 0xe9, 0x22, 0x37, 0x39, 0x36,
+	0x2d, 0x4e, 0xe6, 0xdd, 0xdf, 0x1a, 0xb8, 0xa2, 0xec, 0xe0, 0x1d, 0xd4, 0xcd, 0x4e, 0x4b, 0xce,
+	0x42, 0x16, 0x9d, 0x3f, 0x5c, 0xf6, 0x4a, 0x4b, 0xaf, 0x6a, 0x27, 0x3b, 0x2d, 0x05, 0x19, 0x10,
+	0xa1, 0x6e, 0x64, 0x91, 0xf1, 0x5a, 0xc8, 0x22, 0x47, 0x50, 0x6d, 0xb5, 0x55, 0x91, 0x67, 0xdc,
+	0x09, 0x59, 0xe4, 0x09, 0xaa, 0xf1, 0x16, 0x60, 0x3d, 0xdb, 0x9a, 0x81, 0x54, 0xe9, 0xb7, 0xe1,
+	0x75, 0x72, 0x9f, 0x28, 0x78, 0x0d, 0x2d, 0x4b, 0x89, 0x9d, 0xd5, 0xa0, 0xee, 0x81, 0x91, 0x83,
+	0x9b, 0x16, 0xb3, 0x8d, 0x91, 0x4b, 0xde, 0x0c, 0x59, 0xd4, 0x12, 0x7b, 0xc4, 0x1b, 0xf0, 0x8c,
+	0xd2, 0xd5, 0x50, 0x97, 0x62, 0x47, 0x01, 0xaf, 0xa0, 0x61, 0x94, 0x1e, 0x2e, 0x79, 0x2b, 0x64,
+	0x91, 0x2f, 0x4a, 0xc0, 0x2e, 0xf8, 0x8b, 0x3c, 0xcb, 0xd4, 0x7e, 0x17, 0x8f, 0x62, 0xff, 0x34,
+	0xbb, 0x4d, 0xc9, 0xc3, 0x25, 0x07, 0x0a, 0x1f, 0xd8, 0x7e, 0x53, 0xff, 0xcc, 0xd7, 0x6a, 0x31,
+	0x92, 0x3b, 0xde, 0xa6, 0x13, 0x8f, 0x82, 0xbd, 0x7d, 0xab, 0xd2, 0x0d, 0xf7, 0x29, 0x45, 0xf5,
+	0xfd, 0x0b, 0xb4, 0x4f, 0x7e, 0x1c, 0x06, 0xe0, 0x7f, 0x8e, 0x93, 0xf8, 0x4b, 0xc4, 0xef, 0x1f,
+	0xf1, 0x34, 0x09, 0xce, 0xf0, 0x02, 0x3a, 0x95, 0x32, 0x9d, 0x8c, 0xdf, 0xa6, 0x71, 0xc0, 0xb0,
+	0x03, 0xde, 0x20, 0xee, 0x8b, 0xe4, 0x29, 0xee, 0x27, 0x41, 0x0d, 0x5d, 0x70, 0xfa, 0xcf, 0xa3,
+	0xc0, 0x99, 0x37, 0xe9, 0xcd, 0x1e, 0xff, 0x06, 0x00, 0xa1, 0xe9, 0xa8, 0xd5, 0xc4, 0x01, 0x00,
+	0x00,
+}
@@ -0,0 +1,469 @@
+package raft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/crypto/core/hash"
+	raftPb "github.com/xuperchain/xupercore/bcs/consensus/raft/pb"
+	"github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/consensus"
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/consensus/def"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/timer"
+	"github.com/xuperchain/xupercore/lib/utils"
+	xuperp2p "github.com/xuperchain/xupercore/protos"
+)
+
+func init() {
+	consensus.Register("raft", NewRaftConsensus)
+}
+
+// raftConsensus 面向许可链的崩溃容错共识，leader由raft选举产生，
+// 有交易时按照最小出块间隔出块，区块被多数派节点确认后提交，提交后的区块不可回滚
+type raftConsensus struct {
+	cCtx        cctx.ConsensusCtx
+	bcName      string
+	address     string
+	config      *raftConfig
+	version     int64
+	startHeight int64
+	status      *RaftStatus
+	node        *node
+	contract    contract.Manager
+	kMethod     map[string]contract.KernMethod
+	log         logs.Logger
+
+	// 按照tip区块缓存验证人集合
+	mutex      sync.Mutex
+	cacheTipId []byte
+	cacheVali  []string
+
+	p2pMsgChan chan *xuperp2p.XuperMessage
+	subscriber p2p.Subscriber
+	quitCh     chan bool
+}
+
+// NewRaftConsensus 初始化实例
+func NewRaftConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consensus.ConsensusImplInterface {
+	if cCtx.XLog == nil {
+		return nil
+	}
+	if cCtx.Crypto == nil || cCtx.Address == nil {
+		cCtx.XLog.Error("consensus:raft:NewRaftConsensus: CryptoClient in context is nil")
+		return nil
+	}
+	if cCtx.Ledger == nil {
+		cCtx.XLog.Error("consensus:raft:NewRaftConsensus: Ledger in context is nil")
+		return nil
+	}
+	if cCfg.ConsensusName != "raft" {
+		cCtx.XLog.Error("consensus:raft:NewRaftConsensus: consensus name in config is wrong", "name", cCfg.ConsensusName)
+		return nil
+	}
+	config, err := buildConfig([]byte(cCfg.Config))
+	if err != nil {
+		cCtx.XLog.Error("consensus:raft:NewRaftConsensus: raft config error", "error", err)
+		return nil
+	}
+	version, err := ParseVersion(cCfg.Config)
+	if err != nil {
+		cCtx.XLog.Error("consensus:raft:NewRaftConsensus: version error", "error", err)
+		return nil
+	}
+
+	r := &raftConsensus{
+		cCtx:        cCtx,
+		bcName:      cCtx.BcName,
+		address:     cCtx.Address.Address,
+		config:      config,
+		version:     version,
+		startHeight: cCfg.StartHeight,
+		contract:    cCtx.Contract,
+		log:         cCtx.XLog,
+		p2pMsgChan:  make(chan *xuperp2p.XuperMessage, 1000),
+		quitCh:      make(chan bool, 1),
+	}
+	r.node = newNode(r.address, config, r, r, r.tipValidators, time.Now, cCtx.XLog)
+	r.status = &RaftStatus{
+		Version:     version,
+		StartHeight: cCfg.StartHeight,
+		Index:       cCfg.Index,
+		raft:        r,
+	}
+	r.kMethod = map[string]contract.KernMethod{
+		contractEditValidate: r.methodEditValidates,
+		contractGetValidates: r.methodGetValidates,
+	}
+	cCtx.XLog.Debug("consensus:raft:NewRaftConsensus: create a raft instance successfully!")
+	return r
+}
+
+// CompeteMaster 返回是否为矿工以及是否需要进行SyncBlock
+// leader距上一区块超过最小出块间隔时出块，其余节点等待一个心跳间隔后同步区块
+func (r *raftConsensus) CompeteMaster(height int64) (bool, bool, error) {
+	if isLeader, _ := r.node.isLeader(); !isLeader {
+		time.Sleep(time.Duration(r.config.HeartbeatInterval) * time.Millisecond)
+		return false, false, nil
+	}
+	tipBlock := r.cCtx.Ledger.GetTipBlock()
+	wait := time.Unix(0, tipBlock.GetTimestamp()).Add(time.Duration(r.config.MinInterval) * time.Millisecond).Sub(time.Now())
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	isLeader, term := r.node.isLeader()
+	r.log.Debug("consensus:raft:CompeteMaster", "isMiner", isLeader, "term", term, "height", height)
+	return isLeader, false, nil
+}
+
+// AllowEmptyBlock 实现consensus.EmptyBlockDecider接口
+// leader当选后出一个空块用于提交之前任期的区块，配置max_interval时超过该间隔出空块
+func (r *raftConsensus) AllowEmptyBlock(height int64) bool {
+	if r.node.needNoop() {
+		return true
+	}
+	if r.config.MaxInterval <= 0 {
+		return false
+	}
+	tipBlock := r.cCtx.Ledger.GetTipBlock()
+	return time.Now().UnixNano()-tipBlock.GetTimestamp() >= r.config.MaxInterval*int64(time.Millisecond)
+}
+
+// CheckMinerMatch 查看block是否合法
+func (r *raftConsensus) CheckMinerMatch(ctx xcontext.XContext, block cctx.BlockInterface) (bool, error) {
+	if err := r.verifySign(block); err != nil {
+		ctx.GetLog().Warn("consensus:raft:CheckMinerMatch: verify sign error", "blockId", utils.F(block.GetBlockid()), "err", err)
+		return false, err
+	}
+	// 出块人必须是父区块时的验证人
+	validators, err := r.getValidatesByBlockId(block.GetPreHash())
+	if err != nil {
+		return false, err
+	}
+	proposer := string(block.GetProposer())
+	if !find(proposer, validators) {
+		ctx.GetLog().Warn("consensus:raft:CheckMinerMatch: proposer is not a validator", "proposer", proposer,
+			"validators", validators)
+		return false, ErrInvalidProposer
+	}
+	// 任期不能低于父区块
+	term := r.blockTerm(block)
+	preBlock, err := r.cCtx.Ledger.QueryBlockHeader(block.GetPreHash())
+	if err != nil {
+		return false, err
+	}
+	if term <= 0 || term < r.blockTerm(preBlock) {
+		ctx.GetLog().Warn("consensus:raft:CheckMinerMatch: invalid term", "term", term, "blockId", utils.F(block.GetBlockid()))
+		return false, ErrInvalidTerm
+	}
+	if err := r.node.checkBlock(block.GetHeight(), term, block.GetBlockid(), proposer); err != nil {
+		ctx.GetLog().Warn("consensus:raft:CheckMinerMatch: check block error", "term", term,
+			"blockId", utils.F(block.GetBlockid()), "err", err)
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *raftConsensus) verifySign(block cctx.BlockInterface) error {
+	bid, err := block.MakeBlockId()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(bid, block.GetBlockid()) {
+		return ErrInvalidSign
+	}
+	k, err := r.cCtx.Crypto.GetEcdsaPublicKeyFromJsonStr(block.GetPublicKey())
+	if err != nil {
+		return err
+	}
+	addr, err := r.cCtx.Crypto.GetAddressFromPublicKey(k)
+	if err != nil {
+		return err
+	}
+	if addr != string(block.GetProposer()) {
+		return ErrInvalidSign
+	}
+	valid, err := r.cCtx.Crypto.VerifyECDSA(k, block.GetSign(), block.GetBlockid())
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrInvalidSign
+	}
+	return nil
+}
+
+// ProcessBeforeMiner 开始挖矿前进行相应的处理, 返回truncate目标(如需裁剪), 返回写consensusStorage, 返回err
+func (r *raftConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, []byte, error) {
+	isLeader, term := r.node.isLeader()
+	if !isLeader {
+		return nil, nil, ErrNotLeader
+	}
+	storage, err := json.Marshal(common.ConsensusStorage{
+		CurTerm:      term,
+		CommitHeight: r.node.status().CommitHeight,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, storage, nil
+}
+
+// CalculateBlock 矿工挖矿时共识需要做的工作, 如PoW时共识需要完成存在性证明
+func (r *raftConsensus) CalculateBlock(block cctx.BlockInterface) error {
+	return nil
+}
+
+// ProcessConfirmBlock 用于确认块后进行相应的处理
+func (r *raftConsensus) ProcessConfirmBlock(block cctx.BlockInterface) error {
+	r.node.onBlock(r.blockTerm(block), string(block.GetProposer()), r.blockCommit(block))
+	return nil
+}
+
+// GetConsensusStatus 获取区块链共识信息
+func (r *raftConsensus) GetConsensusStatus() (consensus.ConsensusStatus, error) {
+	return r.status, nil
+}
+
+// Start 共识实例的启动逻辑
+func (r *raftConsensus) Start() error {
+	for method, f := range r.kMethod {
+		// 若有历史句柄，删除老句柄
+		r.contract.GetKernRegistry().UnregisterKernMethod(raftBucket, method)
+		r.contract.GetKernRegistry().RegisterKernMethod(raftBucket, method, f)
+	}
+	if r.cCtx.Network == nil {
+		return nil
+	}
+	r.subscriber = r.cCtx.Network.NewSubscriber(xuperp2p.XuperMessage_RAFT_MSG, r.p2pMsgChan)
+	if err := r.cCtx.Network.Register(r.subscriber); err != nil {
+		r.log.Error("consensus:raft:Start: register subscriber error", "err", err)
+		return err
+	}
+	go r.run()
+	return nil
+}
+
+// Stop 共识实例的挂起逻辑
+func (r *raftConsensus) Stop() error {
+	for method := range r.kMethod {
+		r.contract.GetKernRegistry().UnregisterKernMethod(raftBucket, method)
+	}
+	if r.subscriber != nil {
+		r.quitCh <- true
+		r.cCtx.Network.UnRegister(r.subscriber)
+		r.subscriber = nil
+	}
+	return nil
+}
+
+// run 处理网络消息并定期驱动状态机
+func (r *raftConsensus) run() {
+	interval := r.config.HeartbeatInterval / 5
+	if interval > 100 {
+		interval = 100
+	}
+	ticker := time.NewTicker(time.Duration(interval) * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg := <-r.p2pMsgChan:
+			r.handleReceivedMsg(msg)
+		case <-ticker.C:
+			r.node.tick()
+		case <-r.quitCh:
+			return
+		}
+	}
+}
+
+func (r *raftConsensus) handleReceivedMsg(msg *xuperp2p.XuperMessage) {
+	if msg.GetHeader().GetBcname() != r.bcName {
+		return
+	}
+	raftMsg := &raftPb.RaftMsg{}
+	if err := p2p.Unmarshal(msg, raftMsg); err != nil {
+		r.log.Warn("consensus:raft:handleReceivedMsg: unmarshal msg error", "err", err)
+		return
+	}
+	// from由发送方自行填写，须校验签名确认消息来自from，验证人身份由node.step校验
+	if err := r.verifyMsg(raftMsg); err != nil {
+		r.log.Warn("consensus:raft:handleReceivedMsg: verify msg error", "from", raftMsg.GetFrom(), "err", err)
+		return
+	}
+	r.node.step(raftMsg)
+}
+
+// msgDigest 返回消息除公钥和签名以外内容的摘要
+func msgDigest(msg *raftPb.RaftMsg) ([]byte, error) {
+	m := proto.Clone(msg).(*raftPb.RaftMsg)
+	m.PublicKey, m.Sign = "", nil
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return hash.DoubleSha256(b), nil
+}
+
+// signMsg 使用本节点的私钥对消息签名
+func (r *raftConsensus) signMsg(msg *raftPb.RaftMsg) error {
+	digest, err := msgDigest(msg)
+	if err != nil {
+		return err
+	}
+	sign, err := r.cCtx.Crypto.SignECDSA(r.cCtx.Address.PrivateKey, digest)
+	if err != nil {
+		return err
+	}
+	msg.PublicKey, msg.Sign = r.cCtx.Address.PublicKeyStr, sign
+	return nil
+}
+
+// verifyMsg 校验消息的公钥与from对应且签名有效
+func (r *raftConsensus) verifyMsg(msg *raftPb.RaftMsg) error {
+	k, err := r.cCtx.Crypto.GetEcdsaPublicKeyFromJsonStr(msg.GetPublicKey())
+	if err != nil {
+		return err
+	}
+	addr, err := r.cCtx.Crypto.GetAddressFromPublicKey(k)
+	if err != nil {
+		return err
+	}
+	if addr != msg.GetFrom() {
+		return ErrInvalidMsgSign
+	}
+	digest, err := msgDigest(msg)
+	if err != nil {
+		return err
+	}
+	valid, err := r.cCtx.Crypto.VerifyECDSA(k, msg.GetSign(), digest)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrInvalidMsgSign
+	}
+	return nil
+}
+
+// send 实现transport接口
+func (r *raftConsensus) send(msg *raftPb.RaftMsg, to []string) {
+	if r.cCtx.Network == nil || len(to) == 0 {
+		return
+	}
+	if err := r.signMsg(msg); err != nil {
+		r.log.Error("consensus:raft:send: sign msg error", "err", err)
+		return
+	}
+	netMsg := p2p.NewMessage(xuperp2p.XuperMessage_RAFT_MSG, msg, p2p.WithBCName(r.bcName))
+	if netMsg == nil {
+		r.log.Error("consensus:raft:send: NewMessage error")
+		return
+	}
+	go r.cCtx.Network.SendMessage(&xcontext.BaseCtx{XLog: r.log, Timer: timer.NewXTimer()}, netMsg, p2p.WithAccounts(to))
+}
+
+// lastEntry 实现raftLog接口
+func (r *raftConsensus) lastEntry() (int64, int64, []byte) {
+	tipBlock := r.cCtx.Ledger.GetTipBlock()
+	return tipBlock.GetHeight(), r.blockTerm(tipBlock), tipBlock.GetBlockid()
+}
+
+// entryAt 实现raftLog接口
+func (r *raftConsensus) entryAt(height int64) (int64, []byte, error) {
+	block, err := r.cCtx.Ledger.QueryBlockHeaderByHeight(height)
+	if err != nil {
+		return 0, nil, err
+	}
+	return r.blockTerm(block), block.GetBlockid(), nil
+}
+
+// lastCommit 实现raftLog接口
+func (r *raftConsensus) lastCommit() int64 {
+	return r.blockCommit(r.cCtx.Ledger.GetTipBlock())
+}
+
+// blockStorage 返回区块的raft共识存储，共识升级之前的区块返回nil
+func (r *raftConsensus) blockStorage(block cctx.BlockInterface) *common.ConsensusStorage {
+	if block.GetHeight() < r.startHeight {
+		return nil
+	}
+	storage, err := block.GetConsensusStorage()
+	if err != nil || len(storage) == 0 {
+		return nil
+	}
+	s, err := common.ParseOldQCStorage(storage)
+	if err != nil {
+		return nil
+	}
+	return s
+}
+
+// blockTerm 返回区块的raft任期，共识升级之前的区块任期为0
+func (r *raftConsensus) blockTerm(block cctx.BlockInterface) int64 {
+	if s := r.blockStorage(block); s != nil {
+		return s.CurTerm
+	}
+	return 0
+}
+
+// blockCommit 返回区块中记录的leader出块时已提交的高度，提交高度不会超过父区块
+func (r *raftConsensus) blockCommit(block cctx.BlockInterface) int64 {
+	s := r.blockStorage(block)
+	if s == nil || s.CommitHeight >= block.GetHeight() {
+		return 0
+	}
+	return s.CommitHeight
+}
+
+// tipValidators 返回tip区块时的验证人集合
+func (r *raftConsensus) tipValidators() []string {
+	tipId := r.cCtx.Ledger.QueryTipBlockHeader().GetBlockid()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.cacheVali != nil && bytes.Equal(tipId, r.cacheTipId) {
+		return r.cacheVali
+	}
+	validators, err := r.getValidatesByBlockId(tipId)
+	if err != nil {
+		r.log.Warn("consensus:raft:tipValidators: get validators error", "err", err)
+		return r.config.InitProposer.Address
+	}
+	r.cacheTipId, r.cacheVali = tipId, validators
+	return validators
+}
+
+// getValidatesByBlockId 读取blockId对应快照中的验证人集合，未修改过时为初始验证人
+func (r *raftConsensus) getValidatesByBlockId(blockId []byte) ([]string, error) {
+	reader, err := r.cCtx.Ledger.CreateSnapshot(blockId)
+	if err != nil {
+		r.log.Error("consensus:raft:getValidatesByBlockId: createSnapshot error", "err", err)
+		return nil, err
+	}
+	res, err := reader.Get(raftBucket, []byte(fmt.Sprintf("%d_%s", r.version, validateKeys)))
+	if err != nil {
+		r.log.Error("consensus:raft:getValidatesByBlockId: reader Get error", "err", err)
+		return nil, err
+	}
+	if res == nil || res.PureData == nil || res.PureData.Value == nil {
+		return r.config.InitProposer.Address, nil
+	}
+	return loadValidators(res.PureData.Value)
+}
+
+// ParseConsensusStorage 共识占用blockinterface的专有存储，特定共识需要提供parse接口，在此作为接口高亮
+func (r *raftConsensus) ParseConsensusStorage(block cctx.BlockInterface) (interface{}, error) {
+	b, err := block.GetConsensusStorage()
+	if err != nil {
+		return nil, err
+	}
+	return common.ParseOldQCStorage(b)
+}
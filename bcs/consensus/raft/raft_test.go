@@ -0,0 +1,184 @@
+package raft
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	bmock "github.com/xuperchain/xupercore/bcs/consensus/mock"
+	raftPb "github.com/xuperchain/xupercore/bcs/consensus/raft/pb"
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/consensus/def"
+	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
+)
+
+func getRaftConsensusConf(validators ...string) string {
+	conf := map[string]interface{}{
+		"version":            "1",
+		"min_interval":       10,
+		"heartbeat_interval": 10,
+		"election_timeout":   20,
+		"init_proposer": map[string][]string{
+			"address": validators,
+		},
+	}
+	b, _ := json.Marshal(conf)
+	return string(b)
+}
+
+func prepare(config string) (*cctx.ConsensusCtx, error) {
+	l := kmock.NewFakeLedger([]byte(config))
+	cCtx, err := bmock.NewConsensusCtx(l)
+	if err != nil {
+		return nil, err
+	}
+	cCtx.Ledger = l
+	p, ctxN, err := kmock.NewP2P("node")
+	if err != nil {
+		return nil, err
+	}
+	p.Init(ctxN)
+	cCtx.Network = p
+	return cCtx, nil
+}
+
+func getConfig(config string) def.ConsensusConfig {
+	return def.ConsensusConfig{
+		ConsensusName: "raft",
+		Config:        config,
+		StartHeight:   1,
+		Index:         0,
+	}
+}
+
+func newTestRaft(t *testing.T, config string) *raftConsensus {
+	cCtx, err := prepare(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := NewRaftConsensus(*cCtx, getConfig(config))
+	if i == nil {
+		t.Fatal("NewRaftConsensus error")
+	}
+	return i.(*raftConsensus)
+}
+
+func TestBuildConfig(t *testing.T) {
+	conf, err := buildConfig([]byte(`{"init_proposer": {"address": ["a"]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.MinInterval != defaultMinInterval || conf.ElectionTimeout != defaultElectionTimeout {
+		t.Fatalf("default config not applied: %+v", conf)
+	}
+	if _, err := buildConfig([]byte(`{"init_proposer": {"address": []}}`)); err != ErrEmptyValidators {
+		t.Fatalf("empty validators should be rejected, err:%v", err)
+	}
+	if _, err := buildConfig([]byte(`{"heartbeat_interval": 500, "election_timeout": 600,
+		"init_proposer": {"address": ["a"]}}`)); err != ErrInvalidConfig {
+		t.Fatalf("election timeout should be longer than two heartbeats, err:%v", err)
+	}
+}
+
+func TestRaftMining(t *testing.T) {
+	r := newTestRaft(t, getRaftConsensusConf(bmock.Miner))
+	if _, _, err := r.ProcessBeforeMiner(3, time.Now().UnixNano()); err != ErrNotLeader {
+		t.Fatalf("follower should not mine, err:%v", err)
+	}
+	// 单个验证人选举超时后直接成为leader
+	time.Sleep(50 * time.Millisecond)
+	r.node.tick()
+	isMiner, needSync, err := r.CompeteMaster(3)
+	if !isMiner || needSync || err != nil {
+		t.Fatalf("single validator should be leader, isMiner:%v, err:%v", isMiner, err)
+	}
+	// 当选后需要出空块提交之前的区块
+	if !r.AllowEmptyBlock(3) {
+		t.Fatal("new leader should produce a noop block")
+	}
+	_, storage, err := r.ProcessBeforeMiner(3, time.Now().UnixNano())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _ := common.ParseOldQCStorage(storage)
+	if s.CurTerm != 1 {
+		t.Fatalf("unexpected term: %d", s.CurTerm)
+	}
+
+	cc, a, _ := bmock.NewCryptoClient()
+	block, err := bmock.NewBlockWithStorage(3, cc, a, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, _ := r.cCtx.Ledger.(*kmock.FakeLedger)
+	if ok, err := r.CheckMinerMatch(&r.cCtx.BaseCtx, block); !ok || err != nil {
+		t.Fatalf("check block error: %v", err)
+	}
+	l.Put(block)
+	r.ProcessConfirmBlock(block)
+	if r.AllowEmptyBlock(4) {
+		t.Fatal("leader should not produce empty blocks")
+	}
+	if s := r.node.status(); s.CommitHeight != 3 {
+		t.Fatalf("block should be committed by single validator, status:%+v", s)
+	}
+	info := ValidatorsInfo{}
+	if err := json.Unmarshal(r.status.GetCurrentValidatorsInfo(), &info); err != nil || info.Miner != bmock.Miner || info.Term != 1 {
+		t.Fatalf("unexpected validators info: %+v, err:%v", info, err)
+	}
+
+	// 任期低于父区块的区块不合法
+	old, _ := bmock.NewBlockWithStorage(4, cc, a, []byte(`{"curTerm":0}`))
+	old.PreHash = block.Blockid
+	if ok, _ := r.CheckMinerMatch(&r.cCtx.BaseCtx, old); ok {
+		t.Fatal("block with lower term should be rejected")
+	}
+	// 篡改签名
+	block.Sign = []byte("fake")
+	if ok, _ := r.CheckMinerMatch(&r.cCtx.BaseCtx, block); ok {
+		t.Fatal("block with invalid sign should be rejected")
+	}
+}
+
+func TestCheckProposer(t *testing.T) {
+	r := newTestRaft(t, getRaftConsensusConf("WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT"))
+	cc, a, _ := bmock.NewCryptoClient()
+	block, _ := bmock.NewBlockWithStorage(3, cc, a, []byte(`{"curTerm":1}`))
+	if ok, err := r.CheckMinerMatch(&r.cCtx.BaseCtx, block); ok || err != ErrInvalidProposer {
+		t.Fatalf("block from non-validator should be rejected, err:%v", err)
+	}
+	if isMiner, _, _ := r.CompeteMaster(3); isMiner {
+		t.Fatal("non-validator should not be leader")
+	}
+}
+
+func TestMsgSign(t *testing.T) {
+	r := newTestRaft(t, getRaftConsensusConf(bmock.Miner))
+	msg := &raftPb.RaftMsg{
+		Type: raftPb.RaftMsgType_HEARTBEAT,
+		Term: 1,
+		From: bmock.Miner,
+	}
+	if err := r.signMsg(msg); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.verifyMsg(msg); err != nil {
+		t.Fatalf("verify signed msg error: %v", err)
+	}
+	// 冒充其他节点或篡改消息内容
+	forged := proto.Clone(msg).(*raftPb.RaftMsg)
+	forged.From = "WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT"
+	if err := r.verifyMsg(forged); err != ErrInvalidMsgSign {
+		t.Fatalf("msg with forged from should be rejected, err:%v", err)
+	}
+	forged = proto.Clone(msg).(*raftPb.RaftMsg)
+	forged.Term = 2
+	if err := r.verifyMsg(forged); err == nil {
+		t.Fatal("tampered msg should be rejected")
+	}
+	if err := r.verifyMsg(&raftPb.RaftMsg{From: bmock.Miner}); err == nil {
+		t.Fatal("unsigned msg should be rejected")
+	}
+}
@@ -0,0 +1,71 @@
+package raft
+
+import (
+	"encoding/json"
+
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+type ValidatorsInfo struct {
+	Validators []string `json:"validators"`
+	// 当前任期的leader，尚未选出时为空
+	Miner         string `json:"miner"`
+	Term          int64  `json:"term"`
+	Role          string `json:"role"`
+	CommitHeight  int64  `json:"commit_height"`
+	CommitBlockid string `json:"commit_blockid"`
+}
+
+// RaftStatus 实现了ConsensusStatus接口
+type RaftStatus struct {
+	Version     int64 `json:"version"`
+	StartHeight int64 `json:"startHeight"`
+	Index       int   `json:"index"`
+	raft        *raftConsensus
+}
+
+// 获取共识版本号
+func (r *RaftStatus) GetVersion() int64 {
+	return r.Version
+}
+
+// 共识起始高度
+func (r *RaftStatus) GetConsensusBeginInfo() int64 {
+	return r.StartHeight
+}
+
+// 获取共识item所在consensus slice中的index
+func (r *RaftStatus) GetStepConsensusIndex() int {
+	return r.Index
+}
+
+// 获取共识类型
+func (r *RaftStatus) GetConsensusName() string {
+	return "raft"
+}
+
+// 获取当前raft任期
+func (r *RaftStatus) GetCurrentTerm() int64 {
+	return r.raft.node.status().Term
+}
+
+// 获取当前验证人、leader及已提交的区块
+func (r *RaftStatus) GetCurrentValidatorsInfo() []byte {
+	s := r.raft.node.status()
+	i := ValidatorsInfo{
+		Validators:    r.raft.tipValidators(),
+		Miner:         s.Leader,
+		Term:          s.Term,
+		Role:          s.Role,
+		CommitHeight:  s.CommitHeight,
+		CommitBlockid: utils.F(s.CommitId),
+	}
+	b, _ := json.Marshal(i)
+	return b
+}
+
+// GetFinalizedBlock raft中已被多数节点提交的区块不可逆
+func (r *RaftStatus) GetFinalizedBlock() (int64, []byte) {
+	s := r.raft.node.status()
+	return s.CommitHeight, s.CommitId
+}
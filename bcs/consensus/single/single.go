@@ -58,6 +58,7 @@ func NewSingleConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consen
 
 	// newHeight取上一共识的最高值，因为此时BeginHeight也许并为生产出来
 	status := &SingleStatus{
+		ledger:      cCtx.Ledger,
 		startHeight: cCfg.StartHeight,
 		newHeight:   cCfg.StartHeight - 1,
 		index:       cCfg.Index,
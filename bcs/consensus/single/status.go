@@ -3,6 +3,9 @@ package single
 import (
 	"encoding/json"
 	"sync"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 )
 
 type ValidatorsInfo struct {
@@ -10,6 +13,7 @@ type ValidatorsInfo struct {
 }
 
 type SingleStatus struct {
+	ledger      cctx.LedgerRely
 	startHeight int64
 	mutex       sync.RWMutex
 	newHeight   int64
@@ -52,3 +56,8 @@ func (s *SingleStatus) GetCurrentValidatorsInfo() []byte {
 	m, _ := json.Marshal(miner)
 	return m
 }
+
+// GetFinalizedBlock single共识只有一个矿工，不会分叉，最新区块即不可逆
+func (s *SingleStatus) GetFinalizedBlock() (int64, []byte) {
+	return common.FinalizedByDepth(s.ledger, 0)
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/liveness"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 )
 
@@ -21,12 +22,15 @@ const (
 	contractVote              = "voteCandidate"
 	contractRevokeVote        = "revokeVote"
 	contractGetTdposInfos     = "getTdposInfos"
+	contractSubmitEvidence    = "submitEvidence"
 
 	tdposBucket   = "$tdpos"
 	xposBucket    = "$xpos"
 	nominateKey   = "nominate"
 	voteKeyPrefix = "vote_"
 	revokeKey     = "revoke"
+	slashKey      = "slash"
+	commissionKey = "commission"
 
 	NOMINATETYPE = "nominate"
 	VOTETYPE     = "vote"
@@ -47,6 +51,13 @@ var (
 	ErrValueNotFound    = errors.New("value not found, please check your input parameters")
 	ErrSchedule         = errors.New("minerScheduling overflow")
 	ErrNotFound         = errors.New("Key not found")
+	ErrEvidenceHeight   = errors.New("evidence height should be higher than consensus start height")
+	ErrNotCandidate     = errors.New("offender is neither a candidate nor an initial proposer")
+	ErrRepeatSlash      = errors.New("candidate had been slashed")
+	ErrCandidateSlashed = errors.New("candidate had been slashed, its nomination and votes are frozen")
+	ErrCommission       = errors.New("commission should be an integer percentage between 0 and 100")
+	ErrInvalidAward     = errors.New("award tx does not match reward sharing rule")
+	ErrVRFConfig        = errors.New("enable_vrf cannot be used with liveness skip policy")
 )
 
 // tdpos 共识机制的配置
@@ -69,6 +80,12 @@ type tdposConfig struct {
 	// 系统指定的前两轮的候选人名单
 	InitProposer map[string][]string `json:"init_proposer"`
 	EnableBFT    map[string]bool     `json:"bft_config,omitempty"`
+	// 候选人活跃度统计及跳过策略
+	Liveness *liveness.Config `json:"liveness,omitempty"`
+	// 区块奖励分成，为空时奖励全部归出块人
+	RewardSharing *rewardConfig `json:"reward_sharing,omitempty"`
+	// 开启后每个出块时间片的出块人由VRF随机数从本轮候选人中抽取，不能与跳过策略同时开启
+	EnableVRF bool `json:"enable_vrf,omitempty"`
 }
 
 func (tp *tdposConsensus) needSync() bool {
@@ -144,8 +161,11 @@ func buildConfigs(input []byte) (*tdposConfig, error) {
 	tdposCfg.VoteUnitPrice = voteUnitPrice
 
 	type tempStruct struct {
-		InitProposer map[string][]string `json:"init_proposer"`
-		EnableBFT    map[string]bool     `json:"bft_config,omitempty"`
+		InitProposer  map[string][]string `json:"init_proposer"`
+		EnableBFT     map[string]bool     `json:"bft_config,omitempty"`
+		Liveness      *liveness.Config    `json:"liveness,omitempty"`
+		RewardSharing *rewardConfig       `json:"reward_sharing,omitempty"`
+		EnableVRF     bool                `json:"enable_vrf,omitempty"`
 	}
 	var temp tempStruct
 	err = json.Unmarshal(input, &temp)
@@ -165,6 +185,17 @@ func buildConfigs(input []byte) (*tdposConfig, error) {
 
 	tdposCfg.InitProposer = temp.InitProposer
 	tdposCfg.EnableBFT = temp.EnableBFT
+	tdposCfg.Liveness = temp.Liveness
+	if temp.RewardSharing != nil {
+		if c := temp.RewardSharing.DefaultCommission; c < 0 || c > 100 {
+			return nil, fmt.Errorf("reward_sharing.default_commission set error")
+		}
+	}
+	tdposCfg.RewardSharing = temp.RewardSharing
+	if temp.EnableVRF && temp.Liveness != nil && temp.Liveness.MissThreshold > 0 {
+		return nil, ErrVRFConfig
+	}
+	tdposCfg.EnableVRF = temp.EnableVRF
 
 	return tdposCfg, nil
 }
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
 	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
 
 	"github.com/xuperchain/xupercore/kernel/contract"
@@ -21,6 +22,10 @@ import (
 //                value = <${from_addr}, ${ballot_count}>
 // 3. 撤销动作相关  key = "revoke_${candi_addr}"
 //                value = <${from_addr}, <(${TYPE_VOTE/TYPE_NOMINATE}, ${ballot_count})>>
+// 4. 双签惩罚相关  key = "slash"
+//                value = <${candi_addr}, ${slash_item}>
+// 5. 奖励佣金相关  key = "commission"
+//                value = <${candi_addr}, ${commission_percent}>
 // 以上所有的数据读通过快照读取, 快照读取的是当前区块的前三个区块的值
 // 以上所有数据都更新到各自的链上存储中，直接走三代合约写入，去除原Finalize的最后写入更新机制
 // 由于三代合约读写集限制，不能针对同一个ExeInput触发并行操作，后到的tx将会出现读写集错误，即针对同一个大key的操作同一个区块只能顺序执行
@@ -39,6 +44,15 @@ func (tp *tdposConsensus) runNominateCandidate(contractCtx contract.KContext) (*
 	if amount <= 0 || err != nil {
 		return common.NewContractErrResponse(common.StatusErr, ErrAmount.Error()), ErrAmount
 	}
+	// 可选参数，候选人声明的出块奖励佣金比例，未声明时使用配置中的默认值
+	commission, hasCommission, err := parseCommission(contractCtx.Args())
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	// 被惩罚过的候选人不能再次提名
+	if err := tp.checkNotSlashed(contractCtx, candidateName); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
 	// 1.2 是否按照要求多签
 	if ok := tp.isAuthAddress(candidateName, contractCtx.Initiator(), contractCtx.AuthRequire()); !ok {
 		return common.NewContractErrResponse(common.StatusErr, ErrAuth.Error()), ErrAuth
@@ -82,6 +96,17 @@ func (tp *tdposConsensus) runNominateCandidate(contractCtx contract.KContext) (*
 	if err := contractCtx.Put(tp.election.bindContractBucket, []byte(nKey), returnBytes); err != nil {
 		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
 	}
+	// 4. 记录佣金比例
+	if hasCommission {
+		commissionValue, err := tp.getCommissionValue(contractCtx)
+		if err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+		commissionValue[candidateName] = commission
+		if err := tp.putCommissionValue(contractCtx, commissionValue); err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+	}
 	delta := contract.Limits{
 		XFee: fee,
 	}
@@ -171,6 +196,17 @@ func (tp *tdposConsensus) runRevokeCandidate(contractCtx contract.KContext) (*co
 	if err := contractCtx.Put(tp.election.bindContractBucket, []byte(nKey), nominateBytes); err != nil {
 		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
 	}
+	// 5. 删除佣金记录
+	commissionValue, err := tp.getCommissionValue(contractCtx)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if _, ok := commissionValue[candidateName]; ok {
+		delete(commissionValue, candidateName)
+		if err := tp.putCommissionValue(contractCtx, commissionValue); err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+	}
 	delta := contract.Limits{
 		XFee: fee,
 	}
@@ -270,6 +306,10 @@ func (tp *tdposConsensus) runRevokeVote(contractCtx contract.KContext) (*contrac
 	if amount <= 0 || err != nil {
 		return common.NewContractErrResponse(common.StatusErr, ErrAmount.Error()), ErrAmount
 	}
+	// 投给被惩罚候选人的选票被冻结，不能撤销
+	if err := tp.checkNotSlashed(contractCtx, candidateName); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
 	// 1.2 调用解冻接口，Args: FromAddr, amount
 	tokenArgs := map[string][]byte{
 		"from":      []byte(contractCtx.Initiator()),
@@ -355,7 +395,10 @@ func (tp *tdposConsensus) runGetTdposInfos(contractCtx contract.KContext) (*cont
 	nKey := fmt.Sprintf("%s_%d_%s", tp.status.Name, tp.status.Version, nominateKey)
 	res, err := contractCtx.Get(tp.election.bindContractBucket, []byte(nKey))
 	if res == nil {
-		return common.NewContractOKResponse([]byte("{}")), nil
+		return_bytes, _ := json.Marshal(map[string]interface{}{
+			"liveness": tp.election.getLiveness(),
+		})
+		return common.NewContractOKResponse(return_bytes), nil
 	}
 	if err != nil {
 		return common.NewContractErrResponse(common.StatusErr, "Internal error."), err
@@ -402,15 +445,175 @@ func (tp *tdposConsensus) runGetTdposInfos(contractCtx contract.KContext) (*cont
 		}
 	}
 
+	// slash信息
+	slashValue, err := tp.getSlashValue(contractCtx)
+	if err != nil {
+		tp.election.log.Error("tdpos: getTdposInfos: load slash read set err.", "err", err)
+		return common.NewContractErrResponse(common.StatusErr, "Internal error."), err
+	}
+
+	// commission信息
+	commissionValue, err := tp.getCommissionValue(contractCtx)
+	if err != nil {
+		tp.election.log.Error("tdpos: getTdposInfos: load commission read set err.", "err", err)
+		return common.NewContractErrResponse(common.StatusErr, "Internal error."), err
+	}
+
 	return_map := map[string]interface{}{
-		"nominate": nominateValue,
-		"vote":     voteMap,
-		"revoke":   revokeValue,
+		"nominate":   nominateValue,
+		"vote":       voteMap,
+		"revoke":     revokeValue,
+		"slash":      slashValue,
+		"commission": commissionValue,
+		"liveness":   tp.election.getLiveness(),
 	}
 	return_bytes, _ := json.Marshal(return_map)
 	return common.NewContractOKResponse(return_bytes), nil
 }
 
+// runSubmitEvidence 提交候选人双签证据，验证通过后取消其提名资格，提名和投给它的选票均被冻结
+// Args:
+//
+//	evidence::json格式的双签证据
+func (tp *tdposConsensus) runSubmitEvidence(contractCtx contract.KContext) (*contract.Response, error) {
+	// 1. 校验证据本身
+	ev, err := evidence.Unmarshal(contractCtx.Args()["evidence"])
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if err := evidence.Verify(ev, tp.cCtx.Crypto, tp.config.Period); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	// 共识升级之前的双签由之前的共识实例处理
+	if ev.Height <= tp.status.StartHeight {
+		return common.NewContractErrResponse(common.StatusErr, ErrEvidenceHeight.Error()), ErrEvidenceHeight
+	}
+
+	// 2. 每个候选人只惩罚一次
+	slashValue, err := tp.getSlashValue(contractCtx)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if _, ok := slashValue[ev.Offender]; ok {
+		return common.NewContractErrResponse(common.StatusErr, ErrRepeatSlash.Error()), ErrRepeatSlash
+	}
+
+	// 3. 删除提名记录，提名时冻结的治理代币不再解冻，候选人不再参与选举
+	nKey := fmt.Sprintf("%s_%d_%s", tp.status.Name, tp.status.Version, nominateKey)
+	res, err := contractCtx.Get(tp.election.bindContractBucket, []byte(nKey))
+	if err != nil && err.Error() != ErrNotFound.Error() {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	nominateValue := NewNominateValue()
+	if res != nil {
+		if err := json.Unmarshal(res, &nominateValue); err != nil {
+			tp.log.Error("tdpos::runSubmitEvidence::load nominate read set err.")
+			return common.NewContractErrResponse(common.StatusErr, "Internal error."), err
+		}
+	}
+	_, nominated := nominateValue[ev.Offender]
+	if !nominated && !isInitProposer(ev.Offender, tp.election.initValidators) {
+		return common.NewContractErrResponse(common.StatusErr, ErrNotCandidate.Error()), ErrNotCandidate
+	}
+
+	// 4. 记录惩罚，投给该候选人的选票从此不能撤销
+	slashValue[ev.Offender] = slashItem{
+		EvidenceType: ev.Type,
+		Height:       ev.Height,
+		EvidenceId:   ev.ID(),
+	}
+	slashBytes, err := json.Marshal(slashValue)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if err := contractCtx.Put(tp.election.bindContractBucket, []byte(tp.slashKey()), slashBytes); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if nominated {
+		delete(nominateValue, ev.Offender)
+		nominateBytes, err := json.Marshal(nominateValue)
+		if err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+		if err := contractCtx.Put(tp.election.bindContractBucket, []byte(nKey), nominateBytes); err != nil {
+			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+		}
+	}
+	tp.log.Warn("tdpos::runSubmitEvidence::candidate slashed", "offender", ev.Offender, "type", ev.Type,
+		"height", ev.Height, "nominated", nominated)
+	delta := contract.Limits{
+		XFee: fee,
+	}
+	contractCtx.AddResourceUsed(delta)
+	return common.NewContractOKResponse([]byte("ok")), nil
+}
+
+func (tp *tdposConsensus) slashKey() string {
+	return fmt.Sprintf("%s_%d_%s", tp.status.Name, tp.status.Version, slashKey)
+}
+
+func (tp *tdposConsensus) getSlashValue(contractCtx contract.KContext) (slashValue, error) {
+	res, err := contractCtx.Get(tp.election.bindContractBucket, []byte(tp.slashKey()))
+	if err != nil && err.Error() != ErrNotFound.Error() {
+		return nil, err
+	}
+	value := NewSlashValue()
+	if res != nil {
+		if err := json.Unmarshal(res, &value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+func (tp *tdposConsensus) getCommissionValue(contractCtx contract.KContext) (commissionValue, error) {
+	key := fmt.Sprintf("%s_%d_%s", tp.status.Name, tp.status.Version, commissionKey)
+	res, err := contractCtx.Get(tp.election.bindContractBucket, []byte(key))
+	if err != nil && err.Error() != ErrNotFound.Error() {
+		return nil, err
+	}
+	value := NewCommissionValue()
+	if res != nil {
+		if err := json.Unmarshal(res, &value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+func (tp *tdposConsensus) putCommissionValue(contractCtx contract.KContext, value commissionValue) error {
+	key := fmt.Sprintf("%s_%d_%s", tp.status.Name, tp.status.Version, commissionKey)
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return contractCtx.Put(tp.election.bindContractBucket, []byte(key), buf)
+}
+
+// parseCommission 解析提名参数中的佣金比例，取值为0到100的整数
+func parseCommission(txArgs map[string][]byte) (int64, bool, error) {
+	commissionBytes, ok := txArgs["commission"]
+	if !ok || len(commissionBytes) == 0 {
+		return 0, false, nil
+	}
+	commission, err := strconv.ParseInt(string(commissionBytes), 10, 64)
+	if err != nil || commission < 0 || commission > 100 {
+		return 0, false, ErrCommission
+	}
+	return commission, true, nil
+}
+
+func (tp *tdposConsensus) checkNotSlashed(contractCtx contract.KContext, candidate string) error {
+	slashValue, err := tp.getSlashValue(contractCtx)
+	if err != nil {
+		return err
+	}
+	if _, ok := slashValue[candidate]; ok {
+		return ErrCandidateSlashed
+	}
+	return nil
+}
+
 func (tp *tdposConsensus) checkArgs(txArgs map[string][]byte) (string, error) {
 	candidateBytes := txArgs["candidate"]
 	candidateName := string(candidateBytes)
@@ -444,6 +647,34 @@ func NewRevokeValue() revokeValue {
 	return make(map[string][]revokeItem)
 }
 
+type slashValue map[string]slashItem
+
+type slashItem struct {
+	EvidenceType string
+	Height       int64
+	EvidenceId   string
+}
+
+func NewSlashValue() slashValue {
+	return make(map[string]slashItem)
+}
+
+// commissionValue 候选人声明的佣金比例，单位为百分比
+type commissionValue map[string]int64
+
+func NewCommissionValue() commissionValue {
+	return make(map[string]int64)
+}
+
+func isInitProposer(addr string, initProposers []string) bool {
+	for _, v := range initProposers {
+		if v == addr {
+			return true
+		}
+	}
+	return false
+}
+
 func (tp *tdposConsensus) isAuthAddress(candidate string, initiator string, authRequire []string) bool {
 	if strings.HasSuffix(initiator, candidate) {
 		return true
@@ -2,10 +2,17 @@ package tdpos
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
+	bmock "github.com/xuperchain/xupercore/bcs/consensus/mock"
+	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
+	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
 	"github.com/xuperchain/xupercore/kernel/consensus/mock"
 	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
+	"github.com/xuperchain/xupercore/lib/utils"
 )
 
 var nominate_key = "tdpos_0_nominate"
@@ -202,3 +209,73 @@ func TestRunRevokeVote(t *testing.T) {
 	fakeCtx := mock.NewFakeKContext(NewNominateArgs(), NewM())
 	tdpos.runRevokeVote(fakeCtx)
 }
+
+func NewEvidenceArgs(t *testing.T) map[string][]byte {
+	cc, addr, err := bmock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cCrypto.NewCBFTCrypto(addr, cc)
+	ts := time.Now().UnixNano() / int64(3*time.Second) * int64(3*time.Second)
+	ev := &evidence.Evidence{
+		Type:     evidence.TypeProposal,
+		Offender: addr.Address,
+		Height:   10,
+	}
+	for i, id := range []string{"p1", "p2"} {
+		msg, err := c.SignProposalMsg(&chainedBftPb.ProposalMsg{
+			ProposalView: 10,
+			ProposalId:   []byte(id),
+			Timestamp:    ts + int64(i),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ev.Proposals = append(ev.Proposals, msg)
+	}
+	buf, _ := ev.Marshal()
+	return map[string][]byte{"evidence": buf, "candidate": []byte(addr.Address), "amount": []byte("1")}
+}
+
+func TestRunSubmitEvidence(t *testing.T) {
+	cCtx, err := prepare(getTdposConsensusConf())
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := NewTdposConsensus(*cCtx, getConfig(getTdposConsensusConf()))
+	tdpos, _ := i.(*tdposConsensus)
+	args := NewEvidenceArgs(t)
+
+	// 未提名的候选人不能被惩罚
+	if _, err := tdpos.runSubmitEvidence(mock.NewFakeKContext(args, NewM())); err != ErrNotCandidate {
+		t.Fatalf("offender is not candidate, err:%v", err)
+	}
+
+	nKey := fmt.Sprintf("%s_%d_%s", tdpos.status.Name, tdpos.status.Version, nominateKey)
+	nominate, _ := json.Marshal(map[string]map[string]int64{
+		bmock.Miner:                         {bmock.Miner: 10},
+		"SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co": {"SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co": 10},
+	})
+	m := NewM()
+	m[tdpos.election.bindContractBucket] = map[string][]byte{
+		utils.F([]byte(nKey)): nominate,
+	}
+	if _, err := tdpos.runSubmitEvidence(mock.NewFakeKContext(args, m)); err != nil {
+		t.Fatal(err)
+	}
+	nominateValue := NewNominateValue()
+	json.Unmarshal(m[tdpos.election.bindContractBucket][utils.F([]byte(nKey))], &nominateValue)
+	if _, ok := nominateValue[bmock.Miner]; ok || len(nominateValue) != 1 {
+		t.Fatalf("nomination should be forfeited:%v", nominateValue)
+	}
+
+	if _, err := tdpos.runSubmitEvidence(mock.NewFakeKContext(args, m)); err != ErrRepeatSlash {
+		t.Fatalf("repeat slash should be rejected, err:%v", err)
+	}
+	if _, err := tdpos.runRevokeVote(mock.NewFakeKContext(args, m)); err != ErrCandidateSlashed {
+		t.Fatalf("votes of slashed candidate should be frozen, err:%v", err)
+	}
+	if _, err := tdpos.runNominateCandidate(mock.NewFakeKContext(args, m)); err != ErrCandidateSlashed {
+		t.Fatalf("slashed candidate should not be nominated again, err:%v", err)
+	}
+}
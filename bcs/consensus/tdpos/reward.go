@@ -0,0 +1,205 @@
+package tdpos
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// 本文件实现区块奖励分成
+// 候选人提名时可以声明佣金比例，出块奖励中佣金部分归出块人，其余部分按票数分配给投票该出块人的投票人。
+// 分成结果直接作为奖励交易的多个输出，随区块一起生成和回滚，不需要额外的链上状态。
+// 投票人的有效票数取父区块所在term选举快照中的票数与父区块时票数的较小值，
+// 即term内新增的投票从下一个term开始参与分成，term内撤销的投票立即停止分成。
+
+var (
+	// defaultMaxRewardVoters 每个区块默认最多分配的投票人数
+	defaultMaxRewardVoters int64 = 100
+)
+
+// rewardConfig 区块奖励分成配置，对应共识配置中的reward_sharing字段
+type rewardConfig struct {
+	// 未声明佣金比例的候选人使用的佣金比例，单位为百分比
+	DefaultCommission int64 `json:"default_commission"`
+	// 每个区块最多分配的投票人数，按票数从多到少选取，为0时使用defaultMaxRewardVoters
+	MaxVoters int64 `json:"max_voters,omitempty"`
+}
+
+// awardBlock 能够取得奖励交易的区块
+type awardBlock interface {
+	GetAwardTx() *lpb.Transaction
+}
+
+// SplitAward 实现consensus.AwardSplitter接口，未开启奖励分成时奖励全部归出块人
+func (tp *tdposConsensus) SplitAward(preHash []byte, height int64, proposer string, award *big.Int) ([]*protos.TxOutput, error) {
+	conf := tp.config.RewardSharing
+	if conf == nil || award.Sign() <= 0 || height <= tp.status.StartHeight {
+		return []*protos.TxOutput{{ToAddr: []byte(proposer), Amount: award.Bytes()}}, nil
+	}
+	parent, err := tp.election.ledger.QueryBlockHeader(preHash)
+	if err != nil {
+		tp.log.Error("consensus:tdpos:SplitAward: query parent block error", "err", err)
+		return nil, err
+	}
+	commission, err := tp.election.getCommission(parent, proposer, conf.DefaultCommission)
+	if err != nil {
+		return nil, err
+	}
+	ballots, err := tp.election.getRewardBallots(parent, proposer)
+	if err != nil {
+		return nil, err
+	}
+	maxVoters := conf.MaxVoters
+	if maxVoters <= 0 {
+		maxVoters = defaultMaxRewardVoters
+	}
+	return splitAward(award, proposer, commission, ballots, maxVoters), nil
+}
+
+// verifyAward 校验区块奖励交易的分配方式，奖励总额由账本校验
+func (tp *tdposConsensus) verifyAward(block cctx.BlockInterface) error {
+	if tp.config.RewardSharing == nil {
+		return nil
+	}
+	ab, ok := block.(awardBlock)
+	if !ok {
+		return nil
+	}
+	awardTx := ab.GetAwardTx()
+	if awardTx == nil {
+		return nil
+	}
+	total := big.NewInt(0)
+	for _, output := range awardTx.TxOutputs {
+		total.Add(total, new(big.Int).SetBytes(output.Amount))
+	}
+	want, err := tp.SplitAward(block.GetPreHash(), block.GetHeight(), string(block.GetProposer()), total)
+	if err != nil {
+		return err
+	}
+	if len(want) != len(awardTx.TxOutputs) {
+		return ErrInvalidAward
+	}
+	for i, output := range awardTx.TxOutputs {
+		if string(output.ToAddr) != string(want[i].ToAddr) ||
+			new(big.Int).SetBytes(output.Amount).Cmp(new(big.Int).SetBytes(want[i].Amount)) != 0 {
+			return ErrInvalidAward
+		}
+	}
+	return nil
+}
+
+// splitAward 按照佣金比例和投票人票数拆分奖励，proposer始终为第一个输出，整除的余数归proposer
+func splitAward(award *big.Int, proposer string, commission int64, ballots map[string]int64, maxVoters int64) []*protos.TxOutput {
+	var voters termBallotsSlice
+	for addr, ballot := range ballots {
+		if ballot > 0 {
+			voters = append(voters, &termBallots{Address: addr, Ballots: ballot})
+		}
+	}
+	sort.Stable(voters)
+	if int64(len(voters)) > maxVoters {
+		voters = voters[:maxVoters]
+	}
+	total := big.NewInt(0)
+	for _, v := range voters {
+		total.Add(total, big.NewInt(v.Ballots))
+	}
+
+	remain := new(big.Int).Set(award)
+	var shares []*protos.TxOutput
+	if total.Sign() > 0 {
+		voterPart := new(big.Int).Mul(award, big.NewInt(100-commission))
+		voterPart.Div(voterPart, big.NewInt(100))
+		for _, v := range voters {
+			// proposer作为投票人的分成与佣金合并在第一个输出中
+			if v.Address == proposer {
+				continue
+			}
+			share := new(big.Int).Mul(voterPart, big.NewInt(v.Ballots))
+			share.Div(share, total)
+			if share.Sign() <= 0 {
+				continue
+			}
+			remain.Sub(remain, share)
+			shares = append(shares, &protos.TxOutput{ToAddr: []byte(v.Address), Amount: share.Bytes()})
+		}
+	}
+	return append([]*protos.TxOutput{{ToAddr: []byte(proposer), Amount: remain.Bytes()}}, shares...)
+}
+
+// getCommission 读取candidate在parent区块时的佣金比例，未声明时返回defaultCommission
+func (s *tdposSchedule) getCommission(parent ledger.BlockHandle, candidate string, defaultCommission int64) (int64, error) {
+	key := fmt.Sprintf("%s_%d_%s", s.consensusName, s.consensusVersion, commissionKey)
+	res, err := s.getBlockSnapshotKey(parent.GetBlockid(), s.bindContractBucket, []byte(key))
+	if err != nil {
+		s.log.Error("tdpos::getCommission::load commission read set err.", "err", err)
+		return 0, err
+	}
+	if res == nil {
+		return defaultCommission, nil
+	}
+	value := NewCommissionValue()
+	if err := json.Unmarshal(res, &value); err != nil {
+		return 0, err
+	}
+	if c, ok := value[candidate]; ok {
+		return c, nil
+	}
+	return defaultCommission, nil
+}
+
+// getRewardBallots 返回参与candidate出块奖励分成的投票人及其有效票数
+func (s *tdposSchedule) getRewardBallots(parent ledger.BlockHandle, candidate string) (map[string]int64, error) {
+	key := []byte(fmt.Sprintf("%s_%d_%s%s", s.consensusName, s.consensusVersion, voteKeyPrefix, candidate))
+	current, err := s.loadVoteValue(s.getBlockSnapshotKey(parent.GetBlockid(), s.bindContractBucket, key))
+	if err != nil {
+		s.log.Error("tdpos::getRewardBallots::load vote read set err.", "err", err)
+		return nil, err
+	}
+	if len(current) == 0 || parent.GetHeight() < s.startHeight+3 {
+		return current, nil
+	}
+	// 与选举时一致，读取term选举高度前三个区块的快照，读取失败时不能退回当前票数，
+	// 否则各节点计算出的奖励分配可能不一致
+	height, err := s.termElectionHeight(parent)
+	if err != nil {
+		s.log.Error("tdpos::getRewardBallots::termElectionHeight err.", "err", err)
+		return nil, err
+	}
+	term, err := s.loadVoteValue(s.getSnapshotKey(height-3, s.bindContractBucket, key))
+	if err != nil {
+		s.log.Error("tdpos::getRewardBallots::load term vote err.", "err", err)
+		return nil, err
+	}
+	ballots := make(map[string]int64, len(current))
+	for voter, ballot := range current {
+		if termBallot, ok := term[voter]; ok {
+			if termBallot < ballot {
+				ballot = termBallot
+			}
+			ballots[voter] = ballot
+		}
+	}
+	return ballots, nil
+}
+
+func (s *tdposSchedule) loadVoteValue(res []byte, err error) (voteValue, error) {
+	if err != nil {
+		return nil, err
+	}
+	value := NewvoteValue()
+	if res == nil {
+		return value, nil
+	}
+	if err := json.Unmarshal(res, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
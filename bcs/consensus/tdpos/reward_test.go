@@ -0,0 +1,126 @@
+package tdpos
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func getRewardTdposConsensusConf() string {
+	return `{
+		"version": "2",
+        "timestamp": "1559021720000000000",
+        "proposer_num": "2",
+        "period": "3000",
+        "alternate_interval": "3000",
+        "term_interval": "6000",
+        "block_num": "20",
+        "vote_unit_price": "1",
+        "init_proposer": {
+            "1": ["TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY", "SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"]
+        },
+		"reward_sharing": {"default_commission": 50}
+	}`
+}
+
+func checkOutputs(t *testing.T, outputs []*protos.TxOutput, want [][2]interface{}) {
+	if len(outputs) != len(want) {
+		t.Fatalf("unexpected outputs count:%d, want:%d", len(outputs), len(want))
+	}
+	for i, w := range want {
+		amount := new(big.Int).SetBytes(outputs[i].Amount).Int64()
+		if string(outputs[i].ToAddr) != w[0].(string) || amount != w[1].(int64) {
+			t.Fatalf("unexpected output %d: %s %d, want:%v", i, outputs[i].ToAddr, amount, w)
+		}
+	}
+}
+
+func TestSplitAward(t *testing.T) {
+	ballots := map[string]int64{"P": 10, "V1": 30, "V2": 60}
+	// 投票人分得800，proposer自己的票数对应的分成与佣金合并
+	outputs := splitAward(big.NewInt(1000), "P", 20, ballots, 100)
+	checkOutputs(t, outputs, [][2]interface{}{{"P", int64(280)}, {"V2", int64(480)}, {"V1", int64(240)}})
+
+	// 只分配给票数最多的投票人
+	outputs = splitAward(big.NewInt(1000), "P", 20, ballots, 1)
+	checkOutputs(t, outputs, [][2]interface{}{{"P", int64(200)}, {"V2", int64(800)}})
+
+	// 整除的余数归proposer
+	outputs = splitAward(big.NewInt(10), "P", 0, map[string]int64{"V1": 1, "V2": 2}, 100)
+	checkOutputs(t, outputs, [][2]interface{}{{"P", int64(1)}, {"V2", int64(6)}, {"V1", int64(3)}})
+
+	outputs = splitAward(big.NewInt(1000), "P", 20, nil, 100)
+	checkOutputs(t, outputs, [][2]interface{}{{"P", int64(1000)}})
+}
+
+func TestRewardSharing(t *testing.T) {
+	cCtx, err := prepare(getRewardTdposConsensusConf())
+	if err != nil {
+		t.Fatal("prepare error", err)
+	}
+	i := NewTdposConsensus(*cCtx, getConfig(getRewardTdposConsensusConf()))
+	tdpos, _ := i.(*tdposConsensus)
+	proposer := "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"
+
+	// 提名时声明佣金比例
+	args := NewNominateArgs()
+	args["commission"] = []byte("120")
+	if _, err := tdpos.runNominateCandidate(kmock.NewFakeKContext(args, NewM())); err != ErrCommission {
+		t.Fatalf("invalid commission should be rejected, err:%v", err)
+	}
+	args["commission"] = []byte("20")
+	fakeCtx := kmock.NewFakeKContext(args, NewM())
+	if _, err := tdpos.runNominateCandidate(fakeCtx); err != nil {
+		t.Fatal(err)
+	}
+	commissions, err := tdpos.getCommissionValue(fakeCtx)
+	if err != nil || commissions[proposer] != 20 {
+		t.Fatalf("commission not saved, value:%v, err:%v", commissions, err)
+	}
+
+	l, _ := cCtx.Ledger.(*kmock.FakeLedger)
+	voteKey := fmt.Sprintf("tdpos_%d_%s%s", tdpos.status.Version, voteKeyPrefix, proposer)
+	votes, _ := json.Marshal(map[string]int64{"V1": 30, "V2": 10})
+	l.SetSnapshot(tdposBucket, []byte(voteKey), votes)
+	outputs, err := tdpos.SplitAward([]byte{2}, 3, proposer, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 未声明佣金比例时使用默认值
+	checkOutputs(t, outputs, [][2]interface{}{{proposer, int64(500)}, {"V1", int64(375)}, {"V2", int64(125)}})
+	cKey := []byte(fmt.Sprintf("tdpos_%d_%s", tdpos.status.Version, commissionKey))
+	cValue, _ := fakeCtx.Get(tdposBucket, cKey)
+	l.SetSnapshot(tdposBucket, cKey, cValue)
+	outputs, _ = tdpos.SplitAward([]byte{2}, 3, proposer, big.NewInt(1000))
+	checkOutputs(t, outputs, [][2]interface{}{{proposer, int64(200)}, {"V1", int64(600)}, {"V2", int64(200)}})
+
+	// 校验区块中的奖励交易
+	block := state.NewBlockAgent(&lpb.InternalBlock{
+		Height:       3,
+		PreHash:      []byte{2},
+		Proposer:     []byte(proposer),
+		Transactions: []*lpb.Transaction{{Coinbase: true, TxOutputs: outputs}},
+	})
+	if err := tdpos.verifyAward(block); err != nil {
+		t.Fatal(err)
+	}
+	outputs[1].Amount, outputs[2].Amount = outputs[2].Amount, outputs[1].Amount
+	if err := tdpos.verifyAward(block); err != ErrInvalidAward {
+		t.Fatalf("tampered award should be rejected, err:%v", err)
+	}
+	block = state.NewBlockAgent(&lpb.InternalBlock{
+		Height:       3,
+		PreHash:      []byte{2},
+		Proposer:     []byte(proposer),
+		Transactions: []*lpb.Transaction{{Coinbase: true, TxOutputs: outputs[:1]}},
+	})
+	if err := tdpos.verifyAward(block); err != ErrInvalidAward {
+		t.Fatalf("award without voter outputs should be rejected, err:%v", err)
+	}
+}
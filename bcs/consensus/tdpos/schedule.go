@@ -6,8 +6,11 @@ import (
 	"sort"
 	"time"
 
+	"github.com/xuperchain/xupercore/kernel/consensus/base/beacon"
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/liveness"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/lib/logs"
 )
 
@@ -39,9 +42,14 @@ type tdposSchedule struct {
 	consensusName      string
 	consensusVersion   int64
 	bindContractBucket string
+	// 候选人活跃度统计
+	liveness *liveness.Tracker
+	// 开启VRF选举时的随机信标，未开启时为nil
+	beacon *beacon.Beacon
 
 	log    logs.Logger
 	ledger cctx.LedgerRely
+	clock  cctx.Clock
 }
 
 // NewSchedule 新建schedule实例
@@ -60,6 +68,7 @@ func NewSchedule(xconfig *tdposConfig, log logs.Logger, ledger cctx.LedgerRely,
 		bindContractBucket: tdposBucket,
 		log:                log,
 		ledger:             ledger,
+		clock:              cctx.SystemClock,
 	}
 	index := 0
 	for index < len(schedule.validators) {
@@ -86,6 +95,8 @@ func NewSchedule(xconfig *tdposConfig, log logs.Logger, ledger cctx.LedgerRely,
 		schedule.consensusName = "xpos"
 		schedule.bindContractBucket = xposBucket
 	}
+	schedule.liveness = liveness.NewTracker(xconfig.Liveness, xconfig.Period, startHeight, ledger,
+		schedule.livenessSlot, schedule.blockValidators, log)
 	return schedule
 }
 
@@ -128,7 +139,12 @@ func (s *tdposSchedule) getSnapshotKey(height int64, bucket string, key []byte)
 		s.log.Debug("tdpos::getSnapshotKey::QueryBlockByHeight err.", "err", err)
 		return nil, err
 	}
-	reader, err := s.ledger.CreateSnapshot(block.GetBlockid())
+	return s.getBlockSnapshotKey(block.GetBlockid(), bucket, key)
+}
+
+// getBlockSnapshotKey 获取指定区块对应key的快照，区块可以不在主干上
+func (s *tdposSchedule) getBlockSnapshotKey(blockId []byte, bucket string, key []byte) ([]byte, error) {
+	reader, err := s.ledger.CreateSnapshot(blockId)
 	if err != nil {
 		s.log.Error("tdpos::getSnapshotKey::CreateSnapshot err.", "err", err)
 		return nil, err
@@ -157,16 +173,69 @@ func (s *tdposSchedule) GetLeader(round int64) string {
 	if proposers == nil {
 		return ""
 	}
-	addTime := s.calAddTime(round, s.ledger.QueryTipBlockHeader().GetHeight())
-	_, pos, _ := s.minerScheduling(time.Now().UnixNano() + addTime)
+	tipBlock := s.ledger.QueryTipBlockHeader()
+	nTime := s.clock.Now().UnixNano() + s.calAddTime(round, tipBlock.GetHeight())
+	_, pos, _ := s.minerScheduling(nTime)
 	if pos >= s.proposerNum {
 		return ""
 	}
-	return proposers[pos]
+	return s.getProposer(tipBlock.GetBlockid(), nTime, proposers)
+}
+
+// getProposer 返回parentId之后timestamp所在时间片实际的出块人，开启活跃度跳过策略或VRF选举时可能不是proposers[pos]
+func (s *tdposSchedule) getProposer(parentId []byte, timestamp int64, proposers []string) string {
+	term, pos, blockPos := s.minerScheduling(timestamp)
+	if pos < 0 || pos >= int64(len(proposers)) {
+		return ""
+	}
+	if s.beacon != nil {
+		return s.beacon.Proposer(parentId, s.slotBegin(term, pos), proposers)
+	}
+	if s.liveness == nil {
+		return proposers[pos]
+	}
+	slot := liveness.Slot{Term: term, Pos: pos, BlockPos: blockPos, First: blockPos <= 0}
+	return s.liveness.Proposer(parentId, slot, proposers)
+}
+
+// slotBegin 返回第term轮第pos个候选人轮值时间段的开始时间，单位为unixnano，与minerScheduling一致
+func (s *tdposSchedule) slotBegin(term int64, pos int64) int64 {
+	termTime := s.termInterval + (s.blockNum-1)*s.proposerNum*s.period + (s.proposerNum-1)*s.alternateInterval
+	termBegin := s.initTimestamp/int64(time.Millisecond) + (term-1)*termTime + s.termInterval - s.alternateInterval
+	posTime := s.alternateInterval + s.period*(s.blockNum-1)
+	return (termBegin + pos*posTime) * int64(time.Millisecond)
+}
+
+// livenessSlot 活跃度统计使用的时间片划分，与minerScheduling一致
+func (s *tdposSchedule) livenessSlot(timestamp int64, proposers []string) (liveness.Slot, bool) {
+	term, pos, blockPos := s.minerScheduling(timestamp)
+	if blockPos < 0 || blockPos >= s.blockNum || pos >= s.proposerNum || pos >= int64(len(proposers)) {
+		return liveness.Slot{}, false
+	}
+	return liveness.Slot{Term: term, Pos: pos, BlockPos: blockPos, First: blockPos == 0}, true
+}
+
+// blockValidators 返回校验该区块时使用的候选人集合
+func (s *tdposSchedule) blockValidators(block ledger.BlockHandle) ([]string, error) {
+	storage, _ := block.GetConsensusStorage()
+	return s.CalOldProposers(block.GetHeight(), block.GetTimestamp(), storage)
+}
+
+// getLiveness 返回当前候选人在tip区块及之前统计窗口内的出块情况
+func (s *tdposSchedule) getLiveness() map[string]*liveness.Stat {
+	if s.liveness == nil {
+		return nil
+	}
+	stats, err := s.liveness.ValidatorStats(s.ledger.QueryTipBlockHeader().GetBlockid(), s.validators)
+	if err != nil {
+		s.log.Warn("tdpos::getLiveness::calculate stats err.", "err", err)
+		return nil
+	}
+	return stats
 }
 
 func (s *tdposSchedule) calAddTime(round int64, tipHeight int64) int64 {
-	_, nowPos, nowBlockPos := s.minerScheduling(time.Now().UnixNano())
+	_, nowPos, nowBlockPos := s.minerScheduling(s.clock.Now().UnixNano())
 	if round <= tipHeight {
 		return 0
 	}
@@ -224,7 +293,7 @@ func (s *tdposSchedule) CalculateProposers(height int64) ([]string, error) {
 		return s.initValidators, nil
 	}
 	addTime := s.calAddTime(height, s.ledger.QueryTipBlockHeader().GetHeight())
-	inputTerm, _, _ := s.minerScheduling(time.Now().UnixNano() + addTime)
+	inputTerm, _, _ := s.minerScheduling(s.clock.Now().UnixNano() + addTime)
 	if s.curTerm == inputTerm {
 		return s.validators, nil
 	}
@@ -350,6 +419,15 @@ func (s *tdposSchedule) calHisValidators(height int64) ([]string, error) {
 		s.log.Error("tdpos::CalculateProposers::QueryBlockByHeight err.", "err", err)
 		return nil, err
 	}
+	targetHeight, err := s.termElectionHeight(block)
+	if err != nil {
+		return nil, err
+	}
+	return s.calTopKNominator(targetHeight)
+}
+
+// termElectionHeight 返回block所在term选举候选人时使用的高度，即上一个term的最后一个区块高度
+func (s *tdposSchedule) termElectionHeight(block ledger.BlockHandle) (int64, error) {
 	term, pos, blockPos := s.minerScheduling(block.GetTimestamp())
 	// 往前回溯的最远距离为internal，即该轮term之前最多生产过多少个区块
 	internal := pos*s.blockNum + blockPos
@@ -360,12 +438,12 @@ func (s *tdposSchedule) calHisValidators(height int64) ([]string, error) {
 	// 二分法实现快速查找
 	targetHeight, err := s.binarySearch(begin, block.GetHeight(), term)
 	if err != nil {
-		return nil, err
+		return -1, err
 	}
-	s.log.Debug("tdpos::CalculateProposers::target height.", "inputHeight", height, "targetHeight", targetHeight,
+	s.log.Debug("tdpos::CalculateProposers::target height.", "inputHeight", block.GetHeight(), "targetHeight", targetHeight,
 		"begin", begin, "end", block.GetHeight(), "term", term, "pos", pos, "blockPos", blockPos, "internal", internal,
 		"blockNum", s.blockNum, "block.Timestamp", block.GetTimestamp())
-	return s.calTopKNominator(targetHeight)
+	return targetHeight, nil
 }
 
 // binarySearch 二分法快速查找
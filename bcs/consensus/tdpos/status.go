@@ -2,6 +2,10 @@ package tdpos
 
 import (
 	"encoding/json"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	chainedBft "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/liveness"
 )
 
 type ValidatorsInfo struct {
@@ -9,6 +13,8 @@ type ValidatorsInfo struct {
 	Miner        string   `json:"miner"`
 	Curterm      int64    `json:"curterm"`
 	ContractInfo string   `json:"contract"`
+	// 各候选人最近的出块情况
+	Liveness map[string]*liveness.Stat `json:"liveness,omitempty"`
 }
 
 // tdposStatus 实现了ConsensusStatus接口
@@ -18,6 +24,8 @@ type TdposStatus struct {
 	StartHeight int64 `json:"startHeight"`
 	Index       int   `json:"index"`
 	election    *tdposSchedule
+	// 开启chained-bft时的状态机，用于查询commitQC
+	smr *chainedBft.Smr
 }
 
 // 获取共识版本号
@@ -55,7 +63,22 @@ func (t *TdposStatus) GetCurrentValidatorsInfo() []byte {
 		Curterm:      t.election.curTerm,
 		Miner:        t.election.miner,
 		ContractInfo: "pls invoke getTdposInfos",
+		Liveness:     t.election.getLiveness(),
 	}
 	b, _ := json.Marshal(&v)
 	return b
 }
+
+// GetFinalizedBlock 开启chained-bft时commitQC对应的区块不可逆
+// 未开启时按账本的不可逆滑动窗口计算，未配置滑动窗口时以全部候选人各出blockNum个块的一整轮作为窗口
+func (t *TdposStatus) GetFinalizedBlock() (int64, []byte) {
+	if t.smr != nil {
+		qc := t.smr.GetCommitQC()
+		return qc.GetProposalView(), qc.GetProposalId()
+	}
+	window := common.SlideWindow(t.election.ledger)
+	if window <= 0 {
+		window = t.election.proposerNum * t.election.blockNum
+	}
+	return common.FinalizedByDepth(t.election.ledger, window)
+}
@@ -1,8 +1,11 @@
 package tdpos
 
 import (
+	"bytes"
 	"encoding/json"
 	"testing"
+
+	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
 )
 
 func TestGetCurrentValidatorsInfo(t *testing.T) {
@@ -28,4 +31,35 @@ func TestGetCurrentValidatorsInfo(t *testing.T) {
 	if err := json.Unmarshal(b, &addrs); err != nil {
 		t.Error("GetCurrentValidatorsInfo error", "error", err)
 	}
+	for _, v := range addrs.Validators {
+		if _, ok := addrs.Liveness[v]; !ok {
+			t.Error("GetCurrentValidatorsInfo liveness missing", "validator", v)
+		}
+	}
+}
+
+func TestGetFinalizedBlock(t *testing.T) {
+	tdposCfg, err := buildConfigs([]byte(getTdposConsensusConf()))
+	if err != nil {
+		t.Fatal("Config unmarshal err", "err", err)
+	}
+	cCtx, err := prepare(getTdposConsensusConf())
+	if err != nil {
+		t.Fatal("prepare error", "error", err)
+	}
+	status := TdposStatus{
+		Version:     1,
+		StartHeight: 1,
+		election:    NewSchedule(tdposCfg, cCtx.XLog, cCtx.Ledger, 1),
+	}
+	l, _ := cCtx.Ledger.(*kmock.FakeLedger)
+	for i := 3; i <= 50; i++ {
+		l.Put(kmock.NewBlock(i))
+	}
+	// 未配置滑动窗口时以一整轮(2个候选人各20个块)作为窗口
+	height, blockid := status.GetFinalizedBlock()
+	b, _ := l.QueryBlockHeaderByHeight(10)
+	if height != 10 || !bytes.Equal(blockid, b.GetBlockid()) {
+		t.Errorf("GetFinalizedBlock want 10, got %d", height)
+	}
 }
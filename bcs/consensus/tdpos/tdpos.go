@@ -8,11 +8,13 @@ import (
 
 	"github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/consensus"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/beacon"
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
 	chainedBft "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft"
 	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
 	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
 	quorumcert "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/lib/utils"
@@ -35,6 +37,7 @@ type tdposConsensus struct {
 	smr       *chainedBft.Smr
 	contract  contract.Manager
 	kMethod   map[string]contract.KernMethod
+	evidence  *evidence.Pool
 	log       logs.Logger
 }
 
@@ -67,6 +70,10 @@ func NewTdposConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consens
 		return nil
 	}
 	schedule.address = cCtx.Network.PeerInfo().Account
+	schedule.clock = cCtx.GetClock()
+	if xconfig.EnableVRF {
+		schedule.beacon = beacon.NewBeacon(cCfg.StartHeight, cCtx.Ledger, cCtx.Crypto, cCtx.XLog)
+	}
 
 	status := &TdposStatus{
 		Version:     xconfig.Version,
@@ -86,6 +93,7 @@ func NewTdposConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consens
 		election:  schedule,
 		status:    status,
 		contract:  cCtx.Contract,
+		evidence:  evidence.NewPool(cCtx.Crypto, xconfig.Period, cCtx.XLog),
 		log:       cCtx.XLog,
 		cCtx:      cCtx,
 	}
@@ -96,12 +104,13 @@ func NewTdposConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consens
 		contractVote:              tdpos.runVote,
 		contractRevokeVote:        tdpos.runRevokeVote,
 		contractGetTdposInfos:     tdpos.runGetTdposInfos,
+		contractSubmitEvidence:    tdpos.runSubmitEvidence,
 	}
 
 	tdpos.kMethod = tdposKMethods
 
 	// 凡属于共识升级的逻辑，新建的Tdpos实例将直接将当前值置为true，原因是上一共识模块已经在当前值生成了高度为trigger height的区块，新的实例会再生成一边
-	timeKey := time.Now().Sub(time.Unix(0, 0)).Milliseconds() / tdpos.config.Period
+	timeKey := schedule.clock.Now().Sub(time.Unix(0, 0)).Milliseconds() / tdpos.config.Period
 	tdpos.isProduce[timeKey] = true
 	return tdpos
 }
@@ -109,7 +118,7 @@ func NewTdposConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consens
 // CompeteMaster is the specific implementation of ConsensusInterface
 func (tp *tdposConsensus) CompeteMaster(height int64) (bool, bool, error) {
 Again:
-	t := time.Now().UnixNano() / int64(time.Millisecond)
+	t := tp.election.clock.Now().UnixNano() / int64(time.Millisecond)
 	key := t / tp.config.Period
 	sleep := tp.config.Period - t%tp.config.Period
 	if sleep > MAXSLEEPTIME {
@@ -119,14 +128,15 @@ Again:
 	if !ok {
 		tp.isProduce[key] = true
 	} else {
-		time.Sleep(time.Duration(sleep) * time.Millisecond)
+		tp.election.clock.Sleep(time.Duration(sleep) * time.Millisecond)
 		// 定期清理isProduce
-		common.CleanProduceMap(tp.isProduce, tp.config.Period)
+		common.CleanProduceMap(tp.isProduce, tp.config.Period, tp.election.clock.Now())
 		goto Again
 	}
 
 	// 查当前时间的term 和 pos
-	term, pos, blockPos := tp.election.minerScheduling(time.Now().UnixNano())
+	now := tp.election.clock.Now().UnixNano()
+	term, pos, blockPos := tp.election.minerScheduling(now)
 	if blockPos < 0 || blockPos >= tp.election.blockNum || pos >= tp.election.proposerNum {
 		tp.log.Debug("consensus:tdpos:CompeteMaster: minerScheduling err", "term", term, "pos", pos, "blockPos", blockPos)
 		goto Again
@@ -137,9 +147,9 @@ Again:
 	}
 	// 查当前term 和 pos是否是自己
 	tp.election.curTerm = term
-	tp.election.miner = tp.election.validators[pos]
+	tp.election.miner = tp.election.getProposer(tp.election.ledger.QueryTipBlockHeader().GetBlockid(), now, tp.election.validators)
 	// master check
-	if tp.election.validators[pos] == tp.election.address {
+	if tp.election.miner == tp.election.address {
 		tp.log.Debug("consensus:tdpos:CompeteMaster: now xterm infos", "term", term, "pos", pos, "blockPos", blockPos, "master", true, "height", tp.election.ledger.QueryTipBlockHeader().GetHeight())
 		s := tp.needSync()
 		return true, s, nil
@@ -176,12 +186,35 @@ func (tp *tdposConsensus) CheckMinerMatch(ctx xcontext.XContext, block cctx.Bloc
 		tp.log.Error("consensus:tdpos:CheckMinerMatch: CalculateProposers error", "err", err)
 		return false, err
 	}
-	if wantProposers[pos] != string(block.GetProposer()) {
+	// 开启活跃度跳过策略时，按照父区块之前的统计确定该时间片实际的出块人
+	want := tp.election.getProposer(block.GetPreHash(), block.GetTimestamp(), wantProposers)
+	if want != string(block.GetProposer()) {
 		tp.log.Error("consensus:tdpos:CheckMinerMatch: invalid proposer",
-			"want", wantProposers[pos], "have", string(block.GetProposer()),
+			"want", want, "have", string(block.GetProposer()),
 			"wantProposers", wantProposers, "pos", pos)
 		return false, ErrInvalidProposer
 	}
+	// 开启VRF选举时校验出块人的VRF证明，该证明决定了后续时间片的出块人
+	if tp.election.beacon != nil {
+		if err := tp.election.beacon.Verify(block); err != nil {
+			tp.log.Error("consensus:tdpos:CheckMinerMatch: verify vrf proof error", "err", err, "blockId", utils.F(block.GetBlockid()))
+			return false, err
+		}
+	}
+	// 校验区块记录的活跃度统计，该统计决定了后续时间片的出块人
+	if tp.election.liveness != nil {
+		if err := tp.election.liveness.Verify(block); err != nil {
+			tp.log.Error("consensus:tdpos:CheckMinerMatch: verify liveness counts error", "err", err, "blockId", utils.F(block.GetBlockid()))
+			return false, err
+		}
+	}
+	// 开启奖励分成时，校验奖励交易按照佣金比例和投票人票数分配
+	if err := tp.verifyAward(block); err != nil {
+		tp.log.Error("consensus:tdpos:CheckMinerMatch: invalid award tx", "err", err, "blockId", utils.F(block.GetBlockid()))
+		return false, err
+	}
+	// 记录区块，同一矿工在同一时间片生产了不同区块时生成双签证据
+	tp.evidence.AddBlock(block)
 
 	if !tp.election.enableChainedBFT {
 		return true, nil
@@ -223,7 +256,8 @@ func (tp *tdposConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, [
 			"blockPos", blockPos, "tp.election.blockNum", tp.election.blockNum, "pos", pos, "tp.election.proposerNum", tp.election.proposerNum)
 		return nil, nil, ErrTimeoutBlock
 	}
-	if tp.election.validators[pos] != tp.election.address {
+	tipBlock := tp.election.ledger.GetTipBlock()
+	if tp.election.getProposer(tipBlock.GetBlockid(), timestamp, tp.election.validators) != tp.election.address {
 		return nil, nil, ErrTimeoutBlock
 	}
 	storage := common.ConsensusStorage{
@@ -231,6 +265,12 @@ func (tp *tdposConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, [
 		CurBlockNum: blockPos,
 	}
 	if !tp.election.enableChainedBFT {
+		if err := tp.recordLiveness(&storage, tipBlock.GetBlockid()); err != nil {
+			return nil, nil, err
+		}
+		if err := tp.proveVrf(&storage, tipBlock.GetBlockid()); err != nil {
+			return nil, nil, err
+		}
 		storageBytes, err := json.Marshal(storage)
 		if err != nil {
 			return nil, nil, err
@@ -240,7 +280,6 @@ func (tp *tdposConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, [
 
 	// 根据BFT配置判断是否需要加入Chained-BFT相关存储，及变更smr状态
 	// 即本地smr的HightQC和账本TipId不相等，tipId尚未收集到足够签名，回滚到本地HighQC，重做区块
-	tipBlock := tp.election.ledger.GetTipBlock()
 	// smr返回一个裁剪目标，供miner模块直接回滚并出块
 	truncate, qc, err := tp.smr.ResetProposerStatus(tipBlock, tp.election.ledger.QueryBlockHeader, tp.election.validators)
 	if err != nil {
@@ -248,7 +287,14 @@ func (tp *tdposConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, [
 	}
 	// 候选人组仅一个时无需操作
 	if qc == nil {
-		return nil, nil, nil
+		if tp.election.beacon == nil {
+			return nil, nil, nil
+		}
+		if err := tp.proveVrf(&storage, tipBlock.GetBlockid()); err != nil {
+			return nil, nil, err
+		}
+		storageBytes, _ := json.Marshal(storage)
+		return nil, storageBytes, nil
 	}
 
 	qcQuorumCert, _ := qc.(*quorumcert.QuorumCert)
@@ -258,14 +304,58 @@ func (tp *tdposConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, [
 	if truncate {
 		tp.log.Warn("consensus:tdpos:ProcessBeforeMiner: last block not confirmed, walk to previous block",
 			"target", utils.F(qc.GetProposalId()), "ledger", tipBlock.GetHeight())
+		// 回滚后父区块发生变化，需要按照新的父区块重新确认出块人
+		if tp.election.getProposer(qc.GetProposalId(), timestamp, tp.election.validators) != tp.election.address {
+			return nil, nil, ErrTimeoutBlock
+		}
 		storage.TargetBits = int32(tipBlock.GetHeight())
+		if err := tp.recordLiveness(&storage, qc.GetProposalId()); err != nil {
+			return nil, nil, err
+		}
+		if err := tp.proveVrf(&storage, qc.GetProposalId()); err != nil {
+			return nil, nil, err
+		}
 		storageBytes, _ := json.Marshal(storage)
 		return qc.GetProposalId(), storageBytes, nil
 	}
+	if err := tp.recordLiveness(&storage, tipBlock.GetBlockid()); err != nil {
+		return nil, nil, err
+	}
+	if err := tp.proveVrf(&storage, tipBlock.GetBlockid()); err != nil {
+		return nil, nil, err
+	}
 	storageBytes, _ := json.Marshal(storage)
 	return nil, storageBytes, nil
 }
 
+// recordLiveness 将以parentId为结尾的活跃度统计写入storage，后续区块在此基础上统计
+func (tp *tdposConsensus) recordLiveness(storage *common.ConsensusStorage, parentId []byte) error {
+	if tp.election.liveness == nil {
+		return nil
+	}
+	counts, err := tp.election.liveness.Counts(parentId)
+	if err != nil {
+		tp.log.Error("consensus:tdpos:ProcessBeforeMiner: calculate liveness counts error", "err", err)
+		return err
+	}
+	storage.Liveness = counts
+	return nil
+}
+
+// proveVrf 开启VRF选举时，为parentId之后的新区块计算VRF证明并写入storage
+func (tp *tdposConsensus) proveVrf(storage *common.ConsensusStorage, parentId []byte) error {
+	if tp.election.beacon == nil {
+		return nil
+	}
+	proof, err := tp.election.beacon.Prove(tp.cCtx.Address.PrivateKey, parentId)
+	if err != nil {
+		tp.log.Error("consensus:tdpos:ProcessBeforeMiner: calculate vrf proof error", "err", err)
+		return err
+	}
+	storage.VrfProof = proof
+	return nil
+}
+
 // ProcessConfirmBlock 用于确认块后进行相应的处理
 func (tp *tdposConsensus) ProcessConfirmBlock(block cctx.BlockInterface) error {
 	if !tp.election.enableChainedBFT {
@@ -294,7 +384,8 @@ func (tp *tdposConsensus) ProcessConfirmBlock(block cctx.BlockInterface) error {
 		return ErrSchedule
 	}
 	var nextValidators []string
-	if tp.election.validators[pos] == tp.election.address && string(block.GetProposer()) == tp.election.address {
+	if string(block.GetProposer()) == tp.election.address &&
+		tp.election.getProposer(block.GetPreHash(), block.GetTimestamp(), tp.election.validators) == tp.election.address {
 		// 如果是当前矿工，检测到下一轮需变更validates，且下一轮proposer并不在节点列表中，此时需在广播列表中新加入节点
 		nextValidators = tp.election.GetValidators(block.GetHeight() + 1)
 	}
@@ -347,6 +438,7 @@ func (tp *tdposConsensus) initBFT() error {
 		Log:    tp.cCtx.XLog,
 	}
 	smr := chainedBft.NewSmr(tp.bcName, tp.election.address, tp.log, tp.cCtx.Network, cryptoClient, pacemaker, saftyrules, tp.election, qcTree)
+	smr.SetEvidencePool(tp.evidence)
 	// 重启状态检查2，重做tipBlock，此时需重装载justify签名
 	if !bytes.Equal(qcTree.GetGenesisQC().In.GetProposalId(), qcTree.GetRootQC().In.GetProposalId()) {
 		for i := int64(0); i < 3; i++ {
@@ -358,6 +450,7 @@ func (tp *tdposConsensus) initBFT() error {
 		}
 	}
 	tp.smr = smr
+	tp.status.smr = smr
 	tp.smr.Start()
 	return nil
 }
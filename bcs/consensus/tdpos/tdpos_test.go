@@ -228,3 +228,75 @@ func TestBFT(t *testing.T) {
 		return
 	}
 }
+
+func getVRFTdposConsensusConf() string {
+	return `{
+		"version": "2",
+        "timestamp": "1559021720000000000",
+        "proposer_num": "2",
+        "period": "3000",
+        "alternate_interval": "3000",
+        "term_interval": "6000",
+        "block_num": "2",
+        "vote_unit_price": "1",
+        "init_proposer": {
+            "1": ["dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN", "SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"]
+        },
+		"enable_vrf": true
+	}`
+}
+
+func TestVRF(t *testing.T) {
+	cCtx, err := prepare(getVRFTdposConsensusConf())
+	if err != nil {
+		t.Fatal("prepare error", "error", err)
+	}
+	i := NewTdposConsensus(*cCtx, getConfig(getVRFTdposConsensusConf()))
+	if i == nil {
+		t.Fatal("NewTdposConsensus error", "conf", getConfig(getVRFTdposConsensusConf()))
+	}
+	tdpos, _ := i.(*tdposConsensus)
+	tdpos.election.address = bmock.Miner
+	l, _ := cCtx.Ledger.(*kmock.FakeLedger)
+	tip := l.GetTipBlock()
+
+	// 找到本节点被抽中的出块时间片
+	validators := tdpos.election.validators
+	ts := tip.GetTimestamp()
+	for n := 0; ; n++ {
+		if n > 1000 {
+			t.Fatal("local node is never elected")
+		}
+		ts += tdpos.election.period * int64(time.Millisecond)
+		_, pos, blockPos := tdpos.election.minerScheduling(ts)
+		if blockPos < 0 || blockPos >= tdpos.election.blockNum || pos >= tdpos.election.proposerNum {
+			continue
+		}
+		if tdpos.election.getProposer(tip.GetBlockid(), ts, validators) == bmock.Miner {
+			break
+		}
+	}
+	tdpos.election.curTerm, _, _ = tdpos.election.minerScheduling(ts)
+	_, storage, err := tdpos.ProcessBeforeMiner(tip.GetHeight()+1, ts)
+	if err != nil {
+		t.Fatal("ProcessBeforeMiner error", "err", err)
+	}
+	b3 := kmock.NewBlock(3)
+	b3.SetTimestamp(ts)
+	b3.PublicKey = bmock.PubKey
+	b3.ConsensusStorage = storage
+	if ok, err := tdpos.CheckMinerMatch(&cCtx.BaseCtx, b3); !ok || err != nil {
+		t.Fatal("CheckMinerMatch error", "err", err)
+	}
+	s, _ := common.ParseOldQCStorage(storage)
+	s.VrfProof = s.VrfProof[1:]
+	b3.ConsensusStorage, _ = json.Marshal(s)
+	if ok, _ := tdpos.CheckMinerMatch(&cCtx.BaseCtx, b3); ok {
+		t.Fatal("block with invalid vrf proof should be rejected")
+	}
+
+	conf := `{"liveness": {"miss_threshold": 3}, ` + getVRFTdposConsensusConf()[1:]
+	if _, err := buildConfigs([]byte(conf)); err != ErrVRFConfig {
+		t.Fatal("vrf should not be enabled with liveness skip policy", "err", err)
+	}
+}
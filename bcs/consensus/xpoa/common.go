@@ -4,6 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"strconv"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	quorumcert "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/liveness"
+	"github.com/xuperchain/xupercore/kernel/ledger"
 )
 
 var (
@@ -15,6 +20,13 @@ var (
 	tooLowHeight     = errors.New("The height should be higher than 3.")
 	aclErr           = errors.New("Xpoa needs valid acl account.")
 	scheduleErr      = errors.New("minerScheduling overflow")
+	vrfConfigErr     = errors.New("enable_vrf cannot be used with liveness skip policy")
+	weightErr        = errors.New("Validator weights are invalid, weights should be positive and the reduced total weight should not exceed 1000.")
+
+	evidenceHeightErr = errors.New("evidence height should be higher than consensus start height")
+	repeatEvidenceErr = errors.New("evidence has been submitted")
+	notValidatorErr   = errors.New("offender is not a current validator")
+	lastValidatorErr  = errors.New("cannot remove the last validator")
 )
 
 const (
@@ -23,11 +35,19 @@ const (
 	validateKeys         = "validates"
 	contractGetValidates = "getValidates"
 	contractEditValidate = "editValidates"
+	// 提交双签证据
+	contractSubmitEvidence = "submitEvidence"
+	evidenceKeyPrefix      = "evidence_"
 
 	fee = 1000
 
 	MAXSLEEPTIME = 1000
 	MAXMAPSIZE   = 1000
+	// 按权重展开后每一轮的最大出块时间片个数
+	MAXWEIGHTSLOTS = 1000
+
+	// bft_config中开启纪元重配置的配置项，开启后验证人变更在变更区块被旧集合的QC提交后才生效
+	bftEpochKey = "epoch"
 )
 
 type xpoaConfig struct {
@@ -37,17 +57,52 @@ type xpoaConfig struct {
 	Period       int64        `json:"period"`
 	InitProposer ProposerInfo `json:"init_proposer"`
 
+	// 非空时开启chained-bft，{"epoch": true}时开启纪元重配置
 	EnableBFT map[string]bool `json:"bft_config,omitempty"`
+	// 验证人活跃度统计及跳过策略
+	Liveness *liveness.Config `json:"liveness,omitempty"`
+	// 开启后每个出块时间片的出块人由VRF随机数从验证人中抽取，不能与跳过策略同时开启
+	EnableVRF bool `json:"enable_vrf,omitempty"`
 }
 
 type ProposerInfo struct {
 	Address []string `json:"address"`
+	// 候选人投票权重，为空时各候选人等权，未列出的候选人权重为1
+	Weights map[string]int64 `json:"weights,omitempty"`
+}
+
+// slots 返回按权重展开的出块顺序
+func (p *ProposerInfo) slots() []string {
+	return scheduleSlots(p.Address, p.Weights)
 }
 
 // LoadValidatorsMultiInfo
 // xpoa 格式为
 // { "address": [$ADDR_STRING...] }
 func loadValidatorsMultiInfo(res []byte) ([]string, error) {
+	info, err := loadProposerInfo(res)
+	if err != nil {
+		return nil, err
+	}
+	return info.Address, nil
+}
+
+// blockJustify 解析区块共识存储中的justify，区块未携带justify时返回nil
+func blockJustify(block ledger.BlockHandle) (quorumcert.QuorumCertInterface, error) {
+	storage, err := block.GetConsensusStorage()
+	if err != nil || len(storage) == 0 {
+		return nil, err
+	}
+	justify, err := common.OldQCToNew(storage)
+	if err == common.InvalidJustify {
+		return nil, nil
+	}
+	return justify, err
+}
+
+// loadProposerInfo 读取候选人及其权重
+// { "address": [$ADDR_STRING...], "weights": {$ADDR_STRING: $WEIGHT...} }
+func loadProposerInfo(res []byte) (*ProposerInfo, error) {
 	if res == nil {
 		return nil, NotValidContract
 	}
@@ -56,7 +111,104 @@ func loadValidatorsMultiInfo(res []byte) ([]string, error) {
 	if err := json.Unmarshal(res, &contractInfo); err != nil {
 		return nil, err
 	}
-	return contractInfo.Address, nil
+	return &contractInfo, nil
+}
+
+// checkWeights 检查候选人权重，权重需为正数且只能设置给候选人，约分后的总权重不能超过MAXWEIGHTSLOTS
+func checkWeights(validators []string, weights map[string]int64) error {
+	if len(weights) == 0 {
+		return nil
+	}
+	for addr, w := range weights {
+		if w <= 0 || !Find(addr, validators) {
+			return weightErr
+		}
+	}
+	if _, total := reducedWeights(validators, weights); total > MAXWEIGHTSLOTS {
+		return weightErr
+	}
+	return nil
+}
+
+// weightOf 返回候选人的投票权重，未设置权重时为1
+func weightOf(weights map[string]int64, addr string) int64 {
+	if w, ok := weights[addr]; ok {
+		return w
+	}
+	return 1
+}
+
+// totalWeight 返回候选人的总权重
+func totalWeight(validators []string, weights map[string]int64) int64 {
+	var sum int64
+	for _, v := range validators {
+		sum += weightOf(weights, v)
+	}
+	return sum
+}
+
+// scheduleSlots 按权重展开一轮的出块顺序，每个候选人的时间片个数与其约分后的权重相等
+// 采用平滑加权轮询，同一候选人的时间片尽量分散，权重为空时即为validators本身
+func scheduleSlots(validators []string, weights map[string]int64) []string {
+	w, total := reducedWeights(validators, weights)
+	if w == nil || total == int64(len(validators)) {
+		return validators
+	}
+	current := make([]int64, len(validators))
+	slots := make([]string, 0, total)
+	for k := int64(0); k < total; k++ {
+		best := 0
+		for i := range validators {
+			current[i] += w[i]
+			if current[i] > current[best] {
+				best = i
+			}
+		}
+		current[best] -= total
+		slots = append(slots, validators[best])
+	}
+	return slots
+}
+
+// reducedWeights 返回按最大公约数约分后的各候选人权重及其总和
+func reducedWeights(validators []string, weights map[string]int64) ([]int64, int64) {
+	if len(weights) == 0 || len(validators) == 0 {
+		return nil, 0
+	}
+	var g int64
+	for _, v := range validators {
+		g = gcd(g, weightOf(weights, v))
+	}
+	if g <= 0 {
+		return nil, 0
+	}
+	w := make([]int64, len(validators))
+	var total int64
+	for i, v := range validators {
+		w[i] = weightOf(weights, v) / g
+		total += w[i]
+	}
+	return w, total
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// weightsEqual 判断两组权重是否一致
+func weightsEqual(a, b map[string]int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
 }
 
 func Find(a string, t []string) bool {
@@ -81,6 +233,14 @@ func CalFault(input, sum int64) bool {
 	return input >= (sum-f)/2+1
 }
 
+// CalWeightFault 候选人带投票权重时，判断签名候选人的权重之和是否超过总权重的1/3
+func CalWeightFault(input, sum int64) bool {
+	if sum <= 0 {
+		return false
+	}
+	return 3*input > sum
+}
+
 // 每个地址每一轮的总票数
 type aksItem struct {
 	Address string
@@ -79,3 +79,56 @@ func TestParseVersion(t *testing.T) {
 		return
 	}
 }
+
+func TestScheduleSlots(t *testing.T) {
+	v := []string{"a", "b", "c"}
+	if s := scheduleSlots(v, nil); len(s) != 3 {
+		t.Fatalf("equal weights should keep validators, slots:%v", s)
+	}
+	if s := scheduleSlots(v, map[string]int64{"a": 2, "b": 2, "c": 2}); len(s) != 3 || s[0] != "a" || s[2] != "c" {
+		t.Fatalf("same weights should keep validators, slots:%v", s)
+	}
+	s := scheduleSlots(v, map[string]int64{"a": 6, "b": 2})
+	cnt := make(map[string]int)
+	for _, addr := range s {
+		cnt[addr]++
+	}
+	if len(s) != 9 || cnt["a"] != 6 || cnt["b"] != 2 || cnt["c"] != 1 {
+		t.Fatalf("slots should be proportional to weights, slots:%v", s)
+	}
+	// 平滑加权轮询下同一候选人不会连续占满一轮的开头
+	if s[0] != "a" || s[1] == "a" && s[2] == "a" && s[3] == "a" {
+		t.Fatalf("slots should be interleaved, slots:%v", s)
+	}
+}
+
+func TestCheckWeights(t *testing.T) {
+	v := []string{"a", "b"}
+	if err := checkWeights(v, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkWeights(v, map[string]int64{"a": 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkWeights(v, map[string]int64{"a": 0}); err != weightErr {
+		t.Fatal("zero weight should be rejected")
+	}
+	if err := checkWeights(v, map[string]int64{"c": 1}); err != weightErr {
+		t.Fatal("weight of non validator should be rejected")
+	}
+	if err := checkWeights(v, map[string]int64{"a": 1000, "b": 1}); err != weightErr {
+		t.Fatal("too many slots should be rejected")
+	}
+}
+
+func TestCalWeightFault(t *testing.T) {
+	if CalWeightFault(1, 3) {
+		t.Error("TestCalWeightFault error 1.")
+	}
+	if !CalWeightFault(2, 3) {
+		t.Error("TestCalWeightFault error 2.")
+	}
+	if CalWeightFault(1, 0) {
+		t.Error("TestCalWeightFault error 3.")
+	}
+}
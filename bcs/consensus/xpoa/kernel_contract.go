@@ -8,11 +8,12 @@ import (
 	"strings"
 
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
 	"github.com/xuperchain/xupercore/kernel/contract"
 )
 
 // runChangeValidates 候选人变更，替代原三代合约的add_validates/delete_validates/change_validates三个操作方法
-// Args: validates::候选人钱包地址
+// Args: validates::候选人钱包地址, weights::可选，json格式的候选人投票权重，未设置时各候选人等权
 func (x *xpoaConsensus) methodEditValidates(contractCtx contract.KContext) (*contract.Response, error) {
 	// 核查变更候选人合约参数有效性
 	txArgs := contractCtx.Args()
@@ -34,24 +35,11 @@ func (x *xpoaConsensus) methodEditValidates(contractCtx contract.KContext) (*con
 		return common.NewContractErrResponse(common.StatusBadRequest, "invalid acl: pls check accept value."), err
 	}
 
-	curValiBytes, err := contractCtx.Get(x.election.bindContractBucket,
-		[]byte(fmt.Sprintf("%d_%s", x.election.consensusVersion, validateKeys)))
-	curVali, err := func() ([]string, error) {
-		if err != nil || curValiBytes == nil {
-			return x.election.initValidators, nil
-		}
-		var curValiKey ProposerInfo
-		err = json.Unmarshal(curValiBytes, &curValiKey)
-		if err != nil {
-			x.log.Error("Unmarshal error")
-			return nil, err
-		}
-		return curValiKey.Address, nil
-	}()
+	curVali, err := x.getCurrentValidatorInfo(contractCtx)
 	if err != nil {
 		return common.NewContractErrResponse(common.StatusBadRequest, err.Error()), err
 	}
-	if !x.isAuthAddress(curVali, aks, acceptValue, x.election.enableBFT) {
+	if !x.isAuthValidators(curVali, aks, acceptValue, x.election.enableBFT) {
 		return common.NewContractErrResponse(common.StatusBadRequest, aclErr.Error()), aclErr
 	}
 
@@ -62,8 +50,18 @@ func (x *xpoaConsensus) methodEditValidates(contractCtx contract.KContext) (*con
 		return common.NewContractErrResponse(common.StatusBadRequest, targetParamErr.Error()), targetParamErr
 	}
 	validators := strings.Split(validatesAddrs, ";")
+	var weights map[string]int64
+	if weightsBytes := txArgs["weights"]; len(weightsBytes) > 0 {
+		if err := json.Unmarshal(weightsBytes, &weights); err != nil {
+			return common.NewContractErrResponse(common.StatusBadRequest, weightErr.Error()), weightErr
+		}
+	}
+	if err := checkWeights(validators, weights); err != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, err.Error()), err
+	}
 	rawV := &ProposerInfo{
 		Address: validators,
+		Weights: weights,
 	}
 	rawBytes, err := json.Marshal(rawV)
 	if err != nil {
@@ -87,9 +85,12 @@ func (x *xpoaConsensus) methodGetValidates(contractCtx contract.KContext) (*cont
 	validatesBytes, err := contractCtx.Get(x.election.bindContractBucket,
 		[]byte(fmt.Sprintf("%d_%s", x.election.consensusVersion, validateKeys)))
 	if err != nil {
-		returnV := map[string][]string{
+		returnV := map[string]interface{}{
 			"validators": x.election.initValidators,
 		}
+		if len(x.election.initWeights) > 0 {
+			returnV["weights"] = x.election.initWeights
+		}
 		jsonBytes, err = json.Marshal(returnV)
 		if err != nil {
 			return common.NewContractErrResponse(common.StatusErr, err.Error()), err
@@ -97,6 +98,14 @@ func (x *xpoaConsensus) methodGetValidates(contractCtx contract.KContext) (*cont
 	} else {
 		jsonBytes = validatesBytes
 	}
+	// 附带各验证人最近的出块情况
+	if stats := x.election.getLiveness(); stats != nil {
+		returnV := make(map[string]interface{})
+		if err := json.Unmarshal(jsonBytes, &returnV); err == nil {
+			returnV["liveness"] = stats
+			jsonBytes, _ = json.Marshal(returnV)
+		}
+	}
 	delta := contract.Limits{
 		XFee: fee / 1000,
 	}
@@ -104,6 +113,128 @@ func (x *xpoaConsensus) methodGetValidates(contractCtx contract.KContext) (*cont
 	return common.NewContractOKResponse(jsonBytes), nil
 }
 
+// methodSubmitEvidence 提交验证人双签证据，验证通过后将作恶的验证人移出候选人集合
+// Args: evidence::json格式的双签证据
+func (x *xpoaConsensus) methodSubmitEvidence(contractCtx contract.KContext) (*contract.Response, error) {
+	// 1. 校验证据本身
+	ev, err := evidence.Unmarshal(contractCtx.Args()["evidence"])
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, err.Error()), err
+	}
+	if err := evidence.Verify(ev, x.cCtx.Crypto, x.config.Period); err != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, err.Error()), err
+	}
+	// 共识升级之前的双签由之前的共识实例处理
+	if ev.Height <= x.status.StartHeight {
+		return common.NewContractErrResponse(common.StatusBadRequest, evidenceHeightErr.Error()), evidenceHeightErr
+	}
+
+	// 2. 同一证据只能使用一次，被移除的验证人重新加入后不会被旧证据再次惩罚
+	evKey := []byte(fmt.Sprintf("%d_%s%s", x.election.consensusVersion, evidenceKeyPrefix, ev.ID()))
+	res, err := contractCtx.Get(x.election.bindContractBucket, evKey)
+	if err == nil && res != nil {
+		return common.NewContractErrResponse(common.StatusBadRequest, repeatEvidenceErr.Error()), repeatEvidenceErr
+	}
+
+	// 3. 将作恶的验证人移出候选人集合，至少保留一个验证人
+	curVali, err := x.getCurrentValidatorInfo(contractCtx)
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if !Find(ev.Offender, curVali.Address) {
+		return common.NewContractErrResponse(common.StatusBadRequest, notValidatorErr.Error()), notValidatorErr
+	}
+	if len(curVali.Address) == 1 {
+		return common.NewContractErrResponse(common.StatusBadRequest, lastValidatorErr.Error()), lastValidatorErr
+	}
+	validators := make([]string, 0, len(curVali.Address)-1)
+	for _, v := range curVali.Address {
+		if v != ev.Offender {
+			validators = append(validators, v)
+		}
+	}
+	var weights map[string]int64
+	for addr, w := range curVali.Weights {
+		if addr == ev.Offender {
+			continue
+		}
+		if weights == nil {
+			weights = make(map[string]int64)
+		}
+		weights[addr] = w
+	}
+	rawBytes, err := json.Marshal(&ProposerInfo{
+		Address: validators,
+		Weights: weights,
+	})
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	evBytes, err := ev.Marshal()
+	if err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if err := contractCtx.Put(x.election.bindContractBucket,
+		[]byte(fmt.Sprintf("%d_%s", x.election.consensusVersion, validateKeys)), rawBytes); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	if err := contractCtx.Put(x.election.bindContractBucket, evKey, evBytes); err != nil {
+		return common.NewContractErrResponse(common.StatusErr, err.Error()), err
+	}
+	x.log.Warn("consensus:xpoa:methodSubmitEvidence: validator removed", "offender", ev.Offender,
+		"type", ev.Type, "height", ev.Height, "validators", validators)
+	delta := contract.Limits{
+		XFee: fee,
+	}
+	contractCtx.AddResourceUsed(delta)
+	return common.NewContractOKResponse(rawBytes), nil
+}
+
+// getCurrentValidators 读取当前的候选人集合，未修改过时为初始候选人
+func (x *xpoaConsensus) getCurrentValidators(contractCtx contract.KContext) ([]string, error) {
+	info, err := x.getCurrentValidatorInfo(contractCtx)
+	if err != nil {
+		return nil, err
+	}
+	return info.Address, nil
+}
+
+// getCurrentValidatorInfo 读取当前的候选人集合及权重，未修改过时为初始候选人
+func (x *xpoaConsensus) getCurrentValidatorInfo(contractCtx contract.KContext) (*ProposerInfo, error) {
+	curValiBytes, err := contractCtx.Get(x.election.bindContractBucket,
+		[]byte(fmt.Sprintf("%d_%s", x.election.consensusVersion, validateKeys)))
+	if err != nil || curValiBytes == nil {
+		return x.election.initValidatorInfo(), nil
+	}
+	var curValiKey ProposerInfo
+	if err := json.Unmarshal(curValiBytes, &curValiKey); err != nil {
+		x.log.Error("Unmarshal error")
+		return nil, err
+	}
+	return &curValiKey, nil
+}
+
+// isAuthValidators 候选人等权时同isAuthAddress，否则要求贪心下签名候选人的投票权重之和>33%(Chained-BFT装载) or 50%(一般情况)
+func (x *xpoaConsensus) isAuthValidators(info *ProposerInfo, aks map[string]float64, threshold float64, enableBFT bool) bool {
+	if len(info.Weights) == 0 || len(info.Address) == 1 {
+		return x.isAuthAddress(info.Address, aks, threshold, enableBFT)
+	}
+	for addr := range aks {
+		if !Find(addr, info.Address) {
+			return false
+		}
+	}
+	var signed int64
+	for _, addr := range greedySigners(aks, threshold) {
+		signed += weightOf(info.Weights, addr)
+	}
+	total := totalWeight(info.Address, info.Weights)
+	if !enableBFT {
+		return 2*signed > total
+	}
+	return CalWeightFault(signed, total)
+}
+
 // isAuthAddress 判断输入aks是否能在贪心下仍能满足签名数量>33%(Chained-BFT装载) or 50%(一般情况)
 func (x *xpoaConsensus) isAuthAddress(validators []string, aks map[string]float64, threshold float64, enableBFT bool) bool {
 	// 0. 是否是单个候选人
@@ -121,6 +252,15 @@ func (x *xpoaConsensus) isAuthAddress(validators []string, aks map[string]float6
 		}
 	}
 	// 2. 判断贪心下签名集合数目仍满足要求
+	greedyCount := len(greedySigners(aks, threshold))
+	if !enableBFT {
+		return greedyCount >= len(validators)/2+1
+	}
+	return CalFault(int64(greedyCount), int64(len(validators)))
+}
+
+// greedySigners 按acl权重从大到小选取签名地址，返回刚好满足threshold所需的最少地址
+func greedySigners(aks map[string]float64, threshold float64) []string {
 	var s aksSlice
 	for k, v := range aks {
 		s = append(s, aksItem{
@@ -129,17 +269,14 @@ func (x *xpoaConsensus) isAuthAddress(validators []string, aks map[string]float6
 		})
 	}
 	sort.Stable(s)
-	greedyCount := 0
+	var signers []string
 	sum := threshold
-	for i := 0; i < len(aks); i++ {
+	for i := 0; i < len(s); i++ {
 		if sum <= 0 {
 			break
 		}
 		sum -= s[i].Weight
-		greedyCount++
+		signers = append(signers, s[i].Address)
 	}
-	if !enableBFT {
-		return greedyCount >= len(validators)/2+1
-	}
-	return CalFault(int64(greedyCount), int64(len(validators)))
+	return signers
 }
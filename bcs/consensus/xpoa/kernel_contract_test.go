@@ -3,7 +3,12 @@ package xpoa
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
+	bmock "github.com/xuperchain/xupercore/bcs/consensus/mock"
+	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
+	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
 	"github.com/xuperchain/xupercore/kernel/consensus/mock"
 )
 
@@ -58,6 +63,46 @@ func TestMethodEditValidates(t *testing.T) {
 	}
 }
 
+func TestMethodEditValidatesWeights(t *testing.T) {
+	cCtx, err := prepare(getXpoaConsensusConf())
+	if err != nil {
+		t.Fatal("prepare error", "error", err)
+	}
+	i := NewXpoaConsensus(*cCtx, getConfig(getXpoaConsensusConf()))
+	xpoa, ok := i.(*xpoaConsensus)
+	if !ok {
+		t.Fatal("transfer err.")
+	}
+	args := NewEditArgs()
+	args["validates"] = []byte("dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN;WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT")
+	args["weights"] = []byte(`{"dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN":3}`)
+	m := NewEditM()
+	if _, err := xpoa.methodEditValidates(mock.NewFakeKContext(args, m)); err != nil {
+		t.Fatal(err)
+	}
+	info, err := xpoa.getCurrentValidatorInfo(mock.NewFakeKContext(args, m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Address) != 2 || info.Weights["dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN"] != 3 {
+		t.Fatalf("weights should be saved, info:%v", info)
+	}
+	// 权重只能设置给候选人
+	args["weights"] = []byte(`{"akf7qunmeaqb51Wu418d6TyPKp4jdLdpV":3}`)
+	if _, err := xpoa.methodEditValidates(mock.NewFakeKContext(args, m)); err != weightErr {
+		t.Fatalf("invalid weights should be rejected, err:%v", err)
+	}
+	// 一个权重占3/4的候选人可以单独发起变更
+	weighted := map[string]float64{"dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN": 0.6}
+	if !xpoa.isAuthValidators(info, weighted, 0.6, false) {
+		t.Error("validator with 3/4 weight should be authorized")
+	}
+	weighted = map[string]float64{"WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT": 0.6}
+	if xpoa.isAuthValidators(info, weighted, 0.6, false) {
+		t.Error("validator with 1/4 weight should not be authorized")
+	}
+}
+
 func TestMethodGetValidates(t *testing.T) {
 	cCtx, err := prepare(getXpoaConsensusConf())
 	if err != nil {
@@ -110,3 +155,62 @@ func TestIsAuthAddress(t *testing.T) {
 		t.Error("isAuthAddress err.")
 	}
 }
+
+func NewEvidenceArgs(t *testing.T) map[string][]byte {
+	cc, addr, err := bmock.NewCryptoClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cCrypto.NewCBFTCrypto(addr, cc)
+	ts := time.Now().UnixNano() / int64(3*time.Second) * int64(3*time.Second)
+	ev := &evidence.Evidence{
+		Type:     evidence.TypeProposal,
+		Offender: addr.Address,
+		Height:   10,
+	}
+	for i, id := range []string{"p1", "p2"} {
+		msg, err := c.SignProposalMsg(&chainedBftPb.ProposalMsg{
+			ProposalView: 10,
+			ProposalId:   []byte(id),
+			Timestamp:    ts + int64(i),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ev.Proposals = append(ev.Proposals, msg)
+	}
+	buf, _ := ev.Marshal()
+	return map[string][]byte{"evidence": buf}
+}
+
+func TestMethodSubmitEvidence(t *testing.T) {
+	cCtx, err := prepare(getXpoaConsensusConf())
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := NewXpoaConsensus(*cCtx, getConfig(getXpoaConsensusConf()))
+	xpoa, ok := i.(*xpoaConsensus)
+	if !ok {
+		t.Fatal("transfer err.")
+	}
+	m := NewEditM()
+	args := NewEvidenceArgs(t)
+	if _, err := xpoa.methodSubmitEvidence(mock.NewFakeKContext(args, m)); err != nil {
+		t.Fatal(err)
+	}
+	validators, err := xpoa.getCurrentValidators(mock.NewFakeKContext(args, m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(validators) != 1 || validators[0] != "WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT" {
+		t.Fatalf("offender should be removed, validators:%v", validators)
+	}
+	if _, err := xpoa.methodSubmitEvidence(mock.NewFakeKContext(args, m)); err != repeatEvidenceErr {
+		t.Fatalf("repeat evidence should be rejected, err:%v", err)
+	}
+
+	args["evidence"] = []byte(`{"type":"proposal","offender":"dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN","height":10}`)
+	if _, err := xpoa.methodSubmitEvidence(mock.NewFakeKContext(args, NewEditM())); err != evidence.ErrInvalidEvidence {
+		t.Fatalf("invalid evidence should be rejected, err:%v", err)
+	}
+}
@@ -4,10 +4,15 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/xuperchain/xupercore/kernel/consensus/base/beacon"
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	chainedBft "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/liveness"
 	"github.com/xuperchain/xupercore/kernel/consensus/context"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/utils"
 )
 
 // xpoaSchedule 实现了ProposerElectionInterface接口，接口定义了validators操作
@@ -20,18 +25,28 @@ type xpoaSchedule struct {
 	blockNum int64
 	// 当前validators的address
 	validators []string
-	miner      string
+	// 当前validators的投票权重，为空时各validator等权
+	weights map[string]int64
+	miner   string
 	// 存储初始值
 	initValidators []string
+	initWeights    map[string]int64
 	startHeight    int64
 
 	enableBFT          bool
 	consensusName      string
 	consensusVersion   int64
 	bindContractBucket string
+	// 验证人活跃度统计
+	liveness *liveness.Tracker
+	// 开启VRF选举时的随机信标，未开启时为nil
+	beacon *beacon.Beacon
+	// 开启纪元重配置时计算区块所在的纪元，未开启时为nil
+	epoch *chainedBft.EpochTracker
 
 	log    logs.Logger
 	ledger cctx.LedgerRely
+	clock  cctx.Clock
 }
 
 func NewXpoaSchedule(xconfig *xpoaConfig, cCtx context.ConsensusCtx, startHeight, version int64) *xpoaSchedule {
@@ -45,11 +60,15 @@ func NewXpoaSchedule(xconfig *xpoaConfig, cCtx context.ConsensusCtx, startHeight
 		bindContractBucket: poaBucket,
 		ledger:             cCtx.Ledger,
 		log:                cCtx.XLog,
+		clock:              cCtx.GetClock(),
 	}
 	if xconfig.EnableBFT != nil {
 		s.enableBFT = true
 		s.consensusName = "xpoa"
 		s.bindContractBucket = xpoaBucket
+		if xconfig.EnableBFT[bftEpochKey] {
+			s.epoch = chainedBft.NewEpochTracker(startHeight, cCtx.Ledger, blockJustify)
+		}
 	}
 	// xpoaSchedule 实现了ProposerElectionInterface接口，接口定义了validators操作
 	// 重启时需要使用最新的validator数据，而不是initValidators数据
@@ -58,15 +77,24 @@ func NewXpoaSchedule(xconfig *xpoaConfig, cCtx context.ConsensusCtx, startHeight
 		validators = append(validators, v)
 	}
 	s.initValidators = validators
+	s.initWeights = xconfig.InitProposer.Weights
+	weights := s.initWeights
 	reader, _ := s.ledger.GetTipXMSnapshotReader()
 	res, err := reader.Get(s.bindContractBucket, []byte(fmt.Sprintf("%d_%s", s.consensusVersion, validateKeys)))
 	if err != nil {
 		return nil
 	}
-	if snapshotValidators, _ := loadValidatorsMultiInfo(res); snapshotValidators != nil {
-		validators = snapshotValidators
+	if info, _ := loadProposerInfo(res); info != nil && info.Address != nil {
+		validators = info.Address
+		weights = info.Weights
 	}
 	s.validators = validators
+	s.weights = weights
+	s.liveness = liveness.NewTracker(xconfig.Liveness, xconfig.Period, startHeight, cCtx.Ledger,
+		s.livenessSlot, s.blockValidators, cCtx.XLog)
+	if xconfig.EnableVRF {
+		s.beacon = beacon.NewBeacon(startHeight, cCtx.Ledger, cCtx.Crypto, cCtx.XLog)
+	}
 	return &s
 }
 
@@ -93,45 +121,175 @@ func (s *xpoaSchedule) GetLeader(round int64) string {
 	if b, err := s.ledger.QueryBlockHeaderByHeight(round); err == nil {
 		return string(b.GetProposer())
 	}
-	v := s.GetValidators(round)
-	if v == nil {
+	info := s.getRoundValidatorInfo(round)
+	if info == nil {
 		return ""
 	}
 	// 计算round对应的timestamp大致区间
-	nTime := time.Now().UnixNano()
-	if round > s.ledger.QueryTipBlockHeader().GetHeight() {
+	nTime := s.clock.Now().UnixNano()
+	tipBlock := s.ledger.QueryTipBlockHeader()
+	if round > tipBlock.GetHeight() {
 		nTime += s.period * int64(time.Millisecond)
 	}
-	_, pos, _ := s.minerScheduling(nTime, len(v))
-	return v[pos]
+	return s.getProposer(tipBlock.GetBlockid(), nTime, info.slots())
+}
+
+// schedule 返回当前validators按权重展开的出块顺序
+func (s *xpoaSchedule) schedule() []string {
+	return scheduleSlots(s.validators, s.weights)
+}
+
+// getProposer 返回parentId之后timestamp所在时间片实际的出块人，validators为按权重展开的出块顺序
+// 开启活跃度跳过策略或VRF选举时可能不是validators[pos]
+func (s *xpoaSchedule) getProposer(parentId []byte, timestamp int64, validators []string) string {
+	term, pos, blockPos := s.minerScheduling(timestamp, len(validators))
+	if pos < 0 || pos >= int64(len(validators)) {
+		return ""
+	}
+	if s.beacon != nil {
+		return s.beacon.Proposer(parentId, s.slotBegin(term, pos, len(validators)), validators)
+	}
+	if s.liveness == nil {
+		return validators[pos]
+	}
+	slot := liveness.Slot{Term: term, Pos: pos, BlockPos: blockPos, First: blockPos <= 1}
+	return s.liveness.Proposer(parentId, slot, validators)
+}
+
+// slotBegin 返回第term轮第pos个候选人轮值时间片的开始时间，单位为unixnano，与minerScheduling一致
+func (s *xpoaSchedule) slotBegin(term int64, pos int64, length int) int64 {
+	termTime := s.period * int64(length) * s.blockNum
+	posTime := s.period * s.blockNum
+	return ((term-1)*termTime + pos*posTime) * int64(time.Millisecond)
+}
+
+// livenessSlot 活跃度统计使用的时间片划分，与minerScheduling一致
+func (s *xpoaSchedule) livenessSlot(timestamp int64, validators []string) (liveness.Slot, bool) {
+	term, pos, blockPos := s.minerScheduling(timestamp, len(validators))
+	if blockPos < 1 || blockPos > s.blockNum || pos >= int64(len(validators)) {
+		return liveness.Slot{}, false
+	}
+	return liveness.Slot{Term: term, Pos: pos, BlockPos: blockPos, First: blockPos == 1}, true
+}
+
+// blockValidators 返回校验该区块时使用的验证人集合，按权重展开为出块顺序
+func (s *xpoaSchedule) blockValidators(block ledger.BlockHandle) ([]string, error) {
+	storage, _ := block.GetConsensusStorage()
+	info, err := s.getBlockValidatorInfo(block.GetHeight(), storage, block.GetPreHash())
+	if err != nil {
+		return nil, err
+	}
+	return info.slots(), nil
+}
+
+// getLiveness 返回当前验证人在tip区块及之前统计窗口内的出块情况
+func (s *xpoaSchedule) getLiveness() map[string]*liveness.Stat {
+	if s.liveness == nil {
+		return nil
+	}
+	stats, err := s.liveness.ValidatorStats(s.ledger.QueryTipBlockHeader().GetBlockid(), s.validators)
+	if err != nil {
+		s.log.Warn("Xpoa::getLiveness::calculate stats error.", "err", err)
+		return nil
+	}
+	return stats
 }
 
 // GetValidators 用于计算目标round候选人信息，同时更新schedule address到internet地址映射
 func (s *xpoaSchedule) GetValidators(round int64) []string {
+	info := s.getRoundValidatorInfo(round)
+	if info == nil {
+		return nil
+	}
+	return info.Address
+}
+
+// GetValidatorWeights 返回目标round候选人的投票权重，与GetValidators使用同一快照，为nil时各候选人等权
+func (s *xpoaSchedule) GetValidatorWeights(round int64) map[string]int64 {
+	info := s.getRoundValidatorInfo(round)
+	if info == nil {
+		return nil
+	}
+	return info.Weights
+}
+
+// getRoundValidatorInfo 返回目标round的候选人及权重
+func (s *xpoaSchedule) getRoundValidatorInfo(round int64) *ProposerInfo {
 	if round-1 <= 3 {
-		return s.initValidators
+		return s.initValidatorInfo()
 	}
 	block, err := s.ledger.QueryBlockHeaderByHeight(round)
-	var validators []string
+	var info *ProposerInfo
 	var calErr error
 	if err != nil {
 		// 尚未产生的区块，使用的是tipHeight-3的快照，tipHeight存在
-		validators, calErr = s.getValidates(round - 1)
+		info, calErr = s.getValidatorInfo(round - 1)
 	} else {
 		storage, _ := block.GetConsensusStorage()
-		validators, _ = s.GetLocalValidates(block.GetTimestamp(), round, storage)
+		info, calErr = s.getBlockValidatorInfo(round, storage, block.GetPreHash())
 	}
 	if calErr != nil {
 		return nil
 	}
-	return validators
+	return info
+}
+
+// initValidatorInfo 返回xuper.json中指定的初始候选人及权重
+func (s *xpoaSchedule) initValidatorInfo() *ProposerInfo {
+	return &ProposerInfo{
+		Address: s.initValidators,
+		Weights: s.initWeights,
+	}
 }
 
 // GetLocalValidates 用于收到一个新块时, 验证该块的时间戳和proposer是否能与本地计算结果匹配
 func (s *xpoaSchedule) GetLocalValidates(timestamp int64, round int64, storage []byte) ([]string, error) {
+	info, err := s.getLocalValidatorInfo(round, storage)
+	if err != nil {
+		return nil, err
+	}
+	return info.Address, nil
+}
+
+// getBlockValidatorInfo 返回校验父区块为preHash的round高度区块时使用的候选人及权重
+// 开启纪元重配置时由父区块所在的纪元决定，否则按高度推算
+func (s *xpoaSchedule) getBlockValidatorInfo(round int64, storage []byte, preHash []byte) (*ProposerInfo, error) {
+	if s.epoch != nil {
+		return s.getEpochValidatorInfo(preHash)
+	}
+	return s.getLocalValidatorInfo(round, storage)
+}
+
+// getJustifyValidates 开启纪元重配置时，返回校验justify签名使用的候选人，即justify指向区块的候选人
+func (s *xpoaSchedule) getJustifyValidates(proposalId []byte) ([]string, error) {
+	block, err := s.ledger.QueryBlockHeader(proposalId)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.getEpochValidatorInfo(block.GetPreHash())
+	if err != nil {
+		return nil, err
+	}
+	return info.Address, nil
+}
+
+// GetProposalValidators 返回为proposalId区块的QC签名的候选人，与CheckMinerMatch校验justify时使用相同的候选人
+func (s *xpoaSchedule) GetProposalValidators(proposalId []byte) ([]string, error) {
+	if s.epoch != nil {
+		return s.getJustifyValidates(proposalId)
+	}
+	block, err := s.ledger.QueryBlockHeader(proposalId)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetValidators(block.GetHeight()), nil
+}
+
+// getLocalValidatorInfo 返回校验round高度区块时使用的候选人及权重
+func (s *xpoaSchedule) getLocalValidatorInfo(round int64, storage []byte) (*ProposerInfo, error) {
 	targetHeight := round - 1
 	if targetHeight <= 3 {
-		return s.initValidators, nil
+		return s.initValidatorInfo(), nil
 	}
 	// ATTENTION: 获取候选人信息时，时刻注意拿取的是check目的round的前三个块，候选人变更是在3个块之后生效，即round-3
 	// 注意: 在competeMaster时，拿到的当前tipHeightMiner-3的快照生成的候选人集合，
@@ -148,78 +306,112 @@ func (s *xpoaSchedule) GetLocalValidates(timestamp int64, round int64, storage [
 		}
 	}
 	// 目前使用的是targetHeight，后面需要变为Blockid
-	localValidators, err := s.getValidates(targetHeight)
-	if err != nil || localValidators == nil {
+	info, err := s.getValidatorInfo(targetHeight)
+	if err != nil || info.Address == nil {
 		return nil, targetParamErr
 	}
-	return localValidators, nil
+	return info, nil
 }
 
-// GetLocalLeader 用于收到一个新块时, 验证该块的时间戳和proposer是否能与本地计算结果匹配
-func (s *xpoaSchedule) GetLocalLeader(timestamp int64, round int64, storage []byte) string {
-	localValidators, err := s.GetLocalValidates(timestamp, round, storage)
+// GetLocalLeader 用于收到一个新块时, 验证该块的时间戳和proposer是否能与本地计算结果匹配, preHash为该块的父区块
+func (s *xpoaSchedule) GetLocalLeader(timestamp int64, round int64, storage []byte, preHash []byte) string {
+	info, err := s.getBlockValidatorInfo(round, storage, preHash)
 	if err != nil {
 		return ""
 	}
-	_, pos, blockPos := s.minerScheduling(timestamp, len(localValidators))
-	if blockPos < 0 || blockPos > s.blockNum || pos >= int64(len(localValidators)) {
+	slots := info.slots()
+	_, pos, blockPos := s.minerScheduling(timestamp, len(slots))
+	if blockPos < 0 || blockPos > s.blockNum || pos >= int64(len(slots)) {
 		return ""
 	}
+	// 开启活跃度跳过策略时，按照父区块之前的统计确定该时间片实际的出块人
+	leader := s.getProposer(preHash, timestamp, slots)
 	s.log.Debug("xpoa schedule miner Scheduling", "pos", pos, "blockPos",
-		blockPos, "timestamp", timestamp, "validators", localValidators, "leader", localValidators[pos])
-	return localValidators[pos]
+		blockPos, "timestamp", timestamp, "validators", info.Address, "weights", info.Weights, "leader", leader)
+	return leader
 }
 
-// getValidatesByBlockId 根据当前输入blockid，用快照的方式在xmodel中寻找<=当前blockid的最新的候选人值，若无则使用xuper.json中指定的初始值
-func (s *xpoaSchedule) getValidatesByBlockId(blockId []byte) ([]string, error) {
+// getValidatorInfoByBlockId 根据当前输入blockid，用快照的方式在xmodel中寻找<=当前blockid的最新的候选人值及权重，若无则使用xuper.json中指定的初始值
+func (s *xpoaSchedule) getValidatorInfoByBlockId(blockId []byte) (*ProposerInfo, error) {
 	reader, err := s.ledger.CreateSnapshot(blockId)
 	if err != nil {
-		s.log.Error("Xpoa::getValidatesByBlockId::createSnapshot error.", "err", err)
+		s.log.Error("Xpoa::getValidatorInfoByBlockId::createSnapshot error.", "err", err)
 		return nil, err
 	}
 	res, err := reader.Get(s.bindContractBucket, []byte(fmt.Sprintf("%d_%s", s.consensusVersion, validateKeys)))
 	if err != nil {
-		s.log.Error("Xpoa::getValidatesByBlockId::reader Get error.", "err", err)
+		s.log.Error("Xpoa::getValidatorInfoByBlockId::reader Get error.", "err", err)
 		return nil, err
 	}
 	if res == nil || res.PureData == nil || res.PureData.Value == nil {
-		return s.initValidators, nil
+		return s.initValidatorInfo(), nil
 	}
-	validators, err := loadValidatorsMultiInfo(res.PureData.Value)
+	info, err := loadProposerInfo(res.PureData.Value)
 	if err != nil {
-		s.log.Error("Xpoa::getValidatesByBlockId::loadValidatorsMultiInfo error.", "err", err)
+		s.log.Error("Xpoa::getValidatorInfoByBlockId::loadProposerInfo error.", "err", err)
 		return nil, err
 	}
-	s.log.Debug("xpoaSchedule getValidatesByBlockId result", "validators", validators)
-	return validators, nil
+	s.log.Debug("xpoaSchedule getValidatorInfoByBlockId result", "validators", info.Address, "weights", info.Weights)
+	return info, nil
+}
+
+// getEpochValidatorInfo 返回父区块为parentId的区块使用的候选人及权重，即其纪元区块快照中的值
+// 包含变更的区块被旧集合的QC提交之前，纪元区块不会越过该区块，因此新集合不会提前生效
+func (s *xpoaSchedule) getEpochValidatorInfo(parentId []byte) (*ProposerInfo, error) {
+	blockId, err := s.epoch.EpochBlock(parentId)
+	if err != nil {
+		s.log.Error("Xpoa::getEpochValidatorInfo::EpochBlock error.", "err", err, "parentId", utils.F(parentId))
+		return nil, err
+	}
+	return s.getValidatorInfoByBlockId(blockId)
 }
 
 func (s *xpoaSchedule) getValidates(height int64) ([]string, error) {
+	info, err := s.getValidatorInfo(height)
+	if err != nil {
+		return nil, err
+	}
+	return info.Address, nil
+}
+
+// getValidatorInfo 返回height生效的候选人及权重，权重与候选人存储在同一快照中，随高度一同回溯
+func (s *xpoaSchedule) getValidatorInfo(height int64) (*ProposerInfo, error) {
+	// 开启纪元重配置时，返回以height区块为父区块的区块使用的候选人
+	if s.epoch != nil {
+		b, err := s.ledger.QueryBlockHeaderByHeight(height)
+		if err != nil {
+			s.log.Error("Xpoa::getValidatorInfo::QueryBlockByHeight error.", "err", err, "height", height)
+			return nil, err
+		}
+		return s.getEpochValidatorInfo(b.GetBlockid())
+	}
 	if height < s.startHeight+3 {
-		return s.initValidators, nil
+		return s.initValidatorInfo(), nil
 	}
 	// xpoa的validators变更在包含变更tx的block的后3个块后生效, 即当B0包含了变更tx，在B3时validators才正式统一变更
 	b, err := s.ledger.QueryBlockHeaderByHeight(height - 3)
 	if err != nil {
-		s.log.Error("Xpoa::getValidates::QueryBlockByHeight error.", "err", err, "height", height-3)
+		s.log.Error("Xpoa::getValidatorInfo::QueryBlockByHeight error.", "err", err, "height", height-3)
 		return nil, err
 	}
-	validators, err := s.getValidatesByBlockId(b.GetBlockid())
+	info, err := s.getValidatorInfoByBlockId(b.GetBlockid())
 	if err != nil {
-		s.log.Error("Xpoa::getValidates::getValidatesByBlockId error.", "err", err)
+		s.log.Error("Xpoa::getValidatorInfo::getValidatorInfoByBlockId error.", "err", err)
 		return nil, err
 	}
-	return validators, nil
+	return info, nil
 }
 
 func (s *xpoaSchedule) UpdateValidator(height int64) bool {
-	validators, err := s.getValidates(height)
-	if err != nil || len(validators) == 0 {
+	info, err := s.getValidatorInfo(height)
+	if err != nil || len(info.Address) == 0 {
 		return false
 	}
-	if !common.AddressEqual(validators, s.validators) {
-		s.log.Debug("Xpoa::UpdateValidator", "new validators", validators, "s.validators", s.validators)
-		s.validators = validators
+	if !common.AddressEqual(info.Address, s.validators) || !weightsEqual(info.Weights, s.weights) {
+		s.log.Debug("Xpoa::UpdateValidator", "new validators", info.Address, "new weights", info.Weights,
+			"s.validators", s.validators, "s.weights", s.weights)
+		s.validators = info.Address
+		s.weights = info.Weights
 		return true
 	}
 	return false
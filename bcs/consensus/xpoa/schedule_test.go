@@ -8,7 +8,9 @@ import (
 
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	chainedBft "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft"
 	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
+	"github.com/xuperchain/xupercore/kernel/ledger"
 )
 
 var (
@@ -27,6 +29,7 @@ func NewSchedule(address string, validators []string, enableBFT bool) (*xpoaSche
 		enableBFT:      enableBFT,
 		ledger:         c.Ledger,
 		log:            c.XLog,
+		clock:          c.GetClock(),
 	}, err
 }
 
@@ -96,3 +99,106 @@ func TestGetValidates(t *testing.T) {
 		t.Error("AddressEqual error1.", "v", v)
 	}
 }
+
+func TestGetValidatorWeights(t *testing.T) {
+	s, err := NewSchedule("dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN", InitValidators, true)
+	if err != nil {
+		t.Fatal("newSchedule error.")
+	}
+	l, _ := s.ledger.(*kmock.FakeLedger)
+	for i := 3; i <= 6; i++ {
+		l.Put(kmock.NewBlock(i))
+	}
+	rawBytes, _ := json.Marshal(&ProposerInfo{
+		Address: newValidators,
+		Weights: map[string]int64{newValidators[0]: 2},
+	})
+	l.SetSnapshot(poaBucket, []byte(fmt.Sprintf("0_%s", validateKeys)), rawBytes)
+	if w := s.GetValidatorWeights(3); w != nil {
+		t.Errorf("init validators should have no weights, weights:%v", w)
+	}
+	if w := s.GetValidatorWeights(7); w[newValidators[0]] != 2 {
+		t.Errorf("weights should be read from snapshot, weights:%v", w)
+	}
+	if !s.UpdateValidator(6) || s.weights[newValidators[0]] != 2 {
+		t.Fatalf("UpdateValidator should update weights, weights:%v", s.weights)
+	}
+	// 按权重展开后每轮4个时间片，第一个候选人占2个
+	slots := s.schedule()
+	if len(slots) != 4 || slots[0] != newValidators[0] {
+		t.Errorf("schedule should follow weights, slots:%v", slots)
+	}
+}
+
+// epochLedger 按区块返回快照，changeHeight及之后的区块快照中包含变更后的候选人
+type epochLedger struct {
+	*kmock.FakeLedger
+	changeHeight int64
+}
+
+func (l *epochLedger) CreateSnapshot(blkId []byte) (ledger.XMReader, error) {
+	b, err := l.QueryBlockHeader(blkId)
+	if err != nil {
+		return nil, err
+	}
+	if b.GetHeight() < l.changeHeight {
+		reader := kmock.NewFakeXMReader()
+		return &reader, nil
+	}
+	return l.FakeLedger.CreateSnapshot(blkId)
+}
+
+// newEpochSchedule 创建开启纪元重配置的schedule，区块3~10的justify均指向父区块，候选人变更包含在区块4中
+func newEpochSchedule(t *testing.T) (*xpoaSchedule, *kmock.FakeLedger) {
+	s, err := NewSchedule("dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN", InitValidators, true)
+	if err != nil {
+		t.Fatal("newSchedule error.")
+	}
+	l, _ := s.ledger.(*kmock.FakeLedger)
+	for i := 3; i <= 10; i++ {
+		l.Put(kmock.NewBlock(i))
+	}
+	for i := 2; i <= 10; i++ {
+		l.SetConsensusStorage(i, SetXpoaStorage(1, justify(int64(i-1))))
+	}
+	l.SetSnapshot(poaBucket, []byte(fmt.Sprintf("0_%s", validateKeys)), ValidateKey1())
+	s.ledger = &epochLedger{FakeLedger: l, changeHeight: 4}
+	s.epoch = chainedBft.NewEpochTracker(1, s.ledger, blockJustify)
+	return s, l
+}
+
+func TestEpochValidators(t *testing.T) {
+	s, _ := newEpochSchedule(t)
+	// 区块4在区块7的justify形成三链后提交，父区块为7的区块8开始使用新的候选人
+	if v, _ := s.getValidates(6); !common.AddressEqual(v, InitValidators) {
+		t.Errorf("validators should not change before block 4 committed, validators:%v", v)
+	}
+	if v, _ := s.getValidates(7); !common.AddressEqual(v, newValidators) {
+		t.Errorf("validators should change after block 4 committed, validators:%v", v)
+	}
+	b8, _ := s.ledger.QueryBlockHeaderByHeight(8)
+	if v := s.GetLocalLeader(b8.GetTimestamp(), 8, nil, b8.GetPreHash()); !Find(v, newValidators) {
+		t.Errorf("unexpected leader %s", v)
+	}
+	// justify由其指向区块所在纪元的候选人签名，区块7仍由旧候选人投票
+	if v, _ := s.getJustifyValidates([]byte{7}); !common.AddressEqual(v, InitValidators) {
+		t.Errorf("justify of block 7 should be signed by old validators, validators:%v", v)
+	}
+	if v, _ := s.getJustifyValidates([]byte{8}); !common.AddressEqual(v, newValidators) {
+		t.Errorf("justify of block 8 should be signed by new validators, validators:%v", v)
+	}
+}
+
+func TestEpochValidatorsWaitCommit(t *testing.T) {
+	s, l := newEpochSchedule(t)
+	// 区块6回滚重做，justify指向区块4而不是父区块，三链在区块6处断开，区块4需等待区块9的justify才能提交
+	l.SetConsensusStorage(6, SetXpoaStorage(1, justify(4)))
+	for h := int64(6); h <= 8; h++ {
+		if v, _ := s.getValidates(h); !common.AddressEqual(v, InitValidators) {
+			t.Errorf("validators after block %d should not change, validators:%v", h, v)
+		}
+	}
+	if v, _ := s.getValidates(9); !common.AddressEqual(v, newValidators) {
+		t.Errorf("validators after block 9 should change, validators:%v", v)
+	}
+}
@@ -2,12 +2,19 @@ package xpoa
 
 import (
 	"encoding/json"
-	"time"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	chainedBft "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/liveness"
 )
 
 type ValidatorsInfo struct {
 	Validators []string `json:"validators"`
-	Miner      string   `json:"miner"`
+	// 各验证人的投票权重，为空时各验证人等权
+	Weights map[string]int64 `json:"weights,omitempty"`
+	Miner   string           `json:"miner"`
+	// 各验证人最近的出块情况
+	Liveness map[string]*liveness.Stat `json:"liveness,omitempty"`
 }
 
 // xpoaStatus 实现了ConsensusStatus接口
@@ -17,6 +24,8 @@ type XpoaStatus struct {
 	StartHeight int64 `json:"startHeight"`
 	Index       int   `json:"index"`
 	election    *xpoaSchedule
+	// 开启chained-bft时的状态机，用于查询commitQC
+	smr *chainedBft.Smr
 }
 
 // 获取共识版本号
@@ -41,7 +50,7 @@ func (x *XpoaStatus) GetConsensusName() string {
 
 // 获取当前状态机term
 func (x *XpoaStatus) GetCurrentTerm() int64 {
-	term, _, _ := x.election.minerScheduling(time.Now().UnixNano(), len(x.election.validators))
+	term, _, _ := x.election.minerScheduling(x.election.clock.Now().UnixNano(), len(x.election.schedule()))
 	return term
 }
 
@@ -49,8 +58,24 @@ func (x *XpoaStatus) GetCurrentTerm() int64 {
 func (x *XpoaStatus) GetCurrentValidatorsInfo() []byte {
 	i := ValidatorsInfo{
 		Validators: x.election.validators,
+		Weights:    x.election.weights,
 		Miner:      x.election.miner,
+		Liveness:   x.election.getLiveness(),
 	}
 	b, _ := json.Marshal(i)
 	return b
 }
+
+// GetFinalizedBlock 开启chained-bft时commitQC对应的区块不可逆
+// 未开启时按账本的不可逆滑动窗口计算，未配置滑动窗口时以全部验证人各出blockNum个块的一整轮作为窗口
+func (x *XpoaStatus) GetFinalizedBlock() (int64, []byte) {
+	if x.smr != nil {
+		qc := x.smr.GetCommitQC()
+		return qc.GetProposalView(), qc.GetProposalId()
+	}
+	window := common.SlideWindow(x.election.ledger)
+	if window <= 0 {
+		window = int64(len(x.election.validators)) * x.election.blockNum
+	}
+	return common.FinalizedByDepth(x.election.ledger, window)
+}
@@ -1,8 +1,11 @@
 package xpoa
 
 import (
+	"bytes"
 	"encoding/json"
 	"testing"
+
+	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
 )
 
 func TestGetCurrentValidatorsInfo(t *testing.T) {
@@ -23,3 +26,54 @@ func TestGetCurrentValidatorsInfo(t *testing.T) {
 		t.Error("GetCurrentValidatorsInfo error", "error", err)
 	}
 }
+
+// slideWindowLedger 配置了不可逆滑动窗口的账本
+type slideWindowLedger struct {
+	*kmock.FakeLedger
+	window int64
+}
+
+func (l *slideWindowLedger) GetIrreversibleSlideWindow() int64 {
+	return l.window
+}
+
+func TestGetFinalizedBlock(t *testing.T) {
+	cCtx, err := prepare(getXpoaConsensusConf())
+	if err != nil {
+		t.Fatal("prepare error", "error", err)
+	}
+	l, _ := cCtx.Ledger.(*kmock.FakeLedger)
+	for i := 3; i <= 30; i++ {
+		l.Put(kmock.NewBlock(i))
+	}
+	i := NewXpoaConsensus(*cCtx, getConfig(getXpoaConsensusConf()))
+	status, _ := i.(*xpoaConsensus).GetConsensusStatus()
+	// 未配置滑动窗口时以一整轮(2个验证人各10个块)作为窗口
+	height, blockid := status.GetFinalizedBlock()
+	b, _ := l.QueryBlockHeaderByHeight(10)
+	if height != 10 || !bytes.Equal(blockid, b.GetBlockid()) {
+		t.Errorf("GetFinalizedBlock by rotation want 10, got %d", height)
+	}
+
+	cCtx.Ledger = &slideWindowLedger{FakeLedger: l, window: 5}
+	i = NewXpoaConsensus(*cCtx, getConfig(getXpoaConsensusConf()))
+	status, _ = i.(*xpoaConsensus).GetConsensusStatus()
+	if height, _ := status.GetFinalizedBlock(); height != 25 {
+		t.Errorf("GetFinalizedBlock by slide window want 25, got %d", height)
+	}
+}
+
+func TestGetFinalizedBlockBFT(t *testing.T) {
+	cCtx, err := prepare(getBFTXpoaConsensusConf())
+	if err != nil {
+		t.Fatal("prepare error", "error", err)
+	}
+	i := NewXpoaConsensus(*cCtx, getConfig(getBFTXpoaConsensusConf()))
+	xpoa, _ := i.(*xpoaConsensus)
+	xpoa.initBFT()
+	height, blockid := xpoa.status.GetFinalizedBlock()
+	qc := xpoa.smr.GetCommitQC()
+	if height != qc.GetProposalView() || !bytes.Equal(blockid, qc.GetProposalId()) {
+		t.Errorf("GetFinalizedBlock want commitQC %d, got %d", qc.GetProposalView(), height)
+	}
+}
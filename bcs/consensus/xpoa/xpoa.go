@@ -12,6 +12,7 @@ import (
 	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
 	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
 	quorumcert "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/kernel/consensus/def"
 	"github.com/xuperchain/xupercore/kernel/contract"
@@ -34,6 +35,7 @@ type xpoaConsensus struct {
 	status        *XpoaStatus
 	contract      contract.Manager
 	kMethod       map[string]contract.KernMethod
+	evidence      *evidence.Pool
 	log           logs.Logger
 }
 
@@ -72,6 +74,16 @@ func NewXpoaConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consensu
 		return nil
 	}
 
+	if err := checkWeights(xconfig.InitProposer.Address, xconfig.InitProposer.Weights); err != nil {
+		cCtx.XLog.Error("consensus:xpoa:NewXpoaConsensus: config init_proposer.weights error", "error", err)
+		return nil
+	}
+
+	if xconfig.EnableVRF && xconfig.Liveness != nil && xconfig.Liveness.MissThreshold > 0 {
+		cCtx.XLog.Error("consensus:xpoa:NewXpoaConsensus: config error", "error", vrfConfigErr)
+		return nil
+	}
+
 	version, err := ParseVersion(cCfg.Config)
 	if err != nil {
 		cCtx.XLog.Error("consensus:xpoa:NewXpoaConsensus: version error", "error", err)
@@ -101,21 +113,23 @@ func NewXpoaConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consensu
 		election:      schedule,
 		isProduce:     make(map[int64]bool),
 		config:        xconfig,
-		initTimestamp: time.Now().UnixNano(),
+		initTimestamp: schedule.clock.Now().UnixNano(),
 		status:        status,
 		contract:      cCtx.Contract,
+		evidence:      evidence.NewPool(cCtx.Crypto, xconfig.Period, cCtx.XLog),
 		log:           cCtx.XLog,
 	}
 
 	xpoaKMethods := map[string]contract.KernMethod{
-		contractEditValidate: xpoa.methodEditValidates,
-		contractGetValidates: xpoa.methodGetValidates,
+		contractEditValidate:   xpoa.methodEditValidates,
+		contractGetValidates:   xpoa.methodGetValidates,
+		contractSubmitEvidence: xpoa.methodSubmitEvidence,
 	}
 
 	xpoa.kMethod = xpoaKMethods
 
 	// 凡属于共识升级的逻辑，新建的Xpoa实例将直接将当前值置为true，原因是上一共识模块已经在当前值生成了高度为trigger height的区块，新的实例会再生成一边
-	timeKey := time.Now().Sub(time.Unix(0, 0)).Milliseconds() / xpoa.config.Period
+	timeKey := schedule.clock.Now().Sub(time.Unix(0, 0)).Milliseconds() / xpoa.config.Period
 	xpoa.isProduce[timeKey] = true
 
 	cCtx.XLog.Debug("consensus:xpoa:NewXpoaConsensus: create a poa instance successfully!")
@@ -125,7 +139,14 @@ func NewXpoaConsensus(cCtx cctx.ConsensusCtx, cCfg def.ConsensusConfig) consensu
 func (x *xpoaConsensus) initBFT() error {
 	// create smr/ chained-bft实例, 需要新建CBFTCrypto、pacemaker和saftyrules实例
 	cryptoClient := cCrypto.NewCBFTCrypto(x.cCtx.Address, x.cCtx.Crypto)
-	qcTree := quorumcert.InitQCTree(x.status.StartHeight, x.cCtx.Ledger, x.cCtx.XLog)
+	var qcTree *quorumcert.QCPendingTree
+	var justifies []quorumcert.QuorumCertInterface
+	if x.election.epoch != nil {
+		// 开启纪元重配置时，根据最近区块中的justify重建QC树，root为已提交的区块
+		qcTree, justifies = x.election.epoch.RebuildQCTree(x.cCtx.XLog)
+	} else {
+		qcTree = quorumcert.InitQCTree(x.status.StartHeight, x.cCtx.Ledger, x.cCtx.XLog)
+	}
 	if qcTree == nil {
 		x.log.Error("consensus:xpoa:NewXpoaConsensus: init QCTree err", "startHeight", x.status.StartHeight)
 		return nil
@@ -135,17 +156,27 @@ func (x *xpoaConsensus) initBFT() error {
 	}
 	// 重启状态检查1，pacemaker需要重置
 	tipHeight := x.cCtx.Ledger.QueryTipBlockHeader().GetHeight()
-	if !bytes.Equal(qcTree.GetGenesisQC().In.GetProposalId(), qcTree.GetRootQC().In.GetProposalId()) {
+	if x.election.epoch != nil {
+		if tipHeight > x.status.StartHeight {
+			pacemaker.CurrentView = qcTree.GetHighQC().In.GetProposalView()
+		}
+	} else if !bytes.Equal(qcTree.GetGenesisQC().In.GetProposalId(), qcTree.GetRootQC().In.GetProposalId()) {
 		pacemaker.CurrentView = tipHeight - 1
 	}
 	saftyrules := &chainedBft.DefaultSaftyRules{
-		Crypto: cryptoClient,
-		QcTree: qcTree,
-		Log:    x.cCtx.XLog,
+		Crypto:  cryptoClient,
+		QcTree:  qcTree,
+		Weights: x.election.GetValidatorWeights,
+		Log:     x.cCtx.XLog,
 	}
 	smr := chainedBft.NewSmr(x.cCtx.BcName, x.election.address, x.log, x.cCtx.Network, cryptoClient, pacemaker, saftyrules, x.election, qcTree)
+	smr.SetEvidencePool(x.evidence)
 	// 重启状态检查2，重做tipBlock，此时需重装载justify签名
-	if !bytes.Equal(qcTree.GetGenesisQC().In.GetProposalId(), qcTree.GetRootQC().In.GetProposalId()) {
+	if x.election.epoch != nil {
+		for _, justify := range justifies {
+			smr.LoadVotes(justify.GetProposalId(), justify.GetSignsInfo())
+		}
+	} else if !bytes.Equal(qcTree.GetGenesisQC().In.GetProposalId(), qcTree.GetRootQC().In.GetProposalId()) {
 		for i := int64(0); i < 3; i++ {
 			b, err := x.cCtx.Ledger.QueryBlockHeaderByHeight(tipHeight - i)
 			if err != nil {
@@ -155,14 +186,19 @@ func (x *xpoaConsensus) initBFT() error {
 		}
 	}
 	x.smr = smr
+	x.status.smr = smr
 	x.smr.Start()
+	// 新加入或重启的验证人从其他验证人处获取账本中尚未包含的最新QC
+	if x.election.epoch != nil {
+		x.smr.SyncQC(x.election.GetValidators(tipHeight + 1))
+	}
 	return nil
 }
 
 // CompeteMaster 返回是否为矿工以及是否需要进行SyncBlock
 func (x *xpoaConsensus) CompeteMaster(height int64) (bool, bool, error) {
 Again:
-	t := time.Now().UnixNano() / int64(time.Millisecond)
+	t := x.election.clock.Now().UnixNano() / int64(time.Millisecond)
 	key := t / x.election.period
 	sleep := x.election.period - t%x.election.period
 	if sleep > MAXSLEEPTIME {
@@ -172,9 +208,9 @@ Again:
 	if !ok || !v {
 		x.isProduce[key] = true
 	} else {
-		time.Sleep(time.Duration(sleep) * time.Millisecond)
+		x.election.clock.Sleep(time.Duration(sleep) * time.Millisecond)
 		// 定期清理isProduce
-		common.CleanProduceMap(x.isProduce, x.election.period)
+		common.CleanProduceMap(x.isProduce, x.election.period, x.election.clock.Now())
 		goto Again
 	}
 
@@ -182,13 +218,19 @@ Again:
 	tipBlock := x.election.ledger.GetTipBlock()
 	if x.election.UpdateValidator(tipBlock.GetHeight()) {
 		x.log.Debug("consensus:xpoa:CompeteMaster: change validators", "valisators", x.election.validators)
+		// 进入新纪元时，新加入的验证人从其他验证人处获取最新的QC
+		if x.election.epoch != nil && x.smr != nil && Find(x.election.address, x.election.validators) {
+			x.smr.SyncQC(x.election.validators)
+		}
 	}
-	_, pos, blockPos := x.election.minerScheduling(time.Now().UnixNano(), len(x.election.validators))
-	if blockPos > x.election.blockNum || pos >= int64(len(x.election.validators)) {
+	now := x.election.clock.Now().UnixNano()
+	slots := x.election.schedule()
+	_, pos, blockPos := x.election.minerScheduling(now, len(slots))
+	if blockPos > x.election.blockNum || pos >= int64(len(slots)) {
 		x.log.Debug("consensus:xpoa:CompeteMaster: minerScheduling err", "pos", pos, "blockPos", blockPos)
 		goto Again
 	}
-	x.election.miner = x.election.validators[pos]
+	x.election.miner = x.election.getProposer(tipBlock.GetBlockid(), now, slots)
 	if x.election.miner == x.election.address {
 		x.log.Debug("consensus:xpoa:CompeteMaster", "isMiner", true, "height", tipBlock.GetHeight())
 		needSync := tipBlock.GetHeight() == 0 || string(tipBlock.GetProposer()) != x.election.miner
@@ -208,12 +250,30 @@ func (x *xpoaConsensus) CheckMinerMatch(ctx xcontext.XContext, block cctx.BlockI
 	// 获取block中共识专有存储, 检查justify是否符合要求
 	conStoreBytes, _ := block.GetConsensusStorage()
 	// 验证矿工身份
-	proposer := x.election.GetLocalLeader(block.GetTimestamp(), block.GetHeight(), conStoreBytes)
+	proposer := x.election.GetLocalLeader(block.GetTimestamp(), block.GetHeight(), conStoreBytes, block.GetPreHash())
 	if proposer != string(block.GetProposer()) {
 		ctx.GetLog().Error("consensus:xpoa:CheckMinerMatch: calculate proposer error", "logid", ctx.GetLog().GetLogId(), "want", proposer,
 			"have", string(block.GetProposer()), "blockId", utils.F(block.GetBlockid()))
 		return false, MinerSelectErr
 	}
+	// 开启VRF选举时校验出块人的VRF证明，该证明决定了后续时间片的出块人
+	if x.election.beacon != nil {
+		if err := x.election.beacon.Verify(block); err != nil {
+			ctx.GetLog().Error("consensus:xpoa:CheckMinerMatch: verify vrf proof error", "logid", ctx.GetLog().GetLogId(), "err", err,
+				"blockId", utils.F(block.GetBlockid()))
+			return false, err
+		}
+	}
+	// 校验区块记录的活跃度统计，该统计决定了后续时间片的出块人
+	if x.election.liveness != nil {
+		if err := x.election.liveness.Verify(block); err != nil {
+			ctx.GetLog().Error("consensus:xpoa:CheckMinerMatch: verify liveness counts error", "logid", ctx.GetLog().GetLogId(), "err", err,
+				"blockId", utils.F(block.GetBlockid()))
+			return false, err
+		}
+	}
+	// 记录区块，同一矿工在同一时间片生产了不同区块时生成双签证据
+	x.evidence.AddBlock(block)
 	if !x.election.enableBFT {
 		return true, nil
 	}
@@ -228,9 +288,15 @@ func (x *xpoaConsensus) CheckMinerMatch(ctx xcontext.XContext, block cctx.BlockI
 			"blockId", utils.F(block.GetBlockid()))
 		return false, err
 	}
-	preBlock, _ := x.election.ledger.QueryBlockHeader(block.GetPreHash())
-	preConStoreBytes, _ := preBlock.GetConsensusStorage()
-	validators, _ := x.election.GetLocalValidates(preBlock.GetTimestamp(), justify.GetProposalView(), preConStoreBytes)
+	var validators []string
+	if x.election.epoch != nil {
+		// justify由其指向区块所在纪元的验证人签名
+		validators, _ = x.election.getJustifyValidates(justify.GetProposalId())
+	} else {
+		preBlock, _ := x.election.ledger.QueryBlockHeader(block.GetPreHash())
+		preConStoreBytes, _ := preBlock.GetConsensusStorage()
+		validators, _ = x.election.GetLocalValidates(preBlock.GetTimestamp(), justify.GetProposalView(), preConStoreBytes)
+	}
 
 	// 包装成统一入口访问smr
 	err = x.smr.CheckProposal(block, justify, validators)
@@ -245,11 +311,22 @@ func (x *xpoaConsensus) CheckMinerMatch(ctx xcontext.XContext, block cctx.BlockI
 
 // ProcessBeforeMiner 开始挖矿前进行相应的处理, 返回truncate目标(如需裁剪), 返回写consensusStorage, 返回err
 func (x *xpoaConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, []byte, error) {
+	tipBlock := x.election.ledger.GetTipBlock()
 	if !x.election.enableBFT {
-		return nil, nil, nil
+		storage := common.ConsensusStorage{}
+		if err := x.recordLiveness(&storage, tipBlock.GetBlockid()); err != nil {
+			return nil, nil, err
+		}
+		if err := x.proveVrf(&storage, tipBlock.GetBlockid()); err != nil {
+			return nil, nil, err
+		}
+		if len(storage.Liveness) == 0 && storage.VrfProof == nil {
+			return nil, nil, nil
+		}
+		bytes, err := json.Marshal(storage)
+		return nil, bytes, err
 	}
 	// 即本地smr的HightQC和账本TipId不相等，tipId尚未收集到足够签名，回滚到本地HighQC，重做区块
-	tipBlock := x.election.ledger.GetTipBlock()
 	// smr返回一个裁剪目标，供miner模块直接回滚并出块
 	truncate, qc, err := x.smr.ResetProposerStatus(tipBlock, x.election.ledger.QueryBlockHeader, x.election.validators)
 	if err != nil {
@@ -257,7 +334,8 @@ func (x *xpoaConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, []b
 	}
 	// 候选人组仅一个时无需操作
 	if qc == nil {
-		return nil, nil, nil
+		bytes, err := x.vrfStorage(tipBlock.GetBlockid())
+		return nil, bytes, err
 	}
 
 	qcQuorumCert, _ := qc.(*quorumcert.QuorumCert)
@@ -270,13 +348,66 @@ func (x *xpoaConsensus) ProcessBeforeMiner(height, timestamp int64) ([]byte, []b
 		x.log.Warn("consensus:xpoa:ProcessBeforeMiner: last block not confirmed, walk to previous block",
 			"target", utils.F(qc.GetProposalId()), "ledger", tipBlock.GetHeight())
 		storage.TargetBits = int32(tipBlock.GetHeight())
+		// 回滚后父区块发生变化，VRF证明和活跃度统计需要基于新的父区块计算
+		if err := x.recordLiveness(&storage, qc.GetProposalId()); err != nil {
+			return nil, nil, err
+		}
+		if err := x.proveVrf(&storage, qc.GetProposalId()); err != nil {
+			return nil, nil, err
+		}
 		bytes, _ := json.Marshal(storage)
 		return qc.GetProposalId(), bytes, nil
 	}
+	if err := x.recordLiveness(&storage, tipBlock.GetBlockid()); err != nil {
+		return nil, nil, err
+	}
+	if err := x.proveVrf(&storage, tipBlock.GetBlockid()); err != nil {
+		return nil, nil, err
+	}
 	bytes, _ := json.Marshal(storage)
 	return nil, bytes, nil
 }
 
+// vrfStorage 无需QC时，仅包含VRF证明的共识存储，未开启VRF选举时为nil
+func (x *xpoaConsensus) vrfStorage(parentId []byte) ([]byte, error) {
+	if x.election.beacon == nil {
+		return nil, nil
+	}
+	storage := common.ConsensusStorage{}
+	if err := x.proveVrf(&storage, parentId); err != nil {
+		return nil, err
+	}
+	return json.Marshal(storage)
+}
+
+// recordLiveness 将以parentId为结尾的活跃度统计写入storage，后续区块在此基础上统计
+func (x *xpoaConsensus) recordLiveness(storage *common.ConsensusStorage, parentId []byte) error {
+	if x.election.liveness == nil {
+		return nil
+	}
+	counts, err := x.election.liveness.Counts(parentId)
+	if err != nil {
+		x.log.Error("consensus:xpoa:ProcessBeforeMiner: calculate liveness counts error", "err", err)
+		return err
+	}
+	storage.Liveness = counts
+	return nil
+}
+
+// proveVrf 开启VRF选举时，为parentId之后的新区块计算VRF证明并写入storage
+func (x *xpoaConsensus) proveVrf(storage *common.ConsensusStorage, parentId []byte) error {
+	if x.election.beacon == nil {
+		return nil
+	}
+	proof, err := x.election.beacon.Prove(x.cCtx.Address.PrivateKey, parentId)
+	if err != nil {
+		x.log.Error("consensus:xpoa:ProcessBeforeMiner: calculate vrf proof error", "err", err)
+		return err
+	}
+	storage.VrfProof = proof
+	return nil
+}
+
 // ProcessConfirmBlock 用于确认块后进行相应的处理
 func (x *xpoaConsensus) ProcessConfirmBlock(block cctx.BlockInterface) error {
 	if !x.election.enableBFT {
@@ -299,15 +430,17 @@ func (x *xpoaConsensus) ProcessConfirmBlock(block cctx.BlockInterface) error {
 	}
 
 	// 查看本地是否是最新round的生产者
-	_, pos, blockPos := x.election.minerScheduling(block.GetTimestamp(), len(x.election.validators))
-	if blockPos > x.election.blockNum || pos >= int64(len(x.election.validators)) {
+	slots := x.election.schedule()
+	_, pos, blockPos := x.election.minerScheduling(block.GetTimestamp(), len(slots))
+	if blockPos > x.election.blockNum || pos >= int64(len(slots)) {
 		x.log.Debug("consensus:xpoa:smr::ProcessConfirmBlock: minerScheduling overflow.")
 		return scheduleErr
 	}
 
 	var minerValidator []string
 	// 如果是当前矿工，则发送Proposal消息
-	if x.election.validators[pos] == x.election.address && string(block.GetProposer()) == x.election.address {
+	if string(block.GetProposer()) == x.election.address &&
+		x.election.getProposer(block.GetPreHash(), block.GetTimestamp(), slots) == x.election.address {
 		minerValidator = x.election.GetValidators(block.GetHeight() + 1)
 	}
 
@@ -8,6 +8,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	bmock "github.com/xuperchain/xupercore/bcs/consensus/mock"
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/kernel/consensus/def"
 	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
@@ -223,3 +224,92 @@ func TestBFT(t *testing.T) {
 		return
 	}
 }
+
+func getVRFXpoaConsensusConf() string {
+	return `{
+		"version": "2",
+        "period":3000,
+        "block_num":2,
+        "init_proposer": {
+            "address" : ["dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN", "WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT"]
+        },
+		"enable_vrf": true
+	}`
+}
+
+func TestVRF(t *testing.T) {
+	cCtx, err := prepare(getVRFXpoaConsensusConf())
+	if err != nil {
+		t.Fatal("prepare error", "error", err)
+	}
+	i := NewXpoaConsensus(*cCtx, getConfig(getVRFXpoaConsensusConf()))
+	if i == nil {
+		t.Fatal("NewXpoaConsensus error", "conf", getConfig(getVRFXpoaConsensusConf()))
+	}
+	xpoa, _ := i.(*xpoaConsensus)
+	l, _ := xpoa.election.ledger.(*kmock.FakeLedger)
+	tip := l.GetTipBlock()
+
+	// 找到本节点被抽中的时间片
+	validators := xpoa.election.validators
+	period := xpoa.election.period * int64(time.Millisecond)
+	ts := tip.GetTimestamp() + period
+	for n := 0; xpoa.election.getProposer(tip.GetBlockid(), ts, validators) != bmock.Miner; n++ {
+		if n > 100 {
+			t.Fatal("local node is never elected")
+		}
+		ts += period * xpoa.election.blockNum
+	}
+	_, storage, err := xpoa.ProcessBeforeMiner(tip.GetHeight()+1, ts)
+	if err != nil || storage == nil {
+		t.Fatal("ProcessBeforeMiner error", "err", err)
+	}
+	b3 := kmock.NewBlock(3)
+	b3.SetTimestamp(ts)
+	b3.PublicKey = bmock.PubKey
+	b3.ConsensusStorage = storage
+	if ok, err := xpoa.CheckMinerMatch(&cCtx.BaseCtx, b3); !ok || err != nil {
+		t.Fatal("CheckMinerMatch error", "err", err)
+	}
+
+	// 缺少或篡改VRF证明的区块被拒绝
+	b3.ConsensusStorage = nil
+	if ok, _ := xpoa.CheckMinerMatch(&cCtx.BaseCtx, b3); ok {
+		t.Fatal("block without vrf proof should be rejected")
+	}
+	s, _ := common.ParseOldQCStorage(storage)
+	s.VrfProof[len(s.VrfProof)-1] ^= 0x01
+	b3.ConsensusStorage, _ = json.Marshal(s)
+	if ok, _ := xpoa.CheckMinerMatch(&cCtx.BaseCtx, b3); ok {
+		t.Fatal("block with invalid vrf proof should be rejected")
+	}
+
+	// 时间片的出块人由时间片开始前最后一个区块的随机数决定，时间片内的后续区块不改变出块人
+	b3.ConsensusStorage = storage
+	l.Put(b3)
+	term, pos, _ := xpoa.election.minerScheduling(ts, len(validators))
+	slotEnd := xpoa.election.slotBegin(term, pos, len(validators)) + period*xpoa.election.blockNum - 1
+	if got := xpoa.election.getProposer(b3.GetBlockid(), slotEnd, validators); got != bmock.Miner {
+		t.Fatal("proposer should not change within slot", "got", got)
+	}
+}
+
+func TestVRFConfig(t *testing.T) {
+	conf := `{
+		"version": "2",
+        "period":3000,
+        "block_num":2,
+        "init_proposer": {
+            "address" : ["dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN", "WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT"]
+        },
+		"enable_vrf": true,
+		"liveness": {"miss_threshold": 3}
+	}`
+	cCtx, err := prepare(conf)
+	if err != nil {
+		t.Fatal("prepare error", "error", err)
+	}
+	if i := NewXpoaConsensus(*cCtx, getConfig(conf)); i != nil {
+		t.Fatal("vrf should not be enabled with liveness skip policy")
+	}
+}
@@ -28,15 +28,26 @@ const (
 )
 
 type evmCreator struct {
-	vm *evm.EVM
+	vm      *evm.EVM
+	syscall contractCaller
 }
 
-func newEvmCreator(_ *bridge.InstanceCreatorConfig) (bridge.InstanceCreator, error) {
-	opt := evm.Options{}
+func newEvmCreator(config *bridge.InstanceCreatorConfig) (bridge.InstanceCreator, error) {
+	natives, err := newNatives()
+	if err != nil {
+		return nil, err
+	}
+	opt := evm.Options{
+		Natives: natives,
+	}
 	vm := evm.New(opt)
-	return &evmCreator{
+	creator := &evmCreator{
 		vm: vm,
-	}, nil
+	}
+	if config != nil && config.SyscallService != nil {
+		creator.syscall = config.SyscallService
+	}
+	return creator, nil
 }
 
 // CreateInstance instances an evm virtual machine instance which can run a single contract call
@@ -50,6 +61,7 @@ func (e *evmCreator) CreateInstance(ctx *bridge.Context, cp bridge.ContractCodeP
 		blockState: blockState,
 		cp:         cp,
 		fromCache:  ctx.ReadFromCache,
+		syscall:    e.syscall,
 	}, nil
 }
 
@@ -66,6 +78,9 @@ type evmInstance struct {
 	abi        []byte
 	gasUsed    uint64
 	fromCache  bool
+	syscall    contractCaller
+	// savepoints 预编译合约发起的跨合约调用尚未确定是否保留的保存点
+	savepoints []frameSavepoint
 }
 
 func (i *evmInstance) Exec() error {
@@ -147,7 +162,7 @@ func (i *evmInstance) Exec() error {
 		Value:    value,
 		Gas:      &gas,
 	}
-	out, err := i.vm.Execute(i.state, i.blockState, i, params, i.code)
+	out, err := i.execute(params, i.code)
 	if err != nil {
 		return err
 	}
@@ -169,6 +184,41 @@ func (i *evmInstance) Exec() error {
 	return nil
 }
 
+// execute 执行 evm 调用。账户默认不加载余额，执行中才加载到读取余额的代码时，
+// 回滚本次执行的修改，加载余额后重新执行
+func (i *evmInstance) execute(params engine.CallParams, code []byte) ([]byte, error) {
+	if codeReadsBalance(code) {
+		i.state.loadBalance = true
+	}
+	sandbox, ok := i.ctx.State.(contract.SavepointSandbox)
+	if !ok {
+		i.state.loadBalance = true
+	}
+	if i.state.loadBalance {
+		return i.vm.Execute(i.state, i.blockState, i, params, code)
+	}
+
+	gas := *params.Gas
+	events := len(i.ctx.Events)
+	savepoint := sandbox.Savepoint()
+	out, err := i.vm.Execute(i.state, i.blockState, i, params, code)
+	if !i.state.reload {
+		if rerr := sandbox.ReleaseSavepoint(savepoint); rerr != nil {
+			return nil, rerr
+		}
+		return out, err
+	}
+	if err := sandbox.RollbackTo(savepoint); err != nil {
+		return nil, err
+	}
+	i.state.loadBalance = true
+	i.state.reload = false
+	i.savepoints = nil
+	i.ctx.Events = i.ctx.Events[:events]
+	*params.Gas = gas
+	return i.vm.Execute(i.state, i.blockState, i, params, code)
+}
+
 func (i *evmInstance) ResourceUsed() contract.Limits {
 	return contract.Limits{
 		Cpu: int64(i.gasUsed),
@@ -181,8 +231,9 @@ func (i *evmInstance) Release() {
 func (i *evmInstance) Abort(msg string) {
 }
 
+// Call 在每个调用帧结束时触发，调用帧回滚时一起回滚其中预编译合约发起的跨合约调用
 func (i *evmInstance) Call(call *exec.CallEvent, exception *errors.Exception) error {
-	return nil
+	return i.settleSavepoints(call.StackDepth, exception != nil)
 }
 
 func (i *evmInstance) Log(log *exec.LogEvent) error {
@@ -279,7 +330,7 @@ func (i *evmInstance) deployContract() error {
 		Value:    big.NewInt(0),
 		Gas:      &gas,
 	}
-	contractCode, err := i.vm.Execute(i.state, i.blockState, i, params, input)
+	contractCode, err := i.execute(params, input)
 	if err != nil {
 		return err
 	}
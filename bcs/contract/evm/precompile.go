@@ -0,0 +1,339 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/native"
+	"github.com/hyperledger/burrow/permission"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+)
+
+// xchain 预编译合约的名字，合约地址为名字的 keccak256 哈希的后 20 字节，
+// 对应的 Solidity 接口见 sol/XchainPrecompiles.sol
+const (
+	xchainAddressPrecompile  = "XchainAddress"
+	xchainContractPrecompile = "XchainContract"
+	xchainAclPrecompile      = "XchainAcl"
+)
+
+// xchain 预编译合约的 gas 与 burrow 的 native 合约一致，按基础消耗加上输入的字数计费，
+// 读取账本状态和转账另外按 native.GasGetAccount 和 native.GasStorageUpdate 计费，
+// 被调用合约的资源消耗通过 SubResourceUsed 单独计入
+const (
+	precompileGasBase uint64 = native.GasIdentityBase
+	precompileGasWord uint64 = native.GasIdentityWord
+)
+
+var (
+	errNotEVMInstance    = errors.New("xchain precompile must be called in xchain evm instance")
+	errCallerNotContract = errors.New("contract call precompile must be called by the executing contract")
+	errInsufficientGas   = errors.New("insufficient gas for xchain precompile")
+	errNoSavepoint       = errors.New("state sandbox does not support savepoint")
+)
+
+// contractCaller 是预编译合约发起跨合约调用的接口，由 bridge.SyscallService 实现
+type contractCaller interface {
+	ContractCall(ctx context.Context, in *pb.ContractCallRequest) (*pb.ContractCallResponse, error)
+}
+
+var xchainPrecompiles = native.New().
+	MustContract(xchainAddressPrecompile,
+		`* Convert between xchain addresses and evm addresses`,
+		native.Function{
+			Comment: `
+			* @notice Convert an xchain address, contract account or contract name to evm address
+			* @param Address xchain address
+			* @return Result evm address
+			`,
+			PermFlag: permission.None,
+			Pure:     true,
+			F:        xchainToEVMAddress,
+		},
+		native.Function{
+			Comment: `
+			* @notice Convert an evm address to xchain address, contract account or contract name
+			* @param Address evm address
+			* @return Result xchain address
+			`,
+			PermFlag: permission.None,
+			Pure:     true,
+			F:        evmAddressToXchain,
+		},
+	).
+	MustContract(xchainContractPrecompile,
+		`* Call wasm, native and kernel contracts`,
+		native.Function{
+			Comment: `
+			* @notice Call another contract through the xchain syscall service
+			* @param Module contract module, empty for looking up by contract name
+			* @param Contract contract name
+			* @param Method method name
+			* @param Args json encoded object of string arguments
+			* @param Amount native token transferred from the calling contract to the callee
+			* @return Status response status
+			* @return Message response message
+			* @return Body response body
+			`,
+			PermFlag: permission.None,
+			F:        contractCall,
+		},
+	).
+	MustContract(xchainAclPrecompile,
+		`* Query account acl`,
+		native.Function{
+			Comment: `
+			* @notice Check whether an address is a member of an account acl
+			* @param Account account name
+			* @param Address xchain address
+			* @return Result whether address is in account acl
+			`,
+			PermFlag: permission.None,
+			Pure:     true,
+			F:        isAccountMember,
+		},
+	)
+
+// newNatives 返回 burrow 默认的 natives 以及 xchain 预编译合约
+func newNatives() (*native.Natives, error) {
+	return native.Merge(native.Permissions, native.Precompiles, xchainPrecompiles)
+}
+
+// evmInstanceFromContext 通过 EventSink 取得当前执行的合约实例
+func evmInstanceFromContext(ctx native.Context) (*evmInstance, error) {
+	instance, ok := ctx.State.EventSink.(*evmInstance)
+	if !ok {
+		return nil, errNotEVMInstance
+	}
+	return instance, nil
+}
+
+// inputGas 返回预编译合约按输入计算的 gas
+func inputGas(ctx native.Context) uint64 {
+	words := (uint64(len(ctx.Input)) + 31) / 32
+	return precompileGasBase + words*precompileGasWord
+}
+
+func useGas(ctx native.Context, gas uint64) error {
+	if ctx.Gas == nil || *ctx.Gas < gas {
+		return errInsufficientGas
+	}
+	*ctx.Gas -= gas
+	return nil
+}
+
+type xchainToEVMAddressArgs struct {
+	Address string
+}
+
+type xchainToEVMAddressRets struct {
+	Result crypto.Address
+}
+
+func xchainToEVMAddress(ctx native.Context, args xchainToEVMAddressArgs) (xchainToEVMAddressRets, error) {
+	if err := useGas(ctx, inputGas(ctx)); err != nil {
+		return xchainToEVMAddressRets{}, err
+	}
+	var addr crypto.Address
+	var err error
+	if IsContractAccount(args.Address) {
+		addr, err = ContractAccountToEVMAddress(args.Address)
+	} else if IsContractName(args.Address) {
+		addr, err = ContractNameToEVMAddress(args.Address)
+	} else {
+		addr, err = XchainToEVMAddress(args.Address)
+	}
+	if err != nil {
+		return xchainToEVMAddressRets{}, err
+	}
+	return xchainToEVMAddressRets{Result: addr}, nil
+}
+
+type evmAddressToXchainArgs struct {
+	Address crypto.Address
+}
+
+type evmAddressToXchainRets struct {
+	Result string
+}
+
+func evmAddressToXchain(ctx native.Context, args evmAddressToXchainArgs) (evmAddressToXchainRets, error) {
+	if err := useGas(ctx, inputGas(ctx)); err != nil {
+		return evmAddressToXchainRets{}, err
+	}
+	instance, err := evmInstanceFromContext(ctx)
+	if err != nil {
+		return evmAddressToXchainRets{}, err
+	}
+	addr, addrType, err := DetermineEVMAddress(args.Address)
+	if err != nil {
+		return evmAddressToXchainRets{}, err
+	}
+	if addrType == contractAccountType {
+		addr = instance.state.accountName(addr)
+	}
+	return evmAddressToXchainRets{Result: addr}, nil
+}
+
+type contractCallArgs struct {
+	Module   string
+	Contract string
+	Method   string
+	Args     string
+	Amount   uint64
+}
+
+type contractCallRets struct {
+	Status  int64
+	Message string
+	Body    string
+}
+
+func contractCall(ctx native.Context, args contractCallArgs) (contractCallRets, error) {
+	// 查找被调用合约按读取账户计费，转账需要更新双方的余额
+	gas := inputGas(ctx) + native.GasGetAccount
+	if args.Amount > 0 {
+		gas += 2 * native.GasStorageUpdate
+	}
+	if err := useGas(ctx, gas); err != nil {
+		return contractCallRets{}, err
+	}
+	instance, err := evmInstanceFromContext(ctx)
+	if err != nil {
+		return contractCallRets{}, err
+	}
+	if instance.syscall == nil {
+		return contractCallRets{}, errors.New("syscall service is not available")
+	}
+	// 转账从当前合约的账户发起，因此只允许正在执行的合约本身调用
+	caller, err := DetermineContractNameFromEVM(ctx.Caller)
+	if err != nil || caller != instance.ctx.ContractName {
+		return contractCallRets{}, errCallerNotContract
+	}
+
+	var callArgs map[string]string
+	if args.Args != "" {
+		if err := json.Unmarshal([]byte(args.Args), &callArgs); err != nil {
+			return contractCallRets{}, fmt.Errorf("bad contract call args:%v", err)
+		}
+	}
+	req := &pb.ContractCallRequest{
+		Header: &pb.SyscallHeader{
+			Ctxid: instance.ctx.ID,
+		},
+		Module:   args.Module,
+		Contract: args.Contract,
+		Method:   args.Method,
+	}
+	keys := make([]string, 0, len(callArgs))
+	for key := range callArgs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		req.Args = append(req.Args, &pb.ArgPair{
+			Key:   key,
+			Value: []byte(callArgs[key]),
+		})
+	}
+	if args.Amount > 0 {
+		req.Amount = strconv.FormatUint(args.Amount, 10)
+	}
+
+	// 转账和被调用合约的写集直接进入共享的沙盒，不随 burrow 的调用帧回滚，
+	// 因此在保存点中执行，所在调用帧回滚时一起回滚，见 evmInstance.Call
+	sandbox, ok := instance.ctx.State.(contract.SavepointSandbox)
+	if !ok {
+		return contractCallRets{}, errNoSavepoint
+	}
+	savepoint := sandbox.Savepoint()
+	resp, err := instance.syscall.ContractCall(context.Background(), req)
+	if err != nil {
+		if rerr := sandbox.RollbackTo(savepoint); rerr != nil {
+			return contractCallRets{}, rerr
+		}
+		return contractCallRets{}, err
+	}
+	instance.savepoints = append(instance.savepoints, frameSavepoint{
+		depth: ctx.State.CallFrame.CallStackDepth(),
+		id:    savepoint,
+	})
+	return contractCallRets{
+		Status:  int64(resp.GetResponse().GetStatus()),
+		Message: resp.GetResponse().GetMessage(),
+		Body:    string(resp.GetResponse().GetBody()),
+	}, nil
+}
+
+// frameSavepoint 预编译合约发起跨合约调用前创建的保存点，depth 为保存点当前所属调用帧的深度
+type frameSavepoint struct {
+	depth uint64
+	id    int
+}
+
+// settleSavepoints 在深度为 depth 的调用帧结束时处理其中的保存点。
+// 调用帧回滚时回滚这些保存点；调用帧成功时保存点归入上一层调用帧，最外层调用帧成功时释放
+func (i *evmInstance) settleSavepoints(depth uint64, reverted bool) error {
+	// 保存点按创建顺序排列，所属调用帧的深度单调不减
+	n := len(i.savepoints)
+	for n > 0 && i.savepoints[n-1].depth >= depth {
+		n--
+	}
+	if n == len(i.savepoints) {
+		return nil
+	}
+	sandbox := i.ctx.State.(contract.SavepointSandbox)
+	first := i.savepoints[n].id
+	if reverted {
+		i.savepoints = i.savepoints[:n]
+		return sandbox.RollbackTo(first)
+	}
+	if depth == 0 {
+		i.savepoints = i.savepoints[:n]
+		return sandbox.ReleaseSavepoint(first)
+	}
+	for j := n; j < len(i.savepoints); j++ {
+		i.savepoints[j].depth = depth - 1
+	}
+	return nil
+}
+
+type isAccountMemberArgs struct {
+	Account string
+	Address string
+}
+
+type isAccountMemberRets struct {
+	Result bool
+}
+
+func isAccountMember(ctx native.Context, args isAccountMemberArgs) (isAccountMemberRets, error) {
+	if err := useGas(ctx, inputGas(ctx)+native.GasGetAccount); err != nil {
+		return isAccountMemberRets{}, err
+	}
+	instance, err := evmInstanceFromContext(ctx)
+	if err != nil {
+		return isAccountMemberRets{}, err
+	}
+	addrs, err := instance.ctx.Core.GetAccountAddresses(args.Account)
+	if err != nil {
+		return isAccountMemberRets{}, err
+	}
+	// acl 中的每个地址按读取一个账户计费
+	if err := useGas(ctx, uint64(len(addrs))*native.GasGetAccount); err != nil {
+		return isAccountMemberRets{}, err
+	}
+	for _, addr := range addrs {
+		if addr == args.Address {
+			return isAccountMemberRets{Result: true}, nil
+		}
+	}
+	return isAccountMemberRets{}, nil
+}
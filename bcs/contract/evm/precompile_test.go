@@ -0,0 +1,333 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/hyperledger/burrow/acm/acmstate"
+	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/engine"
+	"github.com/hyperledger/burrow/execution/evm"
+	"github.com/hyperledger/burrow/execution/evm/abi"
+	"github.com/hyperledger/burrow/execution/evm/asm"
+	"github.com/hyperledger/burrow/execution/evm/asm/bc"
+	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/hyperledger/burrow/execution/native"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+)
+
+type fakeChainCore struct {
+	contract.ChainCore
+	accounts map[string][]string
+}
+
+func (c *fakeChainCore) GetAccountAddresses(accountName string) ([]string, error) {
+	return c.accounts[accountName], nil
+}
+
+// testSandbox 不依赖 utxo 的沙盒，余额均为 balance，转账不生效
+type testSandbox struct {
+	*sandbox.XMCache
+	balance      int64
+	balanceReads int
+}
+
+func (s *testSandbox) GetBalance(addr string, frozen bool) (*big.Int, error) {
+	s.balanceReads++
+	return big.NewInt(s.balance), nil
+}
+
+func (s *testSandbox) Transfer(from, to string, amount *big.Int) error {
+	return nil
+}
+
+// fakeContractCaller 记录请求，state 不为 nil 时模拟被调用合约写入数据
+type fakeContractCaller struct {
+	req   *pb.ContractCallRequest
+	state contract.StateSandbox
+}
+
+func (c *fakeContractCaller) ContractCall(ctx context.Context, in *pb.ContractCallRequest) (*pb.ContractCallResponse, error) {
+	c.req = in
+	if c.state != nil {
+		if err := c.state.Put(in.GetContract(), []byte("called"), []byte("true")); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.ContractCallResponse{
+		Response: &pb.Response{
+			Status: 200,
+			Body:   []byte("ok"),
+		},
+	}, nil
+}
+
+func newPrecompileContext(instance *evmInstance, caller crypto.Address) native.Context {
+	gas := uint64(10)
+	return native.Context{
+		State: engine.State{
+			CallFrame: engine.NewCallFrame(acmstate.NewMemoryState()),
+			EventSink: instance,
+		},
+		CallParams: engine.CallParams{
+			Caller: caller,
+			Gas:    &gas,
+		},
+	}
+}
+
+func newPrecompileInstance(caller contractCaller) *evmInstance {
+	ctx := &bridge.Context{
+		ID:           1,
+		ContractName: "counter",
+		ChainName:    "xuper",
+		Core: &fakeChainCore{
+			accounts: map[string][]string{
+				"XC1111111111111111@xuper": {"jSPJQSAR3NWoKcSFMxYGfcY8KVskvNMtm"},
+			},
+		},
+		State: &testSandbox{
+			XMCache: sandbox.NewXModelCache(&contract.SandboxConfig{
+				XMReader: sandbox.NewMemXModel(),
+			}),
+		},
+	}
+	return &evmInstance{
+		ctx:     ctx,
+		state:   newStateManager(ctx),
+		syscall: caller,
+	}
+}
+
+func TestXchainPrecompilesRegistered(t *testing.T) {
+	natives, err := newNatives()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{xchainAddressPrecompile, xchainContractPrecompile, xchainAclPrecompile} {
+		if !natives.IsRegistered(native.AddressFromName(name)) {
+			t.Errorf("precompile %s not registered", name)
+		}
+	}
+	// burrow 默认的预编译合约仍然可用
+	if !natives.IsRegistered(crypto.Address{19: 2}) {
+		t.Error("sha256 precompile not registered")
+	}
+}
+
+func TestAddressPrecompile(t *testing.T) {
+	instance := newPrecompileInstance(nil)
+	ctx := newPrecompileContext(instance, crypto.Address{})
+
+	for _, addr := range []string{
+		"jSPJQSAR3NWoKcSFMxYGfcY8KVskvNMtm",
+		"counter",
+		"XC1111111111111111@xuper",
+	} {
+		evmAddr, err := xchainToEVMAddress(ctx, xchainToEVMAddressArgs{Address: addr})
+		if err != nil {
+			t.Fatal(err)
+		}
+		xchainAddr, err := evmAddressToXchain(ctx, evmAddressToXchainArgs{Address: evmAddr.Result})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if xchainAddr.Result != addr {
+			t.Errorf("expect %s got %s", addr, xchainAddr.Result)
+		}
+	}
+
+	if *ctx.Gas != 4 {
+		t.Errorf("expect gas left 4 got %d", *ctx.Gas)
+	}
+
+	// 输入按字计费
+	ctx.Input = make([]byte, 64)
+	if _, err := xchainToEVMAddress(ctx, xchainToEVMAddressArgs{Address: "counter"}); err != nil {
+		t.Fatal(err)
+	}
+	if *ctx.Gas != 1 {
+		t.Errorf("expect gas left 1 got %d", *ctx.Gas)
+	}
+	if _, err := xchainToEVMAddress(ctx, xchainToEVMAddressArgs{Address: "counter"}); err != errInsufficientGas {
+		t.Errorf("expect %v got %v", errInsufficientGas, err)
+	}
+}
+
+func TestContractCallPrecompile(t *testing.T) {
+	caller := &fakeContractCaller{}
+	instance := newPrecompileInstance(caller)
+
+	callerAddr, err := ContractNameToEVMAddress("counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := newPrecompileContext(instance, callerAddr)
+	rets, err := contractCall(ctx, contractCallArgs{
+		Module:   "wasm",
+		Contract: "token",
+		Method:   "transfer",
+		Args:     `{"to":"bob","amount":"10"}`,
+		Amount:   100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rets.Status != 200 || rets.Body != "ok" {
+		t.Errorf("unexpected response %v", rets)
+	}
+	// 查找被调用合约和转账双方余额的更新另外计费
+	if *ctx.Gas != 6 {
+		t.Errorf("expect gas left 6 got %d", *ctx.Gas)
+	}
+
+	req := caller.req
+	if req.GetHeader().GetCtxid() != 1 || req.GetModule() != "wasm" ||
+		req.GetContract() != "token" || req.GetMethod() != "transfer" || req.GetAmount() != "100" {
+		t.Errorf("unexpected request %v", req)
+	}
+	if len(req.GetArgs()) != 2 || req.GetArgs()[0].GetKey() != "amount" || req.GetArgs()[1].GetKey() != "to" {
+		t.Errorf("unexpected args %v", req.GetArgs())
+	}
+
+	// 只有正在执行的合约可以发起跨合约调用
+	otherAddr, _ := ContractNameToEVMAddress("other")
+	ctx = newPrecompileContext(instance, otherAddr)
+	_, err = contractCall(ctx, contractCallArgs{Contract: "token", Method: "transfer"})
+	if err != errCallerNotContract {
+		t.Errorf("expect %v got %v", errCallerNotContract, err)
+	}
+}
+
+func TestAclPrecompile(t *testing.T) {
+	instance := newPrecompileInstance(nil)
+	ctx := newPrecompileContext(instance, crypto.Address{})
+
+	rets, err := isAccountMember(ctx, isAccountMemberArgs{
+		Account: "XC1111111111111111@xuper",
+		Address: "jSPJQSAR3NWoKcSFMxYGfcY8KVskvNMtm",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rets.Result {
+		t.Error("expect address in account acl")
+	}
+
+	rets, err = isAccountMember(ctx, isAccountMemberArgs{
+		Account: "XC1111111111111111@xuper",
+		Address: "dpzuVdosQrF2kmzumhVeFQZa1aYcdgFpN",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rets.Result {
+		t.Error("expect address not in account acl")
+	}
+	// 每次查询按读取账户和 acl 中的地址计费
+	if *ctx.Gas != 4 {
+		t.Errorf("expect gas left 4 got %d", *ctx.Gas)
+	}
+}
+
+// precompileInput 按照预编译合约函数的 abi 编码输入
+func precompileInput(t *testing.T, name, function string, args ...interface{}) ([]byte, *abi.FunctionSpec) {
+	spec := xchainPrecompiles.GetContract(name).FunctionByName(function).Abi()
+	packed, err := abi.Pack(spec.Inputs, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(spec.FunctionID[:], packed...), spec
+}
+
+// callPrecompileCode 将输入原样转发给预编译合约
+func callPrecompileCode(precompile crypto.Address) []byte {
+	return bc.MustSplice(asm.PUSH1, 0, asm.PUSH1, 0, asm.CALLDATASIZE, asm.PUSH1, 0, asm.PUSH1, 0, asm.PUSH20, precompile, asm.GAS, asm.CALL, asm.POP)
+}
+
+// execEVM 以合约 counter 的身份在 burrow evm 中执行 code
+func execEVM(t *testing.T, instance *evmInstance, code, input []byte) ([]byte, error) {
+	natives, err := newNatives()
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance.vm = evm.New(evm.Options{Natives: natives})
+	caller, _ := XchainToEVMAddress("jSPJQSAR3NWoKcSFMxYGfcY8KVskvNMtm")
+	callee, _ := ContractNameToEVMAddress(instance.ctx.ContractName)
+	if err := instance.ctx.State.Put("contract", evmCodeKey(instance.ctx.ContractName), code); err != nil {
+		t.Fatal(err)
+	}
+	gas := uint64(contract.MaxLimits.Cpu)
+	instance.blockState = newBlockStateManager(instance.ctx)
+	return instance.execute(engine.CallParams{
+		CallType: exec.CallTypeCode,
+		Caller:   caller,
+		Callee:   callee,
+		Input:    input,
+		Value:    big.NewInt(0),
+		Gas:      &gas,
+	}, code)
+}
+
+func TestPrecompileThroughEVM(t *testing.T) {
+	instance := newPrecompileInstance(nil)
+	// 转发输入并返回预编译合约的输出
+	code := bc.MustSplice(asm.CALLDATASIZE, asm.PUSH1, 0, asm.PUSH1, 0, asm.CALLDATACOPY,
+		callPrecompileCode(native.AddressFromName(xchainAddressPrecompile)),
+		asm.RETURNDATASIZE, asm.PUSH1, 0, asm.PUSH1, 0, asm.RETURNDATACOPY, asm.RETURNDATASIZE, asm.PUSH1, 0, asm.RETURN)
+	input, spec := precompileInput(t, xchainAddressPrecompile, "xchainToEVMAddress", "counter")
+	out, err := execEVM(t, instance, code, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var addr crypto.Address
+	if err := abi.Unpack(spec.Outputs, out, &addr); err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := ContractNameToEVMAddress("counter"); addr != want {
+		t.Errorf("expect %v got %v", want, addr)
+	}
+}
+
+func TestContractCallPrecompileRevert(t *testing.T) {
+	for _, revert := range []bool{true, false} {
+		caller := &fakeContractCaller{}
+		instance := newPrecompileInstance(caller)
+		caller.state = instance.ctx.State
+		// 外层调用合约自身，内层调用预编译合约后按照 revert 回滚或者返回，外层忽略内层的结果
+		end := asm.RETURN
+		if revert {
+			end = asm.REVERT
+		}
+		head := bc.MustSplice(asm.CALLDATASIZE, asm.PUSH1, 0, asm.PUSH1, 0, asm.CALLDATACOPY, asm.ADDRESS, asm.CALLER, asm.EQ, asm.PUSH1, 0, asm.JUMPI)
+		outer := bc.MustSplice(asm.PUSH1, 0, asm.PUSH1, 0, asm.CALLDATASIZE, asm.PUSH1, 0, asm.PUSH1, 0, asm.ADDRESS, asm.GAS, asm.CALL, asm.POP, asm.STOP)
+		inner := bc.MustSplice(asm.JUMPDEST, callPrecompileCode(native.AddressFromName(xchainContractPrecompile)),
+			asm.PUSH1, 0, asm.PUSH1, 0, end)
+		head[len(head)-2] = byte(len(head) + len(outer))
+		code := bc.MustSplice(head, outer, inner)
+
+		input, _ := precompileInput(t, xchainContractPrecompile, "contractCall", "wasm", "token", "transfer", "", uint64(0))
+		if _, err := execEVM(t, instance, code, input); err != nil {
+			t.Fatal(err)
+		}
+		if caller.req.GetContract() != "token" {
+			t.Fatalf("precompile should be called, got %v", caller.req)
+		}
+		// 调用帧回滚时被调用合约的写集一起回滚
+		_, err := instance.ctx.State.Get("token", []byte("called"))
+		if revert && err == nil {
+			t.Error("contract call in reverted frame should be rolled back")
+		}
+		if !revert && err != nil {
+			t.Errorf("contract call should be kept, got %v", err)
+		}
+		if len(instance.savepoints) != 0 {
+			t.Errorf("savepoints should be settled, got %v", instance.savepoints)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+pragma solidity >=0.5.0;
+
+// Interfaces of the xchain precompiled contracts registered in the xchain evm.
+// The address of each precompile is the last 20 bytes of keccak256(name).
+
+// XchainAddress converts between xchain addresses and evm addresses.
+// name: XchainAddress
+interface XchainAddress {
+    // Convert an xchain address, contract account or contract name to evm address.
+    function xchainToEVMAddress(string calldata xchainAddr) external view returns (address);
+
+    // Convert an evm address to xchain address, contract account or contract name.
+    function evmAddressToXchain(address evmAddr) external view returns (string memory);
+}
+
+// XchainContract calls wasm, native and kernel contracts.
+// name: XchainContract
+interface XchainContract {
+    // Call another contract through the xchain syscall service.
+    // module can be empty, in which case the module is looked up by contract name.
+    // args is a json encoded object of string arguments, e.g. {"key":"value"}.
+    // amount is transferred from the calling contract to the callee.
+    // The call can only be made by the executing contract itself.
+    // Transfers and writes of the call are reverted together with the calling frame.
+    function contractCall(
+        string calldata module,
+        string calldata contractName,
+        string calldata method,
+        string calldata args,
+        uint64 amount
+    ) external returns (int64 status, string memory message, string memory body);
+}
+
+// XchainAcl queries account acl.
+// name: XchainAcl
+interface XchainAcl {
+    // Check whether an address is a member of an account acl.
+    function isAccountMember(string calldata account, string calldata addr) external view returns (bool);
+}
+
+library XchainPrecompiles {
+    XchainAddress internal constant ADDRESS = XchainAddress(0x46053d5a60C55BE019E498c56B3fF5AdBAB027eC);
+    XchainContract internal constant CONTRACT = XchainContract(0x185246FDbDC30Bf50865Bd8d44e64573A70B8010);
+    XchainAcl internal constant ACL = XchainAcl(0xF0Be4F5E83bF182FF04D59Fe44Ef2F75f40e5CF0);
+}
@@ -1,19 +1,30 @@
 package evm
 
 import (
+	"errors"
 	"math/big"
 	"time"
 
 	"github.com/hyperledger/burrow/acm"
 	"github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/evm/asm"
 	"github.com/hyperledger/burrow/permission"
 
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
 )
 
+var errBalanceReload = errors.New("evm balance is required after accounts are cached")
+
 type stateManager struct {
 	ctx *bridge.Context
+	// loadBalance 账户是否携带余额。读取余额会把账户的全部 utxo 记入读集，
+	// 因此只有执行的代码中有 BALANCE 或 SELFDESTRUCT 时才加载，附带金额的 CALL 通过 Transfer 转账，不读取余额
+	loadBalance bool
+	// noBalance 是否返回过未加载余额的账户
+	noBalance bool
+	// reload 执行中才加载到读取余额的代码，此前返回的账户已被 burrow 缓存，需要加载余额重新执行
+	reload bool
 }
 
 func newStateManager(ctx *bridge.Context) *stateManager {
@@ -36,13 +47,27 @@ func (s *stateManager) GetAccount(address crypto.Address) (*acm.Account, error)
 			return nil, nil
 		}
 		evmCode = v
+		if !s.loadBalance && codeReadsBalance(evmCode) {
+			if s.noBalance {
+				s.reload = true
+				return nil, errBalanceReload
+			}
+			s.loadBalance = true
+		}
 	}
 
-	// balance, err := s.ctx.Core.GetBalance(addr)
-	// if err != nil {
-	// return nil, nil
-	// }
-	var balance *big.Int
+	balance := new(big.Int)
+	if !s.loadBalance {
+		s.noBalance = true
+	} else {
+		if addrType == contractAccountType {
+			addr = s.accountName(addr)
+		}
+		balance, err = s.ctx.State.GetBalance(addr, false)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &acm.Account{
 		Address:     address,
 		Balance:     balance,
@@ -51,6 +76,18 @@ func (s *stateManager) GetAccount(address crypto.Address) (*acm.Account, error)
 	}, nil
 }
 
+// codeReadsBalance 返回代码中是否有读取账户余额的指令，跳过 PUSH 指令携带的数据
+func codeReadsBalance(code []byte) bool {
+	for pc := 0; pc < len(code); pc++ {
+		op := asm.OpCode(code[pc])
+		if op == asm.BALANCE || op == asm.SELFDESTRUCT {
+			return true
+		}
+		pc += op.Pushes()
+	}
+	return false
+}
+
 // Retrieve a 32-byte value stored at key for the account at address, return Zero256 if key does not exist but
 // error if address does not
 func (s *stateManager) GetStorage(address crypto.Address, key binary.Word256) ([]byte, error) {
@@ -106,13 +143,17 @@ func (s *stateManager) Transfer(from, to crypto.Address, amount *big.Int) error
 	}
 
 	if addrType == contractAccountType {
-		// 构造完整的合约账户
-		toAddr = "XC" + toAddr + "@" + s.ctx.ChainName
+		toAddr = s.accountName(toAddr)
 	}
 
 	return s.ctx.State.Transfer(fromAddr, toAddr, amount)
 }
 
+// accountName 构造完整的合约账户
+func (s *stateManager) accountName(account string) string {
+	return "XC" + account + "@" + s.ctx.ChainName
+}
+
 type blockStateManager struct {
 	ctx *bridge.Context
 }
@@ -3,7 +3,10 @@ package evm
 import (
 	"testing"
 
+	"github.com/hyperledger/burrow/binary"
 	"github.com/hyperledger/burrow/crypto"
+	"github.com/hyperledger/burrow/execution/evm/asm"
+	"github.com/hyperledger/burrow/execution/evm/asm/bc"
 
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
 )
@@ -19,3 +22,42 @@ func TestNewStateManager(t *testing.T) {
 
 	st.RemoveAccount(crypto.Address{})
 }
+
+func TestGetAccountBalance(t *testing.T) {
+	instance := newPrecompileInstance(nil)
+	st := instance.ctx.State.(*testSandbox)
+	st.balance = 7
+	addr, _ := ContractNameToEVMAddress("counter")
+	// PUSH 的数据不是指令
+	code := bc.MustSplice(asm.PUSH1, asm.BALANCE, asm.POP)
+	if err := st.Put("contract", evmCodeKey("counter"), code); err != nil {
+		t.Fatal(err)
+	}
+	acc, err := instance.state.GetAccount(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.Balance.Sign() != 0 || st.balanceReads != 0 {
+		t.Errorf("balance should not be loaded, got %v", acc.Balance)
+	}
+
+	// 执行中才调用到读取余额的合约，重新执行并加载余额
+	reader := bc.MustSplice(asm.ADDRESS, asm.BALANCE, asm.PUSH1, 0, asm.MSTORE, asm.PUSH1, 32, asm.PUSH1, 0, asm.RETURN)
+	if err := st.Put("contract", evmCodeKey("reader"), reader); err != nil {
+		t.Fatal(err)
+	}
+	readerAddr, _ := ContractNameToEVMAddress("reader")
+	code = bc.MustSplice(asm.PUSH1, 0, asm.PUSH1, 0, asm.PUSH1, 0, asm.PUSH1, 0, asm.PUSH1, 0, asm.PUSH20, readerAddr, asm.GAS, asm.CALL, asm.POP,
+		asm.RETURNDATASIZE, asm.PUSH1, 0, asm.PUSH1, 0, asm.RETURNDATACOPY, asm.RETURNDATASIZE, asm.PUSH1, 0, asm.RETURN)
+	instance.state = newStateManager(instance.ctx)
+	out, err := execEVM(t, instance, code, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := binary.BigIntFromWord256(binary.LeftPadWord256(out)); balance.Int64() != 7 {
+		t.Errorf("expect balance 7, got %v", balance)
+	}
+	if !instance.state.loadBalance || st.balanceReads == 0 {
+		t.Error("balance should be loaded")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
@@ -27,6 +28,8 @@ type contractProcess struct {
 	binpath   string
 	chainAddr string
 	desc      *protos.WasmCodeDesc
+	// sandboxDir 沙箱模式下存放code socket和rootfs挂载点的临时目录
+	sandboxDir string
 
 	process       Process
 	monitorStopch chan struct{}
@@ -51,6 +54,14 @@ func newContractProcess(cfg *contract.NativeConfig, name, basedir, chainAddr str
 		logger:        log15.New(),
 		//logger:        log.DefaultLogger.New("contract", name),
 	}
+	if useSandbox(cfg) {
+		// unix socket的路径长度有限，不能放在可能很深的basedir中
+		dir, err := os.MkdirTemp("", "xchain-sandbox-")
+		if err != nil {
+			return nil, err
+		}
+		process.sandboxDir = dir
+	}
 	return process, nil
 }
 
@@ -63,6 +74,17 @@ func (c *contractProcess) makeNativeProcess() (Process, error) {
 	if err != nil {
 		return nil, err
 	}
+	if useSandbox(c.cfg) {
+		return &SandboxProcess{
+			basedir:   c.basedir,
+			startcmd:  startcmd,
+			codePort:  c.rpcPort,
+			rundir:    c.sandboxDir,
+			chainSock: sandboxChainSock(c.chainAddr),
+			cfg:       &c.cfg.Sandbox,
+			Logger:    c.logger,
+		}, nil
+	}
 	if !c.cfg.Docker.Enable {
 		return &HostProcess{
 			basedir:  c.basedir,
@@ -146,7 +168,12 @@ func (c *contractProcess) resetRpcClient() error {
 	if err != nil {
 		return err
 	}
-	conn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", port), grpc.WithInsecure())
+	target := fmt.Sprintf("127.0.0.1:%d", port)
+	if useSandbox(c.cfg) {
+		// 沙箱中的端口只在其网络命名空间中可见，经由init进程转发
+		target = "unix://" + sandboxCodeSock(c.sandboxDir)
+	}
+	conn, err := grpc.Dial(target, grpc.WithInsecure())
 	if err != nil {
 		return err
 	}
@@ -195,7 +222,11 @@ func (c *contractProcess) start(startMonitor bool) error {
 }
 
 func (c *contractProcess) Start() error {
-	return c.start(true)
+	err := c.start(true)
+	if err != nil && c.sandboxDir != "" {
+		os.RemoveAll(c.sandboxDir)
+	}
+	return err
 }
 
 func (c *contractProcess) Stop() {
@@ -207,6 +238,9 @@ func (c *contractProcess) Stop() {
 	if err != nil {
 		c.logger.Error("process stoped error", "error", err)
 	}
+	if c.sandboxDir != "" {
+		os.RemoveAll(c.sandboxDir)
+	}
 }
 
 func (c *contractProcess) GetDesc() *protos.WasmCodeDesc {
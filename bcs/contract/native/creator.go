@@ -2,14 +2,19 @@ package native
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge/pbrpc"
+	"github.com/xuperchain/xupercore/lib/metrics"
 	"google.golang.org/grpc"
 )
 
@@ -17,12 +22,17 @@ type nativeCreator struct {
 	config   *bridge.InstanceCreatorConfig
 	listener net.Listener
 	pm       *processManager
+	// metered 启用计量时不为nil
+	metered *meteredInstances
 }
 
 func newNativeCreator(cfg *bridge.InstanceCreatorConfig) (bridge.InstanceCreator, error) {
 	creator := &nativeCreator{
 		config: cfg,
 	}
+	if cfg.VMConfig.(*contract.NativeConfig).Metering.Enable {
+		creator.metered = newMeteredInstances()
+	}
 	err := os.MkdirAll(cfg.Basedir, 0755)
 	if err != nil {
 		return nil, err
@@ -43,13 +53,21 @@ func newNativeCreator(cfg *bridge.InstanceCreatorConfig) (bridge.InstanceCreator
 }
 
 func (n *nativeCreator) startRpcServer(service *bridge.SyscallService) (string, error) {
+	var opts []grpc.ServerOption
+	if n.metered != nil {
+		opts = append(opts, grpc.UnaryInterceptor(n.metered.intercept))
+	}
+	rpcServer := grpc.NewServer(opts...)
+	pbrpc.RegisterSyscallServer(rpcServer, service)
+	if useSandbox(n.config.VMConfig.(*contract.NativeConfig)) {
+		return n.startUnixRpcServer(rpcServer)
+	}
+
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
 		return "", err
 	}
 	n.listener = listener
-	rpcServer := grpc.NewServer()
-	pbrpc.RegisterSyscallServer(rpcServer, service)
 
 	port := listener.Addr().(*net.TCPAddr).Port
 
@@ -63,12 +81,37 @@ func (n *nativeCreator) startRpcServer(service *bridge.SyscallService) (string,
 	return addr, nil
 }
 
+// startUnixRpcServer 沙箱没有宿主机的网络，syscall服务通过unix socket提供，目录会被挂载到沙箱中
+func (n *nativeCreator) startUnixRpcServer(rpcServer *grpc.Server) (string, error) {
+	dir, err := os.MkdirTemp("", "xchain-syscall-")
+	if err != nil {
+		return "", err
+	}
+	sock := filepath.Join(dir, "syscall.sock")
+	listener, err := net.Listen("unix", sock)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	n.listener = listener
+	// 服务停止后清理临时目录
+	go func() {
+		rpcServer.Serve(listener)
+		os.RemoveAll(dir)
+	}()
+	return "unix://" + sock, nil
+}
+
 func (n *nativeCreator) CreateInstance(ctx *bridge.Context, cp bridge.ContractCodeProvider) (bridge.Instance, error) {
 	process, err := n.pm.GetProcess(ctx.ContractName, cp)
 	if err != nil {
 		return nil, err
 	}
-	return newNativeVmInstance(ctx, process), nil
+	instance := newNativeVmInstance(ctx, process)
+	if n.metered != nil {
+		n.metered.add(instance, newSyscallMeter())
+	}
+	return instance, nil
 }
 
 func (n *nativeCreator) RemoveCache(name string) {
@@ -78,6 +121,13 @@ func (n *nativeCreator) RemoveCache(name string) {
 type nativeVmInstance struct {
 	ctx     *bridge.Context
 	process *contractProcess
+	// meter 启用计量时不为nil，此时按系统调用统计资源消耗
+	meter   *syscallMeter
+	release func()
+
+	mutex    sync.Mutex
+	cancel   context.CancelFunc
+	abortMsg string
 }
 
 func newNativeVmInstance(ctx *bridge.Context, process *contractProcess) *nativeVmInstance {
@@ -91,21 +141,94 @@ func (i *nativeVmInstance) Exec() error {
 	request := &pb.NativeCallRequest{
 		Ctxid: i.ctx.ID,
 	}
-	_, err := i.process.RpcClient().Call(context.TODO(), request)
+	// 能够统计资源的进程以调用前后的差值作为本次调用的CPU消耗，并发调用同一合约时会互相计入
+	reporter, ok := i.process.process.(ResourceReporter)
+	var cpuBefore int64
+	if ok {
+		cpuBefore, _, _ = reporter.ResourceUsage()
+	}
+	ctx, cancel := i.execContext()
+	defer cancel()
+	_, err := i.process.RpcClient().Call(ctx, request)
+	if msg := i.aborted(); msg != "" {
+		return errors.New(msg)
+	}
+	if ok {
+		cpu, memory, uerr := reporter.ResourceUsage()
+		if uerr == nil && cpu >= cpuBefore {
+			i.reportMeasured(cpu-cpuBefore, memory)
+		}
+	}
 	return err
 }
 
+// reportMeasured 输出沙箱测量的CPU时间(微秒)和内存(字节)。
+// 测量值与节点负载有关，不同节点的结果不同，只用于日志和监控，不计入合约的资源消耗
+func (i *nativeVmInstance) reportMeasured(cpu, memory int64) {
+	if i.ctx.Logger != nil {
+		i.ctx.Logger.Debug("native contract resource measured", "contract", i.ctx.ContractName, "cpu", cpu, "memory", memory)
+	}
+	metrics.ContractNativeCpuHistogram.WithLabelValues(i.ctx.ContractName).Observe(float64(cpu) / 1e6)
+	metrics.ContractNativeMemoryGauge.WithLabelValues(i.ctx.ContractName).Set(float64(memory))
+}
+
+// execContext 返回本次调用的context，Abort或超出ExecTimeout时取消
+func (i *nativeVmInstance) execContext() (context.Context, context.CancelFunc) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout := i.process.cfg.ExecTimeout; timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Duration(timeout)*time.Millisecond)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.cancel = cancel
+	return ctx, cancel
+}
+
+func (i *nativeVmInstance) aborted() string {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.abortMsg
+}
+
+// checkResource 合约当前的资源消耗超出ResourceLimits时中止合约调用
+func (i *nativeVmInstance) checkResource() error {
+	if !i.ctx.ResourceUsed().Exceed(i.ctx.ResourceLimits) {
+		return nil
+	}
+	i.Abort(ErrResourceExceeds.Error())
+	return ErrResourceExceeds
+}
+
+// ResourceUsed 启用计量时返回按系统调用统计的确定性消耗，否则返回固定的XFee，
+// 任何情况下都不计入测量值，保证各节点的结果一致
 func (i *nativeVmInstance) ResourceUsed() contract.Limits {
+	if i.meter != nil {
+		return i.meter.Used()
+	}
 	return contract.Limits{
 		XFee: 1,
 	}
 }
 
 func (i *nativeVmInstance) Release() {
-
+	if i.release != nil {
+		i.release()
+	}
 }
 
+// Abort 取消正在进行的合约调用，Exec返回msg作为错误
 func (i *nativeVmInstance) Abort(msg string) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if i.abortMsg == "" {
+		i.abortMsg = msg
+	}
+	if i.cancel != nil {
+		i.cancel()
+	}
 }
 
 func init() {
@@ -0,0 +1,162 @@
+package native
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+)
+
+// 系统调用的计费权重，单位均为cpu。权重决定了交易的gas和交易是否超出资源限制，
+// 链上所有节点必须一致，因此固定在代码中而不是由节点配置
+const (
+	// syscallCpu 未在下面列出的系统调用每次的消耗
+	syscallCpu int64 = 1000
+	// getCpu GetObject和GetBalance每次的消耗
+	getCpu int64 = 2000
+	// putCpu PutObject和DeleteObject每次的消耗
+	putCpu int64 = 5000
+	// iteratorItemCpu NewIterator每返回一条数据的消耗，另外计入syscallCpu
+	iteratorItemCpu int64 = 500
+	// contractCallCpu ContractCall每次的消耗，不包括被调用合约自身的消耗
+	contractCallCpu int64 = 10000
+	// emitEventCpu EmitEvent每次的消耗
+	emitEventCpu int64 = 5000
+	// byteCpu 系统调用的请求和响应每字节的消耗
+	byteCpu int64 = 10
+)
+
+var (
+	// ErrResourceExceeds 合约的系统调用消耗超出ResourceLimits
+	ErrResourceExceeds = errors.New("native contract resource exceeds")
+)
+
+// syscallMeter 按系统调用及其传输的字节数确定性地统计native合约的资源消耗
+// 合约进程内部的计算不计入，由ExecTimeout保证合约调用不会无限运行
+type syscallMeter struct {
+	mutex sync.Mutex
+	used  contract.Limits
+}
+
+func newSyscallMeter() *syscallMeter {
+	return &syscallMeter{}
+}
+
+// syscallCpu 返回一次系统调用本身的消耗，method为不带服务名的方法名
+func (m *syscallMeter) syscallCpu(method string) int64 {
+	switch method {
+	case "GetObject", "GetBalance":
+		return getCpu
+	case "PutObject", "DeleteObject":
+		return putCpu
+	case "ContractCall":
+		return contractCallCpu
+	case "EmitEvent":
+		return emitEventCpu
+	default:
+		return syscallCpu
+	}
+}
+
+// chargeRequest 计入系统调用本身和请求的字节数
+func (m *syscallMeter) chargeRequest(method string, req interface{}) {
+	m.charge(m.syscallCpu(method) + m.bytesCpu(req))
+}
+
+// chargeResponse 计入响应的字节数，迭代器按返回的条目数额外计费
+func (m *syscallMeter) chargeResponse(resp interface{}) {
+	cpu := m.bytesCpu(resp)
+	if iter, ok := resp.(*pb.IteratorResponse); ok {
+		cpu += int64(len(iter.GetItems())) * iteratorItemCpu
+	}
+	m.charge(cpu)
+}
+
+func (m *syscallMeter) bytesCpu(msg interface{}) int64 {
+	pmsg, ok := msg.(proto.Message)
+	if !ok {
+		return 0
+	}
+	return int64(proto.Size(pmsg)) * byteCpu
+}
+
+func (m *syscallMeter) charge(cpu int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.used.Cpu += cpu
+}
+
+// Used 返回已经计入的资源消耗
+func (m *syscallMeter) Used() contract.Limits {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.used
+}
+
+// meteredInstances 启用计量时正在执行的合约实例，系统调用按ctxid找到对应的实例计费
+type meteredInstances struct {
+	mutex     sync.Mutex
+	instances map[int64]*nativeVmInstance
+}
+
+func newMeteredInstances() *meteredInstances {
+	return &meteredInstances{
+		instances: make(map[int64]*nativeVmInstance),
+	}
+}
+
+// add 登记合约实例并开始计费，实例Release时取消登记
+func (m *meteredInstances) add(instance *nativeVmInstance, meter *syscallMeter) {
+	instance.meter = meter
+	instance.release = func() {
+		m.remove(instance.ctx.ID)
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.instances[instance.ctx.ID] = instance
+}
+
+func (m *meteredInstances) remove(ctxid int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.instances, ctxid)
+}
+
+func (m *meteredInstances) get(ctxid int64) (*nativeVmInstance, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	instance, ok := m.instances[ctxid]
+	return instance, ok
+}
+
+// intercept 作为syscall服务的拦截器，在调用前后计费，超出ResourceLimits时中止合约调用并返回错误
+func (m *meteredInstances) intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	header, ok := req.(interface{ GetHeader() *pb.SyscallHeader })
+	if !ok {
+		return handler(ctx, req)
+	}
+	instance, ok := m.get(header.GetHeader().GetCtxid())
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	instance.meter.chargeRequest(path.Base(info.FullMethod), req)
+	if err := instance.checkResource(); err != nil {
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	instance.meter.chargeResponse(resp)
+	if err := instance.checkResource(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,145 @@
+package native
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pbrpc"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+)
+
+type meteringHelper struct {
+	ctxmgr  *bridge.ContextManager
+	metered *meteredInstances
+	client  pbrpc.SyscallClient
+	cfg     contract.NativeConfig
+}
+
+func newMeteringHelper(t *testing.T) *meteringHelper {
+	h := &meteringHelper{
+		ctxmgr:  bridge.NewContextManager(),
+		metered: newMeteredInstances(),
+		cfg:     contract.DefaultContractConfig().Native,
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(grpc.UnaryInterceptor(h.metered.intercept))
+	pbrpc.RegisterSyscallServer(server, bridge.NewSyscallService(h.ctxmgr, nil))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	h.client = pbrpc.NewSyscallClient(conn)
+	return h
+}
+
+func (h *meteringHelper) newInstance(limits contract.Limits) *nativeVmInstance {
+	ctx := h.ctxmgr.MakeContext()
+	ctx.ContractName = "counter"
+	ctx.State = sandbox.NewXModelCache(&contract.SandboxConfig{
+		XMReader: sandbox.NewMemXModel(),
+	})
+	ctx.ResourceLimits = limits
+	instance := newNativeVmInstance(ctx, &contractProcess{cfg: &h.cfg})
+	ctx.Instance = instance
+	h.metered.add(instance, newSyscallMeter())
+	return instance
+}
+
+func TestSyscallMetering(t *testing.T) {
+	h := newMeteringHelper(t)
+	instance := h.newInstance(contract.MaxLimits)
+	header := &pb.SyscallHeader{Ctxid: instance.ctx.ID}
+	bytesCpu := func(msgs ...proto.Message) int64 {
+		var size int
+		for _, msg := range msgs {
+			size += proto.Size(msg)
+		}
+		return int64(size) * byteCpu
+	}
+
+	var expect int64
+	for _, key := range []string{"a", "b", "c"} {
+		req := &pb.PutRequest{Header: header, Key: []byte(key), Value: []byte("value")}
+		resp, err := h.client.PutObject(context.TODO(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect += putCpu + bytesCpu(req, resp)
+	}
+	getReq := &pb.GetRequest{Header: header, Key: []byte("a")}
+	getResp, err := h.client.GetObject(context.TODO(), getReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect += getCpu + bytesCpu(getReq, getResp)
+	iterReq := &pb.IteratorRequest{Header: header, Start: []byte("a"), Limit: []byte("z")}
+	iterResp, err := h.client.NewIterator(context.TODO(), iterReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(iterResp.GetItems()) != 3 {
+		t.Fatalf("expect 3 items, got %d", len(iterResp.GetItems()))
+	}
+	expect += syscallCpu + 3*iteratorItemCpu + bytesCpu(iterReq, iterResp)
+
+	if used := instance.ResourceUsed(); used.Cpu != expect || used.XFee != 0 {
+		t.Errorf("expect cpu %d, got %+v", expect, used)
+	}
+
+	// 未登记的ctxid不计费
+	instance.Release()
+	if _, err := h.client.GetObject(context.TODO(), getReq); err != nil {
+		t.Fatal(err)
+	}
+	if used := instance.ResourceUsed(); used.Cpu != expect {
+		t.Errorf("released instance should not be charged, got %d", used.Cpu)
+	}
+}
+
+func TestSyscallMeteringAbort(t *testing.T) {
+	h := newMeteringHelper(t)
+	limits := contract.MaxLimits
+	limits.Cpu = putCpu
+	instance := h.newInstance(limits)
+	ctx, cancel := instance.execContext()
+	defer cancel()
+
+	_, err := h.client.PutObject(context.TODO(), &pb.PutRequest{
+		Header: &pb.SyscallHeader{Ctxid: instance.ctx.ID},
+		Key:    []byte("a"),
+		Value:  []byte("value"),
+	})
+	if err == nil {
+		t.Fatal("syscall exceeding limits should fail")
+	}
+	if instance.aborted() != ErrResourceExceeds.Error() {
+		t.Errorf("instance should be aborted, got %q", instance.aborted())
+	}
+	if ctx.Err() != context.Canceled {
+		t.Error("running call should be canceled")
+	}
+}
+
+func TestExecTimeout(t *testing.T) {
+	h := newMeteringHelper(t)
+	h.cfg.ExecTimeout = 10
+	ctx, cancel := h.newInstance(contract.MaxLimits).execContext()
+	defer cancel()
+	<-ctx.Done()
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("expect deadline exceeded, got %v", ctx.Err())
+	}
+}
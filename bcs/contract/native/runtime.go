@@ -16,3 +16,9 @@ type Process interface {
 	// Stop 停止进程，如果在超时时间内进程没有退出则强制杀死进程
 	Stop(timeout time.Duration) error
 }
+
+// ResourceReporter 能够统计合约进程资源占用的Process实现该接口
+type ResourceReporter interface {
+	// ResourceUsage 返回进程启动以来累计使用的CPU时间(微秒)和当前占用的内存(字节)
+	ResourceUsage() (cpu int64, memory int64, err error)
+}
@@ -0,0 +1,94 @@
+package native
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/go-units"
+	log "github.com/xuperchain/log15"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+)
+
+const (
+	// defaultSandboxCgroupRoot 未配置CgroupRoot时使用的cgroup v2目录
+	defaultSandboxCgroupRoot = "/sys/fs/cgroup/xchain-native"
+)
+
+var (
+	// defaultSandboxMounts 未配置Mounts时只读挂载到沙箱中的运行时目录
+	defaultSandboxMounts = []string{"/bin", "/lib", "/lib64", "/usr"}
+)
+
+// SandboxProcess is the process running in linux namespaces, limited by cgroup v2 and seccomp
+// 沙箱中的init进程通过unix socket转发节点与合约之间的rpc，合约所在的网络命名空间只有lo
+type SandboxProcess struct {
+	basedir   string
+	startcmd  *exec.Cmd
+	codePort  int
+	rundir    string
+	chainSock string
+	cfg       *contract.NativeSandboxConfig
+
+	cmd    *exec.Cmd
+	done   chan error
+	cgroup *sandboxCgroup
+	log.Logger
+}
+
+// useSandbox 未启用docker且启用了沙箱时使用SandboxProcess
+func useSandbox(cfg *contract.NativeConfig) bool {
+	return !cfg.Docker.Enable && cfg.Sandbox.Enable
+}
+
+// sandboxCodeSock 节点访问沙箱中合约rpc的unix socket
+func sandboxCodeSock(rundir string) string {
+	return filepath.Join(rundir, "code.sock")
+}
+
+// sandboxChainSock 从unix://前缀的chainAddr中解析节点syscall服务的unix socket
+func sandboxChainSock(chainAddr string) string {
+	return strings.TrimPrefix(chainAddr, "unix://")
+}
+
+func (s *SandboxProcess) resourceConfig() (int64, int64, error) {
+	const cpuPeriod = 100000
+
+	var cpuLimit, memLimit int64
+	cpuLimit = int64(cpuPeriod * s.cfg.Cpus)
+	if s.cfg.Memory != "" {
+		var err error
+		memLimit, err = units.RAMInBytes(s.cfg.Memory)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return cpuLimit, memLimit, nil
+}
+
+// Start implements process interface
+func (s *SandboxProcess) Start() error {
+	return s.start()
+}
+
+// Stop implements process interface
+func (s *SandboxProcess) Stop(timeout time.Duration) error {
+	// init进程收到SIGTERM后转发给合约进程，init退出时内核杀死pid命名空间中的所有进程
+	s.cmd.Process.Signal(syscall.SIGTERM)
+	var err error
+	select {
+	case err = <-s.done:
+	case <-time.After(timeout):
+		s.cmd.Process.Kill()
+		err = <-s.done
+	}
+	s.Info("stop sandbox success", "pid", s.cmd.Process.Pid)
+	if s.cgroup != nil {
+		s.cgroup.remove()
+		s.cgroup = nil
+	}
+	return err
+}
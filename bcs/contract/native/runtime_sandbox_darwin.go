@@ -0,0 +1,19 @@
+package native
+
+import (
+	"errors"
+)
+
+// sandboxCgroup cgroup仅在linux上可用
+type sandboxCgroup struct{}
+
+func (c *sandboxCgroup) remove() {}
+
+func (s *SandboxProcess) start() error {
+	return errors.New("native sandbox is only supported on linux")
+}
+
+// ResourceUsage implements ResourceReporter
+func (s *SandboxProcess) ResourceUsage() (int64, int64, error) {
+	return 0, 0, errors.New("native sandbox is only supported on linux")
+}
@@ -0,0 +1,240 @@
+package native
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const (
+	// sandboxInitArg 以此为argv[0]重新执行节点程序时，进程作为沙箱的init进程运行，见sandboxInit
+	sandboxInitArg = "xchain-native-sandbox-init"
+	sandboxSpecEnv = "XCHAIN_SANDBOX_SPEC"
+)
+
+// sandboxSpec 由节点传递给沙箱init进程的启动参数
+type sandboxSpec struct {
+	// Path和Args为合约的启动命令
+	Path string
+	Args []string
+	// Basedir 合约目录，只读挂载到沙箱中的相同路径
+	Basedir string
+	Mounts  []string
+	// Rootfs 宿主机上的空目录，作为沙箱rootfs的挂载点
+	Rootfs string
+	// CodePort 合约在沙箱网络命名空间中监听的端口，CodeSock为节点一侧对应的unix socket
+	CodePort int
+	CodeSock string
+	// ChainSock 节点syscall服务的unix socket
+	ChainSock string
+}
+
+func (s *SandboxProcess) start() error {
+	mounts := s.cfg.Mounts
+	if len(mounts) == 0 {
+		mounts = defaultSandboxMounts
+	}
+	spec := &sandboxSpec{
+		Path:      s.startcmd.Path,
+		Args:      s.startcmd.Args,
+		Basedir:   s.basedir,
+		Mounts:    mounts,
+		Rootfs:    filepath.Join(s.rundir, "rootfs"),
+		CodePort:  s.codePort,
+		CodeSock:  sandboxCodeSock(s.rundir),
+		ChainSock: s.chainSock,
+	}
+	if err := os.MkdirAll(spec.Rootfs, 0755); err != nil {
+		return err
+	}
+	buf, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	uid, gid := os.Getuid(), os.Getgid()
+	attr := &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID |
+			syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+		UidMappings:                []syscall.SysProcIDMap{{ContainerID: 0, HostID: uid, Size: 1}},
+		GidMappings:                []syscall.SysProcIDMap{{ContainerID: 0, HostID: gid, Size: 1}},
+		GidMappingsEnableSetgroups: false,
+		Setsid:                     true,
+	}
+
+	cpuLimit, memLimit, err := s.resourceConfig()
+	if err != nil {
+		return err
+	}
+	if cpuLimit > 0 || memLimit > 0 {
+		root := s.cfg.CgroupRoot
+		if root == "" {
+			root = defaultSandboxCgroupRoot
+		}
+		s.cgroup, err = newSandboxCgroup(root, filepath.Base(s.basedir), cpuLimit, memLimit)
+		if err != nil {
+			return fmt.Errorf("create sandbox cgroup error:%s", err)
+		}
+		attr.UseCgroupFD = true
+		attr.CgroupFD = s.cgroup.fd
+	}
+
+	cmd := &exec.Cmd{
+		Path:        "/proc/self/exe",
+		Args:        []string{sandboxInitArg},
+		Env:         []string{sandboxSpecEnv + "=" + string(buf)},
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+		SysProcAttr: attr,
+	}
+	if err := cmd.Start(); err != nil {
+		if s.cgroup != nil {
+			s.cgroup.remove()
+			s.cgroup = nil
+		}
+		return err
+	}
+	s.Info("start sandbox success", "pid", cmd.Process.Pid)
+	s.cmd = cmd
+	s.done = make(chan error, 1)
+	go func() {
+		s.done <- cmd.Wait()
+	}()
+	return nil
+}
+
+// ResourceUsage implements ResourceReporter
+// 使用cgroup时读取cgroup的统计，否则累加沙箱中所有进程的/proc统计
+func (s *SandboxProcess) ResourceUsage() (int64, int64, error) {
+	if s.cgroup != nil {
+		return s.cgroup.usage()
+	}
+	return procTreeUsage(s.cmd.Process.Pid)
+}
+
+// sandboxCgroup 每个合约进程独占的cgroup v2
+type sandboxCgroup struct {
+	dir string
+	fd  int
+}
+
+func newSandboxCgroup(root, name string, cpuLimit, memLimit int64) (*sandboxCgroup, error) {
+	const cpuPeriod = 100000
+
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+	// 子cgroup需要父cgroup开启cpu和memory控制器，已开启或无权限时忽略错误，由下面写入限制时报错
+	os.WriteFile(filepath.Join(root, "cgroup.subtree_control"), []byte("+cpu +memory"), 0644)
+	dir, err := os.MkdirTemp(root, name+"-")
+	if err != nil {
+		return nil, err
+	}
+	cg := &sandboxCgroup{dir: dir, fd: -1}
+	if cpuLimit > 0 {
+		err = cg.write("cpu.max", fmt.Sprintf("%d %d", cpuLimit, cpuPeriod))
+	}
+	if err == nil && memLimit > 0 {
+		err = cg.write("memory.max", strconv.FormatInt(memLimit, 10))
+	}
+	if err == nil {
+		cg.fd, err = syscall.Open(dir, syscall.O_DIRECTORY|syscall.O_RDONLY|syscall.O_CLOEXEC, 0)
+	}
+	if err != nil {
+		cg.remove()
+		return nil, err
+	}
+	return cg, nil
+}
+
+func (c *sandboxCgroup) write(file, value string) error {
+	return os.WriteFile(filepath.Join(c.dir, file), []byte(value), 0644)
+}
+
+func (c *sandboxCgroup) usage() (int64, int64, error) {
+	stat, err := os.ReadFile(filepath.Join(c.dir, "cpu.stat"))
+	if err != nil {
+		return 0, 0, err
+	}
+	var cpu int64
+	scanner := bufio.NewScanner(bytes.NewReader(stat))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "usage_usec" {
+			cpu, err = strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	current, err := os.ReadFile(filepath.Join(c.dir, "memory.current"))
+	if err != nil {
+		return 0, 0, err
+	}
+	memory, err := strconv.ParseInt(strings.TrimSpace(string(current)), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cpu, memory, nil
+}
+
+// remove 删除cgroup，调用前cgroup中的进程需要全部退出
+func (c *sandboxCgroup) remove() {
+	if c.fd >= 0 {
+		syscall.Close(c.fd)
+	}
+	os.Remove(c.dir)
+}
+
+// procTreeUsage 累加pid及其所有子孙进程的CPU时间(微秒)和常驻内存(字节)
+func procTreeUsage(pid int) (int64, int64, error) {
+	// /proc/[pid]/stat中的CPU时间以USER_HZ为单位，linux上固定为100
+	const usecPerTick = 1000000 / 100
+
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, 0, err
+	}
+	// comm字段可能包含空格，从最后一个')'之后开始解析，utime和stime为第14、15个字段
+	fields := strings.Fields(string(stat[bytes.LastIndexByte(stat, ')')+1:]))
+	if len(fields) < 13 {
+		return 0, 0, fmt.Errorf("bad stat of process %d", pid)
+	}
+	utime, _ := strconv.ParseInt(fields[11], 10, 64)
+	stime, _ := strconv.ParseInt(fields[12], 10, 64)
+	cpu := (utime + stime) * usecPerTick
+
+	var memory int64
+	statm, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+	if err != nil {
+		return 0, 0, err
+	}
+	if fields := strings.Fields(string(statm)); len(fields) > 1 {
+		rss, _ := strconv.ParseInt(fields[1], 10, 64)
+		memory = rss * int64(os.Getpagesize())
+	}
+
+	children, err := os.ReadFile(fmt.Sprintf("/proc/%d/task/%d/children", pid, pid))
+	if err != nil {
+		return cpu, memory, nil
+	}
+	for _, child := range strings.Fields(string(children)) {
+		childPid, err := strconv.Atoi(child)
+		if err != nil {
+			continue
+		}
+		// 子进程可能在统计过程中退出
+		if childCpu, childMem, err := procTreeUsage(childPid); err == nil {
+			cpu += childCpu
+			memory += childMem
+		}
+	}
+	return cpu, memory, nil
+}
@@ -0,0 +1,176 @@
+package native
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pbrpc"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestMain(m *testing.M) {
+	// 测试程序被复制为沙箱中的合约运行
+	if os.Getenv("XCHAIN_CODE_PORT") != "" {
+		runSandboxContract()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// sandboxContract 在Call中检查沙箱的隔离效果
+type sandboxContract struct {
+	pbrpc.UnimplementedNativeCodeServer
+	chainAddr string
+}
+
+func (s *sandboxContract) Ping(ctx context.Context, in *pb.PingRequest) (*pb.PingResponse, error) {
+	return new(pb.PingResponse), nil
+}
+
+func (s *sandboxContract) Call(ctx context.Context, in *pb.NativeCallRequest) (*pb.NativeCallResponse, error) {
+	// 消耗一些CPU
+	for deadline := time.Now().Add(50 * time.Millisecond); time.Now().Before(deadline); {
+	}
+	if os.Getppid() != 1 {
+		return nil, fmt.Errorf("contract should be child of sandbox init, ppid %d", os.Getppid())
+	}
+	if _, err := os.Stat("/etc"); !os.IsNotExist(err) {
+		return nil, errors.New("host filesystem should not be visible")
+	}
+	if err := os.WriteFile("counter", nil, 0644); err == nil {
+		return nil, errors.New("contract dir should be read-only")
+	}
+	if err := syscall.Unshare(syscall.CLONE_NEWUSER); err != syscall.EPERM {
+		return nil, fmt.Errorf("unshare should be denied by seccomp, got %v", err)
+	}
+	ifaces, err := net.Interfaces()
+	if err != nil || len(ifaces) != 1 || ifaces[0].Name != "lo" {
+		return nil, fmt.Errorf("sandbox should only have lo, got %v %v", ifaces, err)
+	}
+	conn, err := grpc.Dial(strings.TrimPrefix(s.chainAddr, "tcp://"), grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_, err = pbrpc.NewSyscallClient(conn).PutObject(ctx, new(pb.PutRequest))
+	if status.Code(err) != codes.Unimplemented {
+		return nil, fmt.Errorf("syscall service should be reachable, got %v", err)
+	}
+	return new(pb.NativeCallResponse), nil
+}
+
+func runSandboxContract() {
+	listener, err := net.Listen("tcp", "127.0.0.1:"+os.Getenv("XCHAIN_CODE_PORT"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	server := grpc.NewServer()
+	pbrpc.RegisterNativeCodeServer(server, &sandboxContract{chainAddr: os.Getenv("XCHAIN_CHAIN_ADDR")})
+	server.Serve(listener)
+}
+
+func TestSandboxProcess(t *testing.T) {
+	if resp, err := exec.Command("unshare", "--user", "--map-root-user", "--mount", "--pid", "--net", "--fork", "true").CombinedOutput(); err != nil {
+		t.Skip("user namespace not available:", string(resp), err)
+	}
+
+	// 节点一侧的syscall服务
+	syscallDir := t.TempDir()
+	sock := filepath.Join(syscallDir, "syscall.sock")
+	listener, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	pbrpc.RegisterSyscallServer(server, new(pbrpc.UnimplementedSyscallServer))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	basedir := t.TempDir()
+	desc := &protos.WasmCodeDesc{
+		Runtime: "go",
+		Digest:  []byte("sandboxtest"),
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := os.ReadFile(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(basedir, nativeCodeFileName(desc)), code, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err := newContractProcess(&contract.NativeConfig{
+		Driver:      "native",
+		StopTimeout: 5,
+		Enable:      true,
+		Sandbox: contract.NativeSandboxConfig{
+			Enable: true,
+		},
+	}, "sandboxtest", basedir, "unix://"+sock, desc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Stop()
+
+	instance := newNativeVmInstance(&bridge.Context{ID: 1}, cp)
+	if err := instance.Exec(); err != nil {
+		t.Fatal(err)
+	}
+	cpu, memory, err := cp.process.(ResourceReporter).ResourceUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpu <= 0 || memory <= 0 {
+		t.Errorf("resource usage should be measured, got cpu %d memory %d", cpu, memory)
+	}
+	// 测量值与节点负载有关，未启用计量时只收取固定的XFee
+	if used := instance.ResourceUsed(); used != (contract.Limits{XFee: 1}) {
+		t.Errorf("measured resource should not be charged, got %+v", used)
+	}
+}
+
+func TestUnixRpcServerCleanup(t *testing.T) {
+	server := grpc.NewServer()
+	creator := &nativeCreator{}
+	addr, err := creator.startUnixRpcServer(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Dir(strings.TrimPrefix(addr, "unix://"))
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	server.Stop()
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("syscall dir %s not removed after server stopped", dir)
+}
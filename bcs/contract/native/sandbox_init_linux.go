@@ -0,0 +1,255 @@
+package native
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// sandboxRunDir 节点syscall socket所在目录在沙箱中的挂载点
+	sandboxRunDir = "/run/xchain"
+)
+
+func init() {
+	if len(os.Args) == 0 || os.Args[0] != sandboxInitArg {
+		return
+	}
+	if err := sandboxInit(); err != nil {
+		fmt.Fprintln(os.Stderr, "native sandbox init error:", err)
+		os.Exit(1)
+	}
+}
+
+// sandboxInit 作为沙箱中pid为1的进程运行，搭建rootfs和网络后启动合约进程，合约退出时随之退出
+// 节点与合约之间的两个方向的rpc分别由init进程转发:
+// 节点 -> CodeSock(unix) -> init -> 127.0.0.1:CodePort -> 合约
+// 合约 -> 127.0.0.1:随机端口 -> init -> ChainSock(unix) -> 节点
+func sandboxInit() error {
+	runtime.LockOSThread()
+	var spec sandboxSpec
+	if err := json.Unmarshal([]byte(os.Getenv(sandboxSpecEnv)), &spec); err != nil {
+		return err
+	}
+
+	// CodeSock位于宿主机路径，需要在切换rootfs之前监听
+	os.Remove(spec.CodeSock)
+	codeListener, err := net.Listen("unix", spec.CodeSock)
+	if err != nil {
+		return err
+	}
+	if err := setupRootfs(&spec); err != nil {
+		return err
+	}
+	if err := setupLoopback(); err != nil {
+		return err
+	}
+	chainListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	chainPort := chainListener.Addr().(*net.TCPAddr).Port
+	if err := loadSeccomp(); err != nil {
+		return err
+	}
+
+	cmd := &exec.Cmd{
+		Path: spec.Path,
+		Args: spec.Args,
+		Dir:  spec.Basedir,
+		Env: []string{
+			"PATH=/usr/local/bin:/usr/bin:/bin",
+			"XCHAIN_PING_TIMEOUT=" + strconv.Itoa(pingTimeoutSecond),
+			"XCHAIN_CODE_PORT=" + strconv.Itoa(spec.CodePort),
+			"XCHAIN_CHAIN_ADDR=tcp://127.0.0.1:" + strconv.Itoa(chainPort),
+		},
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() {
+		for sig := range signals {
+			cmd.Process.Signal(sig)
+		}
+	}()
+
+	codeAddr := "127.0.0.1:" + strconv.Itoa(spec.CodePort)
+	go serveProxy(codeListener, func() (net.Conn, error) {
+		return net.Dial("tcp", codeAddr)
+	})
+	chainSock := filepath.Join(sandboxRunDir, filepath.Base(spec.ChainSock))
+	go serveProxy(chainListener, func() (net.Conn, error) {
+		return net.Dial("unix", chainSock)
+	})
+
+	cmd.Wait()
+	os.Exit(cmd.ProcessState.ExitCode())
+	return nil
+}
+
+// setupRootfs 在tmpfs上构建只读的rootfs，并切换为沙箱的根目录
+func setupRootfs(spec *sandboxSpec) error {
+	// 挂载事件不传播回宿主机
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("make mounts private error:%s", err)
+	}
+	root := spec.Rootfs
+	if err := syscall.Mount("tmpfs", root, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=0755,size=1m"); err != nil {
+		return fmt.Errorf("mount rootfs error:%s", err)
+	}
+	// /tmp需要先于其他目录挂载，避免覆盖位于/tmp中的合约目录
+	tmp := filepath.Join(root, "tmp")
+	if err := os.MkdirAll(tmp, 0777); err != nil {
+		return err
+	}
+	if err := syscall.Mount("tmpfs", tmp, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=1777,size=64m"); err != nil {
+		return fmt.Errorf("mount tmp error:%s", err)
+	}
+	for _, m := range spec.Mounts {
+		if _, err := os.Stat(m); os.IsNotExist(err) {
+			continue
+		}
+		if err := bindMount(m, filepath.Join(root, m), true); err != nil {
+			return err
+		}
+	}
+	if err := bindMount(spec.Basedir, filepath.Join(root, spec.Basedir), true); err != nil {
+		return err
+	}
+	for _, dev := range []string{"/dev/null", "/dev/zero", "/dev/random", "/dev/urandom"} {
+		if err := bindMount(dev, filepath.Join(root, dev), false); err != nil {
+			return err
+		}
+	}
+	if err := bindMount(filepath.Dir(spec.ChainSock), filepath.Join(root, sandboxRunDir), false); err != nil {
+		return err
+	}
+	// 节点所在的容器可能屏蔽了/proc的部分路径，此时无法挂载新的proc，不影响合约运行
+	proc := filepath.Join(root, "proc")
+	if err := os.MkdirAll(proc, 0555); err != nil {
+		return err
+	}
+	syscall.Mount("proc", proc, "proc", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, "")
+
+	oldroot := filepath.Join(root, ".oldroot")
+	if err := os.MkdirAll(oldroot, 0700); err != nil {
+		return err
+	}
+	if err := syscall.PivotRoot(root, oldroot); err != nil {
+		return fmt.Errorf("pivot root error:%s", err)
+	}
+	if err := os.Chdir("/"); err != nil {
+		return err
+	}
+	if err := syscall.Unmount("/.oldroot", syscall.MNT_DETACH); err != nil {
+		return fmt.Errorf("unmount old root error:%s", err)
+	}
+	os.Remove("/.oldroot")
+	flags := uintptr(syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY | syscall.MS_NOSUID | syscall.MS_NODEV)
+	if err := syscall.Mount("", "/", "", flags, ""); err != nil {
+		return fmt.Errorf("remount rootfs read-only error:%s", err)
+	}
+	return nil
+}
+
+// bindMount 将src绑定挂载到dst，readonly时重新以只读方式挂载
+func bindMount(src, dst string, readonly bool) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		err = os.MkdirAll(dst, 0755)
+	} else if err = os.MkdirAll(filepath.Dir(dst), 0755); err == nil {
+		var f *os.File
+		f, err = os.OpenFile(dst, os.O_CREATE|os.O_RDONLY, 0644)
+		if err == nil {
+			f.Close()
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := syscall.Mount(src, dst, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("bind mount %s error:%s", src, err)
+	}
+	if !readonly {
+		return nil
+	}
+	// 用户命名空间中重新挂载时必须保留宿主机上被锁定的挂载选项
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dst, &st); err != nil {
+		return err
+	}
+	locked := uintptr(st.Flags) & (syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC |
+		syscall.MS_NOATIME | syscall.MS_NODIRATIME | syscall.MS_RELATIME)
+	flags := syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY | locked
+	if err := syscall.Mount("", dst, "", flags, ""); err != nil {
+		return fmt.Errorf("remount %s read-only error:%s", src, err)
+	}
+	return nil
+}
+
+// setupLoopback 启用新网络命名空间中的lo，沙箱中没有其他网络设备
+func setupLoopback() error {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(fd)
+	var ifr struct {
+		Name  [syscall.IFNAMSIZ]byte
+		Flags uint16
+		_     [22]byte
+	}
+	copy(ifr.Name[:], "lo")
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), syscall.SIOCGIFFLAGS, uintptr(unsafe.Pointer(&ifr))); errno != 0 {
+		return fmt.Errorf("get lo flags error:%s", errno)
+	}
+	ifr.Flags |= syscall.IFF_UP | syscall.IFF_RUNNING
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), syscall.SIOCSIFFLAGS, uintptr(unsafe.Pointer(&ifr))); errno != 0 {
+		return fmt.Errorf("set lo up error:%s", errno)
+	}
+	return nil
+}
+
+// serveProxy 将listener上的每个连接转发到dial建立的连接
+func serveProxy(listener net.Listener, dial func() (net.Conn, error)) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			target, err := dial()
+			if err != nil {
+				return
+			}
+			defer target.Close()
+			done := make(chan struct{}, 2)
+			go func() {
+				io.Copy(target, conn)
+				done <- struct{}{}
+			}()
+			go func() {
+				io.Copy(conn, target)
+				done <- struct{}{}
+			}()
+			<-done
+		}()
+	}
+}
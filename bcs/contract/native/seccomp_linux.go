@@ -0,0 +1,139 @@
+package native
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	seccompSetModeFilter = 1
+	seccompFlagTsync     = 1
+
+	seccompRetKillProcess = 0x80000000
+	seccompRetErrno       = 0x00050000
+	seccompRetAllow       = 0x7fff0000
+
+	// struct seccomp_data中各字段的偏移，参数取低32位(小端)
+	seccompDataNr   = 0
+	seccompDataArch = 4
+	seccompDataArg0 = 16
+	seccompDataArg1 = 24
+
+	// sandboxCloneFlags 合约不能创建新的命名空间
+	sandboxCloneFlags = syscall.CLONE_NEWNS | syscall.CLONE_NEWUTS | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUSER |
+		syscall.CLONE_NEWPID | syscall.CLONE_NEWNET | unix.CLONE_NEWCGROUP
+)
+
+// sandboxSyscalls 沙箱中允许使用的系统调用，包括init进程自身和合约进程，其余调用返回EPERM
+// 挂载、命名空间、ptrace、bpf、内核模块、改变身份和时间等调用均不在其中
+var sandboxSyscalls = append([]uint32{
+	unix.SYS_READ, unix.SYS_WRITE, unix.SYS_READV, unix.SYS_WRITEV, unix.SYS_PREAD64, unix.SYS_PWRITE64,
+	unix.SYS_PREADV, unix.SYS_PWRITEV, unix.SYS_OPENAT, unix.SYS_OPENAT2, unix.SYS_CLOSE, unix.SYS_CLOSE_RANGE,
+	unix.SYS_FSTAT, unix.SYS_STATX, unix.SYS_LSEEK, unix.SYS_FCNTL, unix.SYS_FLOCK, unix.SYS_IOCTL,
+	unix.SYS_DUP, unix.SYS_DUP3, unix.SYS_PIPE2, unix.SYS_GETDENTS64, unix.SYS_GETCWD, unix.SYS_CHDIR,
+	unix.SYS_FCHDIR, unix.SYS_READLINKAT, unix.SYS_FACCESSAT, unix.SYS_FACCESSAT2, unix.SYS_MKDIRAT,
+	unix.SYS_UNLINKAT, unix.SYS_RENAMEAT, unix.SYS_RENAMEAT2, unix.SYS_SYMLINKAT, unix.SYS_LINKAT,
+	unix.SYS_FCHMOD, unix.SYS_FCHMODAT, unix.SYS_FTRUNCATE, unix.SYS_FSYNC, unix.SYS_FDATASYNC,
+	unix.SYS_FALLOCATE, unix.SYS_FADVISE64, unix.SYS_UTIMENSAT, unix.SYS_STATFS, unix.SYS_FSTATFS,
+	unix.SYS_GETXATTR, unix.SYS_LGETXATTR, unix.SYS_FGETXATTR, unix.SYS_SENDFILE, unix.SYS_SPLICE,
+	unix.SYS_TEE, unix.SYS_COPY_FILE_RANGE, unix.SYS_UMASK, unix.SYS_MEMFD_CREATE,
+
+	unix.SYS_MMAP, unix.SYS_MPROTECT, unix.SYS_MUNMAP, unix.SYS_MREMAP, unix.SYS_BRK, unix.SYS_MADVISE,
+	unix.SYS_MINCORE, unix.SYS_MSYNC, unix.SYS_MLOCK, unix.SYS_MUNLOCK, unix.SYS_MEMBARRIER,
+
+	unix.SYS_CLONE, unix.SYS_EXECVE, unix.SYS_EXIT, unix.SYS_EXIT_GROUP, unix.SYS_WAIT4, unix.SYS_WAITID,
+	unix.SYS_KILL, unix.SYS_TKILL, unix.SYS_TGKILL, unix.SYS_PIDFD_OPEN, unix.SYS_PIDFD_SEND_SIGNAL,
+	unix.SYS_GETPID, unix.SYS_GETPPID, unix.SYS_GETTID, unix.SYS_GETUID, unix.SYS_GETEUID, unix.SYS_GETGID,
+	unix.SYS_GETEGID, unix.SYS_GETRESUID, unix.SYS_GETRESGID, unix.SYS_GETGROUPS, unix.SYS_GETPGID,
+	unix.SYS_SETPGID, unix.SYS_GETSID, unix.SYS_SETSID, unix.SYS_CAPGET, unix.SYS_PRCTL, unix.SYS_PRLIMIT64,
+	unix.SYS_GETRLIMIT, unix.SYS_GETRUSAGE, unix.SYS_GETPRIORITY, unix.SYS_UNAME, unix.SYS_SYSINFO,
+	unix.SYS_TIMES, unix.SYS_SET_TID_ADDRESS, unix.SYS_SET_ROBUST_LIST, unix.SYS_GET_ROBUST_LIST,
+	unix.SYS_RSEQ, unix.SYS_GETRANDOM, unix.SYS_GETCPU, unix.SYS_RESTART_SYSCALL,
+
+	unix.SYS_FUTEX, unix.SYS_SCHED_YIELD, unix.SYS_SCHED_GETAFFINITY, unix.SYS_SCHED_SETAFFINITY,
+	unix.SYS_SCHED_GETPARAM, unix.SYS_SCHED_GETSCHEDULER, unix.SYS_SCHED_GET_PRIORITY_MAX,
+	unix.SYS_SCHED_GET_PRIORITY_MIN, unix.SYS_NANOSLEEP, unix.SYS_CLOCK_GETTIME, unix.SYS_CLOCK_GETRES,
+	unix.SYS_CLOCK_NANOSLEEP, unix.SYS_GETTIMEOFDAY, unix.SYS_GETITIMER, unix.SYS_SETITIMER,
+	unix.SYS_TIMER_CREATE, unix.SYS_TIMER_SETTIME, unix.SYS_TIMER_GETTIME, unix.SYS_TIMER_GETOVERRUN,
+	unix.SYS_TIMER_DELETE, unix.SYS_TIMERFD_CREATE, unix.SYS_TIMERFD_SETTIME, unix.SYS_TIMERFD_GETTIME,
+
+	unix.SYS_RT_SIGACTION, unix.SYS_RT_SIGPROCMASK, unix.SYS_RT_SIGRETURN, unix.SYS_RT_SIGPENDING,
+	unix.SYS_RT_SIGTIMEDWAIT, unix.SYS_RT_SIGQUEUEINFO, unix.SYS_RT_SIGSUSPEND, unix.SYS_SIGALTSTACK,
+	unix.SYS_SIGNALFD4, unix.SYS_EVENTFD2, unix.SYS_EPOLL_CREATE1, unix.SYS_EPOLL_CTL, unix.SYS_EPOLL_PWAIT,
+	unix.SYS_EPOLL_PWAIT2, unix.SYS_PPOLL, unix.SYS_PSELECT6, unix.SYS_INOTIFY_INIT1,
+
+	unix.SYS_SOCKET, unix.SYS_SOCKETPAIR, unix.SYS_CONNECT, unix.SYS_BIND, unix.SYS_LISTEN, unix.SYS_ACCEPT,
+	unix.SYS_ACCEPT4, unix.SYS_GETSOCKNAME, unix.SYS_GETPEERNAME, unix.SYS_SETSOCKOPT, unix.SYS_GETSOCKOPT,
+	unix.SYS_SENDTO, unix.SYS_RECVFROM, unix.SYS_SENDMSG, unix.SYS_RECVMSG, unix.SYS_SENDMMSG,
+	unix.SYS_RECVMMSG, unix.SYS_SHUTDOWN,
+}, sandboxArchSyscalls...)
+
+// loadSeccomp 为当前进程的所有线程加载系统调用白名单，并设置no_new_privs，过滤器随fork和exec继承
+func loadSeccomp() error {
+	if sandboxAuditArch == 0 {
+		return errors.New("seccomp is not supported on this architecture")
+	}
+	filter := seccompFilter()
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return err
+	}
+	prog := unix.SockFprog{
+		Len:    uint16(len(filter)),
+		Filter: &filter[0],
+	}
+	_, _, errno := unix.Syscall(unix.SYS_SECCOMP, seccompSetModeFilter, seccompFlagTsync, uintptr(unsafe.Pointer(&prog)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// seccompFilter 生成白名单的BPF程序
+func seccompFilter() []unix.SockFilter {
+	ld := func(offset uint32) unix.SockFilter {
+		return unix.SockFilter{Code: unix.BPF_LD | unix.BPF_W | unix.BPF_ABS, K: offset}
+	}
+	jump := func(op uint16, k uint32, jt, jf uint8) unix.SockFilter {
+		return unix.SockFilter{Code: unix.BPF_JMP | op | unix.BPF_K, K: k, Jt: jt, Jf: jf}
+	}
+	ret := func(k uint32) unix.SockFilter {
+		return unix.SockFilter{Code: unix.BPF_RET | unix.BPF_K, K: k}
+	}
+	deny := ret(seccompRetErrno | uint32(syscall.EPERM))
+	allow := ret(seccompRetAllow)
+
+	filter := []unix.SockFilter{
+		// 其他架构的系统调用号含义不同，直接杀死进程
+		ld(seccompDataArch),
+		jump(unix.BPF_JEQ, sandboxAuditArch, 1, 0),
+		ret(seccompRetKillProcess),
+		ld(seccompDataNr),
+	}
+	if sandboxSyscallLimit != 0 {
+		// 如x86_64上的x32调用
+		filter = append(filter, jump(unix.BPF_JGE, sandboxSyscallLimit, 0, 1), deny)
+	}
+	filter = append(filter,
+		// libc在clone3返回ENOSYS时退回到clone，从而可以检查clone的参数
+		jump(unix.BPF_JEQ, unix.SYS_CLONE3, 0, 1),
+		ret(seccompRetErrno|uint32(syscall.ENOSYS)),
+		jump(unix.BPF_JEQ, unix.SYS_CLONE, 0, 4),
+		ld(seccompDataArg0),
+		jump(unix.BPF_JSET, sandboxCloneFlags, 0, 1),
+		deny,
+		allow,
+		// 禁止通过TIOCSTI向节点的终端注入输入
+		jump(unix.BPF_JEQ, unix.SYS_IOCTL, 0, 4),
+		ld(seccompDataArg1),
+		jump(unix.BPF_JEQ, syscall.TIOCSTI, 0, 1),
+		deny,
+		allow,
+	)
+	for _, nr := range sandboxSyscalls {
+		filter = append(filter, jump(unix.BPF_JEQ, nr, 0, 1), allow)
+	}
+	return append(filter, deny)
+}
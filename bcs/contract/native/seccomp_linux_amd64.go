@@ -0,0 +1,22 @@
+package native
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// sandboxAuditArch AUDIT_ARCH_X86_64
+	sandboxAuditArch = 0xc000003e
+	// sandboxSyscallLimit 大于等于该值的为x32调用
+	sandboxSyscallLimit = 0x40000000
+)
+
+// sandboxArchSyscalls x86_64上保留的旧系统调用
+var sandboxArchSyscalls = []uint32{
+	unix.SYS_OPEN, unix.SYS_STAT, unix.SYS_LSTAT, unix.SYS_NEWFSTATAT, unix.SYS_ACCESS, unix.SYS_READLINK,
+	unix.SYS_MKDIR, unix.SYS_RMDIR, unix.SYS_UNLINK, unix.SYS_RENAME, unix.SYS_SYMLINK, unix.SYS_LINK,
+	unix.SYS_CHMOD, unix.SYS_CREAT, unix.SYS_GETDENTS, unix.SYS_DUP2, unix.SYS_PIPE, unix.SYS_POLL,
+	unix.SYS_SELECT, unix.SYS_EPOLL_CREATE, unix.SYS_EPOLL_WAIT, unix.SYS_EVENTFD, unix.SYS_SIGNALFD,
+	unix.SYS_INOTIFY_INIT, unix.SYS_FORK, unix.SYS_VFORK, unix.SYS_PAUSE, unix.SYS_ALARM, unix.SYS_TIME,
+	unix.SYS_ARCH_PRCTL, unix.SYS_GETPGRP, unix.SYS_UTIMES, unix.SYS_FUTIMESAT,
+}
@@ -0,0 +1,16 @@
+package native
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// sandboxAuditArch AUDIT_ARCH_AARCH64
+	sandboxAuditArch    = 0xc00000b7
+	sandboxSyscallLimit = 0
+)
+
+// sandboxArchSyscalls arm64上特有的系统调用
+var sandboxArchSyscalls = []uint32{
+	unix.SYS_FSTATAT,
+}
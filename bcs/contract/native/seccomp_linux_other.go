@@ -0,0 +1,12 @@
+//go:build linux && !amd64 && !arm64
+// +build linux,!amd64,!arm64
+
+package native
+
+const (
+	// 未适配的架构上沙箱无法启动
+	sandboxAuditArch    = 0
+	sandboxSyscallLimit = 0
+)
+
+var sandboxArchSyscalls []uint32
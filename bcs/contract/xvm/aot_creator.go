@@ -57,6 +57,9 @@ func newXVMCreator(creatorConfig *bridge.InstanceCreatorConfig) (bridge.Instance
 	if err != nil {
 		return nil, err
 	}
+	if creator.vmconfig != nil {
+		creator.cm.setCacheSize(&creator.vmconfig.XVM)
+	}
 	return creator, nil
 }
 
@@ -129,6 +132,19 @@ func (x *xvmCreator) CreateInstance(ctx *bridge.Context, cp bridge.ContractCodeP
 	return createInstance(ctx, code, x.config.SyscallService)
 }
 
+// Precompile implements bridge.Precompiler
+func (x *xvmCreator) Precompile(name string, cp bridge.ContractCodeProvider) {
+	x.precompile(name, cp, nil)
+}
+
+func (x *xvmCreator) precompile(name string, cp bridge.ContractCodeProvider, done func(error)) {
+	desc, err := cp.GetContractCodeDesc(name)
+	if err != nil {
+		return
+	}
+	x.cm.Precompile(name, desc, cp, done)
+}
+
 func (x *xvmCreator) RemoveCache(contractName string) {
 	x.cm.RemoveCode(contractName)
 }
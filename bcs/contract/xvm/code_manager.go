@@ -2,6 +2,7 @@ package xvm
 
 import (
 	"bytes"
+	"container/list"
 	"crypto/sha1"
 	"encoding/hex"
 	"encoding/json"
@@ -10,16 +11,33 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/lib/metrics"
 	"github.com/xuperchain/xupercore/protos"
 	"github.com/xuperchain/xvm/compile"
 	"github.com/xuperchain/xvm/exec"
 	"golang.org/x/sync/singleflight"
 )
 
+const (
+	// 内存中最多缓存的合约个数
+	defaultCodeCacheSize = 256
+	// 磁盘缓存的大小上限，单位为MB
+	defaultDiskCacheSize = 4096
+	// 同时进行的后台预编译个数
+	maxPrecompileWorkers = 1
+	// 磁盘缓存被其他版本并发更新时的重试次数
+	maxDiskCacheRetry = 3
+
+	// 运行时目录，不属于磁盘缓存
+	varDirName = "var"
+)
+
 type compileFunc func([]byte, string) error
 type makeExecCodeFunc func(libpath string) (exec.Code, error)
 
@@ -29,6 +47,11 @@ type contractCode struct {
 	Desc         protos.WasmCodeDesc
 }
 
+type diskCacheEntry struct {
+	size     int64
+	lastUsed time.Time
+}
+
 type codeManager struct {
 	basedir      string
 	rundir       string
@@ -36,17 +59,37 @@ type codeManager struct {
 	compileCode  compileFunc
 	makeExecCode makeExecCodeFunc
 
+	// 用于 metric 区分不同的虚拟机
+	module string
+	// 内存缓存的合约个数上限
+	codeCacheSize int
+	// 磁盘缓存的字节数上限
+	diskCacheSize int64
+
 	makeCacheLock singleflight.Group
+	// 同一个合约同时只有一个 goroutine 生成磁盘缓存
+	compileLock singleflight.Group
+	// 限制后台预编译的并发数
+	precompileSem chan struct{}
 
-	mutex sync.Mutex // protect codes
-	codes map[string]*contractCode
+	mutex sync.Mutex // protect fields below
+	// 按照最近使用排序的内存缓存
+	codes   map[string]*list.Element
+	codeLRU *list.List
+	// 磁盘缓存的使用情况
+	diskEntries map[string]*diskCacheEntry
+	diskUsed    int64
+	// 正在预编译的合约
+	precompiling map[string]bool
+	// 预编译失败的合约版本，避免重复编译
+	precompileFailed map[string][]byte
 }
 
 func newCodeManager(basedir string, compile compileFunc, makeExec makeExecCodeFunc) (*codeManager, error) {
-	runDirFull := filepath.Join(basedir, "var", "run")
+	runDirFull := filepath.Join(basedir, varDirName, "run")
 	// clean all contract.so file in the run dir
 	os.RemoveAll(runDirFull)
-	cacheDirFull := filepath.Join(basedir, "var", "cache")
+	cacheDirFull := filepath.Join(basedir, varDirName, "cache")
 	if err := os.MkdirAll(runDirFull, 0755); err != nil {
 		return nil, err
 	}
@@ -54,14 +97,92 @@ func newCodeManager(basedir string, compile compileFunc, makeExec makeExecCodeFu
 		return nil, err
 	}
 
-	return &codeManager{
-		basedir:      basedir,
-		rundir:       runDirFull,
-		cachedir:     cacheDirFull,
-		compileCode:  compile,
-		makeExecCode: makeExec,
-		codes:        make(map[string]*contractCode),
-	}, nil
+	c := &codeManager{
+		basedir:          basedir,
+		rundir:           runDirFull,
+		cachedir:         cacheDirFull,
+		compileCode:      compile,
+		makeExecCode:     makeExec,
+		module:           "xvm",
+		codeCacheSize:    defaultCodeCacheSize,
+		diskCacheSize:    defaultDiskCacheSize << 20,
+		precompileSem:    make(chan struct{}, maxPrecompileWorkers),
+		codes:            make(map[string]*list.Element),
+		codeLRU:          list.New(),
+		diskEntries:      make(map[string]*diskCacheEntry),
+		precompiling:     make(map[string]bool),
+		precompileFailed: make(map[string][]byte),
+	}
+	if err := c.loadDiskEntries(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// setCacheSize sets the limits of memory and disk cache, zero means default
+func (c *codeManager) setCacheSize(config *contract.XVMConfig) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if config.CodeCacheSize > 0 {
+		c.codeCacheSize = config.CodeCacheSize
+	}
+	if config.DiskCacheSize > 0 {
+		c.diskCacheSize = config.DiskCacheSize << 20
+	}
+}
+
+// loadDiskEntries 统计已有的磁盘缓存，最近使用时间取描述文件的修改时间
+func (c *codeManager) loadDiskEntries() error {
+	entries, err := os.ReadDir(c.basedir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == varDirName {
+			continue
+		}
+		name := entry.Name()
+		if !fileExists(filepath.Join(c.basedir, name, "code.desc")) &&
+			!fileExists(filepath.Join(c.basedir, name, "code.so")) {
+			continue
+		}
+		size, err := dirSize(filepath.Join(c.basedir, name))
+		if err != nil {
+			return err
+		}
+		lastUsed := time.Time{}
+		if stat, err := os.Stat(filepath.Join(c.basedir, name, "code.desc")); err == nil {
+			lastUsed = stat.ModTime()
+		}
+		c.diskEntries[name] = &diskCacheEntry{
+			size:     size,
+			lastUsed: lastUsed,
+		}
+		c.diskUsed += size
+	}
+	c.updateCacheMetrics()
+	return nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// updateCacheMetrics must be called with c.mutex held
+func (c *codeManager) updateCacheMetrics() {
+	metrics.ContractCodeCacheGauge.WithLabelValues(c.module, "memory").Set(float64(len(c.codes)))
+	metrics.ContractCodeCacheGauge.WithLabelValues(c.module, "disk").Set(float64(len(c.diskEntries)))
+	metrics.ContractCodeCacheBytesGauge.WithLabelValues(c.module).Set(float64(c.diskUsed))
 }
 
 func codeDescEqual(a, b *protos.WasmCodeDesc) bool {
@@ -71,19 +192,21 @@ func codeDescEqual(a, b *protos.WasmCodeDesc) bool {
 func (c *codeManager) lookupMemCache(name string, desc *protos.WasmCodeDesc) (*contractCode, bool) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	ccode, ok := c.codes[name]
+	elem, ok := c.codes[name]
 	if !ok {
 		return nil, false
 	}
+	ccode := elem.Value.(*contractCode)
 	if codeDescEqual(&ccode.Desc, desc) {
+		c.codeLRU.MoveToFront(elem)
 		return ccode, true
 	}
 	return nil, false
 }
 
+// makeMemCache 加载合约的磁盘缓存并放入内存缓存，由 makeCacheLock 保证同一个合约同时只有一个 goroutine 加载，
+// 复制和加载 so 文件不持有 c.mutex，避免阻塞其他合约的缓存查找
 func (c *codeManager) makeMemCache(name, libpath string, desc *protos.WasmCodeDesc) (*contractCode, error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
 	// 创建临时文件，这样每个合约版本独享一个so文件，不会相互影响
 	tmpfile := fmt.Sprintf("%s-%d-%d.so", name, time.Now().UnixNano(), rand.Int()%10000)
 	libpathFull := filepath.Join(c.rundir, tmpfile)
@@ -94,6 +217,7 @@ func (c *codeManager) makeMemCache(name, libpath string, desc *protos.WasmCodeDe
 
 	execCode, err := c.makeExecCode(libpathFull)
 	if err != nil {
+		os.Remove(libpathFull)
 		return nil, err
 	}
 	code := &contractCode{
@@ -101,10 +225,24 @@ func (c *codeManager) makeMemCache(name, libpath string, desc *protos.WasmCodeDe
 		ExecCode:     execCode,
 		Desc:         *desc,
 	}
+	// 被淘汰的合约在没有实例引用之后释放
 	runtime.SetFinalizer(code, func(c *contractCode) {
 		c.ExecCode.Release()
+		os.Remove(libpathFull)
 	})
-	c.codes[name] = code
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if elem, ok := c.codes[name]; ok {
+		c.codeLRU.Remove(elem)
+	}
+	c.codes[name] = c.codeLRU.PushFront(code)
+	for c.codeLRU.Len() > c.codeCacheSize {
+		elem := c.codeLRU.Back()
+		c.codeLRU.Remove(elem)
+		delete(c.codes, elem.Value.(*contractCode).ContractName)
+	}
+	c.updateCacheMetrics()
 
 	return code, nil
 }
@@ -136,6 +274,12 @@ func (c *codeManager) lookupDiskCache(name string, desc *protos.WasmCodeDesc) (s
 		localDesc.GetVmCompiler() != compile.Version {
 		return "", false
 	}
+
+	c.mutex.Lock()
+	if entry, ok := c.diskEntries[name]; ok {
+		entry.lastUsed = time.Now()
+	}
+	c.mutex.Unlock()
 	return libpath, true
 }
 
@@ -148,9 +292,23 @@ func (c *codeManager) makeDiskCache(name string, desc *protos.WasmCodeDesc, code
 	if err != nil {
 		return "", err
 	}
-
-	err = c.compileCode(codebuf, libpath)
+	// 先删除旧版本的描述文件，编译到临时文件后再替换，
+	// 避免其他 goroutine 读到旧版本描述对应的不完整的 so 文件
+	os.Remove(descpath)
+	tmplibpath := libpath + ".tmp"
+	beginTime := time.Now()
+	err = c.compileCode(codebuf, tmplibpath)
+	metrics.ContractCodeCompileHistogram.WithLabelValues(c.module).Observe(time.Since(beginTime).Seconds())
 	if err != nil {
+		metrics.ContractCodeCompileCounter.WithLabelValues(c.module, "Error").Inc()
+		os.RemoveAll(basedir)
+		c.removeDiskEntry(name)
+		return "", err
+	}
+	metrics.ContractCodeCompileCounter.WithLabelValues(c.module, "OK").Inc()
+	if err = os.Rename(tmplibpath, libpath); err != nil {
+		os.RemoveAll(basedir)
+		c.removeDiskEntry(name)
 		return "", err
 	}
 	localDesc := *desc
@@ -159,11 +317,100 @@ func (c *codeManager) makeDiskCache(name string, desc *protos.WasmCodeDesc, code
 	err = os.WriteFile(descpath, descbuf, 0600)
 	if err != nil {
 		os.RemoveAll(basedir)
+		c.removeDiskEntry(name)
 		return "", err
 	}
+
+	size, err := dirSize(basedir)
+	if err != nil {
+		return "", err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if entry, ok := c.diskEntries[name]; ok {
+		c.diskUsed -= entry.size
+	}
+	c.diskEntries[name] = &diskCacheEntry{
+		size:     size,
+		lastUsed: time.Now(),
+	}
+	c.diskUsed += size
+	c.evictDiskCache(name)
+	c.updateCacheMetrics()
 	return libpath, nil
 }
 
+func (c *codeManager) removeDiskEntry(name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if entry, ok := c.diskEntries[name]; ok {
+		c.diskUsed -= entry.size
+		delete(c.diskEntries, name)
+	}
+	c.updateCacheMetrics()
+}
+
+// evictDiskCache 按照最近使用时间淘汰磁盘缓存直到不超过上限，不会淘汰刚生成的 keep，
+// must be called with c.mutex held
+func (c *codeManager) evictDiskCache(keep string) {
+	if c.diskUsed <= c.diskCacheSize {
+		return
+	}
+	names := make([]string, 0, len(c.diskEntries))
+	for name := range c.diskEntries {
+		if name != keep {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return c.diskEntries[names[i]].lastUsed.Before(c.diskEntries[names[j]].lastUsed)
+	})
+	for _, name := range names {
+		if c.diskUsed <= c.diskCacheSize {
+			break
+		}
+		// 正在编译的合约由编译完成后重新统计
+		if c.precompiling[name] {
+			continue
+		}
+		os.RemoveAll(filepath.Join(c.basedir, name))
+		c.diskUsed -= c.diskEntries[name].size
+		delete(c.diskEntries, name)
+	}
+}
+
+// getDiskCache 返回合约对应版本的磁盘缓存，不存在时编译生成
+func (c *codeManager) getDiskCache(name string, desc *protos.WasmCodeDesc, getCode func() ([]byte, error)) (string, error) {
+	for i := 0; i < maxDiskCacheRetry; i++ {
+		libpath, ok := c.lookupDiskCache(name, desc)
+		if ok {
+			metrics.ContractCodeCacheCounter.WithLabelValues(c.module, "disk_hit").Inc()
+			return libpath, nil
+		}
+		// 返回编译的版本，共享的结果可能是其他版本的编译结果，此时需要重新检查
+		digest, err, _ := c.compileLock.Do(name, func() (interface{}, error) {
+			defer c.compileLock.Forget(name)
+			if _, ok := c.lookupDiskCache(name, desc); ok {
+				return desc.GetDigest(), nil
+			}
+			metrics.ContractCodeCacheCounter.WithLabelValues(c.module, "miss").Inc()
+			codebuf, err := getCode()
+			if err != nil {
+				return desc.GetDigest(), err
+			}
+			_, err = c.makeDiskCache(name, desc, codebuf)
+			return desc.GetDigest(), err
+		})
+		if !bytes.Equal(digest.([]byte), desc.GetDigest()) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("disk cache of contract %s is being updated", name)
+}
+
 func (c *codeManager) GetExecCode(name string, cp bridge.ContractCodeProvider) (*contractCode, error) {
 	desc, err := cp.GetContractCodeDesc(name)
 	if err != nil {
@@ -172,9 +419,24 @@ func (c *codeManager) GetExecCode(name string, cp bridge.ContractCodeProvider) (
 	execCode, ok := c.lookupMemCache(name, desc)
 	if ok {
 		// log.Debug("contract code hit memory cache", "contract", name)
+		metrics.ContractCodeCacheCounter.WithLabelValues(c.module, "mem_hit").Inc()
 		return execCode, nil
 	}
 
+	// 共享的结果可能是其他版本，此时需要重试
+	for i := 0; i < maxDiskCacheRetry; i++ {
+		ccode, err := c.makeCache(name, desc, cp)
+		if err != nil {
+			return nil, err
+		}
+		if codeDescEqual(&ccode.Desc, desc) {
+			return ccode, nil
+		}
+	}
+	return nil, fmt.Errorf("contract code of %s is being updated", name)
+}
+
+func (c *codeManager) makeCache(name string, desc *protos.WasmCodeDesc, cp bridge.ContractCodeProvider) (*contractCode, error) {
 	// Only allow one goroutine make disk and memory cache at given contract name
 	// other goroutine will block on the same contract name.
 	icode, err, _ := c.makeCacheLock.Do(name, func() (interface{}, error) {
@@ -188,19 +450,11 @@ func (c *codeManager) GetExecCode(name string, cp bridge.ContractCodeProvider) (
 		if ok {
 			return execCode, nil
 		}
-		libpath, ok := c.lookupDiskCache(name, desc)
-		if !ok {
-			// log.Debug("contract code need make disk cache", "contract", name)
-			codebuf, err := cp.GetContractCode(name)
-			if err != nil {
-				return nil, err
-			}
-			libpath, err = c.makeDiskCache(name, desc, codebuf)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			// log.Debug("contract code hit disk cache", "contract", name)
+		libpath, err := c.getDiskCache(name, desc, func() ([]byte, error) {
+			return cp.GetContractCode(name)
+		})
+		if err != nil {
+			return nil, err
 		}
 		return c.makeMemCache(name, libpath, desc)
 	})
@@ -210,11 +464,73 @@ func (c *codeManager) GetExecCode(name string, cp bridge.ContractCodeProvider) (
 	return icode.(*contractCode), nil
 }
 
+// Precompile 在后台为合约生成磁盘缓存，同一个合约同时只有一个预编译任务，
+// 编译失败的版本不再重试。done 在预编译结束后调用，可以为 nil，
+// 已经有磁盘缓存或者没有发起预编译时不会调用。
+func (c *codeManager) Precompile(name string, desc *protos.WasmCodeDesc, cp bridge.ContractCodeProvider, done func(error)) {
+	if _, ok := c.lookupDiskCache(name, desc); ok {
+		return
+	}
+	c.mutex.Lock()
+	if c.precompiling[name] || bytes.Equal(c.precompileFailed[name], desc.GetDigest()) {
+		c.mutex.Unlock()
+		return
+	}
+	c.precompiling[name] = true
+	c.mutex.Unlock()
+
+	// 合约代码在当前 goroutine 中读取，cp 可能不能在交易执行结束后使用
+	codebuf, err := cp.GetContractCode(name)
+	if err != nil {
+		// 读取失败不是合约代码的问题，不记录为编译失败
+		c.mutex.Lock()
+		delete(c.precompiling, name)
+		c.mutex.Unlock()
+		if done != nil {
+			done(err)
+		}
+		return
+	}
+	go func() {
+		c.precompileSem <- struct{}{}
+		defer func() {
+			<-c.precompileSem
+		}()
+		_, err := c.getDiskCache(name, desc, func() ([]byte, error) {
+			return codebuf, nil
+		})
+		c.finishPrecompile(name, desc, err, done)
+	}()
+}
+
+func (c *codeManager) finishPrecompile(name string, desc *protos.WasmCodeDesc, err error, done func(error)) {
+	c.mutex.Lock()
+	delete(c.precompiling, name)
+	if err != nil {
+		c.precompileFailed[name] = desc.GetDigest()
+	} else {
+		delete(c.precompileFailed, name)
+	}
+	c.mutex.Unlock()
+	if done != nil {
+		done(err)
+	}
+}
+
 func (c *codeManager) RemoveCode(name string) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	delete(c.codes, name)
+	if elem, ok := c.codes[name]; ok {
+		c.codeLRU.Remove(elem)
+		delete(c.codes, name)
+	}
+	if entry, ok := c.diskEntries[name]; ok {
+		c.diskUsed -= entry.size
+		delete(c.diskEntries, name)
+	}
+	delete(c.precompileFailed, name)
 	os.RemoveAll(filepath.Join(c.basedir, name))
+	c.updateCacheMetrics()
 }
 
 // not used now
@@ -1,10 +1,15 @@
 package xvm
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/protos"
 	"github.com/xuperchain/xvm/exec"
 )
@@ -159,3 +164,189 @@ func TestMakeCacheBlocking(t *testing.T) {
 	}
 
 }
+
+func TestMakeMemCacheNotBlocking(t *testing.T) {
+	release := make(chan struct{})
+	cm := newTestCodeManager(t, writeCodeFunc)
+	cm.makeExecCode = func(libpath string) (exec.Code, error) {
+		if strings.HasPrefix(filepath.Base(libpath), "slow-") {
+			<-release
+		}
+		return new(fakeCode), nil
+	}
+	cp := &memCodeProvider{
+		code: []byte("binary code"),
+		desc: &protos.WasmCodeDesc{
+			Digest: []byte("digest1"),
+		},
+	}
+	if _, err := cm.GetExecCode("c1", cp); err != nil {
+		t.Fatal(err)
+	}
+	// 加载 slow 的 so 文件期间，其他合约的内存缓存查找不被阻塞
+	go cm.GetExecCode("slow", cp)
+	time.Sleep(50 * time.Millisecond)
+	c1 := make(chan int)
+	go func() {
+		cm.GetExecCode("c1", cp)
+		close(c1)
+	}()
+	select {
+	case <-time.After(100 * time.Millisecond):
+		t.Error("wait timeout")
+	case <-c1:
+	}
+	close(release)
+}
+
+func newTestCodeManager(t *testing.T, compile compileFunc) *codeManager {
+	tmpdir := t.TempDir()
+	makeExecCodeFunc := func(libpath string) (exec.Code, error) {
+		return new(fakeCode), nil
+	}
+	cm, err := newCodeManager(tmpdir, compile, makeExecCodeFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cm
+}
+
+func writeCodeFunc(code []byte, output string) error {
+	return os.WriteFile(output, code, 0700)
+}
+
+func TestMemCacheEviction(t *testing.T) {
+	cm := newTestCodeManager(t, writeCodeFunc)
+	cm.setCacheSize(&contract.XVMConfig{CodeCacheSize: 2})
+
+	cp := &memCodeProvider{
+		code: []byte("binary code"),
+		desc: &protos.WasmCodeDesc{
+			Digest: []byte("digest1"),
+		},
+	}
+	for _, name := range []string{"c1", "c2", "c1", "c3"} {
+		if _, err := cm.GetExecCode(name, cp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// c2 最久没有使用，被淘汰
+	if _, ok := cm.lookupMemCache("c2", cp.desc); ok {
+		t.Error("expect c2 evicted from memory cache")
+	}
+	for _, name := range []string{"c1", "c3"} {
+		if _, ok := cm.lookupMemCache(name, cp.desc); !ok {
+			t.Errorf("expect %s in memory cache", name)
+		}
+	}
+	// 磁盘缓存不受影响
+	if _, ok := cm.lookupDiskCache("c2", cp.desc); !ok {
+		t.Error("expect c2 in disk cache")
+	}
+}
+
+func TestDiskCacheEviction(t *testing.T) {
+	cm := newTestCodeManager(t, writeCodeFunc)
+
+	cp := &memCodeProvider{
+		code: make([]byte, 1000),
+		desc: &protos.WasmCodeDesc{
+			Digest: []byte("digest1"),
+		},
+	}
+	if _, err := cm.GetExecCode("c1", cp); err != nil {
+		t.Fatal(err)
+	}
+	// 只能容纳两个合约的磁盘缓存
+	cm.mutex.Lock()
+	cm.diskCacheSize = cm.diskUsed * 2
+	cm.mutex.Unlock()
+
+	for _, name := range []string{"c2", "c1", "c3"} {
+		if _, err := cm.GetExecCode(name, cp); err != nil {
+			t.Fatal(err)
+		}
+		// 保证最近使用时间不同
+		time.Sleep(10 * time.Millisecond)
+	}
+	// c1 命中内存缓存，不会更新磁盘缓存的使用时间
+	if _, ok := cm.lookupDiskCache("c1", cp.desc); ok {
+		t.Error("expect c1 evicted from disk cache")
+	}
+	for _, name := range []string{"c2", "c3"} {
+		if _, ok := cm.lookupDiskCache(name, cp.desc); !ok {
+			t.Errorf("expect %s in disk cache", name)
+		}
+	}
+	if cm.diskUsed > cm.diskCacheSize || len(cm.diskEntries) != 2 {
+		t.Errorf("unexpected disk usage:%d entries:%d", cm.diskUsed, len(cm.diskEntries))
+	}
+
+	// 重新打开时统计已有的磁盘缓存
+	cm1, err := newCodeManager(cm.basedir, writeCodeFunc, cm.makeExecCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm1.diskUsed != cm.diskUsed || len(cm1.diskEntries) != 2 {
+		t.Errorf("unexpected disk usage after reload:%d entries:%d", cm1.diskUsed, len(cm1.diskEntries))
+	}
+}
+
+func TestPrecompile(t *testing.T) {
+	var compileCount int32
+	cm := newTestCodeManager(t, func(code []byte, output string) error {
+		atomic.AddInt32(&compileCount, 1)
+		if string(code) == "bad code" {
+			return errors.New("compile error")
+		}
+		return os.WriteFile(output, code, 0700)
+	})
+
+	cp := &memCodeProvider{
+		code: []byte("binary code"),
+		desc: &protos.WasmCodeDesc{
+			Digest: []byte("digest1"),
+		},
+	}
+	precompile := func(cp *memCodeProvider) error {
+		done := make(chan error, 1)
+		cm.Precompile("c1", cp.desc, cp, func(err error) {
+			done <- err
+		})
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(time.Second):
+			return errors.New("precompile timeout")
+		}
+	}
+	if err := precompile(cp); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cm.lookupDiskCache("c1", cp.desc); !ok {
+		t.Fatal("expect c1 in disk cache after precompile")
+	}
+	// 已经有磁盘缓存时不再编译
+	cm.Precompile("c1", cp.desc, cp, nil)
+	if _, err := cm.GetExecCode("c1", cp); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&compileCount); n != 1 {
+		t.Errorf("expect compile once, got %d", n)
+	}
+
+	// 编译失败的版本不再重试
+	badcp := &memCodeProvider{
+		code: []byte("bad code"),
+		desc: &protos.WasmCodeDesc{
+			Digest: []byte("digest2"),
+		},
+	}
+	if err := precompile(badcp); err == nil {
+		t.Fatal("expect precompile error")
+	}
+	cm.Precompile("c1", badcp.desc, badcp, nil)
+	if n := atomic.LoadInt32(&compileCount); n != 2 {
+		t.Errorf("expect compile twice, got %d", n)
+	}
+}
@@ -5,18 +5,20 @@ import (
 
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/metrics"
+	"github.com/xuperchain/xupercore/protos"
 )
 
+// HXVMCreator 先使用解释器执行合约，同时在后台编译，编译完成后使用编译后的代码执行
 type HXVMCreator struct {
 	tier0Creator *xvmInterpCreator
 	tier1Creator *xvmCreator
 	tier2Creator *xvmCreator
+
+	log *logs.LogFitter
 }
 
-// TODO @chenfengjin
-// 1. 添加 tier up 时的错误处理
-// 2. 避免重复编译，以节省计算资源
-// 3. 增加对应的 prometheus metric
 func newHXVMCreator(creatorConfig *bridge.InstanceCreatorConfig) (bridge.InstanceCreator, error) {
 	baseDir := creatorConfig.Basedir
 
@@ -28,15 +30,20 @@ func newHXVMCreator(creatorConfig *bridge.InstanceCreatorConfig) (bridge.Instanc
 	tier1Config.Basedir = filepath.Join(baseDir, "tier1")
 	tier2Config.Basedir = filepath.Join(baseDir, "tier2")
 
+	// 缓存配置对所有层级生效
+	var xvmConfig contract.XVMConfig
+	if wasmConfig, ok := creatorConfig.VMConfig.(*contract.WasmConfig); ok {
+		xvmConfig = wasmConfig.XVM
+	}
+	tier1XVMConfig := xvmConfig
+	tier1XVMConfig.OptLevel = 0
+	tier2XVMConfig := xvmConfig
+	tier2XVMConfig.OptLevel = 2
 	tier1Config.VMConfig = &contract.WasmConfig{
-		XVM: contract.XVMConfig{
-			OptLevel: 0,
-		},
+		XVM: tier1XVMConfig,
 	}
 	tier2Config.VMConfig = &contract.WasmConfig{
-		XVM: contract.XVMConfig{
-			OptLevel: 2,
-		},
+		XVM: tier2XVMConfig,
 	}
 
 	tier0Creator, err := newXVMInterpCreator(&tier0Config)
@@ -53,20 +60,48 @@ func newHXVMCreator(creatorConfig *bridge.InstanceCreatorConfig) (bridge.Instanc
 		return nil, err
 	}
 
+	// 日志未初始化时不记录 tier up 日志，只记录 metric
+	log, _ := logs.NewLogger("", "hxvm")
+
 	creator := &HXVMCreator{
 		tier0Creator: tier0Creator.(*xvmInterpCreator),
 		tier1Creator: tier1Creator.(*xvmCreator),
 		tier2Creator: tier2Creator.(*xvmCreator),
+		log:          log,
 	}
+	creator.tier0Creator.cm.module = "hxvm_tier0"
+	creator.tier1Creator.cm.module = "hxvm_tier1"
+	creator.tier2Creator.cm.module = "hxvm_tier2"
 	return creator, nil
 }
 
-func (creator *HXVMCreator) tierUp1(ctx *bridge.Context, cp bridge.ContractCodeProvider) {
-	creator.tier1Creator.CreateInstance(ctx, cp)
+// tierUp 在后台编译合约，同一个合约同时只有一个编译任务，编译失败的版本不再重试
+func (creator *HXVMCreator) tierUp(tier string, target *xvmCreator, name string, desc *protos.WasmCodeDesc,
+	cp bridge.ContractCodeProvider, done func()) {
+	target.cm.Precompile(name, desc, cp, func(err error) {
+		if err != nil {
+			if creator.log != nil {
+				creator.log.Warn("tier up contract error", "contract", name, "tier", tier, "error", err)
+			}
+			metrics.ContractCodeTierUpCounter.WithLabelValues(tier, "Error").Inc()
+			return
+		}
+		if creator.log != nil {
+			creator.log.Info("tier up contract", "contract", name, "tier", tier)
+		}
+		metrics.ContractCodeTierUpCounter.WithLabelValues(tier, "OK").Inc()
+		if done != nil {
+			done()
+		}
+	})
+}
+
+func (creator *HXVMCreator) tierUp1(name string, desc *protos.WasmCodeDesc, cp bridge.ContractCodeProvider) {
+	creator.tierUp("tier1", creator.tier1Creator, name, desc, cp, nil)
 }
 
-func (creator *HXVMCreator) tierUp2(ctx *bridge.Context, cp bridge.ContractCodeProvider) {
-	creator.tier2Creator.CreateInstance(ctx, cp)
+func (creator *HXVMCreator) tierUp2(name string, desc *protos.WasmCodeDesc, cp bridge.ContractCodeProvider) {
+	creator.tierUp("tier2", creator.tier2Creator, name, desc, cp, nil)
 }
 
 func (creator *HXVMCreator) CreateInstance(ctx *bridge.Context, cp bridge.ContractCodeProvider) (bridge.Instance, error) {
@@ -80,7 +115,7 @@ func (creator *HXVMCreator) CreateInstance(ctx *bridge.Context, cp bridge.Contra
 	}
 
 	if _, find := creator.tier1Creator.cm.lookupDiskCache(ctx.ContractName, codeDesc); find {
-		go creator.tierUp2(ctx, cp)
+		creator.tierUp2(ctx.ContractName, codeDesc, cp)
 		return creator.tier1Creator.CreateInstance(ctx, cp)
 	}
 
@@ -89,9 +124,26 @@ func (creator *HXVMCreator) CreateInstance(ctx *bridge.Context, cp bridge.Contra
 		return nil, err
 	}
 
-	go creator.tierUp1(ctx, cp)
-	return instance, err
+	creator.tierUp1(ctx.ContractName, codeDesc, cp)
+	return instance, nil
+}
 
+// Precompile implements bridge.Precompiler, it compiles tier1 code first and then tier2 code
+func (creator *HXVMCreator) Precompile(name string, cp bridge.ContractCodeProvider) {
+	codeDesc, err := cp.GetContractCodeDesc(name)
+	if err != nil {
+		return
+	}
+	if _, find := creator.tier2Creator.cm.lookupDiskCache(name, codeDesc); find {
+		return
+	}
+	if _, find := creator.tier1Creator.cm.lookupDiskCache(name, codeDesc); find {
+		creator.tierUp2(name, codeDesc, cp)
+		return
+	}
+	creator.tierUp("tier1", creator.tier1Creator, name, codeDesc, cp, func() {
+		creator.tierUp2(name, codeDesc, cp)
+	})
 }
 
 func (creator *HXVMCreator) RemoveCache(name string) {
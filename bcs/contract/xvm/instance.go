@@ -37,6 +37,7 @@ func createInstance(ctx *bridge.Context, code *contractCode, syscall *bridge.Sys
 		bridgeCtx: ctx,
 		execCtx:   execCtx,
 		desc:      code.Desc,
+		code:      code,
 	}
 	instance.InitDebugWriter(syscall)
 	return instance, nil
@@ -47,6 +48,8 @@ type xvmInstance struct {
 	execCtx   exec.Context
 	desc      protos.WasmCodeDesc
 	syscall   *bridge.SyscallService
+	// 持有合约代码的引用，避免执行过程中代码被缓存淘汰后释放
+	code *contractCode
 }
 
 func (x *xvmInstance) Exec() error {
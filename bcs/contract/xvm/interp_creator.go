@@ -3,6 +3,7 @@ package xvm
 import (
 	"os"
 
+	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
 	"github.com/xuperchain/xvm/exec"
 	"github.com/xuperchain/xvm/runtime/emscripten"
@@ -24,6 +25,10 @@ func newXVMInterpCreator(creatorConfig *bridge.InstanceCreatorConfig) (bridge.In
 	if err != nil {
 		return nil, err
 	}
+	creator.cm.module = "ixvm"
+	if vmconfig, ok := creatorConfig.VMConfig.(*contract.WasmConfig); ok {
+		creator.cm.setCacheSize(&vmconfig.XVM)
+	}
 	return creator, nil
 }
 
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 
+	storagecfg "github.com/xuperchain/xupercore/lib/storage/config"
 	"github.com/xuperchain/xupercore/lib/utils"
 
 	"github.com/spf13/viper"
@@ -17,6 +18,10 @@ type XLedgerConf struct {
 	BlockCacheSize int        `yaml:"blockCacheSize,omitempty"`
 	TxCacheSize    int        `yaml:"txCacheSize,omitempty"`
 	MempoolTxLimit int        `yaml:"mempoolTxLimit,omitempty"`
+	// object store config, only used when storageType is cloud
+	CloudStorage *storagecfg.CloudStorageConfig `yaml:"cloudStorage,omitempty"`
+	// tiered placement of ledger table files, only used when storageType is multi
+	Tiering *storagecfg.TieringConfig `yaml:"tiering,omitempty"`
 }
 
 type UtxoConfig struct {
@@ -20,6 +20,7 @@ import (
 	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/metrics"
+	storagecfg "github.com/xuperchain/xupercore/lib/storage/config"
 	"github.com/xuperchain/xupercore/lib/storage/kvdb"
 	"github.com/xuperchain/xupercore/lib/timer"
 	"github.com/xuperchain/xupercore/lib/utils"
@@ -106,6 +107,17 @@ func CreateLedger(lctx *LedgerCtx, genesisCfg []byte) (*Ledger, error) {
 	return newLedger(lctx, true, genesisCfg)
 }
 
+// ledgerTieringConfig treats blocks and confirmed txs as cold data by default,
+// both of them are never modified once written
+func ledgerTieringConfig(cfg *storagecfg.TieringConfig) *storagecfg.TieringConfig {
+	if cfg == nil || len(cfg.ColdPrefixes) > 0 {
+		return cfg
+	}
+	c := *cfg
+	c.ColdPrefixes = []string{pb.BlocksTablePrefix, pb.ConfirmedTablePrefix}
+	return &c
+}
+
 // OpenLedger open ledger which already exists
 func OpenLedger(lctx *LedgerCtx) (*Ledger, error) {
 	return newLedger(lctx, false, nil)
@@ -126,6 +138,10 @@ func newLedger(lctx *LedgerCtx, createIfMissing bool, genesisCfg []byte) (*Ledge
 		FileHandlersCacheSize: FileHandlersCacheSize,
 		OtherPaths:            lctx.LedgerCfg.OtherPaths,
 		StorageType:           lctx.LedgerCfg.StorageType,
+		Options: map[string]interface{}{
+			"cloudStorage": lctx.LedgerCfg.CloudStorage,
+			"tiering":      ledgerTieringConfig(lctx.LedgerCfg.Tiering),
+		},
 	}
 	baseDB, err := kvdb.CreateKVInstance(kvParam)
 	if err != nil {
@@ -489,11 +505,12 @@ func (l *Ledger) IsValidTx(idx int, tx *pb.Transaction, block *pb.InternalBlock)
 			l.xlog.Warn("invalid length of coinbase tx outputs, when ConfirmBlock", "len", len(tx.TxOutputs))
 			return false
 		}
-		//交易奖励的金额是否符合策略?
+		//交易奖励的金额是否符合策略? 共识可以将奖励拆分为多个输出，此时检查总额，拆分方式由共识校验
 		awardTarget := l.GenesisBlock.CalcAward(block.Height)
-		amountBytes := tx.TxOutputs[0].Amount
 		awardN := big.NewInt(0)
-		awardN.SetBytes(amountBytes)
+		for _, output := range tx.TxOutputs {
+			awardN.Add(awardN, new(big.Int).SetBytes(output.Amount))
+		}
 		if awardN.Cmp(awardTarget) != 0 {
 			l.xlog.Warn("invalid block award found", "award", awardN.String(), "target", awardTarget.String())
 			return false
@@ -124,3 +124,23 @@ func (t *BlockAgent) GetTxIDs() []string {
 	return txIDs
 
 }
+
+// GetBlockHeader 返回不含交易的区块头，仍然可以用于还原blockid和校验区块签名
+func (t *BlockAgent) GetBlockHeader() *lpb.InternalBlock {
+	header := *t.blk
+	header.Transactions = nil
+	header.MerkleTree = nil
+	header.InTrunk = false
+	header.NextHash = nil
+	return &header
+}
+
+// GetAwardTx 返回区块中的奖励交易，没有时返回nil
+func (t *BlockAgent) GetAwardTx() *lpb.Transaction {
+	for _, tx := range t.blk.Transactions {
+		if tx.Coinbase {
+			return tx
+		}
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/meta"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/supply"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
@@ -30,6 +31,7 @@ import (
 	proposeUtils "github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
 	kledger "github.com/xuperchain/xupercore/kernel/ledger"
 	aclBase "github.com/xuperchain/xupercore/kernel/permission/acl/base"
+	aclu "github.com/xuperchain/xupercore/kernel/permission/acl/utils"
 	"github.com/xuperchain/xupercore/lib/cache"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/metrics"
@@ -56,11 +58,15 @@ var (
 	ErrInvalidTxExt   = errors.New("Invalid tx ext")
 	ErrTxTooLarge     = errors.New("Tx size is too large")
 
-	ErrParseContractUtxos   = errors.New("Parse contract utxos error")
-	ErrContractTxAmout      = errors.New("Contract transfer amount error")
-	ErrGetReservedContracts = errors.New("Get reserved contracts error")
+	ErrParseContractUtxos      = errors.New("Parse contract utxos error")
+	ErrParseContractBlockReads = errors.New("Parse contract block reads error")
+	ErrContractTxAmout         = errors.New("Contract transfer amount error")
+	ErrContractBlockRead       = errors.New("Block read by contract is not on trunk")
+	ErrGetReservedContracts    = errors.New("Get reserved contracts error")
 
 	ErrMempoolIsFull = errors.New("Mempool is full")
+
+	ErrHeightNotIrreversible = errors.New("Query height is not irreversible")
 )
 
 const (
@@ -86,6 +92,7 @@ type State struct {
 	xmodel        *xmodel.XModel //xmodel数据表和历史表
 	meta          *meta.Meta     //meta表
 	tx            *tx.Tx         //未确认交易表
+	supply        *supply.Supply //原生代币供应量统计
 	ldb           kvdb.Database
 	latestBlockid []byte
 
@@ -114,6 +121,9 @@ func NewState(sctx *context.StateCtx) (*State, error) {
 		FileHandlersCacheSize: ledger.FileHandlersCacheSize,
 		OtherPaths:            sctx.LedgerCfg.OtherPaths,
 		StorageType:           sctx.LedgerCfg.StorageType,
+		Options: map[string]interface{}{
+			"cloudStorage": sctx.LedgerCfg.CloudStorage,
+		},
 	}
 	obj.ldb, err = kvdb.CreateKVInstance(kvParam)
 	if err != nil {
@@ -155,6 +165,14 @@ func NewState(sctx *context.StateCtx) (*State, error) {
 		return nil, loadErr
 	}
 
+	obj.supply, err = supply.NewSupply(sctx, obj.ldb)
+	if err != nil {
+		return nil, fmt.Errorf("create state failed because create supply error:%s", err)
+	}
+	if err = obj.rebuildSupply(); err != nil {
+		return nil, fmt.Errorf("create state failed because rebuild supply error:%s", err)
+	}
+
 	obj.heightNotifier = NewBlockHeightNotifier()
 
 	// go obj.collectDelayedTxs(defaultUndoDelayedTxsInterval)
@@ -234,11 +252,68 @@ func (t *State) GetContractStatus(contractName string) (*protos.ContractStatus,
 	}
 	res.Desc = tx.GetDesc()
 	res.Timestamp = tx.GetReceivedTimestamp()
+	// 销毁合约时 desc 被删除，txid 为销毁合约的交易
+	descbuf := verdata.GetPureData().GetValue()
+	if bytes.Equal(descbuf, []byte(xmodel.DelFlag)) {
+		res.IsDestroyed = true
+	} else {
+		desc := new(protos.WasmCodeDesc)
+		if err := proto.Unmarshal(descbuf, desc); err == nil {
+			res.IsFrozen = desc.GetFrozen()
+		}
+	}
+	if account, err := t.xmodel.Get(aclu.GetContract2AccountBucket(), []byte(contractName)); err == nil {
+		res.AccountName = string(account.GetPureData().GetValue())
+	}
 	// query if contract is bannded
 	res.IsBanned, err = t.queryContractBannedStatus(contractName)
 	return res, nil
 }
 
+// GetContractVersions 按版本号顺序返回合约的历史版本，
+// 版本生效的交易、高度和时间由写入版本记录的交易确定。
+// 版本记录上线之前部署的合约没有部署时的版本，第一次升级记录为版本 1
+func (t *State) GetContractVersions(contractName string) ([]*protos.ContractVersion, error) {
+	prefix := bridge.ContractVersionKeyPrefix(contractName)
+	iter, err := t.xmodel.Select("contract", prefix, proposeUtils.PrefixRange(prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var versions []*protos.ContractVersion
+	for iter.Next() {
+		verdata := iter.Value()
+		version := new(protos.ContractVersion)
+		if err := proto.Unmarshal(verdata.GetPureData().GetValue(), version); err != nil {
+			t.log.Warn("GetContractVersions unmarshal version error", "error", err.Error())
+			return nil, err
+		}
+		if version.GetContractName() != contractName {
+			continue
+		}
+		txid := verdata.GetRefTxid()
+		version.Txid = fmt.Sprintf("%x", txid)
+		tx, _, err := t.xmodel.QueryTx(txid)
+		if err != nil {
+			t.log.Warn("GetContractVersions query tx error", "error", err.Error())
+			return nil, err
+		}
+		version.Timestamp = tx.GetReceivedTimestamp()
+		block, err := t.sctx.Ledger.QueryBlockHeader(tx.GetBlockid())
+		if err != nil {
+			t.log.Warn("GetContractVersions query block error", "error", err.Error())
+			return nil, err
+		}
+		version.Height = block.GetHeight()
+		versions = append(versions, version)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
 func (t *State) QueryAccountACL(accountName string) (*protos.Acl, error) {
 	return t.sctx.AclMgr.GetAccountACL(accountName)
 }
@@ -442,6 +517,10 @@ func (t *State) PlayForMiner(blockid []byte) error {
 		}
 	}
 	timer.Mark("do_tx")
+	// 统计区块带来的余额变化
+	if err = t.supply.DoBlock(block, batch); err != nil {
+		return err
+	}
 	// 更新不可逆区块高度
 	curIrreversibleBlockHeight := t.meta.GetIrreversibleBlockHeight()
 	curIrreversibleSlideWindow := t.meta.GetIrreversibleSlideWindow()
@@ -462,6 +541,7 @@ func (t *State) PlayForMiner(blockid []byte) error {
 	newMeta := proto.Clone(t.meta.MetaTmp).(*pb.UtxoMeta)
 	t.meta.Meta = newMeta
 	t.meta.MutexMeta.Unlock()
+	t.precompileContracts(block)
 	t.log.Info("play for miner", "height", block.Height, "blockId", utils.F(block.Blockid), "costs", timer.Print())
 	return nil
 }
@@ -526,6 +606,10 @@ func (t *State) PlayAndRepost(blockid []byte, needRepost bool, isRootTx bool) er
 		}
 	}
 	timer.Mark("do_tx")
+	// 统计区块带来的余额变化
+	if err = t.supply.DoBlock(block, batch); err != nil {
+		return err
+	}
 	// 更新不可逆区块高度
 	curIrreversibleBlockHeight := t.meta.GetIrreversibleBlockHeight()
 	curIrreversibleSlideWindow := t.meta.GetIrreversibleSlideWindow()
@@ -562,6 +646,8 @@ func (t *State) PlayAndRepost(blockid []byte, needRepost bool, isRootTx bool) er
 	t.meta.Meta = newMeta
 	t.meta.MutexMeta.Unlock()
 
+	t.precompileContracts(block)
+
 	// mempool 中回滚的交易重新验证、执行一遍，避免正确的交易丢失。
 	// 主要是在区块内有只读交易，mempool中有写交易的情况。
 	go t.recoverUnconfirmedTx(mempoolDelTxs)
@@ -570,6 +656,38 @@ func (t *State) PlayAndRepost(blockid []byte, needRepost bool, isRootTx bool) er
 	return nil
 }
 
+// precompileContracts 区块确认后在后台预编译区块内部署、升级或者由定时任务生效升级的合约，避免在第一次调用时编译
+func (t *State) precompileContracts(block *pb.InternalBlock) {
+	precompiler, ok := t.sctx.ContractMgr.(contract.CodePrecompiler)
+	if !ok {
+		return
+	}
+	var reqs []*protos.InvokeRequest
+	for _, tx := range block.Transactions {
+		reqs = append(reqs, tx.ContractRequests...)
+		// 定时任务交易只有读写集，使用生成它的调用代替，由合约管理找到定时任务生效的升级
+		if tx.Autogen && len(tx.TxOutputsExt) != 0 {
+			reqs = append(reqs, timerTaskRequest(block.Height))
+		}
+	}
+	// 读取合约代码和检查磁盘缓存同样在后台进行，不阻塞区块的执行
+	if len(reqs) != 0 {
+		go precompiler.PrecompileContracts(reqs)
+	}
+}
+
+// timerTaskRequest 返回执行blockHeight高度定时任务的调用
+func timerTaskRequest(blockHeight int64) *protos.InvokeRequest {
+	return &protos.InvokeRequest{
+		ModuleName:   "xkernel",
+		ContractName: "$timer_task",
+		MethodName:   "Do",
+		Args: map[string][]byte{
+			"block_height": []byte(strconv.FormatInt(blockHeight, 10)),
+		},
+	}
+}
+
 func (t *State) GetTimerTx(blockHeight int64) (*pb.Transaction, error) {
 	stateConfig := &contract.SandboxConfig{
 		XMReader:   t.CreateXMReader(),
@@ -591,14 +709,7 @@ func (t *State) GetTimerTx(blockHeight int64) (*pb.Transaction, error) {
 		AuthRequire: nil,
 	}
 
-	args := make(map[string][]byte)
-	args["block_height"] = []byte(strconv.FormatInt(blockHeight, 10))
-	req := protos.InvokeRequest{
-		ModuleName:   "xkernel",
-		ContractName: "$timer_task",
-		MethodName:   "Do",
-		Args:         args,
-	}
+	req := timerTaskRequest(blockHeight)
 
 	contextConfig.ResourceLimits = contract.MaxLimits
 	contextConfig.Module = req.ModuleName
@@ -758,6 +869,61 @@ func (t *State) GetTotal() *big.Int {
 	return t.utxo.GetTotal()
 }
 
+// GetSupplyInfo 查询原生代币的总量、冻结量和锁定量，height小于0时查询最新状态，
+// 否则只能查询不可逆高度
+func (t *State) GetSupplyInfo(height int64) (*supply.Info, error) {
+	if err := t.checkSupplyHeight(height); err != nil {
+		return nil, err
+	}
+	t.utxo.Mutex.RLock()
+	defer t.utxo.Mutex.RUnlock()
+	return t.supply.GetInfo(height)
+}
+
+// GetTopHolders 查询余额最多的n个地址，height的含义同GetSupplyInfo
+func (t *State) GetTopHolders(height int64, n int) (*supply.Ranking, error) {
+	if err := t.checkSupplyHeight(height); err != nil {
+		return nil, err
+	}
+	t.utxo.Mutex.RLock()
+	defer t.utxo.Mutex.RUnlock()
+	return t.supply.GetTopHolders(height, n)
+}
+
+// GetBalanceDistribution 查询余额分布，height的含义同GetSupplyInfo
+func (t *State) GetBalanceDistribution(height int64) (*supply.Distribution, error) {
+	if err := t.checkSupplyHeight(height); err != nil {
+		return nil, err
+	}
+	t.utxo.Mutex.RLock()
+	defer t.utxo.Mutex.RUnlock()
+	return t.supply.GetDistribution(height)
+}
+
+func (t *State) checkSupplyHeight(height int64) error {
+	if height >= 0 && height > t.meta.GetIrreversibleBlockHeight() {
+		return ErrHeightNotIrreversible
+	}
+	return nil
+}
+
+// rebuildSupply 已有数据的链首次开启统计，或者统计的历史版本失效时，根据utxo重新统计
+func (t *State) rebuildSupply() error {
+	if len(t.latestBlockid) == 0 {
+		return nil
+	}
+	need, err := t.supply.NeedRebuild()
+	if err != nil || !need {
+		return err
+	}
+	blk, err := t.sctx.Ledger.QueryBlockHeader(t.latestBlockid)
+	if err != nil {
+		return err
+	}
+	t.log.Info("rebuild supply from utxo", "height", blk.GetHeight())
+	return t.supply.Rebuild(blk.GetHeight())
+}
+
 // 查找状态机meta信息
 func (t *State) GetMeta() *pb.UtxoMeta {
 	meta := &pb.UtxoMeta{}
@@ -776,6 +942,11 @@ func (t *State) GetMeta() *pb.UtxoMeta {
 	return meta
 }
 
+// GetIrreversibleSlideWindow 返回当前的不可逆滑动窗口
+func (t *State) GetIrreversibleSlideWindow() int64 {
+	return t.meta.GetIrreversibleSlideWindow()
+}
+
 func (t *State) doTxSync(tx *pb.Transaction) error {
 	pbTxBuf, pbErr := proto.Marshal(tx)
 	if pbErr != nil {
@@ -846,12 +1017,50 @@ func (t *State) doTxSync(tx *pb.Transaction) error {
 	return nil
 }
 
+// checkContractUtxoReads 校验合约余额查询读到的utxo，读取过的地址下的utxo发生变化时交易的读集失效
+func (t *State) checkContractUtxoReads(tx *pb.Transaction, batch kvdb.Batch) error {
+	reads, err := xmodel.ParseContractUtxoReads(tx)
+	if err != nil {
+		return ErrParseContractUtxos
+	}
+	// 本交易花费的utxo在校验之后才会删除，读到后又被转出的utxo同样能通过校验
+	if err := t.utxo.CheckContractUtxoReads(reads.Addrs, reads.Reads, false, batch); err != nil {
+		return err
+	}
+	return t.utxo.CheckContractUtxoReads(reads.FrozenAddrs, reads.FrozenReads, true, batch)
+}
+
+// checkContractBlockReads 校验合约读取过的区块仍在主干上，读到分叉上的区块时交易的读集失效
+func (t *State) checkContractBlockReads(tx *pb.Transaction) error {
+	blockids, err := xmodel.ParseContractBlockReads(tx)
+	if err != nil {
+		return ErrParseContractBlockReads
+	}
+	for _, id := range blockids {
+		block, err := t.sctx.Ledger.QueryBlockHeader(id)
+		if err != nil {
+			return ErrContractBlockRead
+		}
+		trunkBlock, err := t.sctx.Ledger.QueryBlockByHeight(block.GetHeight())
+		if err != nil || !bytes.Equal(trunkBlock.GetBlockid(), id) {
+			return ErrContractBlockRead
+		}
+	}
+	return nil
+}
+
 func (t *State) doTxInternal(tx *pb.Transaction, batch kvdb.Batch, cacheFiller *utxo.CacheFiller) error {
 	t.utxo.CleanBatchCache(batch) // 根据 batch 清理缓存。
 	if tx.GetModifyBlock() == nil || (tx.GetModifyBlock() != nil && !tx.ModifyBlock.Marked) {
 		if err := t.utxo.CheckInputEqualOutput(tx, batch); err != nil {
 			return err
 		}
+		if err := t.checkContractUtxoReads(tx, batch); err != nil {
+			return err
+		}
+		if err := t.checkContractBlockReads(tx); err != nil {
+			return err
+		}
 	}
 
 	beginTime := time.Now()
@@ -931,6 +1140,21 @@ func (t *State) QueryBlock(blockid []byte) (kledger.BlockHandle, error) {
 	return NewBlockAgent(block), nil
 
 }
+
+// QueryBlockByHeight query block on trunk by height
+func (t *State) QueryBlockByHeight(height int64) (kledger.BlockHandle, error) {
+	block, err := t.sctx.Ledger.QueryBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return NewBlockAgent(block), nil
+}
+
+// QueryTipBlockHeight query height of the trunk tip block
+func (t *State) QueryTipBlockHeight() (int64, error) {
+	return t.sctx.Ledger.GetMeta().GetTrunkHeight(), nil
+}
+
 func (t *State) QueryTransaction(txid []byte) (*pb2.Transaction, error) {
 	ltx, err := t.sctx.Ledger.QueryTransaction(txid)
 	if err != nil {
@@ -1111,6 +1335,12 @@ func (t *State) procUndoBlkForWalk(undoBlocks []*pb.InternalBlock,
 			}
 		}
 
+		// 回滚区块带来的余额变化
+		err = t.supply.UndoBlock(undoBlk, batch)
+		if err != nil {
+			return fmt.Errorf("undo block supply fail.blockid:%s,err:%v", showBlkId, err)
+		}
+
 		// 账本裁剪时，无视区块不可逆原则
 		if ledgerPrune {
 			curIrreversibleBlockHeight := t.meta.GetIrreversibleBlockHeight()
@@ -1231,6 +1461,12 @@ func (t *State) procTodoBlkForWalk(todoBlocks []*pb.InternalBlock) (err error) {
 
 		t.log.Debug("Begin to Finalize", "blockid", showBlkId)
 
+		// 统计区块带来的余额变化
+		err = t.supply.DoBlock(todoBlk, batch)
+		if err != nil {
+			return fmt.Errorf("do block supply fail.blockid:%s,err:%v", showBlkId, err)
+		}
+
 		// 更新不可逆区块高度
 		curIrreversibleBlockHeight := t.meta.GetIrreversibleBlockHeight()
 		curIrreversibleSlideWindow := t.meta.GetIrreversibleSlideWindow()
@@ -1251,6 +1487,7 @@ func (t *State) procTodoBlkForWalk(todoBlocks []*pb.InternalBlock) (err error) {
 		t.meta.Meta = newMeta
 		t.meta.MutexMeta.Unlock()
 
+		t.precompileContracts(todoBlk)
 		t.log.Info("finish todo this block", "blockid", showBlkId)
 	}
 
@@ -0,0 +1,290 @@
+package supply
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"math/big"
+	"sort"
+)
+
+// Info 某一高度的原生代币供应量
+type Info struct {
+	Height int64
+	// 总量
+	Total *big.Int
+	// 冻结量，到达解冻高度后可以使用
+	Frozen *big.Int
+	// 锁定量，FrozenHeight为-1的utxo永远不能使用
+	Locked *big.Int
+}
+
+// Circulating 流通量，总量扣除冻结和锁定的部分
+func (i *Info) Circulating() *big.Int {
+	v := new(big.Int).Sub(i.Total, i.Frozen)
+	return v.Sub(v, i.Locked)
+}
+
+type Holder struct {
+	Address string
+	Balance *big.Int
+}
+
+// Ranking 余额从大到小排列的持有者
+type Ranking struct {
+	Height  int64
+	Holders []*Holder
+}
+
+// Bucket 余额在[Min, Max)区间的持有者统计，区间按十进制位数划分
+type Bucket struct {
+	Min     *big.Int
+	Max     *big.Int
+	Holders int64
+	Amount  *big.Int
+}
+
+// Distribution 余额分布，只统计余额大于0的地址
+type Distribution struct {
+	Height  int64
+	Holders int64
+	Buckets []*Bucket
+}
+
+// GetInfo 查询指定高度的供应量，height小于0时查询最新高度
+func (s *Supply) GetInfo(height int64) (*Info, error) {
+	height, atTip, err := s.resolve(height)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{Height: height, Frozen: big.NewInt(0)}
+	if atTip {
+		if info.Total, err = s.getTip(totalItem); err != nil {
+			return nil, err
+		}
+		if info.Locked, err = s.getTip(lockedItem); err != nil {
+			return nil, err
+		}
+		err = s.scanTip(frozenItem, func(item string, value *big.Int) {
+			if frozenRelease(item) > height {
+				info.Frozen.Add(info.Frozen, value)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		return info, nil
+	}
+
+	if info.Total, err = s.valueAt(totalItem, height); err != nil {
+		return nil, err
+	}
+	if info.Locked, err = s.valueAt(lockedItem, height); err != nil {
+		return nil, err
+	}
+	err = s.scanAt(frozenItem, height, func(item string, value *big.Int) {
+		if frozenRelease(item) > height {
+			info.Frozen.Add(info.Frozen, value)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetTopHolders 查询指定高度余额最多的n个地址，余额相同时按地址排序
+func (s *Supply) GetTopHolders(height int64, n int) (*Ranking, error) {
+	if n <= 0 {
+		return nil, ErrInvalidLimit
+	}
+	height, atTip, err := s.resolve(height)
+	if err != nil {
+		return nil, err
+	}
+
+	ranking := &Ranking{Height: height}
+	if atTip {
+		// 排名索引已经按余额从大到小排序
+		it := s.db.NewIteratorWithPrefix(rawKey(rankPrefix))
+		defer it.Release()
+		for len(ranking.Holders) < n && it.Next() {
+			addr, balance, err := parseRankKey(it.Key())
+			if err != nil {
+				return nil, err
+			}
+			ranking.Holders = append(ranking.Holders, &Holder{Address: addr, Balance: balance})
+		}
+		if err := it.Error(); err != nil {
+			return nil, err
+		}
+		return ranking, nil
+	}
+
+	h := &holderHeap{}
+	err = s.scanAt(addrItem, height, func(item string, value *big.Int) {
+		if value.Sign() == 0 {
+			return
+		}
+		heap.Push(h, &Holder{Address: item[len(addrItem):], Balance: value})
+		if h.Len() > n {
+			heap.Pop(h)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	ranking.Holders = make([]*Holder, h.Len())
+	for i := len(ranking.Holders) - 1; i >= 0; i-- {
+		ranking.Holders[i] = heap.Pop(h).(*Holder)
+	}
+	return ranking, nil
+}
+
+// GetDistribution 查询指定高度的余额分布
+func (s *Supply) GetDistribution(height int64) (*Distribution, error) {
+	height, atTip, err := s.resolve(height)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make(map[int]*Bucket)
+	dist := &Distribution{Height: height}
+	visit := func(item string, value *big.Int) {
+		if value.Sign() == 0 {
+			return
+		}
+		digits := len(value.String())
+		b, ok := buckets[digits]
+		if !ok {
+			ten := big.NewInt(10)
+			b = &Bucket{
+				Min:    new(big.Int).Exp(ten, big.NewInt(int64(digits-1)), nil),
+				Max:    new(big.Int).Exp(ten, big.NewInt(int64(digits)), nil),
+				Amount: big.NewInt(0),
+			}
+			buckets[digits] = b
+		}
+		b.Holders++
+		b.Amount.Add(b.Amount, value)
+		dist.Holders++
+	}
+	if atTip {
+		err = s.scanTip(addrItem, visit)
+	} else {
+		err = s.scanAt(addrItem, height, visit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range buckets {
+		dist.Buckets = append(dist.Buckets, b)
+	}
+	sort.Slice(dist.Buckets, func(i, j int) bool {
+		return dist.Buckets[i].Min.Cmp(dist.Buckets[j].Min) < 0
+	})
+	return dist, nil
+}
+
+// resolve 校验查询高度，返回实际高度以及是否可以直接读取最新值
+func (s *Supply) resolve(height int64) (int64, bool, error) {
+	tip, err := s.tipHeight()
+	if err != nil {
+		return 0, false, err
+	}
+	if height < 0 || height == tip {
+		return tip, true, nil
+	}
+	if height > tip {
+		return 0, false, ErrInvalidHeight
+	}
+	base, err := s.baseHeight()
+	if err != nil {
+		return 0, false, err
+	}
+	if height < base {
+		return 0, false, ErrHistoryUnavailable
+	}
+	return height, false, nil
+}
+
+// scanTip 遍历某类统计项的最新值
+func (s *Supply) scanTip(itemPrefix string, visit func(item string, value *big.Int)) error {
+	it := s.db.NewIteratorWithPrefix(tipKey(itemPrefix))
+	defer it.Release()
+	skip := len(tipKey(""))
+	for it.Next() {
+		visit(string(it.Key()[skip:]), new(big.Int).SetBytes(it.Value()))
+	}
+	return it.Error()
+}
+
+// valueAt 查询统计项在指定高度的值，即不高于该高度的最新版本
+func (s *Supply) valueAt(item string, height int64) (*big.Int, error) {
+	it := s.db.NewIteratorWithRange(histKey(item, height), append(rawKey(histPrefix+item), 1))
+	defer it.Release()
+	if it.Next() {
+		return new(big.Int).SetBytes(it.Value()), nil
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return big.NewInt(0), nil
+}
+
+// scanAt 遍历某类统计项在指定高度的值。同一统计项的版本按高度从高到低排列，
+// 取第一个不高于该高度的版本
+func (s *Supply) scanAt(itemPrefix string, height int64, visit func(item string, value *big.Int)) error {
+	it := s.db.NewIteratorWithPrefix(rawKey(histPrefix + itemPrefix))
+	defer it.Release()
+
+	skip := len(rawKey(histPrefix))
+	var cur string
+	var found bool
+	for it.Next() {
+		key := it.Key()[skip:]
+		if len(key) < heightLen+1 {
+			continue
+		}
+		item := string(key[:len(key)-heightLen-1])
+		version := ^int64(binary.BigEndian.Uint64(key[len(key)-heightLen:]))
+		if item != cur {
+			cur, found = item, false
+		}
+		if found || version > height {
+			continue
+		}
+		found = true
+		visit(item, new(big.Int).SetBytes(it.Value()))
+	}
+	return it.Error()
+}
+
+func frozenRelease(item string) int64 {
+	return int64(binary.BigEndian.Uint64([]byte(item[len(frozenItem):])))
+}
+
+// holderHeap 小顶堆，堆顶是当前排名最靠后的持有者
+type holderHeap []*Holder
+
+func (h holderHeap) Len() int { return len(h) }
+func (h holderHeap) Less(i, j int) bool {
+	if c := h[i].Balance.Cmp(h[j].Balance); c != 0 {
+		return c < 0
+	}
+	return h[i].Address > h[j].Address
+}
+func (h holderHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *holderHeap) Push(x interface{}) {
+	*h = append(*h, x.(*Holder))
+}
+
+func (h *holderHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
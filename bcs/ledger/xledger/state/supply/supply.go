@@ -0,0 +1,387 @@
+// 原生代币供应量统计，按地址汇总余额，维护总量、冻结量和锁定量，
+// 每个区块的变化按高度保存历史版本，用于查询富豪榜和余额分布，避免扫描全部utxo。
+package supply
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/def"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+)
+
+// 统计表内的key布局，均以pb.SupplyTablePrefix开头：
+//
+//	B                       历史版本的起始高度
+//	H                       统计数据对应的区块高度
+//	c{lk}                   最新值
+//	v{lk}\x00{^height}      历史版本，高度取反后按从高到低排序
+//	r{^len}{^balance}{addr} 余额排名，按余额从大到小排序
+//
+// 其中lk为统计项：a{addr}地址余额，t总量，l锁定量，f{release}在release高度解冻的冻结量
+const (
+	baseKey    = "B"
+	heightKey  = "H"
+	tipPrefix  = "c"
+	histPrefix = "v"
+	rankPrefix = "r"
+
+	addrItem   = "a"
+	totalItem  = "t"
+	lockedItem = "l"
+	frozenItem = "f"
+
+	heightLen = 8
+)
+
+var (
+	ErrHistoryUnavailable = errors.New("supply history not available at this height")
+	ErrInvalidHeight      = errors.New("invalid supply query height")
+	ErrInvalidLimit       = errors.New("invalid holder count")
+)
+
+type Supply struct {
+	log logs.Logger
+	db  kvdb.Database
+}
+
+func NewSupply(sctx *context.StateCtx, stateDB kvdb.Database) (*Supply, error) {
+	if sctx == nil || stateDB == nil {
+		return nil, fmt.Errorf("create supply failed because context set error")
+	}
+	return newSupply(sctx.XLog, stateDB), nil
+}
+
+func newSupply(log logs.Logger, stateDB kvdb.Database) *Supply {
+	return &Supply{
+		log: log,
+		db:  stateDB,
+	}
+}
+
+// delta 一个区块带来的各统计项变化
+type delta struct {
+	items map[string]*big.Int
+}
+
+func newDelta() *delta {
+	return &delta{items: make(map[string]*big.Int)}
+}
+
+func (d *delta) add(item string, amount *big.Int) {
+	if amount.Sign() == 0 {
+		return
+	}
+	v, ok := d.items[item]
+	if !ok {
+		v = big.NewInt(0)
+		d.items[item] = v
+	}
+	v.Add(v, amount)
+}
+
+// addUtxo 记录一个utxo的产生(amount为正)或消耗(amount为负)
+func (d *delta) addUtxo(addr []byte, amount *big.Int, frozenHeight int64) {
+	d.add(addrItem+string(addr), amount)
+	d.add(totalItem, amount)
+	switch {
+	case frozenHeight == -1:
+		d.add(lockedItem, amount)
+	case frozenHeight > 0:
+		d.add(frozenItemKey(frozenHeight), amount)
+	}
+}
+
+// addTx 按照状态机的执行逻辑统计交易的utxo变化，小费归属区块的proposer
+func (d *delta) addTx(tx *pb.Transaction, proposer []byte) {
+	for _, input := range tx.TxInputs {
+		amount := new(big.Int).SetBytes(input.Amount)
+		d.addUtxo(input.FromAddr, amount.Neg(amount), input.FrozenHeight)
+	}
+	for _, output := range tx.TxOutputs {
+		amount := new(big.Int).SetBytes(output.Amount)
+		if bytes.Equal(output.ToAddr, []byte(pb.FeePlaceholder)) {
+			if proposer != nil {
+				d.addUtxo(proposer, amount, 0)
+			}
+			continue
+		}
+		d.addUtxo(output.ToAddr, amount, output.FrozenHeight)
+	}
+}
+
+func blockDelta(block *pb.InternalBlock) *delta {
+	d := newDelta()
+	for _, tx := range block.Transactions {
+		d.addTx(tx, block.Proposer)
+	}
+	return d
+}
+
+// DoBlock 将区块带来的余额变化写入batch，需要在状态机执行区块时调用
+func (s *Supply) DoBlock(block *pb.InternalBlock, batch kvdb.Batch) error {
+	d := blockDelta(block)
+	for item, amount := range d.items {
+		value, err := s.update(batch, item, amount)
+		if err != nil {
+			return err
+		}
+		batch.Put(histKey(item, block.Height), value.Bytes())
+	}
+	if block.Height == 0 {
+		// 创世块开始统计，历史版本完整
+		batch.Put(rawKey(baseKey), encodeHeight(0))
+	}
+	batch.Put(rawKey(heightKey), encodeHeight(block.Height))
+	return nil
+}
+
+// UndoBlock 回滚区块带来的余额变化，需要在状态机回滚区块时调用
+func (s *Supply) UndoBlock(block *pb.InternalBlock, batch kvdb.Batch) error {
+	d := blockDelta(block)
+	for item, amount := range d.items {
+		if _, err := s.update(batch, item, amount.Neg(amount)); err != nil {
+			return err
+		}
+		batch.Delete(histKey(item, block.Height))
+	}
+	if base, err := s.baseHeight(); err == nil && block.Height <= base {
+		// 回滚到起始高度以下后，起始高度的快照不再可用，重启时重新统计
+		s.log.Warn("supply history invalidated by undo block", "height", block.Height, "base", base)
+		batch.Delete(rawKey(baseKey))
+	}
+	batch.Put(rawKey(heightKey), encodeHeight(block.Height-1))
+	return nil
+}
+
+// update 更新统计项的最新值，地址余额同时维护排名
+func (s *Supply) update(batch kvdb.Batch, item string, amount *big.Int) (*big.Int, error) {
+	old, err := s.getTip(item)
+	if err != nil {
+		return nil, err
+	}
+	value := new(big.Int).Add(old, amount)
+	if value.Sign() < 0 {
+		s.log.Warn("supply item underflow", "item", item, "old", old.String(), "delta", amount.String())
+		value.SetInt64(0)
+	}
+
+	if value.Sign() == 0 {
+		batch.Delete(tipKey(item))
+	} else {
+		batch.Put(tipKey(item), value.Bytes())
+	}
+	if item[:1] == addrItem {
+		addr := item[1:]
+		if old.Sign() > 0 {
+			batch.Delete(rankKey(old, addr))
+		}
+		if value.Sign() > 0 {
+			batch.Put(rankKey(value, addr), []byte{})
+		}
+	}
+	return value, nil
+}
+
+func (s *Supply) getTip(item string) (*big.Int, error) {
+	buf, err := s.db.Get(tipKey(item))
+	if err != nil {
+		if def.NormalizedKVError(err) == def.ErrKVNotFound {
+			return big.NewInt(0), nil
+		}
+		return nil, err
+	}
+	return new(big.Int).SetBytes(buf), nil
+}
+
+// Rebuild 根据utxo表重新统计当前高度的数据，未确认交易的影响会被剔除。
+// 用于在已有数据的链上开启统计，或者回滚导致历史版本失效之后
+func (s *Supply) Rebuild(height int64) error {
+	if err := s.clear(); err != nil {
+		return err
+	}
+
+	d := newDelta()
+	it := s.db.NewIteratorWithPrefix([]byte(pb.UTXOTablePrefix))
+	for it.Next() {
+		addr, err := utxoAddress(it.Key())
+		if err != nil {
+			it.Release()
+			return err
+		}
+		uItem := &utxo.UtxoItem{}
+		if err := uItem.Loads(it.Value()); err != nil {
+			it.Release()
+			return err
+		}
+		d.addUtxo(addr, uItem.Amount, uItem.FrozenHeight)
+	}
+	err := it.Error()
+	it.Release()
+	if err != nil {
+		return err
+	}
+
+	// 未确认交易已经修改了utxo表，反向扣除
+	it = s.db.NewIteratorWithPrefix([]byte(pb.UnconfirmedTablePrefix))
+	for it.Next() {
+		tx := &pb.Transaction{}
+		if err := proto.Unmarshal(it.Value(), tx); err != nil {
+			it.Release()
+			return err
+		}
+		undo := newDelta()
+		undo.addTx(tx, nil)
+		for item, amount := range undo.items {
+			d.add(item, amount.Neg(amount))
+		}
+	}
+	err = it.Error()
+	it.Release()
+	if err != nil {
+		return err
+	}
+
+	batch := s.db.NewBatch()
+	for item, value := range d.items {
+		if value.Sign() <= 0 {
+			continue
+		}
+		batch.Put(tipKey(item), value.Bytes())
+		batch.Put(histKey(item, height), value.Bytes())
+		if item[:1] == addrItem {
+			batch.Put(rankKey(value, item[1:]), []byte{})
+		}
+	}
+	batch.Put(rawKey(baseKey), encodeHeight(height))
+	batch.Put(rawKey(heightKey), encodeHeight(height))
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	s.log.Info("rebuild supply finish", "height", height, "items", len(d.items))
+	return nil
+}
+
+// NeedRebuild 统计数据缺失或者历史版本失效时需要重新统计
+func (s *Supply) NeedRebuild() (bool, error) {
+	_, err := s.baseHeight()
+	if err == ErrHistoryUnavailable {
+		return true, nil
+	}
+	return false, err
+}
+
+func (s *Supply) clear() error {
+	it := s.db.NewIteratorWithPrefix([]byte(pb.SupplyTablePrefix))
+	defer it.Release()
+	batch := s.db.NewBatch()
+	for it.Next() {
+		batch.Delete(append([]byte(nil), it.Key()...))
+		if batch.ValueSize() > 1<<20 {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+func (s *Supply) baseHeight() (int64, error) {
+	return s.getHeight(baseKey)
+}
+
+func (s *Supply) tipHeight() (int64, error) {
+	return s.getHeight(heightKey)
+}
+
+func (s *Supply) getHeight(key string) (int64, error) {
+	buf, err := s.db.Get(rawKey(key))
+	if err != nil {
+		if def.NormalizedKVError(err) == def.ErrKVNotFound {
+			return 0, ErrHistoryUnavailable
+		}
+		return 0, err
+	}
+	if len(buf) != heightLen {
+		return 0, fmt.Errorf("invalid supply height %x", buf)
+	}
+	return int64(binary.BigEndian.Uint64(buf)), nil
+}
+
+// utxoAddress 从utxo表的key中解析地址，key格式为U{addr}_{txid}_{offset}
+func utxoAddress(key []byte) ([]byte, error) {
+	key = key[len(pb.UTXOTablePrefix):]
+	end := bytes.LastIndexByte(key, '_')
+	if end > 0 {
+		end = bytes.LastIndexByte(key[:end], '_')
+	}
+	if end <= 0 {
+		return nil, fmt.Errorf("unexpected utxo key %s", key)
+	}
+	return key[:end], nil
+}
+
+func rawKey(key string) []byte {
+	return []byte(pb.SupplyTablePrefix + key)
+}
+
+func tipKey(item string) []byte {
+	return rawKey(tipPrefix + item)
+}
+
+func histItemPrefix(item string) []byte {
+	return append(rawKey(histPrefix+item), 0)
+}
+
+func histKey(item string, height int64) []byte {
+	return append(histItemPrefix(item), encodeHeight(^height)...)
+}
+
+func frozenItemKey(release int64) string {
+	return frozenItem + string(encodeHeight(release))
+}
+
+// rankKey 按位取反编码余额，使得字典序从小到大对应余额从大到小
+func rankKey(balance *big.Int, addr string) []byte {
+	buf := balance.Bytes()
+	key := rawKey(rankPrefix)
+	key = append(key, ^byte(len(buf)))
+	for _, b := range buf {
+		key = append(key, ^b)
+	}
+	return append(key, addr...)
+}
+
+func parseRankKey(key []byte) (string, *big.Int, error) {
+	key = key[len(rawKey(rankPrefix)):]
+	if len(key) == 0 {
+		return "", nil, fmt.Errorf("invalid rank key")
+	}
+	size := int(^key[0])
+	if len(key) < 1+size {
+		return "", nil, fmt.Errorf("invalid rank key")
+	}
+	buf := make([]byte, size)
+	for i := range buf {
+		buf[i] = ^key[1+i]
+	}
+	return string(key[1+size:]), new(big.Int).SetBytes(buf), nil
+}
+
+func encodeHeight(height int64) []byte {
+	buf := make([]byte, heightLen)
+	binary.BigEndian.PutUint64(buf, uint64(height))
+	return buf
+}
@@ -0,0 +1,215 @@
+package supply
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/leveldb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func newTestSupply(t *testing.T) *Supply {
+	basedir := t.TempDir()
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), filepath.Join(basedir, "log"))
+	log, _ := logs.NewLogger("", "supply_test")
+
+	db, err := kvdb.CreateKVInstance(&kvdb.KVParameter{
+		DBPath:                filepath.Join(basedir, "state"),
+		KVEngineType:          "leveldb",
+		MemCacheSize:          128,
+		FileHandlersCacheSize: 512,
+		StorageType:           "single",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+	return newSupply(log, db)
+}
+
+func amount(v int64) []byte {
+	return big.NewInt(v).Bytes()
+}
+
+func output(to string, v int64, frozenHeight int64) *protos.TxOutput {
+	return &protos.TxOutput{ToAddr: []byte(to), Amount: amount(v), FrozenHeight: frozenHeight}
+}
+
+func input(from string, v int64) *protos.TxInput {
+	return &protos.TxInput{FromAddr: []byte(from), Amount: amount(v)}
+}
+
+func applyBlock(t *testing.T, s *Supply, block *pb.InternalBlock, undo bool) {
+	batch := s.db.NewBatch()
+	var err error
+	if undo {
+		err = s.UndoBlock(block, batch)
+	} else {
+		err = s.DoBlock(block, batch)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkInfo(t *testing.T, s *Supply, height int64, total, frozen, locked int64) {
+	info, err := s.GetInfo(height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Total.Int64() != total || info.Frozen.Int64() != frozen || info.Locked.Int64() != locked {
+		t.Fatalf("unexpected supply at %d: total:%s frozen:%s locked:%s",
+			height, info.Total, info.Frozen, info.Locked)
+	}
+}
+
+func holderString(r *Ranking) string {
+	var out string
+	for _, h := range r.Holders {
+		out += h.Address + ":" + h.Balance.String() + " "
+	}
+	return out
+}
+
+func TestSupplyDoAndUndoBlock(t *testing.T) {
+	s := newTestSupply(t)
+
+	genesis := &pb.InternalBlock{
+		Height: 0,
+		Transactions: []*pb.Transaction{{
+			Coinbase: true,
+			TxOutputs: []*protos.TxOutput{
+				output("alice", 1000, 0),
+				output("bob", 500, 3),
+				output("foundation", 200, -1),
+			},
+		}},
+	}
+	applyBlock(t, s, genesis, false)
+	checkInfo(t, s, -1, 1700, 500, 200)
+
+	// alice给carol转账100，手续费10给矿工miner
+	b1 := &pb.InternalBlock{
+		Height:   1,
+		Proposer: []byte("miner"),
+		Transactions: []*pb.Transaction{
+			{Coinbase: true, TxOutputs: []*protos.TxOutput{output("miner", 50, 0)}},
+			{
+				TxInputs:  []*protos.TxInput{input("alice", 1000)},
+				TxOutputs: []*protos.TxOutput{output("carol", 100, 0), output("alice", 890, 0), output("$", 10, 0)},
+			},
+		},
+	}
+	applyBlock(t, s, b1, false)
+	checkInfo(t, s, -1, 1750, 500, 200)
+	checkInfo(t, s, 0, 1700, 500, 200)
+
+	top, err := s.GetTopHolders(-1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := holderString(top); got != "alice:890 bob:500 foundation:200 " {
+		t.Fatalf("unexpected top holders:%s", got)
+	}
+	top, _ = s.GetTopHolders(0, 2)
+	if got := holderString(top); got != "alice:1000 bob:500 " {
+		t.Fatalf("unexpected history top holders:%s", got)
+	}
+
+	dist, err := s.GetDistribution(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// miner:60 | carol:100 bob:500 foundation:200 alice:890
+	if dist.Holders != 5 || len(dist.Buckets) != 2 || dist.Buckets[0].Holders != 1 ||
+		dist.Buckets[1].Holders != 4 || dist.Buckets[1].Amount.Int64() != 1690 {
+		t.Fatalf("unexpected distribution:%+v", dist)
+	}
+
+	// 高度3之后bob的余额解冻
+	b2 := &pb.InternalBlock{Height: 2}
+	b3 := &pb.InternalBlock{Height: 3}
+	applyBlock(t, s, b2, false)
+	applyBlock(t, s, b3, false)
+	checkInfo(t, s, -1, 1750, 0, 200)
+	checkInfo(t, s, 2, 1750, 500, 200)
+
+	applyBlock(t, s, b3, true)
+	applyBlock(t, s, b2, true)
+	applyBlock(t, s, b1, true)
+	checkInfo(t, s, -1, 1700, 500, 200)
+	top, _ = s.GetTopHolders(-1, 10)
+	if got := holderString(top); got != "alice:1000 bob:500 foundation:200 " {
+		t.Fatalf("unexpected top holders after undo:%s", got)
+	}
+	if _, err := s.GetInfo(1); err != ErrInvalidHeight {
+		t.Fatalf("query above tip should fail, err:%v", err)
+	}
+}
+
+func TestSupplyRebuild(t *testing.T) {
+	s := newTestSupply(t)
+
+	utxos := map[string]*utxo.UtxoItem{
+		utxo.GenUtxoKeyWithPrefix([]byte("alice"), []byte("tx1"), 0): {Amount: big.NewInt(300)},
+		utxo.GenUtxoKeyWithPrefix([]byte("alice"), []byte("tx2"), 1): {Amount: big.NewInt(200), FrozenHeight: 100},
+		utxo.GenUtxoKeyWithPrefix([]byte("bob"), []byte("tx3"), 0):   {Amount: big.NewInt(50)},
+	}
+	for key, item := range utxos {
+		buf, _ := item.Dumps()
+		if err := s.db.Put([]byte(key), buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 未确认交易bob->carol已经修改了utxo表
+	unconfirmed := &pb.Transaction{
+		Txid:      []byte("tx3"),
+		TxInputs:  []*protos.TxInput{input("bob", 80)},
+		TxOutputs: []*protos.TxOutput{output("bob", 50, 0), output("carol", 30, 0)},
+	}
+	item, _ := (&utxo.UtxoItem{Amount: big.NewInt(30)}).Dumps()
+	s.db.Put([]byte(utxo.GenUtxoKeyWithPrefix([]byte("carol"), []byte("tx3"), 1)), item)
+	buf, _ := proto.Marshal(unconfirmed)
+	s.db.Put(append([]byte(pb.UnconfirmedTablePrefix), unconfirmed.Txid...), buf)
+
+	need, err := s.NeedRebuild()
+	if err != nil || !need {
+		t.Fatalf("expect rebuild, need:%v err:%v", need, err)
+	}
+	if err := s.Rebuild(10); err != nil {
+		t.Fatal(err)
+	}
+	checkInfo(t, s, 10, 580, 200, 0)
+	top, _ := s.GetTopHolders(10, 5)
+	if got := holderString(top); got != "alice:500 bob:80 " {
+		t.Fatalf("unexpected top holders:%s", got)
+	}
+	if _, err := s.GetInfo(9); err != ErrHistoryUnavailable {
+		t.Fatalf("query below base should fail, err:%v", err)
+	}
+
+	b11 := &pb.InternalBlock{
+		Height:       11,
+		Transactions: []*pb.Transaction{unconfirmed},
+	}
+	applyBlock(t, s, b11, false)
+	checkInfo(t, s, 10, 580, 200, 0)
+	top, _ = s.GetTopHolders(-1, 5)
+	if got := holderString(top); got != "alice:500 bob:50 carol:30 " {
+		t.Fatalf("unexpected top holders:%s", got)
+	}
+}
@@ -582,7 +582,16 @@ func (t *State) verifyTxRWSets(tx *pb.Transaction) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	utxoReader := sandbox.NewUTXOReaderFromInput(utxoInput)
+	utxoReads, err := xmodel.ParseContractUtxoReads(tx)
+	if err != nil {
+		return false, err
+	}
+	utxoReader := sandbox.NewUTXOReaderFromRWSet(utxoInput, &contract.UTXORWSet{
+		Reads:           utxoReads.Reads,
+		FrozenReads:     utxoReads.FrozenReads,
+		ReadAddrs:       utxoReads.Addrs,
+		FrozenReadAddrs: utxoReads.FrozenAddrs,
+	})
 	sandBoxConfig := &contract.SandboxConfig{
 		XMReader:   reader,
 		UTXOReader: utxoReader,
@@ -12,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -85,6 +86,7 @@ type UtxoVM struct {
 	unconfirmTxAmount int64                    // 未确认的Tx数目，用于监控
 	bcname            string
 	batchCache        *sync.Map  // 同一个 batch 的 utxo 缓存，play block 时缓存同一个区块内的交易的 utxo。
+	batchSpent        *sync.Map  // 同一个 batch 内已经花费的 utxo，这些 utxo 在 batch 写入之前仍然存在于数据库中。
 	lastBatch         kvdb.Batch // 上一个交易对应的 batch，postTx 时每个交易的 batch 不同，但是执行区块时（walk 或者 play）batch 相同。
 }
 
@@ -113,6 +115,7 @@ func GenUtxoKeyWithPrefix(addr []byte, txid []byte, offset int32) string {
 func (uv *UtxoVM) CleanBatchCache(newBatch kvdb.Batch) {
 	if uv.lastBatch != newBatch {
 		uv.batchCache = &sync.Map{}
+		uv.batchSpent = &sync.Map{}
 		uv.lastBatch = newBatch
 	}
 }
@@ -125,6 +128,53 @@ func (uv *UtxoVM) InsertBatchCache(key, value interface{}) {
 // RemoveBatchCache remove key from batch cache.
 func (uv *UtxoVM) RemoveBatchCache(key interface{}) {
 	uv.batchCache.Delete(key)
+	uv.batchSpent.Store(key, true)
+}
+
+// loadUtxo 依次从utxo cache、batch cache和数据库中查找utxo，返回金额和冻结高度
+func (uv *UtxoVM) loadUtxo(addr, txid []byte, offset int32, batch kvdb.Batch) (amountBytes []byte, frozenHeight int64, err error) {
+	utxoKey := GenUtxoKey(addr, txid, offset)
+	uv.UtxoCache.Lock()
+	if l2Cache, exist := uv.UtxoCache.All[string(addr)]; exist {
+		uItem := l2Cache[pb.UTXOTablePrefix+utxoKey]
+		if uItem != nil {
+			amountBytes = uItem.Amount.Bytes()
+			frozenHeight = uItem.FrozenHeight
+		}
+	}
+	uv.UtxoCache.Unlock()
+
+	if amountBytes == nil && batch != nil && batch == uv.lastBatch {
+		// 如果 utxo cache 查找不到，从 batch cache 查找，如果此处查不到再去数据库查。
+		// 目的是解决同步一个区块时，utxo cache 不能缓存所有的 utxo 导致区块执行失败。
+		// 此处缓存为同一个区块内交易的 utxo 缓存。
+		value, ok := uv.batchCache.Load(GenUtxoKeyWithPrefix(addr, txid, offset))
+		if ok {
+			uItem := value.(*UtxoItem)
+			amountBytes = uItem.Amount.Bytes()
+			frozenHeight = uItem.FrozenHeight
+		}
+	}
+
+	if amountBytes == nil {
+		uBinary, findErr := uv.utxoTable.Get([]byte(utxoKey))
+		if findErr != nil {
+			if def.NormalizedKVError(findErr) == def.ErrKVNotFound {
+				uv.log.Error("not found utxo key:", "utxoKey", utxoKey)
+				return nil, 0, ErrUTXONotFound
+			}
+			uv.log.Warn("unexpected leveldb error when do checkInputEqualOutput", "findErr", findErr)
+			return nil, 0, findErr
+		}
+		uItem := &UtxoItem{}
+		uErr := uItem.Loads(uBinary)
+		if uErr != nil {
+			return nil, 0, uErr
+		}
+		amountBytes = uItem.Amount.Bytes()
+		frozenHeight = uItem.FrozenHeight
+	}
+	return amountBytes, frozenHeight, nil
 }
 
 // CheckInputEqualOutput 校验交易的输入输出是否相等
@@ -153,47 +203,9 @@ func (uv *UtxoVM) CheckInputEqualOutput(tx *pb.Transaction, batch kvdb.Batch) er
 			return ErrUTXODuplicated
 		}
 		utxoDedup[utxoKey] = true
-		var amountBytes []byte
-		var frozenHeight int64
-		uv.UtxoCache.Lock()
-		if l2Cache, exist := uv.UtxoCache.All[string(addr)]; exist {
-			uItem := l2Cache[pb.UTXOTablePrefix+utxoKey]
-			if uItem != nil {
-				amountBytes = uItem.Amount.Bytes()
-				frozenHeight = uItem.FrozenHeight
-			}
-		}
-		uv.UtxoCache.Unlock()
-
-		if amountBytes == nil && batch != nil && batch == uv.lastBatch {
-			// 如果 utxo cache 查找不到，从 batch cache 查找，如果此处查不到再去数据库查。
-			// 目的是解决同步一个区块时，utxo cache 不能缓存所有的 utxo 导致区块执行失败。
-			// 此处缓存为同一个区块内交易的 utxo 缓存。
-			value, ok := uv.batchCache.Load(GenUtxoKeyWithPrefix(addr, txid, offset))
-			if ok {
-				uItem := value.(*UtxoItem)
-				amountBytes = uItem.Amount.Bytes()
-				frozenHeight = uItem.FrozenHeight
-			}
-		}
-
-		if amountBytes == nil {
-			uBinary, findErr := uv.utxoTable.Get([]byte(utxoKey))
-			if findErr != nil {
-				if def.NormalizedKVError(findErr) == def.ErrKVNotFound {
-					uv.log.Error("not found utxo key:", "utxoKey", utxoKey)
-					return ErrUTXONotFound
-				}
-				uv.log.Warn("unexpected leveldb error when do checkInputEqualOutput", "findErr", findErr)
-				return findErr
-			}
-			uItem := &UtxoItem{}
-			uErr := uItem.Loads(uBinary)
-			if uErr != nil {
-				return uErr
-			}
-			amountBytes = uItem.Amount.Bytes()
-			frozenHeight = uItem.FrozenHeight
+		amountBytes, frozenHeight, err := uv.loadUtxo(addr, txid, offset, batch)
+		if err != nil {
+			return err
 		}
 		amount := big.NewInt(0)
 		amount.SetBytes(amountBytes)
@@ -565,6 +577,131 @@ func (uv *UtxoVM) GetBalance(addr string) (*big.Int, error) {
 	return balanceCopy, nil
 }
 
+// ListUtxo 列出地址下的全部utxo，frozen为true时只返回仍处于冻结状态的utxo，否则只返回可用的utxo。
+// 已经被其他交易锁定但尚未花费的utxo同样会被列出
+func (uv *UtxoVM) ListUtxo(addr string, frozen bool) ([]*protos.TxInput, error) {
+	return uv.listUtxo(addr, frozen, nil)
+}
+
+// listUtxo 列出地址下的utxo，batch为当前执行区块的batch时，
+// 同时计入batch内之前的交易产生和花费的utxo
+func (uv *UtxoVM) listUtxo(addr string, frozen bool, batch kvdb.Batch) ([]*protos.TxInput, error) {
+	curLedgerHeight := uv.ledger.GetMeta().GetTrunkHeight()
+	inBatch := batch != nil && batch == uv.lastBatch
+	addrPrefix := fmt.Sprintf("%s%s_", pb.UTXOTablePrefix, addr)
+	var txInputs []*protos.TxInput
+	appendUtxo := func(key string, uItem *UtxoItem) error {
+		isFrozen := uItem.FrozenHeight > curLedgerHeight || uItem.FrozenHeight == -1
+		if isFrozen != frozen {
+			return nil
+		}
+		refTxid, offset, err := uv.parseUtxoKeys(key)
+		if err != nil {
+			return err
+		}
+		txInputs = append(txInputs, &protos.TxInput{
+			RefTxid:      refTxid,
+			RefOffset:    int32(offset),
+			FromAddr:     []byte(addr),
+			Amount:       uItem.Amount.Bytes(),
+			FrozenHeight: uItem.FrozenHeight,
+		})
+		return nil
+	}
+
+	it := uv.ldb.NewIteratorWithPrefix([]byte(addrPrefix))
+	defer it.Release()
+	for it.Next() {
+		key := string(it.Key())
+		if inBatch {
+			if _, spent := uv.batchSpent.Load(key); spent {
+				continue
+			}
+		}
+		uItem := &UtxoItem{}
+		uErr := uItem.Loads(it.Value())
+		if uErr != nil {
+			return nil, uErr
+		}
+		if err := appendUtxo(key, uItem); err != nil {
+			return nil, err
+		}
+	}
+	if it.Error() != nil {
+		return nil, it.Error()
+	}
+	if !inBatch {
+		return txInputs, nil
+	}
+
+	// batch内产生的utxo按key排序，保证不同节点列出的顺序一致
+	var batchKeys []string
+	batchItems := map[string]*UtxoItem{}
+	uv.batchCache.Range(func(k, v interface{}) bool {
+		key, ok := k.(string)
+		if ok && strings.HasPrefix(key, addrPrefix) {
+			batchKeys = append(batchKeys, key)
+			batchItems[key] = v.(*UtxoItem)
+		}
+		return true
+	})
+	sort.Strings(batchKeys)
+	for _, key := range batchKeys {
+		if err := appendUtxo(key, batchItems[key]); err != nil {
+			return nil, err
+		}
+	}
+	return txInputs, nil
+}
+
+// CheckContractUtxoReads 校验合约余额查询读到的utxo。对读取过的每个地址重新列出utxo，
+// 读到的utxo必须和执行交易时地址下全部的utxo完全一致，冻结状态按当前高度重新计算，
+// 读取之后地址下的utxo被花费或者产生了新的utxo都会使交易的读集失效
+func (uv *UtxoVM) CheckContractUtxoReads(addrs []string, reads []*protos.TxInput, frozen bool, batch kvdb.Batch) error {
+	expected := make(map[string]map[string][]byte, len(addrs))
+	for _, addr := range addrs {
+		if _, ok := expected[addr]; ok {
+			uv.log.Warn("duplicated contract utxo read address", "addr", addr)
+			return ErrUnexpected
+		}
+		expected[addr] = map[string][]byte{}
+	}
+	for _, read := range reads {
+		utxos, ok := expected[string(read.GetFromAddr())]
+		if !ok {
+			uv.log.Warn("contract utxo read of unrecorded address", "addr", string(read.GetFromAddr()))
+			return ErrUnexpected
+		}
+		utxoKey := GenUtxoKeyWithPrefix(read.GetFromAddr(), read.GetRefTxid(), read.GetRefOffset())
+		if _, ok := utxos[utxoKey]; ok {
+			return ErrUTXODuplicated
+		}
+		utxos[utxoKey] = read.GetAmount()
+	}
+
+	for _, addr := range addrs {
+		actual, err := uv.listUtxo(addr, frozen, batch)
+		if err != nil {
+			return err
+		}
+		utxos := expected[addr]
+		if len(actual) != len(utxos) {
+			uv.log.Warn("contract utxo read missmatch utxos of address", "addr", addr,
+				"read", len(utxos), "actual", len(actual), "frozen", frozen)
+			return ErrUnexpected
+		}
+		for _, input := range actual {
+			utxoKey := GenUtxoKeyWithPrefix(input.GetFromAddr(), input.GetRefTxid(), input.GetRefOffset())
+			amount, ok := utxos[utxoKey]
+			if !ok || !bytes.Equal(amount, input.GetAmount()) {
+				uv.log.Warn("contract utxo read missmatch utxo", "reftxid", utils.F(input.GetRefTxid()), "frozen", frozen)
+				return ErrUnexpected
+			}
+		}
+	}
+	return nil
+}
+
 // Close 关闭utxo vm, 目前主要是关闭leveldb
 func (uv *UtxoVM) Close() {
 	uv.ldb.Close()
@@ -11,12 +11,23 @@ type UTXOSandbox struct {
 	inputCache  []*protos.TxInput
 	outputCache []*protos.TxOutput
 	utxoReader  contract.UtxoReader
+
+	// readCache和frozenReadCache记录GetBalance读到的utxo，
+	// 每个地址在一个沙盒内只读取一次，保证重复读的结果一致
+	readCache          []*protos.TxInput
+	frozenReadCache    []*protos.TxInput
+	readAddrs          map[string][]*protos.TxInput
+	frozenReadAddrs    map[string][]*protos.TxInput
+	readAddrList       []string
+	frozenReadAddrList []string
 }
 
 func NewUTXOSandbox(cfg *contract.SandboxConfig) *UTXOSandbox {
 	return &UTXOSandbox{
-		outputCache: []*protos.TxOutput{},
-		utxoReader:  cfg.UTXOReader,
+		outputCache:     []*protos.TxOutput{},
+		utxoReader:      cfg.UTXOReader,
+		readAddrs:       map[string][]*protos.TxInput{},
+		frozenReadAddrs: map[string][]*protos.TxInput{},
 	}
 }
 
@@ -43,9 +54,108 @@ func (u *UTXOSandbox) Transfer(from, to string, amount *big.Int) error {
 	return nil
 }
 
+// GetBalance 返回addr在沙盒视图下的余额，包含本沙盒内已经发生的转账。
+// 冻结余额只统计读到的冻结utxo，转账产生的输出不会被冻结。
+func (u *UTXOSandbox) GetBalance(addr string, frozen bool) (*big.Int, error) {
+	reads, err := u.listUtxo(addr, frozen)
+	if err != nil {
+		return nil, err
+	}
+	balance := new(big.Int)
+	for _, input := range reads {
+		balance.Add(balance, new(big.Int).SetBytes(input.GetAmount()))
+	}
+	if frozen {
+		return balance, nil
+	}
+	for _, input := range u.inputCache {
+		if string(input.GetFromAddr()) == addr {
+			balance.Sub(balance, new(big.Int).SetBytes(input.GetAmount()))
+		}
+	}
+	for _, output := range u.outputCache {
+		if string(output.GetToAddr()) == addr {
+			balance.Add(balance, new(big.Int).SetBytes(output.GetAmount()))
+		}
+	}
+	// 转账选中的utxo可能晚于读取产生，此时不会出现在读到的utxo里
+	if balance.Sign() < 0 {
+		balance.SetInt64(0)
+	}
+	return balance, nil
+}
+
+func (u *UTXOSandbox) listUtxo(addr string, frozen bool) ([]*protos.TxInput, error) {
+	addrs := u.readAddrs
+	if frozen {
+		addrs = u.frozenReadAddrs
+	}
+	if reads, ok := addrs[addr]; ok {
+		return reads, nil
+	}
+	lister, ok := u.utxoReader.(contract.UtxoLister)
+	if !ok {
+		return nil, errors.New("utxo reader can not list utxos")
+	}
+	reads, err := lister.ListUtxo(addr, frozen)
+	if err != nil {
+		return nil, err
+	}
+	addrs[addr] = reads
+	if frozen {
+		u.frozenReadCache = append(u.frozenReadCache, reads...)
+		u.frozenReadAddrList = append(u.frozenReadAddrList, addr)
+	} else {
+		u.readCache = append(u.readCache, reads...)
+		u.readAddrList = append(u.readAddrList, addr)
+	}
+	return reads, nil
+}
+
+// UTXOSnapshot 记录沙盒中utxo输入输出缓存的位置
+type UTXOSnapshot struct {
+	inputs  int
+	outputs int
+}
+
+// Snapshot 返回当前utxo缓存的位置，用于回滚
+func (u *UTXOSandbox) Snapshot() UTXOSnapshot {
+	return UTXOSnapshot{
+		inputs:  len(u.inputCache),
+		outputs: len(u.outputCache),
+	}
+}
+
+// RollbackTo 丢弃快照之后的转账输出。
+// 快照之后选中的utxo已经被记录和锁定，验证时也会按相同顺序重放，因此保留这些输入，
+// 按地址首次出现的顺序把金额找零给原地址
+func (u *UTXOSandbox) RollbackTo(snapshot UTXOSnapshot) {
+	u.outputCache = u.outputCache[:snapshot.outputs]
+	var addrs []string
+	refunds := map[string]*big.Int{}
+	for _, input := range u.inputCache[snapshot.inputs:] {
+		addr := string(input.GetFromAddr())
+		if _, ok := refunds[addr]; !ok {
+			addrs = append(addrs, addr)
+			refunds[addr] = new(big.Int)
+		}
+		refunds[addr].Add(refunds[addr], new(big.Int).SetBytes(input.GetAmount()))
+	}
+	for _, addr := range addrs {
+		u.outputCache = append(u.outputCache, &protos.TxOutput{
+			Amount: refunds[addr].Bytes(),
+			ToAddr: []byte(addr),
+		})
+	}
+}
+
 func (uc *UTXOSandbox) GetUTXORWSets() *contract.UTXORWSet {
 	return &contract.UTXORWSet{
-		Rset: uc.inputCache,
-		WSet: uc.outputCache,
+		Rset:            uc.inputCache,
+		WSet:            uc.outputCache,
+		Reads:           uc.readCache,
+		FrozenReads:     uc.frozenReadCache,
+		ReadAddrs:       uc.readAddrList,
+		FrozenReadAddrs: uc.frozenReadAddrList,
 	}
 }
@@ -59,13 +59,13 @@ var GenesisConf = []byte(`
 }
     `)
 
-func TestBasicFunc(t *testing.T) {
+// newTestUtxo 创建一条只有创世块的链，返回链上状态和独立的utxo实例
+func newTestUtxo(t *testing.T) (*state.State, *utxo.UtxoVM, *pb.Transaction, func()) {
 	workspace, dirErr := os.MkdirTemp("/tmp", "")
 	if dirErr != nil {
 		t.Fatal(dirErr)
 	}
 	os.RemoveAll(workspace)
-	defer os.RemoveAll(workspace)
 	econf, err := mock.NewEnvConfForTest()
 	if err != nil {
 		t.Fatal(err)
@@ -150,6 +150,14 @@ func TestBasicFunc(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	return stateHandle, utxoHandle, tx, func() {
+		os.RemoveAll(workspace)
+	}
+}
+
+func TestBasicFunc(t *testing.T) {
+	stateHandle, utxoHandle, tx, closer := newTestUtxo(t)
+	defer closer()
 	balance, err := utxoHandle.GetBalance(BobAddress)
 	utxoHandle.AddBalance([]byte(BobAddress), big.NewInt(10000000))
 	balance, err = utxoHandle.GetBalance(BobAddress)
@@ -235,3 +243,41 @@ func TestBasicFunc(t *testing.T) {
 	}
 	t.Log("records", record)
 }
+
+func TestCheckContractUtxoReads(t *testing.T) {
+	_, utxoHandle, _, closer := newTestUtxo(t)
+	defer closer()
+
+	reads, err := utxoHandle.ListUtxo(BobAddress, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reads) == 0 {
+		t.Fatal("expect utxos of bob")
+	}
+	addrs := []string{BobAddress}
+	if err := utxoHandle.CheckContractUtxoReads(addrs, reads, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	// 读取过的地址没有utxo时同样会校验
+	if err := utxoHandle.CheckContractUtxoReads([]string{"empty"}, nil, false, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		addrs  []string
+		reads  []*protos.TxInput
+		frozen bool
+	}{
+		{"utxo left out", addrs, reads[:len(reads)-1], false},
+		{"address left out", nil, reads, false},
+		{"frozen split", addrs, reads, true},
+		{"duplicated utxo", addrs, append(reads, reads[0]), false},
+	}
+	for _, c := range cases {
+		if err := utxoHandle.CheckContractUtxoReads(c.addrs, c.reads, c.frozen, nil); err == nil {
+			t.Errorf("%s: expect forged utxo reads rejected", c.name)
+		}
+	}
+}
@@ -25,8 +25,13 @@ const (
 )
 
 var (
-	contractUtxoInputKey  = []byte("ContractUtxo.Inputs")
-	contractUtxoOutputKey = []byte("ContractUtxo.Outputs")
+	contractUtxoInputKey      = []byte("ContractUtxo.Inputs")
+	contractUtxoOutputKey     = []byte("ContractUtxo.Outputs")
+	contractUtxoReadKey       = []byte("ContractUtxo.Reads")
+	contractUtxoFrozenKey     = []byte("ContractUtxo.FrozenReads")
+	contractUtxoAddrKey       = []byte("ContractUtxo.ReadAddrs")
+	contractUtxoFrozenAddrKey = []byte("ContractUtxo.FrozenReadAddrs")
+	contractBlockReadKey      = []byte("ContractBlock.Reads")
 )
 
 // XModel xmodel data structure
@@ -367,6 +372,92 @@ func GenWriteKeyWithPrefix(txOutputExt *protos.TxOutputExt) string {
 	return pb.ExtUtxoTablePrefix + baseWriteSetKey
 }
 
+// ContractUtxoReads is the utxos and addresses read by contract balance queries
+type ContractUtxoReads struct {
+	Addrs       []string
+	Reads       []*protos.TxInput
+	FrozenAddrs []string
+	FrozenReads []*protos.TxInput
+}
+
+// MakeUtxoReadAddrs encode addresses read by contract balance queries as TxInputs with only FromAddr
+func MakeUtxoReadAddrs(addrs []string) []*protos.TxInput {
+	inputs := make([]*protos.TxInput, 0, len(addrs))
+	for _, addr := range addrs {
+		inputs = append(inputs, &protos.TxInput{
+			FromAddr: []byte(addr),
+		})
+	}
+	return inputs
+}
+
+func parseUtxoReadAddrs(value []byte) ([]string, error) {
+	var inputs []*protos.TxInput
+	if err := UnmsarshalMessages(value, &inputs); err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		addrs = append(addrs, string(input.GetFromAddr()))
+	}
+	return addrs, nil
+}
+
+// ParseContractUtxoReads parse utxos and addresses read by contract balance queries from tx write sets
+func ParseContractUtxoReads(tx *pb.Transaction) (*ContractUtxoReads, error) {
+	reads := new(ContractUtxoReads)
+	for _, out := range tx.GetTxOutputsExt() {
+		if out.GetBucket() != TransientBucket {
+			continue
+		}
+		var err error
+		switch {
+		case bytes.Equal(out.GetKey(), contractUtxoReadKey):
+			err = UnmsarshalMessages(out.GetValue(), &reads.Reads)
+		case bytes.Equal(out.GetKey(), contractUtxoFrozenKey):
+			err = UnmsarshalMessages(out.GetValue(), &reads.FrozenReads)
+		case bytes.Equal(out.GetKey(), contractUtxoAddrKey):
+			reads.Addrs, err = parseUtxoReadAddrs(out.GetValue())
+		case bytes.Equal(out.GetKey(), contractUtxoFrozenAddrKey):
+			reads.FrozenAddrs, err = parseUtxoReadAddrs(out.GetValue())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return reads, nil
+}
+
+// MakeBlockReads encode trunk blocks read by contract as TxInputs with only RefTxid
+func MakeBlockReads(blockids [][]byte) []*protos.TxInput {
+	inputs := make([]*protos.TxInput, 0, len(blockids))
+	for _, id := range blockids {
+		inputs = append(inputs, &protos.TxInput{
+			RefTxid: id,
+		})
+	}
+	return inputs
+}
+
+// ParseContractBlockReads parse trunk blocks read by contract from tx write sets
+func ParseContractBlockReads(tx *pb.Transaction) ([][]byte, error) {
+	for _, out := range tx.GetTxOutputsExt() {
+		if out.GetBucket() != TransientBucket || !bytes.Equal(out.GetKey(), contractBlockReadKey) {
+			continue
+		}
+		var inputs []*protos.TxInput
+		if err := UnmsarshalMessages(out.GetValue(), &inputs); err != nil {
+			return nil, err
+		}
+		blockids := make([][]byte, 0, len(inputs))
+		for _, input := range inputs {
+			blockids = append(blockids, input.GetRefTxid())
+		}
+		return blockids, nil
+	}
+	return nil, nil
+}
+
 // ParseContractUtxoInputs parse contract utxo inputs from tx write sets
 func ParseContractUtxoInputs(tx *pb.Transaction) ([]*protos.TxInput, error) {
 	var (
@@ -22,9 +22,10 @@ import (
 )
 
 var (
-	ErrNegativeAmount = errors.New("amount in transaction can not be negative number")
-	ErrTxNotFound     = errors.New("transaction not found")
-	ErrUnexpected     = errors.New("this is a unexpected error")
+	ErrNegativeAmount      = errors.New("amount in transaction can not be negative number")
+	ErrTxNotFound          = errors.New("transaction not found")
+	ErrUnexpected          = errors.New("this is a unexpected error")
+	ErrInvalidAwardOutputs = errors.New("invalid award tx outputs")
 )
 
 const (
@@ -91,6 +92,28 @@ func GenerateAwardTx(address, awardAmount string, desc []byte) (*pb.Transaction,
 	return utxoTx, nil
 }
 
+// 生成多个输出的奖励TX，用于共识将区块奖励分配给多个地址，第一个输出为出块人
+func GenerateSplitAwardTx(outputs []*protos.TxOutput, desc []byte) (*pb.Transaction, error) {
+	if len(outputs) == 0 {
+		return nil, ErrInvalidAwardOutputs
+	}
+	utxoTx := &pb.Transaction{Version: TxVersion}
+	for _, output := range outputs {
+		if len(output.ToAddr) == 0 {
+			return nil, ErrInvalidAwardOutputs
+		}
+		utxoTx.TxOutputs = append(utxoTx.TxOutputs, &protos.TxOutput{
+			ToAddr: output.ToAddr,
+			Amount: output.Amount,
+		})
+	}
+	utxoTx.Desc = desc
+	utxoTx.Coinbase = true
+	utxoTx.Timestamp = time.Now().UnixNano()
+	utxoTx.Txid, _ = txhash.MakeTransactionID(utxoTx)
+	return utxoTx, nil
+}
+
 // 生成只有Desc的空交易
 func GenerateEmptyTx(desc []byte) (*pb.Transaction, error) {
 	utxoTx := &pb.Transaction{Version: TxVersion}
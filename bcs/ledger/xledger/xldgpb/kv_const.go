@@ -12,4 +12,5 @@ const (
 	ExtUtxoTablePrefix       = "ZU"
 	BlockHeightPrefix        = "ZH"
 	BranchInfoPrefix         = "ZI"
+	SupplyTablePrefix        = "ZS"
 )
@@ -8,6 +8,7 @@ import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
 	xldgpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xpb "github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
 	protos "github.com/xuperchain/xupercore/protos"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -899,6 +900,522 @@ func (m *QueryChainStatusResp) GetBranchBlockId() []string {
 	return nil
 }
 
+type QueryAddressTxsReq struct {
+	Header  *ReqHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Bcname  string     `protobuf:"bytes,2,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	Address string     `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	// 分页游标，为空时从头开始
+	Cursor string `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit  int32  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	// 是否按高度倒序
+	Reverse              bool     `protobuf:"varint,6,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryAddressTxsReq) Reset()         { *m = QueryAddressTxsReq{} }
+func (m *QueryAddressTxsReq) String() string { return proto.CompactTextString(m) }
+func (*QueryAddressTxsReq) ProtoMessage()    {}
+func (*QueryAddressTxsReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_db0991b9525664ca, []int{15}
+}
+
+func (m *QueryAddressTxsReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryAddressTxsReq.Unmarshal(m, b)
+}
+func (m *QueryAddressTxsReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryAddressTxsReq.Marshal(b, m, deterministic)
+}
+func (m *QueryAddressTxsReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryAddressTxsReq.Merge(m, src)
+}
+func (m *QueryAddressTxsReq) XXX_Size() int {
+	return xxx_messageInfo_QueryAddressTxsReq.Size(m)
+}
+func (m *QueryAddressTxsReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryAddressTxsReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryAddressTxsReq proto.InternalMessageInfo
+
+func (m *QueryAddressTxsReq) GetHeader() *ReqHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *QueryAddressTxsReq) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *QueryAddressTxsReq) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *QueryAddressTxsReq) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *QueryAddressTxsReq) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *QueryAddressTxsReq) GetReverse() bool {
+	if m != nil {
+		return m.Reverse
+	}
+	return false
+}
+
+type QueryContractTxsReq struct {
+	Header               *ReqHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Bcname               string     `protobuf:"bytes,2,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	ContractName         string     `protobuf:"bytes,3,opt,name=contractName,proto3" json:"contractName,omitempty"`
+	Cursor               string     `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit                int32      `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Reverse              bool       `protobuf:"varint,6,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *QueryContractTxsReq) Reset()         { *m = QueryContractTxsReq{} }
+func (m *QueryContractTxsReq) String() string { return proto.CompactTextString(m) }
+func (*QueryContractTxsReq) ProtoMessage()    {}
+func (*QueryContractTxsReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_db0991b9525664ca, []int{16}
+}
+
+func (m *QueryContractTxsReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryContractTxsReq.Unmarshal(m, b)
+}
+func (m *QueryContractTxsReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryContractTxsReq.Marshal(b, m, deterministic)
+}
+func (m *QueryContractTxsReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryContractTxsReq.Merge(m, src)
+}
+func (m *QueryContractTxsReq) XXX_Size() int {
+	return xxx_messageInfo_QueryContractTxsReq.Size(m)
+}
+func (m *QueryContractTxsReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryContractTxsReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryContractTxsReq proto.InternalMessageInfo
+
+func (m *QueryContractTxsReq) GetHeader() *ReqHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *QueryContractTxsReq) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *QueryContractTxsReq) GetContractName() string {
+	if m != nil {
+		return m.ContractName
+	}
+	return ""
+}
+
+func (m *QueryContractTxsReq) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *QueryContractTxsReq) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *QueryContractTxsReq) GetReverse() bool {
+	if m != nil {
+		return m.Reverse
+	}
+	return false
+}
+
+type QueryIndexTxsResp struct {
+	Header               *RespHeader    `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Txs                  []*xpb.TxIndex `protobuf:"bytes,2,rep,name=txs,proto3" json:"txs,omitempty"`
+	NextCursor           string         `protobuf:"bytes,3,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
+	IndexedHeight        int64          `protobuf:"varint,4,opt,name=indexedHeight,proto3" json:"indexedHeight,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *QueryIndexTxsResp) Reset()         { *m = QueryIndexTxsResp{} }
+func (m *QueryIndexTxsResp) String() string { return proto.CompactTextString(m) }
+func (*QueryIndexTxsResp) ProtoMessage()    {}
+func (*QueryIndexTxsResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_db0991b9525664ca, []int{17}
+}
+
+func (m *QueryIndexTxsResp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryIndexTxsResp.Unmarshal(m, b)
+}
+func (m *QueryIndexTxsResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryIndexTxsResp.Marshal(b, m, deterministic)
+}
+func (m *QueryIndexTxsResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryIndexTxsResp.Merge(m, src)
+}
+func (m *QueryIndexTxsResp) XXX_Size() int {
+	return xxx_messageInfo_QueryIndexTxsResp.Size(m)
+}
+func (m *QueryIndexTxsResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryIndexTxsResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryIndexTxsResp proto.InternalMessageInfo
+
+func (m *QueryIndexTxsResp) GetHeader() *RespHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *QueryIndexTxsResp) GetTxs() []*xpb.TxIndex {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+func (m *QueryIndexTxsResp) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *QueryIndexTxsResp) GetIndexedHeight() int64 {
+	if m != nil {
+		return m.IndexedHeight
+	}
+	return 0
+}
+
+type QueryContractEventsReq struct {
+	Header               *ReqHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Bcname               string     `protobuf:"bytes,2,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	ContractName         string     `protobuf:"bytes,3,opt,name=contractName,proto3" json:"contractName,omitempty"`
+	EventName            string     `protobuf:"bytes,4,opt,name=eventName,proto3" json:"eventName,omitempty"`
+	Cursor               string     `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit                int32      `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Reverse              bool       `protobuf:"varint,7,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *QueryContractEventsReq) Reset()         { *m = QueryContractEventsReq{} }
+func (m *QueryContractEventsReq) String() string { return proto.CompactTextString(m) }
+func (*QueryContractEventsReq) ProtoMessage()    {}
+func (*QueryContractEventsReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_db0991b9525664ca, []int{18}
+}
+
+func (m *QueryContractEventsReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryContractEventsReq.Unmarshal(m, b)
+}
+func (m *QueryContractEventsReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryContractEventsReq.Marshal(b, m, deterministic)
+}
+func (m *QueryContractEventsReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryContractEventsReq.Merge(m, src)
+}
+func (m *QueryContractEventsReq) XXX_Size() int {
+	return xxx_messageInfo_QueryContractEventsReq.Size(m)
+}
+func (m *QueryContractEventsReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryContractEventsReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryContractEventsReq proto.InternalMessageInfo
+
+func (m *QueryContractEventsReq) GetHeader() *ReqHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *QueryContractEventsReq) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *QueryContractEventsReq) GetContractName() string {
+	if m != nil {
+		return m.ContractName
+	}
+	return ""
+}
+
+func (m *QueryContractEventsReq) GetEventName() string {
+	if m != nil {
+		return m.EventName
+	}
+	return ""
+}
+
+func (m *QueryContractEventsReq) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *QueryContractEventsReq) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *QueryContractEventsReq) GetReverse() bool {
+	if m != nil {
+		return m.Reverse
+	}
+	return false
+}
+
+type QueryContractEventsResp struct {
+	Header               *RespHeader       `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Events               []*xpb.EventIndex `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+	NextCursor           string            `protobuf:"bytes,3,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
+	IndexedHeight        int64             `protobuf:"varint,4,opt,name=indexedHeight,proto3" json:"indexedHeight,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *QueryContractEventsResp) Reset()         { *m = QueryContractEventsResp{} }
+func (m *QueryContractEventsResp) String() string { return proto.CompactTextString(m) }
+func (*QueryContractEventsResp) ProtoMessage()    {}
+func (*QueryContractEventsResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_db0991b9525664ca, []int{19}
+}
+
+func (m *QueryContractEventsResp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryContractEventsResp.Unmarshal(m, b)
+}
+func (m *QueryContractEventsResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryContractEventsResp.Marshal(b, m, deterministic)
+}
+func (m *QueryContractEventsResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryContractEventsResp.Merge(m, src)
+}
+func (m *QueryContractEventsResp) XXX_Size() int {
+	return xxx_messageInfo_QueryContractEventsResp.Size(m)
+}
+func (m *QueryContractEventsResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryContractEventsResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryContractEventsResp proto.InternalMessageInfo
+
+func (m *QueryContractEventsResp) GetHeader() *RespHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *QueryContractEventsResp) GetEvents() []*xpb.EventIndex {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *QueryContractEventsResp) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *QueryContractEventsResp) GetIndexedHeight() int64 {
+	if m != nil {
+		return m.IndexedHeight
+	}
+	return 0
+}
+
+type QueryTxHistoryReq struct {
+	Header *ReqHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Bcname string     `protobuf:"bytes,2,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	// 普通地址或合约账户
+	Address              string   `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Cursor               string   `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit                int32    `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Reverse              bool     `protobuf:"varint,6,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryTxHistoryReq) Reset()         { *m = QueryTxHistoryReq{} }
+func (m *QueryTxHistoryReq) String() string { return proto.CompactTextString(m) }
+func (*QueryTxHistoryReq) ProtoMessage()    {}
+func (*QueryTxHistoryReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_db0991b9525664ca, []int{20}
+}
+
+func (m *QueryTxHistoryReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryTxHistoryReq.Unmarshal(m, b)
+}
+func (m *QueryTxHistoryReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryTxHistoryReq.Marshal(b, m, deterministic)
+}
+func (m *QueryTxHistoryReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryTxHistoryReq.Merge(m, src)
+}
+func (m *QueryTxHistoryReq) XXX_Size() int {
+	return xxx_messageInfo_QueryTxHistoryReq.Size(m)
+}
+func (m *QueryTxHistoryReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryTxHistoryReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryTxHistoryReq proto.InternalMessageInfo
+
+func (m *QueryTxHistoryReq) GetHeader() *ReqHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *QueryTxHistoryReq) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *QueryTxHistoryReq) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *QueryTxHistoryReq) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *QueryTxHistoryReq) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *QueryTxHistoryReq) GetReverse() bool {
+	if m != nil {
+		return m.Reverse
+	}
+	return false
+}
+
+type QueryTxHistoryResp struct {
+	Header               *RespHeader          `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Items                []*xpb.TxHistoryItem `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	NextCursor           string               `protobuf:"bytes,3,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
+	IndexedHeight        int64                `protobuf:"varint,4,opt,name=indexedHeight,proto3" json:"indexedHeight,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *QueryTxHistoryResp) Reset()         { *m = QueryTxHistoryResp{} }
+func (m *QueryTxHistoryResp) String() string { return proto.CompactTextString(m) }
+func (*QueryTxHistoryResp) ProtoMessage()    {}
+func (*QueryTxHistoryResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_db0991b9525664ca, []int{21}
+}
+
+func (m *QueryTxHistoryResp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryTxHistoryResp.Unmarshal(m, b)
+}
+func (m *QueryTxHistoryResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryTxHistoryResp.Marshal(b, m, deterministic)
+}
+func (m *QueryTxHistoryResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryTxHistoryResp.Merge(m, src)
+}
+func (m *QueryTxHistoryResp) XXX_Size() int {
+	return xxx_messageInfo_QueryTxHistoryResp.Size(m)
+}
+func (m *QueryTxHistoryResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryTxHistoryResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryTxHistoryResp proto.InternalMessageInfo
+
+func (m *QueryTxHistoryResp) GetHeader() *RespHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *QueryTxHistoryResp) GetItems() []*xpb.TxHistoryItem {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *QueryTxHistoryResp) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *QueryTxHistoryResp) GetIndexedHeight() int64 {
+	if m != nil {
+		return m.IndexedHeight
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*ReqHeader)(nil), "xchainpb.ReqHeader")
 	proto.RegisterType((*RespHeader)(nil), "xchainpb.RespHeader")
@@ -915,67 +1432,95 @@ func init() {
 	proto.RegisterType((*QueryBlockResp)(nil), "xchainpb.QueryBlockResp")
 	proto.RegisterType((*QueryChainStatusReq)(nil), "xchainpb.QueryChainStatusReq")
 	proto.RegisterType((*QueryChainStatusResp)(nil), "xchainpb.QueryChainStatusResp")
+	proto.RegisterType((*QueryAddressTxsReq)(nil), "xchainpb.QueryAddressTxsReq")
+	proto.RegisterType((*QueryContractTxsReq)(nil), "xchainpb.QueryContractTxsReq")
+	proto.RegisterType((*QueryIndexTxsResp)(nil), "xchainpb.QueryIndexTxsResp")
+	proto.RegisterType((*QueryContractEventsReq)(nil), "xchainpb.QueryContractEventsReq")
+	proto.RegisterType((*QueryContractEventsResp)(nil), "xchainpb.QueryContractEventsResp")
+	proto.RegisterType((*QueryTxHistoryReq)(nil), "xchainpb.QueryTxHistoryReq")
+	proto.RegisterType((*QueryTxHistoryResp)(nil), "xchainpb.QueryTxHistoryResp")
 }
 
 func init() { proto.RegisterFile("xchain.proto", fileDescriptor_db0991b9525664ca) }
 
 var fileDescriptor_db0991b9525664ca = []byte{
-	// 876 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xdd, 0x6e, 0x1b, 0x45,
-	0x14, 0xee, 0xc6, 0x8d, 0xbd, 0x3e, 0x4e, 0x42, 0x99, 0xd6, 0xed, 0xd6, 0xfc, 0x59, 0x0b, 0x17,
-	0x96, 0x52, 0x39, 0x8a, 0x11, 0xd0, 0x3b, 0x94, 0x44, 0x48, 0x58, 0x4a, 0x2b, 0x98, 0x14, 0x89,
-	0xbb, 0x68, 0xbc, 0x7b, 0xb0, 0x57, 0x59, 0xcf, 0x38, 0x33, 0xe3, 0x6a, 0xb9, 0x47, 0x42, 0x82,
-	0x1b, 0x2e, 0x79, 0x04, 0xee, 0x78, 0x09, 0x24, 0x5e, 0x84, 0x07, 0x41, 0x33, 0xfb, 0x37, 0x59,
-	0xd2, 0x08, 0x4b, 0xee, 0xd5, 0xee, 0xf9, 0x3f, 0xe7, 0x3b, 0x3f, 0x03, 0x7b, 0x59, 0xb4, 0x60,
-	0x09, 0x1f, 0xaf, 0xa4, 0xd0, 0x82, 0xf8, 0x39, 0xb5, 0x9a, 0x0d, 0x8e, 0xb3, 0xf5, 0x0a, 0x65,
-	0x24, 0x24, 0x1e, 0xcd, 0x22, 0x75, 0x94, 0x62, 0x3c, 0x47, 0x79, 0x94, 0x55, 0xdf, 0x78, 0xbe,
-	0x9a, 0x95, 0x64, 0x6e, 0x3c, 0xf8, 0xa8, 0x36, 0xb1, 0x0c, 0x75, 0x14, 0x09, 0xae, 0x25, 0x8b,
-	0x74, 0xae, 0x10, 0x7e, 0x09, 0x5d, 0x8a, 0xd7, 0x5f, 0x23, 0x8b, 0x51, 0x92, 0x3e, 0xb4, 0x53,
-	0x31, 0xbf, 0x4c, 0xe2, 0xc0, 0x1b, 0x7a, 0xa3, 0x2e, 0xdd, 0x4d, 0xc5, 0x7c, 0x1a, 0x93, 0xf7,
-	0xa0, 0xab, 0x30, 0xfd, 0xe1, 0x92, 0xb3, 0x25, 0x06, 0x3b, 0x56, 0xe2, 0x1b, 0xc6, 0x4b, 0xb6,
-	0xc4, 0x50, 0x02, 0x50, 0x54, 0xab, 0xbb, 0x3d, 0x3c, 0x05, 0x1f, 0xa5, 0xbc, 0x8c, 0x44, 0x9c,
-	0x3b, 0x68, 0xd1, 0x0e, 0x4a, 0x79, 0x26, 0x62, 0x24, 0x4f, 0xc0, 0xfc, 0x5e, 0x2e, 0xd5, 0x3c,
-	0x68, 0x59, 0x93, 0x36, 0x4a, 0xf9, 0x42, 0xcd, 0x8d, 0x8d, 0x49, 0x14, 0x8d, 0xb3, 0xfb, 0x56,
-	0xd2, 0xb1, 0xf4, 0x34, 0x0e, 0x3f, 0x87, 0xce, 0x29, 0x53, 0x48, 0xf1, 0x9a, 0x1c, 0x42, 0x7b,
-	0x61, 0x43, 0xdb, 0x80, 0xbd, 0xc9, 0xc3, 0x71, 0x09, 0xd7, 0xb8, 0xaa, 0x8b, 0x16, 0x2a, 0xe1,
-	0x73, 0xf0, 0x73, 0x3b, 0xb5, 0x22, 0xcf, 0x1a, 0x86, 0x8f, 0x5c, 0xc3, 0xb2, 0x9e, 0xca, 0xf2,
-	0x57, 0x0f, 0x7a, 0x17, 0xeb, 0xd9, 0x32, 0xd1, 0xaf, 0xb2, 0x4d, 0xc3, 0x92, 0xc7, 0xd0, 0x9e,
-	0x45, 0x0e, 0x78, 0x05, 0x45, 0x08, 0xdc, 0xd7, 0x59, 0x12, 0xdb, 0xba, 0xf7, 0xa8, 0xfd, 0x27,
-	0x1f, 0xc3, 0x8e, 0xce, 0x6c, 0xbd, 0xd6, 0xa9, 0xed, 0xe9, 0xf8, 0x95, 0x64, 0x5c, 0xb1, 0x48,
-	0x27, 0x82, 0xd3, 0x1d, 0x9d, 0x85, 0x7f, 0x79, 0x00, 0xdf, 0x48, 0xfc, 0x2a, 0xc3, 0x68, 0x6b,
-	0xc9, 0x1c, 0x83, 0x2f, 0xf1, 0x7a, 0x8d, 0x4a, 0xab, 0xa0, 0x35, 0x6c, 0x8d, 0x7a, 0x93, 0x7e,
-	0x3e, 0x22, 0x6a, 0x3c, 0xe5, 0xaf, 0xc5, 0x95, 0x41, 0xdb, 0x48, 0x69, 0xa5, 0x46, 0xde, 0x87,
-	0x6e, 0xc2, 0x13, 0x9d, 0x30, 0x2d, 0x64, 0xd1, 0xa2, 0x9a, 0x41, 0x86, 0xd0, 0x63, 0x6b, 0xbd,
-	0x30, 0x66, 0x89, 0xc4, 0x60, 0x77, 0xd8, 0x1a, 0x75, 0xa9, 0xcb, 0x0a, 0x7f, 0xf6, 0xa0, 0x57,
-	0x95, 0xb1, 0x69, 0x4b, 0xde, 0x58, 0xc8, 0xc4, 0x14, 0xa2, 0x56, 0x82, 0x2b, 0xb4, 0xc8, 0xf6,
-	0x26, 0x8f, 0x9b, 0x85, 0xe4, 0x52, 0x5a, 0xe9, 0x85, 0x7f, 0x78, 0xb0, 0x7f, 0x81, 0x29, 0x46,
-	0xfa, 0x3b, 0x9d, 0x89, 0xad, 0x61, 0x1a, 0x40, 0x87, 0xc5, 0xb1, 0x44, 0xa5, 0x8a, 0xd9, 0x2e,
-	0x49, 0x03, 0x9d, 0x16, 0x9a, 0xa5, 0x2f, 0x11, 0xe3, 0x60, 0x37, 0x87, 0xae, 0x62, 0x90, 0x01,
-	0xf8, 0x1c, 0x31, 0x3e, 0x17, 0xd1, 0x55, 0xd0, 0x1e, 0x7a, 0x23, 0x9f, 0x56, 0x74, 0xf8, 0x8b,
-	0x07, 0x07, 0x6e, 0xaa, 0x1b, 0xe3, 0x36, 0x02, 0x7f, 0xad, 0x33, 0x71, 0x9e, 0x28, 0x1d, 0xec,
-	0xd8, 0x46, 0xef, 0x95, 0x73, 0x66, 0x3d, 0x56, 0x52, 0xd3, 0x41, 0x9b, 0xd3, 0xc9, 0x52, 0xac,
-	0xb9, 0x2e, 0x4a, 0x70, 0x59, 0x21, 0x02, 0x7c, 0xbb, 0x46, 0xf9, 0xe3, 0xdb, 0x5d, 0x8a, 0xf0,
-	0x4f, 0x0f, 0x7a, 0x55, 0x9c, 0x8d, 0x0b, 0x3e, 0x86, 0xb6, 0xd2, 0x4c, 0xaf, 0x95, 0x8d, 0x74,
-	0x30, 0x79, 0x7a, 0xcb, 0x5a, 0x5d, 0x58, 0x05, 0x5a, 0x28, 0x9a, 0x06, 0xc4, 0x89, 0xd2, 0x8c,
-	0x47, 0xf9, 0x0c, 0xb5, 0x68, 0x45, 0xff, 0xbf, 0x0d, 0xfd, 0xcd, 0x83, 0x7d, 0x9b, 0xf1, 0x69,
-	0x2a, 0xa2, 0xab, 0x6d, 0x0e, 0xd4, 0xcc, 0x38, 0x9c, 0x96, 0xf8, 0x94, 0xa4, 0xe9, 0x95, 0x19,
-	0x91, 0x33, 0xc1, 0x35, 0x72, 0x6d, 0xd3, 0xf3, 0xa9, 0xcb, 0x0a, 0x7f, 0xf7, 0xe0, 0xc0, 0x4d,
-	0x69, 0x63, 0x1c, 0x0f, 0x1b, 0x38, 0x56, 0xc5, 0x5b, 0x87, 0x0d, 0x04, 0x0f, 0x61, 0xd7, 0xa6,
-	0x56, 0xac, 0x60, 0xbf, 0xd4, 0x9d, 0x72, 0x8d, 0x92, 0xb3, 0x34, 0x4f, 0x22, 0xd7, 0x09, 0x7f,
-	0xf2, 0xe0, 0xa1, 0x4d, 0xed, 0xcc, 0x44, 0x2f, 0x3c, 0x6d, 0x0b, 0xb3, 0x11, 0xbc, 0x63, 0x60,
-	0x38, 0x95, 0x8c, 0x47, 0x8b, 0xd3, 0x2a, 0x27, 0x9f, 0x36, 0xd9, 0xe1, 0x3f, 0x1e, 0x3c, 0xfa,
-	0x6f, 0x1a, 0x5b, 0x3c, 0x4c, 0x90, 0xbf, 0xcd, 0x2f, 0x50, 0xb3, 0x02, 0x17, 0x52, 0xe2, 0x72,
-	0x5e, 0x49, 0xa8, 0xa3, 0x45, 0x9e, 0xe5, 0xcb, 0x6a, 0x2d, 0xf2, 0x91, 0x7b, 0xe0, 0x2e, 0xab,
-	0xd5, 0xaf, 0x34, 0xc8, 0x27, 0xb0, 0x3f, 0xab, 0xeb, 0x99, 0xc6, 0xc5, 0xd1, 0xbd, 0xc9, 0x9c,
-	0xfc, 0xdd, 0x82, 0xf6, 0xf7, 0x36, 0x7f, 0xf2, 0x19, 0xc0, 0xd9, 0x02, 0xa3, 0xab, 0x93, 0x34,
-	0x79, 0x8d, 0xe4, 0xdd, 0xba, 0xac, 0xe2, 0x79, 0x1d, 0x90, 0x26, 0x4b, 0xad, 0xc2, 0x7b, 0xe4,
-	0x0b, 0xf0, 0xcb, 0xc7, 0x90, 0xf4, 0x6b, 0x0d, 0xe7, 0x81, 0x7c, 0x83, 0xe1, 0x73, 0xe8, 0x14,
-	0x07, 0x9f, 0x38, 0x18, 0xd6, 0x4f, 0xd9, 0xa0, 0x7f, 0x0b, 0xd7, 0x5a, 0x9e, 0x00, 0xd4, 0x57,
-	0x8f, 0x3c, 0x71, 0x82, 0xba, 0x67, 0x7b, 0x10, 0xdc, 0x2e, 0x28, 0x83, 0x17, 0x47, 0xc4, 0x0d,
-	0x5e, 0xdf, 0x2f, 0x37, 0xb8, 0x73, 0x6d, 0xf2, 0xe0, 0xf5, 0xe6, 0xb8, 0xc1, 0x6f, 0xac, 0xb8,
-	0x1b, 0xfc, 0xe6, 0xa2, 0x85, 0xf7, 0xc8, 0x05, 0x3c, 0x68, 0x8e, 0x16, 0xf9, 0xa0, 0xa1, 0x7f,
-	0x73, 0xfa, 0x07, 0x1f, 0xde, 0x25, 0x36, 0x4e, 0x67, 0x6d, 0xfb, 0xae, 0x7d, 0xfa, 0x6f, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0xb0, 0x44, 0x04, 0xda, 0x31, 0x0a, 0x00, 0x00,
+	// 1209 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x17, 0x4f, 0x6f, 0x1b, 0xc5,
+	0xb7, 0x1b, 0xd7, 0x6b, 0xfb, 0x39, 0x6d, 0xd3, 0x69, 0x9c, 0x6c, 0x37, 0xf9, 0xf5, 0xe7, 0x2e,
+	0x1c, 0x2c, 0x52, 0xd9, 0x8a, 0x11, 0xd0, 0x1b, 0x4a, 0xa2, 0x4a, 0xb1, 0x94, 0x44, 0x65, 0x63,
+	0x24, 0x0e, 0x48, 0xd1, 0x7a, 0xf7, 0x61, 0xaf, 0x62, 0xef, 0x3a, 0x33, 0xe3, 0x68, 0x7b, 0x47,
+	0x42, 0x82, 0x0b, 0x27, 0xc4, 0x47, 0x40, 0x5c, 0x38, 0x71, 0x00, 0xae, 0x88, 0x4f, 0x82, 0xc4,
+	0xd7, 0x40, 0x3b, 0x33, 0xfb, 0xc7, 0x5b, 0xa7, 0xc5, 0x95, 0xa9, 0xc4, 0xc9, 0x7e, 0xff, 0xff,
+	0xbf, 0x79, 0x0b, 0xeb, 0x91, 0x3b, 0x72, 0xfc, 0xa0, 0x3d, 0xa5, 0x21, 0x0f, 0x49, 0x55, 0x42,
+	0xd3, 0x81, 0xb9, 0x1f, 0xcd, 0xa6, 0x48, 0xdd, 0x90, 0x62, 0x67, 0xe0, 0xb2, 0xce, 0x18, 0xbd,
+	0x21, 0xd2, 0x4e, 0x94, 0xfe, 0x7a, 0xc3, 0xe9, 0x20, 0x01, 0xa5, 0xb0, 0xf9, 0xff, 0x4c, 0x44,
+	0x20, 0x58, 0xc7, 0x0d, 0x03, 0x4e, 0x1d, 0x97, 0x2b, 0x86, 0x76, 0xc6, 0x70, 0x89, 0x34, 0xc0,
+	0x71, 0x07, 0x83, 0xa1, 0x1f, 0x20, 0xeb, 0x08, 0x42, 0xc8, 0x3a, 0x51, 0xac, 0x74, 0x3a, 0x90,
+	0xfc, 0xd6, 0xc7, 0x50, 0xb3, 0xf1, 0xea, 0x18, 0x1d, 0x0f, 0x29, 0x69, 0x80, 0x3e, 0x0e, 0x87,
+	0x17, 0xbe, 0x67, 0x68, 0x4d, 0xad, 0x55, 0xb3, 0xcb, 0xe3, 0x70, 0xd8, 0xf3, 0xc8, 0x0e, 0xd4,
+	0x18, 0x8e, 0xbf, 0xb8, 0x08, 0x9c, 0x09, 0x1a, 0x6b, 0x82, 0x52, 0x8d, 0x11, 0x67, 0xce, 0x04,
+	0x2d, 0x0a, 0x60, 0x23, 0x9b, 0xbe, 0x5a, 0xc3, 0x43, 0xa8, 0x22, 0xa5, 0x17, 0x6e, 0xe8, 0x49,
+	0x05, 0x25, 0xbb, 0x82, 0x94, 0x1e, 0x85, 0x1e, 0x92, 0x6d, 0x88, 0xff, 0x5e, 0x4c, 0xd8, 0xd0,
+	0x28, 0x09, 0x11, 0x1d, 0x29, 0x3d, 0x65, 0xc3, 0x58, 0x26, 0x0e, 0x0c, 0x63, 0x65, 0xb7, 0x05,
+	0xa5, 0x22, 0xe0, 0x9e, 0x67, 0x7d, 0x08, 0x95, 0x43, 0x87, 0xa1, 0x8d, 0x57, 0x64, 0x0f, 0xf4,
+	0x91, 0x30, 0x2d, 0x0c, 0xd6, 0xbb, 0x0f, 0xda, 0x49, 0x7a, 0xdb, 0x69, 0x5c, 0xb6, 0x62, 0xb1,
+	0x9e, 0x42, 0x55, 0xca, 0xb1, 0x29, 0x79, 0x52, 0x10, 0xdc, 0xcc, 0x0b, 0xb2, 0x69, 0x41, 0xf2,
+	0x1b, 0x0d, 0xea, 0xe7, 0xb3, 0xc1, 0xc4, 0xe7, 0xfd, 0x68, 0x59, 0xb3, 0x64, 0x0b, 0xf4, 0x81,
+	0x9b, 0x4b, 0x9e, 0x82, 0x08, 0x81, 0xdb, 0x3c, 0xf2, 0x3d, 0x11, 0xf7, 0xba, 0x2d, 0xfe, 0x93,
+	0x77, 0x60, 0x8d, 0x47, 0xc6, 0xed, 0x44, 0xa9, 0xe8, 0x81, 0x76, 0x9f, 0x3a, 0x01, 0x73, 0x5c,
+	0xee, 0x87, 0x81, 0xbd, 0xc6, 0x23, 0xeb, 0x77, 0x0d, 0xe0, 0x39, 0xc5, 0x67, 0x11, 0xba, 0x2b,
+	0x73, 0x66, 0x1f, 0xaa, 0x14, 0xaf, 0x66, 0xc8, 0x38, 0x33, 0x4a, 0xcd, 0x52, 0xab, 0xde, 0x6d,
+	0xc8, 0x16, 0x61, 0xed, 0x5e, 0x70, 0x1d, 0x5e, 0xa2, 0x2d, 0xa9, 0x76, 0xca, 0x46, 0x76, 0xa1,
+	0xe6, 0x07, 0x3e, 0xf7, 0x1d, 0x1e, 0x52, 0x55, 0xa2, 0x0c, 0x41, 0x9a, 0x50, 0x77, 0x66, 0x7c,
+	0x14, 0x8b, 0xf9, 0x14, 0x8d, 0x72, 0xb3, 0xd4, 0xaa, 0xd9, 0x79, 0x94, 0xf5, 0x95, 0x06, 0xf5,
+	0x34, 0x8c, 0x65, 0x4b, 0x72, 0x63, 0x20, 0xdd, 0x38, 0x10, 0x36, 0x0d, 0x03, 0x86, 0x22, 0xb3,
+	0xf5, 0xee, 0x56, 0x31, 0x10, 0x49, 0xb5, 0x53, 0x3e, 0xeb, 0x07, 0x0d, 0xee, 0x9c, 0xe3, 0x18,
+	0x5d, 0xfe, 0x29, 0x8f, 0xc2, 0x95, 0xe5, 0xd4, 0x80, 0x8a, 0xe3, 0x79, 0x14, 0x19, 0x53, 0xbd,
+	0x9d, 0x80, 0x71, 0xea, 0x78, 0xc8, 0x9d, 0xf1, 0x19, 0xa2, 0x67, 0x94, 0x65, 0xea, 0x52, 0x04,
+	0x31, 0xa1, 0x1a, 0x20, 0x7a, 0x27, 0xa1, 0x7b, 0x69, 0xe8, 0x4d, 0xad, 0x55, 0xb5, 0x53, 0xd8,
+	0xfa, 0x5a, 0x83, 0xbb, 0x79, 0x57, 0x97, 0xce, 0x5b, 0x0b, 0xaa, 0x33, 0x1e, 0x85, 0x27, 0x3e,
+	0xe3, 0xc6, 0x9a, 0x28, 0xf4, 0x7a, 0xd2, 0x67, 0x42, 0x63, 0x4a, 0x8d, 0x2b, 0x28, 0x7c, 0x3a,
+	0x98, 0x84, 0xb3, 0x80, 0xab, 0x10, 0xf2, 0x28, 0x0b, 0x01, 0x3e, 0x99, 0x21, 0x7d, 0xf1, 0xef,
+	0x0e, 0x85, 0xf5, 0x93, 0x06, 0xf5, 0xd4, 0xce, 0xd2, 0x01, 0xef, 0x83, 0xce, 0xb8, 0xc3, 0x67,
+	0x4c, 0x58, 0xba, 0xdb, 0x7d, 0xb8, 0x60, 0xac, 0xce, 0x05, 0x83, 0xad, 0x18, 0xe3, 0x02, 0x78,
+	0x3e, 0xe3, 0x4e, 0xe0, 0xca, 0x1e, 0x2a, 0xd9, 0x29, 0xfc, 0xcf, 0x26, 0xf4, 0x5b, 0x0d, 0xee,
+	0x08, 0x8f, 0x0f, 0xc7, 0xa1, 0x7b, 0xb9, 0xca, 0x86, 0x1a, 0xc4, 0x0a, 0x7b, 0x49, 0x7e, 0x12,
+	0x30, 0xae, 0x55, 0xdc, 0x22, 0x47, 0x61, 0xc0, 0x31, 0xe0, 0xc2, 0xbd, 0xaa, 0x9d, 0x47, 0x59,
+	0xdf, 0x6b, 0x70, 0x37, 0xef, 0xd2, 0xd2, 0x79, 0xdc, 0x2b, 0xe4, 0x31, 0x0d, 0x5e, 0x28, 0x2c,
+	0x64, 0x70, 0x0f, 0xca, 0xc2, 0x35, 0x35, 0x82, 0x8d, 0x84, 0xb7, 0x17, 0x70, 0xa4, 0x81, 0x33,
+	0x96, 0x4e, 0x48, 0x1e, 0xeb, 0x4b, 0x0d, 0x1e, 0x08, 0xd7, 0x8e, 0x62, 0xeb, 0x4a, 0xd3, 0xaa,
+	0x72, 0xd6, 0x82, 0x7b, 0x71, 0x1a, 0x0e, 0xa9, 0x13, 0xb8, 0xa3, 0xc3, 0xd4, 0xa7, 0xaa, 0x5d,
+	0x44, 0x5b, 0x7f, 0x6a, 0xb0, 0xf9, 0xb2, 0x1b, 0x2b, 0x5c, 0x4c, 0x20, 0xdf, 0xf2, 0x53, 0xe4,
+	0x8e, 0xca, 0x0b, 0x49, 0xf2, 0x72, 0x92, 0x52, 0xec, 0x1c, 0x17, 0x79, 0x22, 0x87, 0x55, 0x48,
+	0xc8, 0x96, 0xdb, 0xc8, 0x0f, 0xab, 0xe0, 0x4f, 0x39, 0xc8, 0xbb, 0x70, 0x67, 0x90, 0xc5, 0xd3,
+	0xf3, 0xd4, 0xd2, 0x9d, 0x47, 0x5a, 0xbf, 0x69, 0x40, 0x44, 0x98, 0x07, 0x72, 0x19, 0xf5, 0x23,
+	0xf6, 0x16, 0x36, 0xde, 0x16, 0xe8, 0xee, 0x8c, 0xb2, 0xf4, 0xa5, 0x50, 0x10, 0xd9, 0x84, 0xf2,
+	0xd8, 0x9f, 0xf8, 0x5c, 0x6c, 0xc1, 0xb2, 0x2d, 0x81, 0x58, 0x0f, 0xc5, 0x6b, 0xa4, 0x0c, 0xd5,
+	0x02, 0x4c, 0x40, 0xeb, 0x8f, 0xb4, 0x57, 0xd4, 0xe1, 0xb3, 0x4a, 0xf7, 0x2d, 0x58, 0x4f, 0xee,
+	0xa9, 0xf8, 0xb8, 0x51, 0x31, 0xcc, 0xe1, 0x56, 0x16, 0xc8, 0x8f, 0x1a, 0xdc, 0x17, 0x81, 0xf4,
+	0x02, 0x0f, 0xa3, 0x7e, 0xf4, 0x26, 0xad, 0xf6, 0x18, 0x4a, 0x3c, 0x62, 0x6a, 0x8d, 0xdf, 0x4b,
+	0x9e, 0xb9, 0x7e, 0x24, 0x54, 0xda, 0x31, 0x8d, 0x3c, 0x02, 0x08, 0x30, 0xe2, 0x47, 0xd2, 0x65,
+	0x19, 0x50, 0x0e, 0x13, 0xf7, 0x8c, 0x1f, 0x73, 0xa3, 0x77, 0x8c, 0xfe, 0x70, 0x24, 0x57, 0x47,
+	0xc9, 0x9e, 0x47, 0x5a, 0x7f, 0x69, 0xb0, 0x35, 0x97, 0xf5, 0x67, 0xd7, 0x18, 0xf0, 0xb7, 0x9b,
+	0xf8, 0x5d, 0xa8, 0x61, 0x6c, 0x55, 0x30, 0xa8, 0x73, 0x23, 0x45, 0xe4, 0xca, 0x52, 0x5e, 0x5c,
+	0x16, 0xfd, 0x86, 0xb2, 0x54, 0xe6, 0xcb, 0xf2, 0x8b, 0x06, 0xdb, 0x0b, 0x23, 0x5d, 0xba, 0x38,
+	0xef, 0x81, 0x2e, 0xdc, 0x4b, 0xea, 0x43, 0x92, 0xfa, 0x08, 0x8d, 0xb2, 0x44, 0x8a, 0x63, 0x45,
+	0x55, 0xfa, 0x35, 0x69, 0xa9, 0x7e, 0x74, 0xec, 0x33, 0x1e, 0xd2, 0x17, 0xff, 0xa1, 0xc1, 0xfe,
+	0x39, 0x59, 0x4b, 0x39, 0xe7, 0xdf, 0xe0, 0x8d, 0x2a, 0xfb, 0x1c, 0x27, 0x49, 0xca, 0x1b, 0xd9,
+	0x48, 0x28, 0x9d, 0x3d, 0x8e, 0x13, 0x5b, 0xf2, 0xac, 0x26, 0xe9, 0xdd, 0xef, 0x74, 0xd0, 0x3f,
+	0x13, 0x2e, 0x91, 0x0f, 0x00, 0x8e, 0x46, 0xe8, 0x5e, 0x1e, 0x8c, 0xfd, 0x6b, 0x24, 0xf7, 0x33,
+	0x4f, 0xd5, 0xd7, 0x8a, 0x49, 0x8a, 0x28, 0x36, 0xb5, 0x6e, 0x91, 0x8f, 0xa0, 0x9a, 0x7c, 0x5b,
+	0x90, 0x46, 0xc6, 0x91, 0xfb, 0xde, 0xb8, 0x41, 0xf0, 0x29, 0x54, 0xd4, 0xfd, 0x4c, 0x72, 0x69,
+	0xc9, 0xbe, 0x0c, 0xcc, 0xc6, 0x02, 0xac, 0x90, 0x3c, 0x00, 0xc8, 0x8e, 0x48, 0xb2, 0x9d, 0x33,
+	0x9a, 0xbf, 0x82, 0x4d, 0x63, 0x31, 0x21, 0x31, 0xae, 0xca, 0x95, 0x37, 0x9e, 0x9d, 0x83, 0x66,
+	0x63, 0x01, 0x36, 0x31, 0x9e, 0x1d, 0x22, 0x79, 0xe3, 0x73, 0x17, 0x93, 0x69, 0x2c, 0x26, 0x08,
+	0x15, 0xe7, 0xb0, 0x51, 0x7c, 0xa9, 0xc9, 0xff, 0x0a, 0xfc, 0xf3, 0xc7, 0x84, 0xf9, 0xe8, 0x55,
+	0x64, 0xa1, 0xf4, 0x0c, 0xee, 0x15, 0xde, 0x45, 0xb2, 0x5b, 0x10, 0x9a, 0x7b, 0x32, 0xcd, 0x9d,
+	0x02, 0x35, 0xbf, 0xc9, 0xad, 0x5b, 0xe4, 0x39, 0x6c, 0xcc, 0x6d, 0x92, 0x7e, 0xb4, 0xc0, 0xc9,
+	0xb9, 0x57, 0xec, 0x75, 0x1a, 0x3f, 0x2f, 0xbc, 0x7d, 0x72, 0x37, 0x91, 0xe6, 0x0d, 0x4a, 0xd3,
+	0x25, 0x6d, 0x3e, 0x7e, 0x0d, 0x87, 0xd0, 0x7e, 0xaa, 0x0e, 0xc4, 0x74, 0x58, 0xc8, 0xce, 0x4b,
+	0x25, 0xcc, 0xf6, 0x8a, 0xb9, 0x7b, 0x33, 0x31, 0x56, 0x37, 0xd0, 0xc5, 0xec, 0xbd, 0xff, 0xf7,
+	0x00, 0x51, 0x51, 0x2d, 0xe7, 0xff, 0x10, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1004,6 +1549,14 @@ type XchainClient interface {
 	QueryBlock(ctx context.Context, in *QueryBlockReq, opts ...grpc.CallOption) (*QueryBlockResp, error)
 	// 查询区块链状态
 	QueryChainStatus(ctx context.Context, in *QueryChainStatusReq, opts ...grpc.CallOption) (*QueryChainStatusResp, error)
+	// 分页查询地址相关交易，需要开启索引服务
+	QueryAddressTxs(ctx context.Context, in *QueryAddressTxsReq, opts ...grpc.CallOption) (*QueryIndexTxsResp, error)
+	// 分页查询合约相关交易，需要开启索引服务
+	QueryContractTxs(ctx context.Context, in *QueryContractTxsReq, opts ...grpc.CallOption) (*QueryIndexTxsResp, error)
+	// 分页查询合约事件，需要开启索引服务
+	QueryContractEvents(ctx context.Context, in *QueryContractEventsReq, opts ...grpc.CallOption) (*QueryContractEventsResp, error)
+	// 分页查询账户转账历史，需要开启索引服务
+	QueryTxHistory(ctx context.Context, in *QueryTxHistoryReq, opts ...grpc.CallOption) (*QueryTxHistoryResp, error)
 }
 
 type xchainClient struct {
@@ -1077,6 +1630,42 @@ func (c *xchainClient) QueryChainStatus(ctx context.Context, in *QueryChainStatu
 	return out, nil
 }
 
+func (c *xchainClient) QueryAddressTxs(ctx context.Context, in *QueryAddressTxsReq, opts ...grpc.CallOption) (*QueryIndexTxsResp, error) {
+	out := new(QueryIndexTxsResp)
+	err := c.cc.Invoke(ctx, "/xchainpb.Xchain/QueryAddressTxs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xchainClient) QueryContractTxs(ctx context.Context, in *QueryContractTxsReq, opts ...grpc.CallOption) (*QueryIndexTxsResp, error) {
+	out := new(QueryIndexTxsResp)
+	err := c.cc.Invoke(ctx, "/xchainpb.Xchain/QueryContractTxs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xchainClient) QueryContractEvents(ctx context.Context, in *QueryContractEventsReq, opts ...grpc.CallOption) (*QueryContractEventsResp, error) {
+	out := new(QueryContractEventsResp)
+	err := c.cc.Invoke(ctx, "/xchainpb.Xchain/QueryContractEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xchainClient) QueryTxHistory(ctx context.Context, in *QueryTxHistoryReq, opts ...grpc.CallOption) (*QueryTxHistoryResp, error) {
+	out := new(QueryTxHistoryResp)
+	err := c.cc.Invoke(ctx, "/xchainpb.Xchain/QueryTxHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // XchainServer is the server API for Xchain service.
 type XchainServer interface {
 	// 示例接口
@@ -1093,6 +1682,14 @@ type XchainServer interface {
 	QueryBlock(context.Context, *QueryBlockReq) (*QueryBlockResp, error)
 	// 查询区块链状态
 	QueryChainStatus(context.Context, *QueryChainStatusReq) (*QueryChainStatusResp, error)
+	// 分页查询地址相关交易，需要开启索引服务
+	QueryAddressTxs(context.Context, *QueryAddressTxsReq) (*QueryIndexTxsResp, error)
+	// 分页查询合约相关交易，需要开启索引服务
+	QueryContractTxs(context.Context, *QueryContractTxsReq) (*QueryIndexTxsResp, error)
+	// 分页查询合约事件，需要开启索引服务
+	QueryContractEvents(context.Context, *QueryContractEventsReq) (*QueryContractEventsResp, error)
+	// 分页查询账户转账历史，需要开启索引服务
+	QueryTxHistory(context.Context, *QueryTxHistoryReq) (*QueryTxHistoryResp, error)
 }
 
 // UnimplementedXchainServer can be embedded to have forward compatible implementations.
@@ -1120,6 +1717,18 @@ func (*UnimplementedXchainServer) QueryBlock(ctx context.Context, req *QueryBloc
 func (*UnimplementedXchainServer) QueryChainStatus(ctx context.Context, req *QueryChainStatusReq) (*QueryChainStatusResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryChainStatus not implemented")
 }
+func (*UnimplementedXchainServer) QueryAddressTxs(ctx context.Context, req *QueryAddressTxsReq) (*QueryIndexTxsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAddressTxs not implemented")
+}
+func (*UnimplementedXchainServer) QueryContractTxs(ctx context.Context, req *QueryContractTxsReq) (*QueryIndexTxsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryContractTxs not implemented")
+}
+func (*UnimplementedXchainServer) QueryContractEvents(ctx context.Context, req *QueryContractEventsReq) (*QueryContractEventsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryContractEvents not implemented")
+}
+func (*UnimplementedXchainServer) QueryTxHistory(ctx context.Context, req *QueryTxHistoryReq) (*QueryTxHistoryResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryTxHistory not implemented")
+}
 
 func RegisterXchainServer(s *grpc.Server, srv XchainServer) {
 	s.RegisterService(&_Xchain_serviceDesc, srv)
@@ -1251,6 +1860,78 @@ func _Xchain_QueryChainStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Xchain_QueryAddressTxs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryAddressTxsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XchainServer).QueryAddressTxs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/xchainpb.Xchain/QueryAddressTxs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XchainServer).QueryAddressTxs(ctx, req.(*QueryAddressTxsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xchain_QueryContractTxs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryContractTxsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XchainServer).QueryContractTxs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/xchainpb.Xchain/QueryContractTxs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XchainServer).QueryContractTxs(ctx, req.(*QueryContractTxsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xchain_QueryContractEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryContractEventsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XchainServer).QueryContractEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/xchainpb.Xchain/QueryContractEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XchainServer).QueryContractEvents(ctx, req.(*QueryContractEventsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xchain_QueryTxHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryTxHistoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XchainServer).QueryTxHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/xchainpb.Xchain/QueryTxHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XchainServer).QueryTxHistory(ctx, req.(*QueryTxHistoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Xchain_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "xchainpb.Xchain",
 	HandlerType: (*XchainServer)(nil),
@@ -1283,6 +1964,22 @@ var _Xchain_serviceDesc = grpc.ServiceDesc{
 			MethodName: "QueryChainStatus",
 			Handler:    _Xchain_QueryChainStatus_Handler,
 		},
+		{
+			MethodName: "QueryAddressTxs",
+			Handler:    _Xchain_QueryAddressTxs_Handler,
+		},
+		{
+			MethodName: "QueryContractTxs",
+			Handler:    _Xchain_QueryContractTxs_Handler,
+		},
+		{
+			MethodName: "QueryContractEvents",
+			Handler:    _Xchain_QueryContractEvents_Handler,
+		},
+		{
+			MethodName: "QueryTxHistory",
+			Handler:    _Xchain_QueryTxHistory_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "xchain.proto",
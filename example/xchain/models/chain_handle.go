@@ -66,6 +66,30 @@ func (t *ChainHandle) QueryChainStatus(needBranch bool) (*xpb.ChainStatus, error
 	return reader.NewChainReader(t.chain.Context(), t.genXctx()).GetChainStatus()
 }
 
+func (t *ChainHandle) QueryAddressTxs(address, cursor string,
+	limit int, reverse bool) (*xpb.TxIndexPage, error) {
+	return reader.NewIndexReader(t.chain.Context(), t.genXctx()).QueryAddressTxs(address, cursor,
+		limit, reverse)
+}
+
+func (t *ChainHandle) QueryContractTxs(contract, cursor string,
+	limit int, reverse bool) (*xpb.TxIndexPage, error) {
+	return reader.NewIndexReader(t.chain.Context(), t.genXctx()).QueryContractTxs(contract, cursor,
+		limit, reverse)
+}
+
+func (t *ChainHandle) QueryContractEvents(contract, event, cursor string,
+	limit int, reverse bool) (*xpb.EventIndexPage, error) {
+	return reader.NewIndexReader(t.chain.Context(), t.genXctx()).QueryContractEvents(contract, event,
+		cursor, limit, reverse)
+}
+
+func (t *ChainHandle) QueryTxHistory(address, cursor string,
+	limit int, reverse bool) (*xpb.TxHistoryPage, error) {
+	return reader.NewUtxoReader(t.chain.Context(), t.genXctx()).QueryTxHistory(address, cursor,
+		limit, reverse)
+}
+
 func (t *ChainHandle) genXctx() xctx.XContext {
 	return &xctx.BaseCtx{
 		XLog:  t.reqCtx.GetLog(),
@@ -212,3 +212,130 @@ func (t *RpcServ) QueryChainStatus(gctx context.Context,
 
 	return resp, err
 }
+
+// 分页查询地址相关交易
+func (t *RpcServ) QueryAddressTxs(gctx context.Context, req *pb.QueryAddressTxsReq) (*pb.QueryIndexTxsResp, error) {
+	// 默认响应
+	resp := &pb.QueryIndexTxsResp{}
+	// 获取请求上下文，对内传递rctx
+	rctx := sctx.ValueReqCtx(gctx)
+
+	// 校验参数
+	if req == nil || req.GetBcname() == "" || req.GetAddress() == "" {
+		return resp, ecom.ErrParameter
+	}
+
+	// 查询索引
+	handle, err := models.NewChainHandle(req.GetBcname(), rctx)
+	if err != nil {
+		rctx.GetLog().Warn("new chain handle failed", "err", err.Error())
+		return resp, err
+	}
+	res, err := handle.QueryAddressTxs(req.GetAddress(), req.GetCursor(), int(req.GetLimit()), req.GetReverse())
+	rctx.GetLog().SetInfoField("bc_name", req.GetBcname())
+	rctx.GetLog().SetInfoField("address", req.GetAddress())
+	// 设置响应
+	if err == nil {
+		resp.Txs = res.GetTxs()
+		resp.NextCursor = res.GetNextCursor()
+		resp.IndexedHeight = res.GetIndexedHeight()
+	}
+
+	return resp, err
+}
+
+// 分页查询合约相关交易
+func (t *RpcServ) QueryContractTxs(gctx context.Context, req *pb.QueryContractTxsReq) (*pb.QueryIndexTxsResp, error) {
+	// 默认响应
+	resp := &pb.QueryIndexTxsResp{}
+	// 获取请求上下文，对内传递rctx
+	rctx := sctx.ValueReqCtx(gctx)
+
+	// 校验参数
+	if req == nil || req.GetBcname() == "" || req.GetContractName() == "" {
+		return resp, ecom.ErrParameter
+	}
+
+	// 查询索引
+	handle, err := models.NewChainHandle(req.GetBcname(), rctx)
+	if err != nil {
+		rctx.GetLog().Warn("new chain handle failed", "err", err.Error())
+		return resp, err
+	}
+	res, err := handle.QueryContractTxs(req.GetContractName(), req.GetCursor(), int(req.GetLimit()), req.GetReverse())
+	rctx.GetLog().SetInfoField("bc_name", req.GetBcname())
+	rctx.GetLog().SetInfoField("contract", req.GetContractName())
+	// 设置响应
+	if err == nil {
+		resp.Txs = res.GetTxs()
+		resp.NextCursor = res.GetNextCursor()
+		resp.IndexedHeight = res.GetIndexedHeight()
+	}
+
+	return resp, err
+}
+
+// 分页查询合约事件
+func (t *RpcServ) QueryContractEvents(gctx context.Context,
+	req *pb.QueryContractEventsReq) (*pb.QueryContractEventsResp, error) {
+	// 默认响应
+	resp := &pb.QueryContractEventsResp{}
+	// 获取请求上下文，对内传递rctx
+	rctx := sctx.ValueReqCtx(gctx)
+
+	// 校验参数
+	if req == nil || req.GetBcname() == "" || req.GetContractName() == "" || req.GetEventName() == "" {
+		return resp, ecom.ErrParameter
+	}
+
+	// 查询索引
+	handle, err := models.NewChainHandle(req.GetBcname(), rctx)
+	if err != nil {
+		rctx.GetLog().Warn("new chain handle failed", "err", err.Error())
+		return resp, err
+	}
+	res, err := handle.QueryContractEvents(req.GetContractName(), req.GetEventName(), req.GetCursor(),
+		int(req.GetLimit()), req.GetReverse())
+	rctx.GetLog().SetInfoField("bc_name", req.GetBcname())
+	rctx.GetLog().SetInfoField("contract", req.GetContractName())
+	rctx.GetLog().SetInfoField("event", req.GetEventName())
+	// 设置响应
+	if err == nil {
+		resp.Events = res.GetEvents()
+		resp.NextCursor = res.GetNextCursor()
+		resp.IndexedHeight = res.GetIndexedHeight()
+	}
+
+	return resp, err
+}
+
+// 分页查询账户交易历史
+func (t *RpcServ) QueryTxHistory(gctx context.Context, req *pb.QueryTxHistoryReq) (*pb.QueryTxHistoryResp, error) {
+	// 默认响应
+	resp := &pb.QueryTxHistoryResp{}
+	// 获取请求上下文，对内传递rctx
+	rctx := sctx.ValueReqCtx(gctx)
+
+	// 校验参数
+	if req == nil || req.GetBcname() == "" || req.GetAddress() == "" {
+		return resp, ecom.ErrParameter
+	}
+
+	// 查询交易历史
+	handle, err := models.NewChainHandle(req.GetBcname(), rctx)
+	if err != nil {
+		rctx.GetLog().Warn("new chain handle failed", "err", err.Error())
+		return resp, err
+	}
+	res, err := handle.QueryTxHistory(req.GetAddress(), req.GetCursor(), int(req.GetLimit()), req.GetReverse())
+	rctx.GetLog().SetInfoField("bc_name", req.GetBcname())
+	rctx.GetLog().SetInfoField("address", req.GetAddress())
+	// 设置响应
+	if err == nil {
+		resp.Items = res.GetItems()
+		resp.NextCursor = res.GetNextCursor()
+		resp.IndexedHeight = res.GetIndexedHeight()
+	}
+
+	return resp, err
+}
@@ -0,0 +1,161 @@
+// 基于VRF的随机信标。每个区块携带出块人以父区块随机数和区块高度为输入计算的VRF证明，
+// VRF输出作为该区块的随机数，由出块人私钥唯一确定，出块人只能选择出块或不出块，无法挑选结果。
+// 出块时间片的出块人由时间片开始前最后一个区块的随机数从验证人中抽取，
+// 因此下一个出块人直到上一个时间片结束时才能确定，无法提前预测。
+package beacon
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/crypto/vrf"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+var (
+	ErrMissingProof = errors.New("vrf proof is missing in consensus storage")
+	ErrInvalidProof = errors.New("vrf proof is invalid")
+)
+
+var (
+	// MaxWalkBlocks 确定时间片随机数时最多回溯的区块数
+	MaxWalkBlocks = 1000
+	// maxRandomnessCache 缓存的区块随机数个数
+	maxRandomnessCache = 1000
+)
+
+// Beacon 随机信标
+type Beacon struct {
+	// startHeight及之前的区块由其他共识产生，不要求携带VRF证明
+	startHeight int64
+	ledger      cctx.LedgerRely
+	crypto      base.CryptoClient
+	log         logs.Logger
+
+	mutex sync.Mutex
+	// key为blockid
+	randomness map[string][]byte
+}
+
+// NewBeacon 新建随机信标实例
+func NewBeacon(startHeight int64, l cctx.LedgerRely, crypto base.CryptoClient, log logs.Logger) *Beacon {
+	return &Beacon{
+		startHeight: startHeight,
+		ledger:      l,
+		crypto:      crypto,
+		log:         log,
+		randomness:  make(map[string][]byte),
+	}
+}
+
+// Randomness 返回区块的随机数，结果按blockid缓存
+func (b *Beacon) Randomness(block ledger.BlockHandle) ([]byte, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if r, ok := b.randomness[string(block.GetBlockid())]; ok {
+		return r, nil
+	}
+	r, _, err := common.BlockRandomness(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.randomness) >= maxRandomnessCache {
+		b.randomness = make(map[string][]byte)
+	}
+	b.randomness[string(block.GetBlockid())] = r
+	return r, nil
+}
+
+// Prove 出块人为parentId之后的新区块计算VRF证明
+func (b *Beacon) Prove(sk *ecdsa.PrivateKey, parentId []byte) ([]byte, error) {
+	alpha, err := b.alpha(parentId)
+	if err != nil {
+		return nil, err
+	}
+	_, proof, err := vrf.Prove(sk, alpha)
+	return proof, err
+}
+
+// Verify 校验区块consensus storage中携带的VRF证明
+func (b *Beacon) Verify(block cctx.BlockInterface) error {
+	if block.GetHeight() <= b.startHeight {
+		return nil
+	}
+	storage, _ := block.GetConsensusStorage()
+	if len(storage) == 0 {
+		return ErrMissingProof
+	}
+	s, err := common.ParseOldQCStorage(storage)
+	if err != nil {
+		return err
+	}
+	if len(s.VrfProof) == 0 {
+		return ErrMissingProof
+	}
+	pk, err := b.crypto.GetEcdsaPublicKeyFromJsonStr(block.GetPublicKey())
+	if err != nil {
+		return err
+	}
+	alpha, err := b.alpha(block.GetPreHash())
+	if err != nil {
+		return err
+	}
+	if _, err := vrf.Verify(pk, alpha, s.VrfProof); err != nil {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// alpha 返回parentId之后新区块的VRF输入
+func (b *Beacon) alpha(parentId []byte) ([]byte, error) {
+	parent, err := b.ledger.QueryBlockHeader(parentId)
+	if err != nil {
+		return nil, err
+	}
+	r, err := b.Randomness(parent)
+	if err != nil {
+		return nil, err
+	}
+	return common.VrfAlpha(r, parent.GetHeight()+1), nil
+}
+
+// Proposer 返回parentId之后、开始于slotBegin(unixnano)的出块时间片的出块人
+// 随机数取自parentId向前回溯的第一个早于slotBegin的区块，时间片内的后续区块回溯到同一区块，出块人保持不变
+func (b *Beacon) Proposer(parentId []byte, slotBegin int64, validators []string) string {
+	if len(validators) == 0 {
+		return ""
+	}
+	block, err := b.ledger.QueryBlockHeader(parentId)
+	if err != nil {
+		b.log.Warn("consensus:beacon: query parent block failed", "err", err)
+		return ""
+	}
+	for i := 0; i < MaxWalkBlocks && block.GetHeight() > b.startHeight && block.GetTimestamp() >= slotBegin; i++ {
+		if block, err = b.ledger.QueryBlockHeader(block.GetPreHash()); err != nil {
+			b.log.Warn("consensus:beacon: query block failed", "err", err)
+			return ""
+		}
+	}
+	r, err := b.Randomness(block)
+	if err != nil {
+		b.log.Warn("consensus:beacon: calculate randomness failed", "err", err)
+		return ""
+	}
+	return validators[pick(r, slotBegin, len(validators))]
+}
+
+// pick 以随机数和时间片开始时间抽取验证人下标
+func pick(randomness []byte, slotBegin int64, n int) int {
+	buf := make([]byte, len(randomness)+8)
+	copy(buf, randomness)
+	binary.BigEndian.PutUint64(buf[len(randomness):], uint64(slotBegin))
+	h := sha256.Sum256(buf)
+	return int(binary.BigEndian.Uint64(h[:8]) % uint64(n))
+}
@@ -0,0 +1,148 @@
+package beacon
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/mock"
+	kmock "github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/crypto/client/base"
+	xchain "github.com/xuperchain/xupercore/lib/crypto/client/xchain"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+var (
+	period     = int64(time.Second)
+	validators = []string{"A", "B", "C", "D"}
+)
+
+type testChain struct {
+	t      *testing.T
+	ledger *mock.FakeLedger
+	beacon *Beacon
+	crypto base.CryptoClient
+	keys   map[string]*ecdsa.PrivateKey
+	tip    *mock.FakeBlock
+}
+
+func newTestChain(t *testing.T) *testChain {
+	econf, err := kmock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), filepath.Join(t.TempDir(), "log"))
+	log, _ := logs.NewLogger("", "beacon_test")
+
+	c := &testChain{
+		t:      t,
+		ledger: mock.NewFakeLedger(nil),
+		crypto: xchain.GetInstance(),
+		keys:   make(map[string]*ecdsa.PrivateKey),
+	}
+	for _, v := range validators {
+		sk, err := c.crypto.GenerateKeyBySeed([]byte("beacon test seed of validator " + v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.keys[v] = sk
+	}
+	// 高度0~2为NewFakeLedger生成的区块，没有VRF证明
+	tip, _ := c.ledger.QueryBlockHeaderByHeight(2)
+	c.tip = tip.(*mock.FakeBlock)
+	c.tip.Timestamp = 0
+	c.beacon = NewBeacon(2, c.ledger, c.crypto, log)
+	return c
+}
+
+// newBlock 由proposer在时间片slot生成新区块
+func (c *testChain) newBlock(proposer string, slot int64) *mock.FakeBlock {
+	sk := c.keys[proposer]
+	proof, err := c.beacon.Prove(sk, c.tip.Blockid)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	storage, _ := json.Marshal(common.ConsensusStorage{VrfProof: proof})
+	pk, _ := c.crypto.GetEcdsaPublicKeyJsonFormatStr(sk)
+	return &mock.FakeBlock{
+		Height:           c.tip.Height + 1,
+		Blockid:          []byte(fmt.Sprintf("b%d", c.tip.Height+1)),
+		PreHash:          c.tip.Blockid,
+		Timestamp:        slot*period + period/2,
+		Proposer:         proposer,
+		PublicKey:        pk,
+		ConsensusStorage: storage,
+	}
+}
+
+func (c *testChain) add(b *mock.FakeBlock) {
+	c.ledger.Put(b)
+	c.tip = b
+}
+
+func TestVerify(t *testing.T) {
+	c := newTestChain(t)
+	b := c.newBlock("A", 1)
+	if err := c.beacon.Verify(b); err != nil {
+		t.Fatalf("verify failed, err:%v", err)
+	}
+	// 随机数为VRF输出，不同出块人在同一父区块上得到不同的随机数
+	other := c.newBlock("B", 1)
+	other.Blockid = []byte("fork")
+	r1, _ := c.beacon.Randomness(b)
+	r2, _ := c.beacon.Randomness(other)
+	if string(r1) == string(r2) {
+		t.Fatal("randomness of different proposers should differ")
+	}
+
+	// 冒用他人的证明无法通过校验
+	forged := *other
+	forged.ConsensusStorage = b.ConsensusStorage
+	if err := c.beacon.Verify(&forged); err != ErrInvalidProof {
+		t.Fatalf("forged proof should be rejected, err:%v", err)
+	}
+	// 证明不能用于其他父区块
+	c.add(b)
+	replay := c.newBlock("A", 2)
+	replay.ConsensusStorage = b.ConsensusStorage
+	if err := c.beacon.Verify(replay); err != ErrInvalidProof {
+		t.Fatalf("replayed proof should be rejected, err:%v", err)
+	}
+	missing := c.newBlock("A", 2)
+	missing.ConsensusStorage = nil
+	if err := c.beacon.Verify(missing); err != ErrMissingProof {
+		t.Fatalf("block without proof should be rejected, err:%v", err)
+	}
+	// 起始高度及之前的区块不要求证明
+	genesis, _ := c.ledger.QueryBlockHeaderByHeight(2)
+	if err := c.beacon.Verify(genesis.(*mock.FakeBlock)); err != nil {
+		t.Fatalf("block before start height should pass, err:%v", err)
+	}
+}
+
+func TestProposer(t *testing.T) {
+	c := newTestChain(t)
+	counts := make(map[string]int)
+	for slot := int64(1); slot <= 200; slot++ {
+		begin := slot * period
+		proposer := c.beacon.Proposer(c.tip.Blockid, begin, validators)
+		if proposer == "" {
+			t.Fatalf("proposer of slot %d not found", slot)
+		}
+		counts[proposer]++
+		c.add(c.newBlock(proposer, slot))
+		// 时间片内的后续区块回溯到同一区块，出块人不变
+		if next := c.beacon.Proposer(c.tip.Blockid, begin, validators); next != proposer {
+			t.Fatalf("proposer changed within slot %d: %s -> %s", slot, proposer, next)
+		}
+	}
+	for _, v := range validators {
+		if counts[v] == 0 {
+			t.Fatalf("validator %s never elected, counts:%v", v, counts)
+		}
+	}
+}
@@ -53,12 +53,12 @@ func AddressEqual(a []string, b []string) bool {
 	return true
 }
 
-func CleanProduceMap(isProduce map[int64]bool, period int64) {
+func CleanProduceMap(isProduce map[int64]bool, period int64, now time.Time) {
 	// 删除已经落盘的所有key
 	if len(isProduce) <= MaxMapSize {
 		return
 	}
-	t := time.Now().UnixNano() / int64(time.Millisecond)
+	t := now.UnixNano() / int64(time.Millisecond)
 	key := t / period
 	for k := range isProduce {
 		if k <= key-int64(MaxMapSize) {
@@ -76,6 +76,19 @@ type ConsensusStorage struct {
 	// TargetBits 是一个trick实现
 	// 1. 在bcs层作为一个复用字段，记录ChainedBFT发生回滚时，当前的TipHeight，此处用int32代替int64，理论上可能造成错误
 	TargetBits int32 `json:"targetBits,omitempty"`
+	// VrfProof 开启VRF选举时，出块人以父区块随机数和当前高度为输入计算的VRF证明
+	VrfProof []byte `json:"vrfProof,omitempty"`
+	// CommitHeight raft的leader出块时已提交的最高区块高度，节点重启后据此恢复提交高度
+	CommitHeight int64 `json:"commitHeight,omitempty"`
+	// Liveness 以父区块为结尾的统计窗口内各验证人的出块情况，key为验证人地址，各节点在CheckMinerMatch中校验
+	Liveness map[string]*LivenessCount `json:"liveness,omitempty"`
+}
+
+// LivenessCount 验证人在统计窗口内的出块数、错过的时间片数，以及最近一次出块后连续错过的时间片数
+type LivenessCount struct {
+	Produced     int64 `json:"produced,omitempty"`
+	Missed       int64 `json:"missed,omitempty"`
+	RecentMissed int64 `json:"recentMissed,omitempty"`
 }
 
 // ParseOldQCStorage 将有Justify结构的老共识结构解析出来
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+)
+
+// FinalizedByDepth 返回主干上深度为depth的区块，即之上已经至少有depth个区块的最高区块，高度最低为0
+func FinalizedByDepth(ledger cctx.LedgerRely, depth int64) (int64, []byte) {
+	tip := ledger.QueryTipBlockHeader()
+	if tip == nil {
+		return 0, nil
+	}
+	if depth <= 0 {
+		return tip.GetHeight(), tip.GetBlockid()
+	}
+	height := tip.GetHeight() - depth
+	if height < 0 {
+		height = 0
+	}
+	block, err := ledger.QueryBlockHeaderByHeight(height)
+	if err != nil {
+		return 0, nil
+	}
+	return block.GetHeight(), block.GetBlockid()
+}
+
+// SlideWindow 返回账本配置的不可逆滑动窗口，账本未提供或未开启滑动窗口时返回0
+func SlideWindow(ledger cctx.LedgerRely) int64 {
+	l, ok := ledger.(cctx.IrreversibleLedger)
+	if !ok {
+		return 0
+	}
+	return l.GetIrreversibleSlideWindow()
+}
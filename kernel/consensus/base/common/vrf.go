@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	cryptoClient "github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/crypto/vrf"
+)
+
+// BlockRandomness 返回区块的随机数
+// 区块携带VRF证明时为VRF输出，该输出由出块人私钥和父区块随机数唯一确定，出块人无法操纵；
+// 未携带VRF证明的区块(创世块、未开启VRF的共识等)退化为区块id的哈希，该值可以被出块人影响
+// verifiable表示随机数是否为VRF输出
+// ATTENTION: 不校验VRF证明，区块上链前已在CheckMinerMatch中完成校验
+func BlockRandomness(block ledger.BlockHandle) (randomness []byte, verifiable bool, err error) {
+	storage, _ := block.GetConsensusStorage()
+	if len(storage) > 0 {
+		s, err := ParseOldQCStorage(storage)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(s.VrfProof) > 0 {
+			client, err := cryptoClient.CreateCryptoClientFromJSONPublicKey([]byte(block.GetPublicKey()))
+			if err != nil {
+				return nil, false, err
+			}
+			pk, err := client.GetEcdsaPublicKeyFromJsonStr(block.GetPublicKey())
+			if err != nil {
+				return nil, false, err
+			}
+			randomness, err = vrf.ProofToHash(pk.Curve, s.VrfProof)
+			return randomness, err == nil, err
+		}
+	}
+	h := sha256.Sum256(block.GetBlockid())
+	return h[:], false, nil
+}
+
+// VrfAlpha 区块VRF证明的输入，由父区块随机数和区块高度组成
+func VrfAlpha(parentRandomness []byte, height int64) []byte {
+	alpha := make([]byte, len(parentRandomness)+8)
+	copy(alpha, parentRandomness)
+	binary.BigEndian.PutUint64(alpha[len(parentRandomness):], uint64(height))
+	return alpha
+}
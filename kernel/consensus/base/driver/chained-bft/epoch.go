@@ -0,0 +1,53 @@
+package chained_bft
+
+import (
+	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/lib/cache"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+const (
+	// DefaultEpochCacheSize 缓存的父区块到纪元区块映射的数量
+	DefaultEpochCacheSize = 1000
+)
+
+// EpochTracker 计算验证人集合的纪元边界
+// 验证人集合的变更只有在包含变更的区块被旧集合的QC提交之后才生效，
+// 因此区块使用的验证人集合由其父区块看来已提交的最高区块(纪元区块)的状态决定，同一父区块在任何分支上得到相同的结果
+type EpochTracker struct {
+	ledger      cctx.LedgerRely
+	justify     storage.JustifyFunc
+	startHeight int64
+	// key: parentId, value: 纪元区块的blockId，父区块的祖先不会改变，因此缓存无需失效
+	epochs *cache.LRUCache
+}
+
+func NewEpochTracker(startHeight int64, ledger cctx.LedgerRely, justify storage.JustifyFunc) *EpochTracker {
+	return &EpochTracker{
+		ledger:      ledger,
+		justify:     justify,
+		startHeight: startHeight,
+		epochs:      cache.NewLRUCache(DefaultEpochCacheSize),
+	}
+}
+
+// EpochBlock 返回父区块为parentId的区块所在纪元的纪元区块，即从parentId看来已经提交的最高区块
+func (e *EpochTracker) EpochBlock(parentId []byte) ([]byte, error) {
+	key := utils.F(parentId)
+	if v, ok := e.epochs.Get(key); ok {
+		return v.([]byte), nil
+	}
+	block, err := storage.CommittedBlock(e.ledger, e.justify, e.startHeight, parentId)
+	if err != nil {
+		return nil, err
+	}
+	e.epochs.Add(key, block.GetBlockid())
+	return block.GetBlockid(), nil
+}
+
+// RebuildQCTree 根据账本最近区块中的justify重建QC树，见storage.RebuildQCTree
+func (e *EpochTracker) RebuildQCTree(log logs.Logger) (*storage.QCPendingTree, []storage.QuorumCertInterface) {
+	return storage.RebuildQCTree(e.startHeight, e.ledger, e.justify, log)
+}
@@ -0,0 +1,249 @@
+package chained_bft
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/mock"
+	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
+	kmock "github.com/xuperchain/xupercore/kernel/consensus/mock"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/kernel/network"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	xuperp2p "github.com/xuperchain/xupercore/protos"
+)
+
+// justifyLedger 区块0~10，高于起始高度1的区块justify默认指向父区块，targets中的区块justify指向指定区块
+type justifyLedger struct {
+	*kmock.FakeLedger
+	targets map[int64]int64
+}
+
+func newJustifyLedger() *justifyLedger {
+	l := kmock.NewFakeLedger(nil)
+	for i := 3; i <= 10; i++ {
+		l.Put(kmock.NewBlock(i))
+	}
+	return &justifyLedger{FakeLedger: l, targets: map[int64]int64{}}
+}
+
+func (l *justifyLedger) justify(block ledger.BlockHandle) (storage.QuorumCertInterface, error) {
+	if block.GetHeight() <= 1 {
+		return nil, nil
+	}
+	target := block.GetHeight() - 1
+	if t, ok := l.targets[block.GetHeight()]; ok {
+		target = t
+	}
+	return mock.MockCreateQC([]byte{byte(target)}, target, []byte{byte(target - 1)}, target-1), nil
+}
+
+func TestEpochBlock(t *testing.T) {
+	l := newJustifyLedger()
+	e := NewEpochTracker(1, l, l.justify)
+	cases := []struct {
+		parent int64
+		want   int64
+	}{
+		// 三链尚未形成时为起始高度的前一个区块
+		{parent: 3, want: 0},
+		{parent: 4, want: 1},
+		{parent: 10, want: 7},
+	}
+	for _, c := range cases {
+		id, err := e.EpochBlock([]byte{byte(c.parent)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(id, []byte{byte(c.want)}) {
+			t.Errorf("parent %d expect epoch block %d, got %v", c.parent, c.want, id)
+		}
+	}
+
+	// 区块8回滚重做，justify不指向父区块，三链断开
+	l = newJustifyLedger()
+	l.targets[8] = 6
+	e = NewEpochTracker(1, l, l.justify)
+	if id, _ := e.EpochBlock([]byte{10}); !bytes.Equal(id, []byte{4}) {
+		t.Errorf("broken chain expect epoch block 4, got %v", id)
+	}
+	// 父区块的祖先不会改变，结果被缓存
+	l.targets[8] = 7
+	if id, _ := e.EpochBlock([]byte{10}); !bytes.Equal(id, []byte{4}) {
+		t.Errorf("epoch block should be cached, got %v", id)
+	}
+	if _, err := e.EpochBlock([]byte{99}); err == nil {
+		t.Error("unknown parent should fail")
+	}
+
+	// 回溯超过最大深度仍未形成三链时不再查找至起始高度
+	defer func(depth int64) { storage.CommitSearchDepth = depth }(storage.CommitSearchDepth)
+	storage.CommitSearchDepth = 4
+	l = newJustifyLedger()
+	for h := int64(2); h <= 10; h++ {
+		l.targets[h] = h - 2
+	}
+	e = NewEpochTracker(1, l, l.justify)
+	if _, err := e.EpochBlock([]byte{10}); err != storage.ErrCommittedBlockNotFound {
+		t.Errorf("expect ErrCommittedBlockNotFound, got %v", err)
+	}
+	if id, _ := e.EpochBlock([]byte{3}); !bytes.Equal(id, []byte{0}) {
+		t.Errorf("start height within depth expect epoch block 0, got %v", id)
+	}
+}
+
+func TestRebuildQCTree(t *testing.T) {
+	th, _ := mock.NewTestHelper()
+	defer th.Close()
+	l := newJustifyLedger()
+	tree, justifies := NewEpochTracker(1, l, l.justify).RebuildQCTree(th.Log)
+	if tree == nil {
+		t.Fatal("RebuildQCTree error")
+	}
+	if v := tree.GetRootQC().In.GetProposalView(); v != 7 {
+		t.Errorf("root should be the committed block 7, got %d", v)
+	}
+	if v := tree.GetHighQC().In.GetProposalView(); v != 9 {
+		t.Errorf("highQC should be the tip justify 9, got %d", v)
+	}
+	if tree.GetGenericQC().In.GetProposalView() != 8 || tree.GetLockedQC().In.GetProposalView() != 7 {
+		t.Error("genericQC and lockedQC should follow highQC")
+	}
+	if tree.DFSQueryNode([]byte{10}) == nil {
+		t.Error("tip block should be in the tree")
+	}
+	if len(justifies) != 3 {
+		t.Errorf("expect justifies of block 8~10, got %d", len(justifies))
+	}
+
+	// tip的justify指向更早的区块时highQC随之回退
+	l.targets[10] = 8
+	tree, _ = NewEpochTracker(1, l, l.justify).RebuildQCTree(th.Log)
+	if v := tree.GetRootQC().In.GetProposalView(); v != 6 {
+		t.Errorf("root should be the committed block 6, got %d", v)
+	}
+	if v := tree.GetHighQC().In.GetProposalView(); v != 8 {
+		t.Errorf("highQC should be 8, got %d", v)
+	}
+}
+
+// recordP2P 记录smr发出的消息
+type recordP2P struct {
+	network.Network
+	msgs chan *xuperp2p.XuperMessage
+}
+
+func (p *recordP2P) SendMessage(_ xctx.XContext, msg *xuperp2p.XuperMessage, _ ...p2p.OptionFunc) error {
+	p.msgs <- msg
+	return nil
+}
+
+func TestSyncQC(t *testing.T) {
+	th, _ := mock.NewTestHelper()
+	defer th.Close()
+	pA := &recordP2P{msgs: make(chan *xuperp2p.XuperMessage, 1)}
+	pB := &recordP2P{msgs: make(chan *xuperp2p.XuperMessage, 1)}
+	sA := NewSMR("nodeA", th.Log, pA, t)
+	sB := NewSMR("nodeB", th.Log, pB, t)
+
+	// A和B都已同步到区块1，B作为第2轮的Leader收集到了区块1的QC
+	id := []byte{1}
+	var signs []*chainedBftPb.QuorumCertSign
+	for _, node := range []string{"nodeA", "nodeC"} {
+		a, cc := NewFakeCryptoClient(node, t)
+		sign, err := cCrypto.NewCBFTCrypto(&a, cc).SignVoteMsg(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signs = append(signs, sign)
+	}
+	for _, s := range []*Smr{sA, sB} {
+		node := mock.MockCreateNode(mock.MockCreateQC(id, 1, []byte{0}, 0), nil)
+		if err := s.qcTree.UpdateQcStatus(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sB.LoadVotes(id, signs)
+	sB.qcTree.UpdateHighQC(id)
+
+	sA.SyncQC([]string{NodeA, NodeB, NodeC})
+	req := <-pA.msgs
+	if req.GetHeader().GetType() != xuperp2p.XuperMessage_CHAINED_BFT_GET_QC_MSG {
+		t.Fatalf("unexpected request type %v", req.GetHeader().GetType())
+	}
+	sB.handleReceivedMsg(req)
+	resp := <-pB.msgs
+	if resp.GetHeader().GetType() != xuperp2p.XuperMessage_CHAINED_BFT_QC_MSG {
+		t.Fatalf("unexpected response type %v", resp.GetHeader().GetType())
+	}
+	sA.handleReceivedMsg(resp)
+	if !bytes.Equal(sA.getHighQC().GetProposalId(), id) {
+		t.Errorf("highQC should be synced, got %v", sA.getHighQC().GetProposalId())
+	}
+	if !sA.validNewHighQC(id, 1, []string{NodeA, NodeB, NodeC}) {
+		t.Error("synced qc signs should be loaded")
+	}
+}
+
+func TestSyncQCInvalidSign(t *testing.T) {
+	th, _ := mock.NewTestHelper()
+	defer th.Close()
+	pA := &recordP2P{msgs: make(chan *xuperp2p.XuperMessage, 1)}
+	sA := NewSMR("nodeA", th.Log, pA, t)
+	id := []byte{1}
+	if err := sA.qcTree.UpdateQcStatus(mock.MockCreateNode(mock.MockCreateQC(id, 1, []byte{0}, 0), nil)); err != nil {
+		t.Fatal(err)
+	}
+	// C的签名针对的是其他区块
+	a, cc := NewFakeCryptoClient("nodeC", t)
+	sign, _ := cCrypto.NewCBFTCrypto(&a, cc).SignVoteMsg([]byte{2})
+	sB := NewSMR("nodeB", th.Log, &recordP2P{msgs: make(chan *xuperp2p.XuperMessage, 1)}, t)
+	if err := sB.qcTree.UpdateQcStatus(mock.MockCreateNode(mock.MockCreateQC(id, 1, []byte{0}, 0), nil)); err != nil {
+		t.Fatal(err)
+	}
+	sB.LoadVotes(id, []*chainedBftPb.QuorumCertSign{sign})
+	sB.qcTree.UpdateHighQC(id)
+	sB.handleReceivedMsg(p2p.NewMessage(xuperp2p.XuperMessage_CHAINED_BFT_GET_QC_MSG,
+		&chainedBftPb.QCRequestMsg{Address: NodeA}, p2p.WithBCName("xuper")))
+	resp := <-sB.p2p.(*recordP2P).msgs
+	sA.handleReceivedMsg(resp)
+	if bytes.Equal(sA.getHighQC().GetProposalId(), id) {
+		t.Error("qc with invalid sign should be dropped")
+	}
+}
+
+func TestSyncQCLocalView(t *testing.T) {
+	th, _ := mock.NewTestHelper()
+	defer th.Close()
+	sA := NewSMR("nodeA", th.Log, &recordP2P{msgs: make(chan *xuperp2p.XuperMessage, 1)}, t)
+	id := []byte{1}
+	if err := sA.qcTree.UpdateQcStatus(mock.MockCreateNode(mock.MockCreateQC(id, 1, []byte{0}, 0), nil)); err != nil {
+		t.Fatal(err)
+	}
+	var signs []*chainedBftPb.QuorumCertSign
+	for _, node := range []string{"nodeB", "nodeC"} {
+		a, cc := NewFakeCryptoClient(node, t)
+		sign, err := cCrypto.NewCBFTCrypto(&a, cc).SignVoteMsg(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signs = append(signs, sign)
+	}
+	// 签名不覆盖view，对端填写了虚高的view
+	qc, _ := json.Marshal(storage.NewQuorumCert(&storage.VoteInfo{
+		ProposalId:   id,
+		ProposalView: 100,
+	}, nil, signs))
+	sA.handleReceivedMsg(p2p.NewMessage(xuperp2p.XuperMessage_CHAINED_BFT_QC_MSG,
+		&chainedBftPb.QCResponseMsg{QC: qc}, p2p.WithBCName("xuper")))
+	if !bytes.Equal(sA.getHighQC().GetProposalId(), id) {
+		t.Errorf("highQC should be synced, got %v", sA.getHighQC().GetProposalId())
+	}
+	if v := sA.pacemaker.GetCurrentView(); v >= 100 {
+		t.Errorf("pacemaker should follow the local view, got %d", v)
+	}
+}
@@ -218,34 +218,120 @@ func (m *VoteMsg) GetSignature() []*QuorumCertSign {
 	return nil
 }
 
+// QCRequestMsg 新加入或重启的验证人向其他验证人请求其本地最新的QC
+type QCRequestMsg struct {
+	// 请求方地址，应答发送给该地址
+	Address              string   `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QCRequestMsg) Reset()         { *m = QCRequestMsg{} }
+func (m *QCRequestMsg) String() string { return proto.CompactTextString(m) }
+func (*QCRequestMsg) ProtoMessage()    {}
+func (*QCRequestMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f59372df81539441, []int{3}
+}
+
+func (m *QCRequestMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QCRequestMsg.Unmarshal(m, b)
+}
+func (m *QCRequestMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QCRequestMsg.Marshal(b, m, deterministic)
+}
+func (m *QCRequestMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QCRequestMsg.Merge(m, src)
+}
+func (m *QCRequestMsg) XXX_Size() int {
+	return xxx_messageInfo_QCRequestMsg.Size(m)
+}
+func (m *QCRequestMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_QCRequestMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QCRequestMsg proto.InternalMessageInfo
+
+func (m *QCRequestMsg) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+// QCResponseMsg 应答方本地带完整签名的HighQC
+type QCResponseMsg struct {
+	// json序列化的QuorumCert
+	QC                   []byte   `protobuf:"bytes,1,opt,name=QC,proto3" json:"QC,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QCResponseMsg) Reset()         { *m = QCResponseMsg{} }
+func (m *QCResponseMsg) String() string { return proto.CompactTextString(m) }
+func (*QCResponseMsg) ProtoMessage()    {}
+func (*QCResponseMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f59372df81539441, []int{4}
+}
+
+func (m *QCResponseMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QCResponseMsg.Unmarshal(m, b)
+}
+func (m *QCResponseMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QCResponseMsg.Marshal(b, m, deterministic)
+}
+func (m *QCResponseMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QCResponseMsg.Merge(m, src)
+}
+func (m *QCResponseMsg) XXX_Size() int {
+	return xxx_messageInfo_QCResponseMsg.Size(m)
+}
+func (m *QCResponseMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_QCResponseMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QCResponseMsg proto.InternalMessageInfo
+
+func (m *QCResponseMsg) GetQC() []byte {
+	if m != nil {
+		return m.QC
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*QuorumCertSign)(nil), "chainedBftPb.QuorumCertSign")
 	proto.RegisterType((*ProposalMsg)(nil), "chainedBftPb.ProposalMsg")
 	proto.RegisterType((*VoteMsg)(nil), "chainedBftPb.VoteMsg")
+	proto.RegisterType((*QCRequestMsg)(nil), "chainedBftPb.QCRequestMsg")
+	proto.RegisterType((*QCResponseMsg)(nil), "chainedBftPb.QCResponseMsg")
 }
 
 func init() { proto.RegisterFile("chainedBFTMsg.proto", fileDescriptor_f59372df81539441) }
 
 var fileDescriptor_f59372df81539441 = []byte{
-	// 307 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0x41, 0x4b, 0xc3, 0x30,
-	0x14, 0xc7, 0x89, 0x9d, 0x9b, 0x7b, 0x2b, 0x22, 0xf1, 0x12, 0x64, 0x48, 0xe9, 0x69, 0x78, 0x18,
-	0xa2, 0x37, 0x6f, 0x3a, 0x11, 0xa6, 0x16, 0xba, 0x28, 0x3b, 0x79, 0x69, 0xd7, 0x34, 0x06, 0xd6,
-	0xa6, 0x24, 0x29, 0xb2, 0x0f, 0xe1, 0x47, 0xf4, 0xbb, 0x48, 0xd2, 0x75, 0xdd, 0xf0, 0xe0, 0x2d,
-	0xff, 0x5f, 0xf2, 0xde, 0xff, 0xfd, 0x5f, 0xe0, 0x7c, 0xf5, 0x99, 0x88, 0x92, 0x65, 0x0f, 0x4f,
-	0xef, 0x91, 0xe6, 0xd3, 0x4a, 0x49, 0x23, 0xb1, 0xdf, 0xc2, 0xdc, 0xc4, 0x69, 0xf8, 0x01, 0xa7,
-	0x8b, 0x5a, 0xaa, 0xba, 0x98, 0x31, 0x65, 0xde, 0x04, 0x2f, 0x31, 0x81, 0xc1, 0x7d, 0x96, 0x29,
-	0xa6, 0x35, 0x41, 0x01, 0x9a, 0x0c, 0x69, 0x2b, 0xf1, 0x18, 0x86, 0x71, 0x9d, 0xae, 0xc5, 0xea,
-	0x85, 0x6d, 0xc8, 0x91, 0xbb, 0xeb, 0x00, 0xc6, 0xd0, 0xb3, 0xf5, 0xc4, 0x0b, 0xd0, 0xc4, 0xa7,
-	0xee, 0x1c, 0xfe, 0x20, 0x18, 0xc5, 0x4a, 0x56, 0x52, 0x27, 0xeb, 0x48, 0x73, 0x1c, 0x82, 0x5f,
-	0x6d, 0xe5, 0x52, 0xb0, 0x2f, 0x67, 0xe0, 0xd1, 0x03, 0x86, 0x2f, 0x01, 0x5a, 0x3d, 0xcf, 0x9c,
-	0x8d, 0x4f, 0xf7, 0x88, 0x9d, 0xc2, 0x88, 0x82, 0x69, 0x93, 0x14, 0x95, 0x33, 0xf3, 0x68, 0x07,
-	0xec, 0xed, 0x73, 0xad, 0x8d, 0xc8, 0x37, 0x8b, 0x19, 0xe9, 0xb9, 0xe2, 0x0e, 0xe0, 0xeb, 0xed,
-	0x8c, 0xc7, 0x01, 0x9a, 0x8c, 0x6e, 0xc6, 0xd3, 0xfd, 0x55, 0x4c, 0x0f, 0xf7, 0xd0, 0x24, 0xb0,
-	0xfd, 0x22, 0xcd, 0x1f, 0x05, 0x67, 0xda, 0x90, 0x7e, 0xd3, 0x6f, 0x07, 0xc2, 0x6f, 0x04, 0x83,
-	0xa5, 0x34, 0xcc, 0x66, 0xbb, 0x80, 0x13, 0x7b, 0x9c, 0x97, 0xb9, 0x74, 0xb9, 0x7c, 0xba, 0xd3,
-	0xf8, 0x0a, 0xce, 0x5e, 0x59, 0xc6, 0x99, 0x9a, 0xc9, 0xa2, 0x10, 0xc6, 0xbd, 0x69, 0x92, 0xfd,
-	0xe1, 0xf8, 0x0e, 0x86, 0xd6, 0x39, 0x31, 0xb5, 0x62, 0xc4, 0x0b, 0xbc, 0x7f, 0x07, 0xed, 0x9e,
-	0xa7, 0x7d, 0xf7, 0xc5, 0xb7, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd7, 0xbd, 0x59, 0xaa, 0xf9,
-	0x01, 0x00, 0x00,
+	// 337 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0x4f, 0x4b, 0xc3, 0x30,
+	0x18, 0xc6, 0xe9, 0x3a, 0x37, 0xf7, 0xae, 0x0e, 0x89, 0x97, 0x20, 0x43, 0x4b, 0x4f, 0xc5, 0xc3,
+	0x10, 0xbd, 0x79, 0xd3, 0x8a, 0x30, 0x75, 0xb0, 0x46, 0xd9, 0xc9, 0x4b, 0xb7, 0xbe, 0xab, 0x81,
+	0xb5, 0xa9, 0x49, 0x8a, 0xec, 0x43, 0xf8, 0x11, 0xfd, 0x2e, 0x92, 0xec, 0x4f, 0x37, 0x44, 0xbc,
+	0xe5, 0xf9, 0xe5, 0x4d, 0x9e, 0xe7, 0x49, 0x0b, 0x27, 0xb3, 0xf7, 0x84, 0x17, 0x98, 0xde, 0x3d,
+	0xbc, 0x8e, 0x54, 0x36, 0x28, 0xa5, 0xd0, 0x82, 0x78, 0x1b, 0x38, 0xd7, 0xe3, 0x69, 0xf0, 0x06,
+	0xbd, 0xb8, 0x12, 0xb2, 0xca, 0x23, 0x94, 0xfa, 0x85, 0x67, 0x05, 0xa1, 0xd0, 0xbe, 0x4d, 0x53,
+	0x89, 0x4a, 0x51, 0xc7, 0x77, 0xc2, 0x0e, 0xdb, 0x48, 0xd2, 0x87, 0xce, 0xb8, 0x9a, 0x2e, 0xf8,
+	0xec, 0x09, 0x97, 0xb4, 0x61, 0xf7, 0x6a, 0x40, 0x08, 0x34, 0xcd, 0x79, 0xea, 0xfa, 0x4e, 0xe8,
+	0x31, 0xbb, 0x0e, 0xbe, 0x1d, 0xe8, 0x8e, 0xa5, 0x28, 0x85, 0x4a, 0x16, 0x23, 0x95, 0x91, 0x00,
+	0xbc, 0x72, 0x2d, 0x27, 0x1c, 0x3f, 0xad, 0x81, 0xcb, 0xf6, 0x18, 0x39, 0x03, 0xd8, 0xe8, 0x61,
+	0x6a, 0x6d, 0x3c, 0xb6, 0x43, 0x4c, 0x0a, 0xcd, 0x73, 0x54, 0x3a, 0xc9, 0x4b, 0x6b, 0xe6, 0xb2,
+	0x1a, 0x98, 0xdd, 0xc7, 0x4a, 0x69, 0x3e, 0x5f, 0xc6, 0x11, 0x6d, 0xda, 0xc3, 0x35, 0x20, 0x97,
+	0xeb, 0x8c, 0x07, 0xbe, 0x13, 0x76, 0xaf, 0xfa, 0x83, 0xdd, 0xa7, 0x18, 0xec, 0xbf, 0xc3, 0xaa,
+	0x81, 0xb9, 0x6f, 0xa4, 0xb2, 0x7b, 0x9e, 0xa1, 0xd2, 0xb4, 0xb5, 0xba, 0x6f, 0x0b, 0x82, 0x2f,
+	0x07, 0xda, 0x13, 0xa1, 0xd1, 0x74, 0x3b, 0x85, 0x43, 0xb3, 0x1c, 0x16, 0x73, 0x61, 0x7b, 0x79,
+	0x6c, 0xab, 0xc9, 0x05, 0x1c, 0x3f, 0x63, 0x9a, 0xa1, 0x8c, 0x44, 0x9e, 0x73, 0x6d, 0x67, 0x56,
+	0xcd, 0x7e, 0x71, 0x72, 0x03, 0x1d, 0xe3, 0x9c, 0xe8, 0x4a, 0x22, 0x75, 0x7d, 0xf7, 0xdf, 0xa0,
+	0xf5, 0x78, 0x10, 0x82, 0x17, 0x47, 0x0c, 0x3f, 0x2a, 0x54, 0xda, 0x64, 0xfa, 0xf3, 0x5b, 0x06,
+	0xe7, 0x70, 0x64, 0x26, 0x55, 0x29, 0x0a, 0x65, 0xe3, 0xf7, 0xa0, 0x11, 0x47, 0xeb, 0xe0, 0x8d,
+	0x38, 0x9a, 0xb6, 0xec, 0xdf, 0x72, 0xfd, 0x33, 0x00, 0x66, 0xf2, 0x60, 0xdc, 0x44, 0x02, 0x00,
+	0x00,
 }
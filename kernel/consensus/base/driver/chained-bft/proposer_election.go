@@ -6,3 +6,9 @@ type ProposerElectionInterface interface {
 	// 获取指定round的候选人节点Address
 	GetValidators(round int64) []string
 }
+
+// ProposalValidatorsInterface 为可选接口，按区块返回为其QC签名的验证人。
+// 开启纪元重配置时验证人由区块所在的纪元决定，与区块的高度无关
+type ProposalValidatorsInterface interface {
+	GetProposalValidators(proposalId []byte) ([]string, error)
+}
@@ -31,6 +31,7 @@ type saftyRulesInterface interface {
 	VoteProposal(proposalId []byte, proposalRound int64, parentQc storage.QuorumCertInterface) bool
 	CheckVote(qc storage.QuorumCertInterface, logid string, validators []string) error
 	CalVotesThreshold(input, sum int) bool
+	CalVotesQuorum(round int64, voted []string, collector string, validators []string) bool
 	CheckProposal(proposal, parent storage.QuorumCertInterface, justifyValidators []string) error
 	CheckPacemaker(pending, local int64) bool
 }
@@ -44,6 +45,8 @@ type DefaultSaftyRules struct {
 	preferredRound int64
 	Crypto         *cCrypto.CBFTCrypto
 	QcTree         *storage.QCPendingTree
+	// Weights 可选，返回指定round候选人的投票权重，返回非空时quorum按签名权重之和计算
+	Weights func(round int64) map[string]int64
 
 	Log logs.Logger
 }
@@ -117,6 +120,45 @@ func (s *DefaultSaftyRules) CalVotesThreshold(input, sum int) bool {
 	return input+1 >= sum-f
 }
 
+// CalVotesQuorum 判断round的签名是否达到quorum，voted为已签名的候选人，collector为收集投票的leader，其签名不包含在voted中
+// 候选人等权时按签名个数计算，否则按CalVotesWeightThreshold计算
+func (s *DefaultSaftyRules) CalVotesQuorum(round int64, voted []string, collector string, validators []string) bool {
+	if s.Weights != nil {
+		if weights := s.Weights(round); len(weights) > 0 {
+			return s.CalVotesWeightThreshold(append(append([]string{}, voted...), collector), validators, weights)
+		}
+	}
+	return s.CalVotesThreshold(len(voted), len(validators))
+}
+
+// CalVotesWeightThreshold 已签名候选人的权重之和需超过总权重的2/3，未设置权重的候选人权重为1
+// 各候选人权重均为1时与CalVotesThreshold结果一致
+func (s *DefaultSaftyRules) CalVotesWeightThreshold(voted []string, validators []string, weights map[string]int64) bool {
+	var sum, votedSum int64
+	for _, v := range validators {
+		sum += validatorWeight(weights, v)
+	}
+	counted := make(map[string]bool)
+	for _, v := range voted {
+		if counted[v] || !isInSlice(v, validators) {
+			continue
+		}
+		counted[v] = true
+		votedSum += validatorWeight(weights, v)
+	}
+	if sum <= 0 {
+		return false
+	}
+	return 3*votedSum > 2*sum
+}
+
+func validatorWeight(weights map[string]int64, addr string) int64 {
+	if w, ok := weights[addr]; ok {
+		return w
+	}
+	return 1
+}
+
 // CheckProposalMsg 原IsQuorumCertValidate 判断justify，即需check的block的parentQC是否合法
 // 需要注意的是，在上层bcs的实现中，由于共识操纵了账本回滚。因此实际上safetyrules需要proposalRound和parentRound严格相邻的
 // 因此在此proposal和parent的QC稍微宽松检查
@@ -146,7 +188,7 @@ func (s *DefaultSaftyRules) CheckProposal(proposal, parent storage.QuorumCertInt
 	// 检查justify的所有vote签名
 	justifySigns := parent.GetSignsInfo()
 	s.Log.Debug("DefaultSaftyRules::CheckProposal", "parent", parent, "justifyValidators", justifyValidators)
-	validCnt := 0
+	var voted []string
 	for _, v := range justifySigns {
 		if !isInSlice(v.GetAddress(), justifyValidators) {
 			continue
@@ -155,9 +197,14 @@ func (s *DefaultSaftyRules) CheckProposal(proposal, parent storage.QuorumCertInt
 		if ok, _ := s.Crypto.VerifyVoteMsgSign(v, parent.GetProposalId()); !ok {
 			return InvalidVoteSign
 		}
-		validCnt++
+		voted = append(voted, v.GetAddress())
+	}
+	// justify的投票由proposal的提出者收集，其自身签名不在justify中
+	var collector string
+	if signs := proposal.GetSignsInfo(); len(signs) > 0 {
+		collector = signs[0].GetAddress()
 	}
-	if !s.CalVotesThreshold(validCnt, len(justifyValidators)) {
+	if !s.CalVotesQuorum(parent.GetProposalView(), voted, collector, justifyValidators) {
 		return NoEnoughVotes
 	}
 	return nil
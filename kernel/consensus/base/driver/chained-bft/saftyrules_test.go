@@ -24,6 +24,53 @@ func TestCalVotesThreshold(t *testing.T) {
 
 }
 
+func TestCalVotesWeightThreshold(t *testing.T) {
+	s := &DefaultSaftyRules{}
+	validators := []string{"a", "b", "c", "d", "e", "f", "g"}
+	// 等权时与CalVotesThreshold一致，CalVotesThreshold的input不包含收集者自身
+	for n := 1; n <= len(validators); n++ {
+		for input := 0; input < n; input++ {
+			want := s.CalVotesThreshold(input, n)
+			if got := s.CalVotesWeightThreshold(validators[:input+1], validators[:n], nil); got != want {
+				t.Errorf("weight threshold mismatch, n:%d input:%d want:%v got:%v", n, input, want, got)
+			}
+		}
+	}
+	weights := map[string]int64{"a": 4, "b": 1, "c": 1}
+	if !s.CalVotesWeightThreshold([]string{"a", "b"}, validators[:3], weights) {
+		t.Error("a and b hold more than 2/3 weight")
+	}
+	if s.CalVotesWeightThreshold([]string{"b", "c", "c"}, validators[:3], weights) {
+		t.Error("b and c hold less than 2/3 weight")
+	}
+	if s.CalVotesWeightThreshold([]string{"a", "x"}, validators[:3], weights) {
+		t.Error("a alone holds less than 2/3 weight")
+	}
+}
+
+func TestCalVotesQuorum(t *testing.T) {
+	validators := []string{"a", "b", "c", "d"}
+	s := &DefaultSaftyRules{}
+	if !s.CalVotesQuorum(1, []string{"b", "c"}, "a", validators) {
+		t.Error("equal weights should count signatures")
+	}
+	s.Weights = func(round int64) map[string]int64 {
+		if round == 1 {
+			return map[string]int64{"d": 10}
+		}
+		return nil
+	}
+	if s.CalVotesQuorum(1, []string{"b", "c"}, "a", validators) {
+		t.Error("a, b and c hold less than 2/3 weight")
+	}
+	if !s.CalVotesQuorum(1, []string{"d"}, "a", validators) {
+		t.Error("a and d hold more than 2/3 weight")
+	}
+	if !s.CalVotesQuorum(2, []string{"b", "c"}, "a", validators) {
+		t.Error("round without weights should count signatures")
+	}
+}
+
 func TestCheckPacemaker(t *testing.T) {
 	s := &DefaultSaftyRules{}
 	if !s.CheckPacemaker(5, 4) {
@@ -12,8 +12,10 @@ import (
 	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
 	chainedBftPb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
 	"github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/storage"
+	"github.com/xuperchain/xupercore/kernel/consensus/base/evidence"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/kernel/network"
 	"github.com/xuperchain/xupercore/kernel/network/p2p"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/timer"
@@ -72,6 +74,8 @@ type Smr struct {
 	localProposal *sync.Map
 	// votes of QC in mem, key: voteId, value: []*QuorumCertSign
 	qcVoteMsgs *sync.Map
+	// 证据池，记录收到的提案和投票中的双签行为，为nil时不记录
+	evidence *evidence.Pool
 
 	// 该锁保护状态机处理msg或者bcs层操作过程，防止状态机get/set时由于bcs操作和msg处理并发导致的脏读脏写
 	mtx sync.Mutex
@@ -113,6 +117,11 @@ func (s *Smr) LoadVotes(proposalId []byte, signs []*chainedBftPb.QuorumCertSign)
 	}
 }
 
+// SetEvidencePool 设置证据池，需要在Start之前调用
+func (s *Smr) SetEvidencePool(pool *evidence.Pool) {
+	s.evidence = pool
+}
+
 // RegisterToNetwork register msg handler to p2p network
 func (s *Smr) RegisterToNetwork() error {
 	sub1 := s.p2p.NewSubscriber(xuperp2p.XuperMessage_CHAINED_BFT_NEW_VIEW_MSG, s.p2pMsgChan)
@@ -130,6 +139,13 @@ func (s *Smr) RegisterToNetwork() error {
 		return err
 	}
 	s.subscribeList.PushBack(sub3)
+	for _, typ := range []xuperp2p.XuperMessage_MessageType{xuperp2p.XuperMessage_CHAINED_BFT_GET_QC_MSG, xuperp2p.XuperMessage_CHAINED_BFT_QC_MSG} {
+		sub := s.p2p.NewSubscriber(typ, s.p2pMsgChan)
+		if err := s.p2p.Register(sub); err != nil {
+			return err
+		}
+		s.subscribeList.PushBack(sub)
+	}
 	return nil
 }
 
@@ -155,6 +171,9 @@ func (s *Smr) Start() {
 			select {
 			case msg := <-s.p2pMsgChan:
 				s.handleReceivedMsg(msg)
+				if acker, ok := s.p2p.(network.MessageAcker); ok {
+					acker.Ack(msg)
+				}
 			case <-s.quitCh:
 				return
 			}
@@ -173,6 +192,16 @@ func (s *Smr) GetRootQC() storage.QuorumCertInterface {
 	return s.qcTree.GetRootQC().In
 }
 
+// GetCommitQC 返回本地已提交的最高节点，该节点及其祖先不会被回滚，尚未形成commitQC时返回root
+func (s *Smr) GetCommitQC() storage.QuorumCertInterface {
+	root := s.qcTree.GetRootQC()
+	commit := s.qcTree.GetCommitQC()
+	if commit == nil || commit.In.GetProposalView() < root.In.GetProposalView() {
+		return root.In
+	}
+	return commit.In
+}
+
 func (s *Smr) GetCurrentView() int64 {
 	return s.pacemaker.GetCurrentView()
 }
@@ -186,7 +215,14 @@ func (s *Smr) CheckProposal(block cctx.BlockInterface, justify storage.QuorumCer
 	defer s.mtx.Unlock()
 
 	pNode := s.blockToProposalNode(block)
-	return s.saftyrules.CheckProposal(pNode.In, justify, validators)
+	// 带上区块的出块人，候选人带投票权重时需计入收集justify投票的leader
+	proposal := storage.NewQuorumCert(&storage.VoteInfo{
+		ProposalId:   pNode.In.GetProposalId(),
+		ProposalView: pNode.In.GetProposalView(),
+		ParentId:     pNode.In.GetParentProposalId(),
+		ParentView:   pNode.In.GetParentView(),
+	}, nil, []*chainedBftPb.QuorumCertSign{{Address: string(block.GetProposer())}})
+	return s.saftyrules.CheckProposal(proposal, justify, validators)
 }
 
 func (s *Smr) KeepUpWithBlock(block cctx.BlockInterface, justify storage.QuorumCertInterface, validators []string) error {
@@ -220,7 +256,7 @@ func (s *Smr) ResetProposerStatus(tipBlock cctx.BlockInterface,
 	defer s.mtx.Unlock()
 
 	if bytes.Equal(s.getHighQC().GetProposalId(), tipBlock.GetBlockid()) &&
-		s.validNewHighQC(tipBlock.GetBlockid(), validators) {
+		s.validNewHighQC(tipBlock.GetBlockid(), tipBlock.GetHeight(), validators) {
 		// 此处需要获取带签名的完整Justify
 		return false, s.getCompleteHighQC(), nil
 	}
@@ -254,7 +290,7 @@ func (s *Smr) ResetProposerStatus(tipBlock cctx.BlockInterface,
 			s.log.Error("consensus:smr:ResetProposerStatus: election error.")
 			return false, nil, ErrEmptyTarget
 		}
-		if !s.validNewHighQC(node.In.GetProposalId(), wantProposers) {
+		if !s.validNewHighQC(node.In.GetProposalId(), node.In.GetProposalView(), wantProposers) {
 			s.log.Warn("consensus:smr:ResetProposerStatus: target not ready", "target", utils.F(node.In.GetProposalId()), "wantProposers", wantProposers, "height", node.In.GetProposalView())
 			targetId = block.GetPreHash()
 			continue
@@ -288,6 +324,10 @@ func (s *Smr) handleReceivedMsg(msg *xuperp2p.XuperMessage) error {
 		s.handleReceivedProposal(msg)
 	case xuperp2p.XuperMessage_CHAINED_BFT_VOTE_MSG:
 		s.handleReceivedVoteMsg(msg)
+	case xuperp2p.XuperMessage_CHAINED_BFT_GET_QC_MSG:
+		s.handleQCRequest(msg)
+	case xuperp2p.XuperMessage_CHAINED_BFT_QC_MSG:
+		s.handleQCResponse(msg)
 	default:
 		s.log.Error("smr::handleReceivedMsg receive unknow type msg", "type", msg.GetHeader().GetType())
 		return nil
@@ -477,6 +517,10 @@ func (s *Smr) handleReceivedProposal(msg *xuperp2p.XuperMessage) {
 		s.log.Error("smr::handleReceivedProposal Unmarshal msg error", "logid", msg.GetHeader().GetLogid(), "error", err)
 		return
 	}
+	// 同一proposalId的提案会被去重，因此需要在去重之前记录
+	if s.evidence != nil {
+		s.evidence.AddProposal(newProposalMsg)
+	}
 
 	_, ok := s.localProposal.LoadOrStore(utils.F(newProposalMsg.GetProposalId()), newProposalMsg.Timestamp)
 	if ok && newProposalMsg.GetSign().Address != s.address {
@@ -630,6 +674,9 @@ func (s *Smr) handleReceivedVoteMsg(msg *xuperp2p.XuperMessage) error {
 		s.log.Error("smr::handleReceivedVoteMsg CheckVote error", "error", err, "msg", utils.F(voteQC.GetProposalId()))
 		return err
 	}
+	if s.evidence != nil {
+		s.evidence.AddVote(voteQC.GetProposalId(), voteQC.GetSignsInfo()[0])
+	}
 	s.log.Debug("smr::handleReceivedVoteMsg::receive vote", "voteId", utils.F(voteQC.GetProposalId()), "voteView", voteQC.GetProposalView(), "from", voteQC.GetSignsInfo()[0].Address)
 
 	// 若vote先于proposal到达，则直接丢弃票数
@@ -643,11 +690,10 @@ func (s *Smr) handleReceivedVoteMsg(msg *xuperp2p.XuperMessage) error {
 	}
 
 	// 存入本地voteInfo内存，查看签名数量是否超过2f+1
-	var VoteLen int
 	// 注意隐式，若!ok则证明签名数量为1，此时不可能超过2f+1
 	v, ok := s.qcVoteMsgs.LoadOrStore(utils.F(voteQC.GetProposalId()), voteQC.GetSignsInfo())
 	// 若ok=false，则仅store一个vote签名
-	VoteLen = 1
+	voted := []string{voteQC.GetSignsInfo()[0].Address}
 	if ok {
 		signs, _ := v.([]*chainedBftPb.QuorumCertSign)
 		stored := false
@@ -661,10 +707,10 @@ func (s *Smr) handleReceivedVoteMsg(msg *xuperp2p.XuperMessage) error {
 			signs = append(signs, voteQC.GetSignsInfo()[0])
 			s.qcVoteMsgs.Store(utils.F(voteQC.GetProposalId()), signs)
 		}
-		VoteLen = len(signs)
+		voted = signAddresses(signs)
 	}
 	// 查看签名数量是否达到2f+1, 需要获取justify对应的validators
-	if !s.saftyrules.CalVotesThreshold(VoteLen, len(s.election.GetValidators(voteQC.GetProposalView()))) {
+	if !s.saftyrules.CalVotesQuorum(voteQC.GetProposalView(), voted, s.address, s.election.GetValidators(voteQC.GetProposalView())) {
 		return nil
 	}
 
@@ -676,6 +722,113 @@ func (s *Smr) handleReceivedVoteMsg(msg *xuperp2p.XuperMessage) error {
 	return nil
 }
 
+// SyncQC 向validators请求其本地带完整签名的HighQC，用于新加入验证人集合或重启的节点追上最新的QC
+// 应答异步到达，校验签名后更新本地HighQC，本地尚未同步到对应区块时忽略
+func (s *Smr) SyncQC(validators []string) {
+	targets := s.removeLocalValidator(validators)
+	if len(targets) == 0 {
+		return
+	}
+	netMsg := p2p.NewMessage(xuperp2p.XuperMessage_CHAINED_BFT_GET_QC_MSG, &chainedBftPb.QCRequestMsg{
+		Address: s.address,
+	}, p2p.WithBCName(s.bcName))
+	if netMsg == nil {
+		s.log.Error("smr::SyncQC::NewMessage error")
+		return
+	}
+	go s.p2p.SendMessage(createNewBCtx(), netMsg, p2p.WithAccounts(targets))
+	s.log.Debug("smr::SyncQC::request qc", "targets", targets)
+}
+
+// handleQCRequest 将本地带完整签名的HighQC发送给请求方，HighQC尚未收集到签名时不应答
+func (s *Smr) handleQCRequest(msg *xuperp2p.XuperMessage) {
+	req := &chainedBftPb.QCRequestMsg{}
+	if err := p2p.Unmarshal(msg, req); err != nil {
+		s.log.Error("smr::handleQCRequest Unmarshal msg error", "logid", msg.GetHeader().GetLogid(), "error", err)
+		return
+	}
+	if req.GetAddress() == "" || req.GetAddress() == s.address {
+		return
+	}
+	s.mtx.Lock()
+	qc := s.getCompleteHighQC()
+	s.mtx.Unlock()
+	if len(qc.GetSignsInfo()) == 0 {
+		return
+	}
+	qcBytes, err := json.Marshal(qc)
+	if err != nil {
+		s.log.Error("smr::handleQCRequest Marshal qc error", "error", err)
+		return
+	}
+	netMsg := p2p.NewMessage(xuperp2p.XuperMessage_CHAINED_BFT_QC_MSG, &chainedBftPb.QCResponseMsg{
+		QC: qcBytes,
+	}, p2p.WithBCName(s.bcName), p2p.WithLogId(msg.GetHeader().GetLogid()))
+	if netMsg == nil {
+		s.log.Error("smr::handleQCRequest::NewMessage error")
+		return
+	}
+	go s.p2p.SendMessage(createNewBCtx(), netMsg, p2p.WithAccounts([]string{req.GetAddress()}))
+}
+
+// handleQCResponse 校验收到的QC，比本地HighQC更高时装载其签名并更新HighQC
+// QC的投票由下一轮的Leader收集，其自身签名不在QC中，因此以该Leader作为collector计算quorum
+func (s *Smr) handleQCResponse(msg *xuperp2p.XuperMessage) {
+	resp := &chainedBftPb.QCResponseMsg{}
+	if err := p2p.Unmarshal(msg, resp); err != nil {
+		s.log.Error("smr::handleQCResponse Unmarshal msg error", "logid", msg.GetHeader().GetLogid(), "error", err)
+		return
+	}
+	qc := &storage.QuorumCert{}
+	if err := json.Unmarshal(resp.GetQC(), qc); err != nil {
+		s.log.Error("smr::handleQCResponse Unmarshal qc error", "error", err)
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	// QC的签名只覆盖ProposalId，对端填写的view不可信，使用本地qcTree中对应节点的view
+	node := s.qcTree.DFSQueryNode(qc.GetProposalId())
+	if node == nil {
+		s.log.Debug("smr::handleQCResponse::qc not in local qcTree, drop it.", "id", utils.F(qc.GetProposalId()))
+		return
+	}
+	view := node.In.GetProposalView()
+	if view <= s.getHighQC().GetProposalView() {
+		return
+	}
+	validators, err := s.proposalValidators(node.In)
+	if err != nil {
+		s.log.Warn("smr::handleQCResponse::get validators error", "error", err, "id", utils.F(qc.GetProposalId()))
+		return
+	}
+	localQC := storage.NewQuorumCert(&storage.VoteInfo{
+		ProposalId:   node.In.GetProposalId(),
+		ProposalView: view,
+		ParentId:     node.In.GetParentProposalId(),
+		ParentView:   node.In.GetParentView(),
+	}, nil, qc.GetSignsInfo())
+	next := storage.NewQuorumCert(&storage.VoteInfo{
+		ProposalView: view + 1,
+	}, nil, []*chainedBftPb.QuorumCertSign{{Address: s.election.GetLeader(view + 1)}})
+	if err := s.saftyrules.CheckProposal(next, localQC, validators); err != nil {
+		s.log.Warn("smr::handleQCResponse::check qc error", "error", err, "id", utils.F(qc.GetProposalId()), "view", view)
+		return
+	}
+	s.updateJustifyQcStatus(localQC)
+	s.pacemaker.AdvanceView(localQC)
+	s.log.Debug("smr::handleQCResponse::sync qc", "id", utils.F(qc.GetProposalId()), "view", view,
+		"pacemaker view", s.pacemaker.GetCurrentView())
+}
+
+// proposalValidators 返回为qc指向的区块签名的验证人，选举模块实现了ProposalValidatorsInterface时按区块查找，否则按高度
+func (s *Smr) proposalValidators(qc storage.QuorumCertInterface) ([]string, error) {
+	if election, ok := s.election.(ProposalValidatorsInterface); ok {
+		return election.GetProposalValidators(qc.GetProposalId())
+	}
+	return s.election.GetValidators(qc.GetProposalView()), nil
+}
+
 // voteMsgToQC 提供一个从VoteMsg转化为quorumCert的方法，注意，两者struct其实相仿
 func (s *Smr) voteMsgToQC(msg *chainedBftPb.VoteMsg) (storage.QuorumCertInterface, error) {
 	voteInfo := &storage.VoteInfo{}
@@ -724,7 +877,7 @@ func (s *Smr) getCompleteHighQC() storage.QuorumCertInterface {
 	return storage.NewQuorumCert(vote, nil, signs)
 }
 
-func (s *Smr) validNewHighQC(inProposalId []byte, validators []string) bool {
+func (s *Smr) validNewHighQC(inProposalId []byte, round int64, validators []string) bool {
 	signInfo, ok := s.qcVoteMsgs.Load(utils.F(inProposalId))
 	if !ok {
 		return false
@@ -736,7 +889,15 @@ func (s *Smr) validNewHighQC(inProposalId []byte, validators []string) bool {
 	if len(validators) == 1 {
 		return len(signs) == len(validators)
 	}
-	return s.saftyrules.CalVotesThreshold(len(signs), len(validators))
+	return s.saftyrules.CalVotesQuorum(round, signAddresses(signs), s.address, validators)
+}
+
+func signAddresses(signs []*chainedBftPb.QuorumCertSign) []string {
+	addrs := make([]string, 0, len(signs))
+	for _, sign := range signs {
+		addrs = append(addrs, sign.GetAddress())
+	}
+	return addrs
 }
 
 func (s *Smr) enforceUpdateHighQC(inProposalId []byte) (bool, error) {
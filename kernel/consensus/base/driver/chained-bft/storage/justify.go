@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"bytes"
+	"container/list"
+	"errors"
+
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+// ErrCommittedBlockNotFound 在最大回溯深度内未找到已提交的区块
+var ErrCommittedBlockNotFound = errors.New("committed block not found within search depth")
+
+// CommitSearchDepth 查找已提交区块时沿父区块回溯的最大深度
+// 正常出块时三链在若干个区块内即可形成，长期无法形成三链说明共识已失去活性，此时不再继续回溯至起始高度
+var CommitSearchDepth int64 = 1000
+
+// JustifyFunc 解析区块中携带的justify，即该区块父链上某个区块的QC，区块未携带justify时返回nil
+type JustifyFunc func(block ledger.BlockHandle) (QuorumCertInterface, error)
+
+// CommittedBlock 根据区块携带的justify，返回从blockId(包含)看来已经提交的最高祖先区块
+// 三链规则: 若区块x的justify为其父区块x-1的QC，x-1的justify为x-2的QC，x-2的justify为x-3的QC，则x-3已经提交，不会再被回滚
+// 沿父区块向前查找，直到BFT起始高度startHeight，此时返回startHeight的前一个区块，即genesisQC对应的区块
+// 回溯超过CommitSearchDepth个区块仍未找到时返回ErrCommittedBlockNotFound
+func CommittedBlock(l cctx.LedgerRely, justify JustifyFunc, startHeight int64, blockId []byte) (ledger.BlockHandle, error) {
+	block, err := l.QueryBlockHeader(blockId)
+	chain := 0
+	for depth := int64(0); ; depth++ {
+		if err != nil {
+			return nil, err
+		}
+		if block.GetHeight() < startHeight {
+			return block, nil
+		}
+		if depth >= CommitSearchDepth {
+			return nil, ErrCommittedBlockNotFound
+		}
+		qc, err := justify(block)
+		if err == nil && qc != nil && bytes.Equal(qc.GetProposalId(), block.GetPreHash()) {
+			chain++
+		} else {
+			chain = 0
+		}
+		if chain == 3 {
+			return l.QueryBlockHeader(block.GetPreHash())
+		}
+		block, err = l.QueryBlockHeader(block.GetPreHash())
+	}
+}
+
+// RebuildQCTree 根据账本最近区块中携带的justify重建QC树，用于节点重启
+// 与InitQCTree按高度推算不同，root为从tipBlock看来已经提交的区块，highQC为tipBlock的justify指向的区块，
+// root至tipBlock之间的区块按父子关系挂在树上，同时返回这些区块携带的justify，调用方需据此重新装载QC签名
+// tipBlock未携带justify时退化为InitQCTree
+func RebuildQCTree(startHeight int64, l cctx.LedgerRely, justify JustifyFunc, log logs.Logger) (*QCPendingTree, []QuorumCertInterface) {
+	tip := l.GetTipBlock()
+	if tip.GetHeight() <= startHeight {
+		return InitQCTree(startHeight, l, log), nil
+	}
+	tipJustify, err := justify(tip)
+	if err != nil || tipJustify == nil {
+		log.Warn("RebuildQCTree tip block has no justify, use InitQCTree", "height", tip.GetHeight())
+		return InitQCTree(startHeight, l, log), nil
+	}
+	g, err := l.QueryBlockHeaderByHeight(startHeight - 1)
+	if err != nil {
+		log.Warn("RebuildQCTree QueryBlockHeaderByHeight failed", "error", err.Error())
+		return nil, nil
+	}
+	committed, err := CommittedBlock(l, justify, startHeight, tip.GetBlockid())
+	if err != nil {
+		log.Warn("RebuildQCTree find committed block failed", "error", err.Error())
+		return nil, nil
+	}
+
+	// 自tipBlock沿父区块回溯至root，逆序挂在树上
+	var blocks []ledger.BlockHandle
+	for b := tip; b.GetHeight() > committed.GetHeight(); {
+		blocks = append(blocks, b)
+		if b, err = l.QueryBlockHeader(b.GetPreHash()); err != nil {
+			log.Warn("RebuildQCTree QueryBlockHeader failed", "error", err.Error())
+			return nil, nil
+		}
+	}
+	gNode := &ProposalNode{
+		In: NewQuorumCert(
+			&VoteInfo{
+				ProposalId:   g.GetBlockid(),
+				ProposalView: g.GetHeight(),
+			},
+			&LedgerCommitInfo{
+				CommitStateId: g.GetBlockid(),
+			},
+			nil),
+	}
+	root := gNode
+	if !bytes.Equal(committed.GetBlockid(), g.GetBlockid()) {
+		root = blockToNode(committed)
+	}
+	tree := &QCPendingTree{
+		genesis:    gNode,
+		root:       root,
+		highQC:     root,
+		log:        log,
+		orphanList: list.New(),
+		orphanMap:  make(map[string]bool),
+	}
+	var justifies []QuorumCertInterface
+	parent := root
+	for i := len(blocks) - 1; i >= 0; i-- {
+		node := blockToNode(blocks[i])
+		parent.Sons = append(parent.Sons, node)
+		parent = node
+		if qc, err := justify(blocks[i]); err == nil && qc != nil {
+			justifies = append(justifies, qc)
+		}
+	}
+	if high := dfsQuery(root, tipJustify.GetProposalId()); high != nil {
+		tree.updateQCs(high)
+	}
+	return tree, justifies
+}
+
+func blockToNode(b ledger.BlockHandle) *ProposalNode {
+	return &ProposalNode{
+		In: NewQuorumCert(&VoteInfo{
+			ProposalId:   b.GetBlockid(),
+			ProposalView: b.GetHeight(),
+			ParentId:     b.GetPreHash(),
+			ParentView:   b.GetHeight() - 1,
+		}, &LedgerCommitInfo{
+			CommitStateId: b.GetBlockid(),
+		}, nil),
+	}
+}
@@ -0,0 +1,212 @@
+// 验证人双签证据，支持chained-bft的提案双签、投票双签以及tdpos/xpoa的区块双签。
+// 由于账本支持回滚重做，同一个proposer在同一高度(view)重新出块是合法的，
+// 因此判定双签的条件是在同一高度的同一个出块时间片内，对两个不同的对象签名。
+package evidence
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xuperchain/crypto/core/hash"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
+	pb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+)
+
+const (
+	// TypeProposal 同一proposer对同一view的两个不同提案签名
+	TypeProposal = "proposal"
+	// TypeVote 同一validator对同一view的两个不同提案投票
+	TypeVote = "vote"
+	// TypeBlock 同一proposer在同一高度生产了两个不同的区块
+	TypeBlock = "block"
+)
+
+var (
+	ErrUnknownType     = errors.New("unknown evidence type")
+	ErrInvalidEvidence = errors.New("invalid evidence structure")
+	ErrInvalidPeriod   = errors.New("invalid consensus period")
+	ErrInvalidSign     = errors.New("invalid signature in evidence")
+	ErrNotConflict     = errors.New("evidence items do not conflict")
+)
+
+// Evidence 双签证据，包含Offender签名的两个冲突对象，任何节点都可以独立验证
+type Evidence struct {
+	Type     string `json:"type"`
+	Offender string `json:"offender"`
+	// 提案和投票为view，区块为高度
+	Height int64 `json:"height"`
+	// 提案双签时为Offender签名的两个提案，投票双签时为Offender投票的两个提案
+	Proposals []*pb.ProposalMsg `json:"proposals,omitempty"`
+	// 投票双签时Offender对两个提案的投票签名，与Proposals一一对应
+	Votes []*pb.QuorumCertSign `json:"votes,omitempty"`
+	// 区块双签时的两个区块头
+	Blocks []*lpb.InternalBlock `json:"blocks,omitempty"`
+}
+
+// Unmarshal 解析submitEvidence合约参数中的证据
+func Unmarshal(buf []byte) (*Evidence, error) {
+	ev := &Evidence{}
+	if err := json.Unmarshal(buf, ev); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+func (e *Evidence) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// ID 证据的唯一标识，与两个冲突对象的先后顺序无关
+func (e *Evidence) ID() string {
+	a, b := e.conflictIds()
+	if bytes.Compare(a, b) > 0 {
+		a, b = b, a
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s_%s_%d_", e.Type, e.Offender, e.Height)
+	buf.Write(a)
+	buf.WriteByte('_')
+	buf.Write(b)
+	return hex.EncodeToString(hash.DoubleSha256(buf.Bytes()))
+}
+
+func (e *Evidence) conflictIds() ([]byte, []byte) {
+	switch e.Type {
+	case TypeProposal, TypeVote:
+		if len(e.Proposals) == 2 {
+			return e.Proposals[0].GetProposalId(), e.Proposals[1].GetProposalId()
+		}
+	case TypeBlock:
+		if len(e.Blocks) == 2 {
+			return e.Blocks[0].GetBlockid(), e.Blocks[1].GetBlockid()
+		}
+	}
+	return nil, nil
+}
+
+// Verify 校验证据的签名和冲突关系，period为共识的出块间隔，单位为毫秒
+func Verify(e *Evidence, c cctx.CryptoClient, period int64) error {
+	if e == nil || e.Offender == "" {
+		return ErrInvalidEvidence
+	}
+	if period <= 0 {
+		return ErrInvalidPeriod
+	}
+	switch e.Type {
+	case TypeProposal:
+		return verifyProposals(e, c, period)
+	case TypeVote:
+		return verifyVotes(e, c, period)
+	case TypeBlock:
+		return verifyBlocks(e, c, period)
+	}
+	return ErrUnknownType
+}
+
+func verifyProposals(e *Evidence, c cctx.CryptoClient, period int64) error {
+	if len(e.Proposals) != 2 || len(e.Votes) != 0 || len(e.Blocks) != 0 {
+		return ErrInvalidEvidence
+	}
+	for _, msg := range e.Proposals {
+		if msg.GetSign().GetAddress() != e.Offender {
+			return ErrInvalidEvidence
+		}
+	}
+	return checkConflictProposals(e, c, period)
+}
+
+func verifyVotes(e *Evidence, c cctx.CryptoClient, period int64) error {
+	if len(e.Proposals) != 2 || len(e.Votes) != 2 || len(e.Blocks) != 0 {
+		return ErrInvalidEvidence
+	}
+	if err := checkConflictProposals(e, c, period); err != nil {
+		return err
+	}
+	// 投票签名的内容为提案id
+	for i, sign := range e.Votes {
+		if sign.GetAddress() != e.Offender {
+			return ErrInvalidEvidence
+		}
+		if err := verifySign(c, sign, e.Proposals[i].GetProposalId()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkConflictProposals 校验两个提案的签名，且属于同一view的同一时间片
+func checkConflictProposals(e *Evidence, c cctx.CryptoClient, period int64) error {
+	for _, msg := range e.Proposals {
+		if msg.GetSign() == nil || msg.GetProposalView() != e.Height {
+			return ErrInvalidEvidence
+		}
+		digest, err := cCrypto.MakeProposalMsgDigest(msg)
+		if err != nil {
+			return err
+		}
+		if err := verifySign(c, msg.GetSign(), digest); err != nil {
+			return err
+		}
+	}
+	a, b := e.Proposals[0], e.Proposals[1]
+	if bytes.Equal(a.GetProposalId(), b.GetProposalId()) ||
+		Slot(a.GetTimestamp(), period) != Slot(b.GetTimestamp(), period) {
+		return ErrNotConflict
+	}
+	return nil
+}
+
+func verifyBlocks(e *Evidence, c cctx.CryptoClient, period int64) error {
+	if len(e.Blocks) != 2 || len(e.Proposals) != 0 || len(e.Votes) != 0 {
+		return ErrInvalidEvidence
+	}
+	for _, block := range e.Blocks {
+		if block == nil || block.Height != e.Height || string(block.Proposer) != e.Offender {
+			return ErrInvalidEvidence
+		}
+		if err := verifyBlockSign(c, block); err != nil {
+			return err
+		}
+	}
+	a, b := e.Blocks[0], e.Blocks[1]
+	if bytes.Equal(a.Blockid, b.Blockid) || Slot(a.Timestamp, period) != Slot(b.Timestamp, period) {
+		return ErrNotConflict
+	}
+	return nil
+}
+
+// verifyBlockSign 与账本的区块校验一致，区块头需要能还原出blockid，且由proposer签名
+func verifyBlockSign(c cctx.CryptoClient, block *lpb.InternalBlock) error {
+	blockid, err := ledger.MakeBlockID(block)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(blockid, block.Blockid) {
+		return ErrInvalidEvidence
+	}
+	return verifySign(c, &pb.QuorumCertSign{
+		Address:   string(block.Proposer),
+		PublicKey: string(block.Pubkey),
+		Sign:      block.Sign,
+	}, block.Blockid)
+}
+
+func verifySign(c cctx.CryptoClient, sign *pb.QuorumCertSign, msg []byte) error {
+	ok, err := cCrypto.NewCBFTCrypto(nil, c).VerifyVoteMsgSign(sign, msg)
+	if err != nil || !ok {
+		return ErrInvalidSign
+	}
+	return nil
+}
+
+// Slot 时间戳所在的出块时间片，与共识CompeteMaster中的时间片划分一致
+func Slot(timestamp int64, period int64) int64 {
+	return timestamp / int64(time.Millisecond) / period
+}
@@ -0,0 +1,203 @@
+package evidence
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
+	pb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+var (
+	NodeA   = "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"
+	PubKeyA = `{"Curvname":"P-256","X":36505150171354363400464126431978257855318414556425194490762274938603757905292,"Y":79656876957602994269528255245092635964473154458596947290316223079846501380076}`
+	PriKeyA = `{"Curvname":"P-256","X":36505150171354363400464126431978257855318414556425194490762274938603757905292,"Y":79656876957602994269528255245092635964473154458596947290316223079846501380076,"D":111497060296999106528800133634901141644446751975433315540300236500052690483486}`
+
+	NodeB   = "SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"
+	PubKeyB = `{"Curvname":"P-256","X":12866043091588565003171939933628544430893620588191336136713947797738961176765,"Y":82755103183873558994270855453149717093321792154549800459286614469868720031056}`
+	PriKeyB = `{"Curvname":"P-256","X":12866043091588565003171939933628544430893620588191336136713947797738961176765,"Y":82755103183873558994270855453149717093321792154549800459286614469868720031056,"D":74053182141043989390619716280199465858509830752513286817516873984288039572219}`
+
+	period int64 = 3000
+)
+
+func newTestCrypto(t *testing.T, addr, pubKey, priKey string) (*cCrypto.CBFTCrypto, cctx.CryptoClient) {
+	cc, err := client.CreateCryptoClientFromJSONPrivateKey([]byte(priKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, _ := cc.GetEcdsaPrivateKeyFromJsonStr(priKey)
+	pk, _ := cc.GetEcdsaPublicKeyFromJsonStr(pubKey)
+	a := &cctx.Address{
+		Address:       addr,
+		PrivateKeyStr: priKey,
+		PublicKeyStr:  pubKey,
+		PrivateKey:    sk,
+		PublicKey:     pk,
+	}
+	return cCrypto.NewCBFTCrypto(a, cc), cc
+}
+
+func newTestPool(t *testing.T, cc cctx.CryptoClient) *Pool {
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), filepath.Join(t.TempDir(), "log"))
+	log, _ := logs.NewLogger("", "evidence_test")
+	return NewPool(cc, period, log)
+}
+
+func slotStart() int64 {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	return (now/period + 1) * period * int64(time.Millisecond)
+}
+
+func signProposal(t *testing.T, c *cCrypto.CBFTCrypto, view int64, id string, ts int64) *pb.ProposalMsg {
+	msg, err := c.SignProposalMsg(&pb.ProposalMsg{
+		ProposalView: view,
+		ProposalId:   []byte(id),
+		Timestamp:    ts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func signBlock(t *testing.T, c *cCrypto.CBFTCrypto, height int64, preHash string, ts int64) *state.BlockAgent {
+	block := &lpb.InternalBlock{
+		Height:    height,
+		PreHash:   []byte(preHash),
+		Proposer:  []byte(c.Address.Address),
+		Pubkey:    []byte(c.Address.PublicKeyStr),
+		Timestamp: ts,
+		Transactions: []*lpb.Transaction{
+			{Txid: []byte("tx")},
+		},
+	}
+	var err error
+	if block.Blockid, err = ledger.MakeBlockID(block); err != nil {
+		t.Fatal(err)
+	}
+	if block.Sign, err = c.CryptoClient.SignECDSA(c.Address.PrivateKey, block.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	return state.NewBlockAgent(block)
+}
+
+func TestPoolProposal(t *testing.T) {
+	a, cc := newTestCrypto(t, NodeA, PubKeyA, PriKeyA)
+	p := newTestPool(t, cc)
+	ts := slotStart()
+
+	if ev := p.AddProposal(signProposal(t, a, 10, "p1", ts)); ev != nil {
+		t.Fatal("single proposal should not be evidence")
+	}
+	// 重复收到同一提案
+	if ev := p.AddProposal(signProposal(t, a, 10, "p1", ts)); ev != nil {
+		t.Fatal("same proposal should not be evidence")
+	}
+	// 回滚后在之后的时间片重做同一高度是合法的
+	if ev := p.AddProposal(signProposal(t, a, 10, "p2", ts+period*int64(time.Millisecond))); ev != nil {
+		t.Fatal("redo proposal in another slot should not be evidence")
+	}
+	ev := p.AddProposal(signProposal(t, a, 10, "p3", ts+int64(time.Millisecond)))
+	if ev == nil || ev.Type != TypeProposal || ev.Offender != NodeA || ev.Height != 10 {
+		t.Fatalf("expect proposal evidence, got:%+v", ev)
+	}
+	// 同一证据只输出一次
+	if ev := p.AddProposal(signProposal(t, a, 10, "p3", ts+int64(time.Millisecond))); ev != nil {
+		t.Fatal("same evidence should not be reported twice")
+	}
+
+	// 证据编码后仍然可以验证，且id与顺序无关
+	buf, _ := ev.Marshal()
+	decoded, err := Unmarshal(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(decoded, cc, period); err != nil {
+		t.Fatal(err)
+	}
+	decoded.Proposals[0], decoded.Proposals[1] = decoded.Proposals[1], decoded.Proposals[0]
+	if decoded.ID() != ev.ID() {
+		t.Fatal("evidence id should not depend on order")
+	}
+	decoded.Proposals[0].Timestamp += int64(time.Millisecond)
+	if err := Verify(decoded, cc, period); err != ErrInvalidSign {
+		t.Fatalf("tampered proposal should be rejected, err:%v", err)
+	}
+}
+
+func TestPoolVote(t *testing.T) {
+	a, cc := newTestCrypto(t, NodeA, PubKeyA, PriKeyA)
+	b, _ := newTestCrypto(t, NodeB, PubKeyB, PriKeyB)
+	p := newTestPool(t, cc)
+	ts := slotStart()
+
+	p1 := signProposal(t, a, 10, "p1", ts)
+	p2 := signProposal(t, a, 10, "p2", ts+int64(time.Millisecond))
+	p.AddProposal(p1)
+	p.AddProposal(p2)
+
+	sign1, _ := b.SignVoteMsg(p1.ProposalId)
+	if ev := p.AddVote(p1.ProposalId, sign1); ev != nil {
+		t.Fatal("single vote should not be evidence")
+	}
+	// 未收到提案的投票无法确定时间片
+	unknown, _ := b.SignVoteMsg([]byte("p9"))
+	if ev := p.AddVote([]byte("p9"), unknown); ev != nil {
+		t.Fatal("vote without proposal should be ignored")
+	}
+	sign2, _ := b.SignVoteMsg(p2.ProposalId)
+	ev := p.AddVote(p2.ProposalId, sign2)
+	if ev == nil || ev.Type != TypeVote || ev.Offender != NodeB {
+		t.Fatalf("expect vote evidence, got:%+v", ev)
+	}
+	if err := Verify(ev, cc, period); err != nil {
+		t.Fatal(err)
+	}
+	ev.Votes[1] = sign1
+	if err := Verify(ev, cc, period); err != ErrInvalidSign {
+		t.Fatalf("vote signature should match proposal, err:%v", err)
+	}
+}
+
+func TestPoolBlock(t *testing.T) {
+	a, cc := newTestCrypto(t, NodeA, PubKeyA, PriKeyA)
+	p := newTestPool(t, cc)
+	ts := slotStart()
+
+	if ev := p.AddBlock(signBlock(t, a, 5, "pre", ts)); ev != nil {
+		t.Fatal("single block should not be evidence")
+	}
+	if ev := p.AddBlock(signBlock(t, a, 5, "pre", ts+period*int64(time.Millisecond))); ev != nil {
+		t.Fatal("redo block in another slot should not be evidence")
+	}
+	ev := p.AddBlock(signBlock(t, a, 5, "fork", ts+int64(time.Millisecond)))
+	if ev == nil || ev.Type != TypeBlock || ev.Offender != NodeA || ev.Height != 5 {
+		t.Fatalf("expect block evidence, got:%+v", ev)
+	}
+	if len(ev.Blocks[0].Transactions) != 0 {
+		t.Fatal("block evidence should only contain headers")
+	}
+	if err := Verify(ev, cc, period); err != nil {
+		t.Fatal(err)
+	}
+	ev.Blocks[1].PreHash = []byte("pre")
+	if err := Verify(ev, cc, period); err != ErrInvalidEvidence {
+		t.Fatalf("tampered header should be rejected, err:%v", err)
+	}
+	ev.Blocks[1] = ev.Blocks[0]
+	if err := Verify(ev, cc, period); err != ErrNotConflict {
+		t.Fatalf("same block should not conflict, err:%v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package evidence
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	cCrypto "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/crypto"
+	pb "github.com/xuperchain/xupercore/kernel/consensus/base/driver/chained-bft/pb"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+var (
+	// MaxRecords 每类签名记录的数量上限，超过后清理较早高度的记录
+	MaxRecords = 1000
+	// KeepHeights 清理时保留的最近高度范围
+	KeepHeights int64 = 100
+	// MaxEvidences 本地记录的证据id数量上限，用于避免重复输出同一证据
+	MaxEvidences = 100
+)
+
+// HeaderBlock 能够导出区块头的区块，用于生成区块双签证据
+type HeaderBlock interface {
+	GetBlockHeader() *lpb.InternalBlock
+}
+
+type voteRecord struct {
+	proposal *pb.ProposalMsg
+	sign     *pb.QuorumCertSign
+}
+
+// Pool 本地证据池，记录收到的提案、投票和区块，签名人在同一高度的同一时间片内出现两个不同的签名对象时生成证据。
+// 证据仅保存在本地，需要通过共识的submitEvidence合约方法提交上链后才会惩罚。
+type Pool struct {
+	crypto cctx.CryptoClient
+	period int64
+	log    logs.Logger
+
+	mutex sync.Mutex
+	// key为signer_height_slot，value为见到的第一个签名对象
+	proposals map[string]*pb.ProposalMsg
+	votes     map[string]*voteRecord
+	blocks    map[string]*lpb.InternalBlock
+	// 按提案id索引的提案，用于确定投票所属的view和时间片
+	proposalIds map[string]*pb.ProposalMsg
+	maxHeight   int64
+
+	// 已发现的证据id，按发现顺序排列
+	foundIds []string
+	found    map[string]bool
+}
+
+// NewPool period为共识的出块间隔，单位为毫秒
+func NewPool(crypto cctx.CryptoClient, period int64, log logs.Logger) *Pool {
+	return &Pool{
+		crypto:      crypto,
+		period:      period,
+		log:         log,
+		proposals:   make(map[string]*pb.ProposalMsg),
+		votes:       make(map[string]*voteRecord),
+		blocks:      make(map[string]*lpb.InternalBlock),
+		proposalIds: make(map[string]*pb.ProposalMsg),
+		found:       make(map[string]bool),
+	}
+}
+
+// AddProposal 记录一个收到的提案，发现提案双签时返回证据
+func (p *Pool) AddProposal(msg *pb.ProposalMsg) *Evidence {
+	if msg.GetSign() == nil || p.period <= 0 {
+		return nil
+	}
+	digest, err := cCrypto.MakeProposalMsgDigest(msg)
+	if err != nil || verifySign(p.crypto, msg.GetSign(), digest) != nil {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.advance(msg.GetProposalView())
+	p.proposalIds[utils.F(msg.GetProposalId())] = msg
+	key := p.recordKey(msg.GetSign().GetAddress(), msg.GetProposalView(), msg.GetTimestamp())
+	prev, ok := p.proposals[key]
+	if !ok {
+		p.proposals[key] = msg
+		return nil
+	}
+	if bytes.Equal(prev.GetProposalId(), msg.GetProposalId()) {
+		return nil
+	}
+	return p.add(&Evidence{
+		Type:      TypeProposal,
+		Offender:  msg.GetSign().GetAddress(),
+		Height:    msg.GetProposalView(),
+		Proposals: []*pb.ProposalMsg{prev, msg},
+	})
+}
+
+// AddVote 记录一个收到的投票，只有收到过对应提案的投票才能确定时间片，发现投票双签时返回证据
+func (p *Pool) AddVote(proposalId []byte, sign *pb.QuorumCertSign) *Evidence {
+	if sign == nil || p.period <= 0 {
+		return nil
+	}
+	if verifySign(p.crypto, sign, proposalId) != nil {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	proposal, ok := p.proposalIds[utils.F(proposalId)]
+	if !ok {
+		return nil
+	}
+	p.advance(proposal.GetProposalView())
+	key := p.recordKey(sign.GetAddress(), proposal.GetProposalView(), proposal.GetTimestamp())
+	prev, ok := p.votes[key]
+	if !ok {
+		p.votes[key] = &voteRecord{proposal: proposal, sign: sign}
+		return nil
+	}
+	if bytes.Equal(prev.proposal.GetProposalId(), proposalId) {
+		return nil
+	}
+	return p.add(&Evidence{
+		Type:      TypeVote,
+		Offender:  sign.GetAddress(),
+		Height:    proposal.GetProposalView(),
+		Proposals: []*pb.ProposalMsg{prev.proposal, proposal},
+		Votes:     []*pb.QuorumCertSign{prev.sign, sign},
+	})
+}
+
+// AddBlock 记录一个通过共识校验的区块，发现区块双签时返回证据
+func (p *Pool) AddBlock(block cctx.BlockInterface) *Evidence {
+	hb, ok := block.(HeaderBlock)
+	if !ok || p.period <= 0 {
+		return nil
+	}
+	header := hb.GetBlockHeader()
+	if header == nil || verifyBlockSign(p.crypto, header) != nil {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.advance(header.Height)
+	key := p.recordKey(string(header.Proposer), header.Height, header.Timestamp)
+	prev, ok := p.blocks[key]
+	if !ok {
+		p.blocks[key] = header
+		return nil
+	}
+	if bytes.Equal(prev.Blockid, header.Blockid) {
+		return nil
+	}
+	return p.add(&Evidence{
+		Type:     TypeBlock,
+		Offender: string(header.Proposer),
+		Height:   header.Height,
+		Blocks:   []*lpb.InternalBlock{prev, header},
+	})
+}
+
+func (p *Pool) add(e *Evidence) *Evidence {
+	id := e.ID()
+	if p.found[id] {
+		return nil
+	}
+	if err := Verify(e, p.crypto, p.period); err != nil {
+		p.log.Warn("consensus:evidence: conflict found but evidence is invalid", "type", e.Type,
+			"offender", e.Offender, "height", e.Height, "err", err)
+		return nil
+	}
+	p.found[id] = true
+	p.foundIds = append(p.foundIds, id)
+	if len(p.foundIds) > MaxEvidences {
+		delete(p.found, p.foundIds[0])
+		p.foundIds = p.foundIds[1:]
+	}
+	// 日志中输出完整证据，便于通过submitEvidence提交
+	buf, _ := e.Marshal()
+	p.log.Warn("consensus:evidence: double sign detected", "type", e.Type, "offender", e.Offender,
+		"height", e.Height, "id", id, "evidence", string(buf))
+	return e
+}
+
+func (p *Pool) recordKey(signer string, height, timestamp int64) string {
+	return fmt.Sprintf("%s_%d_%d", signer, height, Slot(timestamp, p.period))
+}
+
+// advance 更新见到的最高高度，记录过多时清理较早的记录
+func (p *Pool) advance(height int64) {
+	if height > p.maxHeight {
+		p.maxHeight = height
+	}
+	if len(p.proposals)+len(p.proposalIds) <= MaxRecords*2 && len(p.votes) <= MaxRecords && len(p.blocks) <= MaxRecords {
+		return
+	}
+	low := p.maxHeight - KeepHeights
+	for k, v := range p.proposals {
+		if v.GetProposalView() < low {
+			delete(p.proposals, k)
+		}
+	}
+	for k, v := range p.proposalIds {
+		if v.GetProposalView() < low {
+			delete(p.proposalIds, k)
+		}
+	}
+	for k, v := range p.votes {
+		if v.proposal.GetProposalView() < low {
+			delete(p.votes, k)
+		}
+	}
+	for k, v := range p.blocks {
+		if v.Height < low {
+			delete(p.blocks, k)
+		}
+	}
+}
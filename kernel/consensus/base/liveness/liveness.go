@@ -0,0 +1,390 @@
+// 验证人活跃度统计，根据账本中最近若干区块的时间戳和出块人，还原每个出块时间片的轮值验证人，
+// 统计各验证人出块和错过的时间片数。统计结果只依赖账本数据，各节点在同一区块上的计算结果一致，
+// 因此可以作为调度依据：开启跳过策略后，连续错过时间片过多的验证人只保留每次轮值的第一个时间片，
+// 其余时间片交给后续的验证人，直到其重新出块。
+// 出块人将以父区块为结尾的统计写入区块共识存储，后续区块在此基础上滑动窗口，不需要每次回溯整个窗口。
+package liveness
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+var (
+	// ErrCountsMismatch 区块记录的统计与本地计算结果不一致
+	ErrCountsMismatch = errors.New("liveness counts in block mismatch")
+)
+
+var (
+	// DefaultWindow 默认统计窗口的区块数
+	DefaultWindow int64 = 100
+	// MaxGapSlots 两个相邻区块之间最多统计的空闲时间片数，避免链长时间停止后遍历过多时间片
+	MaxGapSlots int64 = 1000
+	// maxStatsCache 缓存的统计结果个数
+	maxStatsCache = 16
+)
+
+// Config 验证人活跃度配置，对应共识配置中的liveness字段
+type Config struct {
+	// 统计窗口的区块数，为0时使用DefaultWindow
+	Window int64 `json:"window,omitempty"`
+	// 验证人最近一次出块后连续错过的时间片数达到该值时临时跳过，为0时不开启跳过策略
+	MissThreshold int64 `json:"miss_threshold,omitempty"`
+}
+
+// Slot 一个出块时间片
+type Slot struct {
+	Term     int64
+	Pos      int64
+	BlockPos int64
+	// 是否为验证人每次轮值的第一个时间片，被跳过的验证人仍然保留该时间片，用于重新出块恢复
+	First bool
+}
+
+// ScheduleFunc 返回时间戳所在的出块时间片，时间戳不在任何出块时间片内时ok为false
+type ScheduleFunc func(timestamp int64, validators []string) (slot Slot, ok bool)
+
+// ValidatorsFunc 返回校验该区块时使用的验证人集合
+type ValidatorsFunc func(block ledger.BlockHandle) ([]string, error)
+
+// Stat 验证人在统计窗口内的出块情况
+type Stat struct {
+	// 出块数
+	Produced int64 `json:"produced"`
+	// 错过的时间片数
+	Missed int64 `json:"missed"`
+	// 最近一次出块后连续错过的时间片数
+	RecentMissed int64 `json:"recent_missed"`
+	// 是否正在被跳过
+	Skipped bool `json:"skipped"`
+}
+
+// record 单个区块对统计的贡献：出块人，以及与前一区块之间错过时间片的轮值验证人
+type record struct {
+	proposer string
+	missed   []string
+}
+
+// Tracker 验证人活跃度统计
+type Tracker struct {
+	conf Config
+	// 出块间隔, 单位为毫秒
+	period      int64
+	startHeight int64
+	ledger      cctx.LedgerRely
+	schedule    ScheduleFunc
+	validators  ValidatorsFunc
+	log         logs.Logger
+
+	mutex sync.Mutex
+	// key为blockid
+	records map[string]*record
+	stats   map[string]map[string]*Stat
+}
+
+// NewTracker 新建统计实例，conf为nil时使用默认配置，startHeight及之前的区块不参与统计
+func NewTracker(conf *Config, period int64, startHeight int64, l cctx.LedgerRely,
+	schedule ScheduleFunc, validators ValidatorsFunc, log logs.Logger) *Tracker {
+	t := &Tracker{
+		period:      period,
+		startHeight: startHeight,
+		ledger:      l,
+		schedule:    schedule,
+		validators:  validators,
+		log:         log,
+		records:     make(map[string]*record),
+		stats:       make(map[string]map[string]*Stat),
+	}
+	if conf != nil {
+		t.conf = *conf
+	}
+	if t.conf.Window <= 0 {
+		t.conf.Window = DefaultWindow
+	}
+	return t
+}
+
+// SkipEnabled 是否开启跳过策略
+func (t *Tracker) SkipEnabled() bool {
+	return t.conf.MissThreshold > 0
+}
+
+// Stats 统计以blockId为结尾的窗口内各验证人的出块情况，key为验证人地址，返回值不可修改
+// 区块共识存储中记录了父区块的统计时，在其基础上滑动窗口；未记录时(升级前的区块等)按照窗口内的区块重新统计
+func (t *Tracker) Stats(blockId []byte) (map[string]*Stat, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if stats, ok := t.stats[string(blockId)]; ok {
+		return stats, nil
+	}
+
+	block, err := t.ledger.QueryBlockHeader(blockId)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := t.storedStats(block)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		if stats, err = t.windowStats(block); err != nil {
+			return nil, err
+		}
+	}
+	if t.SkipEnabled() {
+		for _, s := range stats {
+			s.Skipped = s.RecentMissed >= t.conf.MissThreshold
+		}
+	}
+
+	if len(t.stats) >= maxStatsCache {
+		t.stats = make(map[string]map[string]*Stat)
+	}
+	t.stats[string(blockId)] = stats
+	return stats, nil
+}
+
+// storedStats 基于区块记录的父区块统计计算该区块的统计：加入该区块，移出窗口最早的区块，区块未记录时返回nil
+func (t *Tracker) storedStats(block ledger.BlockHandle) (map[string]*Stat, error) {
+	if block.GetHeight() <= t.startHeight {
+		return nil, nil
+	}
+	counts, err := storedCounts(block)
+	if err != nil || counts == nil {
+		return nil, err
+	}
+	stats := make(map[string]*Stat, len(counts))
+	for addr, c := range counts {
+		stats[addr] = &Stat{Produced: c.Produced, Missed: c.Missed, RecentMissed: c.RecentMissed}
+	}
+	// 父区块的窗口以blockId向前第Window个区块为开始
+	first := block
+	for i := int64(0); i < t.conf.Window && first.GetHeight() > t.startHeight; i++ {
+		if first, err = t.ledger.QueryBlockHeader(first.GetPreHash()); err != nil {
+			return nil, err
+		}
+	}
+	if first.GetHeight() > t.startHeight {
+		r, err := t.blockRecord(first)
+		if err != nil {
+			return nil, err
+		}
+		evictRecord(stats, r)
+	}
+	r, err := t.blockRecord(block)
+	if err != nil {
+		return nil, err
+	}
+	addRecord(stats, r)
+	return stats, nil
+}
+
+// windowStats 按照从旧到新的顺序累计以block为结尾的窗口内的区块
+func (t *Tracker) windowStats(block ledger.BlockHandle) (map[string]*Stat, error) {
+	var records []*record
+	for i := int64(0); i < t.conf.Window && block.GetHeight() > t.startHeight; i++ {
+		parent, err := t.ledger.QueryBlockHeader(block.GetPreHash())
+		if err != nil {
+			return nil, err
+		}
+		r, err := t.getRecord(block, parent)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+		block = parent
+	}
+	stats := make(map[string]*Stat)
+	for i := len(records) - 1; i >= 0; i-- {
+		addRecord(stats, records[i])
+	}
+	return stats, nil
+}
+
+// addRecord 将窗口最新区块的贡献累加到stats
+func addRecord(stats map[string]*Stat, r *record) {
+	for _, addr := range r.missed {
+		s := getStat(stats, addr)
+		s.Missed++
+		s.RecentMissed++
+	}
+	s := getStat(stats, r.proposer)
+	s.Produced++
+	s.RecentMissed = 0
+}
+
+// evictRecord 从stats中减去窗口最早区块的贡献。该区块错过的时间片早于其出块人的出块，
+// 只有验证人在窗口内(含该区块)没有出块时才计入了RecentMissed
+func evictRecord(stats map[string]*Stat, r *record) {
+	for _, addr := range r.missed {
+		s := getStat(stats, addr)
+		if s.Produced == 0 {
+			s.RecentMissed--
+		}
+		s.Missed--
+	}
+	getStat(stats, r.proposer).Produced--
+	for addr, s := range stats {
+		if s.Produced == 0 && s.Missed == 0 && s.RecentMissed == 0 {
+			delete(stats, addr)
+		}
+	}
+}
+
+func getStat(stats map[string]*Stat, addr string) *Stat {
+	if _, ok := stats[addr]; !ok {
+		stats[addr] = &Stat{}
+	}
+	return stats[addr]
+}
+
+// storedCounts 解析区块共识存储中记录的父区块统计，未记录时返回nil
+func storedCounts(block ledger.BlockHandle) (map[string]*common.LivenessCount, error) {
+	storage, _ := block.GetConsensusStorage()
+	if len(storage) == 0 {
+		return nil, nil
+	}
+	s, err := common.ParseOldQCStorage(storage)
+	if err != nil {
+		return nil, err
+	}
+	return s.Liveness, nil
+}
+
+// Counts 返回出块人需要记录在parentId之后新区块共识存储中的统计，不包含统计值均为0的验证人
+func (t *Tracker) Counts(parentId []byte) (map[string]*common.LivenessCount, error) {
+	stats, err := t.Stats(parentId)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]*common.LivenessCount, len(stats))
+	for addr, s := range stats {
+		if s.Produced == 0 && s.Missed == 0 && s.RecentMissed == 0 {
+			continue
+		}
+		counts[addr] = &common.LivenessCount{Produced: s.Produced, Missed: s.Missed, RecentMissed: s.RecentMissed}
+	}
+	return counts, nil
+}
+
+// Verify 校验区块记录的统计与本地按照父区块计算的结果一致，区块未记录统计时不做校验
+func (t *Tracker) Verify(block ledger.BlockHandle) error {
+	if block.GetHeight() <= t.startHeight {
+		return nil
+	}
+	stored, err := storedCounts(block)
+	if err != nil || stored == nil {
+		return err
+	}
+	want, err := t.Counts(block.GetPreHash())
+	if err != nil {
+		return err
+	}
+	if len(stored) != len(want) {
+		return ErrCountsMismatch
+	}
+	for addr, c := range want {
+		if s, ok := stored[addr]; !ok || s == nil || *s != *c {
+			return ErrCountsMismatch
+		}
+	}
+	return nil
+}
+
+// blockRecord 查询父区块并计算区块对统计的贡献
+func (t *Tracker) blockRecord(block ledger.BlockHandle) (*record, error) {
+	parent, err := t.ledger.QueryBlockHeader(block.GetPreHash())
+	if err != nil {
+		return nil, err
+	}
+	return t.getRecord(block, parent)
+}
+
+// getRecord 计算区块对统计的贡献，结果按blockid缓存
+func (t *Tracker) getRecord(block, parent ledger.BlockHandle) (*record, error) {
+	if r, ok := t.records[string(block.GetBlockid())]; ok {
+		return r, nil
+	}
+	validators, err := t.validators(block)
+	if err != nil {
+		return nil, err
+	}
+	r := &record{
+		proposer: string(block.GetProposer()),
+	}
+	// 两个区块之间的每个出块时间片都没有产生区块，记为轮值验证人错过，
+	// 时间片长度均为period，按period步进可以恰好经过每个时间片一次
+	step := t.period * int64(time.Millisecond)
+	last, lastOk := t.schedule(parent.GetTimestamp(), validators)
+	end, endOk := t.schedule(block.GetTimestamp(), validators)
+	for i, ts := int64(0), parent.GetTimestamp()+step; step > 0 && i < MaxGapSlots && ts < block.GetTimestamp(); i, ts = i+1, ts+step {
+		slot, ok := t.schedule(ts, validators)
+		if !ok || (lastOk && slot == last) || (endOk && slot == end) {
+			continue
+		}
+		last, lastOk = slot, true
+		r.missed = append(r.missed, validators[slot.Pos])
+	}
+
+	if int64(len(t.records)) >= t.conf.Window*4 {
+		t.records = make(map[string]*record)
+	}
+	t.records[string(block.GetBlockid())] = r
+	return r, nil
+}
+
+// Proposer 返回parentId之后slot时间片实际的出块人。
+// 开启跳过策略时，被跳过的验证人只保留每次轮值的第一个时间片，其余时间片由其后第一个未被跳过的验证人代替，
+// 所有验证人均被跳过时不做替换。被替换的时间片若仍未出块，依然记为原验证人错过。
+// 无法计算统计时返回空字符串，即该时间片没有合法的出块人，避免各节点对出块人的判断不一致
+func (t *Tracker) Proposer(parentId []byte, slot Slot, validators []string) string {
+	if slot.Pos < 0 || slot.Pos >= int64(len(validators)) {
+		return ""
+	}
+	want := validators[slot.Pos]
+	if !t.SkipEnabled() || slot.First {
+		return want
+	}
+	stats, err := t.Stats(parentId)
+	if err != nil {
+		t.log.Warn("consensus:liveness: calculate stats failed", "err", err)
+		return ""
+	}
+	skipped := func(addr string) bool {
+		s, ok := stats[addr]
+		return ok && s.Skipped
+	}
+	if !skipped(want) {
+		return want
+	}
+	for i := int64(1); i < int64(len(validators)); i++ {
+		next := validators[(slot.Pos+i)%int64(len(validators))]
+		if !skipped(next) {
+			return next
+		}
+	}
+	return want
+}
+
+// ValidatorStats 返回validators在以blockId为结尾的窗口内的出块情况，未出现在窗口内的验证人统计值为0
+func (t *Tracker) ValidatorStats(blockId []byte, validators []string) (map[string]*Stat, error) {
+	stats, err := t.Stats(blockId)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]*Stat, len(validators))
+	for _, v := range validators {
+		s := &Stat{}
+		if cur, ok := stats[v]; ok {
+			*s = *cur
+		}
+		out[v] = s
+	}
+	return out, nil
+}
@@ -0,0 +1,188 @@
+package liveness
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+	"github.com/xuperchain/xupercore/kernel/consensus/mock"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	kmock "github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+var (
+	period     int64 = 1000
+	blockNum   int64 = 2
+	baseSlot   int64 = 1000000
+	validators       = []string{"A", "B", "C"}
+)
+
+// testSchedule 每个验证人轮值blockNum个时间片
+func testSchedule(timestamp int64, v []string) (Slot, bool) {
+	slot := timestamp/int64(time.Millisecond)/period - baseSlot
+	if slot < 0 {
+		return Slot{}, false
+	}
+	pos := (slot / blockNum) % int64(len(v))
+	blockPos := slot % blockNum
+	return Slot{Term: slot / blockNum / int64(len(v)), Pos: pos, BlockPos: blockPos, First: blockPos == 0}, true
+}
+
+func slotTime(slot int64) int64 {
+	return ((baseSlot+slot)*period + period/2) * int64(time.Millisecond)
+}
+
+type testChain struct {
+	ledger *mock.FakeLedger
+	tip    *mock.FakeBlock
+}
+
+func (c *testChain) add(slot int64) {
+	s, _ := testSchedule(slotTime(slot), validators)
+	b := &mock.FakeBlock{
+		Height:    c.tip.Height + 1,
+		Blockid:   []byte(fmt.Sprintf("b%d", slot)),
+		PreHash:   c.tip.Blockid,
+		Timestamp: slotTime(slot),
+		Proposer:  validators[s.Pos],
+	}
+	c.ledger.Put(b)
+	c.tip = b
+}
+
+func newTestTracker(t *testing.T, threshold int64) (*Tracker, *testChain) {
+	econf, err := kmock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), filepath.Join(t.TempDir(), "log"))
+	log, _ := logs.NewLogger("", "liveness_test")
+
+	l := mock.NewFakeLedger(nil)
+	genesis := &mock.FakeBlock{Blockid: []byte("b0"), Timestamp: slotTime(0), Proposer: "A"}
+	l.Put(genesis)
+	getValidators := func(ledger.BlockHandle) ([]string, error) {
+		return validators, nil
+	}
+	conf := &Config{MissThreshold: threshold}
+	return NewTracker(conf, period, 0, l, testSchedule, getValidators, log), &testChain{ledger: l, tip: genesis}
+}
+
+func checkStat(t *testing.T, stats map[string]*Stat, addr string, produced, missed, recent int64, skipped bool) {
+	s, ok := stats[addr]
+	if !ok {
+		t.Fatalf("stat of %s not found", addr)
+	}
+	if s.Produced != produced || s.Missed != missed || s.RecentMissed != recent || s.Skipped != skipped {
+		t.Fatalf("unexpected stat of %s: %+v", addr, s)
+	}
+}
+
+func TestStats(t *testing.T) {
+	tracker, chain := newTestTracker(t, 2)
+	// 时间片4、5属于C，C离线
+	for _, slot := range []int64{1, 2, 3, 6, 7, 8, 9} {
+		chain.add(slot)
+	}
+	stats, err := tracker.ValidatorStats(chain.tip.Blockid, validators)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStat(t, stats, "A", 3, 0, 0, false)
+	checkStat(t, stats, "B", 4, 0, 0, false)
+	checkStat(t, stats, "C", 0, 2, 2, true)
+
+	// C只保留轮值的第一个时间片，其余时间片交给A
+	if p := tracker.Proposer(chain.tip.Blockid, Slot{Pos: 2, BlockPos: 0, First: true}, validators); p != "C" {
+		t.Fatalf("skipped validator should keep its first slot, got:%s", p)
+	}
+	if p := tracker.Proposer(chain.tip.Blockid, Slot{Pos: 2, BlockPos: 1}, validators); p != "A" {
+		t.Fatalf("skipped slot should be taken by next validator, got:%s", p)
+	}
+
+	// C重新出块后恢复
+	chain.add(10)
+	stats, _ = tracker.Stats(chain.tip.Blockid)
+	checkStat(t, stats, "C", 1, 2, 0, false)
+	if p := tracker.Proposer(chain.tip.Blockid, Slot{Pos: 2, BlockPos: 1}, validators); p != "C" {
+		t.Fatalf("recovered validator should be scheduled, got:%s", p)
+	}
+}
+
+func TestStatsWindow(t *testing.T) {
+	tracker, chain := newTestTracker(t, 0)
+	tracker.conf.Window = 3
+	for _, slot := range []int64{1, 2, 3, 6} {
+		chain.add(slot)
+	}
+	// 窗口内为时间片2、3、6的区块
+	stats, err := tracker.Stats(chain.tip.Blockid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStat(t, stats, "A", 1, 0, 0, false)
+	checkStat(t, stats, "B", 2, 0, 0, false)
+	checkStat(t, stats, "C", 0, 2, 2, false)
+	if tracker.SkipEnabled() {
+		t.Fatal("skip policy should be disabled by default")
+	}
+	if p := tracker.Proposer(chain.tip.Blockid, Slot{Pos: 2, BlockPos: 1}, validators); p != "C" {
+		t.Fatalf("validator should not be skipped when policy disabled, got:%s", p)
+	}
+}
+
+func TestStoredCounts(t *testing.T) {
+	miner, chain := newTestTracker(t, 2)
+	miner.conf.Window = 3
+	plain, plainChain := newTestTracker(t, 2)
+	plain.conf.Window = 3
+	// C错过时间片4、5、11，A错过时间片12
+	for _, slot := range []int64{1, 2, 3, 6, 7, 8, 9, 10, 13, 14} {
+		counts, err := miner.Counts(chain.tip.Blockid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		storage, _ := json.Marshal(common.ConsensusStorage{Liveness: counts})
+		chain.add(slot)
+		chain.tip.ConsensusStorage = storage
+		plainChain.add(slot)
+		if err := miner.Verify(chain.tip); err != nil {
+			t.Fatalf("slot %d verify error: %v", slot, err)
+		}
+		// 基于记录滑动窗口的结果与重新统计窗口的结果一致
+		got, err := miner.Stats(chain.tip.Blockid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := plain.Stats(plainChain.tip.Blockid)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("slot %d stored stats mismatch, got:%v want:%v", slot, got, want)
+		}
+	}
+
+	// 区块记录了错误的统计
+	counts, _ := miner.Counts(chain.tip.Blockid)
+	counts["C"].RecentMissed = 0
+	storage, _ := json.Marshal(common.ConsensusStorage{Liveness: counts})
+	chain.add(15)
+	chain.tip.ConsensusStorage = storage
+	if err := miner.Verify(chain.tip); err != ErrCountsMismatch {
+		t.Fatalf("expect ErrCountsMismatch, got %v", err)
+	}
+}
+
+func TestProposerStatsError(t *testing.T) {
+	tracker, _ := newTestTracker(t, 2)
+	// 无法统计时不退回原验证人，该时间片没有合法的出块人
+	if p := tracker.Proposer([]byte("unknown"), Slot{Pos: 2, BlockPos: 1}, validators); p != "" {
+		t.Fatalf("proposer should be empty when stats failed, got:%s", p)
+	}
+	if p := tracker.Proposer([]byte("unknown"), Slot{Pos: 2, BlockPos: 0, First: true}, validators); p != "C" {
+		t.Fatalf("first slot does not depend on stats, got:%s", p)
+	}
+}
@@ -34,4 +34,6 @@ type ConsensusStatus interface {
 	GetCurrentTerm() int64
 	// 获取当前矿工信息
 	GetCurrentValidatorsInfo() []byte
+	// 获取按本共识的规则已不可逆的最高区块高度及blockid，该区块及其祖先不会被回滚
+	GetFinalizedBlock() (int64, []byte)
 }
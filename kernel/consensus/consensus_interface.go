@@ -1,8 +1,11 @@
 package consensus
 
 import (
+	"math/big"
+
 	"github.com/xuperchain/xupercore/kernel/common/xcontext"
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/protos"
 )
 
 // ConsensusInterface 定义了一个共识实例需要实现的接口，用于kernel外的调用
@@ -21,6 +24,18 @@ type ConsensusInterface interface {
 	GetConsensusStatus() (ConsensusStatus, error)
 }
 
+// AwardSplitter 共识可选实现的接口，用于将区块奖励分配给出块人之外的地址，如tdpos的投票人分成
+type AwardSplitter interface {
+	// SplitAward 返回proposer在preHash之后出块时区块奖励award的分配方式，作为奖励交易的输出，第一项为proposer
+	SplitAward(preHash []byte, height int64, proposer string, award *big.Int) ([]*protos.TxOutput, error)
+}
+
+// EmptyBlockDecider 共识可选实现的接口，用于决定没有待打包交易时是否出空块，如raft只在有交易时出块
+type EmptyBlockDecider interface {
+	// AllowEmptyBlock 返回没有待打包交易时是否在height高度出空块
+	AllowEmptyBlock(height int64) bool
+}
+
 type PluggableConsensusInterface interface {
 	ConsensusInterface
 	SwitchConsensus(height int64) error
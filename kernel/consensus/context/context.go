@@ -2,6 +2,8 @@
 package context
 
 import (
+	"time"
+
 	"github.com/xuperchain/xupercore/kernel/common/xaddress"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/contract"
@@ -27,6 +29,11 @@ type LedgerRely interface {
 	QueryTipBlockHeader() ledger.BlockHandle
 }
 
+// IrreversibleLedger 可选的ledger接口，返回账本配置的不可逆滑动窗口，共识据此计算PoA/DPoS的不可逆高度
+type IrreversibleLedger interface {
+	GetIrreversibleSlideWindow() int64
+}
+
 // ConsensusCtx共识运行环境上下文
 type ConsensusCtx struct {
 	xctx.BaseCtx
@@ -36,4 +43,33 @@ type ConsensusCtx struct {
 	Contract contract.Manager
 	Ledger   LedgerRely
 	Network  network.Network
+	// Clock 共识使用的时钟，为nil时使用系统时钟，仿真测试时可注入虚拟时钟
+	Clock Clock
+}
+
+// GetClock 返回共识运行时使用的时钟
+func (ctx *ConsensusCtx) GetClock() Clock {
+	if ctx.Clock == nil {
+		return SystemClock
+	}
+	return ctx.Clock
+}
+
+// Clock 共识模块对时间的依赖，包括出块调度计算当前时间和等待下一个时间片
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
 }
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// SystemClock 系统时钟
+var SystemClock Clock = systemClock{}
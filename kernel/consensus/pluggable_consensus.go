@@ -3,6 +3,7 @@ package consensus
 import (
 	"encoding/json"
 	"errors"
+	"math/big"
 	"strconv"
 
 	"github.com/xuperchain/xupercore/kernel/common/xcontext"
@@ -10,6 +11,7 @@ import (
 	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/kernel/consensus/def"
 	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/protos"
 )
 
 const (
@@ -400,6 +402,31 @@ func (pc *PluggableConsensus) GetConsensusStatus() (ConsensusStatus, error) {
 	return con.GetConsensusStatus()
 }
 
+// SplitAward 调用具体实例的SplitAward()，实例未实现AwardSplitter时奖励全部归出块人
+func (pc *PluggableConsensus) SplitAward(preHash []byte, height int64, proposer string, award *big.Int) ([]*protos.TxOutput, error) {
+	con, _ := pc.getCurrentConsensusItem(height)
+	if con == nil {
+		pc.ctx.XLog.Error("Pluggable Consensus::SplitAward::tail consensus item is empty", "err", EmptyConsensusListErr)
+		return nil, EmptyConsensusListErr
+	}
+	if splitter, ok := con.(AwardSplitter); ok {
+		return splitter.SplitAward(preHash, height, proposer, award)
+	}
+	return []*protos.TxOutput{{ToAddr: []byte(proposer), Amount: award.Bytes()}}, nil
+}
+
+// AllowEmptyBlock 调用具体实例的AllowEmptyBlock()，实例未实现EmptyBlockDecider时允许出空块
+func (pc *PluggableConsensus) AllowEmptyBlock(height int64) bool {
+	con, _ := pc.getCurrentConsensusItem(height)
+	if con == nil {
+		return true
+	}
+	if decider, ok := con.(EmptyBlockDecider); ok {
+		return decider.AllowEmptyBlock(height)
+	}
+	return true
+}
+
 // SwitchConsensus 用于共识升级时切换共识实例
 func (pc *PluggableConsensus) SwitchConsensus(height int64) error {
 	// 获取最新的共识实例
@@ -70,6 +70,10 @@ func (s *FakeConsensusStatus) GetCurrentTerm() int64 {
 	return s.smr.GetCurrentTerm()
 }
 
+func (s *FakeConsensusStatus) GetFinalizedBlock() (int64, []byte) {
+	return 0, nil
+}
+
 type FakeConsensusImp struct {
 	smr    FakeSMRStruct
 	status *FakeConsensusStatus
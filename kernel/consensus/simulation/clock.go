@@ -0,0 +1,89 @@
+package simulation
+
+import (
+	"sync"
+	"time"
+)
+
+// sleepLatency 节点Sleep醒来的延迟
+const sleepLatency = time.Millisecond
+
+// Clock 仿真时钟，全部节点共享同一条虚拟时间线
+// 虚拟时间只在仿真调度推进或节点调用Sleep时前进，与真实时间无关
+type Clock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+// NewClock 新建从start开始计时的仿真时钟
+func NewClock(start time.Time) *Clock {
+	return &Clock{
+		now: start,
+	}
+}
+
+// Now 返回当前虚拟时间
+func (c *Clock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// Sleep 直接将虚拟时间推进d，并叠加sleepLatency模拟真实Sleep的唤醒延迟，避免恰好落在时间片边界上
+// 仿真中只有调度协程会在CompeteMaster中等待下一个时间片，推进全局时间等价于所有节点一起跳过了这段时间
+func (c *Clock) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d + sleepLatency)
+}
+
+// advanceTo 将虚拟时间推进到t，t不晚于当前时间时不做处理
+func (c *Clock) advanceTo(t time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if t.After(c.now) {
+		c.now = t
+	}
+}
+
+// NodeClock 节点本地时钟，在仿真时钟的基础上叠加一个偏移，用于模拟节点之间的时钟漂移
+// NodeClock实现了共识上下文中的Clock接口
+type NodeClock struct {
+	clock *Clock
+
+	mutex sync.Mutex
+	skew  time.Duration
+}
+
+func newNodeClock(clock *Clock) *NodeClock {
+	return &NodeClock{
+		clock: clock,
+	}
+}
+
+// Now 返回节点视角的当前时间
+func (c *NodeClock) Now() time.Time {
+	return c.clock.Now().Add(c.Skew())
+}
+
+// Sleep 推进仿真时钟
+func (c *NodeClock) Sleep(d time.Duration) {
+	c.clock.Sleep(d)
+}
+
+// SetSkew 设置节点时钟相对仿真时钟的偏移
+func (c *NodeClock) SetSkew(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.skew = d
+}
+
+// Skew 返回节点时钟相对仿真时钟的偏移
+func (c *NodeClock) Skew() time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.skew
+}
@@ -0,0 +1,284 @@
+package simulation
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+var (
+	ErrBlockNotFound  = errors.New("block not found")
+	ErrParentNotFound = errors.New("parent block not found")
+	ErrBlockItem      = errors.New("block item not supported")
+)
+
+// Block 仿真账本中的区块，只保留共识需要的区块头字段，实现了ledger.BlockHandle
+type Block struct {
+	Blockid          []byte `json:"blockid"`
+	PreHash          []byte `json:"preHash"`
+	Proposer         string `json:"proposer"`
+	Height           int64  `json:"height"`
+	Timestamp        int64  `json:"timestamp"`
+	PublicKey        string `json:"publicKey"`
+	Nonce            int32  `json:"nonce,omitempty"`
+	ConsensusStorage []byte `json:"consensusStorage,omitempty"`
+}
+
+var _ ledger.BlockHandle = (*Block)(nil)
+
+// MakeBlockId 区块id由父区块、高度、出块人、时间戳和nonce决定
+// 共识存储中包含ECDSA签名，不参与区块id的计算，保证同一种子下区块id稳定
+func (b *Block) MakeBlockId() ([]byte, error) {
+	h := sha256.New()
+	h.Write(b.PreHash)
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(b.Height))
+	h.Write(buf[:])
+	h.Write([]byte(b.Proposer))
+	binary.BigEndian.PutUint64(buf[:], uint64(b.Timestamp))
+	h.Write(buf[:])
+	binary.BigEndian.PutUint32(buf[:4], uint32(b.Nonce))
+	h.Write(buf[:4])
+	return h.Sum(nil), nil
+}
+
+func (b *Block) SetItem(item string, value interface{}) error {
+	switch item {
+	case "nonce":
+		if nonce, ok := value.(int32); ok {
+			b.Nonce = nonce
+			return nil
+		}
+	case "blockid":
+		if id, ok := value.([]byte); ok {
+			b.Blockid = id
+			return nil
+		}
+	}
+	return ErrBlockItem
+}
+
+func (b *Block) GetProposer() []byte {
+	return []byte(b.Proposer)
+}
+
+func (b *Block) GetHeight() int64 {
+	return b.Height
+}
+
+func (b *Block) GetBlockid() []byte {
+	return b.Blockid
+}
+
+func (b *Block) GetConsensusStorage() ([]byte, error) {
+	return b.ConsensusStorage, nil
+}
+
+func (b *Block) GetTimestamp() int64 {
+	return b.Timestamp
+}
+
+func (b *Block) GetPreHash() []byte {
+	return b.PreHash
+}
+
+func (b *Block) GetNextHash() []byte {
+	return nil
+}
+
+func (b *Block) GetPublicKey() string {
+	return b.PublicKey
+}
+
+func (b *Block) GetSign() []byte {
+	return nil
+}
+
+func (b *Block) GetTxIDs() []string {
+	return nil
+}
+
+func (b *Block) GetInTrunk() bool {
+	return false
+}
+
+// Ledger 仿真节点的内存账本，保存收到的全部区块，按最长链选择主干，高度相同时保留先收到的分支
+// Ledger实现了共识依赖的LedgerRely接口，合约状态始终为空
+type Ledger struct {
+	consensusConf []byte
+
+	mutex  sync.RWMutex
+	blocks map[string]*Block
+	trunk  []*Block
+}
+
+// NewLedger 以genesis为创世块新建账本
+func NewLedger(genesis *Block, consensusConf []byte) *Ledger {
+	return &Ledger{
+		consensusConf: consensusConf,
+		blocks: map[string]*Block{
+			utils.F(genesis.Blockid): genesis,
+		},
+		trunk: []*Block{genesis},
+	}
+}
+
+// Append 写入区块，区块高度超过当前主干时切换主干，返回主干是否发生变化
+func (l *Ledger) Append(block *Block) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if _, ok := l.blocks[utils.F(block.PreHash)]; !ok {
+		return false, ErrParentNotFound
+	}
+	l.blocks[utils.F(block.Blockid)] = block
+	if block.Height <= l.trunk[len(l.trunk)-1].Height {
+		return false, nil
+	}
+	l.switchTo(block)
+	return true, nil
+}
+
+// Truncate 将主干回滚到target，target可以位于分支上
+func (l *Ledger) Truncate(target []byte) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	block, ok := l.blocks[utils.F(target)]
+	if !ok {
+		return ErrBlockNotFound
+	}
+	l.switchTo(block)
+	return nil
+}
+
+// switchTo 以block为主干的最新区块重建主干
+func (l *Ledger) switchTo(block *Block) {
+	var branch []*Block
+	for b := block; ; {
+		if b.Height < int64(len(l.trunk)) && bytes.Equal(l.trunk[b.Height].Blockid, b.Blockid) {
+			break
+		}
+		branch = append(branch, b)
+		b = l.blocks[utils.F(b.PreHash)]
+	}
+	trunk := l.trunk[:block.Height-int64(len(branch))+1]
+	for i := len(branch) - 1; i >= 0; i-- {
+		trunk = append(trunk, branch[i])
+	}
+	l.trunk = trunk
+}
+
+// HasBlock 账本中是否存在该区块(包括分支)
+func (l *Ledger) HasBlock(blockid []byte) bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	_, ok := l.blocks[utils.F(blockid)]
+	return ok
+}
+
+// QueryBlock 查询区块(包括分支)
+func (l *Ledger) QueryBlock(blockid []byte) (*Block, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	block, ok := l.blocks[utils.F(blockid)]
+	if !ok {
+		return nil, ErrBlockNotFound
+	}
+	return block, nil
+}
+
+// Trunk 返回当前主干的拷贝，下标即区块高度
+func (l *Ledger) Trunk() []*Block {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return append([]*Block(nil), l.trunk...)
+}
+
+// TipHeight 返回主干高度
+func (l *Ledger) TipHeight() int64 {
+	return l.tip().Height
+}
+
+func (l *Ledger) tip() *Block {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.trunk[len(l.trunk)-1]
+}
+
+func (l *Ledger) GetConsensusConf() ([]byte, error) {
+	return l.consensusConf, nil
+}
+
+func (l *Ledger) QueryBlockHeader(blkId []byte) (ledger.BlockHandle, error) {
+	block, err := l.QueryBlock(blkId)
+	if err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func (l *Ledger) QueryBlockHeaderByHeight(height int64) (ledger.BlockHandle, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	if height < 0 || height >= int64(len(l.trunk)) {
+		return nil, fmt.Errorf("%w: height %d", ErrBlockNotFound, height)
+	}
+	return l.trunk[height], nil
+}
+
+func (l *Ledger) GetTipBlock() ledger.BlockHandle {
+	return l.tip()
+}
+
+func (l *Ledger) QueryTipBlockHeader() ledger.BlockHandle {
+	return l.tip()
+}
+
+func (l *Ledger) GetTipXMSnapshotReader() (ledger.XMSnapshotReader, error) {
+	return emptySnapshotReader{}, nil
+}
+
+func (l *Ledger) CreateSnapshot(blkId []byte) (ledger.XMReader, error) {
+	if !l.HasBlock(blkId) {
+		return nil, ErrBlockNotFound
+	}
+	return emptyReader{}, nil
+}
+
+func (l *Ledger) GetTipSnapshot() (ledger.XMReader, error) {
+	return emptyReader{}, nil
+}
+
+// 仿真不执行合约，状态读取始终为空，共识使用创世配置中的候选人
+type emptySnapshotReader struct{}
+
+func (emptySnapshotReader) Get(bucket string, key []byte) ([]byte, error) {
+	return nil, nil
+}
+
+type emptyReader struct{}
+
+func (emptyReader) Get(bucket string, key []byte) (*ledger.VersionedData, error) {
+	return nil, nil
+}
+
+func (emptyReader) Select(bucket string, startKey []byte, endKey []byte) (ledger.XMIterator, error) {
+	return emptyIterator{}, nil
+}
+
+func (emptyReader) GetUncommited(bucket string, key []byte) (*ledger.VersionedData, error) {
+	return nil, nil
+}
+
+type emptyIterator struct{}
+
+func (emptyIterator) Key() []byte                  { return nil }
+func (emptyIterator) Value() *ledger.VersionedData { return nil }
+func (emptyIterator) Next() bool                   { return false }
+func (emptyIterator) Error() error                 { return nil }
+func (emptyIterator) Close()                       {}
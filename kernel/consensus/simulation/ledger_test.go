@@ -0,0 +1,53 @@
+package simulation
+
+import (
+	"bytes"
+	"testing"
+)
+
+func newTestBlock(parent *Block, proposer string) *Block {
+	b := &Block{
+		PreHash:   parent.Blockid,
+		Proposer:  proposer,
+		Height:    parent.Height + 1,
+		Timestamp: parent.Timestamp + 1,
+	}
+	b.Blockid, _ = b.MakeBlockId()
+	return b
+}
+
+func TestLedgerForkChoice(t *testing.T) {
+	genesis := &Block{Timestamp: DefaultGenesis.UnixNano()}
+	genesis.Blockid, _ = genesis.MakeBlockId()
+	l := NewLedger(genesis, nil)
+
+	a1 := newTestBlock(genesis, "a")
+	b1 := newTestBlock(genesis, "b")
+	b2 := newTestBlock(b1, "b")
+	if changed, err := l.Append(a1); err != nil || !changed {
+		t.Fatalf("append a1: changed=%v err=%v", changed, err)
+	}
+	if changed, err := l.Append(b1); err != nil || changed {
+		t.Fatalf("append b1 should not switch trunk: changed=%v err=%v", changed, err)
+	}
+	if changed, err := l.Append(b2); err != nil || !changed {
+		t.Fatalf("append b2 should switch trunk: changed=%v err=%v", changed, err)
+	}
+	trunk := l.Trunk()
+	if len(trunk) != 3 || !bytes.Equal(trunk[1].Blockid, b1.Blockid) {
+		t.Fatalf("unexpected trunk after switch")
+	}
+	if _, err := l.Append(newTestBlock(newTestBlock(a1, "a"), "a")); err != ErrParentNotFound {
+		t.Errorf("want ErrParentNotFound, got %v", err)
+	}
+
+	if err := l.Truncate(a1.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	if l.TipHeight() != 1 || !bytes.Equal(l.tip().Blockid, a1.Blockid) {
+		t.Errorf("truncate should switch trunk to a1, tip height %d", l.TipHeight())
+	}
+	if !l.HasBlock(b2.Blockid) {
+		t.Errorf("blocks on the abandoned branch should be kept")
+	}
+}
@@ -0,0 +1,464 @@
+package simulation
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/network"
+	nctx "github.com/xuperchain/xupercore/kernel/network/context"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/timer"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+var (
+	ErrUnsupported       = errors.New("not supported by simulated network")
+	ErrInvalidSubscriber = errors.New("subscriber is not created by simulated network")
+	ErrSubscriberExists  = errors.New("subscriber already registered")
+	ErrSubscriberAbsent  = errors.New("subscriber not registered")
+)
+
+const (
+	// settlePoll 等待订阅者处理消息时的轮询间隔(真实时间)
+	settlePoll = 200 * time.Microsecond
+	// settleQuiet 判定网络静默需要的无新消息时长(真实时间)，覆盖共识模块异步发送消息的协程调度延迟
+	settleQuiet = 2 * time.Millisecond
+	// settleTimeout 等待网络静默的最长时间(真实时间)
+	settleTimeout = 5 * time.Second
+)
+
+// NetworkStats 仿真网络的消息统计
+type NetworkStats struct {
+	Sent      int64
+	Delivered int64
+	Dropped   int64
+}
+
+// Network 内存中的仿真网络，连接仿真中的全部节点
+// 消息按照虚拟时间投递，每条消息的时延和是否丢包由种子、收发双方、消息类型和该链路上的序号确定，
+// 同一虚拟时刻到达的消息按照收发双方和序号排序投递，因此故障注入和投递顺序在给定种子下是确定的
+// 支持设置时延区间、丢包率、网络分区和节点宕机
+type Network struct {
+	clock *Clock
+	seed  int64
+	log   logs.Logger
+
+	mutex     sync.Mutex
+	endpoints map[string]*Endpoint
+	queue     []*envelope
+	linkSeq   map[string]uint64
+	minDelay  time.Duration
+	maxDelay  time.Duration
+	dropRate  float64
+	// partition 节点所在的分区编号，为nil时全部节点互通
+	partition map[string]int
+	crashed   map[string]bool
+	stats     NetworkStats
+
+	// 已投递到订阅channel但订阅者尚未处理完毕的消息数
+	inflight int64
+	// 累计调用发送接口的次数，用于判断网络是否静默
+	sends int64
+}
+
+type envelope struct {
+	at   time.Time
+	from string
+	to   string
+	seq  uint64
+	msg  *pb.XuperMessage
+}
+
+// NewNetwork 新建仿真网络
+func NewNetwork(clock *Clock, seed int64, log logs.Logger) *Network {
+	return &Network{
+		clock:     clock,
+		seed:      seed,
+		log:       log,
+		endpoints: make(map[string]*Endpoint),
+		linkSeq:   make(map[string]uint64),
+		crashed:   make(map[string]bool),
+	}
+}
+
+// Join 为address创建网络接入点，接入点实现了network.Network接口
+func (n *Network) Join(address string) *Endpoint {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if e, ok := n.endpoints[address]; ok {
+		return e
+	}
+	e := &Endpoint{
+		net:     n,
+		address: address,
+		ctx: &nctx.NetCtx{
+			BaseCtx: xctx.BaseCtx{XLog: n.log, Timer: timer.NewXTimer()},
+		},
+		subscribers: make(map[pb.XuperMessage_MessageType][]*subscriber),
+	}
+	n.endpoints[address] = e
+	return e
+}
+
+// SetDelay 设置消息投递时延区间
+func (n *Network) SetDelay(min, max time.Duration) {
+	if max < min {
+		max = min
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.minDelay, n.maxDelay = min, max
+}
+
+// SetDropRate 设置丢包率
+func (n *Network) SetDropRate(rate float64) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.dropRate = rate
+}
+
+// Partition 将网络划分为若干互不连通的分区，未出现在groups中的节点各自单独成为一个分区
+func (n *Network) Partition(groups ...[]string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.partition = make(map[string]int)
+	for i, group := range groups {
+		for _, address := range group {
+			n.partition[address] = i + 1
+		}
+	}
+}
+
+// Heal 撤销网络分区
+func (n *Network) Heal() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.partition = nil
+}
+
+// Crash 节点宕机，宕机节点既不能发送也不能接收消息
+func (n *Network) Crash(address string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.crashed[address] = true
+}
+
+// Recover 宕机节点恢复
+func (n *Network) Recover(address string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	delete(n.crashed, address)
+}
+
+// Stats 返回消息统计
+func (n *Network) Stats() NetworkStats {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.stats
+}
+
+// send 将from发出的消息放入投递队列，targets为空时广播给全部其他节点
+func (n *Network) send(from string, targets []string, msg *pb.XuperMessage) {
+	atomic.AddInt64(&n.sends, 1)
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.crashed[from] {
+		return
+	}
+	if len(targets) == 0 {
+		for address := range n.endpoints {
+			targets = append(targets, address)
+		}
+		sort.Strings(targets)
+	}
+	for _, to := range targets {
+		if to == from {
+			continue
+		}
+		if _, ok := n.endpoints[to]; !ok {
+			continue
+		}
+		n.stats.Sent++
+		link := from + "/" + to + "/" + msg.GetHeader().GetType().String()
+		seq := n.linkSeq[link]
+		n.linkSeq[link] = seq + 1
+		r := n.random(link, seq)
+		if !n.connected(from, to) || r.Float64() < n.dropRate {
+			n.stats.Dropped++
+			continue
+		}
+		delay := n.minDelay
+		if n.maxDelay > n.minDelay {
+			delay += time.Duration(r.Int63n(int64(n.maxDelay-n.minDelay) + 1))
+		}
+		n.queue = append(n.queue, &envelope{
+			at:   n.clock.Now().Add(delay),
+			from: from,
+			to:   to,
+			seq:  seq,
+			msg:  proto.Clone(msg).(*pb.XuperMessage),
+		})
+	}
+}
+
+// random 返回由种子、链路和序号确定的随机数生成器
+func (n *Network) random(link string, seq uint64) *rand.Rand {
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(n.seed))
+	h.Write(buf[:])
+	h.Write([]byte(link))
+	binary.BigEndian.PutUint64(buf[:], seq)
+	h.Write(buf[:])
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+func (n *Network) connected(from, to string) bool {
+	if n.crashed[from] || n.crashed[to] {
+		return false
+	}
+	if n.partition == nil {
+		return true
+	}
+	return n.partition[from] == n.partition[to] && n.partition[from] != 0
+}
+
+// nextDelivery 返回队列中最早的投递时间
+func (n *Network) nextDelivery() (time.Time, bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if len(n.queue) == 0 {
+		return time.Time{}, false
+	}
+	next := n.queue[0].at
+	for _, e := range n.queue[1:] {
+		if e.at.Before(next) {
+			next = e.at
+		}
+	}
+	return next, true
+}
+
+// deliverDue 按序投递所有到期消息，返回投递的消息数
+func (n *Network) deliverDue() int {
+	now := n.clock.Now()
+	n.mutex.Lock()
+	var due, rest []*envelope
+	for _, e := range n.queue {
+		if e.at.After(now) {
+			rest = append(rest, e)
+			continue
+		}
+		due = append(due, e)
+	}
+	n.queue = rest
+	sort.Slice(due, func(i, j int) bool {
+		a, b := due[i], due[j]
+		if !a.at.Equal(b.at) {
+			return a.at.Before(b.at)
+		}
+		if a.from != b.from {
+			return a.from < b.from
+		}
+		if a.to != b.to {
+			return a.to < b.to
+		}
+		if a.msg.GetHeader().GetType() != b.msg.GetHeader().GetType() {
+			return a.msg.GetHeader().GetType() < b.msg.GetHeader().GetType()
+		}
+		return a.seq < b.seq
+	})
+	targets := make([]*Endpoint, 0, len(due))
+	for _, e := range due {
+		// 消息在途期间发生分区或宕机的，同样丢弃
+		if !n.connected(e.from, e.to) {
+			n.stats.Dropped++
+			targets = append(targets, nil)
+			continue
+		}
+		n.stats.Delivered++
+		targets = append(targets, n.endpoints[e.to])
+	}
+	n.mutex.Unlock()
+
+	for i, e := range due {
+		if targets[i] != nil {
+			targets[i].deliver(e.msg)
+		}
+	}
+	return len(due)
+}
+
+// waitIdle 等待投递的消息全部被订阅者处理完毕，并且一段静默期内没有新的发送
+// 共识模块通过协程异步发送消息，因此需要用真实时间的静默期来确认没有尚未发出的消息
+func (n *Network) waitIdle() bool {
+	deadline := time.Now().Add(settleTimeout)
+	for time.Now().Before(deadline) {
+		if atomic.LoadInt64(&n.inflight) > 0 {
+			time.Sleep(settlePoll)
+			continue
+		}
+		sends := atomic.LoadInt64(&n.sends)
+		time.Sleep(settleQuiet)
+		if atomic.LoadInt64(&n.inflight) == 0 && atomic.LoadInt64(&n.sends) == sends {
+			return true
+		}
+	}
+	n.log.Warn("simulation: wait network idle timeout", "inflight", atomic.LoadInt64(&n.inflight))
+	return false
+}
+
+func (n *Network) ack() {
+	atomic.AddInt64(&n.inflight, -1)
+}
+
+// Endpoint 节点接入仿真网络的端点，实现了network.Network接口
+// 通过channel订阅消息的模块需要在处理完消息后调用Ack，仿真网络据此判断消息是否处理完毕
+type Endpoint struct {
+	net     *Network
+	address string
+	ctx     *nctx.NetCtx
+
+	mutex       sync.Mutex
+	subscribers map[pb.XuperMessage_MessageType][]*subscriber
+}
+
+var _ network.Network = (*Endpoint)(nil)
+var _ network.MessageAcker = (*Endpoint)(nil)
+
+func (e *Endpoint) Start() {}
+
+func (e *Endpoint) Stop() {}
+
+// SendMessage 发送消息，未指定Accounts时广播给全部其他节点
+func (e *Endpoint) SendMessage(_ xctx.XContext, msg *pb.XuperMessage, opts ...p2p.OptionFunc) error {
+	if msg.GetHeader().GetFrom() == "" {
+		msg.Header.From = e.address
+	}
+	opt := p2p.Apply(opts)
+	e.net.send(e.address, opt.Accounts, msg)
+	return nil
+}
+
+// SendMessageWithResponse 仿真网络中所有消息都是异步投递的，不支持同步请求
+func (e *Endpoint) SendMessageWithResponse(xctx.XContext, *pb.XuperMessage,
+	...p2p.OptionFunc) ([]*pb.XuperMessage, error) {
+	return nil, ErrUnsupported
+}
+
+func (e *Endpoint) NewSubscriber(typ pb.XuperMessage_MessageType, v interface{},
+	opts ...p2p.SubscriberOption) p2p.Subscriber {
+	inner := p2p.NewSubscriber(e.ctx, typ, v, opts...)
+	if inner == nil {
+		return nil
+	}
+	s := &subscriber{
+		Subscriber: inner,
+	}
+	if ch, ok := v.(chan *pb.XuperMessage); ok {
+		s.channel = ch
+	}
+	return s
+}
+
+func (e *Endpoint) Register(sub p2p.Subscriber) error {
+	s, ok := sub.(*subscriber)
+	if !ok {
+		return ErrInvalidSubscriber
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for _, v := range e.subscribers[s.GetMessageType()] {
+		if v == s {
+			return ErrSubscriberExists
+		}
+	}
+	e.subscribers[s.GetMessageType()] = append(e.subscribers[s.GetMessageType()], s)
+	return nil
+}
+
+func (e *Endpoint) UnRegister(sub p2p.Subscriber) error {
+	s, ok := sub.(*subscriber)
+	if !ok {
+		return ErrInvalidSubscriber
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	list := e.subscribers[s.GetMessageType()]
+	for i, v := range list {
+		if v == s {
+			e.subscribers[s.GetMessageType()] = append(list[:i:i], list[i+1:]...)
+			return nil
+		}
+	}
+	return ErrSubscriberAbsent
+}
+
+func (e *Endpoint) Context() *nctx.NetCtx {
+	return e.ctx
+}
+
+func (e *Endpoint) PeerInfo() pb.PeerInfo {
+	return pb.PeerInfo{
+		Id:      e.address,
+		Address: e.address,
+		Account: e.address,
+	}
+}
+
+// Ack 订阅者处理完一条通过channel投递的消息
+func (e *Endpoint) Ack(*pb.XuperMessage) {
+	e.net.ack()
+}
+
+func (e *Endpoint) deliver(msg *pb.XuperMessage) {
+	e.mutex.Lock()
+	subs := append([]*subscriber(nil), e.subscribers[msg.GetHeader().GetType()]...)
+	e.mutex.Unlock()
+
+	for _, s := range subs {
+		if !s.Match(msg) {
+			continue
+		}
+		if s.channel == nil {
+			ctx := &xctx.BaseCtx{XLog: e.net.log, Timer: timer.NewXTimer()}
+			s.HandleMessage(ctx, msg, &stream{endpoint: e, to: msg.GetHeader().GetFrom()})
+			continue
+		}
+		atomic.AddInt64(&e.net.inflight, 1)
+		select {
+		case s.channel <- msg:
+		default:
+			// 与p2p订阅者一致，channel阻塞时丢弃消息
+			atomic.AddInt64(&e.net.inflight, -1)
+			e.net.log.Warn("simulation: discard message because channel block", "to", e.address,
+				"type", msg.GetHeader().GetType())
+		}
+	}
+}
+
+// subscriber 包装p2p订阅者，记录订阅的channel以便统计消息处理进度
+type subscriber struct {
+	p2p.Subscriber
+	channel chan *pb.XuperMessage
+}
+
+// stream 将同步处理函数的响应作为普通消息异步发回请求方
+type stream struct {
+	endpoint *Endpoint
+	to       string
+}
+
+func (s *stream) Send(msg *pb.XuperMessage) error {
+	return s.endpoint.SendMessage(nil, msg, p2p.WithAccounts([]string{s.to}))
+}
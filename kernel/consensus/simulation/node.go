@@ -0,0 +1,313 @@
+package simulation
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/consensus"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/timer"
+	"github.com/xuperchain/xupercore/lib/utils"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+// inboxSize 节点区块消息channel的缓冲大小
+const inboxSize = 1024
+
+var errKernMethodNotFound = errors.New("kernel method not found")
+
+// NodeStats 节点出块和验块统计
+type NodeStats struct {
+	// Mined 本节点生产的区块数
+	Mined int
+	// Accepted 通过验证写入账本的其他节点区块数
+	Accepted int
+	// Rejected CheckMinerMatch未通过的区块数
+	Rejected int
+}
+
+// Node 仿真节点，持有独立的账本、时钟和可插拔共识实例
+// 节点按照miner模块的流程出块和验块，区块通过仿真网络广播，缺少父区块时向发送方逐个补齐
+type Node struct {
+	Index     int
+	Address   string
+	Clock     *NodeClock
+	Ledger    *Ledger
+	Consensus consensus.PluggableConsensusInterface
+
+	key      *xaddress.Address
+	bcName   string
+	endpoint *Endpoint
+	log      logs.Logger
+	inbox    chan *pb.XuperMessage
+	// orphans 缺少父区块的区块，按父区块id索引
+	orphans map[string][]*Block
+	crashed bool
+	stats   NodeStats
+}
+
+func newNode(index int, key *xaddress.Address, bcName string, crypto cryptoBase.CryptoClient,
+	clock *NodeClock, ledger *Ledger, endpoint *Endpoint, log logs.Logger) (*Node, error) {
+	n := &Node{
+		Index:    index,
+		Address:  key.Address,
+		Clock:    clock,
+		Ledger:   ledger,
+		key:      key,
+		bcName:   bcName,
+		endpoint: endpoint,
+		log:      log,
+		inbox:    make(chan *pb.XuperMessage, inboxSize),
+		orphans:  make(map[string][]*Block),
+	}
+	for _, typ := range []pb.XuperMessage_MessageType{pb.XuperMessage_SENDBLOCK, pb.XuperMessage_GET_BLOCK} {
+		sub := endpoint.NewSubscriber(typ, n.inbox, p2p.WithFilterBCName(bcName))
+		if err := endpoint.Register(sub); err != nil {
+			return nil, err
+		}
+	}
+	cCtx := cctx.ConsensusCtx{
+		BaseCtx: xctx.BaseCtx{
+			XLog:  log,
+			Timer: timer.NewXTimer(),
+		},
+		BcName:  bcName,
+		Address: (*cctx.Address)(key),
+		Crypto:  crypto,
+		Contract: &contractManager{
+			registry: &kernRegistry{methods: make(map[string]contract.KernMethod)},
+		},
+		Ledger:  ledger,
+		Network: endpoint,
+		Clock:   clock,
+	}
+	cons, err := consensus.NewPluggableConsensus(cCtx)
+	if err != nil {
+		return nil, err
+	}
+	n.Consensus = cons
+	return n, nil
+}
+
+// Stats 返回节点统计
+func (n *Node) Stats() NodeStats {
+	return n.stats
+}
+
+// Crashed 节点是否处于宕机状态
+func (n *Node) Crashed() bool {
+	return n.crashed
+}
+
+// step 节点在一个时间片内的行为，与miner模块一致：先竞争出块权，是矿工时打包区块并广播
+func (n *Node) step() {
+	if n.crashed {
+		return
+	}
+	isMiner, _, err := n.Consensus.CompeteMaster(n.Ledger.TipHeight() + 1)
+	if err != nil || !isMiner {
+		return
+	}
+	if err := n.mine(); err != nil {
+		n.log.Warn("simulation: mining failed", "node", n.Index, "err", err)
+	}
+}
+
+// mine 共识挖矿前处理(可能回滚账本)、打包、CalculateBlock、写入账本并确认，最后广播区块
+func (n *Node) mine() error {
+	height := n.Ledger.TipHeight() + 1
+	now := n.Clock.Now()
+	truncateTarget, extData, err := n.Consensus.ProcessBeforeMiner(height, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	if truncateTarget != nil {
+		if err := n.Ledger.Truncate(truncateTarget); err != nil {
+			return err
+		}
+		height = n.Ledger.TipHeight() + 1
+	}
+	block := &Block{
+		PreHash:          n.Ledger.tip().Blockid,
+		Proposer:         n.Address,
+		Height:           height,
+		Timestamp:        now.UnixNano(),
+		PublicKey:        n.key.PublicKeyStr,
+		ConsensusStorage: extData,
+	}
+	block.Blockid, _ = block.MakeBlockId()
+	if err := n.Consensus.CalculateBlock(block); err != nil {
+		return err
+	}
+	if _, err := n.Ledger.Append(block); err != nil {
+		return err
+	}
+	if err := n.Consensus.ProcessConfirmBlock(block); err != nil {
+		n.log.Warn("simulation: process confirm block failed", "node", n.Index, "err", err)
+	}
+	n.Consensus.SwitchConsensus(block.Height)
+	n.stats.Mined++
+	return n.sendBlock(block, nil)
+}
+
+// processInbox 处理channel中全部区块消息，返回处理的消息数
+func (n *Node) processInbox() int {
+	count := 0
+	for {
+		select {
+		case msg := <-n.inbox:
+			n.handleMessage(msg)
+			n.endpoint.Ack(msg)
+			count++
+		default:
+			return count
+		}
+	}
+}
+
+func (n *Node) handleMessage(msg *pb.XuperMessage) {
+	from := msg.GetHeader().GetFrom()
+	switch msg.GetHeader().GetType() {
+	case pb.XuperMessage_SENDBLOCK:
+		block := &Block{}
+		if err := json.Unmarshal(msg.GetData().GetMsgInfo(), block); err != nil {
+			n.log.Warn("simulation: unmarshal block failed", "node", n.Index, "err", err)
+			return
+		}
+		n.receiveBlock(block, from)
+	case pb.XuperMessage_GET_BLOCK:
+		block, err := n.Ledger.QueryBlock(msg.GetData().GetMsgInfo())
+		if err != nil {
+			return
+		}
+		n.sendBlock(block, []string{from})
+	}
+}
+
+// receiveBlock 父区块已知时验证并写入区块，否则暂存并向发送方请求父区块
+func (n *Node) receiveBlock(block *Block, from string) {
+	if n.Ledger.HasBlock(block.Blockid) {
+		return
+	}
+	if !n.Ledger.HasBlock(block.PreHash) {
+		key := utils.F(block.PreHash)
+		n.orphans[key] = append(n.orphans[key], block)
+		n.requestBlock(block.PreHash, from)
+		return
+	}
+	queue := []*Block{block}
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		if n.Ledger.HasBlock(b.Blockid) || !n.acceptBlock(b) {
+			continue
+		}
+		key := utils.F(b.Blockid)
+		queue = append(queue, n.orphans[key]...)
+		delete(n.orphans, key)
+	}
+}
+
+// acceptBlock 与同步区块的流程一致：CheckMinerMatch、写入账本、ProcessConfirmBlock
+func (n *Node) acceptBlock(block *Block) bool {
+	ctx := &xctx.BaseCtx{XLog: n.log, Timer: timer.NewXTimer()}
+	if ok, err := n.Consensus.CheckMinerMatch(ctx, block); !ok {
+		n.log.Warn("simulation: check miner match failed", "node", n.Index, "height", block.Height,
+			"blockId", utils.F(block.Blockid), "err", err)
+		n.stats.Rejected++
+		return false
+	}
+	if _, err := n.Ledger.Append(block); err != nil {
+		return false
+	}
+	if err := n.Consensus.ProcessConfirmBlock(block); err != nil {
+		n.log.Warn("simulation: process confirm block failed", "node", n.Index, "err", err)
+	}
+	n.Consensus.SwitchConsensus(block.Height)
+	n.stats.Accepted++
+	return true
+}
+
+func (n *Node) sendBlock(block *Block, to []string) error {
+	data, err := json.Marshal(block)
+	if err != nil {
+		return err
+	}
+	return n.send(pb.XuperMessage_SENDBLOCK, data, to)
+}
+
+func (n *Node) requestBlock(blockid []byte, from string) {
+	if from == "" {
+		return
+	}
+	n.send(pb.XuperMessage_GET_BLOCK, blockid, []string{from})
+}
+
+func (n *Node) send(typ pb.XuperMessage_MessageType, data []byte, to []string) error {
+	msg := p2p.NewMessage(typ, nil, p2p.WithBCName(n.bcName))
+	msg.Data.MsgInfo = data
+	msg.Header.DataCheckSum = p2p.Checksum(msg)
+	return n.endpoint.SendMessage(nil, msg, p2p.WithAccounts(to))
+}
+
+// committed 返回主干上深度不小于depth的区块，即节点视角下已经提交的区块
+func (n *Node) committed(depth int64) []*Block {
+	trunk := n.Ledger.Trunk()
+	end := int64(len(trunk)) - depth
+	if end <= 1 {
+		return nil
+	}
+	return trunk[1:end]
+}
+
+// contractManager 仿真不执行合约，只提供共识注册kernel方法需要的registry
+type contractManager struct {
+	registry *kernRegistry
+}
+
+func (m *contractManager) NewContext(*contract.ContextConfig) (contract.Context, error) {
+	return nil, ErrUnsupported
+}
+
+func (m *contractManager) NewStateSandbox(*contract.SandboxConfig) (contract.StateSandbox, error) {
+	return nil, ErrUnsupported
+}
+
+func (m *contractManager) GetKernRegistry() contract.KernRegistry {
+	return m.registry
+}
+
+type kernRegistry struct {
+	mutex   sync.Mutex
+	methods map[string]contract.KernMethod
+}
+
+func (r *kernRegistry) RegisterKernMethod(ctract, method string, handler contract.KernMethod) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.methods[ctract+"."+method] = handler
+}
+
+func (r *kernRegistry) UnregisterKernMethod(ctract, method string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.methods, ctract+"."+method)
+}
+
+func (r *kernRegistry) RegisterShortcut(oldmethod, contract, method string) {}
+
+func (r *kernRegistry) GetKernMethod(ctract, method string) (contract.KernMethod, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if f, ok := r.methods[ctract+"."+method]; ok {
+		return f, nil
+	}
+	return nil, errKernMethodNotFound
+}
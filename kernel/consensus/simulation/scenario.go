@@ -0,0 +1,86 @@
+package simulation
+
+import (
+	"fmt"
+	"time"
+)
+
+// Fault 在创世之后At时刻注入的故障
+type Fault struct {
+	At    time.Duration
+	Apply func(*Simulation)
+}
+
+// CrashAt 在at时刻使节点i宕机
+func CrashAt(at time.Duration, i int) Fault {
+	return Fault{At: at, Apply: func(s *Simulation) { s.Crash(i) }}
+}
+
+// RecoverAt 在at时刻恢复宕机的节点i
+func RecoverAt(at time.Duration, i int) Fault {
+	return Fault{At: at, Apply: func(s *Simulation) { s.Recover(i) }}
+}
+
+// PartitionAt 在at时刻按节点序号划分网络
+func PartitionAt(at time.Duration, groups ...[]int) Fault {
+	return Fault{At: at, Apply: func(s *Simulation) { s.Partition(groups...) }}
+}
+
+// HealAt 在at时刻撤销网络分区
+func HealAt(at time.Duration) Fault {
+	return Fault{At: at, Apply: func(s *Simulation) { s.Heal() }}
+}
+
+// SkewAt 在at时刻设置节点i的时钟偏移
+func SkewAt(at time.Duration, i int, skew time.Duration) Fault {
+	return Fault{At: at, Apply: func(s *Simulation) { s.nodes[i].Clock.SetSkew(skew) }}
+}
+
+// Scenario 仿真场景：GST(全局稳定时间)之前按时间注入故障，GST时撤销网络分区并停止丢包，
+// 之后网络恢复同步，检查整个过程中没有冲突的提交，并且GST之后每个未宕机节点都能继续提交区块
+type Scenario struct {
+	Name   string
+	Config Config
+	// Faults GST之前注入的故障
+	Faults []Fault
+	// GST 相对创世的全局稳定时间
+	GST time.Duration
+	// Window GST之后继续运行的时长
+	Window time.Duration
+	// MinProgress GST之后每个未宕机节点至少需要新提交的区块数，为0时不检查活性
+	MinProgress int64
+}
+
+// Run 以每个种子运行一次场景，返回第一个违反安全性或活性的错误
+func (sc *Scenario) Run(seeds ...int64) error {
+	for _, seed := range seeds {
+		if err := sc.runSeed(seed); err != nil {
+			return fmt.Errorf("scenario %s seed %d: %w", sc.Name, seed, err)
+		}
+	}
+	return nil
+}
+
+func (sc *Scenario) runSeed(seed int64) error {
+	cfg := sc.Config
+	cfg.Seed = seed
+	s, err := New(cfg)
+	if err != nil {
+		return err
+	}
+	for _, f := range sc.Faults {
+		s.At(f.At, f.Apply)
+	}
+	s.Run(sc.GST - s.Elapsed())
+	s.Heal()
+	s.network.SetDropRate(0)
+	base := s.MinCommittedHeight()
+	s.Run(sc.Window)
+	if err := s.CheckSafety(); err != nil {
+		return err
+	}
+	if sc.MinProgress > 0 {
+		return s.CheckProgress(base + sc.MinProgress)
+	}
+	return nil
+}
@@ -0,0 +1,353 @@
+// Package simulation 多节点共识仿真
+// 在一个进程内运行多个使用真实共识实现(tdpos、xpoa、chained-bft等)的节点，节点之间通过内存网络通信，
+// 全部节点共享一个虚拟时钟。调度协程按时间片驱动各节点出块，并在推进虚拟时间之前等待网络中的消息处理完毕，
+// 从而可以在给定种子下复现消息时延、丢包、网络分区和节点宕机等场景，并检查安全性和活性
+package simulation
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	"github.com/xuperchain/xupercore/kernel/consensus/def"
+	"github.com/xuperchain/xupercore/lib/crypto/client/base"
+	xchainCrypto "github.com/xuperchain/xupercore/lib/crypto/client/xchain"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+const (
+	// DefaultBcName 仿真链名
+	DefaultBcName = "xuper"
+	// DefaultFinalityDepth 默认区块之上有3个区块时视为已提交，与chained-bft的三链提交规则一致
+	DefaultFinalityDepth = 3
+	// maxSettleRounds 单个虚拟时刻内投递消息的最大轮数，防止消息风暴导致调度无法推进
+	maxSettleRounds = 1000
+	// tickOffset 节点在时间片开始之后多久尝试出块，避免恰好落在时间片边界上
+	tickOffset = time.Millisecond
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid simulation config")
+	ErrUnsafe        = errors.New("conflicting commits")
+	ErrNoProgress    = errors.New("no progress")
+)
+
+// DefaultGenesis 默认创世时间，对齐到整秒便于计算时间片
+var DefaultGenesis = time.Unix(1609459200, 0)
+
+// Config 仿真配置
+type Config struct {
+	// Consensus 共识名称，对应的共识需要已经注册到kernel/consensus
+	Consensus string
+	// ConsensusConfig 根据全部节点地址和创世时间生成创世块中的共识配置
+	ConsensusConfig func(addresses []string, genesis time.Time) string
+	// Nodes 节点个数
+	Nodes int
+	// Seed 随机种子，决定节点密钥以及每条消息的时延和是否丢包
+	Seed int64
+	// Period 节点尝试出块的间隔，一般等于共识配置中的出块间隔
+	Period time.Duration
+	// Genesis 创世时间，为零值时使用DefaultGenesis
+	Genesis time.Time
+	// MinDelay, MaxDelay 消息投递时延区间
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// DropRate 丢包率
+	DropRate float64
+	// FinalityDepth 区块之上有多少个区块时视为已提交，为0时使用DefaultFinalityDepth
+	FinalityDepth int64
+	// Logger 为nil时不输出日志
+	Logger logs.Logger
+}
+
+type action struct {
+	at time.Time
+	f  func(*Simulation)
+}
+
+// Simulation 一次多节点仿真
+type Simulation struct {
+	config  Config
+	clock   *Clock
+	network *Network
+	nodes   []*Node
+
+	actions  []*action
+	nextTick time.Time
+	// commits 各高度上最先被提交的区块，用于检查安全性
+	commits    map[int64]string
+	violations []error
+}
+
+// New 新建仿真，生成节点密钥、创世块，并在每个节点上创建可插拔共识实例
+func New(cfg Config) (*Simulation, error) {
+	if cfg.Nodes <= 0 || cfg.Consensus == "" || cfg.ConsensusConfig == nil || cfg.Period <= 0 {
+		return nil, ErrInvalidConfig
+	}
+	if cfg.Genesis.IsZero() {
+		cfg.Genesis = DefaultGenesis
+	}
+	if cfg.FinalityDepth <= 0 {
+		cfg.FinalityDepth = DefaultFinalityDepth
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = &logs.LogFitter{}
+	}
+	s := &Simulation{
+		config:   cfg,
+		clock:    NewClock(cfg.Genesis),
+		nextTick: cfg.Genesis.Add(cfg.Period + tickOffset),
+		commits:  make(map[int64]string),
+	}
+	s.network = NewNetwork(s.clock, cfg.Seed, cfg.Logger)
+	s.network.SetDelay(cfg.MinDelay, cfg.MaxDelay)
+	s.network.SetDropRate(cfg.DropRate)
+
+	crypto := xchainCrypto.GetInstance()
+	keys := make([]*xaddress.Address, 0, cfg.Nodes)
+	addresses := make([]string, 0, cfg.Nodes)
+	for i := 0; i < cfg.Nodes; i++ {
+		key, err := generateKey(crypto, cfg.Seed, i)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		addresses = append(addresses, key.Address)
+	}
+	consensusConf, err := json.Marshal(def.ConsensusConfig{
+		ConsensusName: cfg.Consensus,
+		Config:        cfg.ConsensusConfig(addresses, cfg.Genesis),
+	})
+	if err != nil {
+		return nil, err
+	}
+	genesis := &Block{
+		Timestamp: cfg.Genesis.UnixNano(),
+	}
+	genesis.Blockid, _ = genesis.MakeBlockId()
+	for i, key := range keys {
+		node, err := newNode(i, key, DefaultBcName, crypto, newNodeClock(s.clock),
+			NewLedger(genesis, consensusConf), s.network.Join(key.Address), cfg.Logger)
+		if err != nil {
+			return nil, fmt.Errorf("create node %d error: %v", i, err)
+		}
+		s.nodes = append(s.nodes, node)
+	}
+	s.settle()
+	return s, nil
+}
+
+// generateKey 由种子和节点序号确定性地生成节点密钥
+func generateKey(crypto base.CryptoClient, seed int64, index int) (*xaddress.Address, error) {
+	entropy := sha256.Sum256([]byte(fmt.Sprintf("simulation/%d/%d", seed, index)))
+	privateKey, err := crypto.GenerateKeyBySeed(entropy[:])
+	if err != nil {
+		return nil, err
+	}
+	address, err := crypto.GetAddressFromPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	privateKeyStr, err := crypto.GetEcdsaPrivateKeyJsonFormatStr(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyStr, err := crypto.GetEcdsaPublicKeyJsonFormatStr(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &xaddress.Address{
+		Address:       address,
+		PrivateKey:    privateKey,
+		PrivateKeyStr: privateKeyStr,
+		PublicKey:     &privateKey.PublicKey,
+		PublicKeyStr:  publicKeyStr,
+	}, nil
+}
+
+// Clock 返回仿真时钟
+func (s *Simulation) Clock() *Clock {
+	return s.clock
+}
+
+// Network 返回仿真网络
+func (s *Simulation) Network() *Network {
+	return s.network
+}
+
+// Nodes 返回全部节点
+func (s *Simulation) Nodes() []*Node {
+	return s.nodes
+}
+
+// Node 返回第i个节点
+func (s *Simulation) Node(i int) *Node {
+	return s.nodes[i]
+}
+
+// Elapsed 返回自创世以来经过的虚拟时间
+func (s *Simulation) Elapsed() time.Duration {
+	return s.clock.Now().Sub(s.config.Genesis)
+}
+
+// At 在创世之后offset时刻执行f，用于按时间注入故障
+func (s *Simulation) At(offset time.Duration, f func(*Simulation)) {
+	s.actions = append(s.actions, &action{
+		at: s.config.Genesis.Add(offset),
+		f:  f,
+	})
+	sort.SliceStable(s.actions, func(i, j int) bool {
+		return s.actions[i].at.Before(s.actions[j].at)
+	})
+}
+
+// Run 将仿真推进d
+// 每一步推进到下一个最早的事件：出块时间片、消息投递或故障注入，处理完该时刻的全部消息后再继续推进
+func (s *Simulation) Run(d time.Duration) {
+	end := s.clock.Now().Add(d)
+	for {
+		next := s.nextTick
+		if t, ok := s.network.nextDelivery(); ok && t.Before(next) {
+			next = t
+		}
+		if len(s.actions) > 0 && s.actions[0].at.Before(next) {
+			next = s.actions[0].at
+		}
+		if next.After(end) {
+			s.clock.advanceTo(end)
+			s.settle()
+			return
+		}
+		s.clock.advanceTo(next)
+		for len(s.actions) > 0 && !s.actions[0].at.After(s.clock.Now()) {
+			a := s.actions[0]
+			s.actions = s.actions[1:]
+			a.f(s)
+		}
+		s.settle()
+		if !s.nextTick.After(s.clock.Now()) {
+			for _, node := range s.nodes {
+				node.step()
+				s.settle()
+			}
+			for !s.nextTick.After(s.clock.Now()) {
+				s.nextTick = s.nextTick.Add(s.config.Period)
+			}
+		}
+		s.recordCommits()
+	}
+}
+
+// settle 反复投递当前时刻到期的消息，直到网络静默且没有新的到期消息
+func (s *Simulation) settle() {
+	for i := 0; i < maxSettleRounds; i++ {
+		s.network.waitIdle()
+		delivered := s.network.deliverDue()
+		processed := 0
+		for _, node := range s.nodes {
+			processed += node.processInbox()
+		}
+		if delivered == 0 && processed == 0 {
+			return
+		}
+	}
+}
+
+// Crash 第i个节点宕机，宕机期间既不出块也不收发消息，恢复后保留宕机前的状态
+func (s *Simulation) Crash(i int) {
+	s.nodes[i].crashed = true
+	s.network.Crash(s.nodes[i].Address)
+}
+
+// Recover 第i个节点从宕机中恢复
+func (s *Simulation) Recover(i int) {
+	s.nodes[i].crashed = false
+	s.network.Recover(s.nodes[i].Address)
+}
+
+// Partition 按节点序号将网络划分为若干分区
+func (s *Simulation) Partition(groups ...[]int) {
+	partition := make([][]string, 0, len(groups))
+	for _, group := range groups {
+		addresses := make([]string, 0, len(group))
+		for _, i := range group {
+			addresses = append(addresses, s.nodes[i].Address)
+		}
+		partition = append(partition, addresses)
+	}
+	s.network.Partition(partition...)
+}
+
+// Heal 撤销网络分区
+func (s *Simulation) Heal() {
+	s.network.Heal()
+}
+
+// recordCommits 记录各节点新提交的区块，同一高度提交了不同区块或已提交的区块被回滚时记为违反安全性
+func (s *Simulation) recordCommits() {
+	for _, node := range s.nodes {
+		for _, block := range node.committed(s.config.FinalityDepth) {
+			id := utils.F(block.Blockid)
+			first, ok := s.commits[block.Height]
+			if !ok {
+				s.commits[block.Height] = id
+				continue
+			}
+			if first != id {
+				s.violations = append(s.violations, fmt.Errorf("%w: node %d committed %s at height %d, %s was committed before",
+					ErrUnsafe, node.Index, id, block.Height, first))
+				s.commits[block.Height] = id
+			}
+		}
+	}
+}
+
+// CheckSafety 检查仿真过程中是否出现冲突的提交
+func (s *Simulation) CheckSafety() error {
+	s.recordCommits()
+	if len(s.violations) > 0 {
+		return s.violations[0]
+	}
+	return nil
+}
+
+// CommittedHeight 返回第i个节点已提交的最大高度
+func (s *Simulation) CommittedHeight(i int) int64 {
+	committed := s.nodes[i].committed(s.config.FinalityDepth)
+	if len(committed) == 0 {
+		return 0
+	}
+	return committed[len(committed)-1].Height
+}
+
+// MinCommittedHeight 返回全部未宕机节点中最小的已提交高度
+func (s *Simulation) MinCommittedHeight() int64 {
+	min := int64(-1)
+	for i, node := range s.nodes {
+		if node.crashed {
+			continue
+		}
+		if h := s.CommittedHeight(i); min < 0 || h < min {
+			min = h
+		}
+	}
+	return min
+}
+
+// CheckProgress 检查全部未宕机节点已提交的高度都不低于height
+func (s *Simulation) CheckProgress(height int64) error {
+	for i, node := range s.nodes {
+		if node.crashed {
+			continue
+		}
+		if h := s.CommittedHeight(i); h < height {
+			return fmt.Errorf("%w: node %d committed height %d, want at least %d", ErrNoProgress, i, h, height)
+		}
+	}
+	return nil
+}
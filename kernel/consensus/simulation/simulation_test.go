@@ -0,0 +1,222 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	_ "github.com/xuperchain/xupercore/bcs/consensus/tdpos"
+	_ "github.com/xuperchain/xupercore/bcs/consensus/xpoa"
+)
+
+const testPeriod = time.Second
+
+var testSeeds = []int64{1, 2, 3}
+
+func xpoaConfig(enableBFT bool) func([]string, time.Time) string {
+	return func(addresses []string, _ time.Time) string {
+		cfg := map[string]interface{}{
+			"version":   "2",
+			"period":    testPeriod.Milliseconds(),
+			"block_num": 2,
+			"init_proposer": map[string]interface{}{
+				"address": addresses,
+			},
+		}
+		if enableBFT {
+			cfg["bft_config"] = map[string]interface{}{}
+		}
+		b, _ := json.Marshal(cfg)
+		return string(b)
+	}
+}
+
+func tdposConfig(enableBFT bool) func([]string, time.Time) string {
+	return func(addresses []string, genesis time.Time) string {
+		period := strconv.FormatInt(testPeriod.Milliseconds(), 10)
+		cfg := map[string]interface{}{
+			"version":            "2",
+			"timestamp":          strconv.FormatInt(genesis.UnixNano(), 10),
+			"proposer_num":       strconv.Itoa(len(addresses)),
+			"period":             period,
+			"alternate_interval": period,
+			"term_interval":      period,
+			"block_num":          "2",
+			"vote_unit_price":    "1",
+			"init_proposer": map[string][]string{
+				"1": addresses,
+			},
+		}
+		if enableBFT {
+			cfg["bft_config"] = map[string]interface{}{}
+		}
+		b, _ := json.Marshal(cfg)
+		return string(b)
+	}
+}
+
+func testConfig(name string, conf func([]string, time.Time) string) Config {
+	return Config{
+		Consensus:       name,
+		ConsensusConfig: conf,
+		Nodes:           4,
+		Period:          testPeriod,
+		MinDelay:        10 * time.Millisecond,
+		MaxDelay:        200 * time.Millisecond,
+	}
+}
+
+func TestHealthyNetwork(t *testing.T) {
+	cases := map[string]Config{
+		"xpoa":      testConfig("xpoa", xpoaConfig(false)),
+		"xpoa-bft":  testConfig("xpoa", xpoaConfig(true)),
+		"tdpos":     testConfig("tdpos", tdposConfig(false)),
+		"tdpos-bft": testConfig("tdpos", tdposConfig(true)),
+	}
+	for name, cfg := range cases {
+		sc := &Scenario{
+			Name:        name,
+			Config:      cfg,
+			Window:      20 * testPeriod,
+			MinProgress: 10,
+		}
+		if err := sc.Run(testSeeds...); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestPartitionThenHeal(t *testing.T) {
+	cases := map[string]Config{
+		"xpoa-bft":  testConfig("xpoa", xpoaConfig(true)),
+		"tdpos-bft": testConfig("tdpos", tdposConfig(true)),
+	}
+	for name, cfg := range cases {
+		cfg.DropRate = 0.1
+		sc := &Scenario{
+			Name:   name,
+			Config: cfg,
+			Faults: []Fault{
+				PartitionAt(3*testPeriod, []int{0, 1}, []int{2, 3}),
+				SkewAt(4*testPeriod, 1, 50*time.Millisecond),
+			},
+			GST:         15 * testPeriod,
+			Window:      20 * testPeriod,
+			MinProgress: 5,
+		}
+		if err := sc.Run(testSeeds...); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestCrashFault(t *testing.T) {
+	cases := map[string]Config{
+		"xpoa":      testConfig("xpoa", xpoaConfig(false)),
+		"xpoa-bft":  testConfig("xpoa", xpoaConfig(true)),
+		"tdpos-bft": testConfig("tdpos", tdposConfig(true)),
+	}
+	for name, cfg := range cases {
+		sc := &Scenario{
+			Name:   name,
+			Config: cfg,
+			Faults: []Fault{
+				CrashAt(3*testPeriod, 3),
+				CrashAt(6*testPeriod, 2),
+				RecoverAt(10*testPeriod, 2),
+			},
+			GST:         10 * testPeriod,
+			Window:      20 * testPeriod,
+			MinProgress: 5,
+		}
+		if err := sc.Run(testSeeds...); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestUnsafeCommitDetected(t *testing.T) {
+	// 未开启chained-bft的xpoa在网络分区时两侧会各自出块，恢复后一侧的提交被回滚
+	sc := &Scenario{
+		Name:   "xpoa",
+		Config: testConfig("xpoa", xpoaConfig(false)),
+		Faults: []Fault{
+			PartitionAt(3*testPeriod, []int{0, 1}, []int{2, 3}),
+		},
+		GST:    15 * testPeriod,
+		Window: 10 * testPeriod,
+	}
+	if err := sc.Run(testSeeds[0]); !errors.Is(err, ErrUnsafe) {
+		t.Errorf("want conflicting commits, got %v", err)
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	run := func() [][]string {
+		cfg := testConfig("xpoa", xpoaConfig(true))
+		cfg.Seed = 7
+		cfg.DropRate = 0.1
+		s, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.At(3*testPeriod, func(s *Simulation) { s.Partition([]int{0, 1, 2}, []int{3}) })
+		s.At(8*testPeriod, func(s *Simulation) { s.Heal() })
+		s.Run(15 * testPeriod)
+		var trunks [][]string
+		for _, node := range s.Nodes() {
+			var ids []string
+			for _, block := range node.Ledger.Trunk() {
+				ids = append(ids, fmt.Sprintf("%x", block.Blockid))
+			}
+			trunks = append(trunks, ids)
+		}
+		return trunks
+	}
+	if a, b := run(), run(); !reflect.DeepEqual(a, b) {
+		t.Errorf("same seed produced different chains:\n%v\n%v", a, b)
+	}
+}
+
+func TestFinalizedBlock(t *testing.T) {
+	// chained-bft的commitQC：各节点的不可逆区块持续推进，位于自身主干上，并且不会出现冲突
+	cfg := testConfig("xpoa", xpoaConfig(true))
+	cfg.Seed = testSeeds[0]
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	finalized := make(map[int64]string)
+	for round := 0; round < 20; round++ {
+		s.Run(testPeriod)
+		for _, node := range s.Nodes() {
+			status, err := node.Consensus.GetConsensusStatus()
+			if err != nil {
+				t.Fatal(err)
+			}
+			height, blockid := status.GetFinalizedBlock()
+			trunk := node.Ledger.Trunk()
+			if height >= int64(len(trunk)) || !bytes.Equal(trunk[height].Blockid, blockid) {
+				t.Fatalf("node %d finalized block %x at height %d is not on trunk", node.Index, blockid, height)
+			}
+			for h := int64(1); h <= height; h++ {
+				id := fmt.Sprintf("%x", trunk[h].Blockid)
+				if first, ok := finalized[h]; ok && first != id {
+					t.Fatalf("node %d finalized %s at height %d, %s was finalized before", node.Index, id, h, first)
+				}
+				finalized[h] = id
+			}
+		}
+	}
+	for i := range s.Nodes() {
+		status, _ := s.Node(i).Consensus.GetConsensusStatus()
+		if height, _ := status.GetFinalizedBlock(); height < 10 {
+			t.Errorf("node %d finalized height %d, want at least 10", i, height)
+		}
+	}
+}
@@ -59,6 +59,12 @@ type Context struct {
 	ReadFromCache bool
 
 	ChainName string
+
+	// 用于按需加载 wasm 和 native 合约的接口描述，其他类型或者没有接口描述的合约为 nil
+	codeProvider      ContractCodeProvider
+	contractInterface *ContractInterface
+	interfaceErr      error
+	interfaceLoaded   bool
 }
 
 // DiskUsed returns the bytes written to xmodel
@@ -91,6 +97,30 @@ func (c *Context) ResourceUsed() contract.Limits {
 	return total
 }
 
+// ContractInterface returns the interface description of contract,
+// nil if the contract does not have one
+func (c *Context) ContractInterface() (*ContractInterface, error) {
+	if c.codeProvider == nil || c.interfaceLoaded {
+		return c.contractInterface, c.interfaceErr
+	}
+	// 每个 Context 只加载一次，解析失败同样缓存结果
+	c.interfaceLoaded = true
+	var buf []byte
+	var err error
+	// 与合约代码的读取方式保持一致
+	if c.ReadFromCache {
+		buf, err = c.codeProvider.GetContractAbiFromCache(c.ContractName)
+	} else {
+		buf, err = c.codeProvider.GetContractAbi(c.ContractName)
+	}
+	// 没有接口描述的合约按原来的方式调用
+	if err != nil {
+		return nil, nil
+	}
+	c.contractInterface, c.interfaceErr = ParseContractInterface(buf)
+	return c.contractInterface, c.interfaceErr
+}
+
 // ContextManager 用于管理产生和销毁Context
 type ContextManager struct {
 	// 保护如下两个变量
@@ -35,6 +35,23 @@ func (v *vmContextImpl) Invoke(method string, args map[string][]byte) (*contract
 		return nil, errors.New("invalid contract method " + method)
 	}
 
+	// 参数使用 json 编码时根据合约接口描述编码参数和解码返回值
+	var ci *ContractInterface
+	if string(args[argJSONEncoded]) == "true" {
+		var err error
+		ci, err = v.ctx.ContractInterface()
+		if err != nil {
+			return nil, err
+		}
+		if ci == nil {
+			return nil, fmt.Errorf("contract %s has no interface description", v.ctx.ContractName)
+		}
+		args, err = ci.EncodeArgs(method, args[argInput])
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	v.ctx.Method = method
 	v.ctx.Args = args
 	err := v.instance.Exec()
@@ -53,10 +70,18 @@ func (v *vmContextImpl) Invoke(method string, args map[string][]byte) (*contract
 		}
 	}
 
+	body := v.ctx.Output.GetBody()
+	if ci != nil && v.ctx.Output.GetStatus() < 400 {
+		body, err = ci.DecodeResponse(method, body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &contract.Response{
 		Status:  int(v.ctx.Output.GetStatus()),
 		Message: v.ctx.Output.GetMessage(),
-		Body:    v.ctx.Output.GetBody(),
+		Body:    body,
 	}, nil
 }
 
@@ -0,0 +1,345 @@
+package bridge
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// 调用参数中 argJSONEncoded 为 true 时，参数以 json 对象的形式放在 argInput 中，
+	// 由合约接口描述编码后传给合约，执行结果也按照接口描述解码为 json，与 evm 合约的调用方式一致
+	argJSONEncoded = "jsonEncoded"
+	argInput       = "input"
+)
+
+// 合约接口描述支持的参数类型
+const (
+	paramTypeString = "string"
+	paramTypeBytes  = "bytes"
+	paramTypeBool   = "bool"
+	paramTypeInt64  = "int64"
+	paramTypeUint64 = "uint64"
+	paramTypeBigInt = "bigint"
+)
+
+// ContractInterface 是 wasm 和 native 合约可选的接口描述，和合约代码一起保存在 contractAbiKey 下
+//
+// 合约看到的参数和返回值仍然是字节数组，每种类型的编码如下：
+// string 和 bytes 为原始字节，bool 为 "true" 或 "false"，int64、uint64 和 bigint 为十进制字符串。
+// 事件的内容以及多个返回值编码为 json 对象，值为上述编码的字符串，其中 bytes 使用 hex 编码。
+type ContractInterface struct {
+	Methods []*MethodDesc `json:"methods"`
+	Events  []*EventDesc  `json:"events"`
+}
+
+// MethodDesc describes a contract method
+type MethodDesc struct {
+	Name    string       `json:"name"`
+	Inputs  []*ParamDesc `json:"inputs"`
+	Outputs []*ParamDesc `json:"outputs"`
+}
+
+// EventDesc describes a contract event
+type EventDesc struct {
+	Name   string       `json:"name"`
+	Inputs []*ParamDesc `json:"inputs"`
+}
+
+// ParamDesc describes a typed parameter
+type ParamDesc struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ParseContractInterface parses and validates contract interface description
+func ParseContractInterface(buf []byte) (*ContractInterface, error) {
+	ci := new(ContractInterface)
+	if err := json.Unmarshal(buf, ci); err != nil {
+		return nil, fmt.Errorf("bad contract interface:%s", err)
+	}
+	methods := make(map[string]bool)
+	for _, method := range ci.Methods {
+		if method == nil || method.Name == "" {
+			return nil, errors.New("bad contract interface:empty method name")
+		}
+		if methods[method.Name] {
+			return nil, fmt.Errorf("bad contract interface:duplicated method %s", method.Name)
+		}
+		methods[method.Name] = true
+		if err := validateParams(method.Inputs); err != nil {
+			return nil, fmt.Errorf("bad contract interface:method %s inputs:%s", method.Name, err)
+		}
+		if err := validateParams(method.Outputs); err != nil {
+			return nil, fmt.Errorf("bad contract interface:method %s outputs:%s", method.Name, err)
+		}
+	}
+	events := make(map[string]bool)
+	for _, event := range ci.Events {
+		if event == nil || event.Name == "" {
+			return nil, errors.New("bad contract interface:empty event name")
+		}
+		if events[event.Name] {
+			return nil, fmt.Errorf("bad contract interface:duplicated event %s", event.Name)
+		}
+		events[event.Name] = true
+		if err := validateParams(event.Inputs); err != nil {
+			return nil, fmt.Errorf("bad contract interface:event %s inputs:%s", event.Name, err)
+		}
+	}
+	return ci, nil
+}
+
+func validateParams(params []*ParamDesc) error {
+	names := make(map[string]bool)
+	for _, param := range params {
+		if param == nil || param.Name == "" {
+			return errors.New("empty param name")
+		}
+		if names[param.Name] {
+			return fmt.Errorf("duplicated param %s", param.Name)
+		}
+		names[param.Name] = true
+		switch param.Type {
+		case paramTypeString, paramTypeBytes, paramTypeBool,
+			paramTypeInt64, paramTypeUint64, paramTypeBigInt:
+		default:
+			return fmt.Errorf("unknown type %s of param %s", param.Type, param.Name)
+		}
+	}
+	return nil
+}
+
+// Method returns the description of method, nil if not found
+func (ci *ContractInterface) Method(name string) *MethodDesc {
+	for _, method := range ci.Methods {
+		if method.Name == name {
+			return method
+		}
+	}
+	return nil
+}
+
+// Event returns the description of event, nil if not found
+func (ci *ContractInterface) Event(name string) *EventDesc {
+	for _, event := range ci.Events {
+		if event.Name == name {
+			return event
+		}
+	}
+	return nil
+}
+
+// EncodeArgs encodes the json object input of method into contract args
+func (ci *ContractInterface) EncodeArgs(method string, input []byte) (map[string][]byte, error) {
+	desc := ci.Method(method)
+	if desc == nil {
+		return nil, fmt.Errorf("method %s not found in contract interface", method)
+	}
+	values := make(map[string]json.RawMessage)
+	if len(input) != 0 {
+		if err := json.Unmarshal(input, &values); err != nil {
+			return nil, fmt.Errorf("bad input of method %s:%s", method, err)
+		}
+	}
+	if name := unknownParam(desc.Inputs, values); name != "" {
+		return nil, fmt.Errorf("unknown argument %s of method %s", name, method)
+	}
+	args := make(map[string][]byte, len(desc.Inputs))
+	for _, param := range desc.Inputs {
+		value, ok := values[param.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing argument %s of method %s", param.Name, method)
+		}
+		arg, err := encodeValue(param.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("bad argument %s of method %s:%s", param.Name, method, err)
+		}
+		args[param.Name] = arg
+	}
+	return args, nil
+}
+
+// DecodeResponse decodes the response body of method into json object,
+// body is returned unchanged if the method has no outputs
+func (ci *ContractInterface) DecodeResponse(method string, body []byte) ([]byte, error) {
+	desc := ci.Method(method)
+	if desc == nil {
+		return nil, fmt.Errorf("method %s not found in contract interface", method)
+	}
+	switch len(desc.Outputs) {
+	case 0:
+		return body, nil
+	case 1:
+		param := desc.Outputs[0]
+		value, err := decodeValue(param.Type, body)
+		if err != nil {
+			return nil, fmt.Errorf("bad output %s of method %s:%s", param.Name, method, err)
+		}
+		return json.Marshal(map[string]interface{}{
+			param.Name: value,
+		})
+	default:
+		out, err := decodeFields(desc.Outputs, body)
+		if err != nil {
+			return nil, fmt.Errorf("bad outputs of method %s:%s", method, err)
+		}
+		return out, nil
+	}
+}
+
+// DecodeEvent decodes the event body into json object,
+// body is returned unchanged if the event is not described
+func (ci *ContractInterface) DecodeEvent(name string, body []byte) ([]byte, error) {
+	desc := ci.Event(name)
+	if desc == nil {
+		return body, nil
+	}
+	out, err := decodeFields(desc.Inputs, body)
+	if err != nil {
+		return nil, fmt.Errorf("bad body of event %s:%s", name, err)
+	}
+	return out, nil
+}
+
+// unknownParam 返回 values 中未在 params 里描述的最小的名字，保证错误信息是确定的
+func unknownParam(params []*ParamDesc, values map[string]json.RawMessage) string {
+	known := make(map[string]bool, len(params))
+	for _, param := range params {
+		known[param.Name] = true
+	}
+	var unknown []string
+	for name := range values {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return ""
+	}
+	sort.Strings(unknown)
+	return unknown[0]
+}
+
+// decodeFields 解码 json 对象形式的多个值，对象的值均为字符串，bytes 类型使用 hex 编码
+func decodeFields(params []*ParamDesc, body []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	if name := unknownParam(params, fields); name != "" {
+		return nil, fmt.Errorf("unknown field %s", name)
+	}
+	values := make(map[string]interface{}, len(params))
+	for _, param := range params {
+		value, ok := fields[param.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing field %s", param.Name)
+		}
+		var field string
+		if err := json.Unmarshal(value, &field); err != nil {
+			return nil, fmt.Errorf("bad field %s:%s", param.Name, err)
+		}
+		raw := []byte(field)
+		if param.Type == paramTypeBytes {
+			var err error
+			raw, err = hex.DecodeString(field)
+			if err != nil {
+				return nil, fmt.Errorf("bad field %s:%s", param.Name, err)
+			}
+		}
+		v, err := decodeValue(param.Type, raw)
+		if err != nil {
+			return nil, fmt.Errorf("bad field %s:%s", param.Name, err)
+		}
+		values[param.Name] = v
+	}
+	return json.Marshal(values)
+}
+
+// encodeValue 将 json 值编码为合约看到的字节数组
+func encodeValue(tp string, value json.RawMessage) ([]byte, error) {
+	switch tp {
+	case paramTypeString:
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	case paramTypeBytes:
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return nil, err
+		}
+		return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	case paramTypeBool:
+		var b bool
+		if err := json.Unmarshal(value, &b); err != nil {
+			return nil, err
+		}
+		return []byte(strconv.FormatBool(b)), nil
+	case paramTypeInt64, paramTypeUint64, paramTypeBigInt:
+		// 整数可以是 json 数字，也可以是十进制字符串，避免客户端精度丢失
+		s := string(value)
+		if strings.HasPrefix(s, `"`) {
+			if err := json.Unmarshal(value, &s); err != nil {
+				return nil, err
+			}
+		}
+		v, err := decodeValue(tp, []byte(s))
+		if err != nil {
+			return nil, err
+		}
+		return []byte(fmt.Sprint(v)), nil
+	default:
+		return nil, fmt.Errorf("unknown type %s", tp)
+	}
+}
+
+// decodeValue 将合约看到的字节数组解码为 json 值
+func decodeValue(tp string, raw []byte) (interface{}, error) {
+	switch tp {
+	case paramTypeString:
+		if !utf8.Valid(raw) {
+			return nil, errors.New("invalid utf8 string")
+		}
+		return string(raw), nil
+	case paramTypeBytes:
+		return hex.EncodeToString(raw), nil
+	case paramTypeBool:
+		switch string(raw) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		default:
+			return nil, fmt.Errorf("invalid bool %s", raw)
+		}
+	case paramTypeInt64:
+		v, err := strconv.ParseInt(string(raw), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return json.Number(strconv.FormatInt(v, 10)), nil
+	case paramTypeUint64:
+		v, err := strconv.ParseUint(string(raw), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return json.Number(strconv.FormatUint(v, 10)), nil
+	case paramTypeBigInt:
+		v, ok := new(big.Int).SetString(string(raw), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid bigint %s", raw)
+		}
+		return v.String(), nil
+	default:
+		return nil, fmt.Errorf("unknown type %s", tp)
+	}
+}
@@ -0,0 +1,346 @@
+package bridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	log15 "github.com/xuperchain/log15"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const testInterface = `{
+	"methods": [
+		{
+			"name": "transfer",
+			"inputs": [
+				{"name": "to", "type": "string"},
+				{"name": "amount", "type": "uint64"},
+				{"name": "memo", "type": "bytes"},
+				{"name": "force", "type": "bool"}
+			],
+			"outputs": [
+				{"name": "balance", "type": "bigint"}
+			]
+		},
+		{
+			"name": "info",
+			"outputs": [
+				{"name": "owner", "type": "string"},
+				{"name": "height", "type": "int64"},
+				{"name": "digest", "type": "bytes"}
+			]
+		}
+	],
+	"events": [
+		{
+			"name": "Transfer",
+			"inputs": [
+				{"name": "to", "type": "string"},
+				{"name": "amount", "type": "uint64"}
+			]
+		}
+	]
+}`
+
+func TestParseContractInterface(t *testing.T) {
+	if _, err := ParseContractInterface([]byte(testInterface)); err != nil {
+		t.Fatal(err)
+	}
+
+	badCases := []string{
+		`not json`,
+		`{"methods":[{"name":""}]}`,
+		`{"methods":[{"name":"a"},{"name":"a"}]}`,
+		`{"methods":[{"name":"a","inputs":[{"name":"x","type":"float"}]}]}`,
+		`{"methods":[{"name":"a","inputs":[{"name":"x","type":"string"},{"name":"x","type":"string"}]}]}`,
+		`{"events":[{"name":"e","inputs":[{"name":"","type":"string"}]}]}`,
+	}
+	for _, c := range badCases {
+		if _, err := ParseContractInterface([]byte(c)); err == nil {
+			t.Errorf("expect error for %s", c)
+		}
+	}
+}
+
+func TestContractInterfaceEncodeArgs(t *testing.T) {
+	ci, err := ParseContractInterface([]byte(testInterface))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args, err := ci.EncodeArgs("transfer", []byte(`{"to":"bob","amount":"18446744073709551615","memo":"0x0102","force":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{
+		"to":     "bob",
+		"amount": "18446744073709551615",
+		"memo":   "\x01\x02",
+		"force":  "true",
+	}
+	for k, v := range expect {
+		if string(args[k]) != v {
+			t.Errorf("arg %s expect %q got %q", k, v, args[k])
+		}
+	}
+
+	badInputs := []string{
+		`{"to":"bob","amount":10,"memo":"","force":true,"extra":1}`,
+		`{"to":"bob","amount":10,"memo":""}`,
+		`{"to":"bob","amount":-1,"memo":"","force":true}`,
+		`{"to":"bob","amount":1.5,"memo":"","force":true}`,
+		`{"to":"bob","amount":10,"memo":"zz","force":true}`,
+		`{"to":1,"amount":10,"memo":"","force":true}`,
+	}
+	for _, input := range badInputs {
+		if _, err := ci.EncodeArgs("transfer", []byte(input)); err == nil {
+			t.Errorf("expect error for %s", input)
+		}
+	}
+	if _, err := ci.EncodeArgs("unknown", nil); err == nil {
+		t.Error("expect error for unknown method")
+	}
+}
+
+func TestContractInterfaceDecode(t *testing.T) {
+	ci, err := ParseContractInterface([]byte(testInterface))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := ci.DecodeResponse("transfer", []byte("100000000000000000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"balance":"100000000000000000000"}` {
+		t.Errorf("unexpected response %s", out)
+	}
+
+	out, err = ci.DecodeResponse("info", []byte(`{"owner":"alice","height":"-1","digest":"abcd"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"digest":"abcd","height":-1,"owner":"alice"}` {
+		t.Errorf("unexpected response %s", out)
+	}
+	if _, err := ci.DecodeResponse("info", []byte(`{"owner":"alice","height":"x","digest":"abcd"}`)); err == nil {
+		t.Error("expect error for bad int64 output")
+	}
+
+	out, err = ci.DecodeEvent("Transfer", []byte(`{"to":"bob","amount":"10"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"amount":10,"to":"bob"}` {
+		t.Errorf("unexpected event %s", out)
+	}
+	out, err = ci.DecodeEvent("Other", []byte("raw"))
+	if err != nil || string(out) != "raw" {
+		t.Errorf("expect raw event body got %s %v", out, err)
+	}
+}
+
+type fakeInstance struct {
+	ctx  *Context
+	body []byte
+}
+
+func (f *fakeInstance) Exec() error {
+	f.ctx.Output = &pb.Response{
+		Status: 200,
+		Body:   f.body,
+	}
+	return nil
+}
+
+func (f *fakeInstance) ResourceUsed() contract.Limits {
+	return contract.Limits{}
+}
+
+func (f *fakeInstance) Release() {}
+
+func (f *fakeInstance) Abort(msg string) {}
+
+func newTestInterfaceContext(t *testing.T, withInterface bool) (*ContextManager, *Context) {
+	state := sandbox.NewXModelCache(&contract.SandboxConfig{
+		XMReader: sandbox.NewMemXModel(),
+	})
+	if withInterface {
+		if err := state.Put("contract", contractAbiKey("token"), []byte(testInterface)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctxmgr := NewContextManager()
+	ctx := ctxmgr.MakeContext()
+	ctx.State = state
+	ctx.ContractName = "token"
+	ctx.ResourceLimits = contract.MaxLimits
+	ctx.codeProvider = newCodeProviderWithCache(state)
+	return ctxmgr, ctx
+}
+
+func TestInvokeWithContractInterface(t *testing.T) {
+	_, ctx := newTestInterfaceContext(t, true)
+	instance := &fakeInstance{ctx: ctx, body: []byte("42")}
+	ctx.Instance = instance
+	vctx := &vmContextImpl{ctx: ctx, instance: instance}
+
+	resp, err := vctx.Invoke("transfer", map[string][]byte{
+		argJSONEncoded: []byte("true"),
+		argInput:       []byte(`{"to":"bob","amount":1,"memo":"","force":false}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(ctx.Args["amount"]) != "1" || string(ctx.Args["force"]) != "false" || len(ctx.Args) != 4 {
+		t.Errorf("unexpected args %v", ctx.Args)
+	}
+	if string(resp.Body) != `{"balance":"42"}` {
+		t.Errorf("unexpected body %s", resp.Body)
+	}
+
+	// 不使用 json 编码时参数和返回值保持不变
+	args := map[string][]byte{"to": []byte("bob")}
+	resp, err = vctx.Invoke("transfer", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(ctx.Args["to"]) != "bob" || len(ctx.Args) != 1 || string(resp.Body) != "42" {
+		t.Errorf("unexpected args %v body %s", ctx.Args, resp.Body)
+	}
+}
+
+func TestInvokeWithoutContractInterface(t *testing.T) {
+	_, ctx := newTestInterfaceContext(t, false)
+	instance := &fakeInstance{ctx: ctx, body: []byte("ok")}
+	ctx.Instance = instance
+	vctx := &vmContextImpl{ctx: ctx, instance: instance}
+
+	_, err := vctx.Invoke("transfer", map[string][]byte{
+		argJSONEncoded: []byte("true"),
+		argInput:       []byte(`{}`),
+	})
+	if err == nil {
+		t.Error("expect error for contract without interface")
+	}
+
+	// 无法读取接口描述时同样返回错误，不把原始参数交给合约
+	ctx.codeProvider = nil
+	ctx.Args = nil
+	_, err = vctx.Invoke("transfer", map[string][]byte{
+		argJSONEncoded: []byte("true"),
+		argInput:       []byte(`{}`),
+	})
+	if err == nil || ctx.Args != nil {
+		t.Errorf("expect error for contract without code provider, err %v args %v", err, ctx.Args)
+	}
+
+	resp, err := vctx.Invoke("transfer", map[string][]byte{"to": []byte("bob")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.Body) != "ok" {
+		t.Errorf("unexpected body %s", resp.Body)
+	}
+}
+
+func TestEmitEventWithContractInterface(t *testing.T) {
+	ctxmgr, ctx := newTestInterfaceContext(t, true)
+	syscall := NewSyscallService(ctxmgr, nil)
+
+	_, err := syscall.EmitEvent(context.TODO(), &pb.EmitEventRequest{
+		Header: &pb.SyscallHeader{Ctxid: ctx.ID},
+		Name:   "Transfer",
+		Body:   []byte(`{"to":"bob","amount":"10"}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = syscall.EmitEvent(context.TODO(), &pb.EmitEventRequest{
+		Header: &pb.SyscallHeader{Ctxid: ctx.ID},
+		Name:   "Transfer",
+		Body:   []byte(`{"to":"bob"}`),
+	})
+	if err == nil {
+		t.Error("expect error for bad event body")
+	}
+	if len(ctx.Events) != 1 || string(ctx.Events[0].Body) != `{"amount":10,"to":"bob"}` {
+		t.Errorf("unexpected events %v", ctx.Events)
+	}
+}
+
+// abiCountingReader 记录接口描述被读取的次数
+type abiCountingReader struct {
+	*sandbox.MemXModel
+	abiReads int
+}
+
+func (r *abiCountingReader) Get(bucket string, key []byte) (*ledger.VersionedData, error) {
+	if string(key) == string(contractAbiKey("token")) {
+		r.abiReads++
+	}
+	return r.MemXModel.Get(bucket, key)
+}
+
+func TestNewContextLoadsInterfaceByDesc(t *testing.T) {
+	for _, hasAbi := range []bool{false, true} {
+		// 合约在之前的交易中部署
+		model := &abiCountingReader{MemXModel: sandbox.NewMemXModel()}
+		put := func(key, value []byte) {
+			model.Put("contract", key, &ledger.VersionedData{
+				PureData: &ledger.PureData{Bucket: "contract", Key: key, Value: value},
+				RefTxid:  []byte("deploy"),
+			})
+		}
+		descbuf, _ := proto.Marshal(&protos.WasmCodeDesc{ContractType: "wasm", HasAbi: hasAbi})
+		put(ContractCodeDescKey("token"), descbuf)
+		put(contractAbiKey("token"), []byte(testInterface))
+		state := sandbox.NewXModelCache(&contract.SandboxConfig{XMReader: model})
+
+		ctxmgr := NewContextManager()
+		xbridge := &XBridge{
+			ctxmgr:          ctxmgr,
+			creators:        map[ContractType]InstanceCreator{TypeWasm: &failedCreator{}},
+			debugLogger:     &testLogger{log15.New()},
+			contractManager: &contractManager{codeProvider: newCodeProviderFromXMReader(model)},
+		}
+		c, err := xbridge.NewContext(&contract.ContextConfig{
+			State:          state,
+			ContractName:   "token",
+			ResourceLimits: contract.MaxLimits,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := c.(*vmContextImpl).ctx
+		syscall := NewSyscallService(ctxmgr, xbridge)
+		for i := 0; i < 2; i++ {
+			_, err := syscall.EmitEvent(context.TODO(), &pb.EmitEventRequest{
+				Header: &pb.SyscallHeader{Ctxid: ctx.ID},
+				Name:   "Transfer",
+				Body:   []byte(`{"to":"bob","amount":"10"}`),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		// 没有接口描述的合约不读取，有接口描述的合约只读取一次
+		expectReads := 0
+		if hasAbi {
+			expectReads = 1
+		}
+		if model.abiReads != expectReads {
+			t.Errorf("hasAbi:%v, expect %d abi reads, got %d", hasAbi, expectReads, model.abiReads)
+		}
+		decoded := string(ctx.Events[1].Body) == `{"amount":10,"to":"bob"}`
+		if decoded != hasAbi {
+			t.Errorf("hasAbi:%v, unexpected event body %s", hasAbi, ctx.Events[1].Body)
+		}
+		c.Release()
+	}
+}
@@ -0,0 +1,91 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+)
+
+// FreezeContract 冻结或者解冻合约。冻结状态保存在合约的 desc 中，
+// 调用合约时本来就会读取 desc，因此不会改变合约调用的读集
+func (c *contractManager) FreezeContract(kctx contract.KContext, frozen bool) (*contract.Response, contract.Limits, error) {
+	name := kctx.Args()["contract_name"]
+	if name == nil {
+		return nil, contract.Limits{}, errors.New("bad contract name")
+	}
+	contractName := string(name)
+	desc, err := newCodeProviderWithCache(kctx).GetContractCodeDesc(contractName)
+	if err != nil {
+		return nil, contract.Limits{}, fmt.Errorf("contract %s not exists", contractName)
+	}
+	if desc.GetFrozen() == frozen {
+		if frozen {
+			return nil, contract.Limits{}, fmt.Errorf("contract %s is already frozen", contractName)
+		}
+		return nil, contract.Limits{}, fmt.Errorf("contract %s is not frozen", contractName)
+	}
+	desc.Frozen = frozen
+	descbuf, _ := proto.Marshal(desc)
+	if err := kctx.Put("contract", ContractCodeDescKey(contractName), descbuf); err != nil {
+		return nil, contract.Limits{}, err
+	}
+	body := "unfreeze success"
+	if frozen {
+		body = "freeze success"
+	}
+	return &contract.Response{
+		Status: 200,
+		Body:   []byte(body),
+	}, contract.Limits{
+		Disk: modelCacheDiskUsed(kctx),
+	}, nil
+}
+
+// DestroyContract 删除合约的代码、desc 和接口描述，删除记录和合约的历史版本仍然保留在账本中，
+// 已经销毁的合约名不能再次部署
+func (c *contractManager) DestroyContract(kctx contract.KContext) (*contract.Response, contract.Limits, error) {
+	name := kctx.Args()["contract_name"]
+	if name == nil {
+		return nil, contract.Limits{}, errors.New("bad contract name")
+	}
+	contractName := string(name)
+	if _, err := newCodeProviderWithCache(kctx).GetContractCodeDesc(contractName); err != nil {
+		return nil, contract.Limits{}, fmt.Errorf("contract %s not exists", contractName)
+	}
+	keys := [][]byte{
+		ContractCodeDescKey(contractName),
+		contractCodeKey(contractName),
+	}
+	// 接口描述是可选的，不存在时不需要删除
+	if abi, err := kctx.Get("contract", contractAbiKey(contractName)); err == nil && len(abi) != 0 {
+		keys = append(keys, contractAbiKey(contractName))
+	}
+	for _, key := range keys {
+		if err := kctx.Del("contract", key); err != nil {
+			return nil, contract.Limits{}, err
+		}
+	}
+	return &contract.Response{
+		Status: 200,
+		Body:   []byte("destroy success"),
+	}, contract.Limits{
+		Disk: modelCacheDiskUsed(kctx),
+	}, nil
+}
+
+// isContractDestroyed 通过交易的沙盒判断合约是否被销毁，删除标记的版本会进入交易的读集，
+// 保证验证和打包时基于相同的状态作出判断
+func (c *contractManager) isContractDestroyed(kctx contract.KContext, contractName string) (bool, error) {
+	_, err := kctx.Get("contract", ContractCodeDescKey(contractName))
+	switch err {
+	case sandbox.ErrHasDel:
+		return true, nil
+	case nil, sandbox.ErrNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
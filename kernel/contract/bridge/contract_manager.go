@@ -30,6 +30,13 @@ func (c *contractManager) DeployContract(kctx contract.KContext) (*contract.Resp
 	if err == nil {
 		return nil, contract.Limits{}, fmt.Errorf("contract %s already exists", contractName)
 	}
+	destroyed, err := c.isContractDestroyed(kctx, contractName)
+	if err != nil {
+		return nil, contract.Limits{}, err
+	}
+	if destroyed {
+		return nil, contract.Limits{}, fmt.Errorf("contract %s has been destroyed", contractName)
+	}
 
 	code := args["contract_code"]
 	if code == nil {
@@ -52,6 +59,7 @@ func (c *contractManager) DeployContract(kctx contract.KContext) (*contract.Resp
 		return nil, contract.Limits{}, err
 	}
 	desc.Digest = hash.DoubleSha256(code)
+	desc.HasAbi = len(args["contract_abi"]) != 0
 	descbuf, _ = proto.Marshal(&desc)
 
 	if err := state.Put("contract", ContractCodeDescKey(contractName), descbuf); err != nil {
@@ -61,12 +69,25 @@ func (c *contractManager) DeployContract(kctx contract.KContext) (*contract.Resp
 		return nil, contract.Limits{}, err
 
 	}
+	if err := c.addContractVersion(state, contractName, desc.Digest); err != nil {
+		return nil, contract.Limits{}, err
+	}
 
+	var ci *ContractInterface
 	if desc.ContractType == string(TypeEvm) {
 		abiBuf := args["contract_abi"]
 		if err := state.Put("contract", contractAbiKey(contractName), abiBuf); err != nil {
 			return nil, contract.Limits{}, err
 		}
+	} else if abiBuf := args["contract_abi"]; len(abiBuf) != 0 {
+		// wasm 和 native 合约可选的接口描述
+		ci, err = ParseContractInterface(abiBuf)
+		if err != nil {
+			return nil, contract.Limits{}, err
+		}
+		if err := state.Put("contract", contractAbiKey(contractName), abiBuf); err != nil {
+			return nil, contract.Limits{}, err
+		}
 	}
 
 	contractType, err := getContractType(&desc)
@@ -105,7 +126,7 @@ func (c *contractManager) DeployContract(kctx contract.KContext) (*contract.Resp
 	initConfig.CanInitialize = true
 	initConfig.ContractCodeFromCache = true
 	initConfig.State = kctx
-	out, resourceUsed, err := c.initContract(contractType, &initConfig, initArgs)
+	out, resourceUsed, err := c.initContract(contractType, &initConfig, initArgs, ci)
 	if err != nil {
 		if _, ok := err.(*ContractError); !ok {
 			creator.RemoveCache(contractName)
@@ -116,11 +137,16 @@ func (c *contractManager) DeployContract(kctx contract.KContext) (*contract.Resp
 	return out, resourceUsed, nil
 }
 
-func (v *contractManager) initContract(tp ContractType, contextConfig *contract.ContextConfig, args map[string][]byte) (*contract.Response, contract.Limits, error) {
+func (v *contractManager) initContract(tp ContractType, contextConfig *contract.ContextConfig, args map[string][]byte, ci *ContractInterface) (*contract.Response, contract.Limits, error) {
 	ctx, err := v.xbridge.NewContext(contextConfig)
 	if err != nil {
 		return nil, contract.Limits{}, err
 	}
+	// 部署时接口描述已经从参数中解析，不再从沙盒中读取，避免改变没有接口描述的合约的读集
+	if vctx, ok := ctx.(*vmContextImpl); ok {
+		vctx.ctx.contractInterface = ci
+		vctx.ctx.interfaceLoaded = true
+	}
 	out, err := ctx.Invoke("initialize", args)
 	if err != nil {
 		return nil, contract.Limits{}, err
@@ -128,60 +154,26 @@ func (v *contractManager) initContract(tp ContractType, contextConfig *contract.
 	return out, ctx.ResourceUsed(), nil
 }
 
-// UpgradeContract deploy contract and initialize contract
+// UpgradeContract 检查并写入合约的新代码，和 proposeUpgrade 生效时使用相同的检查和写入逻辑
 func (c *contractManager) UpgradeContract(kctx contract.KContext) (*contract.Response, contract.Limits, error) {
 	args := kctx.Args()
-	if !c.xbridge.config.EnableUpgrade {
-		return nil, contract.Limits{}, errors.New("contract upgrade disabled")
-	}
-
 	name := args["contract_name"]
 	if name == nil {
 		return nil, contract.Limits{}, errors.New("bad contract name")
 	}
 	contractName := string(name)
-	desc, err := c.codeProvider.GetContractCodeDesc(contractName)
-	if err != nil {
-		return nil, contract.Limits{}, fmt.Errorf("contract %s not exists", contractName)
-	}
-
-	code := args["contract_code"]
-	if code == nil {
-		return nil, contract.Limits{}, errors.New("missing contract code")
-	}
-	desc.Digest = hash.DoubleSha256(code)
-	descbuf, _ := proto.Marshal(desc)
-
-	store := kctx
-	store.Put("contract", ContractCodeDescKey(contractName), descbuf)
-	store.Put("contract", contractCodeKey(contractName), code)
-
-	cp := newCodeProviderWithCache(store)
-
-	contractType, err := getContractType(desc)
-	if err != nil {
+	code, abi := args["contract_code"], args["contract_abi"]
+	if _, err := c.CheckUpgrade(kctx, contractName, code, abi); err != nil {
 		return nil, contract.Limits{}, err
 	}
-	creator := c.xbridge.getCreator(contractType)
-	if creator == nil {
-		return nil, contract.Limits{}, fmt.Errorf("contract type %s not found", contractType)
-	}
-	instance, err := creator.CreateInstance(&Context{
-		ContractName:   contractName,
-		ResourceLimits: contract.MaxLimits,
-	}, cp)
+	limits, err := c.ApplyUpgrade(kctx, contractName, code, abi)
 	if err != nil {
-		// log.Error("create contract instance error when upgrade contract", "error", err, "contract", contractName)
 		return nil, contract.Limits{}, err
 	}
-	instance.Release()
-
 	return &contract.Response{
-			Status: 200,
-			Body:   []byte("upgrade success"),
-		}, contract.Limits{
-			Disk: modelCacheDiskUsed(store),
-		}, nil
+		Status: 200,
+		Body:   []byte("upgrade success"),
+	}, limits, nil
 }
 
 func modelCacheDiskUsed(store contract.KContext) int64 {
@@ -0,0 +1,148 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/crypto/core/hash"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// ContractVersionKeyPrefix 返回合约历史版本记录的 key 前缀，记录按版本号递增排列。
+// 合约名中可以包含 '.'，前缀使用合约名不允许的 '/' 分隔，避免包含其他合约的版本记录
+func ContractVersionKeyPrefix(contractName string) []byte {
+	return []byte(contractName + "/version/")
+}
+
+func contractVersionKey(contractName string, version int64) []byte {
+	return []byte(fmt.Sprintf("%s%020d", ContractVersionKeyPrefix(contractName), version))
+}
+
+func contractLatestVersionKey(contractName string) []byte {
+	return []byte(contractName + ".version")
+}
+
+// addContractVersion 记录合约的新版本，版本生效的交易和高度由记录所在的交易确定。
+// 版本记录上线之前部署的合约没有版本记录，第一次升级时从版本 1 开始记录
+func (c *contractManager) addContractVersion(state contract.XMState, contractName string, digest []byte) error {
+	var version int64
+	if buf, err := state.Get("contract", contractLatestVersionKey(contractName)); err == nil && len(buf) != 0 {
+		version, err = strconv.ParseInt(string(buf), 10, 64)
+		if err != nil {
+			return fmt.Errorf("bad version of contract %s:%s", contractName, err)
+		}
+	}
+	version++
+	record, _ := proto.Marshal(&protos.ContractVersion{
+		ContractName: contractName,
+		Version:      version,
+		Digest:       digest,
+	})
+	if err := state.Put("contract", contractVersionKey(contractName, version), record); err != nil {
+		return err
+	}
+	return state.Put("contract", contractLatestVersionKey(contractName), []byte(strconv.FormatInt(version, 10)))
+}
+
+// pendingCodeProvider 提供尚未写入状态的合约代码，用于检查待生效的升级
+type pendingCodeProvider struct {
+	desc *protos.WasmCodeDesc
+	code []byte
+	abi  []byte
+}
+
+func (p *pendingCodeProvider) GetContractCodeDesc(name string) (*protos.WasmCodeDesc, error) {
+	return p.desc, nil
+}
+
+func (p *pendingCodeProvider) GetContractCode(name string) ([]byte, error) {
+	return p.code, nil
+}
+
+func (p *pendingCodeProvider) GetContractAbi(name string) ([]byte, error) {
+	if len(p.abi) == 0 {
+		return nil, errors.New("empty abi")
+	}
+	return p.abi, nil
+}
+
+func (p *pendingCodeProvider) GetContractCodeFromCache(name string) ([]byte, error) {
+	return p.GetContractCode(name)
+}
+
+func (p *pendingCodeProvider) GetContractAbiFromCache(name string) ([]byte, error) {
+	return p.GetContractAbi(name)
+}
+
+// CheckUpgrade 检查待生效的合约代码是否可以加载，返回代码的摘要，代码不会写入状态
+func (c *contractManager) CheckUpgrade(kctx contract.KContext, contractName string, code, abi []byte) ([]byte, error) {
+	if !c.xbridge.config.EnableUpgrade {
+		return nil, errors.New("contract upgrade disabled")
+	}
+	desc, err := newCodeProviderWithCache(kctx).GetContractCodeDesc(contractName)
+	if err != nil {
+		return nil, fmt.Errorf("contract %s not exists", contractName)
+	}
+	if len(code) == 0 {
+		return nil, errors.New("missing contract code")
+	}
+	if len(abi) != 0 && desc.ContractType != string(TypeEvm) {
+		if _, err := ParseContractInterface(abi); err != nil {
+			return nil, err
+		}
+	}
+	desc.Digest = hash.DoubleSha256(code)
+
+	contractType, err := getContractType(desc)
+	if err != nil {
+		return nil, err
+	}
+	creator := c.xbridge.getCreator(contractType)
+	if creator == nil {
+		return nil, fmt.Errorf("contract type %s not found", contractType)
+	}
+	instance, err := creator.CreateInstance(&Context{
+		ContractName:   contractName,
+		ResourceLimits: contract.MaxLimits,
+	}, &pendingCodeProvider{desc: desc, code: code, abi: abi})
+	if err != nil {
+		return nil, err
+	}
+	instance.Release()
+	return desc.Digest, nil
+}
+
+// ApplyUpgrade 将已经检查过的合约代码写入状态并记录新版本，合约的冻结等状态保持不变
+func (c *contractManager) ApplyUpgrade(kctx contract.KContext, contractName string, code, abi []byte) (contract.Limits, error) {
+	desc, err := newCodeProviderWithCache(kctx).GetContractCodeDesc(contractName)
+	if err != nil {
+		return contract.Limits{}, fmt.Errorf("contract %s not exists", contractName)
+	}
+	desc.Digest = hash.DoubleSha256(code)
+	// 升级不带接口描述时沿用原来的接口描述
+	if len(abi) != 0 {
+		desc.HasAbi = true
+	}
+	descbuf, _ := proto.Marshal(desc)
+
+	if err := kctx.Put("contract", ContractCodeDescKey(contractName), descbuf); err != nil {
+		return contract.Limits{}, err
+	}
+	if err := kctx.Put("contract", contractCodeKey(contractName), code); err != nil {
+		return contract.Limits{}, err
+	}
+	if len(abi) != 0 {
+		if err := kctx.Put("contract", contractAbiKey(contractName), abi); err != nil {
+			return contract.Limits{}, err
+		}
+	}
+	if err := c.addContractVersion(kctx, contractName, desc.Digest); err != nil {
+		return contract.Limits{}, err
+	}
+	return contract.Limits{
+		Disk: modelCacheDiskUsed(kctx),
+	}, nil
+}
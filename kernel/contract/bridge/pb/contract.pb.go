@@ -893,6 +893,111 @@ func (m *QueryBlockResponse) GetBlock() *Block {
 	return nil
 }
 
+type GetRandomRequest struct {
+	Header *SyscallHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	// height of the block on trunk whose randomness is returned
+	Height               int64    `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRandomRequest) Reset()         { *m = GetRandomRequest{} }
+func (m *GetRandomRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRandomRequest) ProtoMessage()    {}
+func (*GetRandomRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d19debeba7dea55a, []int{20}
+}
+
+func (m *GetRandomRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRandomRequest.Unmarshal(m, b)
+}
+func (m *GetRandomRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRandomRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRandomRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRandomRequest.Merge(m, src)
+}
+func (m *GetRandomRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRandomRequest.Size(m)
+}
+func (m *GetRandomRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRandomRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRandomRequest proto.InternalMessageInfo
+
+func (m *GetRandomRequest) GetHeader() *SyscallHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetRandomRequest) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type GetRandomResponse struct {
+	// VRF output of the block, or sha256 of blockid if the block carries no VRF proof
+	Random  []byte `protobuf:"bytes,1,opt,name=random,proto3" json:"random,omitempty"`
+	Blockid string `protobuf:"bytes,2,opt,name=blockid,proto3" json:"blockid,omitempty"`
+	// whether random is a VRF output which can not be chosen by the proposer
+	Verifiable           bool     `protobuf:"varint,3,opt,name=verifiable,proto3" json:"verifiable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRandomResponse) Reset()         { *m = GetRandomResponse{} }
+func (m *GetRandomResponse) String() string { return proto.CompactTextString(m) }
+func (*GetRandomResponse) ProtoMessage()    {}
+func (*GetRandomResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d19debeba7dea55a, []int{21}
+}
+
+func (m *GetRandomResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRandomResponse.Unmarshal(m, b)
+}
+func (m *GetRandomResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRandomResponse.Marshal(b, m, deterministic)
+}
+func (m *GetRandomResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRandomResponse.Merge(m, src)
+}
+func (m *GetRandomResponse) XXX_Size() int {
+	return xxx_messageInfo_GetRandomResponse.Size(m)
+}
+func (m *GetRandomResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRandomResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRandomResponse proto.InternalMessageInfo
+
+func (m *GetRandomResponse) GetRandom() []byte {
+	if m != nil {
+		return m.Random
+	}
+	return nil
+}
+
+func (m *GetRandomResponse) GetBlockid() string {
+	if m != nil {
+		return m.Blockid
+	}
+	return ""
+}
+
+func (m *GetRandomResponse) GetVerifiable() bool {
+	if m != nil {
+		return m.Verifiable
+	}
+	return false
+}
+
 type TransferRequest struct {
 	Header               *SyscallHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
 	From                 string         `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
@@ -907,7 +1012,7 @@ func (m *TransferRequest) Reset()         { *m = TransferRequest{} }
 func (m *TransferRequest) String() string { return proto.CompactTextString(m) }
 func (*TransferRequest) ProtoMessage()    {}
 func (*TransferRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{20}
+	return fileDescriptor_d19debeba7dea55a, []int{22}
 }
 
 func (m *TransferRequest) XXX_Unmarshal(b []byte) error {
@@ -966,7 +1071,7 @@ func (m *TransferResponse) Reset()         { *m = TransferResponse{} }
 func (m *TransferResponse) String() string { return proto.CompactTextString(m) }
 func (*TransferResponse) ProtoMessage()    {}
 func (*TransferResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{21}
+	return fileDescriptor_d19debeba7dea55a, []int{23}
 }
 
 func (m *TransferResponse) XXX_Unmarshal(b []byte) error {
@@ -987,22 +1092,122 @@ func (m *TransferResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_TransferResponse proto.InternalMessageInfo
 
+type GetBalanceRequest struct {
+	Header  *SyscallHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Address string         `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	// frozen selects the balance of utxos that are still frozen
+	Frozen               bool     `protobuf:"varint,3,opt,name=frozen,proto3" json:"frozen,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBalanceRequest) Reset()         { *m = GetBalanceRequest{} }
+func (m *GetBalanceRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceRequest) ProtoMessage()    {}
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d19debeba7dea55a, []int{24}
+}
+
+func (m *GetBalanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceRequest.Unmarshal(m, b)
+}
+func (m *GetBalanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBalanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceRequest.Merge(m, src)
+}
+func (m *GetBalanceRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceRequest.Size(m)
+}
+func (m *GetBalanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceRequest proto.InternalMessageInfo
+
+func (m *GetBalanceRequest) GetHeader() *SyscallHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetBalanceRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *GetBalanceRequest) GetFrozen() bool {
+	if m != nil {
+		return m.Frozen
+	}
+	return false
+}
+
+type GetBalanceResponse struct {
+	Balance              string   `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBalanceResponse) Reset()         { *m = GetBalanceResponse{} }
+func (m *GetBalanceResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceResponse) ProtoMessage()    {}
+func (*GetBalanceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d19debeba7dea55a, []int{25}
+}
+
+func (m *GetBalanceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceResponse.Unmarshal(m, b)
+}
+func (m *GetBalanceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceResponse.Marshal(b, m, deterministic)
+}
+func (m *GetBalanceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceResponse.Merge(m, src)
+}
+func (m *GetBalanceResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceResponse.Size(m)
+}
+func (m *GetBalanceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceResponse proto.InternalMessageInfo
+
+func (m *GetBalanceResponse) GetBalance() string {
+	if m != nil {
+		return m.Balance
+	}
+	return ""
+}
+
 type ContractCallRequest struct {
-	Header               *SyscallHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
-	Module               string         `protobuf:"bytes,2,opt,name=module,proto3" json:"module,omitempty"`
-	Contract             string         `protobuf:"bytes,3,opt,name=contract,proto3" json:"contract,omitempty"`
-	Method               string         `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
-	Args                 []*ArgPair     `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	Header   *SyscallHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Module   string         `protobuf:"bytes,2,opt,name=module,proto3" json:"module,omitempty"`
+	Contract string         `protobuf:"bytes,3,opt,name=contract,proto3" json:"contract,omitempty"`
+	Method   string         `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	Args     []*ArgPair     `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`
+	// amount is transferred from the caller contract to the callee before the call
+	Amount string `protobuf:"bytes,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	// try discards the writes, transfers and events of the call when it fails
+	// and returns the failure as response instead of an error
+	Try                  bool     `protobuf:"varint,7,opt,name=try,proto3" json:"try,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ContractCallRequest) Reset()         { *m = ContractCallRequest{} }
 func (m *ContractCallRequest) String() string { return proto.CompactTextString(m) }
 func (*ContractCallRequest) ProtoMessage()    {}
 func (*ContractCallRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{22}
+	return fileDescriptor_d19debeba7dea55a, []int{26}
 }
 
 func (m *ContractCallRequest) XXX_Unmarshal(b []byte) error {
@@ -1058,6 +1263,20 @@ func (m *ContractCallRequest) GetArgs() []*ArgPair {
 	return nil
 }
 
+func (m *ContractCallRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *ContractCallRequest) GetTry() bool {
+	if m != nil {
+		return m.Try
+	}
+	return false
+}
+
 type ContractCallResponse struct {
 	Response             *Response `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
@@ -1069,7 +1288,7 @@ func (m *ContractCallResponse) Reset()         { *m = ContractCallResponse{} }
 func (m *ContractCallResponse) String() string { return proto.CompactTextString(m) }
 func (*ContractCallResponse) ProtoMessage()    {}
 func (*ContractCallResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{23}
+	return fileDescriptor_d19debeba7dea55a, []int{27}
 }
 
 func (m *ContractCallResponse) XXX_Unmarshal(b []byte) error {
@@ -1110,7 +1329,7 @@ func (m *CrossContractQueryRequest) Reset()         { *m = CrossContractQueryReq
 func (m *CrossContractQueryRequest) String() string { return proto.CompactTextString(m) }
 func (*CrossContractQueryRequest) ProtoMessage()    {}
 func (*CrossContractQueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{24}
+	return fileDescriptor_d19debeba7dea55a, []int{28}
 }
 
 func (m *CrossContractQueryRequest) XXX_Unmarshal(b []byte) error {
@@ -1163,7 +1382,7 @@ func (m *CrossContractQueryResponse) Reset()         { *m = CrossContractQueryRe
 func (m *CrossContractQueryResponse) String() string { return proto.CompactTextString(m) }
 func (*CrossContractQueryResponse) ProtoMessage()    {}
 func (*CrossContractQueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{25}
+	return fileDescriptor_d19debeba7dea55a, []int{29}
 }
 
 func (m *CrossContractQueryResponse) XXX_Unmarshal(b []byte) error {
@@ -1204,7 +1423,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{26}
+	return fileDescriptor_d19debeba7dea55a, []int{30}
 }
 
 func (m *Response) XXX_Unmarshal(b []byte) error {
@@ -1258,7 +1477,7 @@ func (m *SetOutputRequest) Reset()         { *m = SetOutputRequest{} }
 func (m *SetOutputRequest) String() string { return proto.CompactTextString(m) }
 func (*SetOutputRequest) ProtoMessage()    {}
 func (*SetOutputRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{27}
+	return fileDescriptor_d19debeba7dea55a, []int{31}
 }
 
 func (m *SetOutputRequest) XXX_Unmarshal(b []byte) error {
@@ -1303,7 +1522,7 @@ func (m *SetOutputResponse) Reset()         { *m = SetOutputResponse{} }
 func (m *SetOutputResponse) String() string { return proto.CompactTextString(m) }
 func (*SetOutputResponse) ProtoMessage()    {}
 func (*SetOutputResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{28}
+	return fileDescriptor_d19debeba7dea55a, []int{32}
 }
 
 func (m *SetOutputResponse) XXX_Unmarshal(b []byte) error {
@@ -1335,7 +1554,7 @@ func (m *GetCallArgsRequest) Reset()         { *m = GetCallArgsRequest{} }
 func (m *GetCallArgsRequest) String() string { return proto.CompactTextString(m) }
 func (*GetCallArgsRequest) ProtoMessage()    {}
 func (*GetCallArgsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{29}
+	return fileDescriptor_d19debeba7dea55a, []int{33}
 }
 
 func (m *GetCallArgsRequest) XXX_Unmarshal(b []byte) error {
@@ -1384,7 +1603,7 @@ func (m *TxInput) Reset()         { *m = TxInput{} }
 func (m *TxInput) String() string { return proto.CompactTextString(m) }
 func (*TxInput) ProtoMessage()    {}
 func (*TxInput) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{30}
+	return fileDescriptor_d19debeba7dea55a, []int{34}
 }
 
 func (m *TxInput) XXX_Unmarshal(b []byte) error {
@@ -1457,7 +1676,7 @@ func (m *TxOutput) Reset()         { *m = TxOutput{} }
 func (m *TxOutput) String() string { return proto.CompactTextString(m) }
 func (*TxOutput) ProtoMessage()    {}
 func (*TxOutput) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{31}
+	return fileDescriptor_d19debeba7dea55a, []int{35}
 }
 
 func (m *TxOutput) XXX_Unmarshal(b []byte) error {
@@ -1525,7 +1744,7 @@ func (m *Transaction) Reset()         { *m = Transaction{} }
 func (m *Transaction) String() string { return proto.CompactTextString(m) }
 func (*Transaction) ProtoMessage()    {}
 func (*Transaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{32}
+	return fileDescriptor_d19debeba7dea55a, []int{36}
 }
 
 func (m *Transaction) XXX_Unmarshal(b []byte) error {
@@ -1629,7 +1848,7 @@ func (m *Block) Reset()         { *m = Block{} }
 func (m *Block) String() string { return proto.CompactTextString(m) }
 func (*Block) ProtoMessage()    {}
 func (*Block) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{33}
+	return fileDescriptor_d19debeba7dea55a, []int{37}
 }
 
 func (m *Block) XXX_Unmarshal(b []byte) error {
@@ -1739,7 +1958,7 @@ func (m *GetAccountAddressesRequest) Reset()         { *m = GetAccountAddressesR
 func (m *GetAccountAddressesRequest) String() string { return proto.CompactTextString(m) }
 func (*GetAccountAddressesRequest) ProtoMessage()    {}
 func (*GetAccountAddressesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{34}
+	return fileDescriptor_d19debeba7dea55a, []int{38}
 }
 
 func (m *GetAccountAddressesRequest) XXX_Unmarshal(b []byte) error {
@@ -1785,7 +2004,7 @@ func (m *GetAccountAddressesResponse) Reset()         { *m = GetAccountAddresses
 func (m *GetAccountAddressesResponse) String() string { return proto.CompactTextString(m) }
 func (*GetAccountAddressesResponse) ProtoMessage()    {}
 func (*GetAccountAddressesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{35}
+	return fileDescriptor_d19debeba7dea55a, []int{39}
 }
 
 func (m *GetAccountAddressesResponse) XXX_Unmarshal(b []byte) error {
@@ -1825,7 +2044,7 @@ func (m *PostLogRequest) Reset()         { *m = PostLogRequest{} }
 func (m *PostLogRequest) String() string { return proto.CompactTextString(m) }
 func (*PostLogRequest) ProtoMessage()    {}
 func (*PostLogRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{36}
+	return fileDescriptor_d19debeba7dea55a, []int{40}
 }
 
 func (m *PostLogRequest) XXX_Unmarshal(b []byte) error {
@@ -1871,7 +2090,7 @@ func (m *PostLogResponse) Reset()         { *m = PostLogResponse{} }
 func (m *PostLogResponse) String() string { return proto.CompactTextString(m) }
 func (*PostLogResponse) ProtoMessage()    {}
 func (*PostLogResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{37}
+	return fileDescriptor_d19debeba7dea55a, []int{41}
 }
 
 func (m *PostLogResponse) XXX_Unmarshal(b []byte) error {
@@ -1912,7 +2131,7 @@ func (m *EmitEventRequest) Reset()         { *m = EmitEventRequest{} }
 func (m *EmitEventRequest) String() string { return proto.CompactTextString(m) }
 func (*EmitEventRequest) ProtoMessage()    {}
 func (*EmitEventRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{38}
+	return fileDescriptor_d19debeba7dea55a, []int{42}
 }
 
 func (m *EmitEventRequest) XXX_Unmarshal(b []byte) error {
@@ -1964,7 +2183,7 @@ func (m *EmitEventResponse) Reset()         { *m = EmitEventResponse{} }
 func (m *EmitEventResponse) String() string { return proto.CompactTextString(m) }
 func (*EmitEventResponse) ProtoMessage()    {}
 func (*EmitEventResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d19debeba7dea55a, []int{39}
+	return fileDescriptor_d19debeba7dea55a, []int{43}
 }
 
 func (m *EmitEventResponse) XXX_Unmarshal(b []byte) error {
@@ -2006,8 +2225,12 @@ func init() {
 	proto.RegisterType((*QueryTxResponse)(nil), "xchain.contract.sdk.QueryTxResponse")
 	proto.RegisterType((*QueryBlockRequest)(nil), "xchain.contract.sdk.QueryBlockRequest")
 	proto.RegisterType((*QueryBlockResponse)(nil), "xchain.contract.sdk.QueryBlockResponse")
+	proto.RegisterType((*GetRandomRequest)(nil), "xchain.contract.sdk.GetRandomRequest")
+	proto.RegisterType((*GetRandomResponse)(nil), "xchain.contract.sdk.GetRandomResponse")
 	proto.RegisterType((*TransferRequest)(nil), "xchain.contract.sdk.TransferRequest")
 	proto.RegisterType((*TransferResponse)(nil), "xchain.contract.sdk.TransferResponse")
+	proto.RegisterType((*GetBalanceRequest)(nil), "xchain.contract.sdk.GetBalanceRequest")
+	proto.RegisterType((*GetBalanceResponse)(nil), "xchain.contract.sdk.GetBalanceResponse")
 	proto.RegisterType((*ContractCallRequest)(nil), "xchain.contract.sdk.ContractCallRequest")
 	proto.RegisterType((*ContractCallResponse)(nil), "xchain.contract.sdk.ContractCallResponse")
 	proto.RegisterType((*CrossContractQueryRequest)(nil), "xchain.contract.sdk.CrossContractQueryRequest")
@@ -2031,84 +2254,90 @@ func init() {
 func init() { proto.RegisterFile("contract.proto", fileDescriptor_d19debeba7dea55a) }
 
 var fileDescriptor_d19debeba7dea55a = []byte{
-	// 1259 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6e, 0xdb, 0xc6,
-	0x12, 0x06, 0xf5, 0xaf, 0x91, 0x2c, 0xdb, 0xb4, 0x91, 0x43, 0x3b, 0x09, 0xa0, 0x30, 0x38, 0x88,
-	0xcf, 0x8d, 0x9c, 0x93, 0x02, 0x2d, 0xd2, 0xf6, 0xc6, 0x71, 0xd3, 0x38, 0x68, 0xd1, 0x38, 0x8c,
-	0x80, 0xa2, 0x01, 0x0a, 0x65, 0x45, 0xae, 0xa4, 0x85, 0x45, 0x2e, 0xb3, 0x3b, 0x34, 0xe8, 0xbe,
-	0x41, 0xd0, 0x47, 0x28, 0x7a, 0xd5, 0x07, 0xea, 0x5d, 0x9f, 0xa7, 0xd8, 0x1f, 0x52, 0x34, 0xa2,
-	0xa4, 0x3f, 0x51, 0xee, 0x66, 0x86, 0xb3, 0x33, 0xdf, 0xcc, 0xce, 0xcc, 0x0e, 0x61, 0x10, 0xf2,
-	0x04, 0x05, 0x09, 0x71, 0x94, 0x0a, 0x8e, 0xdc, 0xdd, 0xcb, 0xc3, 0x05, 0x61, 0xc9, 0xa8, 0x14,
-	0xcb, 0xe8, 0xc2, 0xdf, 0x82, 0xde, 0x39, 0x4b, 0xe6, 0x01, 0x7d, 0x9d, 0x51, 0x89, 0xfe, 0x00,
-	0xfa, 0x86, 0x95, 0x29, 0x4f, 0x24, 0xf5, 0xff, 0x07, 0xbb, 0xdf, 0x11, 0x64, 0x97, 0xf4, 0x94,
-	0x2c, 0x97, 0x56, 0xc9, 0xdd, 0x87, 0x66, 0x88, 0x39, 0x8b, 0x3c, 0x67, 0xe8, 0x1c, 0xd5, 0x03,
-	0xc3, 0xf8, 0xfb, 0xe0, 0x56, 0x55, 0xad, 0x81, 0xff, 0x43, 0xfb, 0x44, 0xcc, 0xcf, 0x09, 0x13,
-	0xee, 0x0e, 0xd4, 0x2f, 0xe8, 0x95, 0x3e, 0xd4, 0x0d, 0x14, 0xa9, 0x0c, 0x5d, 0x92, 0x65, 0x46,
-	0xbd, 0xda, 0xd0, 0x39, 0xea, 0x07, 0x86, 0xf1, 0xff, 0x70, 0xa0, 0xa3, 0x6c, 0x9c, 0x88, 0xb9,
-	0x74, 0x6f, 0x40, 0x2b, 0xa6, 0xb8, 0xe0, 0x91, 0x3d, 0x67, 0x39, 0xf7, 0x3e, 0x34, 0x88, 0x98,
-	0x4b, 0xaf, 0x36, 0xac, 0x1f, 0xf5, 0x1e, 0xdc, 0x1a, 0xad, 0x89, 0x6d, 0x64, 0x1d, 0x07, 0x5a,
-	0xd3, 0xbd, 0x05, 0x5d, 0x96, 0x30, 0x64, 0x04, 0xb9, 0xf0, 0xea, 0xda, 0xd8, 0x4a, 0xe0, 0xde,
-	0x81, 0x3e, 0xc9, 0x70, 0x31, 0x11, 0xf4, 0x75, 0xc6, 0x04, 0xf5, 0x1a, 0xc3, 0xfa, 0x51, 0x37,
-	0xe8, 0x29, 0x59, 0x60, 0x44, 0xee, 0x3d, 0xd8, 0x46, 0x41, 0x12, 0x39, 0xa3, 0x62, 0x42, 0x62,
-	0x9e, 0x25, 0xe8, 0x35, 0xb5, 0x99, 0x41, 0x21, 0x3e, 0xd1, 0x52, 0x85, 0x39, 0x24, 0xcb, 0x25,
-	0x15, 0x5e, 0xcb, 0x60, 0x36, 0x9c, 0xff, 0x5f, 0xd8, 0x7a, 0x71, 0x25, 0x15, 0x73, 0x46, 0x49,
-	0x44, 0xc5, 0x3b, 0x12, 0x99, 0x02, 0x9c, 0x67, 0x58, 0x24, 0xfb, 0x73, 0x68, 0x2d, 0xb4, 0xb6,
-	0x56, 0xea, 0x3d, 0xf0, 0xd7, 0x86, 0x7a, 0xcd, 0x6e, 0x60, 0x4f, 0x14, 0x19, 0x37, 0xd9, 0xbd,
-	0x9e, 0xf1, 0x7a, 0x35, 0xe3, 0xaa, 0x08, 0x94, 0x47, 0x7b, 0x67, 0x2f, 0x01, 0x9e, 0xd0, 0x8f,
-	0x03, 0xc0, 0xbf, 0x0b, 0x3d, 0x6d, 0xdb, 0xb8, 0x5a, 0xe1, 0x71, 0xaa, 0x78, 0x7e, 0x84, 0xad,
-	0xaf, 0xe8, 0x92, 0x22, 0xfd, 0x38, 0x18, 0x76, 0x60, 0x50, 0x98, 0xb7, 0x11, 0xff, 0xec, 0xc0,
-	0xf6, 0x53, 0xa4, 0x42, 0x95, 0xc2, 0x26, 0x7c, 0xee, 0x43, 0x53, 0x22, 0x11, 0x58, 0x14, 0xb6,
-	0x66, 0x94, 0x74, 0xc9, 0x62, 0x86, 0x45, 0xf2, 0x35, 0xa3, 0xf0, 0x85, 0x24, 0xf5, 0x1a, 0x43,
-	0xe7, 0xa8, 0x19, 0x28, 0xd2, 0xff, 0x14, 0xfa, 0x05, 0x98, 0xa7, 0x48, 0xe3, 0x6a, 0xe3, 0xf4,
-	0xdf, 0xd7, 0x38, 0xdf, 0xc0, 0xce, 0x2a, 0x08, 0x9b, 0xe0, 0xcf, 0xa0, 0xc9, 0x90, 0xc6, 0xd2,
-	0x73, 0x74, 0xa3, 0xdc, 0x59, 0x1b, 0x44, 0xd5, 0x5b, 0x60, 0xf4, 0xfd, 0x57, 0x30, 0x78, 0x9e,
-	0x51, 0x71, 0x35, 0xce, 0x37, 0x91, 0x10, 0x17, 0x1a, 0xba, 0xd0, 0x6b, 0xba, 0x21, 0x34, 0xed,
-	0x9f, 0xc2, 0x76, 0xe9, 0xc1, 0xa2, 0xbd, 0x0f, 0x35, 0xcc, 0xad, 0xf9, 0xe1, 0x5a, 0xf3, 0x63,
-	0xd5, 0x6a, 0x24, 0x44, 0xc6, 0x93, 0xa0, 0x86, 0xb9, 0xcf, 0x60, 0x57, 0x1b, 0x79, 0xb4, 0xe4,
-	0xe1, 0xc5, 0x26, 0x90, 0x7a, 0xd0, 0x9e, 0x2a, 0x5b, 0x25, 0xd8, 0x82, 0xf5, 0xbf, 0x06, 0xb7,
-	0xea, 0xaa, 0x84, 0xdc, 0xd4, 0x0a, 0xd6, 0xd5, 0xe1, 0x5a, 0x57, 0xe6, 0x88, 0x51, 0xf4, 0xdf,
-	0x38, 0xb0, 0x3d, 0xb6, 0x13, 0x63, 0x43, 0xb9, 0x9d, 0x09, 0x1e, 0x17, 0xb9, 0x55, 0xb4, 0x3b,
-	0x80, 0x1a, 0x72, 0x3b, 0xe5, 0x6a, 0xc8, 0xd5, 0x48, 0xb2, 0x23, 0xab, 0x61, 0x46, 0x92, 0xe1,
-	0x7c, 0x17, 0x76, 0x56, 0x50, 0x6c, 0x33, 0xfc, 0xee, 0xc0, 0xde, 0xa9, 0x75, 0x5b, 0x1d, 0xfb,
-	0x1f, 0x82, 0x51, 0x8d, 0x71, 0x1e, 0x65, 0x4b, 0x6a, 0x51, 0x5a, 0xce, 0x3d, 0x84, 0x4e, 0x71,
-	0xda, 0xa2, 0x2d, 0xf9, 0xca, 0xe8, 0x6f, 0xac, 0x1d, 0xfd, 0xcd, 0xbf, 0x3b, 0xfa, 0xfd, 0xe7,
-	0xb0, 0x7f, 0x3d, 0x20, 0x7b, 0x77, 0x0f, 0xa1, 0x23, 0x2c, 0x6d, 0x63, 0xba, 0xbd, 0xd6, 0x5a,
-	0x71, 0x20, 0x28, 0xd5, 0xfd, 0x5f, 0x1c, 0x38, 0x38, 0x15, 0x5c, 0xca, 0xc2, 0xb0, 0x2e, 0x8d,
-	0x0d, 0xcd, 0xab, 0x4c, 0x30, 0x9b, 0x27, 0x45, 0xfe, 0x8b, 0x80, 0xbf, 0x87, 0xc3, 0x75, 0xe0,
-	0x3e, 0x3c, 0xec, 0x73, 0xe8, 0x94, 0x66, 0x6e, 0x40, 0x4b, 0x22, 0xc1, 0x4c, 0x6a, 0x23, 0xcd,
-	0xc0, 0x72, 0xaa, 0x83, 0x62, 0x2a, 0x25, 0x99, 0x17, 0x97, 0x5d, 0xb0, 0xaa, 0x52, 0xa7, 0x3c,
-	0xba, 0xb2, 0xf3, 0x4f, 0xd3, 0xaa, 0x1b, 0x76, 0x5e, 0x50, 0x7c, 0x96, 0x61, 0xba, 0x99, 0x47,
-	0xaf, 0x1a, 0x5d, 0xed, 0x9f, 0x45, 0xb7, 0x07, 0xbb, 0x15, 0x28, 0x65, 0xc8, 0xee, 0x13, 0x8a,
-	0xc5, 0x42, 0xb2, 0x01, 0x84, 0xfe, 0xaf, 0x0e, 0xb4, 0xc7, 0xf9, 0xd3, 0x24, 0xcd, 0xd0, 0x3d,
-	0x50, 0x68, 0x67, 0x93, 0x72, 0x0b, 0xe8, 0x06, 0x6d, 0x41, 0x67, 0xe3, 0x9c, 0x45, 0xee, 0x6d,
-	0x00, 0xf5, 0x89, 0xcf, 0x66, 0x92, 0x9a, 0x97, 0xa4, 0x19, 0x74, 0x05, 0x9d, 0x3d, 0xd3, 0x02,
-	0xf7, 0x26, 0x74, 0x55, 0xab, 0x4f, 0x48, 0x14, 0x09, 0xbd, 0x88, 0xf4, 0x83, 0x8e, 0x12, 0x9c,
-	0x44, 0x91, 0xa8, 0xf4, 0x7b, 0xab, 0xda, 0xef, 0xee, 0x5d, 0xd8, 0x9a, 0x09, 0xfe, 0x13, 0x4d,
-	0x26, 0x0b, 0xca, 0xe6, 0x0b, 0xf4, 0xda, 0x7a, 0xf3, 0xe8, 0x1b, 0xe1, 0x99, 0x96, 0xf9, 0xaf,
-	0xa0, 0x33, 0xce, 0x4d, 0x16, 0x2a, 0x86, 0x9c, 0x6b, 0x86, 0xfe, 0x03, 0x6d, 0xe4, 0xc6, 0xb7,
-	0x79, 0x83, 0x5a, 0xc8, 0xb5, 0xe7, 0xb7, 0x3c, 0x34, 0xd6, 0x78, 0x78, 0x53, 0x83, 0x5e, 0x65,
-	0x92, 0x97, 0xcf, 0x83, 0xb3, 0x7a, 0x1e, 0xde, 0x3d, 0x88, 0xdd, 0x87, 0xd0, 0xc5, 0x7c, 0xc2,
-	0x54, 0xfe, 0xa4, 0x57, 0x7f, 0x4f, 0x53, 0xd8, 0x24, 0x07, 0x1d, 0x34, 0x84, 0x74, 0xbf, 0x04,
-	0xc0, 0x7c, 0xc2, 0x75, 0x6c, 0x52, 0x2f, 0x79, 0xef, 0x2a, 0x8f, 0x22, 0x03, 0x41, 0x17, 0x2d,
-	0x25, 0x15, 0xcc, 0x88, 0xca, 0x50, 0xe7, 0xb4, 0x1f, 0x68, 0xfa, 0xfa, 0x5a, 0x79, 0xf8, 0x57,
-	0x6b, 0xe5, 0xcd, 0xb7, 0xd6, 0x4a, 0xff, 0xb7, 0x1a, 0x34, 0xf5, 0xfb, 0x50, 0x8d, 0xb8, 0x7e,
-	0x3d, 0xe2, 0x03, 0xe8, 0xa4, 0x82, 0x4e, 0x16, 0x44, 0x2e, 0xec, 0x30, 0x6c, 0xa7, 0x82, 0x9e,
-	0x11, 0xb9, 0x50, 0x13, 0x34, 0x15, 0x3c, 0xe5, 0x92, 0x96, 0x55, 0x50, 0xf0, 0x0a, 0xaf, 0x64,
-	0xf3, 0xc4, 0xd6, 0x80, 0xa6, 0xd5, 0x85, 0xa6, 0xd9, 0x54, 0xed, 0x13, 0x6d, 0x73, 0x6f, 0x86,
-	0x53, 0x72, 0x7b, 0x61, 0x5d, 0x7d, 0x61, 0x96, 0x53, 0xf1, 0x21, 0x8b, 0xa9, 0x44, 0x12, 0xa7,
-	0x1e, 0xe8, 0x4f, 0x2b, 0x81, 0x5a, 0x44, 0xd4, 0x65, 0x49, 0xaf, 0xa7, 0x03, 0x33, 0x8c, 0x82,
-	0x8b, 0xf9, 0x24, 0xd4, 0x65, 0xd3, 0xd7, 0x75, 0xdb, 0xc6, 0xfc, 0x54, 0xd7, 0xcd, 0x01, 0x74,
-	0x58, 0x32, 0x41, 0x91, 0x25, 0x17, 0xde, 0x60, 0xe8, 0x1c, 0x75, 0x82, 0x36, 0x4b, 0xc6, 0x8a,
-	0x55, 0x05, 0x9d, 0xd0, 0x1c, 0x4d, 0x94, 0xdb, 0xe6, 0x31, 0x50, 0x02, 0x15, 0xa6, 0x2f, 0xe0,
-	0xf0, 0x09, 0xc5, 0x93, 0x50, 0x1b, 0x55, 0x85, 0x46, 0xa5, 0xa4, 0x72, 0x43, 0x0f, 0x3e, 0x31,
-	0x66, 0x8b, 0x3a, 0xb3, 0xac, 0xff, 0x05, 0xdc, 0x5c, 0xeb, 0xd3, 0xce, 0xbf, 0x5b, 0xd0, 0x25,
-	0x85, 0x50, 0xaf, 0x57, 0xdd, 0x60, 0x25, 0xf0, 0xa7, 0x30, 0x38, 0xe7, 0x12, 0xbf, 0xe5, 0xf3,
-	0x0d, 0x2d, 0x94, 0x34, 0x41, 0x71, 0x65, 0x21, 0x1a, 0xc6, 0xbf, 0x07, 0xdb, 0xa5, 0x8f, 0xd5,
-	0x42, 0x6d, 0x14, 0x9d, 0xaa, 0xe2, 0x25, 0xec, 0x3c, 0x8e, 0x19, 0x3e, 0xbe, 0xa4, 0x09, 0x6e,
-	0x68, 0xe5, 0x48, 0x48, 0x5c, 0xcc, 0x77, 0x4d, 0xaf, 0x1d, 0xee, 0x7b, 0xb0, 0x5b, 0xf1, 0x6b,
-	0x20, 0x3e, 0xfa, 0x01, 0x0e, 0x43, 0x1e, 0x8f, 0xa6, 0x84, 0x45, 0xd9, 0x28, 0xcf, 0x52, 0x2a,
-	0x4a, 0x97, 0xe9, 0xf4, 0xac, 0xfe, 0xf2, 0xe1, 0x9c, 0xe1, 0x22, 0x9b, 0x8e, 0x42, 0x1e, 0x1f,
-	0xeb, 0xcf, 0x1a, 0x96, 0x25, 0xb9, 0xa0, 0xc7, 0x17, 0x54, 0x24, 0x74, 0x79, 0x5c, 0x1c, 0x3a,
-	0x9e, 0x0a, 0x16, 0xcd, 0xe9, 0x71, 0x3a, 0x9d, 0xb6, 0xf4, 0x9f, 0xee, 0x27, 0x7f, 0x06, 0x00,
-	0x00, 0xff, 0xff, 0x17, 0xf0, 0xb8, 0x06, 0xfb, 0x0e, 0x00, 0x00,
+	// 1351 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0xd6, 0xfa, 0xdf, 0xc7, 0x8e, 0xe3, 0x6c, 0xa2, 0xb2, 0x4d, 0x5b, 0x94, 0x4e, 0x85, 0x1a,
+	0x6e, 0x9c, 0x52, 0x24, 0x50, 0x81, 0x9b, 0x34, 0x94, 0xa6, 0x02, 0xd1, 0x74, 0x6b, 0x09, 0x51,
+	0x09, 0xb9, 0xe3, 0xdd, 0xb1, 0x3d, 0x8a, 0x77, 0x67, 0x3b, 0x33, 0x1b, 0x6d, 0xb8, 0xe3, 0xb2,
+	0xe2, 0x11, 0x10, 0x57, 0xbc, 0x13, 0xef, 0xc2, 0x1d, 0x9a, 0x9f, 0x5d, 0xaf, 0x55, 0xa7, 0x50,
+	0x75, 0x7b, 0x37, 0xdf, 0xf1, 0xcc, 0x39, 0xdf, 0x39, 0x73, 0x7e, 0x66, 0x0d, 0x83, 0x80, 0xc5,
+	0x92, 0xe3, 0x40, 0x8e, 0x12, 0xce, 0x24, 0x73, 0x77, 0xb3, 0x60, 0x81, 0x69, 0x3c, 0x2a, 0xc4,
+	0x22, 0x3c, 0x47, 0x5b, 0xd0, 0x3b, 0xa3, 0xf1, 0xdc, 0x27, 0xaf, 0x52, 0x22, 0x24, 0x1a, 0x40,
+	0xdf, 0x40, 0x91, 0xb0, 0x58, 0x10, 0xf4, 0x29, 0xec, 0xfc, 0x88, 0x25, 0xbd, 0x20, 0x27, 0x78,
+	0xb9, 0xb4, 0x9b, 0xdc, 0x3d, 0x68, 0x06, 0x32, 0xa3, 0xa1, 0xe7, 0x1c, 0x38, 0x87, 0x75, 0xdf,
+	0x00, 0xb4, 0x07, 0x6e, 0x79, 0xab, 0x55, 0xf0, 0x19, 0xb4, 0x8f, 0xf9, 0xfc, 0x0c, 0x53, 0xee,
+	0x0e, 0xa1, 0x7e, 0x4e, 0x2e, 0xf5, 0xa1, 0xae, 0xaf, 0x96, 0x4a, 0xd1, 0x05, 0x5e, 0xa6, 0xc4,
+	0xab, 0x1d, 0x38, 0x87, 0x7d, 0xdf, 0x00, 0xf4, 0xb7, 0x03, 0x1d, 0xa5, 0xe3, 0x98, 0xcf, 0x85,
+	0x7b, 0x0d, 0x5a, 0x11, 0x91, 0x0b, 0x16, 0xda, 0x73, 0x16, 0xb9, 0xf7, 0xa0, 0x81, 0xf9, 0x5c,
+	0x78, 0xb5, 0x83, 0xfa, 0x61, 0xef, 0xfe, 0xcd, 0xd1, 0x06, 0xdf, 0x46, 0xd6, 0xb0, 0xaf, 0x77,
+	0xba, 0x37, 0xa1, 0x4b, 0x63, 0x2a, 0x29, 0x96, 0x8c, 0x7b, 0x75, 0xad, 0x6c, 0x25, 0x70, 0x6f,
+	0x43, 0x1f, 0xa7, 0x72, 0x31, 0xe1, 0xe4, 0x55, 0x4a, 0x39, 0xf1, 0x1a, 0x07, 0xf5, 0xc3, 0xae,
+	0xdf, 0x53, 0x32, 0xdf, 0x88, 0xdc, 0xbb, 0xb0, 0x2d, 0x39, 0x8e, 0xc5, 0x8c, 0xf0, 0x09, 0x8e,
+	0x58, 0x1a, 0x4b, 0xaf, 0xa9, 0xd5, 0x0c, 0x72, 0xf1, 0xb1, 0x96, 0x2a, 0xce, 0x01, 0x5e, 0x2e,
+	0x09, 0xf7, 0x5a, 0x86, 0xb3, 0x41, 0xe8, 0x13, 0xd8, 0x7a, 0x7e, 0x29, 0x14, 0x38, 0x25, 0x38,
+	0x24, 0xfc, 0x8a, 0x40, 0x26, 0x00, 0x67, 0xa9, 0xcc, 0x83, 0xfd, 0x15, 0xb4, 0x16, 0x7a, 0xb7,
+	0xde, 0xd4, 0xbb, 0x8f, 0x36, 0xba, 0xba, 0xa6, 0xd7, 0xb7, 0x27, 0xf2, 0x88, 0x9b, 0xe8, 0xae,
+	0x47, 0xbc, 0x5e, 0x8e, 0xb8, 0x4a, 0x82, 0x54, 0x16, 0x77, 0xf6, 0x02, 0xe0, 0x31, 0xf9, 0x30,
+	0x04, 0xd0, 0x1d, 0xe8, 0x3d, 0x26, 0x85, 0xa9, 0x15, 0x1f, 0xa7, 0xcc, 0xe7, 0x17, 0xd8, 0xfa,
+	0x96, 0x2c, 0x89, 0x24, 0x1f, 0x86, 0xc3, 0x10, 0x06, 0xb9, 0x7a, 0xeb, 0xf1, 0xef, 0x0e, 0x6c,
+	0x3f, 0x91, 0x84, 0xab, 0x54, 0xa8, 0xc2, 0xe6, 0x1e, 0x34, 0x85, 0xc4, 0x5c, 0xe6, 0x89, 0xad,
+	0x81, 0x92, 0x2e, 0x69, 0x44, 0x65, 0x1e, 0x7c, 0x0d, 0x14, 0xbf, 0x00, 0x27, 0x5e, 0xe3, 0xc0,
+	0x39, 0x6c, 0xfa, 0x6a, 0x89, 0xbe, 0x80, 0x7e, 0x4e, 0xe6, 0x89, 0x24, 0x51, 0xb9, 0x70, 0xfa,
+	0x6f, 0x2b, 0x9c, 0xef, 0x61, 0xb8, 0x72, 0xc2, 0x06, 0xf8, 0x4b, 0x68, 0x52, 0x49, 0x22, 0xe1,
+	0x39, 0xba, 0x50, 0x6e, 0x6f, 0x74, 0xa2, 0x6c, 0xcd, 0x37, 0xfb, 0xd1, 0x4b, 0x18, 0x3c, 0x4b,
+	0x09, 0xbf, 0x1c, 0x67, 0x55, 0x04, 0xc4, 0x85, 0x86, 0x4e, 0xf4, 0x9a, 0x2e, 0x08, 0xbd, 0x46,
+	0x27, 0xb0, 0x5d, 0x58, 0xb0, 0x6c, 0xef, 0x41, 0x4d, 0x66, 0x56, 0xfd, 0xc1, 0x46, 0xf5, 0x63,
+	0x55, 0x6a, 0x38, 0x90, 0x94, 0xc5, 0x7e, 0x4d, 0x66, 0x88, 0xc2, 0x8e, 0x56, 0xf2, 0x70, 0xc9,
+	0x82, 0xf3, 0x2a, 0x98, 0x7a, 0xd0, 0x9e, 0x2a, 0x5d, 0x05, 0xd9, 0x1c, 0xa2, 0xef, 0xc0, 0x2d,
+	0x9b, 0x2a, 0x28, 0x37, 0xf5, 0x06, 0x6b, 0x6a, 0x7f, 0xa3, 0x29, 0x73, 0xc4, 0x6c, 0x44, 0x33,
+	0x18, 0xaa, 0x12, 0xc0, 0x71, 0xc8, 0xa2, 0x2a, 0x18, 0x5f, 0x53, 0x67, 0xe9, 0x7c, 0x61, 0xb2,
+	0xad, 0xee, 0x5b, 0x84, 0x08, 0xec, 0x94, 0xec, 0x58, 0xba, 0xd7, 0xa0, 0xc5, 0xb5, 0xc4, 0xa6,
+	0x93, 0x45, 0x57, 0xbb, 0xed, 0x7e, 0x0c, 0x70, 0x41, 0x38, 0x9d, 0x51, 0x3c, 0x5d, 0x9a, 0xbe,
+	0xd1, 0xf1, 0x4b, 0x12, 0xf4, 0xda, 0x81, 0xed, 0xb1, 0x6d, 0x80, 0x15, 0xa5, 0xca, 0x8c, 0xb3,
+	0x28, 0x4f, 0x15, 0xb5, 0x76, 0x07, 0x50, 0x93, 0xcc, 0x36, 0xed, 0x9a, 0x64, 0xca, 0x0b, 0xdb,
+	0x81, 0x1b, 0x5a, 0x66, 0x11, 0x72, 0x61, 0xb8, 0xa2, 0x62, 0x6b, 0xfb, 0x37, 0x47, 0xc7, 0xe1,
+	0x21, 0x5e, 0xe2, 0x38, 0x20, 0x15, 0xa5, 0x08, 0x0e, 0x43, 0x4e, 0x84, 0xc8, 0x63, 0x65, 0xa1,
+	0xe2, 0x35, 0xe3, 0xec, 0x57, 0x12, 0xdb, 0x38, 0x59, 0x84, 0x46, 0xe0, 0x96, 0x29, 0xd8, 0xbb,
+	0x50, 0x31, 0x37, 0x22, 0x3b, 0xdc, 0x72, 0x88, 0xfe, 0x71, 0x60, 0xf7, 0xc4, 0x12, 0x29, 0x4f,
+	0xde, 0xf7, 0x4c, 0x93, 0x88, 0x85, 0xe9, 0x92, 0x58, 0xd2, 0x16, 0xb9, 0xfb, 0xd0, 0xc9, 0x4f,
+	0xdb, 0x08, 0x17, 0xb8, 0x34, 0x7d, 0x1b, 0x1b, 0xa7, 0x6f, 0xf3, 0x7f, 0x4f, 0xdf, 0xd5, 0x8d,
+	0xb5, 0xca, 0x37, 0xa6, 0x7a, 0x9b, 0xe4, 0x97, 0x5e, 0x5b, 0x87, 0x4b, 0x2d, 0xd1, 0x33, 0xd8,
+	0x5b, 0x77, 0xdd, 0x46, 0xeb, 0x01, 0x74, 0xb8, 0x5d, 0x5b, 0xef, 0x6f, 0x6d, 0xb4, 0x9b, 0x1f,
+	0xf0, 0x8b, 0xed, 0xe8, 0x0f, 0x07, 0xae, 0x9f, 0x70, 0x26, 0x44, 0xae, 0x58, 0xd7, 0x71, 0x45,
+	0xc3, 0x25, 0xe5, 0xd4, 0x46, 0x54, 0x2d, 0xdf, 0x3d, 0x34, 0xe8, 0x27, 0xd8, 0xdf, 0x44, 0xee,
+	0xfd, 0xdd, 0x3e, 0x83, 0x4e, 0xb9, 0xee, 0x85, 0xc4, 0x32, 0x15, 0x5a, 0x49, 0xd3, 0xb7, 0x48,
+	0xe5, 0x60, 0x44, 0x84, 0xc0, 0xf3, 0x3c, 0x2d, 0x72, 0xa8, 0xea, 0x70, 0xca, 0xc2, 0x4b, 0x3b,
+	0xac, 0xf4, 0x5a, 0xd5, 0xfa, 0xf0, 0x39, 0x91, 0x4f, 0x53, 0x99, 0x54, 0xf3, 0x42, 0x29, 0x7b,
+	0x57, 0x7b, 0x37, 0xef, 0x76, 0x61, 0xa7, 0x44, 0xa5, 0x70, 0x59, 0x15, 0x5a, 0xfe, 0x7a, 0xac,
+	0x80, 0x21, 0xfa, 0xd3, 0x81, 0xf6, 0x38, 0x7b, 0x12, 0x27, 0xa9, 0x74, 0xaf, 0x2b, 0xb6, 0xb3,
+	0x49, 0xf1, 0x64, 0xeb, 0xfa, 0x6d, 0x4e, 0x66, 0xe3, 0x8c, 0x86, 0xee, 0x2d, 0x00, 0xf5, 0x13,
+	0x9b, 0xcd, 0x04, 0x31, 0x8d, 0xb8, 0xe9, 0x77, 0x39, 0x99, 0x3d, 0xd5, 0x02, 0xf7, 0x06, 0x74,
+	0x55, 0x23, 0x9b, 0xa8, 0x46, 0xa1, 0x5f, 0x8d, 0x7d, 0xbf, 0xa3, 0x04, 0xc7, 0x61, 0xc8, 0xaf,
+	0xac, 0x8d, 0x3b, 0xb0, 0x65, 0xfa, 0xc7, 0xc4, 0xf6, 0xf7, 0xb6, 0xee, 0xef, 0x7d, 0x23, 0x3c,
+	0xd5, 0x32, 0xf4, 0x12, 0x3a, 0xe3, 0xcc, 0x44, 0xa1, 0xa4, 0xc8, 0x59, 0x53, 0xf4, 0x11, 0xb4,
+	0x25, 0x33, 0xb6, 0xcd, 0x83, 0xa1, 0x25, 0x99, 0xb6, 0xfc, 0x86, 0x85, 0xc6, 0x06, 0x0b, 0xaf,
+	0x6b, 0xd0, 0x2b, 0x8d, 0xdd, 0x62, 0x96, 0x3b, 0xab, 0x59, 0xfe, 0x96, 0xf1, 0xf1, 0x00, 0xba,
+	0x32, 0x9b, 0x50, 0x15, 0x3f, 0xe1, 0xd5, 0xdf, 0x52, 0x14, 0x36, 0xc8, 0x7e, 0x47, 0x9a, 0x85,
+	0x70, 0xbf, 0x01, 0x90, 0xd9, 0x84, 0x69, 0xdf, 0x84, 0x7e, 0x91, 0x5f, 0x95, 0x1e, 0x79, 0x04,
+	0xfc, 0xae, 0xb4, 0x2b, 0xa1, 0x68, 0x86, 0x44, 0x04, 0x3a, 0xa6, 0x7d, 0x5f, 0xaf, 0xd7, 0xbf,
+	0x01, 0xf6, 0xff, 0xeb, 0x1b, 0xe0, 0xc6, 0x1b, 0xdf, 0x00, 0xe8, 0xaf, 0x1a, 0x34, 0xf5, 0x30,
+	0x2f, 0x7b, 0x5c, 0x5f, 0xf7, 0xf8, 0x3a, 0x74, 0x12, 0x4e, 0x26, 0x0b, 0x2c, 0x16, 0xb6, 0x6d,
+	0xb6, 0x13, 0x4e, 0x4e, 0xb1, 0x58, 0xa8, 0x5e, 0x9b, 0x70, 0x96, 0x30, 0x41, 0x8a, 0x2c, 0xc8,
+	0xb1, 0xe2, 0x2b, 0xe8, 0x3c, 0xb6, 0x39, 0xa0, 0xd7, 0xea, 0x42, 0x93, 0x74, 0xaa, 0x1e, 0x7f,
+	0x6d, 0x73, 0x6f, 0x06, 0x95, 0x46, 0x7e, 0xb7, 0x3c, 0xf2, 0x95, 0x7f, 0x92, 0x46, 0x44, 0x48,
+	0x1c, 0x25, 0x1e, 0xe8, 0x9f, 0x56, 0x02, 0xf5, 0x6a, 0x54, 0x97, 0x25, 0xbc, 0x9e, 0x76, 0xcc,
+	0x00, 0x45, 0x57, 0x66, 0x93, 0x40, 0xa7, 0x4d, 0x5f, 0xe7, 0x6d, 0x5b, 0x66, 0x27, 0x0a, 0xaa,
+	0x9f, 0x68, 0x3c, 0x91, 0x3c, 0x8d, 0xcf, 0xbd, 0x81, 0xee, 0xd0, 0x6d, 0x1a, 0x8f, 0x15, 0x54,
+	0x09, 0x1d, 0x93, 0x4c, 0x1a, 0x2f, 0xb7, 0xcd, 0xd8, 0x50, 0x02, 0xe5, 0x26, 0xe2, 0xb0, 0xff,
+	0x98, 0xc8, 0xe3, 0x40, 0x2b, 0x3d, 0x36, 0xb3, 0x91, 0x88, 0xaa, 0x46, 0xaf, 0x51, 0x5b, 0x8c,
+	0x5e, 0x03, 0xd1, 0xd7, 0x70, 0x63, 0xa3, 0x4d, 0xdb, 0xff, 0x6e, 0x42, 0x17, 0xe7, 0x42, 0xfd,
+	0x16, 0xee, 0xfa, 0x2b, 0x01, 0x9a, 0xc2, 0xe0, 0x8c, 0x09, 0xf9, 0x03, 0x9b, 0x57, 0xf4, 0xfa,
+	0x27, 0xb1, 0x9a, 0x6a, 0x86, 0xa2, 0x01, 0xe8, 0x2e, 0x6c, 0x17, 0x36, 0x56, 0x5f, 0x3f, 0x66,
+	0xa3, 0x53, 0xde, 0x78, 0x01, 0xc3, 0x47, 0x11, 0x95, 0x8f, 0x2e, 0x48, 0x2c, 0x2b, 0x7a, 0x50,
+	0xc5, 0x38, 0xca, 0xfb, 0xbb, 0x5e, 0x6f, 0x6c, 0xee, 0xbb, 0xb0, 0x53, 0xb2, 0x6b, 0x28, 0x3e,
+	0xfc, 0x19, 0xf6, 0x03, 0x16, 0x8d, 0xa6, 0x98, 0x86, 0xe9, 0x28, 0x4b, 0x13, 0xc2, 0x0b, 0x93,
+	0xc9, 0xf4, 0xb4, 0xfe, 0xe2, 0xc1, 0x9c, 0xca, 0x45, 0x3a, 0x1d, 0x05, 0x2c, 0x3a, 0xd2, 0x3f,
+	0x6b, 0x5a, 0x76, 0xc9, 0x38, 0x39, 0x3a, 0x27, 0x3c, 0x26, 0xcb, 0xa3, 0xfc, 0xd0, 0xd1, 0x94,
+	0xd3, 0x70, 0x4e, 0x8e, 0x92, 0xe9, 0xb4, 0xa5, 0xff, 0x96, 0xf8, 0xfc, 0xdf, 0x01, 0x00, 0x8e,
+	0xc1, 0xa3, 0x48, 0xa8, 0x10, 0x00, 0x00,
 }
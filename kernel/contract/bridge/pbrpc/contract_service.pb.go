@@ -28,49 +28,51 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 func init() { proto.RegisterFile("contract_service.proto", fileDescriptor_e663a77702825514) }
 
 var fileDescriptor_e663a77702825514 = []byte{
-	// 515 bytes of a gzipped FileDescriptorProto
+	// 545 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x95, 0x41, 0x6f, 0xd3, 0x30,
-	0x14, 0xc7, 0x85, 0x98, 0x18, 0x7d, 0x9b, 0x38, 0x78, 0x12, 0x87, 0x4a, 0x88, 0x01, 0x63, 0x83,
-	0x8b, 0x83, 0xc6, 0x95, 0x4b, 0x57, 0xa6, 0x82, 0x40, 0x5d, 0x61, 0x45, 0x93, 0x2a, 0x10, 0x4a,
-	0x9c, 0x47, 0x17, 0x9a, 0xc6, 0xc1, 0x7e, 0x2e, 0xdd, 0xd7, 0xe2, 0xb3, 0xf1, 0x01, 0x50, 0x5d,
-	0x3b, 0xab, 0x98, 0x93, 0xf6, 0xb2, 0x5b, 0xdb, 0xff, 0xef, 0xff, 0xb3, 0xe3, 0xbc, 0x26, 0xf0,
-	0x50, 0xc8, 0x82, 0x54, 0x2c, 0xe8, 0xbb, 0x46, 0x35, 0xcb, 0x04, 0xf2, 0x52, 0x49, 0x92, 0x6c,
-	0x6f, 0x2e, 0x2e, 0xe3, 0xac, 0xe0, 0x3e, 0xe6, 0x7a, 0x26, 0xda, 0x0f, 0xaa, 0x6f, 0x16, 0x3a,
-	0xfe, 0x73, 0x07, 0xa0, 0x1f, 0x53, 0x36, 0xc3, 0xae, 0x4c, 0x91, 0x5d, 0xc0, 0x56, 0x37, 0xce,
-	0x73, 0x76, 0xc8, 0x6f, 0x94, 0xd3, 0x09, 0x77, 0x60, 0x9c, 0xe7, 0x9f, 0xf1, 0x97, 0x41, 0x4d,
-	0xed, 0xa3, 0xb5, 0x9c, 0x2e, 0x65, 0xa1, 0x91, 0x7d, 0x80, 0xad, 0x41, 0x56, 0x8c, 0xd9, 0x7e,
-	0xb0, 0xb0, 0x88, 0xbc, 0xf2, 0x49, 0x03, 0xb1, 0x94, 0x1d, 0xff, 0x05, 0xd8, 0x3e, 0xbf, 0xd2,
-	0x62, 0xb1, 0xd3, 0x3e, 0xb4, 0x06, 0x86, 0xce, 0x92, 0x9f, 0x28, 0x88, 0x3d, 0x0e, 0x77, 0x0d,
-	0x79, 0xf9, 0x7e, 0x3d, 0xe0, 0x36, 0xda, 0x87, 0x56, 0x0f, 0x9b, 0x7d, 0x3d, 0x5c, 0xe3, 0xb3,
-	0x80, 0xf3, 0x5d, 0xc0, 0xee, 0x5b, 0xcc, 0x91, 0xd0, 0x29, 0x9f, 0x06, 0x1b, 0x4b, 0xc4, 0x5b,
-	0x9f, 0x35, 0x32, 0x4e, 0x3c, 0x82, 0x9d, 0x3e, 0xfe, 0x7e, 0x4f, 0xa8, 0x62, 0x92, 0x8a, 0x1d,
-	0x04, 0x3b, 0x3e, 0xf6, 0xe6, 0xe7, 0x6b, 0x28, 0xe7, 0x1e, 0xc2, 0xf6, 0x27, 0x83, 0xea, 0x6a,
-	0x38, 0x67, 0xe1, 0xbd, 0xb8, 0xd4, 0x6b, 0x0f, 0x9a, 0x21, 0x67, 0xfd, 0x06, 0x60, 0x7f, 0x3a,
-	0xc9, 0xa5, 0x98, 0xd4, 0x8c, 0xd8, 0x35, 0xd0, 0x3c, 0x62, 0xab, 0x5c, 0x75, 0xd2, 0xf7, 0x87,
-	0x2a, 0x2e, 0xf4, 0x0f, 0xac, 0x3b, 0x0d, 0x1f, 0x37, 0x9f, 0xc6, 0x35, 0xe5, 0xc4, 0x02, 0x76,
-	0xbb, 0x0e, 0xb0, 0x7f, 0x8e, 0x17, 0xc1, 0xda, 0x2a, 0xe2, 0x17, 0x78, 0xb9, 0x01, 0xe9, 0x16,
-	0x31, 0xc0, 0xba, 0x4a, 0x6a, 0xed, 0x43, 0x7b, 0x81, 0x8c, 0x87, 0x05, 0x37, 0x40, 0xbf, 0x60,
-	0xb4, 0x31, 0xef, 0x96, 0x9d, 0xc3, 0x5e, 0x0f, 0xa9, 0x23, 0x84, 0x34, 0x05, 0x75, 0xd2, 0x54,
-	0xa1, 0xd6, 0xa8, 0x59, 0x54, 0x37, 0xd7, 0xff, 0x93, 0x7e, 0xe1, 0x57, 0x9b, 0x17, 0x6e, 0xe1,
-	0x89, 0xb0, 0x18, 0xd8, 0x81, 0xd4, 0xf4, 0x51, 0x8e, 0x6b, 0x06, 0xd6, 0xa5, 0xcd, 0x03, 0x5b,
-	0x41, 0xce, 0xfa, 0x05, 0x76, 0x7a, 0x68, 0x6f, 0x53, 0x47, 0x8d, 0x35, 0x3b, 0xaa, 0xbb, 0x46,
-	0x4f, 0x78, 0xfb, 0xa3, 0xf0, 0x5d, 0xf0, 0x9e, 0x11, 0xb4, 0xce, 0x91, 0xce, 0x0c, 0x95, 0x86,
-	0x58, 0x78, 0x06, 0xab, 0xdc, 0x2b, 0x0f, 0xd7, 0x61, 0xd5, 0x53, 0xa1, 0x75, 0x3a, 0xcd, 0xe8,
-	0x74, 0x86, 0x45, 0x9d, 0xbb, 0xca, 0x9b, 0xdd, 0x2b, 0xd8, 0xd2, 0x7d, 0xf2, 0x15, 0xda, 0x42,
-	0x4e, 0x79, 0x12, 0x67, 0xa9, 0xe1, 0x73, 0x53, 0xa2, 0xaa, 0x1a, 0x65, 0xf2, 0xee, 0xee, 0xe8,
-	0xcd, 0x38, 0xa3, 0x4b, 0x93, 0x70, 0x21, 0xa7, 0x91, 0x8d, 0xad, 0xd5, 0x7d, 0x94, 0x0a, 0xa3,
-	0x09, 0xaa, 0x02, 0xf3, 0xc8, 0x97, 0xa2, 0x44, 0x65, 0xe9, 0x18, 0xa3, 0x32, 0x51, 0xa5, 0x48,
-	0xee, 0xd9, 0x17, 0xd2, 0xeb, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xca, 0x68, 0xbe, 0xa6, 0xcf,
-	0x06, 0x00, 0x00,
+	0x14, 0xc7, 0x85, 0x98, 0x18, 0xf3, 0x2a, 0x0e, 0x9e, 0xc4, 0xa1, 0x12, 0x62, 0xc0, 0x36, 0xe0,
+	0x92, 0xa0, 0x71, 0xe5, 0xd2, 0x96, 0x29, 0x20, 0x50, 0x57, 0xb6, 0xa2, 0x49, 0x15, 0x08, 0x39,
+	0xce, 0xa3, 0x0b, 0x4d, 0xed, 0x60, 0x3f, 0x97, 0xee, 0x53, 0x21, 0xf1, 0x09, 0x51, 0x53, 0x3b,
+	0xad, 0x58, 0xec, 0xf6, 0xc2, 0x6d, 0xeb, 0xff, 0xf7, 0x7e, 0xb6, 0xde, 0xf3, 0x6b, 0xc9, 0x43,
+	0x2e, 0x05, 0x2a, 0xc6, 0xf1, 0x9b, 0x06, 0x35, 0xcb, 0x39, 0x44, 0xa5, 0x92, 0x28, 0xe9, 0xc1,
+	0x9c, 0x5f, 0xb3, 0x5c, 0x44, 0x2e, 0x8e, 0xf4, 0x8c, 0xb7, 0x1f, 0xd4, 0xff, 0x55, 0xd0, 0xe9,
+	0x9f, 0x3b, 0x84, 0xf4, 0x19, 0xe6, 0x33, 0xe8, 0xc9, 0x0c, 0xe8, 0x15, 0xd9, 0xe9, 0xb1, 0xa2,
+	0xa0, 0x27, 0xd1, 0xad, 0xe2, 0x6c, 0x12, 0x59, 0x90, 0x15, 0xc5, 0x05, 0xfc, 0x34, 0xa0, 0xb1,
+	0xfd, 0x7c, 0x23, 0xa7, 0x4b, 0x29, 0x34, 0xd0, 0x0f, 0x64, 0x67, 0x90, 0x8b, 0x31, 0x3d, 0x6c,
+	0x2c, 0x58, 0x44, 0x4e, 0xf9, 0x24, 0x40, 0x2c, 0x65, 0xa7, 0xbf, 0x5b, 0x64, 0xf7, 0xf2, 0x46,
+	0xf3, 0xc5, 0x4d, 0xfb, 0x64, 0x6f, 0x60, 0xf0, 0x3c, 0xfd, 0x01, 0x1c, 0xe9, 0xe3, 0xe6, 0x5a,
+	0x83, 0x4e, 0x7e, 0xe8, 0x07, 0xec, 0x45, 0xfb, 0x64, 0x2f, 0x81, 0xb0, 0x2f, 0x81, 0x0d, 0xbe,
+	0x04, 0x56, 0xbe, 0x2b, 0xd2, 0x7a, 0x0b, 0x05, 0x20, 0x58, 0xe5, 0xd3, 0xc6, 0x8a, 0x25, 0xe2,
+	0xac, 0xcf, 0x82, 0x8c, 0x15, 0x8f, 0xc8, 0x7e, 0x1f, 0x7e, 0xbd, 0x47, 0x50, 0x0c, 0xa5, 0xa2,
+	0x47, 0x8d, 0x35, 0x2e, 0x76, 0xe6, 0xe3, 0x0d, 0x94, 0x75, 0x0f, 0xc9, 0xee, 0x27, 0x03, 0xea,
+	0x66, 0x38, 0xa7, 0xcd, 0x77, 0xb1, 0xa9, 0xd3, 0x1e, 0x85, 0x21, 0x6b, 0xfd, 0x4a, 0x48, 0xf5,
+	0x51, 0xb7, 0x90, 0x7c, 0xe2, 0x79, 0x62, 0x2b, 0x20, 0xfc, 0xc4, 0xd6, 0xb9, 0xba, 0x21, 0x8b,
+	0xc9, 0x5d, 0x30, 0x91, 0xc9, 0x29, 0x3d, 0xf6, 0x0e, 0xa6, 0xca, 0x9d, 0xfc, 0x64, 0x13, 0x56,
+	0x4f, 0xf1, 0xfe, 0x50, 0x31, 0xa1, 0xbf, 0x83, 0xaf, 0xd3, 0x2e, 0x0e, 0x77, 0x7a, 0x45, 0xad,
+	0x7a, 0x92, 0x00, 0x76, 0x59, 0xc1, 0x04, 0x07, 0xea, 0xbd, 0x8e, 0x05, 0xc2, 0x3d, 0x59, 0xe7,
+	0xac, 0x9e, 0x93, 0x56, 0xcf, 0x22, 0xd5, 0x5e, 0xbf, 0x68, 0x2c, 0x5c, 0x47, 0xdc, 0x11, 0x2f,
+	0xb7, 0x20, 0xed, 0x21, 0x86, 0xd0, 0x9e, 0x92, 0x5a, 0xbb, 0xb0, 0x9a, 0x0d, 0x8d, 0x9a, 0x05,
+	0xb7, 0x40, 0x77, 0x60, 0xbc, 0x35, 0x6f, 0x8f, 0x9d, 0x93, 0x83, 0x04, 0xb0, 0xc3, 0xb9, 0x34,
+	0x02, 0x3b, 0x59, 0xa6, 0x40, 0x6b, 0xd0, 0x34, 0xf6, 0xf5, 0xe6, 0x5f, 0xd2, 0x1d, 0xfc, 0x6a,
+	0xfb, 0x82, 0xff, 0xf0, 0x65, 0xb6, 0xd8, 0xb5, 0x81, 0xd4, 0xf8, 0x51, 0x8e, 0x3d, 0xbb, 0x66,
+	0xd3, 0xf0, 0xae, 0xd5, 0x90, 0xb5, 0x7e, 0x26, 0xfb, 0x09, 0x54, 0x63, 0xea, 0xa8, 0xb1, 0xa6,
+	0xde, 0x07, 0xe3, 0x08, 0x67, 0x7f, 0xd4, 0x3c, 0x05, 0xe7, 0x19, 0x91, 0xbd, 0x4b, 0xc0, 0x73,
+	0x83, 0xa5, 0x41, 0xcf, 0x8e, 0xd5, 0x79, 0x78, 0xc7, 0xd6, 0xb0, 0xd5, 0xfe, 0x9e, 0x4d, 0x73,
+	0x3c, 0x9b, 0x81, 0xf0, 0xb9, 0xeb, 0x3c, 0xec, 0x5e, 0xc3, 0x96, 0xee, 0xee, 0x17, 0xd2, 0xe6,
+	0x72, 0x1a, 0xa5, 0x2c, 0xcf, 0x4c, 0x34, 0x37, 0x25, 0xa8, 0xba, 0xa2, 0x4c, 0xdf, 0xdd, 0x1d,
+	0xbd, 0x19, 0xe7, 0x78, 0x6d, 0xd2, 0x88, 0xcb, 0x69, 0x5c, 0xc5, 0x95, 0xd5, 0xfe, 0x29, 0x15,
+	0xc4, 0x13, 0x50, 0x02, 0x8a, 0xd8, 0x15, 0xc5, 0xa9, 0xca, 0xb3, 0x31, 0xc4, 0x65, 0xaa, 0x4a,
+	0x9e, 0xde, 0xab, 0x7e, 0x4b, 0x5f, 0xff, 0x1d, 0x00, 0xa9, 0x72, 0xdc, 0xac, 0x8a, 0x07, 0x00,
+	0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ context.Context
-var _ grpc.ClientConnInterface
+var _ grpc.ClientConn
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+const _ = grpc.SupportPackageIsVersion4
 
 // NativeCodeClient is the client API for NativeCode service.
 //
@@ -81,10 +83,10 @@ type NativeCodeClient interface {
 }
 
 type nativeCodeClient struct {
-	cc grpc.ClientConnInterface
+	cc *grpc.ClientConn
 }
 
-func NewNativeCodeClient(cc grpc.ClientConnInterface) NativeCodeClient {
+func NewNativeCodeClient(cc *grpc.ClientConn) NativeCodeClient {
 	return &nativeCodeClient{cc}
 }
 
@@ -192,7 +194,9 @@ type SyscallClient interface {
 	// Chain service
 	QueryTx(ctx context.Context, in *pb.QueryTxRequest, opts ...grpc.CallOption) (*pb.QueryTxResponse, error)
 	QueryBlock(ctx context.Context, in *pb.QueryBlockRequest, opts ...grpc.CallOption) (*pb.QueryBlockResponse, error)
+	GetRandom(ctx context.Context, in *pb.GetRandomRequest, opts ...grpc.CallOption) (*pb.GetRandomResponse, error)
 	Transfer(ctx context.Context, in *pb.TransferRequest, opts ...grpc.CallOption) (*pb.TransferResponse, error)
+	GetBalance(ctx context.Context, in *pb.GetBalanceRequest, opts ...grpc.CallOption) (*pb.GetBalanceResponse, error)
 	ContractCall(ctx context.Context, in *pb.ContractCallRequest, opts ...grpc.CallOption) (*pb.ContractCallResponse, error)
 	CrossContractQuery(ctx context.Context, in *pb.CrossContractQueryRequest, opts ...grpc.CallOption) (*pb.CrossContractQueryResponse, error)
 	GetAccountAddresses(ctx context.Context, in *pb.GetAccountAddressesRequest, opts ...grpc.CallOption) (*pb.GetAccountAddressesResponse, error)
@@ -207,10 +211,10 @@ type SyscallClient interface {
 }
 
 type syscallClient struct {
-	cc grpc.ClientConnInterface
+	cc *grpc.ClientConn
 }
 
-func NewSyscallClient(cc grpc.ClientConnInterface) SyscallClient {
+func NewSyscallClient(cc *grpc.ClientConn) SyscallClient {
 	return &syscallClient{cc}
 }
 
@@ -268,6 +272,15 @@ func (c *syscallClient) QueryBlock(ctx context.Context, in *pb.QueryBlockRequest
 	return out, nil
 }
 
+func (c *syscallClient) GetRandom(ctx context.Context, in *pb.GetRandomRequest, opts ...grpc.CallOption) (*pb.GetRandomResponse, error) {
+	out := new(pb.GetRandomResponse)
+	err := c.cc.Invoke(ctx, "/xchain.contract.svc.Syscall/GetRandom", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *syscallClient) Transfer(ctx context.Context, in *pb.TransferRequest, opts ...grpc.CallOption) (*pb.TransferResponse, error) {
 	out := new(pb.TransferResponse)
 	err := c.cc.Invoke(ctx, "/xchain.contract.svc.Syscall/Transfer", in, out, opts...)
@@ -277,6 +290,15 @@ func (c *syscallClient) Transfer(ctx context.Context, in *pb.TransferRequest, op
 	return out, nil
 }
 
+func (c *syscallClient) GetBalance(ctx context.Context, in *pb.GetBalanceRequest, opts ...grpc.CallOption) (*pb.GetBalanceResponse, error) {
+	out := new(pb.GetBalanceResponse)
+	err := c.cc.Invoke(ctx, "/xchain.contract.svc.Syscall/GetBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *syscallClient) ContractCall(ctx context.Context, in *pb.ContractCallRequest, opts ...grpc.CallOption) (*pb.ContractCallResponse, error) {
 	out := new(pb.ContractCallResponse)
 	err := c.cc.Invoke(ctx, "/xchain.contract.svc.Syscall/ContractCall", in, out, opts...)
@@ -359,7 +381,9 @@ type SyscallServer interface {
 	// Chain service
 	QueryTx(context.Context, *pb.QueryTxRequest) (*pb.QueryTxResponse, error)
 	QueryBlock(context.Context, *pb.QueryBlockRequest) (*pb.QueryBlockResponse, error)
+	GetRandom(context.Context, *pb.GetRandomRequest) (*pb.GetRandomResponse, error)
 	Transfer(context.Context, *pb.TransferRequest) (*pb.TransferResponse, error)
+	GetBalance(context.Context, *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error)
 	ContractCall(context.Context, *pb.ContractCallRequest) (*pb.ContractCallResponse, error)
 	CrossContractQuery(context.Context, *pb.CrossContractQueryRequest) (*pb.CrossContractQueryResponse, error)
 	GetAccountAddresses(context.Context, *pb.GetAccountAddressesRequest) (*pb.GetAccountAddressesResponse, error)
@@ -395,9 +419,15 @@ func (*UnimplementedSyscallServer) QueryTx(ctx context.Context, req *pb.QueryTxR
 func (*UnimplementedSyscallServer) QueryBlock(ctx context.Context, req *pb.QueryBlockRequest) (*pb.QueryBlockResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryBlock not implemented")
 }
+func (*UnimplementedSyscallServer) GetRandom(ctx context.Context, req *pb.GetRandomRequest) (*pb.GetRandomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRandom not implemented")
+}
 func (*UnimplementedSyscallServer) Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransferResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
 }
+func (*UnimplementedSyscallServer) GetBalance(ctx context.Context, req *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
 func (*UnimplementedSyscallServer) ContractCall(ctx context.Context, req *pb.ContractCallRequest) (*pb.ContractCallResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ContractCall not implemented")
 }
@@ -535,6 +565,24 @@ func _Syscall_QueryBlock_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Syscall_GetRandom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pb.GetRandomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyscallServer).GetRandom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/xchain.contract.svc.Syscall/GetRandom",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyscallServer).GetRandom(ctx, req.(*pb.GetRandomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Syscall_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(pb.TransferRequest)
 	if err := dec(in); err != nil {
@@ -553,6 +601,24 @@ func _Syscall_Transfer_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Syscall_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pb.GetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyscallServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/xchain.contract.svc.Syscall/GetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyscallServer).GetBalance(ctx, req.(*pb.GetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Syscall_ContractCall_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(pb.ContractCallRequest)
 	if err := dec(in); err != nil {
@@ -725,10 +791,18 @@ var _Syscall_serviceDesc = grpc.ServiceDesc{
 			MethodName: "QueryBlock",
 			Handler:    _Syscall_QueryBlock_Handler,
 		},
+		{
+			MethodName: "GetRandom",
+			Handler:    _Syscall_GetRandom_Handler,
+		},
 		{
 			MethodName: "Transfer",
 			Handler:    _Syscall_Transfer_Handler,
 		},
+		{
+			MethodName: "GetBalance",
+			Handler:    _Syscall_GetBalance_Handler,
+		},
 		{
 			MethodName: "ContractCall",
 			Handler:    _Syscall_ContractCall_Handler,
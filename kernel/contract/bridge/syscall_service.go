@@ -10,6 +10,7 @@ import (
 	"math/big"
 	"sort"
 
+	consensus "github.com/xuperchain/xupercore/kernel/consensus/base/common"
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
 	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
@@ -84,6 +85,40 @@ func (c *SyscallService) QueryBlock(ctx context.Context, in *pb.QueryBlockReques
 	}, nil
 }
 
+// GetRandom implements Syscall interface
+// 返回主干上指定高度区块的随机数，同一高度的结果在各节点一致，合约执行时该区块需已上链，
+// 通常由合约先记录一个未来的高度，待该高度的区块产生后再读取其随机数。
+// 读到的区块记录到读写集，交易上链时该区块不在主干上则交易失效
+func (c *SyscallService) GetRandom(ctx context.Context, in *pb.GetRandomRequest) (*pb.GetRandomResponse, error) {
+	nctx, ok := c.ctxmgr.Context(in.GetHeader().Ctxid)
+	if !ok {
+		return nil, fmt.Errorf("bad ctx id:%d", in.Header.Ctxid)
+	}
+
+	if in.GetHeight() < 0 {
+		return nil, fmt.Errorf("bad block height:%d", in.GetHeight())
+	}
+	state, ok := nctx.State.(contract.BlockReadState)
+	if !ok {
+		return nil, errors.New("state sandbox does not support block read")
+	}
+	block, err := nctx.Core.QueryBlockByHeight(in.GetHeight())
+	if err != nil {
+		return nil, err
+	}
+	state.AddBlockRead(block.GetBlockid())
+	random, verifiable, err := consensus.BlockRandomness(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetRandomResponse{
+		Random:     random,
+		Blockid:    hex.EncodeToString(block.GetBlockid()),
+		Verifiable: verifiable,
+	}, nil
+}
+
 // QueryTx implements Syscall interface
 func (c *SyscallService) QueryTx(ctx context.Context, in *pb.QueryTxRequest) (*pb.QueryTxResponse, error) {
 
@@ -132,6 +167,25 @@ func (c *SyscallService) Transfer(ctx context.Context, in *pb.TransferRequest) (
 	return resp, nil
 }
 
+// GetBalance implements Syscall interface
+func (c *SyscallService) GetBalance(ctx context.Context, in *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error) {
+	nctx, ok := c.ctxmgr.Context(in.GetHeader().Ctxid)
+	if !ok {
+		return nil, fmt.Errorf("bad ctx id:%d", in.Header.Ctxid)
+	}
+	if in.GetAddress() == "" {
+		return nil, errors.New("empty address")
+	}
+	balance, err := nctx.State.GetBalance(in.GetAddress(), in.GetFrozen())
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.GetBalanceResponse{
+		Balance: balance.String(),
+	}
+	return resp, nil
+}
+
 // ContractCall implements Syscall interface
 func (c *SyscallService) ContractCall(ctx context.Context, in *pb.ContractCallRequest) (*pb.ContractCallResponse, error) {
 	nctx, ok := c.ctxmgr.Context(in.GetHeader().Ctxid)
@@ -156,11 +210,69 @@ func (c *SyscallService) ContractCall(ctx context.Context, in *pb.ContractCallRe
 	// disk usage is shared between all context
 	limits.Disk = nctx.ResourceLimits.Disk
 
+	// 附带转账的调用和try调用在保存点中执行，被调用合约返回失败或者出错时，
+	// 转账、写集和事件随调用一起回滚，调用者处理错误后继续执行也不会保留转账
+	if !in.GetTry() && in.GetAmount() == "" {
+		vresp, err := c.callContract(nctx, in, *limits)
+		if err != nil {
+			return nil, err
+		}
+		return newContractCallResponse(vresp), nil
+	}
+	sandbox, ok := nctx.State.(contract.SavepointSandbox)
+	if !ok {
+		return nil, errors.New("state sandbox does not support savepoint")
+	}
+	savepoint := sandbox.Savepoint()
+	vresp, err := c.callContract(nctx, in, *limits)
+	if err != nil {
+		if rerr := sandbox.RollbackTo(savepoint); rerr != nil {
+			return nil, rerr
+		}
+		// 虚拟机返回的错误(如超时)不一定在所有节点上一致，try语义下仍然终止整个调用
+		return nil, err
+	}
+	if vresp.Status >= contract.StatusErrorThreshold {
+		err = sandbox.RollbackTo(savepoint)
+	} else {
+		err = sandbox.ReleaseSavepoint(savepoint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// 非try语义下附带转账的调用失败时返回错误
+	if !in.GetTry() && vresp.Status >= contract.StatusErrorThreshold {
+		return nil, fmt.Errorf("contract call with amount failed, status:%d, message:%s", vresp.Status, vresp.Message)
+	}
+	return newContractCallResponse(vresp), nil
+}
+
+// callContract 在调用者的沙盒中执行转账和被调用合约
+func (c *SyscallService) callContract(nctx *Context, in *pb.ContractCallRequest, limits contract.Limits) (*contract.Response, error) {
 	args := make(map[string][]byte)
 	for _, arg := range in.GetArgs() {
 		args[arg.GetKey()] = arg.GetValue()
 	}
 
+	// 转账和调用在同一个沙盒中执行，被调用合约通过TransferAmount感知转入的金额
+	var transferAmount string
+	if in.GetAmount() != "" {
+		amount, ok := new(big.Int).SetString(in.GetAmount(), 10)
+		if !ok {
+			return nil, errors.New("parse amount error")
+		}
+		if amount.Sign() < 0 {
+			return nil, errors.New("amount should not be negative")
+		}
+		if amount.Sign() > 0 {
+			err := nctx.State.Transfer(nctx.ContractName, in.GetContract(), amount)
+			if err != nil {
+				return nil, err
+			}
+			transferAmount = amount.String()
+		}
+	}
+
 	nctx.ContractSet[in.GetContract()] = true
 	cfg := &contract.ContextConfig{
 		Module:         in.GetModule(),
@@ -170,11 +282,13 @@ func (c *SyscallService) ContractCall(ctx context.Context, in *pb.ContractCallRe
 		AuthRequire:    nctx.AuthRequire,
 		Initiator:      nctx.Initiator,
 		Caller:         nctx.ContractName,
-		ResourceLimits: *limits,
+		ResourceLimits: limits,
 		ContractSet:    nctx.ContractSet,
+		TransferAmount: transferAmount,
 	}
 	vctx, err := c.bridge.NewContext(cfg)
 	if err != nil {
+		delete(nctx.ContractSet, in.GetContract())
 		return nil, err
 	}
 	defer func() {
@@ -187,13 +301,16 @@ func (c *SyscallService) ContractCall(ctx context.Context, in *pb.ContractCallRe
 		return nil, err
 	}
 	nctx.SubResourceUsed.Add(vctx.ResourceUsed())
+	return vresp, nil
+}
 
+func newContractCallResponse(vresp *contract.Response) *pb.ContractCallResponse {
 	return &pb.ContractCallResponse{
 		Response: &pb.Response{
 			Status:  int32(vresp.Status),
 			Message: vresp.Message,
 			Body:    vresp.Body,
-		}}, nil
+		}}
 }
 
 // CrossContractQuery implements Syscall interface
@@ -379,10 +496,21 @@ func (c *SyscallService) EmitEvent(ctx context.Context, in *pb.EmitEventRequest)
 	if !ok {
 		return nil, fmt.Errorf("bad ctx id:%d", in.GetHeader().GetCtxid())
 	}
+	ci, err := nctx.ContractInterface()
+	if err != nil {
+		return nil, err
+	}
+	body := in.GetBody()
+	if ci != nil {
+		body, err = ci.DecodeEvent(in.GetName(), body)
+		if err != nil {
+			return nil, err
+		}
+	}
 	event := &protos.ContractEvent{
 		Contract: nctx.ContractName,
 		Name:     in.GetName(),
-		Body:     in.GetBody(),
+		Body:     body,
 	}
 	nctx.Events = append(nctx.Events, event)
 	nctx.State.AddEvent(event)
@@ -0,0 +1,137 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	log15 "github.com/xuperchain/log15"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+type testLogger struct {
+	log15.Logger
+}
+
+func (*testLogger) GetLogId() string                           { return "" }
+func (*testLogger) SetCommField(key string, value interface{}) {}
+func (*testLogger) SetInfoField(key string, value interface{}) {}
+
+type testChainCore struct {
+	contract.ChainCore
+}
+
+func (*testChainCore) VerifyContractPermission(initiator string, authRequire []string, contractName, methodName string) (bool, error) {
+	return true, nil
+}
+
+// failedInstance 写入状态后以失败的状态码或者错误结束调用
+type failedInstance struct {
+	ctx *Context
+	err error
+}
+
+func (f *failedInstance) Exec() error {
+	if err := f.ctx.State.Put(f.ctx.ContractName, []byte("key"), []byte("value")); err != nil {
+		return err
+	}
+	if f.err != nil {
+		return f.err
+	}
+	f.ctx.Output = &pb.Response{
+		Status:  500,
+		Message: "callee failed",
+	}
+	return nil
+}
+
+func (f *failedInstance) ResourceUsed() contract.Limits {
+	return contract.Limits{}
+}
+
+func (f *failedInstance) Release() {}
+
+func (f *failedInstance) Abort(msg string) {}
+
+type failedCreator struct {
+	err error
+}
+
+func (c *failedCreator) CreateInstance(ctx *Context, cp ContractCodeProvider) (Instance, error) {
+	return &failedInstance{ctx: ctx, err: c.err}, nil
+}
+
+func (c *failedCreator) RemoveCache(name string) {}
+
+func TestContractCallRollbackTransfer(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+	}{
+		{"status", nil},
+		{"error", errors.New("callee aborted")},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctxmgr := NewContextManager()
+			xbridge := &XBridge{
+				ctxmgr: ctxmgr,
+				creators: map[ContractType]InstanceCreator{
+					TypeKernel: &failedCreator{err: c.err},
+				},
+				core:            &testChainCore{},
+				debugLogger:     &testLogger{log15.New()},
+				contractManager: &contractManager{},
+			}
+			syscall := NewSyscallService(ctxmgr, xbridge)
+
+			state := sandbox.NewXModelCache(&contract.SandboxConfig{
+				XMReader: sandbox.NewMemXModel(),
+				UTXOReader: sandbox.NewUTXOReaderFromInput([]*protos.TxInput{
+					{RefTxid: []byte("tx"), FromAddr: []byte("caller"), Amount: big.NewInt(10).Bytes()},
+				}),
+			})
+			ctx := ctxmgr.MakeContext()
+			ctx.State = state
+			ctx.Core = xbridge.core
+			ctx.ContractName = "caller"
+			ctx.ResourceLimits = contract.MaxLimits
+			ctx.ContractSet = map[string]bool{"caller": true}
+			ctx.Instance = &fakeInstance{ctx: ctx}
+
+			_, err := syscall.ContractCall(context.TODO(), &pb.ContractCallRequest{
+				Header:   &pb.SyscallHeader{Ctxid: ctx.ID},
+				Module:   string(TypeKernel),
+				Contract: "callee",
+				Method:   "run",
+				Amount:   "5",
+			})
+			if err == nil {
+				t.Fatal("expect error of failed paid call")
+			}
+
+			// 调用者处理错误后继续执行，被调用合约的写入和转账都不应保留
+			if err := state.Put("caller", []byte("key"), []byte("value")); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := state.Get("callee", []byte("key")); err == nil {
+				t.Error("write of failed callee should be rolled back")
+			}
+			rwset := state.UTXORWSet()
+			refund := new(big.Int)
+			for _, output := range rwset.WSet {
+				if string(output.GetToAddr()) != "caller" {
+					t.Fatalf("transfer to failed callee should be rolled back, got output to %s", output.GetToAddr())
+				}
+				refund.Add(refund, new(big.Int).SetBytes(output.GetAmount()))
+			}
+			if refund.Int64() != 10 {
+				t.Errorf("expect refund 10, got %s", refund)
+			}
+		})
+	}
+}
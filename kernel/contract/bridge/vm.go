@@ -37,6 +37,12 @@ type InstanceCreator interface {
 	RemoveCache(name string)
 }
 
+// Precompiler is optionally implemented by InstanceCreator to compile contract code
+// in background, so that the first call of the contract does not wait for compiling
+type Precompiler interface {
+	Precompile(name string, cp ContractCodeProvider)
+}
+
 // Instance is a contract virtual machine instance which can run a single contract call
 type Instance interface {
 	Exec() error
@@ -93,6 +93,24 @@ func (b *XBridge) getCreator(tp ContractType) InstanceCreator {
 	return b.creators[tp]
 }
 
+// PrecompileContract compiles the confirmed code of contract in background
+// if the vm of the contract supports precompiling
+func (b *XBridge) PrecompileContract(name string) {
+	desc, err := b.codeProvider.GetContractCodeDesc(name)
+	if err != nil {
+		return
+	}
+	tp, err := getContractType(desc)
+	if err != nil {
+		return
+	}
+	precompiler, ok := b.getCreator(tp).(Precompiler)
+	if !ok {
+		return
+	}
+	precompiler.Precompile(name, newDescProvider(b.codeProvider, desc))
+}
+
 func (b *XBridge) NewContext(ctxCfg *contract.ContextConfig) (contract.Context, error) {
 	var desc *protos.WasmCodeDesc
 	var err error
@@ -108,6 +126,9 @@ func (b *XBridge) NewContext(ctxCfg *contract.ContextConfig) (contract.Context,
 			return nil, err
 		}
 	}
+	if desc.GetFrozen() {
+		return nil, fmt.Errorf("contract %s is frozen", ctxCfg.ContractName)
+	}
 	tp, err := getContractType(desc)
 	if err != nil {
 		return nil, err
@@ -156,6 +177,10 @@ func (b *XBridge) NewContext(ctxCfg *contract.ContextConfig) (contract.Context,
 		ctx.Logger, err = logs.NewLogger(fmt.Sprintf("%016d", ctx.ID), "contract")
 	}
 	ctx.ChainName = ctxCfg.ChainName
+	// 只有部署或升级时带有接口描述的合约才读取接口描述，其他合约的读集不受影响
+	if (tp == TypeWasm || tp == TypeNative) && desc.GetHasAbi() {
+		ctx.codeProvider = cp
+	}
 
 	if err != nil {
 		return nil, err
@@ -10,6 +10,13 @@ type NativeConfig struct {
 	// Timeout (in seconds) to stop native code process
 	StopTimeout int
 	Docker      NativeDockerConfig
+	// Sandbox runs native code in linux namespaces without docker, ignored when docker is enabled
+	Sandbox NativeSandboxConfig
+	// Metering charges native code by syscalls instead of a constant fee
+	Metering NativeMeteringConfig
+	// Timeout (in milliseconds) of a single native contract call, 0 means no limit.
+	// It only guards liveness and is not deterministic, use Metering to limit resources.
+	ExecTimeout int
 	Enable      bool
 }
 
@@ -33,12 +40,35 @@ type NativeDockerConfig struct {
 	Memory    string
 }
 
+// NativeSandboxConfig native contract use linux namespaces, cgroup v2 and seccomp config
+type NativeSandboxConfig struct {
+	Enable bool
+	// Cpus limits cpu cores of the contract, can be a fraction, 0 means no limit
+	Cpus float32
+	// Memory limits memory of the contract, such as "1G", empty means no limit
+	Memory string
+	// CgroupRoot is a delegated cgroup v2 directory, a sub cgroup is created for every contract
+	CgroupRoot string
+	// Mounts are host directories mounted read-only into the sandbox, such as runtime libraries
+	Mounts []string
+}
+
+// NativeMeteringConfig charges native code by syscalls.
+// The weights of syscalls are fixed in the native driver, but all nodes of a chain must still agree on Enable.
+type NativeMeteringConfig struct {
+	Enable bool
+}
+
 // XVMConfig contains the xvm configuration
 type XVMConfig struct {
 	// From 0 to 3
 	// The higher the number, the faster the program runs,
 	// but the compilation speed will be slower
 	OptLevel int `yaml:"optlevel"`
+	// CodeCacheSize limits the number of contracts loaded in memory, 0 means default
+	CodeCacheSize int `yaml:"codecachesize"`
+	// DiskCacheSize limits the size (in MB) of compiled contracts on disk, 0 means default
+	DiskCacheSize int64 `yaml:"diskcachesize"`
 }
 
 // WasmConfig wasm config
@@ -7,6 +7,7 @@ import (
 
 	"github.com/xuperchain/xupercore/kernel/common/xconfig"
 	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/protos"
 )
 
 var (
@@ -22,6 +23,12 @@ type Manager interface {
 	GetKernRegistry() KernRegistry
 }
 
+// CodePrecompiler is optionally implemented by Manager to compile the code of contracts
+// in background after their deploy or upgrade transactions are confirmed
+type CodePrecompiler interface {
+	PrecompileContracts(reqs []*protos.InvokeRequest)
+}
+
 type ManagerConfig struct {
 	Basedir  string
 	BCName   string
@@ -44,6 +51,10 @@ type ChainCore interface {
 	QueryTransaction(txid []byte) (*pb.Transaction, error)
 	// QueryBlock query block
 	QueryBlock(blockid []byte) (ledger.BlockHandle, error)
+	// QueryBlockByHeight query block on trunk by height
+	QueryBlockByHeight(height int64) (ledger.BlockHandle, error)
+	// QueryTipBlockHeight query height of the trunk tip block
+	QueryTipBlockHeight() (int64, error)
 
 	// ResolveChain resolve chain endorsorinfos
 	// ResolveChain(chainName string) (*pb.CrossQueryMeta, error)
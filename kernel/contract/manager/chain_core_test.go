@@ -77,6 +77,12 @@ func TestBridgeFeatures(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	t.Run("Version", func(t *testing.T) {
+		version := getValue(t, th, "contract", mock.FeaturesContractName+".version")
+		if string(version) != "1" {
+			t.Errorf("unexpected contract version %s", version)
+		}
+	})
 	t.Run("Logging", func(t *testing.T) {
 		resp, err := th.Invoke("native", "features", "Logging", map[string][]byte{})
 		if err != nil {
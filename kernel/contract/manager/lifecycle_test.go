@@ -0,0 +1,204 @@
+package manager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/contract/mock"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/kernel/permission/acl/utils"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const lifecycleContract = "counter"
+
+// putTestContract 直接在状态中写入一个已经部署的合约
+func putTestContract(th *mock.TestHelper, contractType string) {
+	descbuf, _ := proto.Marshal(&protos.WasmCodeDesc{
+		ContractType: contractType,
+		Digest:       []byte("digest"),
+	})
+	kvs := []struct {
+		bucket string
+		key    string
+		value  []byte
+	}{
+		{"contract", string(bridge.ContractCodeDescKey(lifecycleContract)), descbuf},
+		{"contract", lifecycleContract + ".code", []byte("code")},
+		{utils.GetContract2AccountBucket(), lifecycleContract, []byte(mock.ContractAccount)},
+		{utils.GetAccount2ContractBucket(), utils.MakeAccountContractKey(mock.ContractAccount, lifecycleContract), []byte(utils.GetAccountContractValue())},
+		{utils.GetAccountBucket(), mock.ContractAccount2, []byte("acl")},
+	}
+	for _, kv := range kvs {
+		th.State().Put(kv.bucket, []byte(kv.key), &ledger.VersionedData{
+			RefTxid: []byte("txid"),
+			PureData: &ledger.PureData{
+				Bucket: kv.bucket,
+				Key:    []byte(kv.key),
+				Value:  kv.value,
+			},
+		})
+	}
+}
+
+func invokeLifecycle(t *testing.T, th *mock.TestHelper, method string, args map[string][]byte) ([]*protos.ContractEvent, error) {
+	m := th.Manager()
+	state, err := m.NewStateSandbox(&contract.SandboxConfig{
+		XMReader: th.State(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := m.NewContext(&contract.ContextConfig{
+		Module:         "xkernel",
+		ContractName:   "$contract",
+		State:          state,
+		ResourceLimits: contract.MaxLimits,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Release()
+	if _, err := ctx.Invoke(method, args); err != nil {
+		return nil, err
+	}
+	th.Commit(state)
+	return state.Events(), nil
+}
+
+func getValue(t *testing.T, th *mock.TestHelper, bucket, key string) []byte {
+	value, err := th.State().Get(bucket, []byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value.GetPureData().GetValue()
+}
+
+func TestContractLifecycle(t *testing.T) {
+	th := mock.NewTestHelper(contractConfig)
+	defer th.Close()
+	putTestContract(th, "wasm")
+	args := map[string][]byte{
+		"contract_name": []byte(lifecycleContract),
+	}
+	newContext := func() error {
+		state, _ := th.Manager().NewStateSandbox(&contract.SandboxConfig{
+			XMReader: th.State(),
+		})
+		ctx, err := th.Manager().NewContext(&contract.ContextConfig{
+			Module:         "wasm",
+			ContractName:   lifecycleContract,
+			State:          state,
+			ResourceLimits: contract.MaxLimits,
+		})
+		if err == nil {
+			ctx.Release()
+		}
+		return err
+	}
+
+	events, err := invokeLifecycle(t, th, "freeze", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Name != "ContractFrozen" ||
+		string(events[0].Body) != `{"contract_name":"counter"}` {
+		t.Errorf("unexpected events %v", events)
+	}
+	if err := newContext(); err == nil || !strings.Contains(err.Error(), "frozen") {
+		t.Errorf("expect frozen error, got %v", err)
+	}
+	if _, err := invokeLifecycle(t, th, "freeze", args); err == nil {
+		t.Error("expect error when freezing a frozen contract")
+	}
+
+	if _, err := invokeLifecycle(t, th, "unfreeze", args); err != nil {
+		t.Fatal(err)
+	}
+	// 解冻后 wasm 虚拟机没有开启，错误不再是冻结
+	if err := newContext(); err == nil || strings.Contains(err.Error(), "frozen") {
+		t.Errorf("expect vm error, got %v", err)
+	}
+
+	events, err = invokeLifecycle(t, th, "transferOwnership", map[string][]byte{
+		"contract_name": []byte(lifecycleContract),
+		"account_name":  []byte(mock.ContractAccount2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Name != "ContractOwnershipTransferred" {
+		t.Errorf("unexpected events %v", events)
+	}
+	if account := getValue(t, th, utils.GetContract2AccountBucket(), lifecycleContract); string(account) != mock.ContractAccount2 {
+		t.Errorf("unexpected owner %s", account)
+	}
+	oldKey := utils.MakeAccountContractKey(mock.ContractAccount, lifecycleContract)
+	if value := getValue(t, th, utils.GetAccount2ContractBucket(), oldKey); !sandbox.IsDelFlag(value) {
+		t.Errorf("expect old account contract deleted, got %q", value)
+	}
+	_, err = invokeLifecycle(t, th, "transferOwnership", map[string][]byte{
+		"contract_name": []byte(lifecycleContract),
+		"account_name":  []byte("XC3333333333333333@xuper"),
+	})
+	if err == nil {
+		t.Error("expect error when transferring to a missing account")
+	}
+
+	events, err = invokeLifecycle(t, th, "destroy", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Name != "ContractDestroyed" {
+		t.Errorf("unexpected events %v", events)
+	}
+	if err := newContext(); err == nil {
+		t.Error("expect error when calling a destroyed contract")
+	}
+	// 合约和账户的对应关系保留
+	if account := getValue(t, th, utils.GetContract2AccountBucket(), lifecycleContract); string(account) != mock.ContractAccount2 {
+		t.Errorf("unexpected owner %s after destroy", account)
+	}
+	if _, err := invokeLifecycle(t, th, "destroy", args); err == nil {
+		t.Error("expect error when destroying a destroyed contract")
+	}
+	_, err = th.Deploy("wasm", "c", lifecycleContract, []byte("code"), map[string][]byte{})
+	if err == nil || !strings.Contains(err.Error(), "destroyed") {
+		t.Errorf("expect destroyed error when redeploying, got %v", err)
+	}
+
+	// 部署时对删除标记的读取进入交易的读集
+	state, _ := th.Manager().NewStateSandbox(&contract.SandboxConfig{
+		XMReader: th.State(),
+	})
+	ctx, err := th.Manager().NewContext(&contract.ContextConfig{
+		Module:         "xkernel",
+		ContractName:   "$contract",
+		State:          state,
+		ResourceLimits: contract.MaxLimits,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Release()
+	if _, err := ctx.Invoke("deployContract", map[string][]byte{
+		"account_name":  []byte(mock.ContractAccount),
+		"contract_name": []byte(lifecycleContract),
+	}); err == nil || !strings.Contains(err.Error(), "destroyed") {
+		t.Errorf("expect destroyed error when redeploying, got %v", err)
+	}
+	descKey := string(bridge.ContractCodeDescKey(lifecycleContract))
+	var tracked bool
+	for _, read := range state.RWSet().RSet {
+		if read.GetPureData().GetBucket() == "contract" && string(read.GetPureData().GetKey()) == descKey {
+			tracked = sandbox.IsDelFlag(read.GetPureData().GetValue())
+		}
+	}
+	if !tracked {
+		t.Error("expect deleted desc in read set of deploy")
+	}
+}
@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/xuperchain/xupercore/lib/logs"
@@ -8,12 +9,16 @@ import (
 
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	putils "github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
 	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/kernel/permission/acl/utils"
+	"github.com/xuperchain/xupercore/protos"
 )
 
 type managerImpl struct {
 	core      contract.ChainCore
+	xmreader  ledger.XMReader
 	xbridge   *bridge.XBridge
 	kregistry registryImpl
 }
@@ -46,7 +51,8 @@ func newManagerImpl(cfg *contract.ManagerConfig) (contract.Manager, error) {
 	}
 
 	m := &managerImpl{
-		core: cfg.Core,
+		core:     cfg.Core,
+		xmreader: cfg.XMReader,
 	}
 	var logDriver logs.Logger
 	if cfg.Config != nil {
@@ -76,6 +82,16 @@ func newManagerImpl(cfg *contract.ManagerConfig) (contract.Manager, error) {
 	registry := &m.kregistry
 	registry.RegisterKernMethod("$contract", "deployContract", m.deployContract)
 	registry.RegisterKernMethod("$contract", "upgradeContract", m.upgradeContract)
+	registry.RegisterKernMethod("$contract", "freeze", m.freezeContract)
+	registry.RegisterKernMethod("$contract", "unfreeze", m.unfreezeContract)
+	registry.RegisterKernMethod("$contract", "transferOwnership", m.transferOwnership)
+	registry.RegisterKernMethod("$contract", "destroy", m.destroyContract)
+	registry.RegisterKernMethod("$contract", "setUpgradePolicy", m.setUpgradePolicy)
+	registry.RegisterKernMethod("$contract", "proposeUpgrade", m.proposeUpgrade)
+	registry.RegisterKernMethod("$contract", "approveUpgrade", m.approveUpgrade)
+	registry.RegisterKernMethod("$contract", "armUpgrade", m.armUpgrade)
+	registry.RegisterKernMethod("$contract", "activateUpgrade", m.activateUpgrade)
+	registry.RegisterKernMethod("$contract", "cancelUpgrade", m.cancelUpgrade)
 	registry.RegisterShortcut("Deploy", "$contract", "deployContract")
 	registry.RegisterShortcut("Upgrade", "$contract", "upgradeContract")
 	return m, nil
@@ -93,6 +109,41 @@ func (m *managerImpl) GetKernRegistry() contract.KernRegistry {
 	return &m.kregistry
 }
 
+// PrecompileContracts implements contract.CodePrecompiler
+func (m *managerImpl) PrecompileContracts(reqs []*protos.InvokeRequest) {
+	for _, req := range reqs {
+		if req.GetModuleName() != string(bridge.TypeKernel) {
+			continue
+		}
+		contractName, method := req.GetContractName(), req.GetMethodName()
+		if contractName == "" {
+			m.kregistry.mutex.Lock()
+			sc, err := m.kregistry.getShortcut(method)
+			m.kregistry.mutex.Unlock()
+			if err != nil {
+				continue
+			}
+			contractName, method = sc.Contract, sc.Method
+		}
+		// 定时任务生效的升级没有对应的调用，从定时任务的参数中找到升级的合约
+		if contractName == putils.TimerTaskKernelContract && method == "Do" {
+			for _, name := range m.activatedUpgrades(req.GetArgs()["block_height"]) {
+				m.xbridge.PrecompileContract(name)
+			}
+			continue
+		}
+		if contractName != "$contract" ||
+			(method != "deployContract" && method != "upgradeContract") {
+			continue
+		}
+		name := req.GetArgs()["contract_name"]
+		if len(name) == 0 {
+			continue
+		}
+		m.xbridge.PrecompileContract(string(name))
+	}
+}
+
 func (m *managerImpl) deployContract(ctx contract.KContext) (*contract.Response, error) {
 	// check if account exist
 	accountName := ctx.Args()["account_name"]
@@ -138,6 +189,14 @@ func (m *managerImpl) upgradeContract(ctx contract.KContext) (*contract.Response
 	if err != nil {
 		return nil, err
 	}
+	// 设置了升级策略的合约只能通过 proposeUpgrade 升级
+	policy, err := getUpgradePolicy(ctx, string(contractName))
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		return nil, fmt.Errorf("contract %s has upgrade policy, upgrade it by proposeUpgrade", contractName)
+	}
 
 	resp, limit, err := m.xbridge.UpgradeContract(ctx)
 	if err != nil {
@@ -147,6 +206,145 @@ func (m *managerImpl) upgradeContract(ctx contract.KContext) (*contract.Response
 	return resp, nil
 }
 
+func (m *managerImpl) freezeContract(ctx contract.KContext) (*contract.Response, error) {
+	return m.setContractFrozen(ctx, true)
+}
+
+func (m *managerImpl) unfreezeContract(ctx contract.KContext) (*contract.Response, error) {
+	return m.setContractFrozen(ctx, false)
+}
+
+func (m *managerImpl) setContractFrozen(ctx contract.KContext, frozen bool) (*contract.Response, error) {
+	contractName := ctx.Args()["contract_name"]
+	if contractName == nil {
+		return nil, errors.New("invoke freeze error, contract name is nil")
+	}
+
+	err := m.core.VerifyContractOwnerPermission(string(contractName), ctx.AuthRequire())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, limit, err := m.xbridge.FreezeContract(ctx, frozen)
+	if err != nil {
+		return nil, err
+	}
+	ctx.AddResourceUsed(limit)
+
+	event := "ContractUnfrozen"
+	if frozen {
+		event = "ContractFrozen"
+	}
+	if err := emitContractEvent(ctx, event, string(contractName), nil); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// transferOwnership 将合约转移到另一个合约账户下，之后由新账户的 ACL 管理合约
+func (m *managerImpl) transferOwnership(ctx contract.KContext) (*contract.Response, error) {
+	contractName := ctx.Args()["contract_name"]
+	accountName := ctx.Args()["account_name"]
+	if contractName == nil || accountName == nil {
+		return nil, errors.New("invoke transferOwnership error, account name or contract name is nil")
+	}
+
+	err := m.core.VerifyContractOwnerPermission(string(contractName), ctx.AuthRequire())
+	if err != nil {
+		return nil, err
+	}
+	// 已经销毁的合约不能转移
+	if _, err := ctx.Get("contract", bridge.ContractCodeDescKey(string(contractName))); err != nil {
+		return nil, fmt.Errorf("contract %s not exists", contractName)
+	}
+	oldAccount, err := ctx.Get(utils.GetContract2AccountBucket(), contractName)
+	if err != nil {
+		return nil, fmt.Errorf("get account of contract `%s` error: %s", contractName, err)
+	}
+	if string(oldAccount) == string(accountName) {
+		return nil, fmt.Errorf("contract `%s` already belongs to account `%s`", contractName, accountName)
+	}
+	_, err = ctx.Get(utils.GetAccountBucket(), accountName)
+	if err != nil {
+		return nil, fmt.Errorf("get account `%s` error: %s", accountName, err)
+	}
+
+	err = ctx.Put(utils.GetContract2AccountBucket(), contractName, accountName)
+	if err != nil {
+		return nil, err
+	}
+	oldKey := utils.MakeAccountContractKey(string(oldAccount), string(contractName))
+	err = ctx.Del(utils.GetAccount2ContractBucket(), []byte(oldKey))
+	if err != nil {
+		return nil, err
+	}
+	key := utils.MakeAccountContractKey(string(accountName), string(contractName))
+	err = ctx.Put(utils.GetAccount2ContractBucket(), []byte(key), []byte(utils.GetAccountContractValue()))
+	if err != nil {
+		return nil, err
+	}
+
+	err = emitContractEvent(ctx, "ContractOwnershipTransferred", string(contractName), map[string]string{
+		"from": string(oldAccount),
+		"to":   string(accountName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &contract.Response{
+		Status: 200,
+		Body:   []byte("transfer ownership success"),
+	}, nil
+}
+
+// destroyContract 销毁合约，合约和账户的对应关系保留，合约名不能再次使用
+func (m *managerImpl) destroyContract(ctx contract.KContext) (*contract.Response, error) {
+	contractName := ctx.Args()["contract_name"]
+	if contractName == nil {
+		return nil, errors.New("invoke destroy error, contract name is nil")
+	}
+
+	err := m.core.VerifyContractOwnerPermission(string(contractName), ctx.AuthRequire())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, limit, err := m.xbridge.DestroyContract(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx.AddResourceUsed(limit)
+	// 等待生效的升级随合约一起取消
+	if _, err := cancelPendingUpgrade(ctx, string(contractName)); err != nil {
+		return nil, err
+	}
+
+	if err := emitContractEvent(ctx, "ContractDestroyed", string(contractName), nil); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// emitContractEvent 记录合约生命周期事件，事件内容为包含合约名的 json 对象
+func emitContractEvent(ctx contract.KContext, name, contractName string, fields map[string]string) error {
+	body := map[string]string{
+		"contract_name": contractName,
+	}
+	for k, v := range fields {
+		body[k] = v
+	}
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	ctx.AddEvent(&protos.ContractEvent{
+		Contract: "$contract",
+		Name:     name,
+		Body:     buf,
+	})
+	return nil
+}
+
 func init() {
 	contract.Register("default", newManagerImpl)
 }
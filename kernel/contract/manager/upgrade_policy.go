@@ -0,0 +1,589 @@
+package manager
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+)
+
+// 升级的批准方式
+const (
+	// 只需要合约所有者发起升级
+	upgradeApprovalOwner = "owner"
+	// 需要通过 $proposal 提案批准，提案的 trigger 调用 $contract 的 approveUpgrade 方法
+	upgradeApprovalProposal = "proposal"
+	// 需要 approvers 中至少 threshold 个地址作为交易发起者调用 approveUpgrade
+	upgradeApprovalACL = "acl"
+)
+
+// upgradePolicy 是合约可选的升级策略，设置之后合约只能通过 proposeUpgrade 升级，
+// 升级策略本身的修改也需要通过 proposeUpgrade 完成
+type upgradePolicy struct {
+	Approval  string   `json:"approval"`
+	Approvers []string `json:"approvers,omitempty"`
+	Threshold int      `json:"threshold,omitempty"`
+	// 升级批准之后至少经过 timelock 个区块新代码才能生效
+	Timelock int64 `json:"timelock"`
+}
+
+// pendingUpgrade 是等待生效的升级，同一个合约同时只有一个
+type pendingUpgrade struct {
+	// 新代码的摘要，只修改升级策略时为空
+	Digest         string         `json:"digest,omitempty"`
+	Abi            []byte         `json:"abi,omitempty"`
+	Policy         *upgradePolicy `json:"policy,omitempty"`
+	ActivateHeight int64          `json:"activate_height"`
+	Approvals      []string       `json:"approvals,omitempty"`
+	Approved       bool           `json:"approved"`
+	// 在 ActivateHeight - Timelock 高度时升级已经批准
+	Armed bool `json:"armed"`
+	// 在 ActivateHeight - Timelock 高度时升级尚未批准，升级不会再生效，可以被新的升级替换
+	Expired bool `json:"expired,omitempty"`
+}
+
+// upgradeTask 是定时任务和提案回调 $contract 时的参数，用于匹配对应的升级
+type upgradeTask struct {
+	ContractName   string `json:"contract_name"`
+	Digest         string `json:"digest"`
+	ActivateHeight int64  `json:"activate_height"`
+}
+
+func upgradePolicyKey(contractName string) []byte {
+	return []byte(contractName + ".upgrade_policy")
+}
+
+func pendingUpgradeKey(contractName string) []byte {
+	return []byte(contractName + ".pending_upgrade")
+}
+
+func pendingCodeKey(contractName string) []byte {
+	return []byte(contractName + ".pending_code")
+}
+
+func parseUpgradePolicy(buf []byte) (*upgradePolicy, error) {
+	policy := new(upgradePolicy)
+	if err := json.Unmarshal(buf, policy); err != nil {
+		return nil, fmt.Errorf("bad upgrade policy:%s", err)
+	}
+	if policy.Approval == "" {
+		policy.Approval = upgradeApprovalOwner
+	}
+	if policy.Timelock < 0 {
+		return nil, errors.New("bad upgrade policy:negative timelock")
+	}
+	switch policy.Approval {
+	case upgradeApprovalOwner, upgradeApprovalProposal:
+		if len(policy.Approvers) != 0 || policy.Threshold != 0 {
+			return nil, fmt.Errorf("bad upgrade policy:approvers are only used by %s approval", upgradeApprovalACL)
+		}
+	case upgradeApprovalACL:
+		approvers := make(map[string]bool)
+		for _, approver := range policy.Approvers {
+			if approver == "" || approvers[approver] {
+				return nil, fmt.Errorf("bad upgrade policy:empty or duplicated approver %q", approver)
+			}
+			approvers[approver] = true
+		}
+		if policy.Threshold <= 0 || policy.Threshold > len(policy.Approvers) {
+			return nil, fmt.Errorf("bad upgrade policy:threshold %d of %d approvers", policy.Threshold, len(policy.Approvers))
+		}
+	default:
+		return nil, fmt.Errorf("bad upgrade policy:unknown approval %s", policy.Approval)
+	}
+	return policy, nil
+}
+
+// getUpgradePolicy 返回合约的升级策略，没有设置时返回 nil
+func getUpgradePolicy(ctx contract.KContext, contractName string) (*upgradePolicy, error) {
+	buf, err := ctx.Get("contract", upgradePolicyKey(contractName))
+	if err != nil || len(buf) == 0 || sandbox.IsDelFlag(buf) {
+		return nil, nil
+	}
+	return parseUpgradePolicy(buf)
+}
+
+func getPendingUpgrade(ctx contract.KContext, contractName string) (*pendingUpgrade, error) {
+	buf, err := ctx.Get("contract", pendingUpgradeKey(contractName))
+	if err != nil || len(buf) == 0 || sandbox.IsDelFlag(buf) {
+		return nil, nil
+	}
+	pending := new(pendingUpgrade)
+	if err := json.Unmarshal(buf, pending); err != nil {
+		return nil, fmt.Errorf("bad pending upgrade of contract %s:%s", contractName, err)
+	}
+	return pending, nil
+}
+
+func putPendingUpgrade(ctx contract.KContext, contractName string, pending *pendingUpgrade) error {
+	buf, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+	return ctx.Put("contract", pendingUpgradeKey(contractName), buf)
+}
+
+func deletePendingUpgrade(ctx contract.KContext, contractName string, pending *pendingUpgrade) error {
+	if err := ctx.Del("contract", pendingUpgradeKey(contractName)); err != nil {
+		return err
+	}
+	if pending.Digest == "" {
+		return nil
+	}
+	return ctx.Del("contract", pendingCodeKey(contractName))
+}
+
+func (p *pendingUpgrade) match(task *upgradeTask) bool {
+	return p != nil && p.Digest == task.Digest && p.ActivateHeight == task.ActivateHeight
+}
+
+// parseUpgradeTask 解析定时任务和提案回调时 json 编码在 args 中的参数
+func parseUpgradeTask(ctx contract.KContext) (*upgradeTask, error) {
+	task := new(upgradeTask)
+	if err := json.Unmarshal(ctx.Args()["args"], task); err != nil {
+		return nil, fmt.Errorf("bad upgrade task args:%s", err)
+	}
+	if task.ContractName == "" {
+		return nil, errors.New("bad upgrade task args:empty contract name")
+	}
+	return task, nil
+}
+
+// addUpgradeTimer 在指定高度回调 $contract 的 method 方法
+func addUpgradeTimer(ctx contract.KContext, height int64, method string, task *upgradeTask) error {
+	trigger, err := json.Marshal(&utils.TriggerDesc{
+		Height:   height,
+		Module:   "xkernel",
+		Contract: "$contract",
+		Method:   method,
+		Args: map[string]interface{}{
+			"contract_name":   task.ContractName,
+			"digest":          task.Digest,
+			"activate_height": task.ActivateHeight,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = ctx.Call("xkernel", utils.TimerTaskKernelContract, "Add", map[string][]byte{
+		"block_height": []byte(strconv.FormatInt(height, 10)),
+		"trigger":      trigger,
+	})
+	return err
+}
+
+func upgradeTaskResponse(status int, msg string) *contract.Response {
+	return &contract.Response{
+		Status:  status,
+		Message: msg,
+	}
+}
+
+// setUpgradePolicy 为没有升级策略的合约设置升级策略
+func (m *managerImpl) setUpgradePolicy(ctx contract.KContext) (*contract.Response, error) {
+	contractName := ctx.Args()["contract_name"]
+	policyBuf := ctx.Args()["upgrade_policy"]
+	if contractName == nil || policyBuf == nil {
+		return nil, errors.New("invoke setUpgradePolicy error, contract name or upgrade policy is nil")
+	}
+	name := string(contractName)
+
+	err := m.core.VerifyContractOwnerPermission(name, ctx.AuthRequire())
+	if err != nil {
+		return nil, err
+	}
+	policy, err := parseUpgradePolicy(policyBuf)
+	if err != nil {
+		return nil, err
+	}
+	old, err := getUpgradePolicy(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if old != nil {
+		return nil, fmt.Errorf("contract %s already has upgrade policy, change it by proposeUpgrade", name)
+	}
+	if _, err := ctx.Get("contract", bridge.ContractCodeDescKey(name)); err != nil {
+		return nil, fmt.Errorf("contract %s not exists", name)
+	}
+
+	buf, _ := json.Marshal(policy)
+	if err := ctx.Put("contract", upgradePolicyKey(name), buf); err != nil {
+		return nil, err
+	}
+	if err := emitContractEvent(ctx, "ContractUpgradePolicySet", name, map[string]string{
+		"upgrade_policy": string(buf),
+	}); err != nil {
+		return nil, err
+	}
+	return &contract.Response{
+		Status: 200,
+		Body:   []byte("set upgrade policy success"),
+	}, nil
+}
+
+// proposeUpgrade 发起一次受升级策略约束的升级，新代码在 activate_height 高度生效。
+// 交易需要在时间锁开始的高度 activate_height - timelock 之前上链，否则升级不会被标记，到期后被丢弃；
+// 在 activate_height 之后才上链的升级不会被定时任务处理，需要合约所有者通过 cancelUpgrade 取消
+func (m *managerImpl) proposeUpgrade(ctx contract.KContext) (*contract.Response, error) {
+	args := ctx.Args()
+	contractName := args["contract_name"]
+	if contractName == nil {
+		return nil, errors.New("invoke proposeUpgrade error, contract name is nil")
+	}
+	name := string(contractName)
+
+	err := m.core.VerifyContractOwnerPermission(name, ctx.AuthRequire())
+	if err != nil {
+		return nil, err
+	}
+	policy, err := getUpgradePolicy(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("contract %s has no upgrade policy, upgrade it by upgradeContract", name)
+	}
+	activateHeight, err := strconv.ParseInt(string(args["activate_height"]), 10, 64)
+	if err != nil || activateHeight-policy.Timelock <= 0 {
+		return nil, fmt.Errorf("bad activate height %s, must be higher than %d",
+			args["activate_height"], policy.Timelock)
+	}
+	pending, err := getPendingUpgrade(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	// 只有 armUpgrade 标记为过期的升级可以直接被替换，是否过期只由合约状态决定，与节点当前高度无关。
+	// 原升级的 activateUpgrade 定时任务仍然会回调，新的升级不能使用相同的生效高度
+	if pending != nil && !pending.Expired {
+		return nil, fmt.Errorf("contract %s has a pending upgrade", name)
+	}
+	if pending != nil && pending.ActivateHeight == activateHeight {
+		return nil, fmt.Errorf("bad activate height %d, same as the expired upgrade", activateHeight)
+	}
+	if pending != nil {
+		if err := deletePendingUpgrade(ctx, name, pending); err != nil {
+			return nil, err
+		}
+	}
+
+	pending = &pendingUpgrade{
+		ActivateHeight: activateHeight,
+		Approved:       policy.Approval == upgradeApprovalOwner,
+		Armed:          policy.Timelock == 0,
+	}
+	if policyBuf := args["upgrade_policy"]; len(policyBuf) != 0 {
+		pending.Policy, err = parseUpgradePolicy(policyBuf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	code := args["contract_code"]
+	if len(code) != 0 {
+		digest, err := m.xbridge.CheckUpgrade(ctx, name, code, args["contract_abi"])
+		if err != nil {
+			return nil, err
+		}
+		pending.Digest = hex.EncodeToString(digest)
+		pending.Abi = args["contract_abi"]
+		if err := ctx.Put("contract", pendingCodeKey(name), code); err != nil {
+			return nil, err
+		}
+	} else if pending.Policy == nil {
+		return nil, errors.New("invoke proposeUpgrade error, contract code and upgrade policy are both nil")
+	}
+	if err := putPendingUpgrade(ctx, name, pending); err != nil {
+		return nil, err
+	}
+
+	task := &upgradeTask{
+		ContractName:   name,
+		Digest:         pending.Digest,
+		ActivateHeight: activateHeight,
+	}
+	// 时间锁开始的高度之后才批准的升级不会被 armUpgrade 标记，到期时不会生效
+	if !pending.Armed {
+		if err := addUpgradeTimer(ctx, activateHeight-policy.Timelock, "armUpgrade", task); err != nil {
+			return nil, err
+		}
+	}
+	if err := addUpgradeTimer(ctx, activateHeight, "activateUpgrade", task); err != nil {
+		return nil, err
+	}
+
+	if err := emitContractEvent(ctx, "ContractUpgradeProposed", name, map[string]string{
+		"digest":          pending.Digest,
+		"activate_height": strconv.FormatInt(activateHeight, 10),
+	}); err != nil {
+		return nil, err
+	}
+	return &contract.Response{
+		Status: 200,
+		Body:   []byte("propose upgrade success"),
+	}, nil
+}
+
+// approveUpgrade 批准等待生效的升级，proposal 方式由提案回调，acl 方式由 approvers 作为发起者调用
+func (m *managerImpl) approveUpgrade(ctx contract.KContext) (*contract.Response, error) {
+	var task *upgradeTask
+	var err error
+	if ctx.Caller() == utils.ProposalKernelContract {
+		task, err = parseUpgradeTask(ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		args := ctx.Args()
+		task = &upgradeTask{
+			ContractName: string(args["contract_name"]),
+			Digest:       string(args["digest"]),
+		}
+		task.ActivateHeight, err = strconv.ParseInt(string(args["activate_height"]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad activate height %s", args["activate_height"])
+		}
+	}
+
+	policy, err := getUpgradePolicy(ctx, task.ContractName)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := getPendingUpgrade(ctx, task.ContractName)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil || !pending.match(task) {
+		return nil, fmt.Errorf("no matching pending upgrade of contract %s", task.ContractName)
+	}
+	if pending.Approved {
+		return nil, fmt.Errorf("pending upgrade of contract %s is already approved", task.ContractName)
+	}
+	if pending.Expired {
+		return nil, fmt.Errorf("pending upgrade of contract %s is expired", task.ContractName)
+	}
+
+	approver := ctx.Caller()
+	switch policy.Approval {
+	case upgradeApprovalProposal:
+		if ctx.Caller() != utils.ProposalKernelContract {
+			return nil, fmt.Errorf("upgrade of contract %s must be approved by proposal", task.ContractName)
+		}
+		pending.Approved = true
+	case upgradeApprovalACL:
+		approver = ctx.Initiator()
+		if !containsString(policy.Approvers, approver) {
+			return nil, fmt.Errorf("%s is not an upgrade approver of contract %s", approver, task.ContractName)
+		}
+		if containsString(pending.Approvals, approver) {
+			return nil, fmt.Errorf("%s has already approved the upgrade", approver)
+		}
+		pending.Approvals = append(pending.Approvals, approver)
+		pending.Approved = len(pending.Approvals) >= policy.Threshold
+	default:
+		return nil, fmt.Errorf("upgrade of contract %s does not need approval", task.ContractName)
+	}
+	if err := putPendingUpgrade(ctx, task.ContractName, pending); err != nil {
+		return nil, err
+	}
+
+	if err := emitContractEvent(ctx, "ContractUpgradeApproved", task.ContractName, map[string]string{
+		"digest":   pending.Digest,
+		"approver": approver,
+		"approved": strconv.FormatBool(pending.Approved),
+	}); err != nil {
+		return nil, err
+	}
+	return &contract.Response{
+		Status: 200,
+		Body:   []byte("approve upgrade success"),
+	}, nil
+}
+
+// armUpgrade 由定时任务在时间锁开始的高度回调，标记升级在这个高度之前已经批准，
+// 时间锁从批准完成之后才开始计算
+func (m *managerImpl) armUpgrade(ctx contract.KContext) (*contract.Response, error) {
+	if ctx.Caller() != utils.TimerTaskKernelContract {
+		return nil, fmt.Errorf("caller %s no authority to armUpgrade", ctx.Caller())
+	}
+	task, err := parseUpgradeTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := getPendingUpgrade(ctx, task.ContractName)
+	if err != nil {
+		return nil, err
+	}
+	// 已经取消的升级留下的定时任务
+	if !pending.match(task) {
+		return upgradeTaskResponse(utils.StatusException, "no matching pending upgrade"), nil
+	}
+	// 结果记录在升级中，没有在时间锁开始之前批准的升级标记为过期
+	if pending.Approved {
+		pending.Armed = true
+	} else {
+		pending.Expired = true
+	}
+	if err := putPendingUpgrade(ctx, task.ContractName, pending); err != nil {
+		return nil, err
+	}
+	if pending.Expired {
+		return upgradeTaskResponse(utils.StatusException, "upgrade not approved before timelock"), nil
+	}
+	return upgradeTaskResponse(utils.StatusOK, "success"), nil
+}
+
+// activateUpgrade 由定时任务在生效高度回调，升级已经批准并且满足时间锁时写入新代码，否则丢弃升级
+func (m *managerImpl) activateUpgrade(ctx contract.KContext) (*contract.Response, error) {
+	if ctx.Caller() != utils.TimerTaskKernelContract {
+		return nil, fmt.Errorf("caller %s no authority to activateUpgrade", ctx.Caller())
+	}
+	task, err := parseUpgradeTask(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := task.ContractName
+	pending, err := getPendingUpgrade(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !pending.match(task) {
+		return upgradeTaskResponse(utils.StatusException, "no matching pending upgrade"), nil
+	}
+
+	if !pending.Approved || !pending.Armed {
+		reason := "not approved"
+		switch {
+		case pending.Expired:
+			reason = "not approved before timelock"
+		case pending.Approved:
+			reason = "timelock not satisfied"
+		}
+		return expireUpgrade(ctx, name, pending, reason)
+	}
+
+	// 先写入新代码再删除等待生效的升级，定时任务会忽略回调的错误，
+	// 写入失败(如合约已经销毁)时同样丢弃升级并记录事件，避免升级无声无息地消失
+	if pending.Digest != "" {
+		code, err := ctx.Get("contract", pendingCodeKey(name))
+		if err != nil {
+			return nil, err
+		}
+		limit, err := m.xbridge.ApplyUpgrade(ctx, name, code, pending.Abi)
+		if err != nil {
+			return expireUpgrade(ctx, name, pending, "apply failed: "+err.Error())
+		}
+		ctx.AddResourceUsed(limit)
+	}
+	if err := deletePendingUpgrade(ctx, name, pending); err != nil {
+		return nil, err
+	}
+	if pending.Policy != nil {
+		buf, _ := json.Marshal(pending.Policy)
+		if err := ctx.Put("contract", upgradePolicyKey(name), buf); err != nil {
+			return nil, err
+		}
+	}
+	if err := emitContractEvent(ctx, "ContractUpgraded", name, map[string]string{
+		"digest":          pending.Digest,
+		"activate_height": strconv.FormatInt(pending.ActivateHeight, 10),
+	}); err != nil {
+		return nil, err
+	}
+	return upgradeTaskResponse(utils.StatusOK, "success"), nil
+}
+
+// activatedUpgrades 返回 height 高度的定时任务中 activateUpgrade 回调的合约，用于预编译生效的新代码
+func (m *managerImpl) activatedUpgrades(height []byte) []string {
+	startKey := utils.MakeTimerBlockHeightPrefix(string(height))
+	endKey := utils.PrefixRange([]byte(utils.MakeTimerBlockHeightPrefixSeparator(string(height))))
+	iter, err := m.xmreader.Select(utils.GetTimerBucket(), []byte(startKey), endKey)
+	if err != nil {
+		return nil
+	}
+	defer iter.Close()
+	var names []string
+	for iter.Next() {
+		var trigger utils.TriggerDesc
+		if err := json.Unmarshal(iter.Value().GetPureData().GetValue(), &trigger); err != nil {
+			continue
+		}
+		if trigger.Contract != "$contract" || trigger.Method != "activateUpgrade" {
+			continue
+		}
+		if name, ok := trigger.Args["contract_name"].(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// expireUpgrade 丢弃没有生效的升级并记录原因
+func expireUpgrade(ctx contract.KContext, contractName string, pending *pendingUpgrade, reason string) (*contract.Response, error) {
+	if err := deletePendingUpgrade(ctx, contractName, pending); err != nil {
+		return nil, err
+	}
+	if err := emitContractEvent(ctx, "ContractUpgradeExpired", contractName, map[string]string{
+		"digest": pending.Digest,
+		"reason": reason,
+	}); err != nil {
+		return nil, err
+	}
+	return upgradeTaskResponse(utils.StatusException, "upgrade expired, "+reason), nil
+}
+
+// cancelPendingUpgrade 删除合约等待生效的升级，没有等待生效的升级时返回 nil
+func cancelPendingUpgrade(ctx contract.KContext, contractName string) (*pendingUpgrade, error) {
+	pending, err := getPendingUpgrade(ctx, contractName)
+	if err != nil || pending == nil {
+		return nil, err
+	}
+	if err := deletePendingUpgrade(ctx, contractName, pending); err != nil {
+		return nil, err
+	}
+	if err := emitContractEvent(ctx, "ContractUpgradeCancelled", contractName, map[string]string{
+		"digest": pending.Digest,
+	}); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// cancelUpgrade 由合约所有者取消等待生效的升级
+func (m *managerImpl) cancelUpgrade(ctx contract.KContext) (*contract.Response, error) {
+	contractName := ctx.Args()["contract_name"]
+	if contractName == nil {
+		return nil, errors.New("invoke cancelUpgrade error, contract name is nil")
+	}
+	name := string(contractName)
+
+	err := m.core.VerifyContractOwnerPermission(name, ctx.AuthRequire())
+	if err != nil {
+		return nil, err
+	}
+	pending, err := cancelPendingUpgrade(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if pending == nil {
+		return nil, fmt.Errorf("contract %s has no pending upgrade", name)
+	}
+	return &contract.Response{
+		Status: 200,
+		Body:   []byte("cancel upgrade success"),
+	}, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
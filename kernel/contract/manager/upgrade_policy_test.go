@@ -0,0 +1,360 @@
+package manager
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/crypto/core/hash"
+	_ "github.com/xuperchain/xupercore/bcs/contract/evm"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/contract/mock"
+	"github.com/xuperchain/xupercore/kernel/contract/proposal/timer"
+	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func invokeKernel(t *testing.T, th *mock.TestHelper, contractName, method, initiator string,
+	args map[string][]byte) ([]*protos.ContractEvent, error) {
+	m := th.Manager()
+	state, err := m.NewStateSandbox(&contract.SandboxConfig{
+		XMReader: th.State(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := m.NewContext(&contract.ContextConfig{
+		Module:         "xkernel",
+		ContractName:   contractName,
+		State:          state,
+		Initiator:      initiator,
+		ResourceLimits: contract.MaxLimits,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Release()
+	if _, err := ctx.Invoke(method, args); err != nil {
+		return nil, err
+	}
+	th.Commit(state)
+	return state.Events(), nil
+}
+
+// runTimer 模拟出块时执行定时任务
+func runTimer(t *testing.T, th *mock.TestHelper, height string) []*protos.ContractEvent {
+	events, err := invokeKernel(t, th, "$timer_task", "Do", "", map[string][]byte{
+		"block_height": []byte(height),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func eventNames(events []*protos.ContractEvent) string {
+	var names []string
+	for _, event := range events {
+		names = append(names, event.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestUpgradePolicy(t *testing.T) {
+	th := mock.NewTestHelper(contractConfig)
+	defer th.Close()
+	timerMethod := timer.NewKernContractMethod("xuper")
+	th.Manager().GetKernRegistry().RegisterKernMethod("$timer_task", "Add", timerMethod.Add)
+	th.Manager().GetKernRegistry().RegisterKernMethod("$timer_task", "Do", timerMethod.Do)
+	putTestContract(th, "wasm")
+
+	const (
+		approverA = "approverA"
+		approverB = "approverB"
+	)
+	invoke := func(method, initiator string, args map[string]string) ([]*protos.ContractEvent, error) {
+		bufArgs := map[string][]byte{
+			"contract_name": []byte(lifecycleContract),
+		}
+		for k, v := range args {
+			bufArgs[k] = []byte(v)
+		}
+		return invokeKernel(t, th, "$contract", method, initiator, bufArgs)
+	}
+	policy := func() string {
+		return string(getValue(t, th, "contract", lifecycleContract+".upgrade_policy"))
+	}
+
+	_, err := invoke("setUpgradePolicy", "", map[string]string{
+		"upgrade_policy": `{"approval":"acl","approvers":["approverA","approverA"],"threshold":1}`,
+	})
+	if err == nil {
+		t.Error("expect error when approvers are duplicated")
+	}
+	_, err = invoke("setUpgradePolicy", "", map[string]string{
+		"upgrade_policy": `{"approval":"acl","approvers":["approverA","approverB"],"threshold":2,"timelock":5}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = invoke("setUpgradePolicy", "", map[string]string{
+		"upgrade_policy": `{"approval":"owner"}`,
+	})
+	if err == nil {
+		t.Error("expect error when setting upgrade policy twice")
+	}
+	_, err = invoke("upgradeContract", "", map[string]string{
+		"contract_code": "code",
+	})
+	if err == nil || !strings.Contains(err.Error(), "proposeUpgrade") {
+		t.Errorf("expect direct upgrade rejected, got %v", err)
+	}
+
+	newPolicy := `{"approval":"owner","timelock":0}`
+	propose := func(activateHeight string) {
+		events, err := invoke("proposeUpgrade", "", map[string]string{
+			"upgrade_policy":  newPolicy,
+			"activate_height": activateHeight,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if eventNames(events) != "ContractUpgradeProposed" {
+			t.Errorf("unexpected events %v", events)
+		}
+	}
+	if _, err := invoke("proposeUpgrade", "", map[string]string{
+		"upgrade_policy":  newPolicy,
+		"activate_height": "5",
+	}); err == nil {
+		t.Error("expect error when activate height is within timelock")
+	}
+
+	// 没有足够的批准，时间锁开始时标记为过期
+	propose("10")
+	if _, err := invoke("proposeUpgrade", "", map[string]string{
+		"upgrade_policy":  newPolicy,
+		"activate_height": "12",
+	}); err == nil {
+		t.Error("expect error when another upgrade is pending")
+	}
+	approve := func(initiator, activateHeight string) ([]*protos.ContractEvent, error) {
+		return invoke("approveUpgrade", initiator, map[string]string{
+			"activate_height": activateHeight,
+		})
+	}
+	if _, err := approve(approverA, "10"); err != nil {
+		t.Fatal(err)
+	}
+	runTimer(t, th, "5")
+	if _, err := approve(approverB, "10"); err == nil {
+		t.Error("expect error when approving an expired upgrade")
+	}
+	// 过期的升级可以被替换，原升级的定时任务不再匹配
+	if _, err := invoke("proposeUpgrade", "", map[string]string{
+		"upgrade_policy":  newPolicy,
+		"activate_height": "10",
+	}); err == nil {
+		t.Error("expect error when replacing with the same activate height")
+	}
+	propose("12")
+	if events := runTimer(t, th, "10"); len(events) != 0 {
+		t.Errorf("unexpected events %v", events)
+	}
+	if events := runTimer(t, th, "12"); eventNames(events) != "ContractUpgradeExpired" {
+		t.Errorf("unexpected events %v", events)
+	}
+
+	// 满足批准和时间锁，到期后生效
+	propose("20")
+	if _, err := approve("approverC", "20"); err == nil {
+		t.Error("expect error when approved by a non approver")
+	}
+	if _, err := approve(approverA, "20"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := approve(approverA, "20"); err == nil {
+		t.Error("expect error when approving twice")
+	}
+	if _, err := approve(approverB, "21"); err == nil {
+		t.Error("expect error when approving a mismatched upgrade")
+	}
+	if _, err := approve(approverB, "20"); err != nil {
+		t.Fatal(err)
+	}
+	runTimer(t, th, "15")
+	if events := runTimer(t, th, "20"); eventNames(events) != "ContractUpgraded" {
+		t.Errorf("unexpected events %v", events)
+	}
+	if policy() != newPolicy {
+		t.Errorf("unexpected upgrade policy %s", policy())
+	}
+
+	// 取消的升级留下的定时任务不生效
+	newPolicy = `{"approval":"proposal","timelock":0}`
+	propose("30")
+	events, err := invoke("cancelUpgrade", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eventNames(events) != "ContractUpgradeCancelled" {
+		t.Errorf("unexpected events %v", events)
+	}
+	if events := runTimer(t, th, "30"); len(events) != 0 {
+		t.Errorf("unexpected events %v", events)
+	}
+	if policy() != `{"approval":"owner","timelock":0}` {
+		t.Errorf("unexpected upgrade policy %s", policy())
+	}
+	if _, err := invoke("activateUpgrade", "", map[string]string{
+		"args": `{"contract_name":"counter","activate_height":30}`,
+	}); err == nil {
+		t.Error("expect error when activateUpgrade is not called by timer")
+	}
+
+}
+
+func TestUpgradePolicyCode(t *testing.T) {
+	th := mock.NewTestHelper(&contract.ContractConfig{
+		EnableUpgrade: true,
+		Xkernel: contract.XkernelConfig{
+			Enable: true,
+			Driver: "default",
+		},
+		EVM: contract.EVMConfig{
+			Enable: true,
+			Driver: "evm",
+		},
+		LogDriver: mock.NewMockLogger(),
+	})
+	defer th.Close()
+	registry := th.Manager().GetKernRegistry()
+	timerMethod := timer.NewKernContractMethod("xuper")
+	registry.RegisterKernMethod("$timer_task", "Add", timerMethod.Add)
+	registry.RegisterKernMethod("$timer_task", "Do", timerMethod.Do)
+	// 模拟通过的提案回调 approveUpgrade
+	registry.RegisterKernMethod(utils.ProposalKernelContract, "Trigger", func(ctx contract.KContext) (*contract.Response, error) {
+		return ctx.Call("xkernel", "$contract", "approveUpgrade", map[string][]byte{
+			"args":   ctx.Args()["args"],
+			"height": []byte("1"),
+		})
+	})
+	putTestContract(th, "evm")
+
+	invoke := func(method string, args map[string]string) ([]*protos.ContractEvent, error) {
+		bufArgs := map[string][]byte{
+			"contract_name": []byte(lifecycleContract),
+		}
+		for k, v := range args {
+			bufArgs[k] = []byte(v)
+		}
+		return invokeKernel(t, th, "$contract", method, "", bufArgs)
+	}
+	propose := func(code, activateHeight string) {
+		if _, err := invoke("proposeUpgrade", map[string]string{
+			"contract_code":   code,
+			"contract_abi":    code + ".abi",
+			"activate_height": activateHeight,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	approveByProposal := func(code, activateHeight string) {
+		digest := hex.EncodeToString(hash.DoubleSha256([]byte(code)))
+		task := fmt.Sprintf(`{"contract_name":%q,"digest":%q,"activate_height":%s}`, lifecycleContract, digest, activateHeight)
+		if _, err := invokeKernel(t, th, utils.ProposalKernelContract, "Trigger", "", map[string][]byte{
+			"args": []byte(task),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := invoke("setUpgradePolicy", map[string]string{
+		"upgrade_policy": `{"approval":"proposal","timelock":5}`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 升级在时间锁开始之后才上链，不会被标记，批准之后时间锁仍然不满足
+	runTimer(t, th, "5")
+	propose("code1", "10")
+	if value := getValue(t, th, "contract", lifecycleContract+".pending_code"); string(value) != "code1" {
+		t.Errorf("unexpected pending code %q", value)
+	}
+	if _, err := invoke("approveUpgrade", map[string]string{
+		"digest":          hex.EncodeToString(hash.DoubleSha256([]byte("code1"))),
+		"activate_height": "10",
+	}); err == nil {
+		t.Error("expect error when approved without proposal")
+	}
+	approveByProposal("code1", "10")
+	events := runTimer(t, th, "10")
+	if eventNames(events) != "ContractUpgradeExpired" || !strings.Contains(string(events[0].Body), "timelock not satisfied") {
+		t.Errorf("unexpected events %v", events)
+	}
+	if value := getValue(t, th, "contract", lifecycleContract+".code"); string(value) != "code" {
+		t.Errorf("expect code unchanged, got %q", value)
+	}
+	if value := getValue(t, th, "contract", lifecycleContract+".pending_code"); !sandbox.IsDelFlag(value) {
+		t.Errorf("expect pending code deleted, got %q", value)
+	}
+
+	// 提案在时间锁开始之前通过，到期后写入新代码、接口描述和版本记录
+	propose("code2", "20")
+	approveByProposal("code2", "20")
+	runTimer(t, th, "15")
+	if events := runTimer(t, th, "20"); eventNames(events) != "ContractUpgraded" {
+		t.Errorf("unexpected events %v", events)
+	}
+	// 区块确认后预编译定时任务生效的新代码
+	if names := th.Manager().(*managerImpl).activatedUpgrades([]byte("20")); len(names) != 1 || names[0] != lifecycleContract {
+		t.Errorf("unexpected activated upgrades %v", names)
+	}
+	if value := getValue(t, th, "contract", lifecycleContract+".code"); string(value) != "code2" {
+		t.Errorf("unexpected code %q", value)
+	}
+	if value := getValue(t, th, "contract", lifecycleContract+".abi"); string(value) != "code2.abi" {
+		t.Errorf("unexpected abi %q", value)
+	}
+	desc := new(protos.WasmCodeDesc)
+	if err := proto.Unmarshal(getValue(t, th, "contract", string(bridge.ContractCodeDescKey(lifecycleContract))), desc); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(desc.Digest, hash.DoubleSha256([]byte("code2"))) || desc.ContractType != "evm" {
+		t.Errorf("unexpected desc %v", desc)
+	}
+	// 升级前部署的合约没有部署时的版本记录
+	if value := getValue(t, th, "contract", lifecycleContract+".version"); string(value) != "1" {
+		t.Errorf("unexpected version %q", value)
+	}
+	version := new(protos.ContractVersion)
+	if err := proto.Unmarshal(getValue(t, th, "contract", fmt.Sprintf("%s/version/%020d", lifecycleContract, 1)), version); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(version.Digest, desc.Digest) {
+		t.Errorf("unexpected version %v", version)
+	}
+
+	// 销毁合约时取消等待生效的升级
+	propose("code3", "30")
+	approveByProposal("code3", "30")
+	events, err := invoke("destroy", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eventNames(events) != "ContractUpgradeCancelled,ContractDestroyed" {
+		t.Errorf("unexpected events %v", events)
+	}
+	if value := getValue(t, th, "contract", lifecycleContract+".pending_code"); !sandbox.IsDelFlag(value) {
+		t.Errorf("expect pending code deleted, got %q", value)
+	}
+	runTimer(t, th, "25")
+	if events := runTimer(t, th, "30"); len(events) != 0 {
+		t.Errorf("unexpected events %v", events)
+	}
+}
@@ -8,6 +8,7 @@ import (
 )
 
 type fakeChainCore struct {
+	tipHeight int64
 }
 
 // GetAccountAddress get addresses associated with account name
@@ -31,9 +32,20 @@ func (t *fakeChainCore) QueryBlock(blockid []byte) (ledger.BlockHandle, error) {
 	}), nil
 }
 
+func (t *fakeChainCore) QueryBlockByHeight(height int64) (ledger.BlockHandle, error) {
+	return state.NewBlockAgent(&xldgpb.InternalBlock{
+		Blockid: []byte("testblockid"),
+		Height:  height,
+	}), nil
+}
+
 func (t *fakeChainCore) QueryTransaction(txid []byte) (*pb.Transaction, error) {
 	return &pb.Transaction{
 		Txid:    "testtxid",
 		Blockid: "testblockd",
 	}, nil
 }
+
+func (t *fakeChainCore) QueryTipBlockHeight() (int64, error) {
+	return t.tipHeight, nil
+}
@@ -27,6 +27,7 @@ type TestHelper struct {
 	utxo       *contract.UTXORWSet
 	utxoReader sandbox.UtxoReader
 	state      *sandbox.MemXModel
+	core       *fakeChainCore
 	manager    contract.Manager
 }
 
@@ -53,6 +54,7 @@ func NewTestHelper(cfg *contract.ContractConfig) *TestHelper {
 		basedir: basedir,
 		manager: m,
 		state:   state,
+		core:    core,
 	}
 	th.initAccount()
 	return th
@@ -73,6 +75,11 @@ func (t *TestHelper) UTXOState() *contract.UTXORWSet {
 	return t.utxo
 }
 
+// SetTipBlockHeight sets the trunk height seen by contracts
+func (t *TestHelper) SetTipBlockHeight(height int64) {
+	t.core.tipHeight = height
+}
+
 func (t *TestHelper) initAccount() {
 	t.state.Put(utils.GetAccountBucket(), []byte(ContractAccount), &ledger.VersionedData{
 		RefTxid:  []byte("txid"),
@@ -128,7 +135,7 @@ func (t *TestHelper) Deploy(module, lang, contractName string, bin []byte, args
 		"contract_desc": descbuf,
 		"init_args":     argsBuf,
 	}
-	if bridge.ContractType(module) == bridge.TypeEvm {
+	if bridge.ContractType(module) == bridge.TypeEvm || args["contract_abi"] != nil {
 		invokeArgs["contract_abi"] = args["contract_abi"]
 	}
 	resp, err := ctx.Invoke("deployContract", invokeArgs)
@@ -46,6 +46,11 @@ func (m *MemXModel) Put(bucket string, key []byte, value *ledger.VersionedData)
 	return nil
 }
 
+// Del 删除一个key，只用于回滚沙盒的写集
+func (m *MemXModel) Del(bucket string, key []byte) {
+	m.tree.Remove(makeRawKey(bucket, key))
+}
+
 func (t *MemXModel) GetUncommited(bucket string, key []byte) (*ledger.VersionedData, error) {
 	return nil, fmt.Errorf("not support")
 }
@@ -3,14 +3,19 @@ package sandbox
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/protos"
 	"math/big"
 )
 
 type UTXOReader struct {
-	inputCache []*protos.TxInput
-	inputIdx   int
+	inputCache  []*protos.TxInput
+	inputIdx    int
+	reads       []*protos.TxInput
+	frozenReads []*protos.TxInput
+	addrs       map[string]bool
+	frozenAddrs map[string]bool
 }
 
 func NewUTXOReaderFromInput(input []*protos.TxInput) contract.UtxoReader {
@@ -20,6 +25,45 @@ func NewUTXOReaderFromInput(input []*protos.TxInput) contract.UtxoReader {
 	}
 }
 
+// NewUTXOReaderFromRWSet 使用交易中记录的utxo输入和余额查询读到的地址和utxo重放合约的utxo操作
+func NewUTXOReaderFromRWSet(input []*protos.TxInput, rwset *contract.UTXORWSet) contract.UtxoReader {
+	return &UTXOReader{
+		inputCache:  input,
+		inputIdx:    0,
+		reads:       rwset.Reads,
+		frozenReads: rwset.FrozenReads,
+		addrs:       addrSet(rwset.ReadAddrs),
+		frozenAddrs: addrSet(rwset.FrozenReadAddrs),
+	}
+}
+
+func addrSet(addrs []string) map[string]bool {
+	set := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = true
+	}
+	return set
+}
+
+// ListUtxo 返回记录中属于addr的utxo，沙盒对每个地址只会读取一次。
+// 读取没有记录的地址说明交易的读集不完整
+func (r *UTXOReader) ListUtxo(addr string, frozen bool) ([]*protos.TxInput, error) {
+	reads, addrs := r.reads, r.addrs
+	if frozen {
+		reads, addrs = r.frozenReads, r.frozenAddrs
+	}
+	if !addrs[addr] {
+		return nil, fmt.Errorf("utxo read of address %s not recorded", addr)
+	}
+	var result []*protos.TxInput
+	for _, input := range reads {
+		if string(input.GetFromAddr()) == addr {
+			result = append(result, input)
+		}
+	}
+	return result, nil
+}
+
 func (r *UTXOReader) SelectUtxo(from string, amount *big.Int, lock bool, excludeUnconfirmed bool) ([]*protos.TxInput, [][]byte, *big.Int, error) {
 	fromBytes := []byte(from)
 	inputCache := r.inputCache[r.inputIdx:]
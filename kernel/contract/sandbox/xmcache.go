@@ -24,14 +24,21 @@ var (
 )
 
 var (
-	contractUtxoInputKey  = []byte("ContractUtxo.Inputs")
-	contractUtxoOutputKey = []byte("ContractUtxo.Outputs")
-	crossQueryInfosKey    = []byte("CrossQueryInfos")
-	contractEventKey      = []byte("contractEvent")
+	contractUtxoInputKey      = []byte("ContractUtxo.Inputs")
+	contractUtxoOutputKey     = []byte("ContractUtxo.Outputs")
+	contractUtxoReadKey       = []byte("ContractUtxo.Reads")
+	contractUtxoFrozenKey     = []byte("ContractUtxo.FrozenReads")
+	contractUtxoAddrKey       = []byte("ContractUtxo.ReadAddrs")
+	contractUtxoFrozenAddrKey = []byte("ContractUtxo.FrozenReadAddrs")
+	contractBlockReadKey      = []byte("ContractBlock.Reads")
+	crossQueryInfosKey        = []byte("CrossQueryInfos")
+	contractEventKey          = []byte("contractEvent")
 )
 
 var (
-	_ contract.StateSandbox = (*XMCache)(nil)
+	_ contract.StateSandbox     = (*XMCache)(nil)
+	_ contract.SavepointSandbox = (*XMCache)(nil)
+	_ contract.BlockReadState   = (*XMCache)(nil)
 )
 
 // UtxoReader manages utxos
@@ -51,6 +58,25 @@ type XMCache struct {
 	utxoSandbox *utxo.UTXOSandbox
 	// crossQueryCache *CrossQueryCache
 	events []*protos.ContractEvent
+	// 合约读取过的主干区块，按读取顺序去重，回滚保存点时同读集一样保留
+	blockReads [][]byte
+
+	// 存在保存点时记录outputsCache被覆盖前的值，用于回滚
+	journal    []journalEntry
+	savepoints []savepoint
+}
+
+type journalEntry struct {
+	bucket string
+	key    []byte
+	// prev 为nil表示写入前outputsCache中没有这个key
+	prev *ledger.VersionedData
+}
+
+type savepoint struct {
+	journal int
+	events  int
+	utxo    utxo.UTXOSnapshot
 }
 
 // NewXModelCache new an instance of XModel Cache
@@ -146,9 +172,67 @@ func (xc *XMCache) Put(bucket string, key []byte, value []byte) error {
 		// put 前先强制get一下
 		xc.Get(bucket, key)
 	}
+	if len(xc.savepoints) > 0 {
+		prev, err := xc.outputsCache.Get(bucket, key)
+		if err != nil && err != ErrNotFound {
+			return err
+		}
+		xc.journal = append(xc.journal, journalEntry{
+			bucket: bucket,
+			key:    key,
+			prev:   prev,
+		})
+	}
 	return xc.outputsCache.Put(bucket, key, val)
 }
 
+// Savepoint 创建一个保存点，返回值用于回滚或释放
+func (xc *XMCache) Savepoint() int {
+	xc.savepoints = append(xc.savepoints, savepoint{
+		journal: len(xc.journal),
+		events:  len(xc.events),
+		utxo:    xc.utxoSandbox.Snapshot(),
+	})
+	return len(xc.savepoints) - 1
+}
+
+// RollbackTo 丢弃保存点之后的写集、转账和事件，读集保持不变
+func (xc *XMCache) RollbackTo(id int) error {
+	if id < 0 || id >= len(xc.savepoints) {
+		return fmt.Errorf("savepoint %d not found", id)
+	}
+	sp := xc.savepoints[id]
+	for i := len(xc.journal) - 1; i >= sp.journal; i-- {
+		entry := xc.journal[i]
+		if entry.prev == nil {
+			xc.outputsCache.Del(entry.bucket, entry.key)
+			continue
+		}
+		xc.outputsCache.Put(entry.bucket, entry.key, entry.prev)
+	}
+	xc.journal = xc.journal[:sp.journal]
+	xc.events = xc.events[:sp.events]
+	xc.utxoSandbox.RollbackTo(sp.utxo)
+	xc.releaseSavepoints(id)
+	return nil
+}
+
+// ReleaseSavepoint 释放保存点，保存点之后的修改归入上一层保存点
+func (xc *XMCache) ReleaseSavepoint(id int) error {
+	if id < 0 || id >= len(xc.savepoints) {
+		return fmt.Errorf("savepoint %d not found", id)
+	}
+	xc.releaseSavepoints(id)
+	return nil
+}
+
+func (xc *XMCache) releaseSavepoints(id int) {
+	xc.savepoints = xc.savepoints[:id]
+	if len(xc.savepoints) == 0 {
+		xc.journal = nil
+	}
+}
+
 // Del delete one key from outPutCache, marked its value as `DelFlag`
 func (xc *XMCache) Del(bucket string, key []byte) error {
 	return xc.Put(bucket, key, []byte(DelFlag))
@@ -228,6 +312,11 @@ func (xc *XMCache) Transfer(from, to string, amount *big.Int) error {
 	return xc.utxoSandbox.Transfer(from, to, amount)
 }
 
+// GetBalance returns the balance of addr seen by the utxo sandbox
+func (xc *XMCache) GetBalance(addr string, frozen bool) (*big.Int, error) {
+	return xc.utxoSandbox.GetBalance(addr, frozen)
+}
+
 // UTXORWSet returns the inputs and outputs of utxo
 func (xc *XMCache) UTXORWSet() *contract.UTXORWSet {
 	return xc.utxoSandbox.GetUTXORWSets()
@@ -264,7 +353,31 @@ func (xc *XMCache) flushUTXORWSet() error {
 			return err
 		}
 	}
-	return nil
+	err = xc.flushUTXOReads(contractUtxoReadKey, UTXORWSet.Reads)
+	if err != nil {
+		return err
+	}
+	err = xc.flushUTXOReads(contractUtxoFrozenKey, UTXORWSet.FrozenReads)
+	if err != nil {
+		return err
+	}
+	err = xc.flushUTXOReads(contractUtxoAddrKey, xmodel.MakeUtxoReadAddrs(UTXORWSet.ReadAddrs))
+	if err != nil {
+		return err
+	}
+	return xc.flushUTXOReads(contractUtxoFrozenAddrKey, xmodel.MakeUtxoReadAddrs(UTXORWSet.FrozenReadAddrs))
+}
+
+// flushUTXOReads put utxos read by GetBalance to TransientBucket
+func (xc *XMCache) flushUTXOReads(key []byte, reads []*protos.TxInput) error {
+	if len(reads) == 0 {
+		return nil
+	}
+	buf, err := xmodel.MarshalMessages(reads)
+	if err != nil {
+		return err
+	}
+	return xc.Put(TransientBucket, key, buf)
 }
 
 // ParseContractUtxoInputs parse contract utxo inputs from tx write sets
@@ -458,6 +571,32 @@ func (xc *XMCache) AddEvent(events ...*protos.ContractEvent) {
 	xc.events = append(xc.events, events...)
 }
 
+// Events returns contract events added to xmodel cache
+func (xc *XMCache) Events() []*protos.ContractEvent {
+	return xc.events
+}
+
+// AddBlockRead records a trunk block read by contract
+func (xc *XMCache) AddBlockRead(blockid []byte) {
+	for _, id := range xc.blockReads {
+		if bytes.Equal(id, blockid) {
+			return
+		}
+	}
+	xc.blockReads = append(xc.blockReads, blockid)
+}
+
+func (xc *XMCache) writeBlockReadRWSet() error {
+	if len(xc.blockReads) == 0 {
+		return nil
+	}
+	buf, err := xmodel.MarshalMessages(xmodel.MakeBlockReads(xc.blockReads))
+	if err != nil {
+		return err
+	}
+	return xc.Put(TransientBucket, contractBlockReadKey, buf)
+}
+
 func (xc *XMCache) writeEventRWSet() error {
 	if len(xc.events) == 0 {
 		return nil
@@ -484,6 +623,11 @@ func (xc *XMCache) Flush() error {
 	// 	return err
 	// }
 
+	err = xc.writeBlockReadRWSet()
+	if err != nil {
+		return err
+	}
+
 	err = xc.writeEventRWSet()
 	if err != nil {
 		return err
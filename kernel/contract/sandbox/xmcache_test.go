@@ -1,13 +1,17 @@
 package sandbox
 
 import (
+	"bytes"
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"math/big"
 	"math/rand"
 	"sort"
 	"testing"
 
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/protos"
 )
 
 func TestXMCachePutGet(t *testing.T) {
@@ -104,3 +108,178 @@ func TestXMCacheIterator(t *testing.T) {
 		t.Logf("%s", r.GetPureData().GetKey())
 	}
 }
+
+func TestXMCacheGetBalance(t *testing.T) {
+	newInput := func(addr string, amount int64, offset int32) *protos.TxInput {
+		return &protos.TxInput{
+			RefTxid:   []byte("txid"),
+			RefOffset: offset,
+			FromAddr:  []byte(addr),
+			Amount:    big.NewInt(amount).Bytes(),
+		}
+	}
+	reads := []*protos.TxInput{newInput("a", 3, 0), newInput("a", 5, 1)}
+	frozenReads := []*protos.TxInput{newInput("a", 7, 2)}
+	mc := NewXModelCache(&contract.SandboxConfig{
+		XMReader: NewMemXModel(),
+		UTXOReader: NewUTXOReaderFromRWSet(reads[:1], &contract.UTXORWSet{
+			Reads:           reads,
+			FrozenReads:     frozenReads,
+			ReadAddrs:       []string{"a", "b"},
+			FrozenReadAddrs: []string{"a"},
+		}),
+	})
+
+	expectBalance := func(addr string, frozen bool, expect int64) {
+		t.Helper()
+		balance, err := mc.GetBalance(addr, frozen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if balance.Int64() != expect {
+			t.Errorf("balance of %s frozen:%v expect %d got %s", addr, frozen, expect, balance)
+		}
+	}
+	expectBalance("a", false, 8)
+	if err := mc.Transfer("a", "b", big.NewInt(2)); err != nil {
+		t.Fatal(err)
+	}
+	expectBalance("a", false, 6)
+	expectBalance("a", true, 7)
+	expectBalance("b", false, 2)
+	// 没有记录的地址不能重放
+	if _, err := mc.GetBalance("c", false); err == nil {
+		t.Error("expect error when reading an unrecorded address")
+	}
+
+	if err := mc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	var recorded, recordedAddrs []*protos.TxInput
+	for _, w := range mc.RWSet().WSet {
+		if w.GetBucket() != TransientBucket {
+			continue
+		}
+		var err error
+		switch {
+		case bytes.Equal(w.GetKey(), contractUtxoReadKey):
+			err = xmodel.UnmsarshalMessages(w.GetValue(), &recorded)
+		case bytes.Equal(w.GetKey(), contractUtxoAddrKey):
+			err = xmodel.UnmsarshalMessages(w.GetValue(), &recordedAddrs)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(recorded) != len(reads) {
+		t.Errorf("expect %d utxo reads recorded, got %d", len(reads), len(recorded))
+	}
+	// 没有utxo的地址b同样被记录
+	if len(recordedAddrs) != 2 || string(recordedAddrs[1].GetFromAddr()) != "b" {
+		t.Errorf("unexpected recorded addresses %v", recordedAddrs)
+	}
+}
+
+func TestXMCacheSavepoint(t *testing.T) {
+	input := &protos.TxInput{
+		RefTxid:  []byte("txid"),
+		FromAddr: []byte("a"),
+		Amount:   big.NewInt(5).Bytes(),
+	}
+	mc := NewXModelCache(&contract.SandboxConfig{
+		XMReader: NewMemXModel(),
+		UTXOReader: NewUTXOReaderFromRWSet([]*protos.TxInput{input}, &contract.UTXORWSet{
+			ReadAddrs: []string{"b"},
+		}),
+	})
+	expectValue := func(key, expect string) {
+		t.Helper()
+		v, err := mc.Get("b1", []byte(key))
+		if expect == "" {
+			if err == nil {
+				t.Errorf("expect %s not found, got %s", key, v)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(v) != expect {
+			t.Errorf("expect %s got %s", expect, v)
+		}
+	}
+
+	mc.Put("b1", []byte("k1"), []byte("v1"))
+	sp := mc.Savepoint()
+	mc.Put("b1", []byte("k1"), []byte("v2"))
+	mc.Put("b1", []byte("k2"), []byte("v2"))
+	mc.AddEvent(&protos.ContractEvent{Name: "e1"})
+	if err := mc.Transfer("a", "b", big.NewInt(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 嵌套的保存点释放后，修改归入外层保存点
+	inner := mc.Savepoint()
+	mc.Put("b1", []byte("k3"), []byte("v3"))
+	if err := mc.ReleaseSavepoint(inner); err != nil {
+		t.Fatal(err)
+	}
+	expectValue("k3", "v3")
+
+	if err := mc.RollbackTo(sp); err != nil {
+		t.Fatal(err)
+	}
+	expectValue("k1", "v1")
+	expectValue("k2", "")
+	expectValue("k3", "")
+	if len(mc.Events()) != 0 {
+		t.Errorf("expect events rolled back, got %d", len(mc.Events()))
+	}
+	balance, err := mc.GetBalance("b", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Sign() != 0 {
+		t.Errorf("expect transfer rolled back, got balance %s", balance)
+	}
+	// 选中的utxo保留在输入中并全额找零给原地址
+	utxoRWSet := mc.UTXORWSet()
+	if len(utxoRWSet.Rset) != 1 || len(utxoRWSet.WSet) != 1 || string(utxoRWSet.WSet[0].GetToAddr()) != "a" {
+		t.Errorf("unexpected utxo rwset after rollback: %v", utxoRWSet)
+	}
+	if err := mc.RollbackTo(sp); err == nil {
+		t.Error("expect error when rolling back a released savepoint")
+	}
+}
+
+func TestXMCacheBlockRead(t *testing.T) {
+	mc := NewXModelCache(&contract.SandboxConfig{
+		XMReader: NewMemXModel(),
+	})
+	mc.AddBlockRead([]byte("block1"))
+	// 回滚保存点不丢弃读取过的区块
+	sp := mc.Savepoint()
+	mc.AddBlockRead([]byte("block2"))
+	mc.AddBlockRead([]byte("block1"))
+	if err := mc.RollbackTo(sp); err != nil {
+		t.Fatal(err)
+	}
+	if err := mc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	tx := &lpb.Transaction{}
+	for _, w := range mc.RWSet().WSet {
+		tx.TxOutputsExt = append(tx.TxOutputsExt, &protos.TxOutputExt{
+			Bucket: w.GetBucket(),
+			Key:    w.GetKey(),
+			Value:  w.GetValue(),
+		})
+	}
+	blockids, err := xmodel.ParseContractBlockReads(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blockids) != 2 || string(blockids[0]) != "block1" || string(blockids[1]) != "block2" {
+		t.Errorf("unexpected block reads %q", blockids)
+	}
+}
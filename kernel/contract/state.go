@@ -14,6 +14,12 @@ type UtxoReader interface {
 	SelectUtxo(string, *big.Int, bool, bool) ([]*protos.TxInput, [][]byte, *big.Int, error)
 }
 
+// UtxoLister 是UtxoReader的可选能力，列出地址下全部未花费的utxo，
+// frozen为true时只列出仍处于冻结状态的utxo，否则只列出可用的utxo
+type UtxoLister interface {
+	ListUtxo(addr string, frozen bool) ([]*protos.TxInput, error)
+}
+
 // Iterator iterates over key/value pairs in key order
 type Iterator interface {
 	Key() []byte
@@ -37,6 +43,8 @@ type XMState interface {
 // XMState 对XuperBridge暴露对账本的UTXO操作能力
 type UTXOState interface {
 	Transfer(from string, to string, amount *big.Int) error
+	// GetBalance 返回地址在当前沙盒视图下的余额，frozen为true时返回冻结余额
+	GetBalance(addr string, frozen bool) (*big.Int, error)
 }
 
 // CrossQueryState 对XuperBridge暴露对跨链只读合约的操作能力
@@ -45,6 +53,8 @@ type CrossQueryState interface {
 
 type ContractEventState interface {
 	AddEvent(events ...*protos.ContractEvent)
+	// Events 返回沙盒中已经产生的合约事件
+	Events() []*protos.ContractEvent
 }
 
 // State 抽象了链的状态机接口，合约通过State里面的方法来修改状态。
@@ -65,6 +75,23 @@ type StateSandbox interface {
 	UTXORWSet() *UTXORWSet
 }
 
+// SavepointSandbox 是StateSandbox的可选能力，回滚到保存点时丢弃保存点之后的写集、转账和事件，
+// 保存点之后的读集仍然保留，保证验证时重放得到相同的读集
+type SavepointSandbox interface {
+	// Savepoint 创建一个保存点，保存点可以嵌套
+	Savepoint() int
+	// RollbackTo 回滚到保存点并释放该保存点及其后创建的保存点
+	RollbackTo(savepoint int) error
+	// ReleaseSavepoint 保留保存点之后的修改并释放该保存点及其后创建的保存点
+	ReleaseSavepoint(savepoint int) error
+}
+
+// BlockReadState 是State的可选能力，记录合约读取过的主干区块，
+// 区块id随读写集写入交易，交易上链时校验这些区块仍在主干上
+type BlockReadState interface {
+	AddBlockRead(blockid []byte)
+}
+
 type RWSet struct {
 	RSet []*ledger.VersionedData
 	WSet []*ledger.PureData
@@ -73,4 +100,11 @@ type RWSet struct {
 type UTXORWSet struct {
 	Rset []*protos.TxInput
 	WSet []*protos.TxOutput
+	// Reads和FrozenReads记录GetBalance读到的utxo，用于验证时重放
+	Reads       []*protos.TxInput
+	FrozenReads []*protos.TxInput
+	// ReadAddrs和FrozenReadAddrs按读取顺序记录GetBalance读取过的地址，没有utxo的地址同样会被记录，
+	// 验证时据此检查读到的utxo是否是地址下全部的utxo
+	ReadAddrs       []string
+	FrozenReadAddrs []string
 }
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
 	"github.com/xuperchain/xupercore/kernel/ledger"
@@ -43,3 +44,17 @@ func (t *ChainCoreAgent) QueryTransaction(txid []byte) (*pb.Transaction, error)
 func (t *ChainCoreAgent) QueryBlock(blockid []byte) (ledger.BlockHandle, error) {
 	return t.chainCtx.State.QueryBlock(blockid)
 }
+
+// QueryBlockByHeight query block on trunk by height
+func (t *ChainCoreAgent) QueryBlockByHeight(height int64) (ledger.BlockHandle, error) {
+	block, err := t.chainCtx.Ledger.QueryBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return state.NewBlockAgent(block), nil
+}
+
+// QueryTipBlockHeight query height of the trunk tip block
+func (t *ChainCoreAgent) QueryTipBlockHeight() (int64, error) {
+	return t.chainCtx.State.QueryTipBlockHeight()
+}
@@ -140,6 +140,11 @@ func (t *LedgerAgent) GetTipBlock() kledger.BlockHandle {
 	return blkAgent
 }
 
+// 获取账本配置的不可逆滑动窗口，为0时未开启
+func (t *LedgerAgent) GetIrreversibleSlideWindow() int64 {
+	return t.chainCtx.State.GetIrreversibleSlideWindow()
+}
+
 // 获取状态机最新确认高度快照（只有Get方法，直接返回[]byte）
 func (t *LedgerAgent) GetTipXMSnapshotReader() (kledger.XMSnapshotReader, error) {
 	return t.chainCtx.State.GetTipXMSnapshotReader()
@@ -162,6 +162,7 @@ func (t *ChainRelyAgentImpl) CreateConsensus() (consensus.PluggableConsensusInte
 		Contract: ctx.Contract,
 		Ledger:   legAgent,
 		Network:  ctx.EngCtx.Net,
+		Clock:    ctx.GetClock(),
 	}
 
 	log, err := logs.NewLogger("", cdef.SubModName)
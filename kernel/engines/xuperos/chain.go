@@ -16,6 +16,7 @@ import (
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/agent"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/indexer"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/miner"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/parachain"
 	"github.com/xuperchain/xupercore/lib/logs"
@@ -45,6 +46,8 @@ type Chain struct {
 
 	// 提交交易cache
 	txIdCache *cache.Cache
+	// 二级索引服务，未开启时为nil
+	indexer *indexer.Indexer
 }
 
 // 从本地存储加载链
@@ -77,6 +80,16 @@ func LoadChain(engCtx *common.EngineCtx, bcName string) (*Chain, error) {
 		return nil, common.ErrNewChainCtxFailed.More("err:%v", err)
 	}
 
+	// 创建二级索引服务
+	if engCtx.EngCfg.Indexer.Enable {
+		chainObj.indexer, err = indexer.OpenIndexer(ctx)
+		if err != nil {
+			log.Error("open indexer failed", "bcName", bcName, "err", err)
+			return nil, common.ErrNewChainCtxFailed.More("err:%v", err)
+		}
+		ctx.Indexer = chainObj.indexer
+	}
+
 	// 创建矿工
 	chainObj.miner = miner.NewMiner(ctx)
 	chainObj.txIdCache = cache.New(TxIdCacheExpired, TxIdCacheGCInterval)
@@ -96,6 +109,10 @@ func (t *Chain) SetRelyAgent(agent common.ChainRelyAgent) error {
 
 // 阻塞
 func (t *Chain) Start() {
+	// 启动二级索引同步
+	if t.indexer != nil {
+		t.indexer.Start()
+	}
 	// 启动矿工
 	t.miner.Start()
 }
@@ -103,6 +120,10 @@ func (t *Chain) Start() {
 func (t *Chain) Stop() {
 	// 停止矿工等其余组件
 	t.miner.Stop()
+	if t.indexer != nil {
+		t.indexer.Stop()
+		t.indexer = nil
+	}
 	t.ctx.Ledger.Close()
 	t.ctx.State.Close()
 	t.ctx = nil
@@ -159,6 +180,7 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 	responseBodes := make([][]byte, 0, len(reqs))
 	requests := make([]*protos.InvokeRequest, 0, len(reqs))
 	responses := make([]*protos.ContractResponse, 0, len(reqs))
+	results := make([]*protos.InvokeResult, 0, len(reqs))
 	for i, req := range reqs {
 		if req == nil {
 			continue
@@ -199,6 +221,7 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 			return nil, common.ErrContractNewCtxFailed.More("%v", err)
 		}
 
+		eventIndex := len(sandbox.Events())
 		resp, err := context.Invoke(req.MethodName, req.Args)
 		if err != nil {
 			// TODO: deal with error
@@ -218,8 +241,10 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 
 		metrics.ContractInvokeCounter.WithLabelValues(t.ctx.BCName, req.ModuleName, req.ContractName, req.MethodName, "OK").Inc()
 		resourceUsed := context.ResourceUsed()
+		var reqGasUsed int64
 		if i >= len(reservedRequests) {
-			gasUsed += resourceUsed.TotalGas(gasPrice)
+			reqGasUsed = resourceUsed.TotalGas(gasPrice)
+			gasUsed += reqGasUsed
 		}
 
 		// request
@@ -235,6 +260,11 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 		}
 		responses = append(responses, response)
 		responseBodes = append(responseBodes, resp.Body)
+		results = append(results, &protos.InvokeResult{
+			Response: response,
+			GasUsed:  reqGasUsed,
+			Events:   append([]*protos.ContractEvent(nil), sandbox.Events()[eventIndex:]...),
+		})
 
 		// TODO: deal with error
 		_ = context.Release()
@@ -257,6 +287,7 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 		Responses:   responses,
 		UtxoInputs:  utxoRWSet.Rset,
 		UtxoOutputs: utxoRWSet.WSet,
+		Results:     results,
 	}
 
 	return invokeResponse, nil
@@ -8,6 +8,7 @@ import (
 	xconf "github.com/xuperchain/xupercore/kernel/common/xconfig"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/consensus"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/kernel/contract"
 	governToken "github.com/xuperchain/xupercore/kernel/contract/proposal/govern_token"
 	"github.com/xuperchain/xupercore/kernel/contract/proposal/propose"
@@ -62,4 +63,16 @@ type ChainCtx struct {
 	Address *xaddress.Address
 	// 异步任务
 	Asyncworker AsyncworkerAgent
+	// 二级索引，未开启时为nil
+	Indexer IndexerAgent
+	// 共识和矿工共用的时钟，为nil时使用系统时钟
+	Clock cctx.Clock
+}
+
+// GetClock 返回链上出块使用的时钟
+func (t *ChainCtx) GetClock() cctx.Clock {
+	if t.Clock == nil {
+		return cctx.SystemClock
+	}
+	return t.Clock
 }
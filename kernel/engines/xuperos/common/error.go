@@ -109,4 +109,10 @@ var (
 
 	// consensus
 	ErrConsensusStatus = &Error{ErrStatusInternalErr, 50701, "consensus status error"}
+
+	// indexer
+	ErrIndexerDisabled = &Error{ErrStatusRefused, 40800, "indexer not enabled"}
+
+	// supply
+	ErrSupplyUnavailable = &Error{ErrStatusRefused, 41000, "supply not available at this height"}
 )
@@ -13,6 +13,7 @@ import (
 	timerTask "github.com/xuperchain/xupercore/kernel/contract/proposal/timer"
 	"github.com/xuperchain/xupercore/kernel/engines"
 	chainConfigBase "github.com/xuperchain/xupercore/kernel/engines/xuperos/chain_config/base"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xtoken/base"
 	kledger "github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/kernel/network"
@@ -82,6 +83,16 @@ type AsyncworkerAgent interface {
 	RegisterHandler(contract string, event string, handler TaskHandler)
 }
 
+// 二级索引服务，避免循环调用
+type IndexerAgent interface {
+	// 分页查询地址相关交易
+	QueryAddressTxs(address, cursor string, limit int, reverse bool) (*xpb.TxIndexPage, error)
+	// 分页查询合约相关交易
+	QueryContractTxs(contract, cursor string, limit int, reverse bool) (*xpb.TxIndexPage, error)
+	// 分页查询合约事件，返回结果不包含事件内容
+	QueryContractEvents(contract, event, cursor string, limit int, reverse bool) (*xpb.EventIndexPage, error)
+}
+
 type TaskHandler func(ctx TaskContext) error
 
 type TaskContext interface {
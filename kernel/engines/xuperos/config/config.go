@@ -32,6 +32,16 @@ type EngineConf struct {
 	// SyncFactorForFactorBucketMode only use for SyncWithFactorBucket mode of SyncBlockFilterMode configuration item
 	SyncFactorForFactorBucketMode float64 `yaml:"SyncFactorForFactorBucketMode,omitempty"`
 	DisableEmptyBlocks            bool    `yaml:"disableEmptyBlocks,omitempty"`
+	// Indexer secondary index service for address, contract and event queries
+	Indexer IndexerConf `yaml:"indexer,omitempty"`
+}
+
+// IndexerConf is the config of the optional secondary index service
+type IndexerConf struct {
+	// Enable turns on the index service for every loaded chain
+	Enable bool `yaml:"enable,omitempty"`
+	// MaxPageSize is the max number of entries returned by one paged query
+	MaxPageSize int `yaml:"maxPageSize,omitempty"`
 }
 
 func LoadEngineConf(cfgFile string) (*EngineConf, error) {
@@ -53,6 +63,10 @@ func GetDefEngineConf() *EngineConf {
 		MaxBlockQueueSize:             100,
 		SyncBlockFilterMode:           0,
 		SyncFactorForFactorBucketMode: 0.5,
+		Indexer: IndexerConf{
+			Enable:      false,
+			MaxPageSize: 100,
+		},
 	}
 }
 
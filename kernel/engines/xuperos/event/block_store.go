@@ -6,6 +6,7 @@ import (
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
 	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/consensus"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
 )
 
@@ -13,6 +14,8 @@ import (
 type ChainManager interface {
 	// GetBlockStore get BlockStore base bcname(the name of block chain)
 	GetBlockStore(bcname string) (BlockStore, error)
+	// GetFinalityStore get FinalityStore base bcname(the name of block chain)
+	GetFinalityStore(bcname string) (FinalityStore, error)
 }
 
 // BlockStore is the interface of block store
@@ -25,6 +28,14 @@ type BlockStore interface {
 	QueryBlockByHeight(int64) (*pb.InternalBlock, error)
 }
 
+// FinalityStore is the interface of finalized block query
+type FinalityStore interface {
+	// FinalizedHeight returns the height of the highest block finalized by consensus
+	FinalizedHeight() (int64, error)
+	// QueryBlockHeaderByHeight returns block header at given height
+	QueryBlockHeaderByHeight(int64) (*pb.InternalBlock, error)
+}
+
 type chainManager struct {
 	engine common.Engine
 }
@@ -45,6 +56,15 @@ func (c *chainManager) GetBlockStore(bcname string) (BlockStore, error) {
 	return NewBlockStore(chain.Context().Ledger, chain.Context().State), nil
 }
 
+func (c *chainManager) GetFinalityStore(bcname string) (FinalityStore, error) {
+	chain, err := c.engine.Get(bcname)
+	if err != nil {
+		return nil, fmt.Errorf("chain %s not found", bcname)
+	}
+
+	return NewFinalityStore(chain.Context().Ledger, chain.Context().Consensus), nil
+}
+
 type blockStore struct {
 	*ledger.Ledger
 	*state.State
@@ -58,6 +78,10 @@ func NewBlockStore(ledger *ledger.Ledger, state *state.State) BlockStore {
 	}
 }
 
+func (b *blockStore) QueryBlockByHeight(height int64) (*pb.InternalBlock, error) {
+	return b.Ledger.QueryBlockByHeight(height)
+}
+
 func (b *blockStore) TipBlockHeight() (int64, error) {
 	tipBlockid := b.Ledger.GetMeta().GetTipBlockid()
 	block, err := b.Ledger.QueryBlockHeader(tipBlockid)
@@ -66,3 +90,25 @@ func (b *blockStore) TipBlockHeight() (int64, error) {
 	}
 	return block.GetHeight(), nil
 }
+
+type finalityStore struct {
+	*ledger.Ledger
+	consensus consensus.PluggableConsensusInterface
+}
+
+// NewFinalityStore wraps ledger and consensus as a FinalityStore
+func NewFinalityStore(ledger *ledger.Ledger, consensus consensus.PluggableConsensusInterface) FinalityStore {
+	return &finalityStore{
+		Ledger:    ledger,
+		consensus: consensus,
+	}
+}
+
+func (f *finalityStore) FinalizedHeight() (int64, error) {
+	status, err := f.consensus.GetConsensusStatus()
+	if err != nil {
+		return 0, err
+	}
+	height, _ := status.GetFinalizedBlock()
+	return height, nil
+}
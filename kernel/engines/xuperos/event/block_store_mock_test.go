@@ -12,6 +12,8 @@ import (
 type mockBlockStore struct {
 	mutex  sync.Mutex
 	blocks []*lpb.InternalBlock
+	// finalized 不可逆高度
+	finalized int64
 
 	heightNotifier *state.BlockHeightNotifier
 }
@@ -60,3 +62,26 @@ func (m *mockBlockStore) AppendBlock(block *lpb.InternalBlock) {
 func (m *mockBlockStore) GetBlockStore(_ string) (BlockStore, error) {
 	return m, nil
 }
+
+// GetFinalityStore get FinalityStore based on blockchain name
+func (m *mockBlockStore) GetFinalityStore(_ string) (FinalityStore, error) {
+	return m, nil
+}
+
+// FinalizedHeight returns the finalized height
+func (m *mockBlockStore) FinalizedHeight() (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.finalized, nil
+}
+
+// QueryBlockHeaderByHeight returns block header at given height
+func (m *mockBlockStore) QueryBlockHeaderByHeight(height int64) (*lpb.InternalBlock, error) {
+	return m.QueryBlockByHeight(height)
+}
+
+func (m *mockBlockStore) SetFinalized(height int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.finalized = height
+}
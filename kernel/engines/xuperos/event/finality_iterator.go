@@ -0,0 +1,95 @@
+package event
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/xuperchain/xupercore/protos"
+)
+
+var _ Iterator = (*FinalityIterator)(nil)
+
+// defaultFinalityPollInterval 不可逆高度没有到达时的轮询间隔
+// 不可逆高度不一定随新区块推进(如raft的commit)，因此轮询共识状态而不是等待区块高度
+const defaultFinalityPollInterval = 500 * time.Millisecond
+
+// FinalityIterator 按高度递增依次返回变为不可逆的区块
+type FinalityIterator struct {
+	bcname       string
+	currNum      int64
+	endNum       int64
+	store        FinalityStore
+	pollInterval time.Duration
+	block        *protos.FinalizedBlock
+
+	closed bool
+	err    error
+}
+
+func NewFinalityIterator(store FinalityStore, bcname string, startNum, endNum int64) *FinalityIterator {
+	return &FinalityIterator{
+		bcname:       bcname,
+		currNum:      startNum,
+		endNum:       endNum,
+		store:        store,
+		pollInterval: defaultFinalityPollInterval,
+	}
+}
+
+func (f *FinalityIterator) Next() bool {
+	if f.closed || f.err != nil {
+		return false
+	}
+	if f.endNum != -1 && f.currNum >= f.endNum {
+		return false
+	}
+
+	if !f.waitFinalized(f.currNum) {
+		return false
+	}
+	block, err := f.store.QueryBlockHeaderByHeight(f.currNum)
+	if err != nil {
+		f.err = err
+		return false
+	}
+
+	f.block = &protos.FinalizedBlock{
+		Bcname:      f.bcname,
+		Blockid:     hex.EncodeToString(block.GetBlockid()),
+		BlockHeight: block.GetHeight(),
+	}
+	f.currNum += 1
+	return true
+}
+
+// waitFinalized 等待不可逆高度达到num，迭代器关闭或出错时返回false
+func (f *FinalityIterator) waitFinalized(num int64) bool {
+	for !f.closed {
+		height, err := f.store.FinalizedHeight()
+		if err != nil {
+			f.err = err
+			return false
+		}
+		if height >= num {
+			return true
+		}
+		time.Sleep(f.pollInterval)
+	}
+	return false
+}
+
+func (f *FinalityIterator) Block() *protos.FinalizedBlock {
+	return f.block
+}
+
+func (f *FinalityIterator) Data() interface{} {
+	return f.Block()
+}
+
+func (f *FinalityIterator) Error() error {
+	return f.err
+}
+
+func (f *FinalityIterator) Close() {
+	f.closed = true
+}
@@ -0,0 +1,84 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/golang/protobuf/proto" //nolint:staticcheck
+
+	"github.com/xuperchain/xupercore/protos"
+)
+
+var _ Topic = (*FinalityTopic)(nil)
+
+// FinalityTopic handles finalized block events
+type FinalityTopic struct {
+	chainmg ChainManager
+}
+
+// NewFinalityTopic instances FinalityTopic from ChainManager
+func NewFinalityTopic(chainmg ChainManager) *FinalityTopic {
+	return &FinalityTopic{
+		chainmg: chainmg,
+	}
+}
+
+// ParseFilter 从指定的bytes buffer反序列化topic过滤器
+// 返回的参数会作为入参传递给NewIterator的filter参数
+func (f *FinalityTopic) ParseFilter(buf []byte) (interface{}, error) {
+	pbfilter := new(protos.FinalityFilter)
+	err := proto.Unmarshal(buf, pbfilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return pbfilter, nil
+}
+
+// MarshalEvent encode event payload returns from Iterator.Data()
+func (f *FinalityTopic) MarshalEvent(x interface{}) ([]byte, error) {
+	msg := x.(proto.Message)
+	return proto.Marshal(msg)
+}
+
+// NewIterator make a new Iterator base on filter
+// 未指定起始高度时从当前的不可逆高度开始
+func (f *FinalityTopic) NewIterator(ifilter interface{}) (Iterator, error) {
+	filter, ok := ifilter.(*protos.FinalityFilter)
+	if !ok {
+		return nil, errors.New("bad filter type for finality event")
+	}
+
+	store, err := f.chainmg.GetFinalityStore(filter.GetBcname())
+	if err != nil {
+		return nil, err
+	}
+
+	var startBlockNum, endBlockNum int64
+	if filter.GetRange().GetStart() == "" {
+		n, err := store.FinalizedHeight()
+		if err != nil {
+			return nil, err
+		}
+		startBlockNum = n
+	} else {
+		n, err := strconv.ParseInt(filter.GetRange().GetStart(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error %s when parse start block number", err)
+		}
+		startBlockNum = n
+	}
+
+	if filter.GetRange().GetEnd() == "" {
+		endBlockNum = -1
+	} else {
+		n, err := strconv.ParseInt(filter.GetRange().GetEnd(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error %s when parse end block number", err)
+		}
+		endBlockNum = n
+	}
+
+	return NewFinalityIterator(store, filter.GetBcname(), startBlockNum, endBlockNum), nil
+}
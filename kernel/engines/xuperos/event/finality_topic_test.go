@@ -0,0 +1,97 @@
+package event
+
+import (
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto" //nolint:staticcheck
+
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestFinalityTopicWaitFinalized(t *testing.T) {
+	ledger := newMockBlockStore()
+	const N = 6
+	var blocks []string
+	for i := 0; i < N; i++ {
+		block := newBlockBuilder().Block()
+		blocks = append(blocks, hex.EncodeToString(block.GetBlockid()))
+		ledger.AppendBlock(block)
+	}
+	ledger.SetFinalized(1)
+	go func() {
+		// 不可逆高度可能一次推进多个区块
+		time.Sleep(time.Millisecond * 100)
+		ledger.SetFinalized(3)
+		time.Sleep(time.Millisecond * 100)
+		ledger.SetFinalized(N - 1)
+	}()
+
+	topic := NewFinalityTopic(ledger)
+	iter, err := topic.NewIterator(&protos.FinalityFilter{
+		Bcname: "xuper",
+		Range: &protos.BlockRange{
+			Start: "1",
+			End:   strconv.Itoa(N),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	iter.(*FinalityIterator).pollInterval = 10 * time.Millisecond
+	defer iter.Close()
+
+	height := int64(1)
+	for ; iter.Next(); height++ {
+		block := iter.Data().(*protos.FinalizedBlock)
+		if block.GetBlockHeight() != height {
+			t.Fatalf("expect height %d got %d", height, block.GetBlockHeight())
+		}
+		if block.GetBlockid() != blocks[height] {
+			t.Errorf("expect %s got %s", blocks[height], block.GetBlockid())
+		}
+		if block.GetBcname() != "xuper" {
+			t.Errorf("unexpected bcname %s", block.GetBcname())
+		}
+		if finalized, _ := ledger.FinalizedHeight(); block.GetBlockHeight() > finalized {
+			t.Errorf("block %d returned before finalized, finalized height %d", block.GetBlockHeight(), finalized)
+		}
+	}
+	if iter.Error() != nil {
+		t.Fatal(iter.Error())
+	}
+	if height != N {
+		t.Errorf("unexpect finality event length %d", height-1)
+	}
+}
+
+func TestFinalityTopicDefaultStart(t *testing.T) {
+	ledger := newMockBlockStore()
+	for i := 0; i < 4; i++ {
+		ledger.AppendBlock(newBlockBuilder().Block())
+	}
+	ledger.SetFinalized(2)
+
+	router := NewRouterFromChainMgr(ledger)
+	buf, err := proto.Marshal(&protos.FinalityFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode, iter, err := router.Subscribe(protos.SubscribeType_FINALITY, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer iter.Close()
+	if !iter.Next() {
+		t.Fatal(iter.Error())
+	}
+	block := iter.Data().(*protos.FinalizedBlock)
+	if block.GetBlockHeight() != 2 {
+		t.Errorf("expect start from finalized height 2, got %d", block.GetBlockHeight())
+	}
+	if _, err := encode(block); err != nil {
+		t.Fatal(err)
+	}
+}
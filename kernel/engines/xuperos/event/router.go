@@ -15,9 +15,11 @@ type Router struct {
 // NewRouterFromChainMgr instance Router from ChainManager
 func NewRouterFromChainMgr(manager ChainManager) *Router {
 	blockTopic := NewBlockTopic(manager)
+	finalityTopic := NewFinalityTopic(manager)
 	return &Router{
 		topics: map[pb.SubscribeType]Topic{
-			pb.SubscribeType_BLOCK: blockTopic,
+			pb.SubscribeType_BLOCK:    blockTopic,
+			pb.SubscribeType_FINALITY: finalityTopic,
 		},
 	}
 }
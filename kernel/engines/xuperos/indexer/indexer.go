@@ -0,0 +1,345 @@
+// 二级索引服务，顺序消费主干区块，维护地址、合约和合约事件到交易的索引，
+// 为读接口提供分页查询能力，避免全账本扫描。
+package indexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	lconf "github.com/xuperchain/xupercore/bcs/ledger/xledger/config"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/def"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/event"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+const (
+	// 索引库存储目录名
+	IndexStrgDirName = "index"
+	// 同步出错后的重试间隔
+	retryInterval = 3 * time.Second
+	// 未指定分页大小时的默认值
+	defaultPageSize = 20
+)
+
+var (
+	ErrIndexerClosed = errors.New("indexer closed")
+)
+
+// BlockSource 索引服务依赖的区块数据源
+type BlockSource interface {
+	event.BlockStore
+	// QueryBlock 按区块id查询区块，回滚分叉区块时使用
+	QueryBlock(blockid []byte) (*lpb.InternalBlock, error)
+}
+
+type blockSource struct {
+	event.BlockStore
+	leg *ledger.Ledger
+}
+
+// NewBlockSource 使用链的账本和状态机构造区块数据源
+func NewBlockSource(leg *ledger.Ledger, stat *state.State) BlockSource {
+	return &blockSource{
+		BlockStore: event.NewBlockStore(leg, stat),
+		leg:        leg,
+	}
+}
+
+func (s *blockSource) QueryBlock(blockid []byte) (*lpb.InternalBlock, error) {
+	return s.leg.QueryBlock(blockid)
+}
+
+// indexCursor 记录最后一个已索引的区块
+type indexCursor struct {
+	Height  int64  `json:"height"`
+	Blockid []byte `json:"blockid"`
+}
+
+type Indexer struct {
+	bcName      string
+	source      BlockSource
+	db          kvdb.Database
+	maxPageSize int
+	log         logs.Logger
+
+	mutex  sync.Mutex
+	cursor indexCursor
+	stopC  chan struct{}
+	closed bool
+	// 用于等待同步协程退出
+	exitWG sync.WaitGroup
+}
+
+// OpenIndexer 在链数据目录下打开索引库并创建索引服务
+func OpenIndexer(chainCtx *common.ChainCtx) (*Indexer, error) {
+	if chainCtx == nil || chainCtx.Ledger == nil || chainCtx.State == nil {
+		return nil, common.ErrParameter
+	}
+
+	envCfg := chainCtx.EngCtx.EnvCfg
+	lcfg, err := lconf.LoadLedgerConf(envCfg.GenConfFilePath(envCfg.LedgerConf))
+	if err != nil {
+		return nil, fmt.Errorf("load ledger config failed.err:%v", err)
+	}
+	storePath := filepath.Join(envCfg.GenDataAbsPath(envCfg.ChainDir), chainCtx.BCName, IndexStrgDirName)
+	db, err := kvdb.CreateKVInstance(&kvdb.KVParameter{
+		DBPath:                storePath,
+		KVEngineType:          lcfg.KVEngineType,
+		MemCacheSize:          ledger.MemCacheSize,
+		FileHandlersCacheSize: ledger.FileHandlersCacheSize,
+		OtherPaths:            lcfg.OtherPaths,
+		StorageType:           lcfg.StorageType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("open index db failed.path:%s,err:%v", storePath, err)
+	}
+
+	source := NewBlockSource(chainCtx.Ledger, chainCtx.State)
+	ix, err := NewIndexer(chainCtx.BCName, source, db, chainCtx.EngCtx.EngCfg.Indexer.MaxPageSize, chainCtx.XLog)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return ix, nil
+}
+
+func NewIndexer(bcName string, source BlockSource, db kvdb.Database, maxPageSize int, log logs.Logger) (*Indexer, error) {
+	if source == nil || db == nil || log == nil {
+		return nil, common.ErrParameter
+	}
+	if maxPageSize <= 0 {
+		maxPageSize = defaultPageSize
+	}
+
+	ix := &Indexer{
+		bcName:      bcName,
+		source:      source,
+		db:          db,
+		maxPageSize: maxPageSize,
+		log:         log,
+		cursor:      indexCursor{Height: -1},
+		stopC:       make(chan struct{}),
+	}
+	if err := ix.loadCursor(); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// Start 启动后台同步协程
+func (ix *Indexer) Start() {
+	ix.exitWG.Add(1)
+	go ix.run()
+	ix.log.Info("indexer started", "bcName", ix.bcName, "height", ix.IndexedHeight())
+}
+
+// Stop 停止同步并关闭索引库
+func (ix *Indexer) Stop() {
+	ix.mutex.Lock()
+	if ix.closed {
+		ix.mutex.Unlock()
+		return
+	}
+	ix.closed = true
+	close(ix.stopC)
+	ix.mutex.Unlock()
+
+	// 同步协程退出后才关闭索引库，避免关闭后仍有写入
+	ix.exitWG.Wait()
+	ix.db.Close()
+}
+
+// IndexedHeight 返回已经完成索引的最高区块高度，尚未索引任何区块时返回-1
+func (ix *Indexer) IndexedHeight() int64 {
+	ix.mutex.Lock()
+	defer ix.mutex.Unlock()
+	return ix.cursor.Height
+}
+
+func (ix *Indexer) run() {
+	defer ix.exitWG.Done()
+	for !ix.isClosed() {
+		err := ix.follow()
+		if err == nil || ix.isClosed() {
+			continue
+		}
+
+		ix.log.Warn("indexer follow blocks failed, retry later", "bcName", ix.bcName, "err", err)
+		select {
+		case <-ix.stopC:
+		case <-time.After(retryInterval):
+		}
+	}
+	ix.log.Info("indexer loop shut down", "bcName", ix.bcName)
+}
+
+// follow 从游标的下一个高度开始顺序消费主干区块，发现分叉时返回，由外层回滚后重新开始。
+// 等待新区块时可以被Stop打断
+func (ix *Indexer) follow() error {
+	if err := ix.rollback(); err != nil {
+		return err
+	}
+
+	cursor := ix.getCursor()
+	iter := event.NewBlockIterator(ix.source, cursor.Height+1, -1)
+	defer iter.Close()
+
+	for {
+		// 先等到下一个高度，迭代器内部的等待无法被打断
+		if !ix.waitHeight(cursor.Height + 1) {
+			return ErrIndexerClosed
+		}
+		if !iter.Next() {
+			break
+		}
+		if ix.isClosed() {
+			return ErrIndexerClosed
+		}
+		block := iter.Block()
+		if !bytes.Equal(block.GetPreHash(), cursor.Blockid) {
+			ix.log.Info("indexer found fork, rollback", "bcName", ix.bcName,
+				"height", block.GetHeight(), "preHash", utils.F(block.GetPreHash()), "cursor", utils.F(cursor.Blockid))
+			return nil
+		}
+		if err := ix.applyBlock(block); err != nil {
+			return err
+		}
+		cursor = ix.getCursor()
+	}
+	return iter.Error()
+}
+
+// waitHeight 等待状态机更新到目标高度，索引服务停止时返回false。
+// 等待协程在目标高度到达后退出，不会访问索引库
+func (ix *Indexer) waitHeight(height int64) bool {
+	done := make(chan struct{})
+	go func() {
+		ix.source.WaitBlockHeight(height)
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ix.stopC:
+		return false
+	}
+}
+
+// rollback 撤销游标处不在主干上的区块索引，直到游标重新指向主干区块
+func (ix *Indexer) rollback() error {
+	for {
+		cursor := ix.getCursor()
+		if cursor.Height < 0 {
+			return nil
+		}
+
+		trunk, err := ix.source.QueryBlockByHeight(cursor.Height)
+		if err == nil && bytes.Equal(trunk.GetBlockid(), cursor.Blockid) {
+			return nil
+		}
+		if err != nil && err != ledger.ErrBlockNotExist {
+			return err
+		}
+
+		block, err := ix.source.QueryBlock(cursor.Blockid)
+		if err != nil {
+			return fmt.Errorf("query forked block failed.blockid:%s,err:%v", utils.F(cursor.Blockid), err)
+		}
+		if err := ix.revertBlock(block); err != nil {
+			return err
+		}
+		ix.log.Info("indexer rollback block", "bcName", ix.bcName,
+			"height", block.GetHeight(), "blockid", utils.F(block.GetBlockid()))
+	}
+}
+
+func (ix *Indexer) applyBlock(block *lpb.InternalBlock) error {
+	keys, err := blockKeys(block)
+	if err != nil {
+		return err
+	}
+
+	batch := ix.db.NewBatch()
+	for key, txid := range keys {
+		if err := batch.Put([]byte(key), txid); err != nil {
+			return err
+		}
+	}
+	next := indexCursor{Height: block.GetHeight(), Blockid: block.GetBlockid()}
+	return ix.commit(batch, next)
+}
+
+func (ix *Indexer) revertBlock(block *lpb.InternalBlock) error {
+	keys, err := blockKeys(block)
+	if err != nil {
+		return err
+	}
+
+	batch := ix.db.NewBatch()
+	for key := range keys {
+		if err := batch.Delete([]byte(key)); err != nil {
+			return err
+		}
+	}
+	prev := indexCursor{Height: block.GetHeight() - 1, Blockid: block.GetPreHash()}
+	return ix.commit(batch, prev)
+}
+
+// commit 原子写入索引项和新游标
+func (ix *Indexer) commit(batch kvdb.Batch, cursor indexCursor) error {
+	buf, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	if err := batch.Put(cursorKey, buf); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	ix.mutex.Lock()
+	ix.cursor = cursor
+	ix.mutex.Unlock()
+	return nil
+}
+
+func (ix *Indexer) loadCursor() error {
+	buf, err := ix.db.Get(cursorKey)
+	if err != nil && def.NormalizedKVError(err) == def.ErrKVNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var cursor indexCursor
+	if err := json.Unmarshal(buf, &cursor); err != nil {
+		return fmt.Errorf("invalid index cursor.err:%v", err)
+	}
+	ix.cursor = cursor
+	return nil
+}
+
+func (ix *Indexer) getCursor() indexCursor {
+	ix.mutex.Lock()
+	defer ix.mutex.Unlock()
+	return ix.cursor
+}
+
+func (ix *Indexer) isClosed() bool {
+	ix.mutex.Lock()
+	defer ix.mutex.Unlock()
+	return ix.closed
+}
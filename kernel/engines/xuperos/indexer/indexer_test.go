@@ -0,0 +1,291 @@
+package indexer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/leveldb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+type mockBlockSource struct {
+	mutex  sync.Mutex
+	trunk  []*lpb.InternalBlock
+	blocks map[string]*lpb.InternalBlock
+
+	heightNotifier *state.BlockHeightNotifier
+}
+
+func newMockBlockSource() *mockBlockSource {
+	return &mockBlockSource{
+		blocks:         make(map[string]*lpb.InternalBlock),
+		heightNotifier: state.NewBlockHeightNotifier(),
+	}
+}
+
+func (m *mockBlockSource) TipBlockHeight() (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return int64(len(m.trunk)) - 1, nil
+}
+
+func (m *mockBlockSource) WaitBlockHeight(target int64) int64 {
+	return m.heightNotifier.WaitHeight(target)
+}
+
+func (m *mockBlockSource) QueryBlockByHeight(height int64) (*lpb.InternalBlock, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if height < 0 {
+		return nil, errors.New("bad height")
+	}
+	if height >= int64(len(m.trunk)) {
+		return nil, ledger.ErrBlockNotExist
+	}
+	return m.trunk[height], nil
+}
+
+func (m *mockBlockSource) QueryBlock(blockid []byte) (*lpb.InternalBlock, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	block, ok := m.blocks[string(blockid)]
+	if !ok {
+		return nil, ledger.ErrBlockNotExist
+	}
+	return block, nil
+}
+
+// appendBlock 在height处追加区块，height之后的主干区块被切换到分叉上
+func (m *mockBlockSource) appendBlock(height int64, txs ...*lpb.Transaction) *lpb.InternalBlock {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	block := &lpb.InternalBlock{
+		Blockid:      makeRandID(),
+		Height:       height,
+		Transactions: txs,
+	}
+	if height > 0 {
+		block.PreHash = m.trunk[height-1].Blockid
+	}
+	m.trunk = append(m.trunk[:height], block)
+	m.blocks[string(block.Blockid)] = block
+	m.heightNotifier.UpdateHeight(height)
+	return block
+}
+
+func makeRandID() []byte {
+	buf := make([]byte, 32)
+	_, _ = rand.Read(buf)
+	return buf
+}
+
+func transferTx(from, to string, events ...*protos.ContractEvent) *lpb.Transaction {
+	tx := &lpb.Transaction{
+		Txid:      makeRandID(),
+		Initiator: from,
+		TxInputs: []*protos.TxInput{
+			{FromAddr: []byte(from), Amount: []byte("1")},
+		},
+		TxOutputs: []*protos.TxOutput{
+			{ToAddr: []byte(to), Amount: []byte("1")},
+			{ToAddr: []byte(lpb.FeePlaceholder), Amount: []byte("1")},
+		},
+	}
+	if len(events) > 0 {
+		buf, _ := xmodel.MarshalMessages(events)
+		tx.ContractRequests = []*protos.InvokeRequest{
+			{ModuleName: "wasm", ContractName: events[0].Contract, MethodName: "invoke"},
+		}
+		tx.TxOutputsExt = []*protos.TxOutputExt{
+			{Bucket: xmodel.TransientBucket, Key: []byte("contractEvent"), Value: buf},
+		}
+	}
+	return tx
+}
+
+func newTestIndexer(t *testing.T, source BlockSource) *Indexer {
+	basedir := t.TempDir()
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), filepath.Join(basedir, "log"))
+	log, _ := logs.NewLogger("", "indexer_test")
+
+	db, err := kvdb.CreateKVInstance(&kvdb.KVParameter{
+		DBPath:                filepath.Join(basedir, IndexStrgDirName),
+		KVEngineType:          "leveldb",
+		MemCacheSize:          ledger.MemCacheSize,
+		FileHandlersCacheSize: ledger.FileHandlersCacheSize,
+		StorageType:           "single",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ix, err := NewIndexer("xuper", source, db, 10, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ix
+}
+
+func waitIndexed(t *testing.T, ix *Indexer, block *lpb.InternalBlock) {
+	for i := 0; i < 100; i++ {
+		cursor := ix.getCursor()
+		if cursor.Height == block.Height && bytes.Equal(cursor.Blockid, block.Blockid) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("index block %d timeout, cursor:%d", block.Height, ix.IndexedHeight())
+}
+
+func txHeights(page *xpb.TxIndexPage) []int64 {
+	var heights []int64
+	for _, tx := range page.GetTxs() {
+		heights = append(heights, tx.GetHeight())
+	}
+	return heights
+}
+
+func TestIndexerFollowAndRollback(t *testing.T) {
+	source := newMockBlockSource()
+	ix := newTestIndexer(t, source)
+	defer ix.Stop()
+
+	event := &protos.ContractEvent{Contract: "counter", Name: "increase", Body: []byte("1")}
+	source.appendBlock(0)
+	source.appendBlock(1, transferTx("alice", "bob", event))
+	b2 := source.appendBlock(2, transferTx("alice", "carol"), transferTx("bob", "alice"))
+	ix.Start()
+	waitIndexed(t, ix, b2)
+
+	page, err := ix.QueryAddressTxs("alice", "", 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := txHeights(page); len(got) != 3 || got[0] != 1 || got[2] != 2 {
+		t.Fatalf("unexpected alice txs:%v", got)
+	}
+	if page.NextCursor != "" || page.IndexedHeight != 2 {
+		t.Fatalf("unexpected page:%v", page)
+	}
+	if page, _ := ix.QueryAddressTxs(lpb.FeePlaceholder, "", 0, false); len(page.Txs) != 0 {
+		t.Fatal("fee placeholder should not be indexed")
+	}
+
+	events, err := ix.QueryContractEvents("counter", "increase", "", 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Events) != 1 || events.Events[0].Height != 1 || events.Events[0].EventIndex != 0 {
+		t.Fatalf("unexpected events:%v", events)
+	}
+	contractTxs, _ := ix.QueryContractTxs("counter", "", 0, false)
+	if len(contractTxs.Txs) != 1 {
+		t.Fatalf("unexpected contract txs:%v", contractTxs)
+	}
+
+	// 分叉：高度2被替换
+	source.appendBlock(2, transferTx("dave", "erin"))
+	b3 := source.appendBlock(3, transferTx("erin", "alice"))
+	waitIndexed(t, ix, b3)
+
+	if page, _ := ix.QueryAddressTxs("carol", "", 0, false); len(page.Txs) != 0 {
+		t.Fatalf("forked tx still indexed:%v", page)
+	}
+	page, _ = ix.QueryAddressTxs("erin", "", 0, false)
+	if got := txHeights(page); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatalf("unexpected erin txs:%v", got)
+	}
+	page, _ = ix.QueryAddressTxs("alice", "", 0, true)
+	if got := txHeights(page); len(got) != 2 || got[0] != 3 || got[1] != 1 {
+		t.Fatalf("unexpected alice txs:%v", got)
+	}
+}
+
+func TestIndexerPagination(t *testing.T) {
+	source := newMockBlockSource()
+	ix := newTestIndexer(t, source)
+	defer ix.Stop()
+
+	source.appendBlock(0)
+	var tip *lpb.InternalBlock
+	for h := int64(1); h <= 25; h++ {
+		tip = source.appendBlock(h, transferTx("alice", "bob"))
+	}
+	ix.Start()
+	waitIndexed(t, ix, tip)
+
+	for _, reverse := range []bool{false, true} {
+		var heights []int64
+		cursor := ""
+		for {
+			page, err := ix.QueryAddressTxs("bob", cursor, 20, reverse)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Txs) > 10 {
+				t.Fatalf("page size exceed max:%d", len(page.Txs))
+			}
+			heights = append(heights, txHeights(page)...)
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+		if len(heights) != 25 {
+			t.Fatalf("unexpected result size:%d", len(heights))
+		}
+		for i := 1; i < len(heights); i++ {
+			if (heights[i] > heights[i-1]) == reverse {
+				t.Fatalf("result not ordered, reverse:%v heights:%v", reverse, heights)
+			}
+		}
+	}
+
+	if _, err := ix.QueryAddressTxs("bob", "zz", 10, false); err == nil {
+		t.Fatal("invalid cursor should be refused")
+	}
+}
+
+func TestIndexerStop(t *testing.T) {
+	source := newMockBlockSource()
+	ix := newTestIndexer(t, source)
+
+	b0 := source.appendBlock(0)
+	ix.Start()
+	waitIndexed(t, ix, b0)
+
+	// 等待新区块时Stop需要打断等待并等同步协程退出
+	stopped := make(chan struct{})
+	go func() {
+		ix.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stop indexer timeout")
+	}
+
+	source.appendBlock(1, transferTx("alice", "bob"))
+	time.Sleep(50 * time.Millisecond)
+	if height := ix.IndexedHeight(); height != 0 {
+		t.Fatalf("indexer still running after stop, height:%d", height)
+	}
+}
@@ -0,0 +1,128 @@
+package indexer
+
+import (
+	"encoding/binary"
+	"strings"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+)
+
+// 索引库key布局，高度和序号均为大端编码，保证同一前缀下按高度有序
+//
+//	A{address}\x00{height}{txIndex}                       -> txid
+//	C{contract}\x00{height}{txIndex}                      -> txid
+//	E{contract}\x00{event}\x00{height}{txIndex}{evtIndex} -> txid
+//	M{name}                                               -> 索引元数据
+const (
+	addressPrefix  = "A"
+	contractPrefix = "C"
+	eventPrefix    = "E"
+	metaPrefix     = "M"
+
+	// 名字之间的分隔符，地址、合约名和事件名中不会出现
+	keySep = 0x00
+
+	txPosLen    = 8 + 4
+	eventPosLen = txPosLen + 4
+)
+
+var cursorKey = []byte(metaPrefix + "cursor")
+
+func addressKeyPrefix(address string) []byte {
+	return namePrefix(addressPrefix, address)
+}
+
+func contractKeyPrefix(contract string) []byte {
+	return namePrefix(contractPrefix, contract)
+}
+
+func eventKeyPrefix(contract, event string) []byte {
+	return namePrefix(eventPrefix, contract, event)
+}
+
+func namePrefix(prefix string, names ...string) []byte {
+	key := []byte(prefix)
+	for _, name := range names {
+		key = append(key, name...)
+		key = append(key, keySep)
+	}
+	return key
+}
+
+func encodeTxPos(height int64, txIndex int) []byte {
+	pos := make([]byte, txPosLen)
+	binary.BigEndian.PutUint64(pos, uint64(height))
+	binary.BigEndian.PutUint32(pos[8:], uint32(txIndex))
+	return pos
+}
+
+func encodeEventPos(height int64, txIndex, eventIndex int) []byte {
+	pos := make([]byte, eventPosLen)
+	copy(pos, encodeTxPos(height, txIndex))
+	binary.BigEndian.PutUint32(pos[txPosLen:], uint32(eventIndex))
+	return pos
+}
+
+func decodeTxPos(pos []byte) (height int64, txIndex int32) {
+	return int64(binary.BigEndian.Uint64(pos)), int32(binary.BigEndian.Uint32(pos[8:]))
+}
+
+func decodeEventPos(pos []byte) (height int64, txIndex, eventIndex int32) {
+	height, txIndex = decodeTxPos(pos)
+	return height, txIndex, int32(binary.BigEndian.Uint32(pos[txPosLen:]))
+}
+
+// blockKeys 计算一个区块产生的全部索引项，key -> txid
+func blockKeys(block *lpb.InternalBlock) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for txIndex, tx := range block.GetTransactions() {
+		pos := encodeTxPos(block.GetHeight(), txIndex)
+		for _, addr := range txAddresses(tx) {
+			keys[string(append(addressKeyPrefix(addr), pos...))] = tx.GetTxid()
+		}
+		for _, req := range tx.GetContractRequests() {
+			if req.GetContractName() == "" {
+				continue
+			}
+			keys[string(append(contractKeyPrefix(req.GetContractName()), pos...))] = tx.GetTxid()
+		}
+
+		events, err := sandbox.ParseContractEvents(tx)
+		if err != nil {
+			return nil, err
+		}
+		for eventIndex, event := range events {
+			evtPos := encodeEventPos(block.GetHeight(), txIndex, eventIndex)
+			keys[string(append(eventKeyPrefix(event.GetContract(), event.GetName()), evtPos...))] = tx.GetTxid()
+		}
+	}
+	return keys, nil
+}
+
+// txAddresses 返回交易涉及的全部地址，包括发起者、授权者和转账双方
+func txAddresses(tx *lpb.Transaction) []string {
+	addrs := make([]string, 0, 2+len(tx.GetTxInputs())+len(tx.GetTxOutputs()))
+	addrs = append(addrs, tx.GetInitiator())
+	for _, auth := range tx.GetAuthRequire() {
+		// 合约账户授权格式为 account/address
+		addrs = append(addrs, strings.Split(auth, "/")...)
+	}
+	for _, input := range tx.GetTxInputs() {
+		addrs = append(addrs, string(input.GetFromAddr()))
+	}
+	for _, output := range tx.GetTxOutputs() {
+		addrs = append(addrs, string(output.GetToAddr()))
+	}
+
+	seen := make(map[string]bool, len(addrs))
+	result := addrs[:0]
+	for _, addr := range addrs {
+		if addr == "" || addr == lpb.FeePlaceholder || seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		result = append(result, addr)
+	}
+	return result
+}
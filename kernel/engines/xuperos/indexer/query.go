@@ -0,0 +1,151 @@
+package indexer
+
+import (
+	"encoding/hex"
+
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+)
+
+var _ common.IndexerAgent = (*Indexer)(nil)
+
+// QueryAddressTxs 分页查询与地址相关的交易，按高度和交易序号排序
+func (ix *Indexer) QueryAddressTxs(address, cursor string, limit int, reverse bool) (*xpb.TxIndexPage, error) {
+	if address == "" {
+		return nil, common.ErrParameter.More("address is empty")
+	}
+	return ix.queryTxs(addressKeyPrefix(address), cursor, limit, reverse)
+}
+
+// QueryContractTxs 分页查询调用了合约的交易，按高度和交易序号排序
+func (ix *Indexer) QueryContractTxs(contract, cursor string, limit int, reverse bool) (*xpb.TxIndexPage, error) {
+	if contract == "" {
+		return nil, common.ErrParameter.More("contract name is empty")
+	}
+	return ix.queryTxs(contractKeyPrefix(contract), cursor, limit, reverse)
+}
+
+// QueryContractEvents 分页查询合约事件的位置，事件内容需要调用方根据txid查询交易获得
+func (ix *Indexer) QueryContractEvents(contract, event, cursor string, limit int, reverse bool) (*xpb.EventIndexPage, error) {
+	if contract == "" || event == "" {
+		return nil, common.ErrParameter.More("contract name or event name is empty")
+	}
+
+	page := &xpb.EventIndexPage{
+		IndexedHeight: ix.IndexedHeight(),
+	}
+	next, err := ix.scan(eventKeyPrefix(contract, event), eventPosLen, cursor, limit, reverse, func(pos, txid []byte) {
+		height, txIndex, eventIndex := decodeEventPos(pos)
+		page.Events = append(page.Events, &xpb.EventIndex{
+			Height:     height,
+			TxIndex:    txIndex,
+			EventIndex: eventIndex,
+			Txid:       txid,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	page.NextCursor = next
+	return page, nil
+}
+
+func (ix *Indexer) queryTxs(prefix []byte, cursor string, limit int, reverse bool) (*xpb.TxIndexPage, error) {
+	page := &xpb.TxIndexPage{
+		IndexedHeight: ix.IndexedHeight(),
+	}
+	next, err := ix.scan(prefix, txPosLen, cursor, limit, reverse, func(pos, txid []byte) {
+		height, txIndex := decodeTxPos(pos)
+		page.Txs = append(page.Txs, &xpb.TxIndex{
+			Height:  height,
+			TxIndex: txIndex,
+			Txid:    txid,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	page.NextCursor = next
+	return page, nil
+}
+
+// scan 从游标之后遍历前缀下最多limit条索引项，还有更多数据时返回下一页游标。
+// 游标是上一页最后一项位置的hex编码
+func (ix *Indexer) scan(prefix []byte, posLen int, cursor string, limit int, reverse bool,
+	visit func(pos, value []byte)) (string, error) {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > ix.maxPageSize {
+		limit = ix.maxPageSize
+	}
+
+	start, end := prefix, prefixEnd(prefix)
+	if cursor != "" {
+		pos, err := hex.DecodeString(cursor)
+		if err != nil || len(pos) != posLen {
+			return "", common.ErrParameter.More("invalid cursor %s", cursor)
+		}
+		if reverse {
+			end = joinKey(prefix, pos)
+		} else {
+			start = append(joinKey(prefix, pos), 0)
+		}
+	}
+
+	iter := ix.db.NewIteratorWithRange(start, end)
+	defer iter.Release()
+
+	var ok bool
+	if reverse {
+		ok = iter.Last()
+	} else {
+		ok = iter.Next()
+	}
+	var count int
+	var lastPos []byte
+	for ; ok; ok = step(iter, reverse) {
+		key := iter.Key()
+		if len(key) != len(prefix)+posLen {
+			continue
+		}
+		if count == limit {
+			return hex.EncodeToString(lastPos), nil
+		}
+
+		pos := append([]byte(nil), key[len(prefix):]...)
+		visit(pos, append([]byte(nil), iter.Value()...))
+		lastPos = pos
+		count++
+	}
+	if err := iter.Error(); err != nil {
+		return "", common.CastError(err)
+	}
+	return "", nil
+}
+
+func step(iter kvdb.Iterator, reverse bool) bool {
+	if reverse {
+		return iter.Prev()
+	}
+	return iter.Next()
+}
+
+func joinKey(prefix, pos []byte) []byte {
+	key := make([]byte, 0, len(prefix)+len(pos)+1)
+	key = append(key, prefix...)
+	return append(key, pos...)
+}
+
+// prefixEnd 返回大于所有以prefix开头的key的最小key
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/consensus"
+	cctx "github.com/xuperchain/xupercore/kernel/consensus/context"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/metrics"
@@ -53,6 +55,8 @@ var (
 type Miner struct {
 	ctx *common.ChainCtx
 	log logs.Logger
+	// 出块时间戳、重试休眠等都取自该时钟，与共识调度保持一致
+	clock cctx.Clock
 
 	// 当前节点状态，矿工或者同步节点
 	// 值得注意的是节点同一时刻只能处于一种角色，并严格执行相应的动作。
@@ -71,8 +75,9 @@ type Miner struct {
 
 func NewMiner(ctx *common.ChainCtx) *Miner {
 	obj := &Miner{
-		ctx: ctx,
-		log: ctx.GetLog(),
+		ctx:   ctx,
+		log:   ctx.GetLog(),
+		clock: ctx.GetClock(),
 	}
 
 	obj.faultPeerIdCache = cache.New(faultPeerIdCacheExpired, faultCacheGCInterval)
@@ -115,7 +120,7 @@ func (m *Miner) Start() {
 		// 如果出错，休眠1s后重试，防止cpu被打满
 		if err != nil {
 			m.log.Warn("miner run occurred error,sleep 1s try", "err", err)
-			time.Sleep(time.Second)
+			m.clock.Sleep(time.Second)
 		}
 	}
 }
@@ -130,11 +135,11 @@ func (m *Miner) IsExit() bool {
 	return m.isExit
 }
 
-func traceMiner() func(string) {
-	last := time.Now()
+func (m *Miner) traceMiner() func(string) {
+	last := m.clock.Now()
 	return func(action string) {
-		metrics.CallMethodHistogram.WithLabelValues("miner", action).Observe(time.Since(last).Seconds())
-		last = time.Now()
+		metrics.CallMethodHistogram.WithLabelValues("miner", action).Observe(m.clock.Now().Sub(last).Seconds())
+		last = m.clock.Now()
 	}
 }
 
@@ -159,7 +164,7 @@ func (m *Miner) step() error {
 		}
 	}
 
-	trace := traceMiner()
+	trace := m.traceMiner()
 
 	ctx.GetLog().Trace("miner step", "ledgerTipHeight", ledgerTipHeight, "ledgerTipId",
 		utils.F(ledgerTipId), "stateTipId", utils.F(stateTipId))
@@ -218,6 +223,11 @@ func (m *Miner) step() error {
 				return nil
 			}
 		}
+		// 共识自行决定是否出空块，如raft只在有交易时出块
+		if decider, ok := m.ctx.Consensus.(consensus.EmptyBlockDecider); ok &&
+			!decider.AllowEmptyBlock(ledgerTipHeight+1) && !m.ctx.State.HasUnconfirmTx() {
+			return nil
+		}
 
 		// 开始挖矿
 		err = m.mining(ctx)
@@ -250,7 +260,7 @@ func (m *Miner) mining(ctx xctx.XContext) error {
 
 	// 1.共识挖矿前处理
 	height := m.ctx.Ledger.GetMeta().TrunkHeight + 1
-	now := time.Now()
+	now := m.clock.Now()
 	truncateTarget, extData, err := m.ctx.Consensus.ProcessBeforeMiner(height, now.UnixNano())
 	ctx.GetTimer().Mark("ProcessBeforeMiner")
 	if err != nil {
@@ -269,10 +279,10 @@ func (m *Miner) mining(ctx xctx.XContext) error {
 	}
 
 	// 2.打包区块
-	beginTime := time.Now()
+	beginTime := m.clock.Now()
 	block, err := m.packBlock(ctx, height, now, extData)
 	ctx.GetTimer().Mark("PackBlock")
-	metrics.CallMethodHistogram.WithLabelValues("miner", "PackBlock").Observe(time.Since(beginTime).Seconds())
+	metrics.CallMethodHistogram.WithLabelValues("miner", "PackBlock").Observe(m.clock.Now().Sub(beginTime).Seconds())
 	if err != nil {
 		ctx.GetLog().Warn("pack block error", "err", err)
 		return err
@@ -370,7 +380,7 @@ func (m *Miner) packBlock(ctx xctx.XContext, height int64,
 	ctx.GetLog().Debug("pack block get general tx succ", "txCount", len(generalTxList))
 
 	// 3.获取矿工奖励交易
-	awardTx, err := m.getAwardTx(height)
+	awardTx, err := m.getAwardTx(height, m.ctx.State.GetLatestBlockid())
 	if err != nil {
 		return nil, err
 	}
@@ -434,12 +444,23 @@ func (m *Miner) getUnconfirmedTx(sizeLimit int) ([]*lpb.Transaction, error) {
 	return m.ctx.State.GetUnconfirmedTx(false, sizeLimit)
 }
 
-func (m *Miner) getAwardTx(height int64) (*lpb.Transaction, error) {
+func (m *Miner) getAwardTx(height int64, preHash []byte) (*lpb.Transaction, error) {
 	amount := m.ctx.Ledger.GenesisBlock.CalcAward(height)
 	if amount.Cmp(big.NewInt(0)) < 0 {
 		return nil, errors.New("amount in transaction can not be negative number")
 	}
 
+	// 共识需要拆分区块奖励时，按照共识给出的分配生成奖励交易
+	if splitter, ok := m.ctx.Consensus.(consensus.AwardSplitter); ok {
+		outputs, err := splitter.SplitAward(preHash, height, m.ctx.Address.Address, amount)
+		if err != nil {
+			return nil, err
+		}
+		if len(outputs) > 1 {
+			return tx.GenerateSplitAwardTx(outputs, []byte("award"))
+		}
+	}
+
 	awardTx, err := tx.GenerateAwardTx(m.ctx.Address.Address, amount.String(), []byte("award"))
 	if err != nil {
 		return nil, err
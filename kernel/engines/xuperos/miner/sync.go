@@ -38,11 +38,11 @@ var (
 	ErrNoNewBlock    = errors.New("no new block found")
 )
 
-func traceSync() func(string) {
-	last := time.Now()
+func (m *Miner) traceSync() func(string) {
+	last := m.clock.Now()
 	return func(action string) {
-		metrics.CallMethodHistogram.WithLabelValues("sync", action).Observe(time.Since(last).Seconds())
-		last = time.Now()
+		metrics.CallMethodHistogram.WithLabelValues("sync", action).Observe(m.clock.Now().Sub(last).Seconds())
+		last = m.clock.Now()
 	}
 }
 
@@ -155,8 +155,8 @@ func (m *Miner) getMaxBlockHeight(ctx xctx.XContext) (string, int64, []byte, err
 
 // syncWithValidators 向拥有最长链的验证人节点进行区块同步，直到区块高度完全一致，timeout用于设置同步超时时间，超时之后无论是否同步完毕都停止。
 func (m *Miner) syncWithValidators(ctx xctx.XContext, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for time.Now().Before(deadline) {
+	deadline := m.clock.Now().Add(timeout)
+	for m.clock.Now().Before(deadline) {
 		size, err := m.syncWithLongestChain(ctx)
 		if err != nil {
 			ctx.GetLog().Warn("syncWithLongestChain error", "error", err)
@@ -225,7 +225,7 @@ func (m *Miner) syncWithNeighbors(ctx xctx.XContext) error {
 
 func (m *Miner) syncBlockWithHeight(ctx xctx.XContext, height int64, size int) (int, error) {
 	ctx.GetLog().Debug("getBlocksByHeight", "height", height, "size", size)
-	trace := traceSync()
+	trace := m.traceSync()
 	blocks, err := m.getBlocksByHeight(ctx, height, size)
 	if err == ErrNoNewBlock {
 		return 0, nil
@@ -267,7 +267,7 @@ func (m *Miner) getBlocksByHeight(ctx xctx.XContext, height int64, size int) ([]
 		Size:   int64(size),
 	}
 
-	trace := traceSync()
+	trace := m.traceSync()
 	var opts []p2p.OptionFunc
 	if ctx.Value(peersKey) != nil {
 		ctx.GetLog().Debug("sync with peer address", "address", ctx.Value(peersKey))
@@ -314,7 +314,7 @@ func (m *Miner) getBlocksByHeight(ctx xctx.XContext, height int64, size int) ([]
 }
 
 func (m *Miner) fillBlockTxs(ctx xctx.XContext, block *lpb.InternalBlock) error {
-	trace := traceSync()
+	trace := m.traceSync()
 	txids := block.GetMerkleTree()[:block.GetTxCount()]
 
 	blockTxs := make([]*lpb.Transaction, len(txids))
@@ -415,7 +415,7 @@ func (m *Miner) batchConfirmBlocks(ctx xctx.XContext, blocks []*lpb.InternalBloc
 	}
 
 	for _, block := range blocks {
-		trace := traceSync()
+		trace := m.traceSync()
 		xTimer := timer.NewXTimer()
 		valid, err := m.ctx.Ledger.VerifyBlock(block, ctx.GetLog().GetLogId())
 		if err != nil {
@@ -65,6 +65,13 @@ func (t *chainReader) GetChainStatus() (*xpb.ChainStatus, error) {
 		chainStatus.BranchIds[i] = fmt.Sprintf("%x", branchId)
 	}
 
+	consensus, err := t.chainCtx.Consensus.GetConsensusStatus()
+	if err != nil {
+		t.log.Warn("get consensus status error", "err", err)
+		return nil, common.ErrChainStatus
+	}
+	chainStatus.FinalizedHeight, chainStatus.FinalizedBlockid = consensus.GetFinalizedBlock()
+
 	return chainStatus, nil
 }
 
@@ -0,0 +1,98 @@
+package reader
+
+import (
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// 基于二级索引的查询，需要在引擎配置中开启indexer
+type IndexReader interface {
+	// 分页查询地址相关交易
+	QueryAddressTxs(address, cursor string, limit int, reverse bool) (*xpb.TxIndexPage, error)
+	// 分页查询合约相关交易
+	QueryContractTxs(contract, cursor string, limit int, reverse bool) (*xpb.TxIndexPage, error)
+	// 分页查询合约事件，包含事件内容
+	QueryContractEvents(contract, event, cursor string, limit int, reverse bool) (*xpb.EventIndexPage, error)
+}
+
+type indexReader struct {
+	chainCtx *common.ChainCtx
+	baseCtx  xctx.XContext
+	log      logs.Logger
+}
+
+func NewIndexReader(chainCtx *common.ChainCtx, baseCtx xctx.XContext) IndexReader {
+	if chainCtx == nil || baseCtx == nil {
+		return nil
+	}
+
+	reader := &indexReader{
+		chainCtx: chainCtx,
+		baseCtx:  baseCtx,
+		log:      baseCtx.GetLog(),
+	}
+
+	return reader
+}
+
+func (t *indexReader) QueryAddressTxs(address, cursor string, limit int, reverse bool) (*xpb.TxIndexPage, error) {
+	if t.chainCtx.Indexer == nil {
+		return nil, common.ErrIndexerDisabled
+	}
+
+	page, err := t.chainCtx.Indexer.QueryAddressTxs(address, cursor, limit, reverse)
+	if err != nil {
+		t.log.Warn("query address txs from index error", "address", address, "err", err)
+		return nil, common.CastError(err)
+	}
+
+	return page, nil
+}
+
+func (t *indexReader) QueryContractTxs(contract, cursor string, limit int, reverse bool) (*xpb.TxIndexPage, error) {
+	if t.chainCtx.Indexer == nil {
+		return nil, common.ErrIndexerDisabled
+	}
+
+	page, err := t.chainCtx.Indexer.QueryContractTxs(contract, cursor, limit, reverse)
+	if err != nil {
+		t.log.Warn("query contract txs from index error", "contract", contract, "err", err)
+		return nil, common.CastError(err)
+	}
+
+	return page, nil
+}
+
+func (t *indexReader) QueryContractEvents(contract, event, cursor string,
+	limit int, reverse bool) (*xpb.EventIndexPage, error) {
+	if t.chainCtx.Indexer == nil {
+		return nil, common.ErrIndexerDisabled
+	}
+
+	page, err := t.chainCtx.Indexer.QueryContractEvents(contract, event, cursor, limit, reverse)
+	if err != nil {
+		t.log.Warn("query contract events from index error", "contract", contract, "event", event, "err", err)
+		return nil, common.CastError(err)
+	}
+
+	// 索引只记录事件位置，事件内容从交易中解析
+	for _, item := range page.Events {
+		tx, err := t.chainCtx.Ledger.QueryTransaction(item.Txid)
+		if err != nil {
+			t.log.Warn("query event tx error", "txid", utils.F(item.Txid), "err", err)
+			return nil, common.ErrTxNotExist
+		}
+		events, err := sandbox.ParseContractEvents(tx)
+		if err != nil || int(item.EventIndex) >= len(events) {
+			t.log.Warn("parse event from tx error", "txid", utils.F(item.Txid), "err", err)
+			return nil, common.ErrInternal.More("parse contract events failed")
+		}
+		item.Event = events[item.EventIndex]
+	}
+
+	return page, nil
+}
@@ -0,0 +1,114 @@
+package reader
+
+import (
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/supply"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/lib/logs"
+)
+
+const (
+	// 富豪榜单次最多返回的地址数
+	MaxTopHolders = 1000
+)
+
+// 原生代币供应量统计查询，height小于0时查询最新状态，否则只能查询不可逆高度
+type SupplyReader interface {
+	// 查询总量、冻结量、锁定量和流通量
+	GetSupply(height int64) (*xpb.SupplyInfo, error)
+	// 查询余额最多的n个地址
+	GetTopHolders(height int64, n int) (*xpb.TopHolders, error)
+	// 查询余额分布
+	GetBalanceDistribution(height int64) (*xpb.BalanceDistribution, error)
+}
+
+type supplyReader struct {
+	chainCtx *common.ChainCtx
+	baseCtx  xctx.XContext
+	log      logs.Logger
+}
+
+func NewSupplyReader(chainCtx *common.ChainCtx, baseCtx xctx.XContext) SupplyReader {
+	if chainCtx == nil || baseCtx == nil {
+		return nil
+	}
+
+	reader := &supplyReader{
+		chainCtx: chainCtx,
+		baseCtx:  baseCtx,
+		log:      baseCtx.GetLog(),
+	}
+
+	return reader
+}
+
+func (t *supplyReader) GetSupply(height int64) (*xpb.SupplyInfo, error) {
+	info, err := t.chainCtx.State.GetSupplyInfo(height)
+	if err != nil {
+		t.log.Warn("get supply info error", "height", height, "err", err)
+		return nil, castSupplyError(err)
+	}
+
+	return &xpb.SupplyInfo{
+		Height:      info.Height,
+		Total:       info.Total.String(),
+		Frozen:      info.Frozen.String(),
+		Locked:      info.Locked.String(),
+		Circulating: info.Circulating().String(),
+	}, nil
+}
+
+func (t *supplyReader) GetTopHolders(height int64, n int) (*xpb.TopHolders, error) {
+	if n <= 0 || n > MaxTopHolders {
+		return nil, common.ErrParameter.More("holder count should be in (0, %d]", MaxTopHolders)
+	}
+
+	ranking, err := t.chainCtx.State.GetTopHolders(height, n)
+	if err != nil {
+		t.log.Warn("get top holders error", "height", height, "n", n, "err", err)
+		return nil, castSupplyError(err)
+	}
+
+	out := &xpb.TopHolders{Height: ranking.Height}
+	for _, holder := range ranking.Holders {
+		out.Holders = append(out.Holders, &xpb.TokenHolder{
+			Address: holder.Address,
+			Balance: holder.Balance.String(),
+		})
+	}
+	return out, nil
+}
+
+func (t *supplyReader) GetBalanceDistribution(height int64) (*xpb.BalanceDistribution, error) {
+	dist, err := t.chainCtx.State.GetBalanceDistribution(height)
+	if err != nil {
+		t.log.Warn("get balance distribution error", "height", height, "err", err)
+		return nil, castSupplyError(err)
+	}
+
+	out := &xpb.BalanceDistribution{
+		Height:  dist.Height,
+		Holders: dist.Holders,
+	}
+	for _, bucket := range dist.Buckets {
+		out.Buckets = append(out.Buckets, &xpb.BalanceBucket{
+			Min:     bucket.Min.String(),
+			Max:     bucket.Max.String(),
+			Holders: bucket.Holders,
+			Amount:  bucket.Amount.String(),
+		})
+	}
+	return out, nil
+}
+
+func castSupplyError(err error) error {
+	switch err {
+	case state.ErrHeightNotIrreversible, supply.ErrHistoryUnavailable:
+		return common.ErrSupplyUnavailable.More("%v", err)
+	case supply.ErrInvalidHeight, supply.ErrInvalidLimit:
+		return common.ErrParameter.More("%v", err)
+	}
+	return common.CastError(err)
+}
@@ -6,6 +6,7 @@ import (
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/utils"
 )
@@ -23,6 +24,8 @@ type UtxoReader interface {
 	SelectUTXO(account string, need *big.Int, isLock, isExclude bool) (*lpb.UtxoOutput, error)
 	// 按最大交易大小选择utxo
 	SelectUTXOBySize(account string, isLock, isExclude bool) (*lpb.UtxoOutput, error)
+	// 分页查询账户转账历史，需要开启二级索引
+	QueryTxHistory(account, cursor string, limit int, reverse bool) (*xpb.TxHistoryPage, error)
 }
 
 type utxoReader struct {
@@ -132,3 +135,115 @@ func (t *utxoReader) SelectUTXOBySize(account string, isLock, isExclude bool) (*
 	}
 	return out, nil
 }
+
+func (t *utxoReader) QueryTxHistory(account, cursor string,
+	limit int, reverse bool) (*xpb.TxHistoryPage, error) {
+	if t.chainCtx.Indexer == nil {
+		return nil, common.ErrIndexerDisabled
+	}
+
+	refs, err := t.chainCtx.Indexer.QueryAddressTxs(account, cursor, limit, reverse)
+	if err != nil {
+		t.log.Warn("query address txs from index error", "account", account, "err", err)
+		return nil, common.CastError(err)
+	}
+
+	out := &xpb.TxHistoryPage{
+		NextCursor:    refs.GetNextCursor(),
+		IndexedHeight: refs.GetIndexedHeight(),
+	}
+	for _, ref := range refs.GetTxs() {
+		tx, err := t.chainCtx.Ledger.QueryTransaction(ref.GetTxid())
+		if err != nil {
+			t.log.Warn("query history tx error", "txid", utils.F(ref.GetTxid()), "err", err)
+			return nil, common.ErrTxNotExist
+		}
+		item := parseTxHistory(account, tx)
+		if item == nil {
+			// 只和账户签名相关，没有转账
+			continue
+		}
+
+		block, err := t.chainCtx.Ledger.QueryBlockHeader(tx.GetBlockid())
+		if err != nil {
+			t.log.Warn("query history block error", "txid", utils.F(ref.GetTxid()), "err", err)
+			return nil, common.ErrBlockNotExist
+		}
+		// 索引回滚可能稍晚于账本切换分支，跳过已经不在主干上的记录
+		if !block.GetInTrunk() || block.GetHeight() != ref.GetHeight() {
+			continue
+		}
+		item.Height = block.GetHeight()
+		item.Timestamp = block.GetTimestamp()
+		out.Items = append(out.Items, item)
+	}
+
+	return out, nil
+}
+
+// parseTxHistory 根据交易的utxo输入输出计算账户的资金变化，没有资金变化时返回nil。
+// 净转入记为转入，否则记为转出，手续费只在账户净转出时计入
+func parseTxHistory(account string, tx *lpb.Transaction) *xpb.TxHistoryItem {
+	spent, received, fee := big.NewInt(0), big.NewInt(0), big.NewInt(0)
+	var payers, payees []string
+	for _, input := range tx.GetTxInputs() {
+		from := string(input.GetFromAddr())
+		if from == account {
+			spent.Add(spent, new(big.Int).SetBytes(input.GetAmount()))
+			continue
+		}
+		payers = appendUnique(payers, from)
+	}
+	for _, output := range tx.GetTxOutputs() {
+		amount := new(big.Int).SetBytes(output.GetAmount())
+		switch to := string(output.GetToAddr()); to {
+		case account:
+			received.Add(received, amount)
+		case lpb.FeePlaceholder:
+			fee.Add(fee, amount)
+		default:
+			payees = appendUnique(payees, to)
+		}
+	}
+	if spent.Sign() == 0 && received.Sign() == 0 {
+		return nil
+	}
+
+	item := &xpb.TxHistoryItem{
+		Txid:     tx.GetTxid(),
+		Coinbase: tx.GetCoinbase(),
+		Fee:      "0",
+	}
+	net := new(big.Int).Sub(received, spent)
+	if net.Sign() > 0 {
+		item.Direction = xpb.TxDirection_TX_DIRECTION_IN
+		item.Amount = net.String()
+		item.Counterparties = payers
+		return item
+	}
+
+	// 净转出部分先扣除手续费，剩余的是转给对手方的金额
+	out := net.Neg(net)
+	if fee.Cmp(out) > 0 {
+		fee.Set(out)
+	}
+	out.Sub(out, fee)
+	item.Fee = fee.String()
+	item.Amount = out.String()
+	if out.Sign() > 0 {
+		item.Direction = xpb.TxDirection_TX_DIRECTION_OUT
+		item.Counterparties = payees
+	} else {
+		item.Direction = xpb.TxDirection_TX_DIRECTION_SELF
+	}
+	return item
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
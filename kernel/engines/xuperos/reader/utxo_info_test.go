@@ -0,0 +1,70 @@
+package reader
+
+import (
+	"math/big"
+	"testing"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func amountBytes(v int64) []byte {
+	return big.NewInt(v).Bytes()
+}
+
+func newTransferTx(from string, spent int64, outputs map[string]int64) *lpb.Transaction {
+	tx := &lpb.Transaction{Txid: []byte("txid")}
+	if from != "" {
+		tx.TxInputs = []*protos.TxInput{{FromAddr: []byte(from), Amount: amountBytes(spent)}}
+	}
+	for to, amount := range outputs {
+		tx.TxOutputs = append(tx.TxOutputs, &protos.TxOutput{ToAddr: []byte(to), Amount: amountBytes(amount)})
+	}
+	return tx
+}
+
+func TestParseTxHistory(t *testing.T) {
+	// alice给bob转账30，手续费1，找零69
+	tx := newTransferTx("alice", 100, map[string]int64{"bob": 30, "alice": 69, lpb.FeePlaceholder: 1})
+
+	cases := []struct {
+		account   string
+		direction xpb.TxDirection
+		amount    string
+		fee       string
+		peers     []string
+	}{
+		{"alice", xpb.TxDirection_TX_DIRECTION_OUT, "30", "1", []string{"bob"}},
+		{"bob", xpb.TxDirection_TX_DIRECTION_IN, "30", "0", []string{"alice"}},
+	}
+	for _, c := range cases {
+		item := parseTxHistory(c.account, tx)
+		if item == nil {
+			t.Fatalf("%s: expect history item", c.account)
+		}
+		if item.Direction != c.direction || item.Amount != c.amount || item.Fee != c.fee {
+			t.Fatalf("%s: unexpected item:%v", c.account, item)
+		}
+		if len(item.Counterparties) != len(c.peers) || item.Counterparties[0] != c.peers[0] {
+			t.Fatalf("%s: unexpected counterparties:%v", c.account, item.Counterparties)
+		}
+	}
+
+	if item := parseTxHistory("carol", tx); item != nil {
+		t.Fatalf("unrelated account should have no history:%v", item)
+	}
+
+	self := newTransferTx("alice", 10, map[string]int64{"alice": 9, lpb.FeePlaceholder: 1})
+	if item := parseTxHistory("alice", self); item.Direction != xpb.TxDirection_TX_DIRECTION_SELF ||
+		item.Amount != "0" || item.Fee != "1" {
+		t.Fatalf("unexpected self transfer item:%v", item)
+	}
+
+	award := newTransferTx("", 0, map[string]int64{"miner": 50})
+	award.Coinbase = true
+	if item := parseTxHistory("miner", award); item.Direction != xpb.TxDirection_TX_DIRECTION_IN ||
+		item.Amount != "50" || !item.Coinbase || len(item.Counterparties) != 0 {
+		t.Fatalf("unexpected coinbase item:%v", item)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
 	xldgpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	protos "github.com/xuperchain/xupercore/protos"
 	math "math"
 )
 
@@ -21,6 +22,41 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// 交易对账户余额的影响方向
+type TxDirection int32
+
+const (
+	TxDirection_TX_DIRECTION_NONE TxDirection = 0
+	// 转入
+	TxDirection_TX_DIRECTION_IN TxDirection = 1
+	// 转出
+	TxDirection_TX_DIRECTION_OUT TxDirection = 2
+	// 转给自己，只扣除手续费
+	TxDirection_TX_DIRECTION_SELF TxDirection = 3
+)
+
+var TxDirection_name = map[int32]string{
+	0: "TX_DIRECTION_NONE",
+	1: "TX_DIRECTION_IN",
+	2: "TX_DIRECTION_OUT",
+	3: "TX_DIRECTION_SELF",
+}
+
+var TxDirection_value = map[string]int32{
+	"TX_DIRECTION_NONE": 0,
+	"TX_DIRECTION_IN":   1,
+	"TX_DIRECTION_OUT":  2,
+	"TX_DIRECTION_SELF": 3,
+}
+
+func (x TxDirection) String() string {
+	return proto.EnumName(TxDirection_name, int32(x))
+}
+
+func (TxDirection) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{0}
+}
+
 type Transactions struct {
 	Txs                  []*xldgpb.Transaction `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
@@ -165,13 +201,16 @@ func (m *BlockInfo) GetBlock() *xldgpb.InternalBlock {
 }
 
 type ChainStatus struct {
-	LedgerMeta           *xldgpb.LedgerMeta    `protobuf:"bytes,1,opt,name=ledger_meta,json=ledgerMeta,proto3" json:"ledger_meta,omitempty"`
-	UtxoMeta             *xldgpb.UtxoMeta      `protobuf:"bytes,2,opt,name=utxo_meta,json=utxoMeta,proto3" json:"utxo_meta,omitempty"`
-	Block                *xldgpb.InternalBlock `protobuf:"bytes,3,opt,name=block,proto3" json:"block,omitempty"`
-	BranchIds            []string              `protobuf:"bytes,4,rep,name=branch_ids,json=branchIds,proto3" json:"branch_ids,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	LedgerMeta *xldgpb.LedgerMeta    `protobuf:"bytes,1,opt,name=ledger_meta,json=ledgerMeta,proto3" json:"ledger_meta,omitempty"`
+	UtxoMeta   *xldgpb.UtxoMeta      `protobuf:"bytes,2,opt,name=utxo_meta,json=utxoMeta,proto3" json:"utxo_meta,omitempty"`
+	Block      *xldgpb.InternalBlock `protobuf:"bytes,3,opt,name=block,proto3" json:"block,omitempty"`
+	BranchIds  []string              `protobuf:"bytes,4,rep,name=branch_ids,json=branchIds,proto3" json:"branch_ids,omitempty"`
+	// 按当前共识的规则已不可逆的最高区块
+	FinalizedHeight      int64    `protobuf:"varint,5,opt,name=finalized_height,json=finalizedHeight,proto3" json:"finalized_height,omitempty"`
+	FinalizedBlockid     []byte   `protobuf:"bytes,6,opt,name=finalized_blockid,json=finalizedBlockid,proto3" json:"finalized_blockid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ChainStatus) Reset()         { *m = ChainStatus{} }
@@ -227,6 +266,20 @@ func (m *ChainStatus) GetBranchIds() []string {
 	return nil
 }
 
+func (m *ChainStatus) GetFinalizedHeight() int64 {
+	if m != nil {
+		return m.FinalizedHeight
+	}
+	return 0
+}
+
+func (m *ChainStatus) GetFinalizedBlockid() []byte {
+	if m != nil {
+		return m.FinalizedBlockid
+	}
+	return nil
+}
+
 type SystemStatus struct {
 	ChainStatus          *ChainStatus `protobuf:"bytes,1,opt,name=chain_status,json=chainStatus,proto3" json:"chain_status,omitempty"`
 	PeerUrls             []string     `protobuf:"bytes,2,rep,name=peer_urls,json=peerUrls,proto3" json:"peer_urls,omitempty"`
@@ -620,7 +673,697 @@ func (m *GetBlockTxsResponse) GetTxs() []*xldgpb.Transaction {
 	return nil
 }
 
+// 二级索引中的交易位置
+type TxIndex struct {
+	Height               int64    `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	TxIndex              int32    `protobuf:"varint,2,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
+	Txid                 []byte   `protobuf:"bytes,3,opt,name=txid,proto3" json:"txid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxIndex) Reset()         { *m = TxIndex{} }
+func (m *TxIndex) String() string { return proto.CompactTextString(m) }
+func (*TxIndex) ProtoMessage()    {}
+func (*TxIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{12}
+}
+
+func (m *TxIndex) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxIndex.Unmarshal(m, b)
+}
+func (m *TxIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxIndex.Marshal(b, m, deterministic)
+}
+func (m *TxIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxIndex.Merge(m, src)
+}
+func (m *TxIndex) XXX_Size() int {
+	return xxx_messageInfo_TxIndex.Size(m)
+}
+func (m *TxIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxIndex.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxIndex proto.InternalMessageInfo
+
+func (m *TxIndex) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *TxIndex) GetTxIndex() int32 {
+	if m != nil {
+		return m.TxIndex
+	}
+	return 0
+}
+
+func (m *TxIndex) GetTxid() []byte {
+	if m != nil {
+		return m.Txid
+	}
+	return nil
+}
+
+type TxIndexPage struct {
+	Txs []*TxIndex `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	// 下一页游标，为空表示没有更多数据
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// 索引服务当前已处理的高度
+	IndexedHeight        int64    `protobuf:"varint,3,opt,name=indexed_height,json=indexedHeight,proto3" json:"indexed_height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxIndexPage) Reset()         { *m = TxIndexPage{} }
+func (m *TxIndexPage) String() string { return proto.CompactTextString(m) }
+func (*TxIndexPage) ProtoMessage()    {}
+func (*TxIndexPage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{13}
+}
+
+func (m *TxIndexPage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxIndexPage.Unmarshal(m, b)
+}
+func (m *TxIndexPage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxIndexPage.Marshal(b, m, deterministic)
+}
+func (m *TxIndexPage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxIndexPage.Merge(m, src)
+}
+func (m *TxIndexPage) XXX_Size() int {
+	return xxx_messageInfo_TxIndexPage.Size(m)
+}
+func (m *TxIndexPage) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxIndexPage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxIndexPage proto.InternalMessageInfo
+
+func (m *TxIndexPage) GetTxs() []*TxIndex {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+func (m *TxIndexPage) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *TxIndexPage) GetIndexedHeight() int64 {
+	if m != nil {
+		return m.IndexedHeight
+	}
+	return 0
+}
+
+// 二级索引中的合约事件位置
+type EventIndex struct {
+	Height               int64                 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	TxIndex              int32                 `protobuf:"varint,2,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
+	EventIndex           int32                 `protobuf:"varint,3,opt,name=event_index,json=eventIndex,proto3" json:"event_index,omitempty"`
+	Txid                 []byte                `protobuf:"bytes,4,opt,name=txid,proto3" json:"txid,omitempty"`
+	Event                *protos.ContractEvent `protobuf:"bytes,5,opt,name=event,proto3" json:"event,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *EventIndex) Reset()         { *m = EventIndex{} }
+func (m *EventIndex) String() string { return proto.CompactTextString(m) }
+func (*EventIndex) ProtoMessage()    {}
+func (*EventIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{14}
+}
+
+func (m *EventIndex) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventIndex.Unmarshal(m, b)
+}
+func (m *EventIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventIndex.Marshal(b, m, deterministic)
+}
+func (m *EventIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventIndex.Merge(m, src)
+}
+func (m *EventIndex) XXX_Size() int {
+	return xxx_messageInfo_EventIndex.Size(m)
+}
+func (m *EventIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventIndex.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventIndex proto.InternalMessageInfo
+
+func (m *EventIndex) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *EventIndex) GetTxIndex() int32 {
+	if m != nil {
+		return m.TxIndex
+	}
+	return 0
+}
+
+func (m *EventIndex) GetEventIndex() int32 {
+	if m != nil {
+		return m.EventIndex
+	}
+	return 0
+}
+
+func (m *EventIndex) GetTxid() []byte {
+	if m != nil {
+		return m.Txid
+	}
+	return nil
+}
+
+func (m *EventIndex) GetEvent() *protos.ContractEvent {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+type EventIndexPage struct {
+	Events []*EventIndex `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// 下一页游标，为空表示没有更多数据
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// 索引服务当前已处理的高度
+	IndexedHeight        int64    `protobuf:"varint,3,opt,name=indexed_height,json=indexedHeight,proto3" json:"indexed_height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EventIndexPage) Reset()         { *m = EventIndexPage{} }
+func (m *EventIndexPage) String() string { return proto.CompactTextString(m) }
+func (*EventIndexPage) ProtoMessage()    {}
+func (*EventIndexPage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{15}
+}
+
+func (m *EventIndexPage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventIndexPage.Unmarshal(m, b)
+}
+func (m *EventIndexPage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventIndexPage.Marshal(b, m, deterministic)
+}
+func (m *EventIndexPage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventIndexPage.Merge(m, src)
+}
+func (m *EventIndexPage) XXX_Size() int {
+	return xxx_messageInfo_EventIndexPage.Size(m)
+}
+func (m *EventIndexPage) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventIndexPage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventIndexPage proto.InternalMessageInfo
+
+func (m *EventIndexPage) GetEvents() []*EventIndex {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *EventIndexPage) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *EventIndexPage) GetIndexedHeight() int64 {
+	if m != nil {
+		return m.IndexedHeight
+	}
+	return 0
+}
+
+// 账户转账历史记录
+type TxHistoryItem struct {
+	Txid   []byte `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Height int64  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// 区块时间戳，单位纳秒
+	Timestamp int64       `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Direction TxDirection `protobuf:"varint,4,opt,name=direction,proto3,enum=protos.TxDirection" json:"direction,omitempty"`
+	// 转入或转出的金额，不含手续费
+	Amount string `protobuf:"bytes,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	// 交易对手方，转入时为付款方，转出时为收款方
+	Counterparties []string `protobuf:"bytes,6,rep,name=counterparties,proto3" json:"counterparties,omitempty"`
+	// 账户支付的手续费
+	Fee                  string   `protobuf:"bytes,7,opt,name=fee,proto3" json:"fee,omitempty"`
+	Coinbase             bool     `protobuf:"varint,8,opt,name=coinbase,proto3" json:"coinbase,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxHistoryItem) Reset()         { *m = TxHistoryItem{} }
+func (m *TxHistoryItem) String() string { return proto.CompactTextString(m) }
+func (*TxHistoryItem) ProtoMessage()    {}
+func (*TxHistoryItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{16}
+}
+
+func (m *TxHistoryItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxHistoryItem.Unmarshal(m, b)
+}
+func (m *TxHistoryItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxHistoryItem.Marshal(b, m, deterministic)
+}
+func (m *TxHistoryItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxHistoryItem.Merge(m, src)
+}
+func (m *TxHistoryItem) XXX_Size() int {
+	return xxx_messageInfo_TxHistoryItem.Size(m)
+}
+func (m *TxHistoryItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxHistoryItem.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxHistoryItem proto.InternalMessageInfo
+
+func (m *TxHistoryItem) GetTxid() []byte {
+	if m != nil {
+		return m.Txid
+	}
+	return nil
+}
+
+func (m *TxHistoryItem) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *TxHistoryItem) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *TxHistoryItem) GetDirection() TxDirection {
+	if m != nil {
+		return m.Direction
+	}
+	return TxDirection_TX_DIRECTION_NONE
+}
+
+func (m *TxHistoryItem) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *TxHistoryItem) GetCounterparties() []string {
+	if m != nil {
+		return m.Counterparties
+	}
+	return nil
+}
+
+func (m *TxHistoryItem) GetFee() string {
+	if m != nil {
+		return m.Fee
+	}
+	return ""
+}
+
+func (m *TxHistoryItem) GetCoinbase() bool {
+	if m != nil {
+		return m.Coinbase
+	}
+	return false
+}
+
+type TxHistoryPage struct {
+	Items []*TxHistoryItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// 下一页游标，为空表示没有更多数据
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// 索引服务当前已处理的高度
+	IndexedHeight        int64    `protobuf:"varint,3,opt,name=indexed_height,json=indexedHeight,proto3" json:"indexed_height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxHistoryPage) Reset()         { *m = TxHistoryPage{} }
+func (m *TxHistoryPage) String() string { return proto.CompactTextString(m) }
+func (*TxHistoryPage) ProtoMessage()    {}
+func (*TxHistoryPage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{17}
+}
+
+func (m *TxHistoryPage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxHistoryPage.Unmarshal(m, b)
+}
+func (m *TxHistoryPage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxHistoryPage.Marshal(b, m, deterministic)
+}
+func (m *TxHistoryPage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxHistoryPage.Merge(m, src)
+}
+func (m *TxHistoryPage) XXX_Size() int {
+	return xxx_messageInfo_TxHistoryPage.Size(m)
+}
+func (m *TxHistoryPage) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxHistoryPage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxHistoryPage proto.InternalMessageInfo
+
+func (m *TxHistoryPage) GetItems() []*TxHistoryItem {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *TxHistoryPage) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *TxHistoryPage) GetIndexedHeight() int64 {
+	if m != nil {
+		return m.IndexedHeight
+	}
+	return 0
+}
+
+// 原生代币供应量，金额均为十进制字符串
+type SupplyInfo struct {
+	Height int64  `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Total  string `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"`
+	// 冻结量，到达解冻高度后可以使用
+	Frozen string `protobuf:"bytes,3,opt,name=frozen,proto3" json:"frozen,omitempty"`
+	// 锁定量，永久不可使用
+	Locked string `protobuf:"bytes,4,opt,name=locked,proto3" json:"locked,omitempty"`
+	// 流通量，总量扣除冻结量和锁定量
+	Circulating          string   `protobuf:"bytes,5,opt,name=circulating,proto3" json:"circulating,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SupplyInfo) Reset()         { *m = SupplyInfo{} }
+func (m *SupplyInfo) String() string { return proto.CompactTextString(m) }
+func (*SupplyInfo) ProtoMessage()    {}
+func (*SupplyInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{18}
+}
+
+func (m *SupplyInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SupplyInfo.Unmarshal(m, b)
+}
+func (m *SupplyInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SupplyInfo.Marshal(b, m, deterministic)
+}
+func (m *SupplyInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SupplyInfo.Merge(m, src)
+}
+func (m *SupplyInfo) XXX_Size() int {
+	return xxx_messageInfo_SupplyInfo.Size(m)
+}
+func (m *SupplyInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SupplyInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SupplyInfo proto.InternalMessageInfo
+
+func (m *SupplyInfo) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *SupplyInfo) GetTotal() string {
+	if m != nil {
+		return m.Total
+	}
+	return ""
+}
+
+func (m *SupplyInfo) GetFrozen() string {
+	if m != nil {
+		return m.Frozen
+	}
+	return ""
+}
+
+func (m *SupplyInfo) GetLocked() string {
+	if m != nil {
+		return m.Locked
+	}
+	return ""
+}
+
+func (m *SupplyInfo) GetCirculating() string {
+	if m != nil {
+		return m.Circulating
+	}
+	return ""
+}
+
+type TokenHolder struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Balance              string   `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TokenHolder) Reset()         { *m = TokenHolder{} }
+func (m *TokenHolder) String() string { return proto.CompactTextString(m) }
+func (*TokenHolder) ProtoMessage()    {}
+func (*TokenHolder) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{19}
+}
+
+func (m *TokenHolder) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenHolder.Unmarshal(m, b)
+}
+func (m *TokenHolder) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenHolder.Marshal(b, m, deterministic)
+}
+func (m *TokenHolder) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenHolder.Merge(m, src)
+}
+func (m *TokenHolder) XXX_Size() int {
+	return xxx_messageInfo_TokenHolder.Size(m)
+}
+func (m *TokenHolder) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenHolder.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TokenHolder proto.InternalMessageInfo
+
+func (m *TokenHolder) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *TokenHolder) GetBalance() string {
+	if m != nil {
+		return m.Balance
+	}
+	return ""
+}
+
+// 余额最多的地址，按余额从大到小排序
+type TopHolders struct {
+	Height               int64          `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Holders              []*TokenHolder `protobuf:"bytes,2,rep,name=holders,proto3" json:"holders,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *TopHolders) Reset()         { *m = TopHolders{} }
+func (m *TopHolders) String() string { return proto.CompactTextString(m) }
+func (*TopHolders) ProtoMessage()    {}
+func (*TopHolders) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{20}
+}
+
+func (m *TopHolders) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopHolders.Unmarshal(m, b)
+}
+func (m *TopHolders) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopHolders.Marshal(b, m, deterministic)
+}
+func (m *TopHolders) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopHolders.Merge(m, src)
+}
+func (m *TopHolders) XXX_Size() int {
+	return xxx_messageInfo_TopHolders.Size(m)
+}
+func (m *TopHolders) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopHolders.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopHolders proto.InternalMessageInfo
+
+func (m *TopHolders) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *TopHolders) GetHolders() []*TokenHolder {
+	if m != nil {
+		return m.Holders
+	}
+	return nil
+}
+
+// 余额在[min, max)区间内的地址统计
+type BalanceBucket struct {
+	Min                  string   `protobuf:"bytes,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max                  string   `protobuf:"bytes,2,opt,name=max,proto3" json:"max,omitempty"`
+	Holders              int64    `protobuf:"varint,3,opt,name=holders,proto3" json:"holders,omitempty"`
+	Amount               string   `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BalanceBucket) Reset()         { *m = BalanceBucket{} }
+func (m *BalanceBucket) String() string { return proto.CompactTextString(m) }
+func (*BalanceBucket) ProtoMessage()    {}
+func (*BalanceBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{21}
+}
+
+func (m *BalanceBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalanceBucket.Unmarshal(m, b)
+}
+func (m *BalanceBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalanceBucket.Marshal(b, m, deterministic)
+}
+func (m *BalanceBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalanceBucket.Merge(m, src)
+}
+func (m *BalanceBucket) XXX_Size() int {
+	return xxx_messageInfo_BalanceBucket.Size(m)
+}
+func (m *BalanceBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalanceBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalanceBucket proto.InternalMessageInfo
+
+func (m *BalanceBucket) GetMin() string {
+	if m != nil {
+		return m.Min
+	}
+	return ""
+}
+
+func (m *BalanceBucket) GetMax() string {
+	if m != nil {
+		return m.Max
+	}
+	return ""
+}
+
+func (m *BalanceBucket) GetHolders() int64 {
+	if m != nil {
+		return m.Holders
+	}
+	return 0
+}
+
+func (m *BalanceBucket) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+type BalanceDistribution struct {
+	Height int64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	// 余额大于0的地址数
+	Holders              int64            `protobuf:"varint,2,opt,name=holders,proto3" json:"holders,omitempty"`
+	Buckets              []*BalanceBucket `protobuf:"bytes,3,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *BalanceDistribution) Reset()         { *m = BalanceDistribution{} }
+func (m *BalanceDistribution) String() string { return proto.CompactTextString(m) }
+func (*BalanceDistribution) ProtoMessage()    {}
+func (*BalanceDistribution) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{22}
+}
+
+func (m *BalanceDistribution) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalanceDistribution.Unmarshal(m, b)
+}
+func (m *BalanceDistribution) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalanceDistribution.Marshal(b, m, deterministic)
+}
+func (m *BalanceDistribution) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalanceDistribution.Merge(m, src)
+}
+func (m *BalanceDistribution) XXX_Size() int {
+	return xxx_messageInfo_BalanceDistribution.Size(m)
+}
+func (m *BalanceDistribution) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalanceDistribution.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalanceDistribution proto.InternalMessageInfo
+
+func (m *BalanceDistribution) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *BalanceDistribution) GetHolders() int64 {
+	if m != nil {
+		return m.Holders
+	}
+	return 0
+}
+
+func (m *BalanceDistribution) GetBuckets() []*BalanceBucket {
+	if m != nil {
+		return m.Buckets
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterEnum("protos.TxDirection", TxDirection_name, TxDirection_value)
 	proto.RegisterType((*Transactions)(nil), "protos.Transactions")
 	proto.RegisterType((*TxInfo)(nil), "protos.TxInfo")
 	proto.RegisterType((*BlockInfo)(nil), "protos.BlockInfo")
@@ -633,6 +1376,17 @@ func init() {
 	proto.RegisterType((*GetBlockHeaderResponse)(nil), "protos.GetBlockHeaderResponse")
 	proto.RegisterType((*GetBlockTxsRequest)(nil), "protos.GetBlockTxsRequest")
 	proto.RegisterType((*GetBlockTxsResponse)(nil), "protos.GetBlockTxsResponse")
+	proto.RegisterType((*TxIndex)(nil), "protos.TxIndex")
+	proto.RegisterType((*TxIndexPage)(nil), "protos.TxIndexPage")
+	proto.RegisterType((*EventIndex)(nil), "protos.EventIndex")
+	proto.RegisterType((*EventIndexPage)(nil), "protos.EventIndexPage")
+	proto.RegisterType((*TxHistoryItem)(nil), "protos.TxHistoryItem")
+	proto.RegisterType((*TxHistoryPage)(nil), "protos.TxHistoryPage")
+	proto.RegisterType((*SupplyInfo)(nil), "protos.SupplyInfo")
+	proto.RegisterType((*TokenHolder)(nil), "protos.TokenHolder")
+	proto.RegisterType((*TopHolders)(nil), "protos.TopHolders")
+	proto.RegisterType((*BalanceBucket)(nil), "protos.BalanceBucket")
+	proto.RegisterType((*BalanceDistribution)(nil), "protos.BalanceDistribution")
 }
 
 func init() {
@@ -640,47 +1394,84 @@ func init() {
 }
 
 var fileDescriptor_e9685bde11a1952e = []byte{
-	// 672 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0x51, 0x6b, 0xdb, 0x3a,
-	0x14, 0x26, 0x71, 0x9a, 0xc6, 0xc7, 0xb9, 0x6d, 0x51, 0x69, 0xf1, 0xed, 0xe5, 0x42, 0xea, 0xad,
-	0x2c, 0x50, 0x9a, 0xd0, 0x94, 0xed, 0x61, 0xf4, 0xa9, 0x1d, 0xb4, 0x85, 0x6d, 0x0f, 0x6e, 0x0a,
-	0x63, 0x83, 0x19, 0xc5, 0x3e, 0x4d, 0x44, 0x1d, 0xc9, 0x93, 0xe4, 0xe2, 0x8d, 0xfd, 0x96, 0xfd,
-	0x93, 0xfd, 0xb7, 0x61, 0xc9, 0x4e, 0xb3, 0xb2, 0xac, 0x0f, 0xc6, 0x3a, 0x47, 0xdf, 0xa7, 0xef,
-	0x3b, 0xc7, 0xf2, 0x81, 0xe7, 0x77, 0x28, 0x39, 0xa6, 0x43, 0xe4, 0x53, 0xc6, 0x51, 0x0d, 0x8b,
-	0x3c, 0x43, 0x29, 0xd4, 0xb0, 0xc8, 0x26, 0xe5, 0x33, 0xc8, 0xa4, 0xd0, 0x82, 0xb4, 0xcd, 0x4b,
-	0xed, 0x1d, 0x9b, 0xed, 0x58, 0x48, 0x1c, 0x4e, 0x62, 0x35, 0x4c, 0x31, 0x99, 0xa2, 0x1c, 0x16,
-	0x8b, 0x77, 0x32, 0x2d, 0x69, 0x36, 0xb4, 0xd4, 0xe0, 0x25, 0x74, 0xc7, 0x92, 0x72, 0x45, 0x63,
-	0xcd, 0x04, 0x57, 0xe4, 0x00, 0x1c, 0x5d, 0x28, 0xbf, 0xd1, 0x73, 0xfa, 0xde, 0x68, 0x7b, 0x60,
-	0x39, 0x83, 0x25, 0x48, 0x58, 0xee, 0x07, 0xdf, 0xa1, 0x3d, 0x2e, 0xae, 0xf8, 0xad, 0x20, 0xc7,
-	0xd0, 0x56, 0x9a, 0xea, 0xbc, 0xe4, 0x34, 0xfa, 0x1b, 0xa3, 0x7f, 0xff, 0xc0, 0xb9, 0x36, 0x80,
-	0xb0, 0x02, 0x92, 0x3d, 0xe8, 0x24, 0x4c, 0x69, 0xca, 0x63, 0xf4, 0x9b, 0xbd, 0x46, 0xdf, 0x09,
-	0x17, 0x31, 0x79, 0x06, 0x4d, 0x5d, 0xf8, 0x4e, 0xaf, 0xb1, 0x4a, 0xbe, 0xa9, 0x8b, 0x00, 0xc1,
-	0x3d, 0x4b, 0x45, 0x7c, 0x67, 0x0c, 0x1c, 0x3e, 0x32, 0xb0, 0x60, 0x19, 0xc8, 0x23, 0xe9, 0x43,
-	0x58, 0x9b, 0x94, 0x69, 0xa3, 0xeb, 0x8d, 0x76, 0x6a, 0xec, 0x15, 0xd7, 0x28, 0x39, 0x4d, 0x0d,
-	0x27, 0xb4, 0x98, 0xe0, 0x67, 0x03, 0xbc, 0xf3, 0x19, 0x65, 0x95, 0x7f, 0x72, 0x02, 0x9e, 0xed,
-	0x5d, 0x34, 0x47, 0x4d, 0x8d, 0x9c, 0x37, 0x22, 0xf5, 0x11, 0x6f, 0xcd, 0xd6, 0x3b, 0xd4, 0x34,
-	0x84, 0x74, 0xb1, 0x26, 0x47, 0xe0, 0xe6, 0xba, 0x10, 0x96, 0x62, 0x55, 0xb7, 0x6a, 0xca, 0x8d,
-	0x2e, 0x84, 0x21, 0x74, 0xf2, 0x6a, 0xf5, 0x60, 0xd0, 0x79, 0xda, 0x20, 0xf9, 0x1f, 0x60, 0x22,
-	0x29, 0x8f, 0x67, 0x11, 0x4b, 0x94, 0xdf, 0xea, 0x39, 0x7d, 0x37, 0x74, 0x6d, 0xe6, 0x2a, 0x51,
-	0x41, 0x0c, 0xdd, 0xeb, 0xaf, 0x4a, 0xe3, 0xbc, 0xf2, 0xff, 0x0a, 0xba, 0x71, 0x59, 0x4e, 0xb4,
-	0xd4, 0xaf, 0xb2, 0xcb, 0xf6, 0xf6, 0x0c, 0x96, 0x4a, 0x0d, 0xbd, 0x78, 0xa9, 0xee, 0xff, 0xc0,
-	0xcd, 0x10, 0x65, 0x94, 0xcb, 0x54, 0xf9, 0x4d, 0xa3, 0xd2, 0x29, 0x13, 0x37, 0x32, 0x55, 0xc1,
-	0x11, 0xb8, 0x63, 0x96, 0x55, 0xc8, 0x1e, 0x74, 0x99, 0x8a, 0xb4, 0xcc, 0xf9, 0x5d, 0xa4, 0x59,
-	0x66, 0x14, 0x3a, 0x21, 0x30, 0x35, 0x2e, 0x53, 0x63, 0x96, 0x05, 0x9f, 0x61, 0xdd, 0x7e, 0xba,
-	0x37, 0x64, 0x17, 0xda, 0x93, 0x98, 0xd3, 0x39, 0x1a, 0x98, 0x1b, 0x56, 0x11, 0xf1, 0x61, 0xdd,
-	0x94, 0xc7, 0x12, 0xd3, 0xaf, 0x6e, 0x58, 0x87, 0x64, 0x1f, 0xba, 0x1c, 0x31, 0x89, 0x62, 0xc1,
-	0x35, 0x72, 0x6d, 0x7a, 0xd4, 0x09, 0xbd, 0x32, 0x77, 0x6e, 0x53, 0xc1, 0x8f, 0x06, 0x6c, 0x9e,
-	0x0b, 0xae, 0x90, 0xab, 0x5c, 0x55, 0xae, 0x7c, 0x58, 0xbf, 0x47, 0xa9, 0x98, 0xe0, 0x95, 0x52,
-	0x1d, 0x92, 0x03, 0xd8, 0x88, 0x6b, 0x70, 0x64, 0xac, 0x34, 0x0d, 0xe0, 0x9f, 0x45, 0xf6, 0x7d,
-	0xe9, 0x68, 0x1f, 0xba, 0x4a, 0x53, 0xa9, 0xa3, 0x19, 0xb2, 0xe9, 0xcc, 0xea, 0xba, 0xa1, 0x67,
-	0x72, 0x97, 0x26, 0x45, 0x5e, 0xc0, 0xe6, 0x3d, 0x4d, 0x59, 0x42, 0xb5, 0x90, 0x2a, 0x62, 0xfc,
-	0x56, 0xf8, 0x2d, 0x83, 0xda, 0x78, 0x48, 0x97, 0xd7, 0x35, 0xf8, 0x04, 0x3b, 0x17, 0xa8, 0x4d,
-	0x0f, 0x2e, 0x91, 0x26, 0x28, 0x43, 0xfc, 0x92, 0xa3, 0xd2, 0x2b, 0xdb, 0xb1, 0x0b, 0xed, 0x4a,
-	0xd6, 0xfe, 0x2b, 0x55, 0x44, 0x08, 0xb4, 0x14, 0xfb, 0x86, 0xc6, 0x8c, 0x13, 0x9a, 0x75, 0x70,
-	0x01, 0xbb, 0x8f, 0x0f, 0x57, 0x59, 0x59, 0x0a, 0x39, 0x82, 0xb6, 0xe9, 0x62, 0xfd, 0x6b, 0xaf,
-	0xb8, 0x58, 0x15, 0x28, 0xf8, 0x00, 0xa4, 0x3e, 0x68, 0x5c, 0xa8, 0xa7, 0x2c, 0xae, 0xfe, 0x62,
-	0x5b, 0x76, 0x9c, 0x38, 0x3d, 0xa7, 0xbf, 0x66, 0x27, 0xc7, 0x29, 0x6c, 0xff, 0x76, 0x72, 0xe5,
-	0xaf, 0x9a, 0x3b, 0xad, 0xbf, 0xcf, 0x9d, 0xb3, 0xd3, 0x8f, 0xaf, 0xa7, 0x4c, 0xcf, 0xf2, 0xc9,
-	0x20, 0x16, 0x73, 0x3b, 0x0d, 0xcd, 0x4d, 0x1d, 0x3e, 0x4c, 0xbe, 0xd5, 0x13, 0x73, 0x62, 0xe7,
-	0xe4, 0xc9, 0xaf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x5b, 0x60, 0xeb, 0x04, 0x56, 0x05, 0x00, 0x00,
+	// 1254 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0xcd, 0x6f, 0xdb, 0x36,
+	0x14, 0x9f, 0x6c, 0xc7, 0x1f, 0x4f, 0x4e, 0xe2, 0x32, 0x6d, 0xa1, 0x76, 0x1b, 0xea, 0x6a, 0xeb,
+	0x96, 0x35, 0x48, 0x8c, 0xa6, 0xd8, 0x0e, 0x43, 0x2f, 0xcb, 0xc7, 0x1a, 0x03, 0x5d, 0x5a, 0x28,
+	0x2e, 0x50, 0x6c, 0xc0, 0x04, 0x5a, 0x62, 0x6c, 0x22, 0x32, 0xa5, 0x91, 0x54, 0xa1, 0x16, 0x3b,
+	0xf6, 0xb8, 0xeb, 0xae, 0x03, 0xf6, 0x6f, 0xee, 0x34, 0xf0, 0x43, 0x96, 0x9c, 0xd5, 0x28, 0x30,
+	0xf4, 0x60, 0x98, 0xef, 0xc7, 0x1f, 0xdf, 0x17, 0xdf, 0x7b, 0x22, 0x7c, 0x79, 0x45, 0x38, 0x23,
+	0xc9, 0x88, 0xb0, 0x19, 0x65, 0x44, 0x8c, 0x8a, 0x3c, 0x23, 0x3c, 0x15, 0xa3, 0x22, 0x9b, 0xaa,
+	0xdf, 0x41, 0xc6, 0x53, 0x99, 0xa2, 0xb6, 0xfe, 0x13, 0x77, 0x1f, 0xe9, 0xed, 0x28, 0xe5, 0x64,
+	0x34, 0x8d, 0xc4, 0x28, 0x21, 0xf1, 0x8c, 0xf0, 0x51, 0xb1, 0xfc, 0x8f, 0x67, 0xd9, 0xb4, 0x14,
+	0xcd, 0xd1, 0xbb, 0xf7, 0xaa, 0x23, 0x46, 0xc9, 0x28, 0x4a, 0x99, 0xe4, 0x38, 0x92, 0x86, 0xe0,
+	0x7f, 0x0b, 0xfd, 0x09, 0xc7, 0x4c, 0xe0, 0x48, 0xd2, 0x94, 0x09, 0xf4, 0x00, 0x9a, 0xb2, 0x10,
+	0x9e, 0x33, 0x6c, 0xee, 0xba, 0x87, 0x3b, 0x07, 0x46, 0xe9, 0x41, 0x8d, 0x12, 0xa8, 0x7d, 0xff,
+	0x77, 0x68, 0x4f, 0x8a, 0x31, 0xbb, 0x4c, 0xd1, 0x23, 0x68, 0x0b, 0x89, 0x65, 0xae, 0xce, 0x38,
+	0xbb, 0x5b, 0x87, 0x77, 0xde, 0x73, 0xe6, 0x42, 0x13, 0x02, 0x4b, 0x44, 0x77, 0xa1, 0x1b, 0x53,
+	0x21, 0x31, 0x8b, 0x88, 0xd7, 0x18, 0x3a, 0xbb, 0xcd, 0x60, 0x29, 0xa3, 0x2f, 0xa0, 0x21, 0x0b,
+	0xaf, 0x39, 0x74, 0xd6, 0x99, 0x6f, 0xc8, 0xc2, 0x27, 0xd0, 0x3b, 0x4a, 0xd2, 0xe8, 0x4a, 0x3b,
+	0xb0, 0x77, 0xcd, 0x81, 0xe5, 0x29, 0x4d, 0xb9, 0x66, 0x7a, 0x0f, 0x36, 0xa6, 0x0a, 0xd6, 0x76,
+	0xdd, 0xc3, 0x5b, 0x25, 0x77, 0xcc, 0x24, 0xe1, 0x0c, 0x27, 0xfa, 0x4c, 0x60, 0x38, 0xfe, 0x9f,
+	0x0d, 0x70, 0x8f, 0xe7, 0x98, 0x5a, 0xff, 0xd1, 0x63, 0x70, 0x4d, 0x72, 0xc3, 0x05, 0x91, 0x58,
+	0x9b, 0x73, 0x0f, 0x51, 0xa9, 0xe2, 0x99, 0xde, 0xfa, 0x89, 0x48, 0x1c, 0x40, 0xb2, 0x5c, 0xa3,
+	0x7d, 0xe8, 0xe5, 0xb2, 0x48, 0xcd, 0x11, 0x63, 0x75, 0x50, 0x1e, 0x79, 0x29, 0x8b, 0x54, 0x1f,
+	0xe8, 0xe6, 0x76, 0x55, 0x39, 0xd8, 0xfc, 0xb0, 0x83, 0xe8, 0x73, 0x80, 0x29, 0xc7, 0x2c, 0x9a,
+	0x87, 0x34, 0x16, 0x5e, 0x6b, 0xd8, 0xdc, 0xed, 0x05, 0x3d, 0x83, 0x8c, 0x63, 0x81, 0xbe, 0x81,
+	0xc1, 0x25, 0x65, 0x38, 0xa1, 0x6f, 0x49, 0x1c, 0xce, 0x09, 0x9d, 0xcd, 0xa5, 0xb7, 0xa1, 0xf3,
+	0xbd, 0xbd, 0xc4, 0xcf, 0x34, 0x8c, 0xf6, 0xe0, 0x46, 0x45, 0xd5, 0xca, 0x69, 0xec, 0xb5, 0x87,
+	0xce, 0x6e, 0x3f, 0xa8, 0x74, 0x1c, 0x19, 0xdc, 0x8f, 0xa0, 0x7f, 0xf1, 0x46, 0x48, 0xb2, 0xb0,
+	0x79, 0xf9, 0x0e, 0xfa, 0x91, 0x4a, 0x53, 0x58, 0xbb, 0x07, 0x75, 0x7b, 0xa6, 0xe2, 0x0e, 0x6a,
+	0x29, 0x0c, 0xdc, 0xa8, 0x12, 0xd0, 0xa7, 0xd0, 0xcb, 0x08, 0xe1, 0x61, 0xce, 0x13, 0xe1, 0x35,
+	0xb4, 0xf7, 0x5d, 0x05, 0xbc, 0xe4, 0x89, 0xf0, 0xf7, 0xa1, 0x37, 0xa1, 0x99, 0x65, 0x0e, 0xa1,
+	0x4f, 0x45, 0x28, 0x79, 0xce, 0xae, 0x42, 0x49, 0x33, 0x6d, 0xa1, 0x1b, 0x00, 0x15, 0x13, 0x05,
+	0x4d, 0x68, 0xe6, 0xff, 0x0a, 0x1d, 0x53, 0x12, 0x27, 0xe8, 0x36, 0xb4, 0xa7, 0x11, 0xc3, 0x0b,
+	0xa2, 0x69, 0xbd, 0xc0, 0x4a, 0xc8, 0x83, 0x4e, 0x19, 0x59, 0x43, 0x47, 0x56, 0x8a, 0xe8, 0x3e,
+	0xf4, 0x19, 0x21, 0x71, 0xa8, 0x7a, 0x83, 0x30, 0xa9, 0x73, 0xdf, 0x0d, 0x5c, 0x85, 0x1d, 0x1b,
+	0xc8, 0xff, 0xcb, 0x81, 0xed, 0xe3, 0x94, 0x09, 0xc2, 0x44, 0x2e, 0xac, 0x57, 0x1e, 0x74, 0x5e,
+	0x13, 0x2e, 0x68, 0xca, 0xac, 0xa5, 0x52, 0x44, 0x0f, 0x60, 0x2b, 0x2a, 0xc9, 0xa1, 0x76, 0xa5,
+	0xa1, 0x09, 0x9b, 0x4b, 0xf4, 0x5c, 0x79, 0x74, 0x1f, 0xfa, 0x42, 0x62, 0x2e, 0xcb, 0xcb, 0x69,
+	0x6a, 0x92, 0xab, 0x31, 0x7b, 0x31, 0x5f, 0xc3, 0xf6, 0x6b, 0x9c, 0xd0, 0x18, 0xcb, 0x94, 0x8b,
+	0x90, 0xb2, 0xcb, 0xd4, 0x6b, 0x69, 0xd6, 0x56, 0x05, 0xab, 0x36, 0xf0, 0x7f, 0x81, 0x5b, 0x4f,
+	0x89, 0xd4, 0x39, 0x38, 0x23, 0x38, 0x26, 0x3c, 0x20, 0xbf, 0xe5, 0x44, 0xc8, 0xb5, 0xe9, 0xb8,
+	0x0d, 0x6d, 0x6b, 0xd6, 0xf4, 0xa0, 0x95, 0x10, 0x82, 0x96, 0xa0, 0x6f, 0x89, 0x76, 0xa6, 0x19,
+	0xe8, 0xb5, 0xff, 0x14, 0x6e, 0x5f, 0x57, 0x2e, 0x32, 0x15, 0x0a, 0xda, 0x87, 0xb6, 0xce, 0x62,
+	0x39, 0x32, 0xd6, 0x14, 0xac, 0x25, 0xf9, 0xaf, 0x00, 0x95, 0x8a, 0x26, 0x85, 0xf8, 0x90, 0x8b,
+	0xeb, 0x6f, 0x6c, 0x60, 0xc6, 0x54, 0x73, 0xd8, 0xdc, 0xdd, 0x30, 0x13, 0xe9, 0x09, 0xec, 0xac,
+	0x68, 0xb6, 0xfe, 0xd9, 0x79, 0xd6, 0xfa, 0xc0, 0x3c, 0x7b, 0x01, 0x1d, 0x35, 0xcf, 0x62, 0x52,
+	0xd4, 0xf2, 0xe2, 0xac, 0xe4, 0xe5, 0x0e, 0x74, 0x65, 0x11, 0x52, 0xc5, 0xd1, 0xde, 0x6c, 0x04,
+	0x1d, 0x69, 0x8f, 0x20, 0x68, 0xc9, 0x82, 0xc6, 0x3a, 0x65, 0xfd, 0x40, 0xaf, 0xfd, 0x02, 0x5c,
+	0xab, 0xf1, 0x05, 0x9e, 0xa9, 0xab, 0xae, 0xcd, 0xd5, 0xed, 0xb2, 0x35, 0x2c, 0x43, 0xfb, 0x80,
+	0xee, 0x81, 0xcb, 0x48, 0x21, 0xc3, 0x28, 0xe7, 0x22, 0xe5, 0xb6, 0x62, 0x40, 0x41, 0xc7, 0x1a,
+	0x51, 0x55, 0xa5, 0xcd, 0x57, 0xdd, 0x6c, 0xee, 0x68, 0xd3, 0xa2, 0xa6, 0x64, 0xfc, 0xbf, 0x1d,
+	0x80, 0xd3, 0xd7, 0x84, 0xc9, 0xff, 0x1d, 0xcf, 0x3d, 0x70, 0x89, 0x52, 0x60, 0x77, 0x9b, 0x7a,
+	0x17, 0x48, 0xa5, 0xb3, 0x0c, 0xb8, 0x55, 0x05, 0xac, 0x26, 0x97, 0x66, 0xe8, 0x11, 0xa3, 0x0a,
+	0xa1, 0x6c, 0x7f, 0xfb, 0xc1, 0xd1, 0x2e, 0x05, 0x86, 0xe3, 0xbf, 0x73, 0x60, 0xab, 0xf2, 0x51,
+	0x67, 0xe8, 0x21, 0xb4, 0xf5, 0x5e, 0x99, 0x24, 0x54, 0x2a, 0xa8, 0x78, 0x81, 0x65, 0x7c, 0xb4,
+	0x54, 0xfd, 0xe3, 0xc0, 0xe6, 0xa4, 0x38, 0xa3, 0x42, 0xa6, 0xfc, 0xcd, 0x58, 0x92, 0xc5, 0x32,
+	0x32, 0xa7, 0x16, 0xd9, 0xba, 0x4e, 0xf9, 0x0c, 0x7a, 0x92, 0x2e, 0x88, 0x90, 0x78, 0x91, 0x59,
+	0xfd, 0x15, 0x80, 0x1e, 0x41, 0x2f, 0xa6, 0x9c, 0xe8, 0x22, 0xf3, 0x5a, 0xf6, 0xd3, 0xb4, 0xbc,
+	0xf7, 0x93, 0x72, 0x2b, 0xa8, 0x58, 0xca, 0x10, 0x5e, 0xa4, 0xb9, 0xcd, 0x61, 0x2f, 0xb0, 0x12,
+	0xfa, 0x4a, 0x8d, 0x93, 0x5c, 0x35, 0x54, 0x86, 0xb9, 0xa4, 0x44, 0x78, 0x6d, 0x3d, 0x2d, 0xaf,
+	0xa1, 0xaa, 0x2b, 0x2e, 0x09, 0xf1, 0x3a, 0xfa, 0xb0, 0x5a, 0xaa, 0x4f, 0x6d, 0x94, 0x52, 0x36,
+	0xc5, 0x82, 0x78, 0x5d, 0x3d, 0xd5, 0x96, 0xb2, 0xff, 0xae, 0x1e, 0xbc, 0xbe, 0x82, 0x3d, 0xd8,
+	0xa0, 0x92, 0x2c, 0xaa, 0x5e, 0x5e, 0xba, 0x5b, 0x4b, 0x51, 0x60, 0x38, 0x1f, 0xed, 0x0e, 0xfe,
+	0x70, 0x00, 0x2e, 0xf2, 0x2c, 0x4b, 0xde, 0xe8, 0xcf, 0xf9, 0xba, 0x72, 0xbd, 0x09, 0x1b, 0x32,
+	0x95, 0x38, 0xb1, 0x86, 0x8c, 0xa0, 0xd8, 0x97, 0x3c, 0x7d, 0x4b, 0x98, 0x9d, 0x9d, 0x56, 0x52,
+	0xb8, 0x9a, 0x04, 0x24, 0xb6, 0xd3, 0xd2, 0x4a, 0x68, 0x08, 0x6e, 0x44, 0x79, 0x94, 0x27, 0x58,
+	0x52, 0x36, 0xb3, 0x69, 0xae, 0x43, 0xfe, 0x0f, 0xe0, 0x4e, 0xd2, 0x2b, 0xc2, 0xce, 0xd2, 0x24,
+	0x26, 0x5c, 0x8d, 0x20, 0x1c, 0xc7, 0x9c, 0x08, 0x51, 0xce, 0x78, 0x2b, 0xaa, 0x9d, 0x29, 0x4e,
+	0x96, 0x8f, 0x98, 0x5e, 0x50, 0x8a, 0xfe, 0x05, 0xc0, 0x24, 0xcd, 0x8c, 0x02, 0xb1, 0x36, 0xa0,
+	0x7d, 0xe8, 0xcc, 0x0d, 0xc5, 0x6b, 0xd8, 0xe9, 0x54, 0xa6, 0xbb, 0xb2, 0x1f, 0x94, 0x1c, 0x9f,
+	0xc0, 0xe6, 0x91, 0xd1, 0x7f, 0x94, 0x47, 0x57, 0x44, 0xaa, 0xcb, 0x5e, 0xd0, 0xf2, 0xcb, 0xa3,
+	0x96, 0x1a, 0xc1, 0x85, 0xf5, 0x46, 0x2d, 0x95, 0x8f, 0xa5, 0x0d, 0x93, 0xfb, 0xce, 0xbc, 0xf2,
+	0xca, 0x96, 0x5a, 0xab, 0x5e, 0x6a, 0x7e, 0x01, 0x3b, 0xd6, 0xcc, 0x09, 0x15, 0x92, 0xd3, 0x69,
+	0x5e, 0x56, 0xe6, 0x7b, 0x83, 0xf0, 0xea, 0x41, 0xac, 0x18, 0x18, 0x41, 0x67, 0xaa, 0x1d, 0x35,
+	0x53, 0xba, 0x56, 0x4d, 0x2b, 0x61, 0x04, 0x25, 0xeb, 0xe1, 0x25, 0xb8, 0xb5, 0xb6, 0x40, 0xb7,
+	0xe0, 0xc6, 0xe4, 0x55, 0x78, 0x32, 0x0e, 0x4e, 0x8f, 0x27, 0xe3, 0xe7, 0xe7, 0xe1, 0xf9, 0xf3,
+	0xf3, 0xd3, 0xc1, 0x27, 0x68, 0x07, 0xb6, 0x57, 0xe0, 0xf1, 0xf9, 0xc0, 0x41, 0x37, 0x61, 0xb0,
+	0x02, 0x3e, 0x7f, 0x39, 0x19, 0x34, 0xfe, 0xa3, 0xe1, 0xe2, 0xf4, 0xd9, 0x8f, 0x83, 0xe6, 0xd1,
+	0x93, 0x9f, 0xbf, 0x9f, 0x51, 0x39, 0xcf, 0xa7, 0x07, 0x51, 0xba, 0x30, 0x2f, 0x6e, 0xfd, 0x28,
+	0x19, 0x55, 0x4f, 0xe5, 0xf5, 0xaf, 0xf2, 0xa9, 0x79, 0x8b, 0x3f, 0xfe, 0x77, 0x00, 0x14, 0xfb,
+	0xf8, 0xb3, 0xba, 0x0b, 0x00, 0x00,
 }
@@ -64,6 +64,10 @@ func (c *FakeKContext) RWSet() *contract.RWSet {
 
 func (c *FakeKContext) AddEvent(events ...*protos.ContractEvent) {}
 
+func (c *FakeKContext) Events() []*protos.ContractEvent {
+	return nil
+}
+
 func (c *FakeKContext) Flush() error {
 	return nil
 }
@@ -118,6 +122,9 @@ func (c *FakeKContext) UTXORWSet() *contract.UTXORWSet {
 func (c *FakeKContext) Transfer(from string, to string, amount *big.Int) error {
 	return nil
 }
+func (c *FakeKContext) GetBalance(addr string, frozen bool) (*big.Int, error) {
+	return new(big.Int), nil
+}
 func (c *FakeKContext) QueryBlock(blockid []byte) (*xldgpb.InternalBlock, error) {
 	return &xldgpb.InternalBlock{}, nil
 }
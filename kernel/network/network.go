@@ -74,6 +74,12 @@ type Network interface {
 	PeerInfo() pb.PeerInfo
 }
 
+// MessageAcker 网络可选实现的接口，通过channel订阅消息的模块处理完一条消息后回调Ack，
+// 便于需要感知消息处理进度的网络实现(如仿真网络)判断消息是否已经处理完毕
+type MessageAcker interface {
+	Ack(*pb.XuperMessage)
+}
+
 // 如果有领域内公共逻辑，可以在这层扩展，对上层暴露高级接口
 // 暂时没有特殊的逻辑，先简单透传，预留方便后续扩展
 type NetworkImpl struct {
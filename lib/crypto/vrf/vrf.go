@@ -0,0 +1,194 @@
+// Package vrf 基于椭圆曲线的可验证随机函数(VRF)
+// 结构参照ECVRF(RFC 9381)，适用于a=-3的短Weierstrass曲线，包括NIST P-256和国密SM2曲线，
+// 私钥持有者对输入alpha计算出唯一的随机输出beta和证明proof，任何人可以用公钥验证beta确实由该私钥生成
+package vrf
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+var (
+	ErrInvalidKey   = errors.New("vrf: invalid key")
+	ErrInvalidProof = errors.New("vrf: invalid proof")
+	ErrHashToCurve  = errors.New("vrf: failed to hash to curve")
+)
+
+// 哈希的域分隔前缀
+const (
+	hashToCurvePrefix = 0x01
+	challengePrefix   = 0x02
+	outputPrefix      = 0x03
+	noncePrefix       = 0x04
+)
+
+// Prove 使用私钥sk对输入alpha计算随机输出beta和证明proof
+// proof的格式为 Gamma(非压缩点) || c || s，c和s按曲线阶的字节长度补齐
+func Prove(sk *ecdsa.PrivateKey, alpha []byte) (beta, proof []byte, err error) {
+	if sk == nil || sk.D == nil || sk.Curve == nil {
+		return nil, nil, ErrInvalidKey
+	}
+	curve := sk.Curve
+	params := curve.Params()
+	hx, hy, err := hashToCurve(curve, &sk.PublicKey, alpha)
+	if err != nil {
+		return nil, nil, err
+	}
+	skBytes := padScalar(params, sk.D)
+	gx, gy := curve.ScalarMult(hx, hy, skBytes)
+
+	k := nonce(params, skBytes, elliptic.Marshal(curve, hx, hy))
+	kBytes := padScalar(params, k)
+	ux, uy := curve.ScalarBaseMult(kBytes)
+	vx, vy := curve.ScalarMult(hx, hy, kBytes)
+	c := challenge(curve, hx, hy, gx, gy, ux, uy, vx, vy)
+
+	// s = k - c*sk mod N
+	s := new(big.Int).Mul(c, sk.D)
+	s.Sub(k, s)
+	s.Mod(s, params.N)
+
+	gamma := elliptic.Marshal(curve, gx, gy)
+	proof = make([]byte, 0, len(gamma)+2*scalarLen(params))
+	proof = append(proof, gamma...)
+	proof = append(proof, padScalar(params, c)...)
+	proof = append(proof, padScalar(params, s)...)
+	return gammaToHash(gamma), proof, nil
+}
+
+// Verify 使用公钥pk验证proof是否为alpha的合法证明，验证通过时返回随机输出beta
+func Verify(pk *ecdsa.PublicKey, alpha, proof []byte) ([]byte, error) {
+	if pk == nil || pk.Curve == nil || pk.X == nil || pk.Y == nil || !pk.Curve.IsOnCurve(pk.X, pk.Y) {
+		return nil, ErrInvalidKey
+	}
+	curve := pk.Curve
+	params := curve.Params()
+	gamma, c, s, err := decodeProof(params, proof)
+	if err != nil {
+		return nil, err
+	}
+	gx, gy := elliptic.Unmarshal(curve, gamma)
+	if gx == nil {
+		return nil, ErrInvalidProof
+	}
+	hx, hy, err := hashToCurve(curve, pk, alpha)
+	if err != nil {
+		return nil, err
+	}
+	cBytes, sBytes := padScalar(params, c), padScalar(params, s)
+	// U = s*G + c*pk, V = s*H + c*Gamma
+	x1, y1 := curve.ScalarBaseMult(sBytes)
+	x2, y2 := curve.ScalarMult(pk.X, pk.Y, cBytes)
+	ux, uy := curve.Add(x1, y1, x2, y2)
+	x1, y1 = curve.ScalarMult(hx, hy, sBytes)
+	x2, y2 = curve.ScalarMult(gx, gy, cBytes)
+	vx, vy := curve.Add(x1, y1, x2, y2)
+	if challenge(curve, hx, hy, gx, gy, ux, uy, vx, vy).Cmp(c) != 0 {
+		return nil, ErrInvalidProof
+	}
+	return gammaToHash(gamma), nil
+}
+
+// ProofToHash 从proof中直接计算随机输出，不做验证，仅用于已验证过的proof
+func ProofToHash(curve elliptic.Curve, proof []byte) ([]byte, error) {
+	gamma, _, _, err := decodeProof(curve.Params(), proof)
+	if err != nil {
+		return nil, err
+	}
+	return gammaToHash(gamma), nil
+}
+
+// hashToCurve 采用try-and-increment方式将公钥和alpha映射到曲线上的点，y取偶数
+func hashToCurve(curve elliptic.Curve, pk *ecdsa.PublicKey, alpha []byte) (*big.Int, *big.Int, error) {
+	params := curve.Params()
+	pkBytes := elliptic.Marshal(curve, pk.X, pk.Y)
+	three := big.NewInt(3)
+	for ctr := 0; ctr < 256; ctr++ {
+		h := sha256.New()
+		h.Write([]byte{hashToCurvePrefix, byte(ctr)})
+		h.Write(pkBytes)
+		h.Write(alpha)
+		x := new(big.Int).SetBytes(h.Sum(nil))
+		if x.Cmp(params.P) >= 0 {
+			continue
+		}
+		// y^2 = x^3 - 3x + b
+		rhs := new(big.Int).Mul(x, x)
+		rhs.Mul(rhs, x)
+		rhs.Sub(rhs, new(big.Int).Mul(three, x))
+		rhs.Add(rhs, params.B)
+		rhs.Mod(rhs, params.P)
+		y := new(big.Int).ModSqrt(rhs, params.P)
+		if y == nil {
+			continue
+		}
+		if y.Bit(0) == 1 {
+			y.Sub(params.P, y)
+		}
+		if curve.IsOnCurve(x, y) {
+			return x, y, nil
+		}
+	}
+	return nil, nil, ErrHashToCurve
+}
+
+// nonce 由私钥和H点确定性地生成随机数k，避免依赖外部随机源
+func nonce(params *elliptic.CurveParams, skBytes, hBytes []byte) *big.Int {
+	for ctr := 0; ; ctr++ {
+		h := sha256.New()
+		h.Write([]byte{noncePrefix, byte(ctr)})
+		h.Write(skBytes)
+		h.Write(hBytes)
+		k := new(big.Int).SetBytes(h.Sum(nil))
+		k.Mod(k, params.N)
+		if k.Sign() != 0 {
+			return k
+		}
+	}
+}
+
+// challenge 计算c = H(G, H, Gamma, U, V) mod N
+func challenge(curve elliptic.Curve, points ...*big.Int) *big.Int {
+	params := curve.Params()
+	h := sha256.New()
+	h.Write([]byte{challengePrefix})
+	h.Write(elliptic.Marshal(curve, params.Gx, params.Gy))
+	for i := 0; i+1 < len(points); i += 2 {
+		h.Write(elliptic.Marshal(curve, points[i], points[i+1]))
+	}
+	c := new(big.Int).SetBytes(h.Sum(nil))
+	return c.Mod(c, params.N)
+}
+
+func gammaToHash(gamma []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{outputPrefix})
+	h.Write(gamma)
+	return h.Sum(nil)
+}
+
+func decodeProof(params *elliptic.CurveParams, proof []byte) ([]byte, *big.Int, *big.Int, error) {
+	pointLen := 1 + 2*((params.BitSize+7)/8)
+	sLen := scalarLen(params)
+	if len(proof) != pointLen+2*sLen {
+		return nil, nil, nil, ErrInvalidProof
+	}
+	c := new(big.Int).SetBytes(proof[pointLen : pointLen+sLen])
+	s := new(big.Int).SetBytes(proof[pointLen+sLen:])
+	if c.Cmp(params.N) >= 0 || s.Cmp(params.N) >= 0 {
+		return nil, nil, nil, ErrInvalidProof
+	}
+	return proof[:pointLen], c, s, nil
+}
+
+func scalarLen(params *elliptic.CurveParams) int {
+	return (params.N.BitLen() + 7) / 8
+}
+
+func padScalar(params *elliptic.CurveParams, v *big.Int) []byte {
+	out := make([]byte, scalarLen(params))
+	return v.FillBytes(out)
+}
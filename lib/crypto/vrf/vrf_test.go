@@ -0,0 +1,78 @@
+package vrf
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/xuperchain/xupercore/lib/crypto/client/gm"
+)
+
+func testKeys(t *testing.T) map[string]*ecdsa.PrivateKey {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm2, err := gm.GetInstance().GenerateKeyBySeed([]byte("this is a vrf test seed for sm2 key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]*ecdsa.PrivateKey{"p256": p256, "sm2": sm2}
+}
+
+func TestProveAndVerify(t *testing.T) {
+	for name, sk := range testKeys(t) {
+		alpha := []byte("block seed")
+		beta, proof, err := Prove(sk, alpha)
+		if err != nil {
+			t.Fatalf("%s: prove failed, err:%v", name, err)
+		}
+		// 同一输入的输出是确定的
+		beta2, proof2, _ := Prove(sk, alpha)
+		if !bytes.Equal(beta, beta2) || !bytes.Equal(proof, proof2) {
+			t.Fatalf("%s: vrf should be deterministic", name)
+		}
+		out, err := Verify(&sk.PublicKey, alpha, proof)
+		if err != nil || !bytes.Equal(out, beta) {
+			t.Fatalf("%s: verify failed, err:%v", name, err)
+		}
+		if out, _ := ProofToHash(sk.Curve, proof); !bytes.Equal(out, beta) {
+			t.Fatalf("%s: proof to hash mismatch", name)
+		}
+
+		// 不同输入的输出不同，且证明不能用于其他输入
+		other, _, _ := Prove(sk, []byte("other seed"))
+		if bytes.Equal(other, beta) {
+			t.Fatalf("%s: different alpha should have different output", name)
+		}
+		if _, err := Verify(&sk.PublicKey, []byte("other seed"), proof); err != ErrInvalidProof {
+			t.Fatalf("%s: proof should not verify with other alpha, err:%v", name, err)
+		}
+		// 篡改的证明无法通过验证
+		bad := append([]byte(nil), proof...)
+		bad[len(bad)-1] ^= 0x01
+		if _, err := Verify(&sk.PublicKey, alpha, bad); err != ErrInvalidProof {
+			t.Fatalf("%s: tampered proof should be rejected, err:%v", name, err)
+		}
+		if _, err := Verify(&sk.PublicKey, alpha, proof[1:]); err != ErrInvalidProof {
+			t.Fatalf("%s: truncated proof should be rejected, err:%v", name, err)
+		}
+	}
+}
+
+func TestVerifyWrongKey(t *testing.T) {
+	sk, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, proof, err := Prove(sk, []byte("alpha"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(&other.PublicKey, []byte("alpha"), proof); err != ErrInvalidProof {
+		t.Fatalf("proof should not verify with other key, err:%v", err)
+	}
+	if _, err := Verify(nil, []byte("alpha"), proof); err != ErrInvalidKey {
+		t.Fatalf("nil key should be rejected, err:%v", err)
+	}
+}
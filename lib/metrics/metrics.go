@@ -10,6 +10,7 @@ const (
 	SubsystemLedger   = "ledger"
 	SubsystemState    = "state"
 	SubsystemNetwork  = "network"
+	SubsystemStorage  = "storage"
 
 	LabelBCName      = "bcname"
 	LabelMessageType = "message"
@@ -22,10 +23,18 @@ const (
 
 	LabelModule = "module"
 	LabelHandle = "handle"
+
+	LabelStoragePath = "path"
+
+	LabelCacheResult = "result"
+	LabelCacheTier   = "tier"
 )
 
 var DefBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5}
 
+// 合约编译耗时较长，单独设置分桶
+var CompileBuckets = []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120}
+
 // common
 var (
 	// 并发请求量
@@ -85,6 +94,73 @@ var (
 			Buckets:   DefBuckets,
 		},
 		[]string{LabelBCName, LabelContractModuleName, LabelContractName, LabelContractMethod})
+	// 合约代码缓存
+	ContractCodeCacheCounter = prom.NewCounterVec(
+		prom.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name:      "code_cache_total",
+			Help:      "Total number of contract code cache lookups.",
+		},
+		[]string{LabelModule, LabelCacheResult})
+	ContractCodeCacheGauge = prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name:      "code_cache_entries",
+			Help:      "Total number of cached contract codes.",
+		},
+		[]string{LabelModule, LabelCacheTier})
+	ContractCodeCacheBytesGauge = prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name:      "code_cache_bytes",
+			Help:      "Total size of contract code disk cache.",
+		},
+		[]string{LabelModule})
+	ContractCodeCompileCounter = prom.NewCounterVec(
+		prom.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name:      "code_compile_total",
+			Help:      "Total number of contract code compilations.",
+		},
+		[]string{LabelModule, LabelErrorCode})
+	ContractCodeCompileHistogram = prom.NewHistogramVec(
+		prom.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name:      "code_compile_seconds",
+			Help:      "Histogram of contract code compilation latency.",
+			Buckets:   CompileBuckets,
+		},
+		[]string{LabelModule})
+	ContractCodeTierUpCounter = prom.NewCounterVec(
+		prom.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name:      "code_tier_up_total",
+			Help:      "Total number of contract code tier up.",
+		},
+		[]string{LabelCacheTier, LabelErrorCode})
+	ContractNativeCpuHistogram = prom.NewHistogramVec(
+		prom.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name:      "native_cpu_seconds",
+			Help:      "Histogram of cpu time measured for native contract calls.",
+			Buckets:   DefBuckets,
+		},
+		[]string{LabelContractName})
+	ContractNativeMemoryGauge = prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name:      "native_memory_bytes",
+			Help:      "Memory measured for native contract processes.",
+		},
+		[]string{LabelContractName})
 )
 
 // ledger
@@ -182,6 +258,34 @@ var (
 		[]string{LabelBCName, LabelMessageType})
 )
 
+// storage
+var (
+	StorageTierFilesGauge = prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemStorage,
+			Name:      "tier_files",
+			Help:      "Total number of table files placed on storage path.",
+		},
+		[]string{LabelStoragePath})
+	StorageTierBytesGauge = prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemStorage,
+			Name:      "tier_bytes",
+			Help:      "Total size of table files placed on storage path.",
+		},
+		[]string{LabelStoragePath})
+	StorageTierMigrateCounter = prom.NewCounterVec(
+		prom.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemStorage,
+			Name:      "tier_migrate_total",
+			Help:      "Total number of table files migrated to storage path.",
+		},
+		[]string{LabelStoragePath, LabelErrorCode})
+)
+
 func RegisterMetrics() {
 	// common
 	prom.MustRegister(BytesCounter)
@@ -191,6 +295,14 @@ func RegisterMetrics() {
 	// contract
 	prom.MustRegister(ContractInvokeCounter)
 	prom.MustRegister(ContractInvokeHistogram)
+	prom.MustRegister(ContractCodeCacheCounter)
+	prom.MustRegister(ContractCodeCacheGauge)
+	prom.MustRegister(ContractCodeCacheBytesGauge)
+	prom.MustRegister(ContractCodeCompileCounter)
+	prom.MustRegister(ContractCodeCompileHistogram)
+	prom.MustRegister(ContractCodeTierUpCounter)
+	prom.MustRegister(ContractNativeCpuHistogram)
+	prom.MustRegister(ContractNativeMemoryGauge)
 	// ledger
 	prom.MustRegister(LedgerConfirmTxCounter)
 	prom.MustRegister(LedgerSwitchBranchCounter)
@@ -204,4 +316,8 @@ func RegisterMetrics() {
 	prom.MustRegister(NetworkMsgReceivedCounter)
 	prom.MustRegister(NetworkMsgReceivedBytesCounter)
 	prom.MustRegister(NetworkServerHandlingHistogram)
+	// storage
+	prom.MustRegister(StorageTierFilesGauge)
+	prom.MustRegister(StorageTierBytesGauge)
+	prom.MustRegister(StorageTierMigrateCounter)
 }
@@ -1,6 +1,7 @@
 package config
 
 type CloudStorageConfig struct {
+	Backend       string `yaml:"backend"`       //object store backend: s3, local or memory
 	Bucket        string `yaml:"bucket"`        //bucket name of s3 or bos
 	Path          string `yaml:"path"`          //path in the bucket
 	Ak            string `yaml:"ak"`            //access key
@@ -8,6 +9,8 @@ type CloudStorageConfig struct {
 	Region        string `yaml:"region"`        //region, eg. bj
 	Endpoint      string `yaml:"endpoint"`      //endpoint, eg. s3.bj.bcebos.com
 	LocalCacheDir string `yaml:"localCacheDir"` //cache directory on local disk
+	//root directory of objects, only used by local backend
+	LocalObjectDir string `yaml:"localObjectDir"`
 }
 
 func NewCloudStorageConfig() *CloudStorageConfig {
@@ -17,6 +20,7 @@ func NewCloudStorageConfig() *CloudStorageConfig {
 }
 
 func (c *CloudStorageConfig) defaultCloudStorageConfig() {
+	c.Backend = "s3"
 	c.Bucket = "xchain-cloud-test"
 	c.Path = "node1"
 	c.Ak = ""
@@ -24,4 +28,29 @@ func (c *CloudStorageConfig) defaultCloudStorageConfig() {
 	c.Region = "bj"
 	c.Endpoint = "s3.bj.bcebos.com"
 	c.LocalCacheDir = "./data/cache"
+	c.LocalObjectDir = "./data/objects"
+}
+
+// TieringConfig places table files of multi disks storage by data age,
+// new table files are written to the fast disk and cold ones are migrated to tiers
+type TieringConfig struct {
+	Enable       bool         `yaml:"enable"`
+	Tiers        []TierConfig `yaml:"tiers"`        //slow or archival disks, in order of preference
+	ColdPrefixes []string     `yaml:"coldPrefixes"` //key prefixes of cold data, eg. blocks and confirmed txs
+	MinAge       int64        `yaml:"minAge"`       //min age in seconds of table files to be migrated
+	HotWatermark float64      `yaml:"hotWatermark"` //used ratio of the fast disk beyond which cold files are migrated regardless of age
+	ScanInterval int64        `yaml:"scanInterval"` //interval in seconds of background migration
+}
+
+type TierConfig struct {
+	Path      string  `yaml:"path"`
+	Watermark float64 `yaml:"watermark"` //max used ratio of the disk, eg. 0.9
+}
+
+func NewTieringConfig() *TieringConfig {
+	return &TieringConfig{
+		MinAge:       3600,
+		HotWatermark: 0.9,
+		ScanInterval: 60,
+	}
 }
@@ -17,15 +17,20 @@ func (ldb *LDBDatabase) OpenCloud(path string, options map[string]interface{}) e
 	cache := options["cache"].(int)
 	fds := options["fds"].(int)
 	cfg := config.NewCloudStorageConfig()
+	if c, ok := options["cloudStorage"].(*config.CloudStorageConfig); ok && c != nil {
+		cfg = c
+	}
 	//cloud storage
 	s3opt := levels3.OpenOption{
-		Bucket:        cfg.Bucket,
-		Path:          pt.Join(cfg.Path, path),
-		Ak:            cfg.Ak,
-		Sk:            cfg.Sk,
-		Region:        cfg.Region,
-		Endpoint:      cfg.Endpoint,
-		LocalCacheDir: cfg.LocalCacheDir,
+		Backend:        cfg.Backend,
+		Bucket:         cfg.Bucket,
+		Path:           pt.Join(cfg.Path, path),
+		Ak:             cfg.Ak,
+		Sk:             cfg.Sk,
+		Region:         cfg.Region,
+		Endpoint:       cfg.Endpoint,
+		LocalCacheDir:  cfg.LocalCacheDir,
+		LocalObjectDir: cfg.LocalObjectDir,
 	}
 	st, err := levels3.NewS3Storage(s3opt)
 	if err != nil {
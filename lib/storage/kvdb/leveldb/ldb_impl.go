@@ -17,12 +17,19 @@ type LDBDatabase struct {
 // GetInstance get instance of LDBDatabase
 func NewKVDBInstance(param *kvdb.KVParameter) (kvdb.Database, error) {
 	baseDB := new(LDBDatabase)
-	err := baseDB.Open(param.GetDBPath(), map[string]interface{}{
+	options := map[string]interface{}{
 		"cache":       param.GetMemCacheSize(),
 		"fds":         param.GetFileHandlersCacheSize(),
 		"dataPaths":   param.GetOtherPaths(),
 		"storageType": param.GetStorageType(),
-	})
+	}
+	// engine specific options, eg. cloudStorage
+	for k, v := range param.Options {
+		if _, ok := options[k]; !ok {
+			options[k] = v
+		}
+	}
+	err := baseDB.Open(param.GetDBPath(), options)
 	if err != nil {
 		return nil, err
 	}
@@ -1,9 +1,13 @@
 package leveldb
 
 import (
-	"github.com/xuperchain/xupercore/lib/storage/kvdb"
 	"math/rand"
+	"os"
 	"testing"
+
+	"github.com/xuperchain/xupercore/lib/storage/config"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/lib/storage/s3"
 )
 
 const (
@@ -175,3 +179,37 @@ func BenchmarkLdbBatch_ParallelGetNotExist(b *testing.B) {
 		}
 	})
 }
+
+func TestOpenCloudMemory(t *testing.T) {
+	defer levels3.ResetMemObjects()
+	cacheDir, err := os.MkdirTemp("", "ldb_cloud")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+	cfg := config.NewCloudStorageConfig()
+	cfg.Backend = levels3.ObjectStoreMemory
+	cfg.LocalCacheDir = cacheDir
+	kvParam := &kvdb.KVParameter{
+		DBPath:                "ledger",
+		KVEngineType:          "leveldb",
+		StorageType:           kvdb.StorageTypeCloud,
+		MemCacheSize:          16,
+		FileHandlersCacheSize: 16,
+		Options: map[string]interface{}{
+			"cloudStorage": cfg,
+		},
+	}
+	db, err := NewKVDBInstance(kvParam)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	value, err := db.Get([]byte("key"))
+	if err != nil || string(value) != "value" {
+		t.Fatal("get value from cloud storage failed", err)
+	}
+}
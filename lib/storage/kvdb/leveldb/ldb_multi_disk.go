@@ -1,10 +1,14 @@
 package leveldb
 
 import (
+	"time"
+
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/xuperchain/xupercore/lib/storage/config"
 	"github.com/xuperchain/xupercore/lib/storage/mstorage"
 )
 
@@ -14,9 +18,13 @@ func (ldb *LDBDatabase) OpenMulti(path string, options map[string]interface{}) e
 	cache := options["cache"].(int)
 	fds := options["fds"].(int)
 	dataPaths := options["dataPaths"].([]string)
+	tiering, _ := options["tiering"].(*config.TieringConfig)
+	if tiering != nil && !tiering.Enable {
+		tiering = nil
+	}
 
 	// Open the db and recover any potential corruptions
-	if dataPaths == nil || len(dataPaths) == 0 {
+	if (dataPaths == nil || len(dataPaths) == 0) && tiering == nil {
 		db, err := leveldb.OpenFile(path, &opt.Options{
 			OpenFilesCacheCapacity: fds,
 			BlockCacheCapacity:     cache / 2 * opt.MiB,
@@ -37,7 +45,13 @@ func (ldb *LDBDatabase) OpenMulti(path string, options map[string]interface{}) e
 		return nil
 	}
 	//多盘存储初始化
-	store, err := mstorage.OpenFile(path, false, dataPaths)
+	var store storage.Storage
+	var err error
+	if tiering != nil {
+		store, err = mstorage.OpenTieredFile(path, false, tieringPolicy(tiering))
+	} else {
+		store, err = mstorage.OpenFile(path, false, dataPaths)
+	}
 	if err != nil {
 		return err
 	}
@@ -59,3 +73,18 @@ func (ldb *LDBDatabase) OpenMulti(path string, options map[string]interface{}) e
 	ldb.db = db
 	return nil
 }
+
+func tieringPolicy(cfg *config.TieringConfig) *mstorage.TieringPolicy {
+	policy := &mstorage.TieringPolicy{
+		MinAge:       time.Duration(cfg.MinAge) * time.Second,
+		HotWatermark: cfg.HotWatermark,
+		ScanInterval: time.Duration(cfg.ScanInterval) * time.Second,
+	}
+	for _, tier := range cfg.Tiers {
+		policy.Tiers = append(policy.Tiers, mstorage.TierPath{Path: tier.Path, Watermark: tier.Watermark})
+	}
+	for _, prefix := range cfg.ColdPrefixes {
+		policy.ColdPrefixes = append(policy.ColdPrefixes, []byte(prefix))
+	}
+	return policy
+}
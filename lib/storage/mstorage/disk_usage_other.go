@@ -0,0 +1,9 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package mstorage
+
+// diskUsage is not supported on this platform, watermarks never take effect
+func diskUsage(path string) (float64, error) {
+	return 0, nil
+}
@@ -104,6 +104,12 @@ type MultiDiskStorage struct {
 	// Opened file counter; if open < 0 means closed.
 	open int
 	day  int
+
+	// tiering placement, nil if placed by file number
+	tiering   *TieringPolicy
+	locations map[int64]string
+	stopC     chan struct{}
+	doneC     chan struct{}
 }
 
 // OpenFile returns instance of MultiDiskStorage supporting multi disks
@@ -597,6 +603,15 @@ func (fs *MultiDiskStorage) Rename(oldfd, newfd storage.FileDesc) error {
 
 // Close close the instance of MultiDiskStorage
 func (fs *MultiDiskStorage) Close() error {
+	// stop tiering migration before closing
+	fs.mu.Lock()
+	stopC := fs.stopC
+	fs.stopC = nil
+	fs.mu.Unlock()
+	if stopC != nil {
+		close(stopC)
+		<-fs.doneC
+	}
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 	if fs.open < 0 {
@@ -714,6 +729,9 @@ func fsParseNamePtr(name string, fd *storage.FileDesc) bool {
 func (fs *MultiDiskStorage) getRealPath(name string) string {
 	var fdNum uint64
 	fmt.Sscanf(filepath.Base(name), "%d.ldb", &fdNum)
+	if fs.tiering != nil {
+		return fs.tieredRealPath(int64(fdNum))
+	}
 	N := uint64(len(fs.dataPaths))
 	return filepath.Join(fs.dataPaths[fdNum%N], fmt.Sprintf(sstFormat, fdNum))
 }
@@ -800,9 +818,14 @@ func (fs *MultiDiskStorage) MultiRemove(name string) error {
 		realName := fs.getRealPath(name)
 		removeErr := os.Remove(realName)
 		if removeErr != nil {
-			return fs.MultiGuessRemove(name)
+			removeErr = fs.MultiGuessRemove(name)
 		}
-		return nil
+		if removeErr == nil && fs.tiering != nil {
+			var fdNum int64
+			fmt.Sscanf(filepath.Base(name), "%d.ldb", &fdNum)
+			delete(fs.locations, fdNum)
+		}
+		return removeErr
 	}
 	return os.Remove(name)
 }
@@ -0,0 +1,351 @@
+package mstorage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	pt "path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/table"
+	"github.com/xuperchain/xupercore/lib/metrics"
+)
+
+const (
+	migratingSuffix = ".migrating"
+
+	defaultWatermark    = 0.9
+	defaultScanInterval = time.Minute
+)
+
+// TierPath is a slow or archival disk used by tiered storage
+type TierPath struct {
+	Path string
+	// max used ratio of the disk, files will not be migrated to it beyond the watermark
+	Watermark float64
+}
+
+// TieringPolicy decides where table files of a tiered storage are placed.
+// New table files are always created on the fast disk (the db path), table files
+// whose key range only holds ColdPrefixes and are older than MinAge are migrated
+// to the first tier under its watermark in the background.
+type TieringPolicy struct {
+	Tiers []TierPath
+	// a table file is cold only if all of its keys have one of the prefixes,
+	// eg. blocks and confirmed txs of ledger, empty means all files
+	ColdPrefixes [][]byte
+	// min age of table files to be migrated
+	MinAge time.Duration
+	// when the fast disk is beyond the watermark, cold files are migrated regardless of MinAge
+	HotWatermark float64
+	ScanInterval time.Duration
+}
+
+// OpenTieredFile returns instance of MultiDiskStorage placing table files by policy
+func OpenTieredFile(path string, readOnly bool, policy *TieringPolicy) (storage.Storage, error) {
+	if policy == nil || len(policy.Tiers) == 0 {
+		return nil, fmt.Errorf("leveldb/mstorage: tiering policy without tier path")
+	}
+	dataPaths := make([]string, 0, len(policy.Tiers))
+	for _, tier := range policy.Tiers {
+		dataPaths = append(dataPaths, tier.Path)
+	}
+	st, err := OpenFile(path, readOnly, dataPaths)
+	if err != nil {
+		return nil, err
+	}
+	fs := st.(*MultiDiskStorage)
+	p := *policy
+	if p.ScanInterval <= 0 {
+		p.ScanInterval = defaultScanInterval
+	}
+	if p.HotWatermark <= 0 {
+		p.HotWatermark = defaultWatermark
+	}
+	p.Tiers = make([]TierPath, len(policy.Tiers))
+	for i, tier := range policy.Tiers {
+		p.Tiers[i] = TierPath{Path: fs.dataPaths[i], Watermark: tier.Watermark}
+		if p.Tiers[i].Watermark <= 0 {
+			p.Tiers[i].Watermark = defaultWatermark
+		}
+	}
+	fs.tiering = &p
+	fs.locations = make(map[int64]string)
+	for _, dir := range fs.expandDataPaths(filepath.Join(fs.path, "CURRENT")) {
+		names, err := readDirNames(dir)
+		if err != nil {
+			fs.Close()
+			return nil, err
+		}
+		for _, name := range names {
+			if strings.HasSuffix(name, migratingSuffix) {
+				// leftover of an interrupted migration
+				os.Remove(filepath.Join(dir, name))
+				continue
+			}
+			// table files on the fast disk are located by default and stay candidates of migration
+			if fd, ok := fsParseName(name); ok && fd.Type == storage.TypeTable && dir != fs.path {
+				fs.locations[fd.Num] = dir
+			}
+		}
+	}
+	for num := range fs.locations {
+		// leftover of a migration interrupted after the tier copy was renamed in place
+		os.Remove(filepath.Join(fs.path, fmt.Sprintf(sstFormat, num)))
+	}
+	fs.updatePlacementMetrics()
+	if !readOnly {
+		fs.stopC = make(chan struct{})
+		fs.doneC = make(chan struct{})
+		go fs.migrateLoop(fs.stopC)
+	}
+	return fs, nil
+}
+
+func readDirNames(path string) ([]string, error) {
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	return dir.Readdirnames(0)
+}
+
+// tieredRealPath returns the location of the table file, new table files go to the fast disk
+func (fs *MultiDiskStorage) tieredRealPath(num int64) string {
+	dir, ok := fs.locations[num]
+	if !ok {
+		dir = fs.path
+	}
+	return filepath.Join(dir, fmt.Sprintf(sstFormat, num))
+}
+
+func (fs *MultiDiskStorage) migrateLoop(stopC <-chan struct{}) {
+	defer close(fs.doneC)
+	ticker := time.NewTicker(fs.tiering.ScanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopC:
+			return
+		case <-ticker.C:
+			fs.migrateOnce()
+		}
+	}
+}
+
+type hotTable struct {
+	num  int64
+	path string
+	info os.FileInfo
+}
+
+// migrateOnce moves cold table files on the fast disk to tiers, oldest first
+func (fs *MultiDiskStorage) migrateOnce() {
+	names, err := readDirNames(fs.path)
+	if err != nil {
+		fs.Log(fmt.Sprintf("tiering: read dir: %v", err))
+		return
+	}
+	var tables []hotTable
+	for _, name := range names {
+		fd, ok := fsParseName(name)
+		if !ok || fd.Type != storage.TypeTable {
+			continue
+		}
+		fullName := filepath.Join(fs.path, name)
+		info, err := os.Stat(fullName)
+		if err != nil {
+			continue
+		}
+		tables = append(tables, hotTable{num: fd.Num, path: fullName, info: info})
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].info.ModTime().Before(tables[j].info.ModTime())
+	})
+
+	hotUsage, _ := diskUsage(fs.path)
+	overWatermark := hotUsage >= fs.tiering.HotWatermark
+	for _, t := range tables {
+		if fs.isClosed() {
+			return
+		}
+		if !overWatermark && time.Since(t.info.ModTime()) < fs.tiering.MinAge {
+			continue
+		}
+		if !fs.isColdTable(t) {
+			continue
+		}
+		tier, ok := fs.pickTier()
+		if !ok {
+			fs.Log("tiering: all tiers beyond watermark")
+			return
+		}
+		err := fs.moveTable(t, tier)
+		code := "OK"
+		if err != nil {
+			code = "Error"
+			fs.Log(fmt.Sprintf("tiering: migrate %s to %s: %v", t.path, tier, err))
+		}
+		metrics.StorageTierMigrateCounter.WithLabelValues(tier, code).Inc()
+	}
+	fs.updatePlacementMetrics()
+}
+
+func (fs *MultiDiskStorage) isClosed() bool {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.open < 0
+}
+
+// isColdTable checks whether all keys of the table file have one of the cold prefixes
+func (fs *MultiDiskStorage) isColdTable(t hotTable) bool {
+	if len(fs.tiering.ColdPrefixes) == 0 {
+		return true
+	}
+	minKey, maxKey, err := tableKeyRange(t.path, t.num, t.info.Size())
+	if err != nil {
+		return false
+	}
+	for _, prefix := range fs.tiering.ColdPrefixes {
+		if bytes.HasPrefix(minKey, prefix) && bytes.HasPrefix(maxKey, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// tableKeyRange returns the first and last user key of a table file
+func tableKeyRange(path string, num int64, size int64) ([]byte, []byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	fd := storage.FileDesc{Type: storage.TypeTable, Num: num}
+	reader, err := table.NewReader(f, size, fd, nil, nil, &opt.Options{})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer reader.Release()
+	iter := reader.NewIterator(nil, nil)
+	defer iter.Release()
+	if !iter.First() {
+		return nil, nil, fmt.Errorf("empty table")
+	}
+	minKey := userKey(iter.Key())
+	if !iter.Last() {
+		return nil, nil, fmt.Errorf("empty table")
+	}
+	maxKey := userKey(iter.Key())
+	return minKey, maxKey, iter.Error()
+}
+
+// userKey strips the sequence and type suffix of leveldb internal key
+func userKey(ikey []byte) []byte {
+	if len(ikey) < 8 {
+		return nil
+	}
+	return append([]byte{}, ikey[:len(ikey)-8]...)
+}
+
+func (fs *MultiDiskStorage) pickTier() (string, bool) {
+	for _, tier := range fs.tiering.Tiers {
+		usage, err := diskUsage(tier.Path)
+		if err != nil {
+			fs.Log(fmt.Sprintf("tiering: disk usage %s: %v", tier.Path, err))
+			continue
+		}
+		if usage < tier.Watermark {
+			return tier.Path, true
+		}
+	}
+	return "", false
+}
+
+// moveTable copies the table file to tier, then switches its location atomically,
+// readers having the old file opened keep reading it until closed
+func (fs *MultiDiskStorage) moveTable(t hotTable, tier string) error {
+	dst := filepath.Join(tier, pt.Base(t.path))
+	tmp := dst + migratingSuffix
+	if err := copyFileSynced(t.path, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.open < 0 {
+		os.Remove(tmp)
+		return storage.ErrClosed
+	}
+	if _, ok := fs.locations[t.num]; ok {
+		// placed on another disk already
+		os.Remove(tmp)
+		return nil
+	}
+	if _, err := os.Stat(t.path); err != nil {
+		// removed by compaction during copying
+		os.Remove(tmp)
+		return nil
+	}
+	if err := rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Remove(t.path); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	fs.locations[t.num] = tier
+	fs.log(fmt.Sprintf("tiering: migrate %s to %s", t.path, tier))
+	return nil
+}
+
+func copyFileSynced(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err == nil {
+		err = out.Sync()
+	}
+	if err1 := out.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+func (fs *MultiDiskStorage) updatePlacementMetrics() {
+	for _, dir := range fs.expandDataPaths(filepath.Join(fs.path, "CURRENT")) {
+		names, err := readDirNames(dir)
+		if err != nil {
+			continue
+		}
+		var files, size int64
+		for _, name := range names {
+			if fd, ok := fsParseName(name); !ok || fd.Type != storage.TypeTable {
+				continue
+			}
+			info, err := os.Stat(filepath.Join(dir, name))
+			if err != nil {
+				continue
+			}
+			files++
+			size += info.Size()
+		}
+		metrics.StorageTierFilesGauge.WithLabelValues(dir).Set(float64(files))
+		metrics.StorageTierBytesGauge.WithLabelValues(dir).Set(float64(size))
+	}
+}
@@ -0,0 +1,166 @@
+package mstorage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+func countTables(t *testing.T, dir string) int {
+	names, err := readDirNames(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cnt := 0
+	for _, name := range names {
+		if strings.HasSuffix(name, ".ldb") {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+func TestTieredStorage(t *testing.T) {
+	root, err := os.MkdirTemp("", "tiering")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	dbPath := filepath.Join(root, "fast", "ledger")
+	policy := &TieringPolicy{
+		Tiers:        []TierPath{{Path: filepath.Join(root, "slow"), Watermark: 1}},
+		ColdPrefixes: [][]byte{[]byte("B")},
+	}
+
+	st, err := OpenTieredFile(dbPath, false, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := leveldb.Open(st, &opt.Options{WriteBuffer: 32 * opt.KiB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := strings.Repeat("x", 512)
+	for i := 0; i < 500; i++ {
+		if err := db.Put([]byte(fmt.Sprintf("B%08d", i)), []byte(value), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.CompactRange(utilRange("B", "C")); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if err := db.Put([]byte(fmt.Sprintf("U%08d", i)), []byte(value), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.CompactRange(utilRange("U", "V")); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := st.(*MultiDiskStorage)
+	fs.migrateOnce()
+	tierDir := fs.tiering.Tiers[0].Path
+	if countTables(t, tierDir) == 0 {
+		t.Fatal("no table migrated to slow disk")
+	}
+	if countTables(t, dbPath) == 0 {
+		t.Fatal("hot table migrated to slow disk")
+	}
+	check := func(db *leveldb.DB) {
+		for _, key := range []string{"B00000000", "B00000499", "U00000050"} {
+			v, err := db.Get([]byte(key), nil)
+			if err != nil || string(v) != value {
+				t.Fatalf("get %s failed: %v", key, err)
+			}
+		}
+	}
+	check(db)
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err = OpenTieredFile(dbPath, false, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err = leveldb.Open(st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	defer db.Close()
+	check(db)
+}
+
+func utilRange(start, limit string) util.Range {
+	return util.Range{Start: []byte(start), Limit: []byte(limit)}
+}
+
+func TestTieredStorageMigrateAfterReopen(t *testing.T) {
+	root, err := os.MkdirTemp("", "tiering")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	dbPath := filepath.Join(root, "fast", "ledger")
+	policy := &TieringPolicy{
+		Tiers: []TierPath{{Path: filepath.Join(root, "slow"), Watermark: 1}},
+	}
+
+	st, err := OpenTieredFile(dbPath, false, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := leveldb.Open(st, &opt.Options{WriteBuffer: 32 * opt.KiB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := strings.Repeat("x", 512)
+	for i := 0; i < 200; i++ {
+		if err := db.Put([]byte(fmt.Sprintf("B%08d", i)), []byte(value), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.CompactRange(utilRange("B", "C")); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	st.Close()
+
+	// tables written before reopening are still migrated
+	st, err = OpenTieredFile(dbPath, false, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	fs := st.(*MultiDiskStorage)
+	if len(fs.locations) != 0 {
+		t.Fatalf("unexpected locations %v", fs.locations)
+	}
+	hot := countTables(t, dbPath)
+	if hot == 0 {
+		t.Fatal("no table on fast disk")
+	}
+	fs.migrateOnce()
+	if countTables(t, dbPath) != 0 || countTables(t, fs.tiering.Tiers[0].Path) != hot {
+		t.Fatal("tables not migrated after reopen")
+	}
+	db, err = leveldb.Open(st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if v, err := db.Get([]byte("B00000199"), nil); err != nil || string(v) != value {
+		t.Fatalf("get after migration failed: %v", err)
+	}
+}
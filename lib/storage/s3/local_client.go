@@ -0,0 +1,66 @@
+package levels3
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+// LocalClient is an ObjectStore which keeps objects under LocalObjectDir/Path
+type LocalClient struct {
+	dir string
+}
+
+var _ ObjectStore = (*LocalClient)(nil)
+
+func GetLocalClient(opt OpenOption) (*LocalClient, error) {
+	if opt.LocalObjectDir == "" {
+		return nil, errors.New("need a local object dir for local object store")
+	}
+	dir := filepath.Join(opt.LocalObjectDir, opt.Path)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalClient{dir: dir}, nil
+}
+
+func (client *LocalClient) PutBytes(key string, data []byte) error {
+	// write to a temp file first, so that readers never see a partial object
+	fullName := filepath.Join(client.dir, key)
+	tmpName := fullName + ".uploading"
+	err := os.WriteFile(tmpName, data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpName, fullName)
+}
+
+func (client *LocalClient) GetBytes(key string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(client.dir, key))
+}
+
+func (client *LocalClient) Remove(key string) error {
+	err := os.Remove(filepath.Join(client.dir, key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (client *LocalClient) List() ([]storage.FileDesc, error) {
+	entries, err := os.ReadDir(client.dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return parseFileDescs(names), nil
+}
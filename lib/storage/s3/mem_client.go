@@ -0,0 +1,85 @@
+package levels3
+
+import (
+	"os"
+	"path"
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+// memBuckets keeps the objects of all MemClient in this process by path,
+// so that a storage can be reopened and see the objects written before
+var (
+	memBucketsMu sync.Mutex
+	memBuckets   = map[string]*memBucket{}
+)
+
+type memBucket struct {
+	mu      sync.RWMutex
+	objects map[string][]byte
+}
+
+// MemClient is an ObjectStore which keeps objects in process memory
+type MemClient struct {
+	bucket *memBucket
+}
+
+var _ ObjectStore = (*MemClient)(nil)
+
+func GetMemClient(opt OpenOption) (*MemClient, error) {
+	name := path.Join(opt.Bucket, opt.Path)
+	memBucketsMu.Lock()
+	defer memBucketsMu.Unlock()
+	bucket, ok := memBuckets[name]
+	if !ok {
+		bucket = &memBucket{objects: map[string][]byte{}}
+		memBuckets[name] = bucket
+	}
+	return &MemClient{bucket: bucket}, nil
+}
+
+// ResetMemObjects drop all objects kept by MemClient
+func ResetMemObjects() {
+	memBucketsMu.Lock()
+	defer memBucketsMu.Unlock()
+	memBuckets = map[string]*memBucket{}
+}
+
+func (client *MemClient) PutBytes(key string, data []byte) error {
+	buf := make([]byte, len(data))
+	copy(buf, data)
+	client.bucket.mu.Lock()
+	defer client.bucket.mu.Unlock()
+	client.bucket.objects[key] = buf
+	return nil
+}
+
+func (client *MemClient) GetBytes(key string) ([]byte, error) {
+	client.bucket.mu.RLock()
+	defer client.bucket.mu.RUnlock()
+	data, ok := client.bucket.objects[key]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	buf := make([]byte, len(data))
+	copy(buf, data)
+	return buf, nil
+}
+
+func (client *MemClient) Remove(key string) error {
+	client.bucket.mu.Lock()
+	defer client.bucket.mu.Unlock()
+	delete(client.bucket.objects, key)
+	return nil
+}
+
+func (client *MemClient) List() ([]storage.FileDesc, error) {
+	client.bucket.mu.RLock()
+	defer client.bucket.mu.RUnlock()
+	names := make([]string, 0, len(client.bucket.objects))
+	for name := range client.bucket.objects {
+		names = append(names, name)
+	}
+	return parseFileDescs(names), nil
+}
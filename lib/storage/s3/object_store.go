@@ -0,0 +1,51 @@
+package levels3
+
+import (
+	"fmt"
+
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+const (
+	// ObjectStoreS3 stores objects in a s3 compatible service, it's the default backend
+	ObjectStoreS3 = "s3"
+	// ObjectStoreLocal stores objects as plain files under a local directory
+	ObjectStoreLocal = "local"
+	// ObjectStoreMemory stores objects in process memory, mainly for testing
+	ObjectStoreMemory = "memory"
+)
+
+// ObjectStore is the object client used by S3Storage,
+// every key is a leveldb file name relative to OpenOption.Path
+type ObjectStore interface {
+	PutBytes(key string, data []byte) error
+	GetBytes(key string) ([]byte, error)
+	Remove(key string) error
+	List() ([]storage.FileDesc, error)
+}
+
+// NewObjectStore create an object store by OpenOption.Backend
+func NewObjectStore(opt OpenOption) (ObjectStore, error) {
+	switch opt.Backend {
+	case "", ObjectStoreS3:
+		return GetS3Client(opt)
+	case ObjectStoreLocal:
+		return GetLocalClient(opt)
+	case ObjectStoreMemory:
+		return GetMemClient(opt)
+	default:
+		return nil, fmt.Errorf("unsupported object store backend:%s", opt.Backend)
+	}
+}
+
+// parseFileDescs convert object names to leveldb file descs, unknown names are skipped
+func parseFileDescs(names []string) []storage.FileDesc {
+	files := []storage.FileDesc{}
+	for _, name := range names {
+		fd, ok := fsParseName(name)
+		if ok {
+			files = append(files, fd)
+		}
+	}
+	return files
+}
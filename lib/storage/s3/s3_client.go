@@ -13,11 +13,14 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/storage"
 )
 
+// S3Client is an ObjectStore backed by a s3 compatible service, eg. s3 or bos
 type S3Client struct {
 	s3Store *s3.S3
 	opt     OpenOption
 }
 
+var _ ObjectStore = (*S3Client)(nil)
+
 func GetS3Client(opt OpenOption) (*S3Client, error) {
 	creds := credentials.NewStaticCredentials(opt.Ak, opt.Sk, "")
 	_, err := creds.Get()
@@ -27,6 +27,7 @@ var errFileOpen = errors.New("leveldb/storage: file still open")
 const CacheSize = 500
 
 type OpenOption struct {
+	Backend       string // object store backend, s3(default), local or memory
 	Bucket        string
 	Path          string
 	Ak            string
@@ -34,6 +35,8 @@ type OpenOption struct {
 	Region        string
 	Endpoint      string
 	LocalCacheDir string
+	// root directory of objects, only used by local backend
+	LocalObjectDir string
 }
 
 type S3StorageLock struct {
@@ -55,15 +58,16 @@ type S3Storage struct {
 	mu       sync.Mutex
 	slock    *S3StorageLock
 	meta     storage.FileDesc
-	objStore *S3Client
+	objStore ObjectStore
 	ramFiles *lru.Cache
 	opt      OpenOption
 }
 
-// NewS3Storage returns a new s3-backed storage implementation.
+// NewS3Storage returns a new object-store-backed storage implementation,
+// the object store is selected by opt.Backend.
 func NewS3Storage(opt OpenOption) (storage.Storage, error) {
 	rand.Seed(int64(time.Now().Nanosecond()))
-	s3Client, err := GetS3Client(opt)
+	objStore, err := NewObjectStore(opt)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +78,7 @@ func NewS3Storage(opt OpenOption) (storage.Storage, error) {
 	}
 	ramFileCache, _ := lru.New(CacheSize)
 	ms := &S3Storage{
-		objStore: s3Client,
+		objStore: objStore,
 		ramFiles: ramFileCache,
 		opt:      opt,
 	}
@@ -0,0 +1,99 @@
+package levels3
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+func testReopen(t *testing.T, sopt OpenOption) {
+	st, err := NewS3Storage(sopt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := leveldb.Open(st, &opt.Options{WriteBuffer: 64 * opt.KiB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2000; i++ {
+		key := []byte(fmt.Sprintf("key_%d", i))
+		if err := db.Put(key, []byte(fmt.Sprintf("value_%d", i)), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err = NewS3Storage(sopt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err = leveldb.Open(st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for i := 0; i < 2000; i += 100 {
+		value, err := db.Get([]byte(fmt.Sprintf("key_%d", i)), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(value) != fmt.Sprintf("value_%d", i) {
+			t.Fatalf("unexpected value %s", value)
+		}
+	}
+	tables, err := st.List(0xff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) == 0 {
+		t.Fatal("no file in object store")
+	}
+}
+
+func TestMemStorage(t *testing.T) {
+	defer ResetMemObjects()
+	dir, err := os.MkdirTemp("", "levels3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	testReopen(t, OpenOption{
+		Backend:       ObjectStoreMemory,
+		Path:          "node1/ledger",
+		LocalCacheDir: dir,
+	})
+}
+
+func TestLocalStorage(t *testing.T) {
+	dir, err := os.MkdirTemp("", "levels3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	testReopen(t, OpenOption{
+		Backend:        ObjectStoreLocal,
+		Path:           "node1/ledger",
+		LocalCacheDir:  dir + "/cache",
+		LocalObjectDir: dir + "/objects",
+	})
+
+	client, err := GetLocalClient(OpenOption{Path: "node1/ledger", LocalObjectDir: dir + "/objects"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetBytes("CURRENT"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnknownBackend(t *testing.T) {
+	_, err := NewObjectStore(OpenOption{Backend: "ftp"})
+	if err == nil {
+		t.Fatal("expect error for unknown backend")
+	}
+}
@@ -246,17 +246,19 @@ func (m *InvokeRequest) GetAmount() string {
 
 // 预执行的返回结构
 type InvokeResponse struct {
-	Inputs               []*TxInputExt       `protobuf:"bytes,1,rep,name=inputs,proto3" json:"inputs,omitempty"`
-	Outputs              []*TxOutputExt      `protobuf:"bytes,2,rep,name=outputs,proto3" json:"outputs,omitempty"`
-	Response             [][]byte            `protobuf:"bytes,3,rep,name=response,proto3" json:"response,omitempty"`
-	GasUsed              int64               `protobuf:"varint,4,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
-	Requests             []*InvokeRequest    `protobuf:"bytes,5,rep,name=requests,proto3" json:"requests,omitempty"`
-	Responses            []*ContractResponse `protobuf:"bytes,6,rep,name=responses,proto3" json:"responses,omitempty"`
-	UtxoInputs           []*TxInput          `protobuf:"bytes,7,rep,name=utxoInputs,proto3" json:"utxoInputs,omitempty"`
-	UtxoOutputs          []*TxOutput         `protobuf:"bytes,8,rep,name=utxoOutputs,proto3" json:"utxoOutputs,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	Inputs      []*TxInputExt       `protobuf:"bytes,1,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Outputs     []*TxOutputExt      `protobuf:"bytes,2,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	Response    [][]byte            `protobuf:"bytes,3,rep,name=response,proto3" json:"response,omitempty"`
+	GasUsed     int64               `protobuf:"varint,4,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	Requests    []*InvokeRequest    `protobuf:"bytes,5,rep,name=requests,proto3" json:"requests,omitempty"`
+	Responses   []*ContractResponse `protobuf:"bytes,6,rep,name=responses,proto3" json:"responses,omitempty"`
+	UtxoInputs  []*TxInput          `protobuf:"bytes,7,rep,name=utxoInputs,proto3" json:"utxoInputs,omitempty"`
+	UtxoOutputs []*TxOutput         `protobuf:"bytes,8,rep,name=utxoOutputs,proto3" json:"utxoOutputs,omitempty"`
+	// results 按请求顺序记录每个请求各自的返回、gas和事件
+	Results              []*InvokeResult `protobuf:"bytes,9,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *InvokeResponse) Reset()         { *m = InvokeResponse{} }
@@ -340,6 +342,69 @@ func (m *InvokeResponse) GetUtxoOutputs() []*TxOutput {
 	return nil
 }
 
+func (m *InvokeResponse) GetResults() []*InvokeResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// 单个请求的执行结果
+type InvokeResult struct {
+	Response             *ContractResponse `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+	GasUsed              int64             `protobuf:"varint,2,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	Events               []*ContractEvent  `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *InvokeResult) Reset()         { *m = InvokeResult{} }
+func (m *InvokeResult) String() string { return proto.CompactTextString(m) }
+func (*InvokeResult) ProtoMessage()    {}
+func (*InvokeResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_919de52f3bf773d2, []int{4}
+}
+
+func (m *InvokeResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InvokeResult.Unmarshal(m, b)
+}
+func (m *InvokeResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InvokeResult.Marshal(b, m, deterministic)
+}
+func (m *InvokeResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InvokeResult.Merge(m, src)
+}
+func (m *InvokeResult) XXX_Size() int {
+	return xxx_messageInfo_InvokeResult.Size(m)
+}
+func (m *InvokeResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_InvokeResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InvokeResult proto.InternalMessageInfo
+
+func (m *InvokeResult) GetResponse() *ContractResponse {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+func (m *InvokeResult) GetGasUsed() int64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *InvokeResult) GetEvents() []*ContractEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
 // ContractResponse is the response returnd by contract
 type ContractResponse struct {
 	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
@@ -354,7 +419,7 @@ func (m *ContractResponse) Reset()         { *m = ContractResponse{} }
 func (m *ContractResponse) String() string { return proto.CompactTextString(m) }
 func (*ContractResponse) ProtoMessage()    {}
 func (*ContractResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{4}
+	return fileDescriptor_919de52f3bf773d2, []int{5}
 }
 
 func (m *ContractResponse) XXX_Unmarshal(b []byte) error {
@@ -397,11 +462,15 @@ func (m *ContractResponse) GetBody() []byte {
 }
 
 type WasmCodeDesc struct {
-	Runtime              string   `protobuf:"bytes,1,opt,name=runtime,proto3" json:"runtime,omitempty"`
-	Compiler             string   `protobuf:"bytes,2,opt,name=compiler,proto3" json:"compiler,omitempty"`
-	Digest               []byte   `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`
-	VmCompiler           string   `protobuf:"bytes,4,opt,name=vm_compiler,json=vmCompiler,proto3" json:"vm_compiler,omitempty"`
-	ContractType         string   `protobuf:"bytes,5,opt,name=contract_type,json=contractType,proto3" json:"contract_type,omitempty"`
+	Runtime      string `protobuf:"bytes,1,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	Compiler     string `protobuf:"bytes,2,opt,name=compiler,proto3" json:"compiler,omitempty"`
+	Digest       []byte `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`
+	VmCompiler   string `protobuf:"bytes,4,opt,name=vm_compiler,json=vmCompiler,proto3" json:"vm_compiler,omitempty"`
+	ContractType string `protobuf:"bytes,5,opt,name=contract_type,json=contractType,proto3" json:"contract_type,omitempty"`
+	// 合约被所有者冻结，冻结期间不能调用
+	Frozen bool `protobuf:"varint,6,opt,name=frozen,proto3" json:"frozen,omitempty"`
+	// 部署或升级时是否带有接口描述
+	HasAbi               bool     `protobuf:"varint,7,opt,name=has_abi,json=hasAbi,proto3" json:"has_abi,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -411,7 +480,7 @@ func (m *WasmCodeDesc) Reset()         { *m = WasmCodeDesc{} }
 func (m *WasmCodeDesc) String() string { return proto.CompactTextString(m) }
 func (*WasmCodeDesc) ProtoMessage()    {}
 func (*WasmCodeDesc) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{5}
+	return fileDescriptor_919de52f3bf773d2, []int{6}
 }
 
 func (m *WasmCodeDesc) XXX_Unmarshal(b []byte) error {
@@ -467,6 +536,20 @@ func (m *WasmCodeDesc) GetContractType() string {
 	return ""
 }
 
+func (m *WasmCodeDesc) GetFrozen() bool {
+	if m != nil {
+		return m.Frozen
+	}
+	return false
+}
+
+func (m *WasmCodeDesc) GetHasAbi() bool {
+	if m != nil {
+		return m.HasAbi
+	}
+	return false
+}
+
 type ContractEvent struct {
 	Contract             string   `protobuf:"bytes,1,opt,name=contract,proto3" json:"contract,omitempty"`
 	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
@@ -480,7 +563,7 @@ func (m *ContractEvent) Reset()         { *m = ContractEvent{} }
 func (m *ContractEvent) String() string { return proto.CompactTextString(m) }
 func (*ContractEvent) ProtoMessage()    {}
 func (*ContractEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{6}
+	return fileDescriptor_919de52f3bf773d2, []int{7}
 }
 
 func (m *ContractEvent) XXX_Unmarshal(b []byte) error {
@@ -534,7 +617,7 @@ func (m *ContractStatData) Reset()         { *m = ContractStatData{} }
 func (m *ContractStatData) String() string { return proto.CompactTextString(m) }
 func (*ContractStatData) ProtoMessage()    {}
 func (*ContractStatData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{7}
+	return fileDescriptor_919de52f3bf773d2, []int{8}
 }
 
 func (m *ContractStatData) XXX_Unmarshal(b []byte) error {
@@ -571,12 +654,16 @@ func (m *ContractStatData) GetContractCount() int64 {
 
 // Status of a contract
 type ContractStatus struct {
-	ContractName         string   `protobuf:"bytes,1,opt,name=contract_name,json=contractName,proto3" json:"contract_name,omitempty"`
-	Txid                 string   `protobuf:"bytes,2,opt,name=txid,proto3" json:"txid,omitempty"`
-	Desc                 []byte   `protobuf:"bytes,3,opt,name=desc,proto3" json:"desc,omitempty"`
-	IsBanned             bool     `protobuf:"varint,4,opt,name=is_banned,json=isBanned,proto3" json:"is_banned,omitempty"`
-	Timestamp            int64    `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Runtime              string   `protobuf:"bytes,6,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	ContractName string `protobuf:"bytes,1,opt,name=contract_name,json=contractName,proto3" json:"contract_name,omitempty"`
+	Txid         string `protobuf:"bytes,2,opt,name=txid,proto3" json:"txid,omitempty"`
+	Desc         []byte `protobuf:"bytes,3,opt,name=desc,proto3" json:"desc,omitempty"`
+	IsBanned     bool   `protobuf:"varint,4,opt,name=is_banned,json=isBanned,proto3" json:"is_banned,omitempty"`
+	Timestamp    int64  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Runtime      string `protobuf:"bytes,6,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	IsFrozen     bool   `protobuf:"varint,7,opt,name=is_frozen,json=isFrozen,proto3" json:"is_frozen,omitempty"`
+	IsDestroyed  bool   `protobuf:"varint,8,opt,name=is_destroyed,json=isDestroyed,proto3" json:"is_destroyed,omitempty"`
+	// 合约所属的合约账户
+	AccountName          string   `protobuf:"bytes,9,opt,name=account_name,json=accountName,proto3" json:"account_name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -586,7 +673,7 @@ func (m *ContractStatus) Reset()         { *m = ContractStatus{} }
 func (m *ContractStatus) String() string { return proto.CompactTextString(m) }
 func (*ContractStatus) ProtoMessage()    {}
 func (*ContractStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{8}
+	return fileDescriptor_919de52f3bf773d2, []int{9}
 }
 
 func (m *ContractStatus) XXX_Unmarshal(b []byte) error {
@@ -649,6 +736,108 @@ func (m *ContractStatus) GetRuntime() string {
 	return ""
 }
 
+func (m *ContractStatus) GetIsFrozen() bool {
+	if m != nil {
+		return m.IsFrozen
+	}
+	return false
+}
+
+func (m *ContractStatus) GetIsDestroyed() bool {
+	if m != nil {
+		return m.IsDestroyed
+	}
+	return false
+}
+
+func (m *ContractStatus) GetAccountName() string {
+	if m != nil {
+		return m.AccountName
+	}
+	return ""
+}
+
+// 合约的一个历史版本
+type ContractVersion struct {
+	ContractName string `protobuf:"bytes,1,opt,name=contract_name,json=contractName,proto3" json:"contract_name,omitempty"`
+	Version      int64  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Digest       []byte `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`
+	// 版本生效的交易、区块高度和时间
+	Txid                 string   `protobuf:"bytes,4,opt,name=txid,proto3" json:"txid,omitempty"`
+	Height               int64    `protobuf:"varint,5,opt,name=height,proto3" json:"height,omitempty"`
+	Timestamp            int64    `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContractVersion) Reset()         { *m = ContractVersion{} }
+func (m *ContractVersion) String() string { return proto.CompactTextString(m) }
+func (*ContractVersion) ProtoMessage()    {}
+func (*ContractVersion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_919de52f3bf773d2, []int{10}
+}
+
+func (m *ContractVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContractVersion.Unmarshal(m, b)
+}
+func (m *ContractVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContractVersion.Marshal(b, m, deterministic)
+}
+func (m *ContractVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContractVersion.Merge(m, src)
+}
+func (m *ContractVersion) XXX_Size() int {
+	return xxx_messageInfo_ContractVersion.Size(m)
+}
+func (m *ContractVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContractVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContractVersion proto.InternalMessageInfo
+
+func (m *ContractVersion) GetContractName() string {
+	if m != nil {
+		return m.ContractName
+	}
+	return ""
+}
+
+func (m *ContractVersion) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ContractVersion) GetDigest() []byte {
+	if m != nil {
+		return m.Digest
+	}
+	return nil
+}
+
+func (m *ContractVersion) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *ContractVersion) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *ContractVersion) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("protos.ResourceType", ResourceType_name, ResourceType_value)
 	proto.RegisterType((*GasPrice)(nil), "protos.GasPrice")
@@ -656,67 +845,79 @@ func init() {
 	proto.RegisterType((*InvokeRequest)(nil), "protos.InvokeRequest")
 	proto.RegisterMapType((map[string][]byte)(nil), "protos.InvokeRequest.ArgsEntry")
 	proto.RegisterType((*InvokeResponse)(nil), "protos.InvokeResponse")
+	proto.RegisterType((*InvokeResult)(nil), "protos.InvokeResult")
 	proto.RegisterType((*ContractResponse)(nil), "protos.ContractResponse")
 	proto.RegisterType((*WasmCodeDesc)(nil), "protos.WasmCodeDesc")
 	proto.RegisterType((*ContractEvent)(nil), "protos.ContractEvent")
 	proto.RegisterType((*ContractStatData)(nil), "protos.ContractStatData")
 	proto.RegisterType((*ContractStatus)(nil), "protos.ContractStatus")
+	proto.RegisterType((*ContractVersion)(nil), "protos.ContractVersion")
 }
 
 func init() { proto.RegisterFile("protos/contract.proto", fileDescriptor_919de52f3bf773d2) }
 
 var fileDescriptor_919de52f3bf773d2 = []byte{
-	// 817 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x55, 0x6d, 0x6f, 0x1b, 0x45,
-	0x10, 0xc6, 0xbe, 0x8b, 0x7d, 0x9e, 0x38, 0xa9, 0xb5, 0xb4, 0xe8, 0x08, 0xa0, 0x46, 0x07, 0x42,
-	0x56, 0x25, 0x62, 0x91, 0x4a, 0x14, 0xf1, 0x01, 0x89, 0x3a, 0x06, 0x45, 0x50, 0x52, 0x6d, 0x5a,
-	0x51, 0x10, 0x92, 0xb5, 0xbe, 0x5b, 0x2e, 0xa7, 0x78, 0x6f, 0x8f, 0x7d, 0xb1, 0x6c, 0x7e, 0x0e,
-	0x3f, 0x81, 0xcf, 0xfc, 0x2a, 0x7e, 0x01, 0xda, 0x37, 0xfb, 0xec, 0xf6, 0xdb, 0x3e, 0x33, 0xcf,
-	0xcc, 0x3d, 0x33, 0xb3, 0xb3, 0x07, 0x8f, 0x1a, 0xc1, 0x15, 0x97, 0x93, 0x9c, 0xd7, 0x4a, 0x90,
-	0x5c, 0x5d, 0x58, 0x8c, 0x7a, 0xce, 0x7c, 0xf6, 0xc9, 0x5a, 0x37, 0x54, 0xe4, 0x5c, 0xd0, 0x89,
-	0x27, 0x2e, 0x69, 0x51, 0x52, 0xe1, 0x68, 0xd9, 0x5f, 0x90, 0xfc, 0x40, 0xe4, 0x4b, 0x51, 0xe5,
-	0x14, 0x7d, 0x08, 0x49, 0xde, 0xe8, 0xb9, 0x20, 0x8a, 0xa6, 0x9d, 0xf3, 0xce, 0x38, 0xc2, 0xfd,
-	0xbc, 0xd1, 0x98, 0x28, 0xeb, 0x62, 0x94, 0x39, 0x57, 0xd7, 0xb9, 0x18, 0x65, 0xd6, 0xf5, 0x11,
-	0x0c, 0x8a, 0x4a, 0xde, 0x3b, 0x5f, 0x64, 0x7d, 0x89, 0x31, 0x04, 0xe7, 0xfa, 0x0f, 0x4a, 0x9d,
-	0x33, 0x76, 0x4e, 0x63, 0x30, 0xce, 0xec, 0x06, 0x4e, 0x30, 0x95, 0x5c, 0x8b, 0x9c, 0xfe, 0x54,
-	0xb1, 0x4a, 0xa1, 0x31, 0xc4, 0x6a, 0xd3, 0xb8, 0x8f, 0x9f, 0x5e, 0x3e, 0x74, 0x12, 0xe5, 0x45,
-	0x20, 0xbd, 0xda, 0x34, 0x14, 0x5b, 0x06, 0x7a, 0x08, 0x47, 0x4b, 0x13, 0xe2, 0xc5, 0x38, 0x90,
-	0xfd, 0xdb, 0x85, 0x93, 0xeb, 0x7a, 0xc5, 0xef, 0x29, 0xa6, 0x7f, 0x6a, 0x2a, 0x15, 0x7a, 0x0c,
-	0xc7, 0x8c, 0x17, 0x7a, 0x49, 0xe7, 0x35, 0x61, 0x2e, 0xf1, 0x00, 0x83, 0x33, 0xfd, 0x4c, 0x18,
-	0x45, 0x9f, 0xc2, 0x49, 0x68, 0x9c, 0xa3, 0x74, 0x2d, 0x65, 0x18, 0x8c, 0x96, 0x64, 0xb2, 0x50,
-	0x75, 0xc7, 0x0b, 0x47, 0x89, 0x7c, 0x16, 0x6b, 0xb2, 0x84, 0xa7, 0x10, 0x13, 0x51, 0xca, 0x34,
-	0x3e, 0x8f, 0xc6, 0xc7, 0x97, 0x8f, 0x83, 0xf0, 0x3d, 0x2d, 0x17, 0xdf, 0x89, 0x52, 0xce, 0x6a,
-	0x25, 0x36, 0xd8, 0x92, 0xd1, 0xb7, 0xf0, 0x40, 0xf8, 0xca, 0xe6, 0x56, 0xbf, 0x4c, 0x8f, 0x6c,
-	0xfc, 0xa3, 0xc3, 0xc2, 0x6d, 0x77, 0xf0, 0xa9, 0x68, 0x43, 0x89, 0x3e, 0x80, 0x1e, 0x61, 0x5c,
-	0xd7, 0x2a, 0xed, 0x59, 0x41, 0x1e, 0x9d, 0x3d, 0x83, 0xc1, 0xf6, 0x53, 0x68, 0x04, 0xd1, 0x3d,
-	0xdd, 0xf8, 0xc2, 0xcd, 0xd1, 0xb4, 0x6e, 0x45, 0x96, 0xda, 0x55, 0x3a, 0xc4, 0x0e, 0x7c, 0xd3,
-	0xfd, 0xba, 0x93, 0xfd, 0xd7, 0x85, 0xd3, 0x20, 0x59, 0x36, 0xbc, 0x96, 0x14, 0x3d, 0x81, 0x5e,
-	0x55, 0x37, 0x5a, 0xc9, 0xb4, 0x63, 0xa5, 0xa1, 0x20, 0xed, 0xd5, 0xfa, 0xda, 0xd8, 0x67, 0x6b,
-	0x85, 0x3d, 0x03, 0x7d, 0x01, 0x7d, 0xae, 0x95, 0x25, 0x77, 0x2d, 0xf9, 0xfd, 0x1d, 0xf9, 0xc6,
-	0x3a, 0x0c, 0x3b, 0x70, 0xd0, 0x19, 0x24, 0xc2, 0x7f, 0x26, 0x8d, 0xce, 0xa3, 0xf1, 0x10, 0x6f,
-	0xb1, 0xb9, 0x6e, 0x25, 0x91, 0x73, 0x2d, 0x69, 0xe1, 0x6f, 0x4d, 0xbf, 0x24, 0xf2, 0xb5, 0xa4,
-	0x05, 0xfa, 0xd2, 0x84, 0xd9, 0x86, 0xbe, 0xd5, 0xae, 0xbd, 0x76, 0xe3, 0x2d, 0x0d, 0x7d, 0x05,
-	0x83, 0x90, 0x59, 0xa6, 0x3d, 0x1b, 0x93, 0x86, 0x98, 0xa9, 0x9f, 0x73, 0xa8, 0x18, 0xef, 0xa8,
-	0x68, 0x02, 0xa0, 0xd5, 0x9a, 0x5f, 0xbb, 0x06, 0xf4, 0x6d, 0xe0, 0x83, 0x83, 0x06, 0xe0, 0x16,
-	0x05, 0x5d, 0xc2, 0xb1, 0x41, 0x37, 0xbe, 0x0b, 0x89, 0x8d, 0x18, 0x1d, 0x76, 0x01, 0xb7, 0x49,
-	0xd9, 0x1b, 0x18, 0x1d, 0x6a, 0x30, 0x93, 0x95, 0x8a, 0x28, 0x2d, 0xed, 0xdc, 0x8e, 0xb0, 0x47,
-	0x28, 0x85, 0x3e, 0xa3, 0x52, 0x92, 0x32, 0x5c, 0xd3, 0x00, 0x11, 0x82, 0x78, 0xc1, 0x8b, 0x8d,
-	0xbd, 0x9a, 0x43, 0x6c, 0xcf, 0xd9, 0xdf, 0x1d, 0x18, 0xfe, 0x42, 0x24, 0x9b, 0xf2, 0x82, 0x5e,
-	0x51, 0x99, 0x9b, 0x70, 0xa1, 0x6b, 0x55, 0x6d, 0x17, 0x21, 0x40, 0x33, 0x8b, 0x9c, 0xb3, 0xa6,
-	0x5a, 0x52, 0xe1, 0x33, 0x6f, 0xb1, 0x11, 0x53, 0x54, 0x25, 0x95, 0xca, 0x27, 0xf7, 0xc8, 0x2c,
-	0xc5, 0x8a, 0xcd, 0xb7, 0x61, 0xb1, 0x5b, 0x8a, 0x15, 0x9b, 0x86, 0xc0, 0xf6, 0x6a, 0xd9, 0xb5,
-	0x3e, 0xda, 0x5f, 0x2d, 0xb3, 0xce, 0xd9, 0x2d, 0x9c, 0x84, 0xf2, 0x67, 0x2b, 0x5a, 0x2b, 0x27,
-	0xc5, 0x19, 0xbc, 0xca, 0x2d, 0x36, 0x55, 0xb6, 0x76, 0xd4, 0x9e, 0xdf, 0x59, 0xf9, 0xef, 0xbb,
-	0x9e, 0xde, 0x2a, 0xa2, 0xae, 0x88, 0x22, 0x28, 0x83, 0x21, 0xc9, 0x73, 0xb3, 0x20, 0x53, 0xbb,
-	0x33, 0xee, 0x81, 0xdb, 0xb3, 0xa1, 0xcf, 0x76, 0x8a, 0x1d, 0xc9, 0xbd, 0x2e, 0xfb, 0xc6, 0xec,
-	0x9f, 0x0e, 0x9c, 0xb6, 0xd3, 0x6b, 0xf9, 0xf6, 0x2b, 0xd2, 0x79, 0xc7, 0x2b, 0x82, 0x20, 0x56,
-	0xeb, 0xaa, 0x08, 0xea, 0xcd, 0xd9, 0xd8, 0x0a, 0x2a, 0xf3, 0xa0, 0xde, 0x9c, 0xcd, 0x9b, 0x59,
-	0xc9, 0xf9, 0x82, 0xd4, 0xb5, 0xbf, 0xfd, 0x09, 0x4e, 0x2a, 0xf9, 0xdc, 0x62, 0xf4, 0x31, 0x0c,
-	0xcc, 0xc4, 0xa4, 0x22, 0xac, 0xb1, 0x0d, 0x8d, 0xf0, 0xce, 0xd0, 0x9e, 0x70, 0x6f, 0x6f, 0xc2,
-	0x4f, 0x9e, 0xc1, 0xb0, 0xfd, 0x8c, 0xa2, 0x3e, 0x44, 0xd3, 0x97, 0xaf, 0x47, 0xef, 0x21, 0x80,
-	0xde, 0x8b, 0xd9, 0x8b, 0x1b, 0xfc, 0xeb, 0xa8, 0x83, 0x12, 0x88, 0xaf, 0xae, 0x6f, 0x7f, 0x1c,
-	0x75, 0xcd, 0xe9, 0xcd, 0xf7, 0xb3, 0xd9, 0x28, 0x7a, 0x3e, 0xfe, 0xed, 0xf3, 0xb2, 0x52, 0x77,
-	0x7a, 0x71, 0x91, 0x73, 0x36, 0x71, 0x3f, 0x93, 0x3b, 0x52, 0xd5, 0x93, 0xc3, 0xff, 0xca, 0xc2,
-	0xfd, 0x71, 0x9e, 0xfe, 0x1f, 0x00, 0x00, 0xff, 0xff, 0x4e, 0xe4, 0xd3, 0xa2, 0x91, 0x06, 0x00,
-	0x00,
+	// 991 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0x6f, 0x6f, 0x1b, 0xc5,
+	0x13, 0xfe, 0xd9, 0xe7, 0xdc, 0x9d, 0x27, 0x4e, 0x62, 0xed, 0x2f, 0x2d, 0x47, 0x00, 0x35, 0x1c,
+	0x08, 0x45, 0x95, 0x9a, 0x88, 0x14, 0x51, 0xc4, 0x0b, 0xa4, 0x36, 0x71, 0x51, 0x04, 0x25, 0xd5,
+	0xa6, 0x85, 0x82, 0x90, 0xac, 0xf5, 0xdd, 0xd6, 0x3e, 0xc5, 0xf7, 0x87, 0x9d, 0x3d, 0xcb, 0xee,
+	0x87, 0xe0, 0x1d, 0x5f, 0x80, 0x8f, 0xc0, 0x6b, 0xbe, 0x05, 0x5f, 0x08, 0xed, 0x3f, 0xe7, 0xce,
+	0x0d, 0x88, 0x37, 0xd6, 0x3e, 0x33, 0xcf, 0xcc, 0xce, 0x3c, 0xbb, 0x3b, 0x3e, 0xb8, 0x53, 0x89,
+	0x52, 0x96, 0x78, 0x92, 0x94, 0x85, 0x14, 0x2c, 0x91, 0xc7, 0x1a, 0x13, 0xdf, 0x98, 0x0f, 0x3e,
+	0x58, 0xd6, 0x15, 0x17, 0x49, 0x29, 0xf8, 0x89, 0x25, 0xce, 0x79, 0x3a, 0xe5, 0xc2, 0xd0, 0xe2,
+	0x37, 0x10, 0x7e, 0xcd, 0xf0, 0xb9, 0xc8, 0x12, 0x4e, 0xde, 0x85, 0x30, 0xa9, 0xea, 0xb1, 0x60,
+	0x92, 0x47, 0x9d, 0xc3, 0xce, 0x91, 0x47, 0x83, 0xa4, 0xaa, 0x29, 0x93, 0xda, 0x95, 0xf3, 0xdc,
+	0xb8, 0xba, 0xc6, 0x95, 0xf3, 0x5c, 0xbb, 0xde, 0x83, 0x7e, 0x9a, 0xe1, 0xb5, 0xf1, 0x79, 0xda,
+	0x17, 0x2a, 0x83, 0x73, 0x2e, 0x5f, 0x73, 0x6e, 0x9c, 0x3d, 0xe3, 0x54, 0x06, 0xe5, 0x8c, 0x2f,
+	0x61, 0x87, 0x72, 0x2c, 0x6b, 0x91, 0xf0, 0x6f, 0xb3, 0x3c, 0x93, 0xe4, 0x08, 0x7a, 0x72, 0x55,
+	0x99, 0xcd, 0x77, 0x4f, 0xf7, 0x4d, 0x89, 0x78, 0xec, 0x48, 0x2f, 0x56, 0x15, 0xa7, 0x9a, 0x41,
+	0xf6, 0x61, 0x6b, 0xae, 0x42, 0x6c, 0x31, 0x06, 0xc4, 0x7f, 0x76, 0x61, 0xe7, 0xa2, 0x58, 0x94,
+	0xd7, 0x9c, 0xf2, 0x5f, 0x6a, 0x8e, 0x92, 0xdc, 0x83, 0xed, 0xbc, 0x4c, 0xeb, 0x39, 0x1f, 0x17,
+	0x2c, 0x37, 0x89, 0xfb, 0x14, 0x8c, 0xe9, 0x3b, 0x96, 0x73, 0xf2, 0x11, 0xec, 0x38, 0xe1, 0x0c,
+	0xa5, 0xab, 0x29, 0x03, 0x67, 0xd4, 0x24, 0x95, 0x85, 0xcb, 0x59, 0x99, 0x1a, 0x8a, 0x67, 0xb3,
+	0x68, 0x93, 0x26, 0x3c, 0x84, 0x1e, 0x13, 0x53, 0x8c, 0x7a, 0x87, 0xde, 0xd1, 0xf6, 0xe9, 0x3d,
+	0x57, 0x78, 0xab, 0x96, 0xe3, 0xc7, 0x62, 0x8a, 0xa3, 0x42, 0x8a, 0x15, 0xd5, 0x64, 0xf2, 0x15,
+	0xec, 0x09, 0xdb, 0xd9, 0x58, 0xd7, 0x8f, 0xd1, 0x96, 0x8e, 0xbf, 0xb3, 0xd9, 0xb8, 0x56, 0x87,
+	0xee, 0x8a, 0x26, 0x44, 0x72, 0x17, 0x7c, 0x96, 0x97, 0x75, 0x21, 0x23, 0x5f, 0x17, 0x64, 0xd1,
+	0xc1, 0x23, 0xe8, 0xaf, 0xb7, 0x22, 0x43, 0xf0, 0xae, 0xf9, 0xca, 0x36, 0xae, 0x96, 0x4a, 0xba,
+	0x05, 0x9b, 0xd7, 0xa6, 0xd3, 0x01, 0x35, 0xe0, 0xcb, 0xee, 0x17, 0x9d, 0xf8, 0x77, 0x0f, 0x76,
+	0x5d, 0xc9, 0x58, 0x95, 0x05, 0x72, 0x72, 0x1f, 0xfc, 0xac, 0xa8, 0x6a, 0x89, 0x51, 0x47, 0x97,
+	0x46, 0x5c, 0x69, 0x2f, 0x96, 0x17, 0xca, 0x3e, 0x5a, 0x4a, 0x6a, 0x19, 0xe4, 0x01, 0x04, 0x65,
+	0x2d, 0x35, 0xb9, 0xab, 0xc9, 0xff, 0xbf, 0x21, 0x5f, 0xd6, 0xd2, 0xb2, 0x1d, 0x87, 0x1c, 0x40,
+	0x28, 0xec, 0x36, 0x91, 0x77, 0xe8, 0x1d, 0x0d, 0xe8, 0x1a, 0xab, 0xeb, 0x36, 0x65, 0x38, 0xae,
+	0x91, 0xa7, 0xf6, 0xd6, 0x04, 0x53, 0x86, 0x2f, 0x91, 0xa7, 0xe4, 0x53, 0x15, 0xa6, 0x05, 0x7d,
+	0x4b, 0xae, 0x96, 0xdc, 0x74, 0x4d, 0x23, 0x9f, 0x43, 0xdf, 0x65, 0xc6, 0xc8, 0xd7, 0x31, 0x91,
+	0x8b, 0x39, 0xb3, 0xe7, 0xec, 0x3a, 0xa6, 0x37, 0x54, 0x72, 0x02, 0x50, 0xcb, 0x65, 0x79, 0x61,
+	0x04, 0x08, 0x74, 0xe0, 0xde, 0x86, 0x00, 0xb4, 0x41, 0x21, 0xa7, 0xb0, 0xad, 0xd0, 0xa5, 0x55,
+	0x21, 0xd4, 0x11, 0xc3, 0x4d, 0x15, 0x68, 0x93, 0x44, 0x8e, 0x21, 0x10, 0x1c, 0xeb, 0xb9, 0xc4,
+	0xa8, 0xaf, 0xf9, 0xfb, 0x9b, 0xed, 0x28, 0x27, 0x75, 0xa4, 0xf8, 0xd7, 0x0e, 0x0c, 0x9a, 0x1e,
+	0xf2, 0x59, 0x43, 0x47, 0x75, 0xcc, 0xff, 0xd6, 0xdc, 0xed, 0x0a, 0x77, 0xdb, 0x0a, 0x3f, 0x00,
+	0x9f, 0x2f, 0x78, 0x21, 0x31, 0xf2, 0xda, 0xfa, 0xba, 0x74, 0x23, 0xe5, 0xa5, 0x96, 0x14, 0xbf,
+	0x82, 0xe1, 0xe6, 0x3e, 0xea, 0x6a, 0xa2, 0x64, 0xb2, 0x46, 0x5d, 0xd1, 0x16, 0xb5, 0x88, 0x44,
+	0x10, 0xe4, 0x1c, 0x91, 0x4d, 0xdd, 0x3b, 0x73, 0x90, 0x10, 0xe8, 0x4d, 0xca, 0x74, 0xa5, 0xdf,
+	0xd6, 0x80, 0xea, 0x75, 0xfc, 0x57, 0x07, 0x06, 0x3f, 0x30, 0xcc, 0xcf, 0xca, 0x94, 0x9f, 0x73,
+	0x4c, 0x54, 0xb8, 0xa8, 0x0b, 0x99, 0xad, 0x5f, 0xb2, 0x83, 0xea, 0x32, 0x25, 0x65, 0x5e, 0x65,
+	0x73, 0x2e, 0x6c, 0xe6, 0x35, 0x56, 0xc5, 0xa4, 0xd9, 0x94, 0xa3, 0xb4, 0xc9, 0x2d, 0x52, 0xaf,
+	0x7a, 0x91, 0x8f, 0xd7, 0x61, 0x3d, 0xf3, 0xaa, 0x17, 0xf9, 0x99, 0x0b, 0x6c, 0xce, 0x06, 0x3d,
+	0x97, 0xb6, 0xda, 0xb3, 0x41, 0xcd, 0x23, 0x95, 0xfd, 0xb5, 0x28, 0xdf, 0xf0, 0x42, 0xbf, 0xc2,
+	0x90, 0x5a, 0x44, 0xde, 0x81, 0x60, 0xc6, 0x70, 0xcc, 0x26, 0x59, 0x14, 0x18, 0xc7, 0x8c, 0xe1,
+	0xe3, 0x49, 0x16, 0x5f, 0xc1, 0x4e, 0x4b, 0x48, 0x53, 0xbb, 0x31, 0xd8, 0xb6, 0xd6, 0x58, 0xc9,
+	0xd2, 0x98, 0x4a, 0x7a, 0x7d, 0xab, 0x54, 0x3f, 0xdf, 0x1c, 0xc2, 0x95, 0x64, 0xf2, 0x9c, 0x49,
+	0x46, 0x62, 0x18, 0xb0, 0x24, 0x51, 0x23, 0xe1, 0x4c, 0xfd, 0xd8, 0x91, 0xde, 0xb2, 0x91, 0x8f,
+	0x6f, 0x5a, 0x34, 0x24, 0x73, 0x17, 0xda, 0xc6, 0xf8, 0xb7, 0x2e, 0xec, 0x36, 0xd3, 0xd7, 0xf8,
+	0xf6, 0xdc, 0xec, 0xdc, 0x32, 0x37, 0x09, 0xf4, 0xe4, 0x32, 0x4b, 0x5d, 0xf5, 0x6a, 0xad, 0x6c,
+	0x29, 0xc7, 0xc4, 0x55, 0xaf, 0xd6, 0xea, 0x5f, 0x22, 0xc3, 0xf1, 0x84, 0x15, 0x85, 0x7d, 0xef,
+	0x21, 0x0d, 0x33, 0x7c, 0xa2, 0x31, 0x79, 0x1f, 0xfa, 0xea, 0x88, 0x51, 0xb2, 0xbc, 0xd2, 0x27,
+	0xe0, 0xd1, 0x1b, 0x43, 0xf3, 0x4a, 0xf8, 0xed, 0x2b, 0x61, 0x92, 0xda, 0xb3, 0x09, 0x5c, 0xd2,
+	0xa7, 0xe6, 0x74, 0x3e, 0x84, 0x41, 0x86, 0xe3, 0x94, 0xa3, 0x14, 0xe5, 0x8a, 0xa7, 0x51, 0xa8,
+	0xfd, 0xdb, 0x19, 0x9e, 0x3b, 0x93, 0xa2, 0x58, 0xa9, 0x4c, 0x83, 0x7d, 0x9d, 0x7e, 0xdb, 0xda,
+	0x54, 0x7f, 0xf1, 0x1f, 0x1d, 0xd8, 0x73, 0xba, 0x7c, 0xcf, 0x05, 0x66, 0x65, 0xf1, 0xdf, 0x84,
+	0x89, 0x20, 0x58, 0x18, 0xbe, 0x7b, 0x7c, 0x16, 0xfe, 0xe3, 0x65, 0x75, 0x52, 0xf6, 0x1a, 0x52,
+	0xde, 0x05, 0x7f, 0xc6, 0xb3, 0xe9, 0x4c, 0x5a, 0x59, 0x2c, 0x6a, 0x2b, 0xe6, 0x6f, 0x28, 0x76,
+	0xff, 0x11, 0x0c, 0x9a, 0x7f, 0xa8, 0x24, 0x00, 0xef, 0xec, 0xf9, 0xcb, 0xe1, 0xff, 0x08, 0x80,
+	0xff, 0x6c, 0xf4, 0xec, 0x92, 0xfe, 0x38, 0xec, 0x90, 0x10, 0x7a, 0xe7, 0x17, 0x57, 0xdf, 0x0c,
+	0xbb, 0x6a, 0xf5, 0xea, 0xe9, 0x68, 0x34, 0xf4, 0x9e, 0x1c, 0xfd, 0xf4, 0xc9, 0x34, 0x93, 0xb3,
+	0x7a, 0x72, 0x9c, 0x94, 0xf9, 0x89, 0xf9, 0xac, 0x98, 0xb1, 0xac, 0x38, 0xd9, 0xfc, 0xc2, 0x98,
+	0x98, 0x6f, 0x8f, 0x87, 0x7f, 0x0f, 0x00, 0x40, 0xd4, 0x85, 0xf9, 0x9b, 0x08, 0x00, 0x00,
 }
@@ -29,14 +29,18 @@ type SubscribeType int32
 const (
 	// 区块事件，payload为BlockFilter
 	SubscribeType_BLOCK SubscribeType = 0
+	// 区块不可逆事件，payload为FinalityFilter
+	SubscribeType_FINALITY SubscribeType = 1
 )
 
 var SubscribeType_name = map[int32]string{
 	0: "BLOCK",
+	1: "FINALITY",
 }
 
 var SubscribeType_value = map[string]int32{
-	"BLOCK": 0,
+	"BLOCK":    0,
+	"FINALITY": 1,
 }
 
 func (x SubscribeType) String() string {
@@ -354,6 +358,109 @@ func (m *FilteredBlock) GetTxs() []*FilteredTransaction {
 	return nil
 }
 
+type FinalityFilter struct {
+	Bcname               string      `protobuf:"bytes,1,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	Range                *BlockRange `protobuf:"bytes,2,opt,name=range,proto3" json:"range,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *FinalityFilter) Reset()         { *m = FinalityFilter{} }
+func (m *FinalityFilter) String() string { return proto.CompactTextString(m) }
+func (*FinalityFilter) ProtoMessage()    {}
+func (*FinalityFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bec55cd27928da5d, []int{5}
+}
+
+func (m *FinalityFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FinalityFilter.Unmarshal(m, b)
+}
+func (m *FinalityFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FinalityFilter.Marshal(b, m, deterministic)
+}
+func (m *FinalityFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FinalityFilter.Merge(m, src)
+}
+func (m *FinalityFilter) XXX_Size() int {
+	return xxx_messageInfo_FinalityFilter.Size(m)
+}
+func (m *FinalityFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_FinalityFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FinalityFilter proto.InternalMessageInfo
+
+func (m *FinalityFilter) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *FinalityFilter) GetRange() *BlockRange {
+	if m != nil {
+		return m.Range
+	}
+	return nil
+}
+
+// 按共识规则变为不可逆的区块，按高度递增依次推送
+type FinalizedBlock struct {
+	Bcname               string   `protobuf:"bytes,1,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	Blockid              string   `protobuf:"bytes,2,opt,name=blockid,proto3" json:"blockid,omitempty"`
+	BlockHeight          int64    `protobuf:"varint,3,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FinalizedBlock) Reset()         { *m = FinalizedBlock{} }
+func (m *FinalizedBlock) String() string { return proto.CompactTextString(m) }
+func (*FinalizedBlock) ProtoMessage()    {}
+func (*FinalizedBlock) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bec55cd27928da5d, []int{6}
+}
+
+func (m *FinalizedBlock) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FinalizedBlock.Unmarshal(m, b)
+}
+func (m *FinalizedBlock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FinalizedBlock.Marshal(b, m, deterministic)
+}
+func (m *FinalizedBlock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FinalizedBlock.Merge(m, src)
+}
+func (m *FinalizedBlock) XXX_Size() int {
+	return xxx_messageInfo_FinalizedBlock.Size(m)
+}
+func (m *FinalizedBlock) XXX_DiscardUnknown() {
+	xxx_messageInfo_FinalizedBlock.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FinalizedBlock proto.InternalMessageInfo
+
+func (m *FinalizedBlock) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *FinalizedBlock) GetBlockid() string {
+	if m != nil {
+		return m.Blockid
+	}
+	return ""
+}
+
+func (m *FinalizedBlock) GetBlockHeight() int64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
 type FilteredTransaction struct {
 	Txid                 string           `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
 	Events               []*ContractEvent `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
@@ -366,7 +473,7 @@ func (m *FilteredTransaction) Reset()         { *m = FilteredTransaction{} }
 func (m *FilteredTransaction) String() string { return proto.CompactTextString(m) }
 func (*FilteredTransaction) ProtoMessage()    {}
 func (*FilteredTransaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bec55cd27928da5d, []int{5}
+	return fileDescriptor_bec55cd27928da5d, []int{7}
 }
 
 func (m *FilteredTransaction) XXX_Unmarshal(b []byte) error {
@@ -408,56 +515,60 @@ func init() {
 	proto.RegisterType((*BlockRange)(nil), "protos.BlockRange")
 	proto.RegisterType((*BlockFilter)(nil), "protos.BlockFilter")
 	proto.RegisterType((*FilteredBlock)(nil), "protos.FilteredBlock")
+	proto.RegisterType((*FinalityFilter)(nil), "protos.FinalityFilter")
+	proto.RegisterType((*FinalizedBlock)(nil), "protos.FinalizedBlock")
 	proto.RegisterType((*FilteredTransaction)(nil), "protos.FilteredTransaction")
 }
 
 func init() { proto.RegisterFile("protos/event.proto", fileDescriptor_bec55cd27928da5d) }
 
 var fileDescriptor_bec55cd27928da5d = []byte{
-	// 536 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x93, 0x6d, 0x6f, 0xd3, 0x30,
-	0x10, 0xc7, 0xc9, 0xda, 0x6e, 0xcb, 0xa5, 0x2d, 0x95, 0x79, 0xb2, 0x3a, 0x10, 0x5d, 0x5e, 0xa0,
-	0x80, 0xb4, 0x16, 0x15, 0xc4, 0x7b, 0x3a, 0x31, 0x21, 0x81, 0x40, 0xf2, 0x8a, 0x84, 0x78, 0x13,
-	0x39, 0x89, 0xd7, 0x5a, 0xb4, 0x71, 0xe7, 0x38, 0x53, 0xfa, 0x39, 0xf8, 0x56, 0x7c, 0x2a, 0xe4,
-	0x73, 0x92, 0x89, 0xa7, 0x77, 0xbe, 0xff, 0xfd, 0x7c, 0x77, 0xf9, 0x3b, 0x07, 0x64, 0xa7, 0x95,
-	0x51, 0xc5, 0x4c, 0xdc, 0x88, 0xdc, 0x4c, 0x31, 0x20, 0x87, 0x4e, 0x1b, 0x3f, 0xad, 0xca, 0x9d,
-	0xd0, 0xa9, 0xd2, 0x62, 0x56, 0x53, 0xa9, 0xca, 0x8d, 0xe6, 0x69, 0x0d, 0x86, 0x5f, 0x60, 0x74,
-	0x59, 0x26, 0x45, 0xaa, 0x65, 0x22, 0x98, 0xb8, 0x2e, 0x45, 0x61, 0xc8, 0x73, 0xe8, 0x9a, 0xfd,
-	0x4e, 0x50, 0x6f, 0xe2, 0x45, 0xc3, 0xf9, 0x03, 0x47, 0x16, 0xd3, 0x96, 0x5b, 0xee, 0x77, 0x82,
-	0x21, 0x42, 0x1e, 0xc2, 0xe1, 0x95, 0xdc, 0x18, 0xa1, 0xe9, 0xc1, 0xc4, 0x8b, 0xfa, 0xac, 0x8e,
-	0xc2, 0x53, 0xe8, 0xbd, 0xb3, 0xe3, 0x10, 0x0a, 0x47, 0x3b, 0xbe, 0xdf, 0x28, 0x9e, 0x61, 0xb9,
-	0x3e, 0x6b, 0xc2, 0xf0, 0x35, 0xc0, 0x62, 0xa3, 0xd2, 0xef, 0x8c, 0xe7, 0x2b, 0x41, 0xee, 0x43,
-	0xaf, 0x30, 0x5c, 0x1b, 0xa4, 0x7c, 0xe6, 0x02, 0x32, 0x82, 0x8e, 0xc8, 0x33, 0xac, 0xed, 0x33,
-	0x7b, 0x0c, 0x7f, 0x1e, 0x40, 0x80, 0xd7, 0x2e, 0xb0, 0x91, 0x1d, 0x20, 0x49, 0x73, 0xbe, 0x15,
-	0xf5, 0xc5, 0x3a, 0x22, 0x11, 0xf4, 0xb4, 0x2d, 0x8c, 0x77, 0x83, 0x39, 0x69, 0x3e, 0xe2, 0xb6,
-	0x25, 0x73, 0x00, 0x79, 0x02, 0x20, 0xaa, 0x74, 0x53, 0x66, 0x22, 0x36, 0x15, 0xed, 0x4c, 0xbc,
-	0xe8, 0x98, 0xf9, 0xb5, 0xb2, 0xac, 0x48, 0x04, 0xa3, 0xdb, 0x74, 0x8c, 0x1e, 0xd3, 0x2e, 0x42,
-	0xc3, 0x16, 0x72, 0x9f, 0x3a, 0x86, 0xe3, 0xc6, 0x5c, 0x0a, 0x38, 0x4c, 0x1b, 0x63, 0x13, 0x0b,
-	0xc5, 0x38, 0x6a, 0x80, 0x59, 0x1f, 0x95, 0x4f, 0x76, 0xda, 0xc7, 0xe0, 0xcb, 0x5c, 0x1a, 0xc9,
-	0x8d, 0xd2, 0xb4, 0xef, 0xb2, 0xad, 0x40, 0x4e, 0xa1, 0xcf, 0x4b, 0xb3, 0x8e, 0xb5, 0xb8, 0x2e,
-	0xa5, 0x16, 0x74, 0x80, 0x40, 0x60, 0x35, 0xe6, 0x24, 0x72, 0x02, 0xfe, 0x95, 0x56, 0xdb, 0x98,
-	0x67, 0x99, 0xa6, 0x43, 0xd7, 0xdc, 0x0a, 0x6f, 0xb3, 0x4c, 0x93, 0x47, 0x70, 0x64, 0x94, 0x4b,
-	0xdd, 0x75, 0x26, 0x19, 0x65, 0x13, 0xe1, 0x0f, 0x0f, 0x06, 0xce, 0x47, 0x91, 0xa1, 0x31, 0xff,
-	0xb5, 0x93, 0xc2, 0x51, 0x62, 0x01, 0xd9, 0x3c, 0x46, 0x13, 0xda, 0xe1, 0xf0, 0x18, 0xaf, 0x85,
-	0x5c, 0xad, 0x0d, 0x1a, 0xd8, 0x61, 0x01, 0x6a, 0xef, 0x51, 0x22, 0x67, 0xd0, 0x31, 0x55, 0x41,
-	0xbb, 0x93, 0x4e, 0x14, 0xcc, 0x4f, 0x9a, 0x97, 0x68, 0x1a, 0x2f, 0x35, 0xcf, 0x0b, 0x9e, 0x1a,
-	0xa9, 0x72, 0x66, 0xb9, 0xf0, 0x2b, 0xdc, 0xfb, 0x47, 0x8e, 0x10, 0xe8, 0x9a, 0x4a, 0x66, 0xf5,
-	0x60, 0x78, 0x26, 0x67, 0x70, 0x88, 0x26, 0x16, 0xf4, 0x00, 0x8b, 0xb7, 0xff, 0xea, 0x79, 0x6d,
-	0x3c, 0xbe, 0x0c, 0xab, 0xa1, 0x17, 0x63, 0x18, 0xfc, 0xf6, 0x13, 0x13, 0x1f, 0x7a, 0x8b, 0x8f,
-	0x9f, 0xcf, 0x3f, 0x8c, 0xee, 0xcc, 0x2f, 0xa0, 0x8f, 0xf0, 0xa5, 0xd0, 0x37, 0x32, 0x15, 0xe4,
-	0x0d, 0xf8, 0x2d, 0x4b, 0xe8, 0x5f, 0x3b, 0x50, 0xef, 0xca, 0x78, 0xd0, 0x64, 0xf0, 0xf2, 0x4b,
-	0x6f, 0x11, 0x7d, 0x7b, 0xb6, 0x92, 0x66, 0x5d, 0x26, 0xd3, 0x54, 0x6d, 0x67, 0x6e, 0xfd, 0xd6,
-	0x5c, 0xe6, 0xb3, 0x3f, 0x37, 0x31, 0x71, 0x3b, 0xfa, 0xea, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff,
-	0xe7, 0xdd, 0xc8, 0x91, 0xc0, 0x03, 0x00, 0x00,
+	// 571 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x54, 0x6f, 0x6f, 0xd3, 0x3e,
+	0x10, 0xfe, 0x65, 0x6d, 0xb7, 0xe5, 0x9a, 0xf6, 0x57, 0x99, 0x7f, 0xd6, 0x06, 0xa2, 0xcb, 0x0b,
+	0x14, 0x90, 0xb6, 0xa2, 0x82, 0x78, 0xbf, 0x4d, 0x54, 0x4c, 0x4c, 0x43, 0xf2, 0x8a, 0x04, 0xbc,
+	0x89, 0x9c, 0xc4, 0x5b, 0x2d, 0xba, 0xb8, 0x73, 0x9c, 0x29, 0xe5, 0x6b, 0xf0, 0xad, 0xf8, 0x54,
+	0xc8, 0xe7, 0x24, 0x13, 0xff, 0xde, 0xc1, 0x3b, 0xdf, 0x73, 0xcf, 0xf9, 0xee, 0x9e, 0xf3, 0x19,
+	0xc8, 0x4a, 0x2b, 0xa3, 0x8a, 0x89, 0xb8, 0x11, 0xb9, 0x39, 0x40, 0x83, 0x6c, 0x3a, 0x6c, 0xe7,
+	0x71, 0x55, 0xae, 0x84, 0x4e, 0x95, 0x16, 0x93, 0x9a, 0x95, 0xaa, 0xdc, 0x68, 0x9e, 0xd6, 0xc4,
+	0xf0, 0x3d, 0x8c, 0xce, 0xcb, 0xa4, 0x48, 0xb5, 0x4c, 0x04, 0x13, 0xd7, 0xa5, 0x28, 0x0c, 0x79,
+	0x0a, 0x5d, 0xb3, 0x5e, 0x09, 0xea, 0x8d, 0xbd, 0x68, 0x38, 0xbd, 0xe7, 0x98, 0xc5, 0x41, 0xcb,
+	0x9b, 0xaf, 0x57, 0x82, 0x21, 0x85, 0xdc, 0x87, 0xcd, 0x0b, 0xb9, 0x34, 0x42, 0xd3, 0x8d, 0xb1,
+	0x17, 0x05, 0xac, 0xb6, 0xc2, 0x3d, 0xe8, 0xbd, 0xb6, 0xe5, 0x10, 0x0a, 0x5b, 0x2b, 0xbe, 0x5e,
+	0x2a, 0x9e, 0xe1, 0x75, 0x01, 0x6b, 0xcc, 0xf0, 0x25, 0xc0, 0xd1, 0x52, 0xa5, 0x9f, 0x19, 0xcf,
+	0x2f, 0x05, 0xb9, 0x0b, 0xbd, 0xc2, 0x70, 0x6d, 0x90, 0xe5, 0x33, 0x67, 0x90, 0x11, 0x74, 0x44,
+	0x9e, 0xe1, 0xdd, 0x3e, 0xb3, 0xc7, 0xf0, 0xdb, 0x06, 0xf4, 0x31, 0x6c, 0x86, 0x89, 0x6c, 0x01,
+	0x49, 0x9a, 0xf3, 0x2b, 0x51, 0x07, 0xd6, 0x16, 0x89, 0xa0, 0xa7, 0xed, 0xc5, 0x18, 0xdb, 0x9f,
+	0x92, 0xa6, 0x89, 0xdb, 0x94, 0xcc, 0x11, 0xc8, 0x23, 0x00, 0x51, 0xa5, 0xcb, 0x32, 0x13, 0xb1,
+	0xa9, 0x68, 0x67, 0xec, 0x45, 0xdb, 0xcc, 0xaf, 0x91, 0x79, 0x45, 0x22, 0x18, 0xdd, 0xba, 0x63,
+	0xd4, 0x98, 0x76, 0x91, 0x34, 0x6c, 0x49, 0xae, 0xd5, 0x1d, 0xd8, 0x6e, 0xc4, 0xa5, 0x80, 0xc5,
+	0xb4, 0x36, 0x26, 0xb1, 0xa4, 0x18, 0x4b, 0xed, 0xa3, 0xd7, 0x47, 0xe4, 0xcc, 0x56, 0xfb, 0x10,
+	0x7c, 0x99, 0x4b, 0x23, 0xb9, 0x51, 0x9a, 0x06, 0xce, 0xdb, 0x02, 0x64, 0x0f, 0x02, 0x5e, 0x9a,
+	0x45, 0xac, 0xc5, 0x75, 0x29, 0xb5, 0xa0, 0x03, 0x24, 0xf4, 0x2d, 0xc6, 0x1c, 0x44, 0x76, 0xc1,
+	0xbf, 0xd0, 0xea, 0x2a, 0xe6, 0x59, 0xa6, 0xe9, 0xd0, 0x25, 0xb7, 0xc0, 0x61, 0x96, 0x69, 0xf2,
+	0x00, 0xb6, 0x8c, 0x72, 0xae, 0xff, 0x9d, 0x48, 0x46, 0x59, 0x47, 0xf8, 0xd5, 0x83, 0x81, 0xd3,
+	0x51, 0x64, 0x28, 0xcc, 0x1f, 0xe5, 0xa4, 0xb0, 0x95, 0x58, 0x82, 0x6c, 0x86, 0xd1, 0x98, 0xb6,
+	0x38, 0x3c, 0xc6, 0x0b, 0x21, 0x2f, 0x17, 0x06, 0x05, 0xec, 0xb0, 0x3e, 0x62, 0x6f, 0x10, 0x22,
+	0xfb, 0xd0, 0x31, 0x55, 0x41, 0xbb, 0xe3, 0x4e, 0xd4, 0x9f, 0xee, 0x36, 0x93, 0x68, 0x12, 0xcf,
+	0x35, 0xcf, 0x0b, 0x9e, 0x1a, 0xa9, 0x72, 0x66, 0x79, 0x21, 0x83, 0xe1, 0x4c, 0xe6, 0x7c, 0x29,
+	0xcd, 0xfa, 0x6f, 0x0d, 0x39, 0x14, 0xcd, 0x9d, 0x5f, 0xfe, 0x65, 0xa7, 0xe1, 0x07, 0xb8, 0xf3,
+	0x9b, 0xb6, 0x08, 0x81, 0xae, 0xa9, 0x64, 0x56, 0x67, 0xc2, 0x33, 0xd9, 0x87, 0x4d, 0x9c, 0x7f,
+	0x41, 0x37, 0x50, 0x97, 0x76, 0xcd, 0x8e, 0xeb, 0x37, 0x83, 0x8f, 0x8a, 0xd5, 0xa4, 0x67, 0x11,
+	0x0c, 0x7e, 0xd8, 0x3f, 0xe2, 0x43, 0xef, 0xe8, 0xf4, 0xdd, 0xf1, 0xdb, 0xd1, 0x7f, 0x24, 0x80,
+	0xed, 0xd9, 0xc9, 0xd9, 0xe1, 0xe9, 0xc9, 0xfc, 0xe3, 0xc8, 0x9b, 0xce, 0x20, 0xc0, 0xd0, 0x73,
+	0xa1, 0x6f, 0x64, 0x2a, 0xc8, 0x2b, 0xf0, 0xdb, 0x48, 0x42, 0x7f, 0x59, 0xe6, 0x7a, 0xe9, 0x77,
+	0x06, 0x8d, 0x07, 0x83, 0x9f, 0x7b, 0x47, 0xd1, 0xa7, 0x27, 0x97, 0xd2, 0x2c, 0xca, 0xe4, 0x20,
+	0x55, 0x57, 0x13, 0xf7, 0x8f, 0x2c, 0xb8, 0xcc, 0x27, 0x3f, 0x7f, 0x29, 0x89, 0xfb, 0x6c, 0x5e,
+	0x7c, 0x1f, 0x00, 0xe5, 0x94, 0x74, 0xca, 0x89, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ context.Context
-var _ grpc.ClientConnInterface
+var _ grpc.ClientConn
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+const _ = grpc.SupportPackageIsVersion4
 
 // EventServiceClient is the client API for EventService service.
 //
@@ -467,10 +578,10 @@ type EventServiceClient interface {
 }
 
 type eventServiceClient struct {
-	cc grpc.ClientConnInterface
+	cc *grpc.ClientConn
 }
 
-func NewEventServiceClient(cc grpc.ClientConnInterface) EventServiceClient {
+func NewEventServiceClient(cc *grpc.ClientConn) EventServiceClient {
 	return &eventServiceClient{cc}
 }
 
@@ -71,6 +71,11 @@ const (
 	XuperMessage_GET_BLOCKS_HEADERS_RES XuperMessage_MessageType = 27
 	XuperMessage_GET_BLOCK_TXS          XuperMessage_MessageType = 28
 	XuperMessage_GET_BLOCKS_TXS_RES     XuperMessage_MessageType = 29
+	// raft consensus message
+	XuperMessage_RAFT_MSG XuperMessage_MessageType = 30
+	// chained-bft验证人向其他验证人同步最新的QC
+	XuperMessage_CHAINED_BFT_GET_QC_MSG XuperMessage_MessageType = 31
+	XuperMessage_CHAINED_BFT_QC_MSG     XuperMessage_MessageType = 32
 )
 
 var XuperMessage_MessageType_name = map[int32]string{
@@ -104,6 +109,9 @@ var XuperMessage_MessageType_name = map[int32]string{
 	27: "GET_BLOCKS_HEADERS_RES",
 	28: "GET_BLOCK_TXS",
 	29: "GET_BLOCKS_TXS_RES",
+	30: "RAFT_MSG",
+	31: "CHAINED_BFT_GET_QC_MSG",
+	32: "CHAINED_BFT_QC_MSG",
 }
 
 var XuperMessage_MessageType_value = map[string]int32{
@@ -137,6 +145,9 @@ var XuperMessage_MessageType_value = map[string]int32{
 	"GET_BLOCKS_HEADERS_RES":       27,
 	"GET_BLOCK_TXS":                28,
 	"GET_BLOCKS_TXS_RES":           29,
+	"RAFT_MSG":                     30,
+	"CHAINED_BFT_GET_QC_MSG":       31,
+	"CHAINED_BFT_QC_MSG":           32,
 }
 
 func (x XuperMessage_MessageType) String() string {
@@ -465,62 +476,63 @@ func init() {
 func init() { proto.RegisterFile("protos/network.proto", fileDescriptor_9898f5d59e04eeea) }
 
 var fileDescriptor_9898f5d59e04eeea = []byte{
-	// 865 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0xcd, 0x73, 0x1a, 0x37,
-	0x14, 0x37, 0x18, 0xf3, 0xf1, 0xf8, 0xb0, 0xfc, 0x4c, 0x9c, 0x2d, 0x71, 0x53, 0x86, 0xe9, 0xa4,
-	0x9c, 0xec, 0x0e, 0xed, 0xa9, 0xd3, 0x0b, 0x2c, 0xb2, 0xd9, 0x71, 0xd0, 0xee, 0x48, 0x22, 0x26,
-	0xbd, 0xec, 0xac, 0x41, 0xb1, 0x99, 0x04, 0x96, 0x59, 0x70, 0xda, 0xfc, 0x29, 0x9d, 0xe9, 0xb9,
-	0x97, 0xfe, 0x93, 0x1d, 0x69, 0x77, 0x31, 0xb6, 0x49, 0x4e, 0xf0, 0x7e, 0x1f, 0xd2, 0x7b, 0x4f,
-	0xd2, 0x5b, 0xa8, 0x2f, 0xa3, 0x70, 0x1d, 0xae, 0xce, 0x17, 0x6a, 0xfd, 0x67, 0x18, 0x7d, 0x3c,
-	0x33, 0x21, 0xe6, 0x63, 0xb4, 0xf5, 0x4f, 0x19, 0x2a, 0xe3, 0xfb, 0xa5, 0x8a, 0x86, 0x6a, 0xb5,
-	0x0a, 0x6e, 0x15, 0xfe, 0x06, 0xf9, 0x81, 0x0a, 0xa6, 0x2a, 0xb2, 0x32, 0xcd, 0x4c, 0xbb, 0xdc,
-	0x69, 0xc5, 0x86, 0xd5, 0xd9, 0xb6, 0xea, 0x2c, 0xf9, 0x8d, 0x95, 0x3c, 0x71, 0xe0, 0xaf, 0x90,
-	0xeb, 0x07, 0xeb, 0xc0, 0xca, 0x1a, 0x67, 0xf3, 0x5b, 0x4e, 0xad, 0xe3, 0x46, 0xdd, 0xf8, 0x2f,
-	0x0b, 0xd5, 0x47, 0xeb, 0xa1, 0x05, 0x85, 0xcf, 0x2a, 0x5a, 0xcd, 0xc2, 0x85, 0x49, 0xa2, 0xc4,
-	0xd3, 0x10, 0xeb, 0x70, 0xf0, 0x29, 0xbc, 0x9d, 0x4d, 0xcd, 0x16, 0x25, 0x1e, 0x07, 0x88, 0x90,
-	0xfb, 0x10, 0x85, 0x73, 0x6b, 0xdf, 0x80, 0xe6, 0x3f, 0x9e, 0x40, 0xfe, 0x66, 0xb2, 0x08, 0xe6,
-	0xca, 0xca, 0x19, 0x34, 0x89, 0x74, 0x8e, 0xeb, 0x2f, 0x4b, 0x65, 0x1d, 0x34, 0x33, 0xed, 0xda,
-	0xb7, 0x73, 0x94, 0x5f, 0x96, 0x8a, 0x1b, 0x35, 0xb6, 0xa0, 0x32, 0x0d, 0xd6, 0x81, 0x7d, 0xa7,
-	0x26, 0x1f, 0xc5, 0xfd, 0xdc, 0xca, 0x37, 0x33, 0xed, 0x2a, 0x7f, 0x84, 0xe1, 0xef, 0x50, 0x52,
-	0x51, 0x14, 0x46, 0xda, 0x66, 0x15, 0xcc, 0xf2, 0xaf, 0x77, 0x2e, 0x4f, 0x53, 0x15, 0x7f, 0x30,
-	0xe0, 0x1b, 0xa8, 0xa9, 0x45, 0x70, 0xf3, 0x49, 0xd9, 0xe1, 0x7c, 0x19, 0xa9, 0xd5, 0xca, 0x2a,
-	0x36, 0x33, 0xed, 0x22, 0x7f, 0x82, 0x36, 0x7e, 0x82, 0xf2, 0x56, 0x0b, 0x75, 0xab, 0xe6, 0xab,
-	0x5b, 0x67, 0xf1, 0x21, 0x34, 0xd5, 0x57, 0x78, 0x1a, 0xb6, 0xfe, 0x3e, 0xd8, 0x28, 0xcd, 0x06,
-	0x55, 0x28, 0x09, 0xca, 0xfa, 0xbd, 0xb7, 0xae, 0x7d, 0x45, 0xf6, 0x10, 0x20, 0xef, 0xb9, 0x42,
-	0xca, 0x31, 0xc9, 0xe0, 0x21, 0x94, 0x7b, 0x5d, 0x69, 0x0f, 0x12, 0x20, 0xab, 0xb5, 0x97, 0x54,
-	0xfa, 0xb1, 0x76, 0x1f, 0x8b, 0x90, 0xf3, 0x1c, 0x76, 0x49, 0x72, 0x68, 0x41, 0x7d, 0x43, 0xd8,
-	0x83, 0xae, 0xc3, 0x84, 0xec, 0xca, 0x91, 0x20, 0x07, 0x78, 0x04, 0xd5, 0x0d, 0xe3, 0x73, 0x2a,
-	0x48, 0x1e, 0x4f, 0xc1, 0xda, 0x25, 0x36, 0x6c, 0x41, 0xb3, 0xb6, 0xcb, 0x2e, 0x1c, 0x3e, 0x7c,
-	0xbe, 0x5c, 0x11, 0x9b, 0x70, 0xfa, 0x35, 0xd6, 0xf8, 0x4b, 0x7a, 0xc3, 0xa1, 0xb8, 0xf4, 0xe5,
-	0x7b, 0x8f, 0xfa, 0xcc, 0x65, 0x94, 0x00, 0x12, 0xa8, 0xe8, 0x0d, 0xb9, 0x67, 0xfb, 0x9e, 0xcb,
-	0x25, 0x29, 0x63, 0x1d, 0xc8, 0x36, 0x62, 0xac, 0x15, 0x3c, 0x01, 0xd4, 0x68, 0x77, 0x24, 0x07,
-	0x94, 0x49, 0xc7, 0xee, 0x4a, 0xc7, 0x65, 0xa4, 0x8a, 0x0d, 0x38, 0x79, 0x8e, 0x1b, 0x4f, 0xcd,
-	0xa4, 0xab, 0x73, 0xa0, 0x7d, 0xbf, 0x77, 0x21, 0x7d, 0x46, 0xaf, 0xfd, 0x77, 0x0e, 0xbd, 0xf6,
-	0x87, 0xe2, 0x92, 0x1c, 0x9a, 0x74, 0x9f, 0xb0, 0x1e, 0x77, 0x3d, 0x57, 0x74, 0xdf, 0x1a, 0x05,
-	0xd1, 0x9d, 0xdb, 0x56, 0xbc, 0x73, 0x25, 0x35, 0xcc, 0x91, 0xee, 0xbe, 0xd6, 0x9b, 0x32, 0x9d,
-	0x3e, 0x41, 0xac, 0x40, 0x51, 0x03, 0xcc, 0xed, 0x53, 0x72, 0x9c, 0x16, 0x95, 0xd0, 0x82, 0xd4,
-	0xd3, 0xa2, 0x52, 0xc4, 0x24, 0xf8, 0x02, 0x6b, 0x00, 0x1b, 0x54, 0x90, 0x13, 0x44, 0xa8, 0x3d,
-	0xc4, 0x46, 0xf3, 0x32, 0x3d, 0x24, 0x8f, 0x52, 0xee, 0x3b, 0xec, 0xc2, 0x25, 0x16, 0xbe, 0x80,
-	0xa3, 0x47, 0x90, 0x51, 0x7e, 0x97, 0xc2, 0xf1, 0x71, 0x0e, 0x68, 0xb7, 0x4f, 0xb9, 0x20, 0x8d,
-	0xb4, 0x43, 0xc9, 0xa2, 0x09, 0x6e, 0x2c, 0xaf, 0x1e, 0xdf, 0x00, 0x39, 0x16, 0xe4, 0x34, 0x6d,
-	0x74, 0x22, 0x97, 0xe3, 0x58, 0xfa, 0x7d, 0xeb, 0xdf, 0x2c, 0x94, 0x36, 0xaf, 0x00, 0xcb, 0x50,
-	0x10, 0x23, 0xdb, 0xa6, 0x42, 0x90, 0x3d, 0x7d, 0xd7, 0xcc, 0x69, 0x66, 0x74, 0xe1, 0x23, 0x76,
-	0xc5, 0xdc, 0x6b, 0x9f, 0x72, 0xee, 0x72, 0x92, 0xc5, 0x63, 0x38, 0xb4, 0x07, 0xd4, 0xbe, 0xf2,
-	0xc5, 0x68, 0x98, 0x80, 0xfb, 0xfa, 0x60, 0x46, 0x6c, 0xd8, 0xe5, 0x62, 0x10, 0xf7, 0xda, 0xef,
-	0xb9, 0xfd, 0xf7, 0x09, 0x9b, 0xd3, 0x5d, 0xb0, 0x5d, 0xc6, 0xa8, 0xad, 0xcf, 0xfe, 0x62, 0x24,
-	0x28, 0x39, 0x78, 0x7e, 0x89, 0x13, 0x75, 0x1e, 0x5f, 0xc2, 0xf1, 0x16, 0xca, 0x5c, 0x49, 0xc7,
-	0x8e, 0x90, 0xa4, 0xa0, 0x77, 0x7e, 0xa8, 0x2d, 0x56, 0x17, 0xb1, 0x05, 0xaf, 0xbf, 0x7a, 0x47,
-	0x63, 0x4d, 0x29, 0x7d, 0x03, 0x4f, 0xae, 0x54, 0xcc, 0x02, 0xfe, 0x00, 0xaf, 0x76, 0xb0, 0xcc,
-	0x95, 0xbe, 0xd7, 0x15, 0x82, 0x94, 0x5b, 0x6b, 0x28, 0x7a, 0x4a, 0x45, 0xfa, 0x41, 0x63, 0x0d,
-	0xb2, 0xb3, 0x69, 0x32, 0x10, 0xb3, 0xb3, 0xa9, 0x7e, 0xfa, 0xc1, 0x74, 0x6a, 0x46, 0x45, 0x3c,
-	0x0d, 0xd3, 0xd0, 0x30, 0x93, 0x49, 0x78, 0xbf, 0x58, 0x27, 0x23, 0x31, 0x0d, 0xf1, 0x47, 0xc8,
-	0x2d, 0x95, 0x8a, 0xac, 0x5c, 0x73, 0xbf, 0x5d, 0xee, 0x90, 0x74, 0x3c, 0xa5, 0x7b, 0x70, 0xc3,
-	0x76, 0x3c, 0x80, 0x65, 0x67, 0x29, 0x54, 0xf4, 0x79, 0x36, 0x51, 0xd8, 0x83, 0x9a, 0x50, 0x8b,
-	0xa9, 0xd7, 0x59, 0xa6, 0xdf, 0x88, 0xfa, 0xae, 0xb1, 0xd6, 0xd8, 0x89, 0xb6, 0xf6, 0xda, 0x99,
-	0x9f, 0x33, 0xbd, 0xf6, 0x1f, 0x6f, 0x6e, 0x67, 0xeb, 0xbb, 0xfb, 0x9b, 0xb3, 0x49, 0x38, 0x3f,
-	0xff, 0x4b, 0x0b, 0x26, 0x77, 0xc1, 0x6c, 0x91, 0xfc, 0x0d, 0x23, 0x75, 0x1e, 0x9b, 0x6f, 0xe2,
-	0x0f, 0xd3, 0x2f, 0xff, 0x07, 0x00, 0x00, 0xff, 0xff, 0xb2, 0xf2, 0x31, 0x64, 0xb7, 0x06, 0x00,
-	0x00,
+	// 887 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0x5f, 0x73, 0xda, 0x46,
+	0x10, 0x37, 0x18, 0xf3, 0x67, 0xf9, 0xe3, 0xf3, 0x9a, 0x38, 0x2a, 0x71, 0x1d, 0x86, 0xe9, 0xa4,
+	0x3c, 0xd9, 0x1d, 0xda, 0xa7, 0x4e, 0x5f, 0x84, 0x38, 0x8c, 0xc6, 0x41, 0x52, 0xef, 0x8e, 0x98,
+	0xf4, 0x45, 0x23, 0xc3, 0xc5, 0x66, 0x12, 0x10, 0x23, 0x70, 0xda, 0x7c, 0xa1, 0xbe, 0xf4, 0x23,
+	0x75, 0xa6, 0x9f, 0xa5, 0x73, 0x27, 0x09, 0x63, 0x9b, 0xe4, 0x09, 0xf6, 0xf7, 0x67, 0x6f, 0x6f,
+	0xef, 0x6e, 0x05, 0xf5, 0x65, 0x14, 0xae, 0xc3, 0xd5, 0xc5, 0x42, 0xae, 0xff, 0x0c, 0xa3, 0x8f,
+	0xe7, 0x3a, 0xc4, 0x7c, 0x8c, 0xb6, 0xfe, 0x2b, 0x43, 0x65, 0x7c, 0xbf, 0x94, 0xd1, 0x50, 0xae,
+	0x56, 0xc1, 0xad, 0xc4, 0x5f, 0x21, 0x3f, 0x90, 0xc1, 0x54, 0x46, 0x46, 0xa6, 0x99, 0x69, 0x97,
+	0x3b, 0xad, 0xd8, 0xb0, 0x3a, 0xdf, 0x56, 0x9d, 0x27, 0xbf, 0xb1, 0x92, 0x25, 0x0e, 0xfc, 0x05,
+	0x72, 0xbd, 0x60, 0x1d, 0x18, 0x59, 0xed, 0x6c, 0x7e, 0xcb, 0xa9, 0x74, 0x4c, 0xab, 0x1b, 0xff,
+	0x64, 0xa1, 0xfa, 0x28, 0x1f, 0x1a, 0x50, 0xf8, 0x2c, 0xa3, 0xd5, 0x2c, 0x5c, 0xe8, 0x22, 0x4a,
+	0x2c, 0x0d, 0xb1, 0x0e, 0x07, 0x9f, 0xc2, 0xdb, 0xd9, 0x54, 0x2f, 0x51, 0x62, 0x71, 0x80, 0x08,
+	0xb9, 0x0f, 0x51, 0x38, 0x37, 0xf6, 0x35, 0xa8, 0xff, 0xe3, 0x09, 0xe4, 0x6f, 0x26, 0x8b, 0x60,
+	0x2e, 0x8d, 0x9c, 0x46, 0x93, 0x48, 0xd5, 0xb8, 0xfe, 0xb2, 0x94, 0xc6, 0x41, 0x33, 0xd3, 0xae,
+	0x7d, 0xbb, 0x46, 0xf1, 0x65, 0x29, 0x99, 0x56, 0x63, 0x0b, 0x2a, 0xd3, 0x60, 0x1d, 0x58, 0x77,
+	0x72, 0xf2, 0x91, 0xdf, 0xcf, 0x8d, 0x7c, 0x33, 0xd3, 0xae, 0xb2, 0x47, 0x18, 0xfe, 0x06, 0x25,
+	0x19, 0x45, 0x61, 0xa4, 0x6c, 0x46, 0x41, 0xa7, 0x3f, 0xdb, 0x99, 0x9e, 0xa6, 0x2a, 0xf6, 0x60,
+	0xc0, 0x37, 0x50, 0x93, 0x8b, 0xe0, 0xe6, 0x93, 0xb4, 0xc2, 0xf9, 0x32, 0x92, 0xab, 0x95, 0x51,
+	0x6c, 0x66, 0xda, 0x45, 0xf6, 0x04, 0x6d, 0xfc, 0x08, 0xe5, 0xad, 0x16, 0xaa, 0x56, 0xcd, 0x57,
+	0xb7, 0xf6, 0xe2, 0x43, 0xa8, 0x77, 0x5f, 0x61, 0x69, 0xd8, 0xfa, 0xf7, 0x60, 0xa3, 0xd4, 0x0b,
+	0x54, 0xa1, 0xc4, 0xa9, 0xd3, 0xeb, 0xbe, 0x75, 0xad, 0x2b, 0xb2, 0x87, 0x00, 0x79, 0xcf, 0xe5,
+	0x42, 0x8c, 0x49, 0x06, 0x0f, 0xa1, 0xdc, 0x35, 0x85, 0x35, 0x48, 0x80, 0xac, 0xd2, 0x5e, 0x52,
+	0xe1, 0xc7, 0xda, 0x7d, 0x2c, 0x42, 0xce, 0xb3, 0x9d, 0x4b, 0x92, 0x43, 0x03, 0xea, 0x1b, 0xc2,
+	0x1a, 0x98, 0xb6, 0xc3, 0x85, 0x29, 0x46, 0x9c, 0x1c, 0xe0, 0x11, 0x54, 0x37, 0x8c, 0xcf, 0x28,
+	0x27, 0x79, 0x3c, 0x05, 0x63, 0x97, 0x58, 0xb3, 0x05, 0xc5, 0x5a, 0xae, 0xd3, 0xb7, 0xd9, 0xf0,
+	0x79, 0xba, 0x22, 0x36, 0xe1, 0xf4, 0x6b, 0xac, 0xf6, 0x97, 0xd4, 0x82, 0x43, 0x7e, 0xe9, 0x8b,
+	0xf7, 0x1e, 0xf5, 0x1d, 0xd7, 0xa1, 0x04, 0x90, 0x40, 0x45, 0x2d, 0xc8, 0x3c, 0xcb, 0xf7, 0x5c,
+	0x26, 0x48, 0x19, 0xeb, 0x40, 0xb6, 0x11, 0x6d, 0xad, 0xe0, 0x09, 0xa0, 0x42, 0xcd, 0x91, 0x18,
+	0x50, 0x47, 0xd8, 0x96, 0x29, 0x6c, 0xd7, 0x21, 0x55, 0x6c, 0xc0, 0xc9, 0x73, 0x5c, 0x7b, 0x6a,
+	0xba, 0x5c, 0x55, 0x03, 0xed, 0xf9, 0xdd, 0xbe, 0xf0, 0x1d, 0x7a, 0xed, 0xbf, 0xb3, 0xe9, 0xb5,
+	0x3f, 0xe4, 0x97, 0xe4, 0x50, 0x97, 0xfb, 0x84, 0xf5, 0x98, 0xeb, 0xb9, 0xdc, 0x7c, 0xab, 0x15,
+	0x44, 0x75, 0x6e, 0x5b, 0xf1, 0xce, 0x15, 0x54, 0x33, 0x47, 0xaa, 0xfb, 0x4a, 0xaf, 0xb7, 0x69,
+	0xf7, 0x08, 0x62, 0x05, 0x8a, 0x0a, 0x70, 0xdc, 0x1e, 0x25, 0xc7, 0xe9, 0xa6, 0x12, 0x9a, 0x93,
+	0x7a, 0xba, 0xa9, 0x14, 0xd1, 0x05, 0xbe, 0xc0, 0x1a, 0xc0, 0x06, 0xe5, 0xe4, 0x04, 0x11, 0x6a,
+	0x0f, 0xb1, 0xd6, 0xbc, 0x4c, 0x0f, 0xc9, 0xa3, 0x94, 0xf9, 0xb6, 0xd3, 0x77, 0x89, 0x81, 0x2f,
+	0xe0, 0xe8, 0x11, 0xa4, 0x95, 0xdf, 0xa5, 0x70, 0x7c, 0x9c, 0x03, 0x6a, 0xf6, 0x28, 0xe3, 0xa4,
+	0x91, 0x76, 0x28, 0x49, 0x9a, 0xe0, 0xda, 0xf2, 0xea, 0xf1, 0x0d, 0x10, 0x63, 0x4e, 0x4e, 0xd3,
+	0x46, 0x27, 0x72, 0x31, 0x8e, 0xa5, 0xdf, 0xab, 0x1d, 0x32, 0xb3, 0x2f, 0x74, 0x03, 0xce, 0x54,
+	0xd2, 0xed, 0xd6, 0x28, 0xc7, 0xef, 0x96, 0xe6, 0x5e, 0xab, 0x0c, 0xdb, 0x5c, 0x82, 0x37, 0x5b,
+	0x7f, 0x67, 0xa1, 0xb4, 0x79, 0x47, 0x58, 0x86, 0x02, 0x1f, 0x59, 0x16, 0xe5, 0x9c, 0xec, 0xa9,
+	0xdb, 0xaa, 0xef, 0x43, 0x46, 0xb5, 0x6e, 0xe4, 0x5c, 0x39, 0xee, 0xb5, 0x4f, 0x19, 0x73, 0x19,
+	0xc9, 0xe2, 0x31, 0x1c, 0x5a, 0x03, 0x6a, 0x5d, 0xf9, 0x7c, 0x34, 0x4c, 0xc0, 0x7d, 0x75, 0xb4,
+	0x23, 0x67, 0x68, 0x32, 0x3e, 0x88, 0x4f, 0xcb, 0xef, 0xba, 0xbd, 0xf7, 0x09, 0x9b, 0x53, 0x7d,
+	0xb4, 0x5c, 0xc7, 0xa1, 0x96, 0xba, 0x3d, 0xfd, 0x11, 0xa7, 0xe4, 0xe0, 0xf9, 0x33, 0x48, 0xd4,
+	0x79, 0x7c, 0x09, 0xc7, 0x5b, 0xa8, 0xe3, 0x0a, 0x3a, 0xb6, 0xb9, 0x20, 0x05, 0xb5, 0xf2, 0x43,
+	0x77, 0x62, 0x75, 0x11, 0x5b, 0x70, 0xf6, 0xd5, 0x5b, 0x1e, 0x6b, 0x4a, 0xe9, 0x2b, 0x7a, 0x72,
+	0x29, 0x63, 0x16, 0xf0, 0x35, 0xbc, 0xda, 0xc1, 0x3a, 0xae, 0xf0, 0x3d, 0x93, 0x73, 0x52, 0x6e,
+	0xad, 0xa1, 0xe8, 0x49, 0x19, 0xa9, 0x91, 0x80, 0x35, 0xc8, 0xce, 0xa6, 0xc9, 0x48, 0xcd, 0xce,
+	0xa6, 0x6a, 0x78, 0x04, 0xd3, 0xa9, 0x1e, 0x36, 0xf1, 0x3c, 0x4d, 0x43, 0xcd, 0x4c, 0x26, 0xe1,
+	0xfd, 0x62, 0x9d, 0x0c, 0xd5, 0x34, 0xc4, 0x1f, 0x20, 0xb7, 0x94, 0x32, 0x32, 0x72, 0xcd, 0xfd,
+	0x76, 0xb9, 0x43, 0xd2, 0x01, 0x97, 0xae, 0xc1, 0x34, 0xdb, 0xf1, 0x00, 0x96, 0x9d, 0x25, 0x97,
+	0xd1, 0xe7, 0xd9, 0x44, 0x62, 0x17, 0x6a, 0x5c, 0x2e, 0xa6, 0x5e, 0x67, 0x99, 0x7e, 0x65, 0xea,
+	0xbb, 0x06, 0x63, 0x63, 0x27, 0xda, 0xda, 0x6b, 0x67, 0x7e, 0xca, 0x74, 0xdb, 0x7f, 0xbc, 0xb9,
+	0x9d, 0xad, 0xef, 0xee, 0x6f, 0xce, 0x27, 0xe1, 0xfc, 0xe2, 0x2f, 0x25, 0x98, 0xdc, 0x05, 0xb3,
+	0x45, 0xf2, 0x37, 0x8c, 0xe4, 0x45, 0x6c, 0xbe, 0x89, 0x3f, 0x6d, 0x3f, 0xff, 0x3f, 0x00, 0xaf,
+	0x9b, 0xbd, 0xb5, 0xf9, 0x06, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.